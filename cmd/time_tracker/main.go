@@ -5,51 +5,131 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/BurntSushi/toml"
 	"github.com/labstack/echo/v4"
+	echoMiddleware "github.com/labstack/echo/v4/middleware"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/redis/go-redis/v9"
 	echoSwagger "github.com/swaggo/echo-swagger"
 
 	configTimeTracker "github.com/BMSTU-TIMETRACKERS/timetracker-backend/config/time_tracker"
 	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/config/time_tracker/flags"
 	_ "github.com/BMSTU-TIMETRACKERS/timetracker-backend/docs"
+	accountDelivery "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/account/delivery"
+	accountRepo "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/account/repository"
+	accountUC "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/account/usecase"
+	auditDelivery "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/audit/delivery"
+	auditRepo "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/audit/repository"
+	auditUC "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/audit/usecase"
+	calendarDelivery "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/calendar/delivery"
+	calendarRepo "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/calendar/repository"
+	calendarUC "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/calendar/usecase"
 	entryDelivery "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/entry/delivery"
 	entryRepo "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/entry/repository"
 	entryUC "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/entry/usecase"
 	goalDelivery "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/goal/delivery"
 	goalRepo "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/goal/repository"
 	goalUC "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/goal/usecase"
+	healthDelivery "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/health/delivery"
+	healthRepo "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/health/repository"
+	healthUC "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/health/usecase"
+	logLevelDelivery "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/loglevel/delivery"
+	logLevelUC "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/loglevel/usecase"
+	metricsCollector "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/metrics/collector"
+	metricsRepo "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/metrics/repository"
 	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/middleware"
+	notificationDelivery "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/notification/delivery"
+	notificationRepo "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/notification/repository"
+	notificationScheduler "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/notification/scheduler"
+	notificationUC "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/notification/usecase"
+	outboxRelay "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/outbox/relay"
+	outboxRepo "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/outbox/repository"
+	outboxUC "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/outbox/usecase"
+	periodLockDelivery "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/periodlock/delivery"
+	periodLockRepo "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/periodlock/repository"
+	periodLockUC "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/periodlock/usecase"
 	projectDelivery "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/project/delivery"
 	projectRepo "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/project/repository"
 	projectUC "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/project/usecase"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/ratelimit"
+	reportDelivery "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/report/delivery"
+	reportRepo "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/report/repository"
+	reportUC "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/report/usecase"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/response"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/statscache"
+	timesheetDelivery "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/timesheet/delivery"
+	timesheetRepo "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/timesheet/repository"
+	timesheetUC "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/timesheet/usecase"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/transaction"
+	trashDelivery "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/trash/delivery"
+	trashPurger "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/trash/purger"
+	trashRepo "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/trash/repository"
+	trashUC "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/trash/usecase"
+	webhookDelivery "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/webhook/delivery"
+	webhookDispatcher "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/webhook/dispatcher"
+	webhookRepo "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/webhook/repository"
+	webhookUC "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/webhook/usecase"
+	workspaceDelivery "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/workspace/delivery"
+	workspaceRepo "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/workspace/repository"
+	workspaceUC "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/workspace/usecase"
 )
 
 var (
-	configPath string
+	configPath      string
+	configOverrides *configTimeTracker.FlagOverrides
 )
 
 func init() {
 	flag.StringVar(&configPath, "config-path", "./config.toml", "path to config file")
+	configOverrides = configTimeTracker.RegisterFlags(flag.CommandLine, &TimeTracker{})
 }
 
 type TimeTracker struct {
 	configTimeTracker.Base
-	PostgresClient            flags.PostgresFlags `toml:"postgres-client"`
-	RedisSessionClient        flags.RedisFlags    `toml:"redis-client"`
-	RedisProjectStorageClient flags.RedisFlags    `toml:"redis-project-storage-client"`
-	Server                    flags.ServerFlags   `toml:"server"`
+	PostgresClient            flags.PostgresFlags     `toml:"postgres-client"`
+	RedisSessionClient        flags.RedisFlags        `toml:"redis-client"`
+	RedisProjectStorageClient flags.RedisFlags        `toml:"redis-project-storage-client"`
+	Server                    flags.ServerFlags       `toml:"server"`
+	Timeouts                  flags.TimeoutFlags      `toml:"timeouts"`
+	RateLimit                 flags.RateLimitFlags    `toml:"rate-limit"`
+	StatsCache                flags.StatsCacheFlags   `toml:"stats-cache"`
+	Trash                     flags.TrashFlags        `toml:"trash"`
+	ICal                      flags.ICalFlags         `toml:"ical"`
+	Webhooks                  flags.WebhookFlags      `toml:"webhooks"`
+	Outbox                    flags.OutboxFlags       `toml:"outbox"`
+	SMTP                      flags.SMTPFlags         `toml:"smtp"`
+	Notifications             flags.NotificationFlags `toml:"notifications"`
+	Admin                     flags.AdminFlags        `toml:"admin"`
 }
 
 func main() {
-	timeTracker := TimeTracker{}
-	_, err := toml.DecodeFile(configPath, &timeTracker)
+	flag.Parse()
 
-	if err != nil {
-		log.Fatal(err)
-	}
+	timeTracker, err := loadConfig()
 
-	err = timeTracker.Run()
+	// Без подкоманды запускается сервис, migrate управляет схемой базы,
+	// config print показывает действующий конфиг.
+	switch {
+	case flag.Arg(0) == "config" && flag.Arg(1) == "print":
+		if printErr := configTimeTracker.Print(os.Stdout, &timeTracker); printErr != nil {
+			log.Fatal(printErr)
+		}
+	case err != nil:
+	case flag.Arg(0) == "":
+		err = timeTracker.Run()
+	case flag.Arg(0) == "migrate":
+		err = timeTracker.Migrate(flag.Args()[1:])
+	default:
+		err = fmt.Errorf("unknown command %q", strings.Join(flag.Args(), " "))
+	}
 
 	if err != nil {
 		log.Fatal(err)
@@ -57,10 +137,51 @@ func main() {
 	}
 }
 
+// loadConfig собирает конфиг. Значения берутся по возрастанию приоритета: из TOML-файла,
+// из переменных окружения TIME_TRACKER_*, из флагов командной строки. Путь к файлу тоже
+// можно задать переменной TIME_TRACKER_CONFIG_PATH, если не задан флаг -config-path.
+func loadConfig() (TimeTracker, error) {
+	path := configPath
+	if envPath, ok := os.LookupEnv(configTimeTracker.EnvName("config-path")); ok && !isFlagSet("config-path") {
+		path = envPath
+	}
+
+	timeTracker := TimeTracker{}
+	if _, err := toml.DecodeFile(path, &timeTracker); err != nil {
+		return timeTracker, fmt.Errorf("decode %s: %v", path, err)
+	}
+
+	if err := configTimeTracker.ApplyEnv(&timeTracker, os.LookupEnv); err != nil {
+		return timeTracker, fmt.Errorf("env: %v", err)
+	}
+
+	if err := configOverrides.Apply(&timeTracker); err != nil {
+		return timeTracker, fmt.Errorf("flags: %v", err)
+	}
+
+	return timeTracker, configTimeTracker.Validate(&timeTracker)
+}
+
+func isFlagSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+
+	return set
+}
+
 func (tt TimeTracker) Run() error {
-	ctx := context.Background()
+	// Контекст отменяется по SIGINT или SIGTERM: фоновые задачи останавливаются,
+	// сервер дообрабатывает текущие запросы и завершается.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
 	e := echo.New()
+	// Все ошибки, в том числе маршрутизатора и мидлварей, отдаются в одном формате.
+	e.HTTPErrorHandler = response.ErrorHandler
 	services, err := tt.Init(e)
 
 	logger := services.Logger
@@ -70,6 +191,23 @@ func (tt TimeTracker) Run() error {
 		return fmt.Errorf("can not init services: %v", err)
 	}
 
+	e.IPExtractor, err = tt.Server.IPExtractor()
+	if err != nil {
+		logger.Error("can not init ip extractor: %w", err)
+		return err
+	}
+
+	// Отправляем накопленные span'ы перед выходом. Контекст сигнала к этому моменту
+	// уже отменен, поэтому на отправку дается отдельный таймаут.
+	defer func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), tt.Server.ShutdownTimeout)
+		defer cancel()
+
+		if err := services.TracerProvider.Shutdown(shutdownCtx); err != nil {
+			logger.Errorf("shutdown tracer provider: %v", err)
+		}
+	}()
+
 	postgresClient, err := tt.PostgresClient.Init(ctx)
 	if err != nil {
 		logger.Error("can not connect to Postgres client: %w", err)
@@ -78,42 +216,293 @@ func (tt TimeTracker) Run() error {
 		logger.Info("Success connect to postgres")
 	}
 
-	// redisSessionClient, err := tt.RedisSessionClient.Init()
+	healthUsecase := healthUC.NewUsecase(tt.Server.HealthCheckTimeout)
+	healthUsecase.AddCheck("postgres", healthRepo.NewPostgresRepository(postgresClient))
 
-	// if err != nil {
-	// 	logger.Error("can not connect to Redis session client: %w", err)
-	// 	return err
-	// } else {
-	// 	logger.Info("Success conect to redis")
-	// }
+	// Redis подключается, только если он есть в конфиге.
+	var redisSessionClient *redis.Client
+	if tt.RedisSessionClient.Addr != "" {
+		redisSessionClient, err = tt.RedisSessionClient.Init(ctx)
+		if err != nil {
+			logger.Error("can not connect to Redis session client: %w", err)
+			return err
+		} else {
+			logger.Info("Success connect to redis")
+		}
+
+		healthUsecase.AddCheck("redis", healthRepo.NewRedisRepository(redisSessionClient))
+	}
+
+	// Без Redis для кеша статистика каждый раз считается в базе.
+	var redisProjectStorageClient *redis.Client
+	if tt.RedisProjectStorageClient.Addr != "" {
+		redisProjectStorageClient, err = tt.RedisProjectStorageClient.Init(ctx)
+		if err != nil {
+			logger.Error("can not connect to Redis project storage client: %w", err)
+			return err
+		} else {
+			logger.Info("Success connect to redis cache")
+		}
+
+		healthUsecase.AddCheck("redis-cache", healthRepo.NewRedisRepository(redisProjectStorageClient))
+	}
+
+	smtpMailer, err := tt.SMTP.Init()
+	if err != nil {
+		logger.Error("can not init SMTP mailer: %w", err)
+		return err
+	}
+
+	notificationLocation, err := time.LoadLocation(tt.Notifications.Timezone)
+	if err != nil {
+		logger.Error("can not load notifications timezone: %w", err)
+		return err
+	}
 
 	// Репозитории.
 	entryRepository := entryRepo.NewRepository(postgresClient)
 	projectRepository := projectRepo.NewRepository(postgresClient)
 	goalRepository := goalRepo.NewRepository(postgresClient)
+	workspaceRepository := workspaceRepo.NewRepository(postgresClient)
+	reportRepository := reportRepo.NewRepository(postgresClient)
+	timesheetRepository := timesheetRepo.NewRepository(postgresClient)
+	periodLockRepository := periodLockRepo.NewRepository(postgresClient)
+	auditRepository := auditRepo.NewRepository(postgresClient)
+	trashRepository := trashRepo.NewRepository(postgresClient)
+	accountRepository := accountRepo.NewRepository(postgresClient)
+	calendarRepository := calendarRepo.NewRepository(postgresClient)
+	webhookRepository := webhookRepo.NewRepository(postgresClient)
+	outboxRepository := outboxRepo.NewRepository(postgresClient)
+	notificationRepository := notificationRepo.NewRepository(postgresClient)
+	metricsRepository := metricsRepo.NewRepository(postgresClient)
+	txManager := transaction.NewManager(postgresClient)
+	statsCache := statscache.NewCache(
+		redisProjectStorageClient,
+		tt.StatsCache.TTL,
+		services.MetricsRegistry,
+		tt.Metrics.Namespace,
+		logger,
+	)
 
 	// Usecases.
-	entryUsecase := entryUC.NewUsecase(entryRepository)
-	projectUsecase := projectUC.NewUsecase(projectRepository, entryRepository)
-	goalUsecase := goalUC.NewUsecase(goalRepository)
+	webhookUsecase := webhookUC.NewUsecase(
+		webhookRepository,
+		workspaceRepository,
+		tt.Webhooks.Timeout,
+		tt.Webhooks.BatchSize,
+		tt.Webhooks.MaxAttempts,
+		tt.Webhooks.RetryBase,
+		tt.Webhooks.AllowPrivateNetworks,
+	)
+	outboxUsecase := outboxUC.NewUsecase(outboxRepository, tt.Outbox.BatchSize, tt.Outbox.Retention, webhookUsecase)
+	auditUsecase := auditUC.NewUsecase(auditRepository, workspaceRepository, outboxRepository)
+	goalUsecase := goalUC.NewUsecase(goalRepository, projectRepository, auditUsecase, txManager, statsCache)
+	entryUsecase := entryUC.NewUsecase(
+		entryRepository,
+		projectRepository,
+		timesheetRepository,
+		periodLockRepository,
+		auditUsecase,
+		accountRepository,
+		goalUsecase,
+		txManager,
+		statsCache,
+	)
+	projectUsecase := projectUC.NewUsecase(
+		projectRepository,
+		entryRepository,
+		workspaceRepository,
+		periodLockRepository,
+		auditUsecase,
+		txManager,
+		statsCache,
+	)
+	workspaceUsecase := workspaceUC.NewUsecase(workspaceRepository, statsCache)
+	reportUsecase := reportUC.NewUsecase(reportRepository, workspaceRepository)
+	timesheetUsecase := timesheetUC.NewUsecase(timesheetRepository, workspaceRepository)
+	periodLockUsecase := periodLockUC.NewUsecase(periodLockRepository, workspaceRepository)
+	trashUsecase := trashUC.NewUsecase(trashRepository, tt.Trash.RetentionPeriod)
+	accountUsecase := accountUC.NewUsecase(accountRepository, auditUsecase, txManager, statsCache)
+	calendarUsecase := calendarUC.NewUsecase(calendarRepository, tt.ICal.Window)
+	logLevelUsecase := logLevelUC.NewUsecase(logger)
+	notificationUsecase := notificationUC.NewUsecase(
+		notificationRepository,
+		projectUsecase,
+		goalUsecase,
+		smtpMailer,
+		notificationUC.Schedule{
+			Location:      notificationLocation,
+			DigestWeekday: time.Weekday(tt.Notifications.DigestWeekday),
+			DigestHour:    tt.Notifications.DigestHour,
+			EndingSoon:    tt.Notifications.EndingSoon,
+			PaceTolerance: tt.Notifications.PaceTolerance,
+			AppURL:        tt.Notifications.AppURL,
+		},
+	)
+
+	// Фоновые задачи. При остановке их дожидаемся, прежде чем закрыть пул соединений.
+	var workers sync.WaitGroup
+	runWorker := func(run func(ctx context.Context)) {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			run(ctx)
+		}()
+	}
+
+	runWorker(trashPurger.NewPurger(trashUsecase, tt.Trash.PurgeInterval, logger).Run)
+	runWorker(webhookDispatcher.NewDispatcher(webhookUsecase, tt.Webhooks.PollInterval, logger).Run)
+	runWorker(outboxRelay.NewRelay(outboxUsecase, tt.Outbox.PollInterval, logger).Run)
+	runWorker(notificationScheduler.NewScheduler(notificationUsecase, tt.Notifications.CheckInterval, logger).Run)
+
+	// Метрики.
+	metricsRegistry := services.MetricsRegistry
+	if tt.Metrics.Enabled {
+		metricsRegistry.MustRegister(collectors.NewDBStatsCollector(postgresClient.DB, "postgres"))
+		runWorker(metricsCollector.NewCollector(
+			metricsRepository,
+			metricsRegistry,
+			tt.Metrics.Namespace,
+			tt.Metrics.BusinessInterval,
+			logger,
+		).Run)
+	}
 
 	// Мидлвары.
-	authMW := middleware.NewAuthMiddleware()
+	authMW := middleware.NewAuthMiddleware(workspaceUsecase)
+	timeoutMW := middleware.NewTimeoutMiddleware(tt.Timeouts.Default, tt.Timeouts.Routes)
+	loggerMW := middleware.NewLoggerMiddleware(logger, tt.Logger.LogHeader)
+	adminMW := middleware.NewAdminMiddleware(tt.Admin.Token)
+
+	var rateLimitMW *middleware.RateLimitMiddleware
+	if tt.RateLimit.Enabled {
+		rateLimitDefault, rateLimitGroups, err := tt.RateLimit.Init()
+		if err != nil {
+			logger.Error("can not init rate limits: %w", err)
+			return err
+		}
+
+		// Без Redis и пока он недоступен лимиты считаются в памяти каждого экземпляра.
+		var rateLimitStore ratelimit.Store = ratelimit.NewMemoryStore()
+		if redisSessionClient != nil {
+			rateLimitStore = ratelimit.NewFallbackStore(
+				ratelimit.NewRedisStore(redisSessionClient),
+				rateLimitStore,
+				func(err error) {
+					logger.Errorf("redis rate limit store, falling back to memory: %v", err)
+				},
+			)
+		}
+
+		rateLimitMW = middleware.NewRateLimitMiddleware(rateLimitStore, rateLimitDefault, rateLimitGroups)
+	}
 
 	// Регистрация мидлвар.
+	e.Use(middleware.RequestID)
+	e.Use(echoMiddleware.LoggerWithConfig(echoMiddleware.LoggerConfig{
+		Format:        tt.Logger.LogHttpFormat,
+		CustomTagFunc: middleware.AccessLogUserID,
+		Output:        logger.Output(),
+	}))
+	e.Use(middleware.Tracing)
+	if tt.Metrics.Enabled {
+		metricsMW := middleware.NewMetricsMiddleware(metricsRegistry, tt.Metrics.Namespace, tt.Metrics.Buckets)
+		e.Use(metricsMW.Metrics)
+	}
+	e.Use(timeoutMW.Timeout)
+	if rateLimitMW != nil {
+		e.Use(rateLimitMW.ByIP)
+	}
 	e.Use(authMW.Auth)
+	if rateLimitMW != nil {
+		e.Use(rateLimitMW.ByUser)
+	}
+	e.Use(loggerMW.RequestLogger)
 
 	// Регистрация обработчиков.
 	entryDelivery.RegisterHandlers(e, entryUsecase, logger)
 	projectDelivery.RegisterHandlers(e, projectUsecase, logger)
 	goalDelivery.RegisterHandlers(e, goalUsecase, logger)
+	workspaceDelivery.RegisterHandlers(e, workspaceUsecase, logger)
+	reportDelivery.RegisterHandlers(e, reportUsecase, logger)
+	timesheetDelivery.RegisterHandlers(e, timesheetUsecase, logger)
+	periodLockDelivery.RegisterHandlers(e, periodLockUsecase, logger)
+	auditDelivery.RegisterHandlers(e, auditUsecase, logger)
+	trashDelivery.RegisterHandlers(e, trashUsecase, logger)
+	accountDelivery.RegisterHandlers(e, accountUsecase, logger)
+	calendarDelivery.RegisterHandlers(e, calendarUsecase, logger)
+	webhookDelivery.RegisterHandlers(e, webhookUsecase, logger)
+	notificationDelivery.RegisterHandlers(e, notificationUsecase, logger)
+	healthDelivery.RegisterHandlers(e, healthUsecase, logger)
+	logLevelDelivery.RegisterHandlers(e, logLevelUsecase, adminMW.Admin, logger)
 
 	e.GET("/swagger/*", echoSwagger.WrapHandler)
+	if tt.Metrics.Enabled {
+		e.GET("/prometheus", echo.WrapHandler(promhttp.HandlerFor(metricsRegistry, promhttp.HandlerOpts{})))
+	}
+
+	// Настройки маршрутов ищутся по шаблону пути, маршрут с опечаткой остался бы без них.
+	if err = flags.CheckRoutes("timeouts.routes", e.Routes(), tt.Timeouts.RouteNames()); err != nil {
+		logger.Error("invalid config: %w", err)
+		return err
+	}
+	if tt.RateLimit.Enabled {
+		if err = flags.CheckRoutes("rate-limit.groups", e.Routes(), tt.RateLimit.RouteNames()); err != nil {
+			logger.Error("invalid config: %w", err)
+			return err
+		}
+	}
 
 	httpServer := tt.Server.Init(e)
 	server := configTimeTracker.Server{HttpServer: httpServer}
-	if err := server.Start(); err != nil {
-		logger.Fatal(err)
+
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- server.Start()
+	}()
+	healthUsecase.SetReady(true)
+
+	select {
+	case err = <-serverErr:
+		logger.Errorf("http server: %v", err)
+	case <-ctx.Done():
+		logger.Info("shutting down")
 	}
-	return nil
+
+	// Балансировщик по /readyz перестает слать запросы, текущие запросы дообрабатываются.
+	healthUsecase.SetReady(false)
+
+	// Балансировщик замечает 503 не сразу: до следующей проверки запросы еще приходят,
+	// и сервер должен их принимать. Если сервер уже упал, ждать нечего.
+	if err == nil && tt.Server.ReadinessDrain > 0 {
+		logger.Infof("waiting %s before closing the listener", tt.Server.ReadinessDrain)
+		time.Sleep(tt.Server.ReadinessDrain)
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), tt.Server.ShutdownTimeout)
+	defer cancel()
+
+	if shutdownErr := server.Shutdown(shutdownCtx); shutdownErr != nil {
+		logger.Errorf("shutdown http server: %v", shutdownErr)
+	}
+
+	stop()
+	workers.Wait()
+
+	// Пул соединений общий у всех репозиториев, закрываем его один раз.
+	if closeErr := entryRepository.Close(); closeErr != nil {
+		logger.Errorf("close postgres: %v", closeErr)
+	}
+	if redisSessionClient != nil {
+		if closeErr := redisSessionClient.Close(); closeErr != nil {
+			logger.Errorf("close redis: %v", closeErr)
+		}
+	}
+	if redisProjectStorageClient != nil {
+		if closeErr := redisProjectStorageClient.Close(); closeErr != nil {
+			logger.Errorf("close redis cache: %v", closeErr)
+		}
+	}
+
+	return err
 }
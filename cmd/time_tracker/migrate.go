@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/db"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/migrations"
+)
+
+var errMigrateUsage = errors.New("usage: migrate up | down [steps] | status | baseline <version>")
+
+// Migrate выполняет подкоманду migrate: применяет, откатывает или показывает миграции схемы.
+func (tt TimeTracker) Migrate(args []string) error {
+	if len(args) == 0 {
+		return errMigrateUsage
+	}
+
+	ctx := context.Background()
+
+	postgresClient, err := tt.PostgresClient.Init(ctx)
+	if err != nil {
+		return fmt.Errorf("connect to postgres: %v", err)
+	}
+
+	defer func() {
+		_ = postgresClient.Close()
+	}()
+
+	files, err := fs.Sub(db.Migrations, "migrations")
+	if err != nil {
+		return fmt.Errorf("migrations dir: %v", err)
+	}
+
+	migrator, err := migrations.NewMigrator(postgresClient, files)
+	if err != nil {
+		return fmt.Errorf("load migrations: %v", err)
+	}
+
+	switch args[0] {
+	case "up":
+		done, err := migrator.Up(ctx)
+		printMigrations("applied", done)
+		return err
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				return errMigrateUsage
+			}
+		}
+
+		done, err := migrator.Down(ctx, steps)
+		printMigrations("reverted", done)
+		return err
+	case "baseline":
+		if len(args) < 2 {
+			return errMigrateUsage
+		}
+
+		version, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return errMigrateUsage
+		}
+
+		done, err := migrator.Baseline(ctx, version)
+		printMigrations("marked as applied", done)
+		return err
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+
+		printStatus(statuses)
+		return nil
+	default:
+		return errMigrateUsage
+	}
+}
+
+func printMigrations(action string, done []migrations.Migration) {
+	for _, migration := range done {
+		fmt.Printf("%s %03d_%s\n", action, migration.Version, migration.Name)
+	}
+	if len(done) == 0 {
+		fmt.Println("nothing to do")
+	}
+}
+
+func printStatus(statuses []migrations.Status) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+
+	for _, status := range statuses {
+		appliedAt := "pending"
+		if status.AppliedAt != nil {
+			appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		if status.Missing {
+			appliedAt += " (no migration file)"
+		}
+
+		_, _ = fmt.Fprintf(w, "%03d\t%s\t%s\n", status.Version, status.Name, appliedAt)
+	}
+
+	_ = w.Flush()
+}
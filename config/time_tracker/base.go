@@ -1,26 +1,42 @@
 package time_tracker
 
 import (
+	"context"
+	"fmt"
+
 	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/config/time_tracker/flags"
 	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
 type Base struct {
-	Logger   flags.LoggerFlags `toml:"logger"`
+	Logger   flags.LoggerFlags  `toml:"logger"`
+	Metrics  flags.MetricsFlags `toml:"metrics"`
+	Tracing  flags.TracingFlags `toml:"tracing"`
 	services *baseServices
 }
 
 type baseServices struct {
-	Logger echo.Logger
-	// Tracer          *otel.Tracer
-	// MetricsRegistry *metrics.Registry
+	Logger          echo.Logger
+	TracerProvider  *sdktrace.TracerProvider
+	MetricsRegistry *prometheus.Registry
 }
 
+// Init создает общие сервисы. Логгер создается первым и возвращается даже при ошибке,
+// чтобы было чем ее записать.
 func (b *Base) Init(e *echo.Echo) (*baseServices, error) {
 	services := &baseServices{}
 	logger := b.Logger.Init(e)
 	services.Logger = logger
+	services.MetricsRegistry = b.Metrics.Init()
 	b.services = services
 
+	tracerProvider, err := b.Tracing.Init(context.Background())
+	if err != nil {
+		return services, fmt.Errorf("init tracing: %v", err)
+	}
+	services.TracerProvider = tracerProvider
+
 	return services, nil
 }
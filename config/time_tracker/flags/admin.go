@@ -0,0 +1,6 @@
+package flags
+
+type AdminFlags struct {
+	// Токен обработчиков /admin/*. Пустой токен отключает их.
+	Token string `toml:"token" secret:"true"`
+}
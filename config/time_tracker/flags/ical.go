@@ -0,0 +1,8 @@
+package flags
+
+import "time"
+
+type ICalFlags struct {
+	// За какой период до текущего момента iCalendar-лента показывает записи.
+	Window time.Duration `toml:"window"`
+}
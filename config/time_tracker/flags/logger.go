@@ -6,7 +6,7 @@ import (
 )
 
 type LoggerFlags struct {
-	LogLevel      uint8  `toml:"level"`
+	LogLevel      uint8  `toml:"level" validate:"min=1,max=5"`
 	LogHeader     string `toml:"header"`
 	LogHttpFormat string `toml:"log-http-format"`
 }
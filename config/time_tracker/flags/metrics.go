@@ -0,0 +1,30 @@
+package flags
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+type MetricsFlags struct {
+	// Отдавать ли метрики на /prometheus.
+	Enabled bool `toml:"enabled"`
+	// Префикс имен метрик сервиса.
+	Namespace string `toml:"namespace"`
+	// Границы гистограммы длительности HTTP-запросов в секундах, по умолчанию стандартные.
+	Buckets []float64 `toml:"buckets"`
+	// Как часто пересчитывать бизнес-метрики по базе.
+	BusinessInterval time.Duration `toml:"business-interval" validate:"min=0"`
+}
+
+// Init создает реестр метрик со стандартными метриками процесса и рантайма Go.
+func (f MetricsFlags) Init() *prometheus.Registry {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+
+	return registry
+}
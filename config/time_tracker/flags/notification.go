@@ -0,0 +1,20 @@
+package flags
+
+import "time"
+
+type NotificationFlags struct {
+	// Как часто проверять, кому пора отправить письмо. Пустой — уведомления выключены.
+	CheckInterval time.Duration `toml:"check-interval" validate:"min=0"`
+	// Часовой пояс расписания и дат в письмах.
+	Timezone string `toml:"timezone" validate:"required,timezone"`
+	// День недели еженедельной сводки: 0 — воскресенье, 1 — понедельник.
+	DigestWeekday int `toml:"digest-weekday" validate:"min=0,max=6"`
+	// Час, начиная с которого отправляется сводка.
+	DigestHour int `toml:"digest-hour" validate:"min=0,max=23"`
+	// За сколько до окончания цели напоминать о ней.
+	EndingSoon time.Duration `toml:"ending-soon"`
+	// На сколько процентных пунктов цель может отставать от равномерного темпа без напоминания.
+	PaceTolerance float64 `toml:"pace-tolerance" validate:"min=0,max=100"`
+	// Адрес приложения для ссылок в письмах.
+	AppURL string `toml:"app-url" validate:"required,url"`
+}
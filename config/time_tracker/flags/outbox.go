@@ -0,0 +1,12 @@
+package flags
+
+import "time"
+
+type OutboxFlags struct {
+	// Как часто проверять неопубликованные события.
+	PollInterval time.Duration `toml:"poll-interval"`
+	// Сколько событий публиковать за одну проверку.
+	BatchSize int `toml:"batch-size" validate:"min=1"`
+	// Сколько хранить опубликованные события.
+	Retention time.Duration `toml:"retention"`
+}
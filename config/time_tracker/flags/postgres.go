@@ -5,19 +5,35 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/XSAM/otelsql"
 	"github.com/jmoiron/sqlx"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
 )
 
 type PostgresFlags struct {
-	ConnectionDSN      string        `toml:"dsn"`
-	MaxOpenConnections int           `toml:"max-open-connections"`
-	ConnectionLifetime time.Duration `toml:"conn-lifetime"`
+	ConnectionDSN      string        `toml:"dsn" secret:"true" validate:"required"`
+	MaxOpenConnections int           `toml:"max-open-connections" validate:"min=1"`
+	ConnectionLifetime time.Duration `toml:"conn-lifetime" validate:"min=0"`
 }
 
-func (f PostgresFlags) Init(_ context.Context) (*sqlx.DB, error) {
-	db, err := sqlx.Connect("postgres", f.ConnectionDSN)
+// Init подключается к Postgres. Каждый запрос к базе становится span'ом с текстом запроса
+// в атрибуте db.statement; параметры запроса в span не попадают.
+func (f PostgresFlags) Init(ctx context.Context) (*sqlx.DB, error) {
+	sqlDB, err := otelsql.Open("postgres", f.ConnectionDSN,
+		otelsql.WithAttributes(semconv.DBSystemPostgreSQL),
+		otelsql.WithSpanOptions(otelsql.SpanOptions{
+			OmitConnResetSession: true,
+			OmitRows:             true,
+		}),
+	)
 	if err != nil {
-		return nil, fmt.Errorf("sqlx connect: %v", err)
+		return nil, fmt.Errorf("otelsql open: %v", err)
+	}
+
+	db := sqlx.NewDb(sqlDB, "postgres")
+	if err = db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("ping: %v", err)
 	}
 
 	db.SetMaxIdleConns(f.MaxOpenConnections)
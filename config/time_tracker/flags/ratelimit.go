@@ -0,0 +1,89 @@
+package flags
+
+import (
+	"fmt"
+
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/ratelimit"
+)
+
+// Имя группы для маршрутов, не попавших ни в одну группу.
+const defaultRateLimitGroup = "default"
+
+type RateLimitFlags struct {
+	// Выключенный лимит пропускает все запросы.
+	Enabled bool                `toml:"enabled"`
+	Default RateLimitGroupFlags `toml:"default"`
+	// Группы маршрутов со своими лимитами, ключ — имя группы.
+	Groups map[string]RateLimitGroupFlags `toml:"groups"`
+}
+
+type RateLimitGroupFlags struct {
+	// user — корзина на пользователя, ip — на адрес клиента (для маршрутов без авторизации).
+	Key string `toml:"key" validate:"omitempty,oneof=user ip"`
+	// Сколько запросов в секунду в среднем. Ноль — без ограничения.
+	Rate float64 `toml:"rate" validate:"min=0"`
+	// Сколько запросов можно сделать подряд, не дожидаясь пополнения.
+	Burst int `toml:"burst" validate:"min=0"`
+	// Маршруты группы: "GET /me/projects/stat". У группы по умолчанию не используются.
+	Routes []string `toml:"routes"`
+}
+
+// Init возвращает группу по умолчанию и остальные группы. Маршрут может быть только в одной группе.
+func (f RateLimitFlags) Init() (ratelimit.Group, []ratelimit.Group, error) {
+	defaultGroup, err := f.Default.group(defaultRateLimitGroup)
+	if err != nil {
+		return ratelimit.Group{}, nil, err
+	}
+
+	groups := make([]ratelimit.Group, 0, len(f.Groups))
+	routes := make(map[string]string)
+	for name, groupFlags := range f.Groups {
+		group, err := groupFlags.group(name)
+		if err != nil {
+			return ratelimit.Group{}, nil, err
+		}
+
+		for _, route := range group.Routes {
+			if other, ok := routes[route]; ok {
+				return ratelimit.Group{}, nil, fmt.Errorf("rate limit route %q is in groups %s and %s", route, other, name)
+			}
+			routes[route] = name
+		}
+
+		groups = append(groups, group)
+	}
+
+	return defaultGroup, groups, nil
+}
+
+func (f RateLimitGroupFlags) group(name string) (ratelimit.Group, error) {
+	group := ratelimit.Group{
+		Name:   name,
+		Key:    ratelimit.Key(f.Key),
+		Limit:  ratelimit.Limit{Rate: f.Rate, Burst: f.Burst},
+		Routes: f.Routes,
+	}
+
+	if group.Limit.Unlimited() {
+		return group, nil
+	}
+
+	if group.Key != ratelimit.KeyUser && group.Key != ratelimit.KeyIP {
+		return ratelimit.Group{}, fmt.Errorf("rate limit group %s: key must be user or ip, got %q", name, f.Key)
+	}
+	if f.Burst < 1 {
+		return ratelimit.Group{}, fmt.Errorf("rate limit group %s: burst must be at least 1", name)
+	}
+
+	return group, nil
+}
+
+// RouteNames возвращает маршруты всех групп.
+func (f RateLimitFlags) RouteNames() []string {
+	var routes []string
+	for _, group := range f.Groups {
+		routes = append(routes, group.Routes...)
+	}
+
+	return routes
+}
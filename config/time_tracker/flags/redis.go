@@ -8,7 +8,7 @@ import (
 
 type RedisFlags struct {
 	Addr     string `toml:"addr"`
-	Password string `toml:"password"`
+	Password string `toml:"password" secret:"true"`
 }
 
 func (f RedisFlags) Init(ctx context.Context) (*redis.Client, error) {
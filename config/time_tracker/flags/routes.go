@@ -0,0 +1,32 @@
+package flags
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// CheckRoutes проверяет, что маршруты из конфига ("GET /me/projects/stat") зарегистрированы.
+// Мидлвары ищут настройки по шаблону маршрута, и опечатка в конфиге молча отключает настройку.
+func CheckRoutes(section string, registered []*echo.Route, routes []string) error {
+	known := make(map[string]struct{}, len(registered))
+	for _, route := range registered {
+		known[route.Method+" "+route.Path] = struct{}{}
+	}
+
+	var unknown []string
+	for _, route := range routes {
+		if _, ok := known[route]; !ok {
+			unknown = append(unknown, route)
+		}
+	}
+
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("%s: unknown routes %s", section, strings.Join(unknown, ", "))
+	}
+
+	return nil
+}
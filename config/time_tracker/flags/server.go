@@ -1,16 +1,29 @@
 package flags
 
 import (
-	"github.com/labstack/echo/v4"
+	"fmt"
+	"net"
 	"net/http"
 	"time"
+
+	"github.com/labstack/echo/v4"
 )
 
 type ServerFlags struct {
-	Addr              string        `toml:"addr"`
-	ReadTimeout       time.Duration `toml:"read-timeout"`
-	ReadHeaderTimeout time.Duration `toml:"read-header-timeout"`
-	WriteTimeout      time.Duration `toml:"write-timeout"`
+	Addr              string        `toml:"addr" validate:"required"`
+	ReadTimeout       time.Duration `toml:"read-timeout" validate:"min=0"`
+	ReadHeaderTimeout time.Duration `toml:"read-header-timeout" validate:"min=0"`
+	WriteTimeout      time.Duration `toml:"write-timeout" validate:"min=0"`
+	// Сколько при остановке отвечать 503 на /readyz, продолжая обслуживать запросы,
+	// чтобы балансировщик успел убрать экземпляр до закрытия порта.
+	ReadinessDrain time.Duration `toml:"readiness-drain" validate:"min=0"`
+	// Сколько при остановке ждать завершения текущих запросов.
+	ShutdownTimeout time.Duration `toml:"shutdown-timeout" validate:"gt=0"`
+	// Таймаут проверки каждой зависимости в /readyz.
+	HealthCheckTimeout time.Duration `toml:"health-check-timeout" validate:"min=0"`
+	// Подсети прокси, которым можно верить в X-Forwarded-For: "10.0.0.0/8".
+	// Без них адрес клиента берется из соединения, а заголовки игнорируются.
+	TrustedProxies []string `toml:"trusted-proxies" validate:"dive,cidr"`
 }
 
 func (f ServerFlags) Init(e *echo.Echo) *http.Server {
@@ -22,3 +35,27 @@ func (f ServerFlags) Init(e *echo.Echo) *http.Server {
 		WriteTimeout:      f.WriteTimeout,
 	}
 }
+
+// IPExtractor возвращает способ определить адрес клиента для c.RealIP(). По умолчанию echo
+// верит X-Forwarded-For и X-Real-IP от кого угодно, и клиент может подставить в них любой адрес.
+func (f ServerFlags) IPExtractor() (echo.IPExtractor, error) {
+	if len(f.TrustedProxies) == 0 {
+		return echo.ExtractIPDirect(), nil
+	}
+
+	// Доверяем только перечисленным подсетям, а не всем внутренним адресам, как по умолчанию.
+	options := []echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}
+	for _, proxy := range f.TrustedProxies {
+		_, ipNet, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("trusted proxy %q: %v", proxy, err)
+		}
+		options = append(options, echo.TrustIPRange(ipNet))
+	}
+
+	return echo.ExtractIPFromXFFHeader(options...), nil
+}
@@ -0,0 +1,120 @@
+package flags
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestIPExtractor(t *testing.T) {
+	tests := []struct {
+		name           string
+		trustedProxies []string
+		remoteAddr     string
+		forwardedFor   string
+		want           string
+	}{
+		{
+			name:         "headers ignored without trusted proxies",
+			remoteAddr:   "203.0.113.7:5000",
+			forwardedFor: "198.51.100.1",
+			want:         "203.0.113.7",
+		},
+		{
+			name:         "private address is not trusted by default",
+			remoteAddr:   "10.0.0.5:5000",
+			forwardedFor: "198.51.100.1",
+			want:         "10.0.0.5",
+		},
+		{
+			name:           "trusted proxy",
+			trustedProxies: []string{"10.0.0.0/8"},
+			remoteAddr:     "10.0.0.5:5000",
+			forwardedFor:   "198.51.100.1",
+			want:           "198.51.100.1",
+		},
+		{
+			name:           "client prepends a fake address",
+			trustedProxies: []string{"10.0.0.0/8"},
+			remoteAddr:     "10.0.0.5:5000",
+			forwardedFor:   "192.0.2.1, 198.51.100.1",
+			want:           "198.51.100.1",
+		},
+		{
+			name:           "untrusted sender",
+			trustedProxies: []string{"10.0.0.0/8"},
+			remoteAddr:     "203.0.113.7:5000",
+			forwardedFor:   "198.51.100.1",
+			want:           "203.0.113.7",
+		},
+		{
+			name:           "other private networks are not trusted",
+			trustedProxies: []string{"10.0.0.0/8"},
+			remoteAddr:     "192.168.1.10:5000",
+			forwardedFor:   "198.51.100.1",
+			want:           "192.168.1.10",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			extract, err := ServerFlags{TrustedProxies: tt.trustedProxies}.IPExtractor()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			req.Header.Set(echo.HeaderXForwardedFor, tt.forwardedFor)
+
+			if got := extract(req); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIPExtractorInvalidProxy(t *testing.T) {
+	if _, err := (ServerFlags{TrustedProxies: []string{"10.0.0.1"}}).IPExtractor(); err == nil {
+		t.Error("expected error for address without mask")
+	}
+}
+
+func TestCheckRoutes(t *testing.T) {
+	e := echo.New()
+	noop := func(echo.Context) error { return nil }
+	e.GET("/me/projects/stat", noop)
+	e.GET("/me/projects/:id/stat", noop)
+	e.PUT("/admin/log-level", noop)
+
+	tests := []struct {
+		name    string
+		routes  []string
+		wantErr string
+	}{
+		{name: "no routes"},
+		{name: "known routes", routes: []string{"GET /me/projects/:id/stat", "PUT /admin/log-level"}},
+		{
+			name:    "unknown routes",
+			routes:  []string{"POST /signin", "GET /me/projects/stat", "GET /me/projects/1/stat", "POST /admin/log-level"},
+			wantErr: "rate-limit.groups: unknown routes GET /me/projects/1/stat, POST /admin/log-level, POST /signin",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckRoutes("rate-limit.groups", e.Routes(), tt.routes)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("got error %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
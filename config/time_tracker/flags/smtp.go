@@ -0,0 +1,31 @@
+package flags
+
+import (
+	"time"
+
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/mailer"
+)
+
+type SMTPFlags struct {
+	Host     string `toml:"host" validate:"required"`
+	Port     int    `toml:"port" validate:"min=1,max=65535"`
+	Username string `toml:"username"`
+	Password string `toml:"password" secret:"true"`
+	// Адрес отправителя, можно с именем: "Time Tracker <noreply@example.com>".
+	From string `toml:"from" validate:"required"`
+	// Шифрование: none, starttls или tls.
+	TLS     string        `toml:"tls" validate:"omitempty,oneof=none starttls tls"`
+	Timeout time.Duration `toml:"timeout" validate:"min=0"`
+}
+
+func (f SMTPFlags) Init() (*mailer.Mailer, error) {
+	return mailer.NewMailer(mailer.Config{
+		Host:     f.Host,
+		Port:     f.Port,
+		Username: f.Username,
+		Password: f.Password,
+		From:     f.From,
+		TLSMode:  f.TLS,
+		Timeout:  f.Timeout,
+	})
+}
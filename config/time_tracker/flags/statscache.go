@@ -0,0 +1,9 @@
+package flags
+
+import "time"
+
+type StatsCacheFlags struct {
+	// Сколько хранится посчитанная статистика. Изменения данных сбрасывают кеш сразу,
+	// TTL лишь ограничивает память Redis. Ноль — без срока.
+	TTL time.Duration `toml:"ttl" validate:"min=0"`
+}
@@ -0,0 +1,21 @@
+package flags
+
+import "time"
+
+type TimeoutFlags struct {
+	// Сколько может выполняться запрос, включая запросы к базе. Ноль — без ограничения.
+	Default time.Duration `toml:"default" validate:"min=0"`
+	// Таймауты отдельных маршрутов вместо Default. Ключ — метод и путь, как при
+	// регистрации обработчика: "GET /me/projects/stat".
+	Routes map[string]time.Duration `toml:"routes"`
+}
+
+// RouteNames возвращает маршруты с отдельным таймаутом.
+func (f TimeoutFlags) RouteNames() []string {
+	routes := make([]string, 0, len(f.Routes))
+	for route := range f.Routes {
+		routes = append(routes, route)
+	}
+
+	return routes
+}
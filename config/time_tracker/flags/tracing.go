@@ -0,0 +1,86 @@
+package flags
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+)
+
+// Куда отправлять трейсы.
+const (
+	TraceExporterNone   = "none"
+	TraceExporterStdout = "stdout"
+	TraceExporterOTLP   = "otlp"
+)
+
+var ErrUnknownTraceExporter = errors.New("unknown trace exporter")
+
+type TracingFlags struct {
+	// none, stdout или otlp. При none span'ы создаются, но никуда не отправляются.
+	Exporter string `toml:"exporter" validate:"omitempty,oneof=none stdout otlp"`
+	// Адрес OTLP/HTTP коллектора, например otel-collector:4318.
+	Endpoint string `toml:"endpoint"`
+	// Отправлять в коллектор по HTTP без TLS.
+	Insecure bool `toml:"insecure"`
+	// Доля записываемых трейсов от 0 до 1. Решение о записи наследуется от вызывающего сервиса.
+	SampleRatio float64 `toml:"sample-ratio" validate:"min=0,max=1"`
+	ServiceName string  `toml:"service-name"`
+}
+
+// Init настраивает глобальный провайдер трейсов и распространение контекста W3C Trace Context.
+// Провайдер нужно остановить при завершении сервиса, чтобы отправить накопленные span'ы.
+func (f TracingFlags) Init(ctx context.Context) (*sdktrace.TracerProvider, error) {
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(f.ServiceName),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("merge resource: %v", err)
+	}
+
+	options := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(f.SampleRatio))),
+	}
+
+	switch f.Exporter {
+	case "", TraceExporterNone:
+	case TraceExporterStdout:
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+		if err != nil {
+			return nil, fmt.Errorf("new stdout exporter: %v", err)
+		}
+		options = append(options, sdktrace.WithBatcher(exporter))
+	case TraceExporterOTLP:
+		clientOptions := []otlptracehttp.Option{otlptracehttp.WithEndpoint(f.Endpoint)}
+		if f.Insecure {
+			clientOptions = append(clientOptions, otlptracehttp.WithInsecure())
+		}
+
+		exporter, err := otlptracehttp.New(ctx, clientOptions...)
+		if err != nil {
+			return nil, fmt.Errorf("new otlp exporter: %v", err)
+		}
+		options = append(options, sdktrace.WithBatcher(exporter))
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownTraceExporter, f.Exporter)
+	}
+
+	provider := sdktrace.NewTracerProvider(options...)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	return provider, nil
+}
@@ -0,0 +1,10 @@
+package flags
+
+import "time"
+
+type TrashFlags struct {
+	// Сколько удаленные данные хранятся в корзине до окончательного удаления.
+	RetentionPeriod time.Duration `toml:"retention-period"`
+	// Как часто запускать очистку корзины.
+	PurgeInterval time.Duration `toml:"purge-interval"`
+}
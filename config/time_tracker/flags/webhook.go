@@ -0,0 +1,19 @@
+package flags
+
+import "time"
+
+type WebhookFlags struct {
+	// Как часто проверять ожидающие доставки.
+	PollInterval time.Duration `toml:"poll-interval"`
+	// Сколько доставок отправлять за одну проверку.
+	BatchSize int `toml:"batch-size" validate:"min=1"`
+	// Таймаут запроса к получателю.
+	Timeout time.Duration `toml:"timeout" validate:"gt=0"`
+	// Сколько раз пытаться доставить событие, прежде чем отметить доставку неудачной.
+	MaxAttempts int `toml:"max-attempts" validate:"min=1"`
+	// Задержка перед первым повтором, каждая следующая вдвое больше.
+	RetryBase time.Duration `toml:"retry-base"`
+	// Разрешает доставку на localhost и адреса внутренней сети. Только для локальной разработки:
+	// иначе любой пользователь может заставить сервис обращаться к внутренним сервисам.
+	AllowPrivateNetworks bool `toml:"allow-private-networks"`
+}
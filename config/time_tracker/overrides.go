@@ -0,0 +1,397 @@
+package time_tracker
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/go-playground/validator.v9"
+)
+
+// EnvPrefix префикс переменных окружения, переопределяющих конфиг:
+// postgres-client.dsn задается переменной TIME_TRACKER_POSTGRES_CLIENT_DSN.
+const EnvPrefix = "TIME_TRACKER"
+
+// Суффикс переменной окружения и флага, в которых вместо значения секрета передан путь
+// к файлу с ним: TIME_TRACKER_POSTGRES_CLIENT_DSN_FILE, -postgres-client.dsn-file.
+const fileSuffix = "-file"
+
+// Так же маскирует пароль url.URL.Redacted.
+const maskedSecret = "xxxxx"
+
+var (
+	ErrInvalidConfig  = errors.New("invalid config")
+	ErrConflictingSet = errors.New("value and file are both set")
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// field поле конфига. Путь составлен из toml-тегов: "postgres-client.dsn".
+type field struct {
+	path     string
+	value    reflect.Value
+	secret   bool
+	validate string
+}
+
+// collectFields возвращает поля конфига, которые можно переопределить. cfg — указатель на структуру.
+func collectFields(cfg interface{}) []field {
+	var fields []field
+	walk(reflect.ValueOf(cfg).Elem(), "", &fields)
+
+	return fields
+}
+
+func walk(v reflect.Value, prefix string, fields *[]field) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" {
+			continue
+		}
+
+		name := strings.Split(sf.Tag.Get("toml"), ",")[0]
+		fv := v.Field(i)
+
+		if sf.Type.Kind() == reflect.Struct {
+			// Встроенная структура без тега (Base) лежит на том же уровне, что и ее владелец.
+			if sf.Anonymous && name == "" {
+				walk(fv, prefix, fields)
+			} else if name != "" {
+				walk(fv, prefix+name+".", fields)
+			}
+			continue
+		}
+
+		if name == "" {
+			continue
+		}
+
+		*fields = append(*fields, field{
+			path:     prefix + name,
+			value:    fv,
+			secret:   sf.Tag.Get("secret") == "true",
+			validate: sf.Tag.Get("validate"),
+		})
+	}
+}
+
+// EnvName возвращает имя переменной окружения для поля конфига.
+func EnvName(path string) string {
+	return EnvPrefix + "_" + strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(path))
+}
+
+// ApplyEnv переопределяет поля конфига значениями из переменных окружения. Секреты можно
+// передать файлом через переменную с суффиксом _FILE, например смонтированным docker secret.
+func ApplyEnv(cfg interface{}, lookup func(string) (string, bool)) error {
+	for _, f := range collectFields(cfg) {
+		name := EnvName(f.path)
+
+		raw, ok := lookup(name)
+		if f.secret {
+			fileName := EnvName(f.path + fileSuffix)
+			if file, fileOk := lookup(fileName); fileOk {
+				if ok {
+					return fmt.Errorf("%w: %s and %s", ErrConflictingSet, name, fileName)
+				}
+
+				var err error
+				if raw, err = readSecretFile(file); err != nil {
+					return fmt.Errorf("%s: %v", fileName, err)
+				}
+				ok = true
+			}
+		}
+
+		if !ok {
+			continue
+		}
+
+		if err := setValue(f.value, raw); err != nil {
+			return fmt.Errorf("%s: %v", name, err)
+		}
+	}
+
+	return nil
+}
+
+// FlagOverrides значения флагов командной строки, заданные при запуске.
+type FlagOverrides struct {
+	values map[string]string
+	files  map[string]string
+}
+
+// RegisterFlags добавляет в fs флаг для каждого поля конфига: -postgres-client.dsn,
+// а для секретов еще и -postgres-client.dsn-file. cfg нужен только для списка полей.
+func RegisterFlags(fs *flag.FlagSet, cfg interface{}) *FlagOverrides {
+	o := &FlagOverrides{
+		values: make(map[string]string),
+		files:  make(map[string]string),
+	}
+
+	for _, f := range collectFields(cfg) {
+		if !isSupported(f.value.Type()) {
+			continue
+		}
+
+		fs.Var(flagValue{path: f.path, values: o.values}, f.path, "overrides "+f.path+" from config")
+		if f.secret {
+			fs.Var(flagValue{path: f.path, values: o.files}, f.path+fileSuffix, "reads "+f.path+" from file")
+		}
+	}
+
+	return o
+}
+
+// Apply переопределяет поля конфига значениями флагов. Флаги главнее переменных окружения.
+func (o *FlagOverrides) Apply(cfg interface{}) error {
+	for _, f := range collectFields(cfg) {
+		raw, ok := o.values[f.path]
+		if file, fileOk := o.files[f.path]; fileOk {
+			if ok {
+				return fmt.Errorf("%w: -%s and -%s", ErrConflictingSet, f.path, f.path+fileSuffix)
+			}
+
+			var err error
+			if raw, err = readSecretFile(file); err != nil {
+				return fmt.Errorf("-%s: %v", f.path+fileSuffix, err)
+			}
+			ok = true
+		}
+
+		if !ok {
+			continue
+		}
+
+		if err := setValue(f.value, raw); err != nil {
+			return fmt.Errorf("-%s: %v", f.path, err)
+		}
+	}
+
+	return nil
+}
+
+type flagValue struct {
+	path   string
+	values map[string]string
+}
+
+func (v flagValue) String() string {
+	return v.values[v.path]
+}
+
+func (v flagValue) Set(raw string) error {
+	v.values[v.path] = raw
+	return nil
+}
+
+func readSecretFile(name string) (string, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return "", err
+	}
+
+	// Файлы секретов обычно заканчиваются переводом строки, он в значение не входит.
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+func isSupported(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	case reflect.Slice:
+		return isSupported(t.Elem())
+	case reflect.Map:
+		return t.Key().Kind() == reflect.String && isSupported(t.Elem())
+	default:
+		return false
+	}
+}
+
+// setValue разбирает строку в значение поля. Длительности записываются как в TOML ("30s"),
+// списки через запятую ("0.1,0.5,1"), словари парами через запятую ("GET /a=5s,GET /b=10s").
+func setValue(v reflect.Value, raw string) error {
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(raw, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(raw, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(raw, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		items := splitList(raw)
+		slice := reflect.MakeSlice(v.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := setValue(slice.Index(i), item); err != nil {
+				return fmt.Errorf("item %d: %v", i, err)
+			}
+		}
+		v.Set(slice)
+	case reflect.Map:
+		m := reflect.MakeMap(v.Type())
+		for _, item := range splitList(raw) {
+			key, value, ok := strings.Cut(item, "=")
+			if !ok {
+				return fmt.Errorf("item %q: expected key=value", item)
+			}
+
+			elem := reflect.New(v.Type().Elem()).Elem()
+			if err := setValue(elem, value); err != nil {
+				return fmt.Errorf("item %q: %v", key, err)
+			}
+			m.SetMapIndex(reflect.ValueOf(key), elem)
+		}
+		v.Set(m)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+
+	return nil
+}
+
+func splitList(raw string) []string {
+	if strings.TrimSpace(raw) == "" {
+		return nil
+	}
+
+	items := strings.Split(raw, ",")
+	for i := range items {
+		items[i] = strings.TrimSpace(items[i])
+	}
+
+	return items
+}
+
+// Validate проверяет конфиг по тегам validate и возвращает все ошибки сразу,
+// по одной строке на поле: "postgres-client.dsn: is required".
+func Validate(cfg interface{}) error {
+	validate := validator.New()
+	_ = validate.RegisterValidation("timezone", func(fl validator.FieldLevel) bool {
+		_, err := time.LoadLocation(fl.Field().String())
+		return err == nil
+	})
+
+	var errs []string
+	for _, f := range collectFields(cfg) {
+		if f.validate == "" {
+			continue
+		}
+
+		err := validate.Var(f.value.Interface(), f.validate)
+
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			for _, fieldErr := range validationErrs {
+				errs = append(errs, fmt.Sprintf("%s: %s", f.path, describe(fieldErr, f.value.Type())))
+			}
+		} else if err != nil {
+			return fmt.Errorf("validate %s: %v", f.path, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%w:\n  %s", ErrInvalidConfig, strings.Join(errs, "\n  "))
+	}
+
+	return nil
+}
+
+// describe переводит ошибку валидатора в понятное сообщение.
+func describe(err validator.FieldError, t reflect.Type) string {
+	param := err.Param()
+	if t == durationType && param != "" {
+		if n, parseErr := strconv.ParseInt(param, 10, 64); parseErr == nil {
+			param = time.Duration(n).String()
+		}
+	}
+
+	switch err.Tag() {
+	case "required":
+		return "is required"
+	case "min", "gte":
+		return "must be at least " + param
+	case "max", "lte":
+		return "must be at most " + param
+	case "gt":
+		return "must be greater than " + param
+	case "oneof":
+		return "must be one of: " + strings.ReplaceAll(param, " ", ", ")
+	case "url":
+		return "must be a URL"
+	case "hostname_port":
+		return "must be host:port"
+	case "cidr":
+		return "must be a subnet in CIDR notation"
+	case "timezone":
+		return "unknown time zone"
+	default:
+		return fmt.Sprintf("failed %q check", err.Tag())
+	}
+}
+
+// Print пишет действующий конфиг в формате TOML. Секреты маскируются.
+func Print(w io.Writer, cfg interface{}) error {
+	masked := reflect.New(reflect.TypeOf(cfg).Elem())
+	masked.Elem().Set(reflect.ValueOf(cfg).Elem())
+
+	for _, f := range collectFields(masked.Interface()) {
+		if f.secret && f.value.Kind() == reflect.String && f.value.String() != "" {
+			f.value.SetString(maskSecret(f.value.String()))
+		}
+	}
+
+	return toml.NewEncoder(w).Encode(masked.Interface())
+}
+
+// maskSecret скрывает секрет. Из адреса с паролем скрывается только пароль,
+// чтобы было видно, куда сервис подключается.
+func maskSecret(secret string) string {
+	u, err := url.Parse(secret)
+	if err == nil && u.Scheme != "" && u.User != nil {
+		if _, ok := u.User.Password(); ok {
+			return u.Redacted()
+		}
+	}
+
+	return maskedSecret
+}
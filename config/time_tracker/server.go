@@ -1,6 +1,8 @@
 package time_tracker
 
 import (
+	"context"
+	"errors"
 	"log"
 	"net/http"
 )
@@ -9,7 +11,20 @@ type Server struct {
 	HttpServer *http.Server
 }
 
+// Start принимает запросы, пока сервер не остановят через Shutdown.
 func (s *Server) Start() error {
 	log.Println("service starting at: ", s.HttpServer.Addr)
-	return s.HttpServer.ListenAndServe()
+
+	err := s.HttpServer.ListenAndServe()
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+
+	return err
+}
+
+// Shutdown перестает принимать новые соединения и ждет завершения текущих запросов,
+// но не дольше, чем живет ctx.
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.HttpServer.Shutdown(ctx)
 }
@@ -0,0 +1,9 @@
+// Package db содержит миграции схемы базы, встроенные в бинарник.
+package db
+
+import "embed"
+
+// Migrations файлы миграций migrations/NNN_name.up.sql и migrations/NNN_name.down.sql.
+//
+//go:embed migrations/*.sql
+var Migrations embed.FS
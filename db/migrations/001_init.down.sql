@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS entries;
+DROP TABLE IF EXISTS projects;
+DROP TABLE IF EXISTS users;
+
+DROP TYPE IF EXISTS role_type;
@@ -0,0 +1,7 @@
+ALTER TABLE projects
+    DROP COLUMN IF EXISTS workspace_id;
+
+DROP TABLE IF EXISTS workspace_members;
+DROP TABLE IF EXISTS workspaces;
+
+DROP TYPE IF EXISTS workspace_role;
@@ -0,0 +1,19 @@
+CREATE TYPE workspace_role AS ENUM ('owner', 'admin', 'member', 'viewer');
+
+CREATE TABLE IF NOT EXISTS workspaces
+(
+    id       INT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    owner_id INT         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    name     VARCHAR(35) NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS workspace_members
+(
+    workspace_id INT            NOT NULL REFERENCES workspaces (id) ON DELETE CASCADE,
+    user_id      INT            NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    role         workspace_role NOT NULL DEFAULT 'member',
+    PRIMARY KEY (workspace_id, user_id)
+);
+
+ALTER TABLE projects
+    ADD COLUMN workspace_id INT REFERENCES workspaces (id) ON DELETE CASCADE;
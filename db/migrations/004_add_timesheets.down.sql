@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS timesheets;
+
+DROP TYPE IF EXISTS timesheet_state;
@@ -0,0 +1,15 @@
+CREATE TYPE timesheet_state AS ENUM ('draft', 'submitted', 'approved', 'rejected');
+
+CREATE TABLE IF NOT EXISTS timesheets
+(
+    id           INT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    user_id      INT             NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    workspace_id INT             NOT NULL REFERENCES workspaces (id) ON DELETE CASCADE,
+    week_start   DATE            NOT NULL,
+    state        timesheet_state NOT NULL DEFAULT 'draft',
+    comment      TEXT            NOT NULL DEFAULT '',
+    reviewer_id  INT REFERENCES users (id) ON DELETE SET NULL,
+    submitted_at TIMESTAMP,
+    reviewed_at  TIMESTAMP,
+    UNIQUE (user_id, workspace_id, week_start)
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS period_lock_overrides;
+DROP TABLE IF EXISTS period_locks;
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS period_locks
+(
+    workspace_id  INT PRIMARY KEY REFERENCES workspaces (id) ON DELETE CASCADE,
+    locked_before TIMESTAMP NOT NULL,
+    locked_by     INT       REFERENCES users (id) ON DELETE SET NULL,
+    updated_at    TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS period_lock_overrides
+(
+    id           INT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    workspace_id INT         NOT NULL REFERENCES workspaces (id) ON DELETE CASCADE,
+    user_id      INT         REFERENCES users (id) ON DELETE SET NULL,
+    entry_id     INT         NOT NULL,
+    action       VARCHAR(16) NOT NULL,
+    reason       TEXT        NOT NULL,
+    created_at   TIMESTAMP   NOT NULL DEFAULT now()
+);
@@ -0,0 +1,30 @@
+CREATE TABLE IF NOT EXISTS audit_events
+(
+    id           BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    actor_id     INT         NOT NULL,
+    workspace_id INT,
+    entity_type  VARCHAR(16) NOT NULL,
+    entity_id    INT         NOT NULL,
+    action       VARCHAR(16) NOT NULL,
+    before       JSONB,
+    after        JSONB,
+    request_id   VARCHAR(64) NOT NULL DEFAULT '',
+    created_at   TIMESTAMP   NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS audit_events_actor_idx ON audit_events (actor_id, id DESC);
+CREATE INDEX IF NOT EXISTS audit_events_workspace_idx ON audit_events (workspace_id, id DESC);
+
+-- Журнал только дополняется: изменять и удалять события нельзя.
+CREATE OR REPLACE FUNCTION audit_events_append_only() RETURNS trigger AS
+$$
+BEGIN
+    RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_events_append_only
+    BEFORE UPDATE OR DELETE
+    ON audit_events
+    FOR EACH ROW
+EXECUTE FUNCTION audit_events_append_only();
@@ -0,0 +1,14 @@
+-- Записи, проекты и цели в корзине при откате становятся обычными.
+DROP INDEX IF EXISTS entries_deleted_at_idx;
+DROP INDEX IF EXISTS goals_deleted_at_idx;
+DROP INDEX IF EXISTS projects_deleted_at_idx;
+
+ALTER TABLE entries
+    DROP COLUMN IF EXISTS deleted_at;
+
+ALTER TABLE goals
+    DROP COLUMN IF EXISTS deleted_at;
+
+ALTER TABLE projects
+    DROP COLUMN IF EXISTS deleted_at,
+    DROP COLUMN IF EXISTS deleted_by;
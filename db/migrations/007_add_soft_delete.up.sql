@@ -0,0 +1,16 @@
+-- Удаленные записи, проекты и цели попадают в корзину и окончательно
+-- удаляются фоновой очисткой после истечения срока хранения.
+-- Каскадно удаленные вместе с проектом записи и цели получают тот же deleted_at.
+ALTER TABLE entries
+    ADD COLUMN deleted_at TIMESTAMP;
+
+ALTER TABLE goals
+    ADD COLUMN deleted_at TIMESTAMP;
+
+ALTER TABLE projects
+    ADD COLUMN deleted_at TIMESTAMP,
+    ADD COLUMN deleted_by INT REFERENCES users (id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS entries_deleted_at_idx ON entries (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS goals_deleted_at_idx ON goals (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS projects_deleted_at_idx ON projects (deleted_at) WHERE deleted_at IS NOT NULL;
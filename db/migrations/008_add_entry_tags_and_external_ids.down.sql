@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS entries_external_idx;
+
+ALTER TABLE entries
+    DROP COLUMN IF EXISTS tags,
+    DROP COLUMN IF EXISTS billable,
+    DROP COLUMN IF EXISTS external_source,
+    DROP COLUMN IF EXISTS external_id;
+
+ALTER TABLE projects
+    DROP COLUMN IF EXISTS client;
@@ -0,0 +1,13 @@
+-- Теги и признак оплачиваемости записи, клиент проекта, а также источник записи
+-- при импорте из других трекеров: по (external_source, external_id) повторный импорт не создает дублей.
+ALTER TABLE entries
+    ADD COLUMN tags            TEXT[]      NOT NULL DEFAULT '{}',
+    ADD COLUMN billable        BOOLEAN     NOT NULL DEFAULT false,
+    ADD COLUMN external_source VARCHAR(16),
+    ADD COLUMN external_id     VARCHAR(64);
+
+CREATE UNIQUE INDEX IF NOT EXISTS entries_external_idx ON entries (user_id, external_source, external_id)
+    WHERE external_source IS NOT NULL;
+
+ALTER TABLE projects
+    ADD COLUMN client VARCHAR(64);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS calendar_tokens;
+
+ALTER TABLE entries
+    DROP COLUMN IF EXISTS updated_at;
@@ -0,0 +1,11 @@
+-- Время последнего изменения записи: по нему календарные клиенты получают 304 на неизмененную ленту.
+ALTER TABLE entries
+    ADD COLUMN updated_at TIMESTAMP NOT NULL DEFAULT now();
+
+-- Секретные токены iCalendar-ленты. Хранится только SHA-256 токена, сам токен показывается один раз.
+CREATE TABLE IF NOT EXISTS calendar_tokens
+(
+    user_id    INT PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    created_at TIMESTAMP   NOT NULL DEFAULT now()
+);
@@ -0,0 +1,40 @@
+-- Когда цель впервые достигнута: событие goal.achieved отправляется один раз.
+ALTER TABLE goals
+    ADD COLUMN achieved_at TIMESTAMP;
+
+-- Подписки на события. Личная подписка получает события, совершенные пользователем,
+-- подписка пространства — события в проектах пространства.
+CREATE TABLE IF NOT EXISTS webhook_subscriptions
+(
+    id           INT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    user_id      INT           NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    workspace_id INT REFERENCES workspaces (id) ON DELETE CASCADE,
+    url          VARCHAR(2048) NOT NULL,
+    secret       VARCHAR(128)  NOT NULL,
+    events       TEXT[]        NOT NULL,
+    created_at   TIMESTAMP     NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS webhook_subscriptions_user_idx ON webhook_subscriptions (user_id) WHERE workspace_id IS NULL;
+CREATE INDEX IF NOT EXISTS webhook_subscriptions_workspace_idx ON webhook_subscriptions (workspace_id);
+
+-- Журнал доставок. Ожидающие доставки забирает фоновый обработчик, повторная доставка
+-- создает новую строку с тем же event_id.
+CREATE TABLE IF NOT EXISTS webhook_deliveries
+(
+    id              BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    subscription_id INT         NOT NULL REFERENCES webhook_subscriptions (id) ON DELETE CASCADE,
+    event_id        VARCHAR(32) NOT NULL,
+    event_type      VARCHAR(32) NOT NULL,
+    payload         JSONB       NOT NULL,
+    status          VARCHAR(16) NOT NULL DEFAULT 'pending',
+    attempts        INT         NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP   NOT NULL DEFAULT now(),
+    response_status INT,
+    last_error      TEXT,
+    created_at      TIMESTAMP   NOT NULL DEFAULT now(),
+    delivered_at    TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS webhook_deliveries_subscription_idx ON webhook_deliveries (subscription_id, id DESC);
+CREATE INDEX IF NOT EXISTS webhook_deliveries_pending_idx ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS webhook_deliveries_event_idx;
+
+ALTER TABLE webhook_deliveries
+    DROP COLUMN IF EXISTS redelivery;
+
+DROP TABLE IF EXISTS outbox;
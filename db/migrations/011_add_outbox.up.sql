@@ -0,0 +1,32 @@
+-- Outbox: события пишутся в одной транзакции с изменением данных, фоновый relay
+-- публикует их подписчикам и отмечает отправленными. Доставка «хотя бы один раз»,
+-- подписчики отбрасывают повторы по idempotency_key.
+CREATE TABLE IF NOT EXISTS outbox
+(
+    id              BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    idempotency_key VARCHAR(32) NOT NULL UNIQUE DEFAULT replace(gen_random_uuid()::text, '-', ''),
+    actor_id        INT         NOT NULL,
+    workspace_id    INT,
+    entity_type     VARCHAR(16) NOT NULL,
+    entity_id       INT         NOT NULL,
+    action          VARCHAR(16) NOT NULL,
+    before          JSONB,
+    after           JSONB,
+    request_id      VARCHAR(64) NOT NULL DEFAULT '',
+    created_at      TIMESTAMP   NOT NULL DEFAULT now(),
+    attempts        INT         NOT NULL DEFAULT 0,
+    available_at    TIMESTAMP   NOT NULL DEFAULT now(),
+    last_error      TEXT,
+    sent_at         TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS outbox_pending_idx ON outbox (available_at) WHERE sent_at IS NULL;
+CREATE INDEX IF NOT EXISTS outbox_sent_idx ON outbox (sent_at) WHERE sent_at IS NOT NULL;
+
+-- Повторно опубликованное событие не создает вторую доставку подписке.
+-- Ручная повторная доставка помечается redelivery и под ограничение не попадает.
+ALTER TABLE webhook_deliveries
+    ADD COLUMN redelivery BOOLEAN NOT NULL DEFAULT false;
+
+CREATE UNIQUE INDEX IF NOT EXISTS webhook_deliveries_event_idx
+    ON webhook_deliveries (subscription_id, event_id) WHERE NOT redelivery;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS notification_log;
+DROP TABLE IF EXISTS notification_settings;
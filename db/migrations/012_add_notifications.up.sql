@@ -0,0 +1,19 @@
+-- Настройки email-уведомлений. Нет строки — пользователь получает все уведомления.
+CREATE TABLE IF NOT EXISTS notification_settings
+(
+    user_id        INT PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    weekly_digest  BOOLEAN   NOT NULL DEFAULT true,
+    goal_reminders BOOLEAN   NOT NULL DEFAULT true,
+    updated_at     TIMESTAMP NOT NULL DEFAULT now()
+);
+
+-- Отправленные уведомления. Ключ определяет повод письма (неделя сводки, цель и тип напоминания),
+-- поэтому одно и то же письмо не уходит дважды, в том числе с разных экземпляров сервиса.
+CREATE TABLE IF NOT EXISTS notification_log
+(
+    user_id INT         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    kind    VARCHAR(16) NOT NULL,
+    key     VARCHAR(64) NOT NULL,
+    sent_at TIMESTAMP   NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, kind, key)
+);
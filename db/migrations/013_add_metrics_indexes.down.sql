@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS audit_events_entry_created_idx;
+DROP INDEX IF EXISTS entries_time_end_idx;
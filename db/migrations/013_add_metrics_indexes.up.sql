@@ -0,0 +1,5 @@
+-- Бизнес-метрики: записи, идущие сейчас, и записи, созданные за последнюю минуту.
+CREATE INDEX IF NOT EXISTS entries_time_end_idx ON entries (time_end) WHERE deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS audit_events_entry_created_idx ON audit_events (created_at)
+    WHERE entity_type = 'entry' AND action = 'create';
@@ -15,9 +15,39 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/entries/create": {
-            "post": {
-                "description": "Создание записи времени.",
+        "/admin/log-level": {
+            "get": {
+                "description": "Получить текущий уровень логов сервиса. Нужен токен администратора в заголовке Authorization: Bearer.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Получить уровень логов.",
+                "responses": {
+                    "200": {
+                        "description": "success get level",
+                        "schema": {
+                            "$ref": "#/definitions/internal_loglevel_delivery.LogLevelOut"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "404": {
+                        "description": "admin api is disabled",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Изменить уровень логов без перезапуска, например включить debug на время разбора проблемы. После перезапуска уровень снова берется из конфига. Нужен токен администратора в заголовке Authorization: Bearer.",
                 "consumes": [
                     "application/json"
                 ],
@@ -25,57 +55,57 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "entries"
+                    "admin"
                 ],
-                "summary": "Создание записи времени.",
+                "summary": "Изменить уровень логов.",
                 "parameters": [
                     {
-                        "description": "Информация о записи времени",
-                        "name": "entry",
+                        "description": "Уровень",
+                        "name": "level",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/internal_entry_delivery.CreateEntryIn"
+                            "$ref": "#/definitions/internal_loglevel_delivery.LogLevelIn"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "success create entry",
+                        "description": "success set level",
                         "schema": {
-                            "$ref": "#/definitions/internal_entry_delivery.CreateEntryOut"
+                            "$ref": "#/definitions/internal_loglevel_delivery.LogLevelOut"
                         }
                     },
                     "400": {
                         "description": "bad request",
                         "schema": {
-                            "$ref": "#/definitions/echo.HTTPError"
+                            "$ref": "#/definitions/Error"
                         }
                     },
-                    "404": {
-                        "description": "item is not found",
+                    "401": {
+                        "description": "unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/echo.HTTPError"
+                            "$ref": "#/definitions/Error"
                         }
                     },
-                    "422": {
-                        "description": "unprocessable entity",
+                    "404": {
+                        "description": "admin api is disabled",
                         "schema": {
-                            "$ref": "#/definitions/echo.HTTPError"
+                            "$ref": "#/definitions/Error"
                         }
                     },
-                    "500": {
-                        "description": "internal server error",
+                    "422": {
+                        "description": "unprocessable entity",
                         "schema": {
-                            "$ref": "#/definitions/echo.HTTPError"
+                            "$ref": "#/definitions/Error"
                         }
                     }
                 }
             }
         },
-        "/goals/create": {
+        "/entries/create": {
             "post": {
-                "description": "Создает цель.",
+                "description": "Создание записи времени.",
                 "consumes": [
                     "application/json"
                 ],
@@ -83,83 +113,81 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "goals"
+                    "entries"
                 ],
-                "summary": "Создание цели.",
+                "summary": "Создание записи времени.",
                 "parameters": [
                     {
-                        "description": "Информация о цели",
-                        "name": "goal",
+                        "description": "Информация о записи времени",
+                        "name": "entry",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/internal_goal_delivery.CreateGoalIn"
+                            "$ref": "#/definitions/internal_entry_delivery.CreateEntryIn"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Причина изменения в заблокированном периоде (только admin и owner)",
+                        "name": "lock_override_reason",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "success create goal",
+                        "description": "success create entry",
                         "schema": {
-                            "$ref": "#/definitions/internal_goal_delivery.CreateGoalOut"
+                            "$ref": "#/definitions/internal_entry_delivery.CreateEntryOut"
                         }
                     },
                     "400": {
                         "description": "bad request",
                         "schema": {
-                            "$ref": "#/definitions/echo.HTTPError"
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
                         }
                     },
                     "404": {
                         "description": "item is not found",
                         "schema": {
-                            "$ref": "#/definitions/echo.HTTPError"
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "409": {
+                        "description": "conflict",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
                         }
                     },
                     "422": {
                         "description": "unprocessable entity",
                         "schema": {
-                            "$ref": "#/definitions/echo.HTTPError"
+                            "$ref": "#/definitions/Error"
                         }
                     },
-                    "500": {
-                        "description": "internal server error",
+                    "423": {
+                        "description": "locked",
                         "schema": {
-                            "$ref": "#/definitions/echo.HTTPError"
+                            "$ref": "#/definitions/Error"
                         }
-                    }
-                }
-            }
-        },
-        "/me/clear_data": {
-            "delete": {
-                "description": "Очистить все пользовательские данные.",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "user"
-                ],
-                "summary": "Очистить все пользовательские данные.",
-                "responses": {
-                    "200": {
-                        "description": "success clear user data"
                     },
                     "500": {
                         "description": "internal server error",
                         "schema": {
-                            "$ref": "#/definitions/echo.HTTPError"
+                            "$ref": "#/definitions/Error"
                         }
                     }
                 }
             }
         },
-        "/me/entries": {
-            "get": {
-                "description": "Получение всех записей времени пользователя.",
+        "/entries/{id}": {
+            "put": {
+                "description": "Изменение своей записи времени. Записи недель с утвержденным табелем изменить нельзя.",
                 "consumes": [
                     "application/json"
                 ],
@@ -169,81 +197,81 @@ const docTemplate = `{
                 "tags": [
                     "entries"
                 ],
-                "summary": "Получить записи времени.",
+                "summary": "Изменение записи времени.",
                 "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор записи",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Информация о записи времени",
+                        "name": "entry",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_entry_delivery.UpdateEntryIn"
+                        }
+                    },
                     {
                         "type": "string",
-                        "description": "day for events in YYYY-MM-DD format",
-                        "name": "day",
+                        "description": "Причина изменения в заблокированном периоде (только admin и owner)",
+                        "name": "lock_override_reason",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "success create entry",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/internal_entry_delivery.EntryOut"
-                            }
-                        }
+                        "description": "success update entry"
                     },
                     "400": {
                         "description": "bad request",
                         "schema": {
-                            "$ref": "#/definitions/echo.HTTPError"
+                            "$ref": "#/definitions/Error"
                         }
                     },
-                    "500": {
-                        "description": "internal server error",
+                    "403": {
+                        "description": "forbidden",
                         "schema": {
-                            "$ref": "#/definitions/echo.HTTPError"
+                            "$ref": "#/definitions/Error"
                         }
-                    }
-                }
-            }
-        },
-        "/me/projects": {
-            "get": {
-                "description": "Получить список проектов пользователя.",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "projects"
-                ],
-                "summary": "Получить список проектов.",
-                "responses": {
-                    "200": {
-                        "description": "success get projects",
+                    },
+                    "404": {
+                        "description": "item is not found",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/internal_project_delivery.ProjectOut"
-                            }
+                            "$ref": "#/definitions/Error"
                         }
                     },
-                    "400": {
-                        "description": "bad request",
+                    "409": {
+                        "description": "conflict",
                         "schema": {
-                            "$ref": "#/definitions/echo.HTTPError"
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "422": {
+                        "description": "unprocessable entity",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "423": {
+                        "description": "locked",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
                         }
                     },
                     "500": {
                         "description": "internal server error",
                         "schema": {
-                            "$ref": "#/definitions/echo.HTTPError"
+                            "$ref": "#/definitions/Error"
                         }
                     }
                 }
-            }
-        },
-        "/me/projects/stat": {
-            "get": {
-                "description": "Получить статистику по проектам.",
+            },
+            "delete": {
+                "description": "Перенос своей записи времени в корзину. Записи недель с утвержденным табелем удалить нельзя.",
                 "consumes": [
                     "application/json"
                 ],
@@ -251,42 +279,70 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "projects"
+                    "entries"
                 ],
-                "summary": "Получить статистику по проектам.",
+                "summary": "Удаление записи времени.",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "RFC3339 format",
-                        "name": "time_start",
-                        "in": "query"
+                        "type": "integer",
+                        "description": "Идентификатор записи",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
                         "type": "string",
-                        "description": "RFC3339 format",
-                        "name": "time_end",
+                        "description": "Причина изменения в заблокированном периоде (только admin и owner)",
+                        "name": "lock_override_reason",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "success",
+                        "description": "success delete entry"
+                    },
+                    "400": {
+                        "description": "bad request",
                         "schema": {
-                            "$ref": "#/definitions/internal_project_delivery.ProjectsStatOut"
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "404": {
+                        "description": "item is not found",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "409": {
+                        "description": "conflict",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "423": {
+                        "description": "locked",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
                         }
                     },
                     "500": {
                         "description": "internal server error",
                         "schema": {
-                            "$ref": "#/definitions/echo.HTTPError"
+                            "$ref": "#/definitions/Error"
                         }
                     }
                 }
             }
         },
-        "/me/projects/{id}/stat": {
-            "get": {
-                "description": "Получить статистику по конкретному проекту.",
+        "/entries/{id}/restore": {
+            "post": {
+                "description": "Восстановление своей записи времени из корзины. Если проект записи тоже в корзине, сначала нужно восстановить проект.",
                 "consumes": [
                     "application/json"
                 ],
@@ -294,55 +350,70 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "projects"
+                    "trash"
                 ],
-                "summary": "Получить статистику по конкретному проекту.",
+                "summary": "Восстановление записи времени.",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "project ID",
+                        "description": "Идентификатор записи",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "RFC3339 format",
-                        "name": "time_start",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "RFC3339 format",
-                        "name": "time_end",
+                        "description": "Причина изменения в заблокированном периоде (только admin и owner)",
+                        "name": "lock_override_reason",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "success",
-                        "schema": {
-                            "$ref": "#/definitions/internal_project_delivery.ProjectEntriesStatOut"
-                        }
+                        "description": "success restore entry"
                     },
                     "400": {
                         "description": "bad request",
                         "schema": {
-                            "$ref": "#/definitions/echo.HTTPError"
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "404": {
+                        "description": "item is not found",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "409": {
+                        "description": "conflict",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "423": {
+                        "description": "locked",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
                         }
                     },
                     "500": {
                         "description": "internal server error",
                         "schema": {
-                            "$ref": "#/definitions/echo.HTTPError"
+                            "$ref": "#/definitions/Error"
                         }
                     }
                 }
             }
         },
-        "/me/projects/{project_id}/goals": {
-            "get": {
-                "description": "Получить все цели пользователя по проекту.",
+        "/goals/create": {
+            "post": {
+                "description": "Создает цель.",
                 "consumes": [
                     "application/json"
                 ],
@@ -352,44 +423,108 @@ const docTemplate = `{
                 "tags": [
                     "goals"
                 ],
-                "summary": "Получить все цели по проекту.",
+                "summary": "Создание цели.",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Идентификатор проекта",
-                        "name": "project_id",
-                        "in": "path",
-                        "required": true
+                        "description": "Информация о цели",
+                        "name": "goal",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_goal_delivery.CreateGoalIn"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "success get goals",
+                        "description": "success create goal",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/internal_goal_delivery.GoalOut"
-                            }
+                            "$ref": "#/definitions/internal_goal_delivery.CreateGoalOut"
                         }
                     },
                     "400": {
                         "description": "bad request",
                         "schema": {
-                            "$ref": "#/definitions/echo.HTTPError"
+                            "$ref": "#/definitions/Error"
                         }
                     },
-                    "500": {
-                        "description": "internal server error",
+                    "403": {
+                        "description": "forbidden",
                         "schema": {
-                            "$ref": "#/definitions/echo.HTTPError"
+                            "$ref": "#/definitions/Error"
                         }
-                    }
-                }
-            }
-        },
-        "/projects/create": {
+                    },
+                    "404": {
+                        "description": "item is not found",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "422": {
+                        "description": "unprocessable entity",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/goals/{id}": {
+            "delete": {
+                "description": "Перенос своей цели в корзину.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "goals"
+                ],
+                "summary": "Удаление цели.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор цели",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success delete goal"
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "404": {
+                        "description": "item is not found",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/goals/{id}/restore": {
             "post": {
-                "description": "Создать проект.",
+                "description": "Восстановление своей цели из корзины. Если проект цели тоже в корзине, сначала нужно восстановить проект.",
                 "consumes": [
                     "application/json"
                 ],
@@ -397,64 +532,3729 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "projects"
+                    "trash"
                 ],
-                "summary": "Создать проект.",
+                "summary": "Восстановление цели.",
                 "parameters": [
                     {
-                        "description": "project info",
-                        "name": "project",
-                        "in": "body",
-                        "required": true,
+                        "type": "integer",
+                        "description": "Идентификатор цели",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success restore goal"
+                    },
+                    "400": {
+                        "description": "bad request",
                         "schema": {
-                            "$ref": "#/definitions/internal_project_delivery.CreateProjectIn"
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "404": {
+                        "description": "item is not found",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "409": {
+                        "description": "conflict",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/healthz": {
+            "get": {
+                "description": "Отвечает 200, пока процесс обрабатывает запросы. Зависимости не проверяются: их недоступность не лечится перезапуском.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Проверка живости.",
+                "responses": {
+                    "200": {
+                        "description": "alive",
+                        "schema": {
+                            "$ref": "#/definitions/internal_health_delivery.HealthOut"
                         }
                     }
+                }
+            }
+        },
+        "/ical/{file}": {
+            "get": {
+                "description": "Записи пользователя за скользящее окно в виде событий VEVENT: название записи — SUMMARY, проект — CATEGORIES. Доступна без авторизации по секретному токену. Поддерживает If-None-Match и If-Modified-Since.",
+                "produces": [
+                    "text/calendar"
+                ],
+                "tags": [
+                    "calendar"
+                ],
+                "summary": "iCalendar-лента записей времени.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Токен с суффиксом .ics",
+                        "name": "file",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
                 "responses": {
                     "200": {
-                        "description": "success create project",
+                        "description": "success get feed"
+                    },
+                    "304": {
+                        "description": "not modified"
+                    },
+                    "404": {
+                        "description": "item is not found",
                         "schema": {
-                            "$ref": "#/definitions/internal_project_delivery.CreateProjectOut"
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/audit": {
+            "get": {
+                "description": "Постраничная история изменений записей, проектов и целей, совершенных пользователем.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "audit"
+                ],
+                "summary": "История изменений пользователя.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы (по умолчанию 50, максимум 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Вернуть события старше указанного",
+                        "name": "before_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success get audit",
+                        "schema": {
+                            "$ref": "#/definitions/internal_audit_delivery.AuditPageOut"
                         }
                     },
                     "400": {
                         "description": "bad request",
                         "schema": {
-                            "$ref": "#/definitions/echo.HTTPError"
+                            "$ref": "#/definitions/Error"
                         }
                     },
-                    "422": {
-                        "description": "unprocessable entity",
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/calendar/token": {
+            "post": {
+                "description": "Выпустить секретную ссылку на iCalendar-ленту записей времени. Прежняя ссылка перестает работать. Токен показывается только в этом ответе.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "calendar"
+                ],
+                "summary": "Выпустить ссылку на календарь.",
+                "responses": {
+                    "200": {
+                        "description": "success create token",
+                        "schema": {
+                            "$ref": "#/definitions/internal_calendar_delivery.CalendarTokenOut"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Отключить iCalendar-ленту записей времени.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "calendar"
+                ],
+                "summary": "Отключить ссылку на календарь.",
+                "responses": {
+                    "200": {
+                        "description": "success delete token"
+                    },
+                    "404": {
+                        "description": "item is not found",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/clear_data": {
+            "delete": {
+                "description": "Перенести в корзину все записи, цели и личные проекты пользователя. Перед очисткой данные можно сохранить через GET /me/export.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Очистить все пользовательские данные.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Причина удаления записей в заблокированном периоде (только admin и owner)",
+                        "name": "lock_override_reason",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success clear user data"
+                    },
+                    "409": {
+                        "description": "conflict",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "423": {
+                        "description": "locked",
                         "schema": {
-                            "$ref": "#/definitions/echo.HTTPError"
+                            "$ref": "#/definitions/Error"
                         }
                     },
                     "500": {
                         "description": "internal server error",
                         "schema": {
-                            "$ref": "#/definitions/echo.HTTPError"
+                            "$ref": "#/definitions/Error"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "echo.HTTPError": {
+        },
+        "/me/entries": {
+            "get": {
+                "description": "Получение всех записей времени пользователя.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "entries"
+                ],
+                "summary": "Получить записи времени.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "day for events in YYYY-MM-DD format",
+                        "name": "day",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success create entry",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/internal_entry_delivery.EntryOut"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/entries/export": {
+            "get": {
+                "description": "Выгрузить записи времени пользователя в CSV или XLSX. Даты и числа форматируются по локали: из параметра locale или заголовка Accept-Language.",
+                "produces": [
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "entries"
+                ],
+                "summary": "Выгрузить записи времени.",
+                "parameters": [
+                    {
+                        "enum": [
+                            "csv",
+                            "xlsx"
+                        ],
+                        "type": "string",
+                        "description": "Формат файла",
+                        "name": "format",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало интервала в формате YYYY-MM-DD",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Конец интервала в формате YYYY-MM-DD, включительно",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор проекта",
+                        "name": "project",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "ru",
+                            "en"
+                        ],
+                        "type": "string",
+                        "description": "Локаль форматирования",
+                        "name": "locale",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success export entries",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/entries/import": {
+            "post": {
+                "description": "Импорт записей из CSV-файла по описанию колонок. Отсутствующие личные проекты создаются. Каждая строка проверяется как при создании записи; если есть ошибки, ничего не сохраняется и возвращается отчет по строкам. При dry_run=true только возвращается отчет.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "entries"
+                ],
+                "summary": "Импорт записей времени из CSV.",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "CSV-файл с заголовком",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Описание колонок, JSON ImportMappingIn",
+                        "name": "mapping",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Пробный запуск",
+                        "name": "dry_run",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success import entries",
+                        "schema": {
+                            "$ref": "#/definitions/internal_entry_delivery.ImportResultOut"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "422": {
+                        "description": "rows with errors, nothing is imported",
+                        "schema": {
+                            "$ref": "#/definitions/internal_entry_delivery.ImportResultOut"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/entries/import/ical": {
+            "post": {
+                "description": "Импорт событий из .ics-файла как записей времени. Проект события выбирается по правилам mapping (календарь, категория, подстрока заголовка), повторяющиеся события разворачиваются по RRULE с учетом EXDATE и измененных вхождений. События на весь день, отмененные и отклоненные пропускаются. Повторный импорт не создает дублей: вхождение определяется по UID события. По умолчанию импортируются события до текущего момента.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "entries"
+                ],
+                "summary": "Импорт событий календаря.",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Файл .ics",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Правила, JSON CalendarMappingIn",
+                        "name": "mapping",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало периода, YYYY-MM-DD",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Окончание периода включительно, YYYY-MM-DD",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Пробный запуск",
+                        "name": "dry_run",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success import entries",
+                        "schema": {
+                            "$ref": "#/definitions/internal_entry_delivery.ImportResultOut"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "422": {
+                        "description": "unprocessable entity",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/entries/import/{source}": {
+            "post": {
+                "description": "Импорт выгрузки другого трекера: toggl — детальный отчет CSV или JSON, clockify — детальный отчет CSV. Клиенты, проекты, теги, описания и признак оплачиваемости переносятся в проекты и записи, отсутствующие личные проекты создаются. Повторный импорт не создает дублей. Строки, которые нельзя импортировать, пропускаются и возвращаются в skipped, остальные сохраняются. При dry_run=true только возвращается отчет.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "entries"
+                ],
+                "summary": "Импорт записей времени из Toggl Track или Clockify.",
+                "parameters": [
+                    {
+                        "enum": [
+                            "toggl",
+                            "clockify"
+                        ],
+                        "type": "string",
+                        "description": "Трекер",
+                        "name": "source",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Файл выгрузки",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Часовой пояс времени без смещения, по умолчанию UTC",
+                        "name": "timezone",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Пробный запуск",
+                        "name": "dry_run",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success import entries",
+                        "schema": {
+                            "$ref": "#/definitions/internal_entry_delivery.ImportResultOut"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/export": {
+            "get": {
+                "description": "Выгрузить профиль, проекты, записи и цели пользователя в версионированный JSON-архив для переноса в другую инсталляцию. Рекомендуется перед /me/clear_data.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Выгрузка аккаунта.",
+                "responses": {
+                    "200": {
+                        "description": "success export account",
+                        "schema": {
+                            "$ref": "#/definitions/internal_account_delivery.Archive"
+                        }
+                    },
+                    "404": {
+                        "description": "item is not found",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/import": {
+            "post": {
+                "description": "Восстановить архив из /me/export в пустой или существующий аккаунт. Идентификаторы переназначаются: проекты сопоставляются с личными проектами по названию или создаются, проекты пространств становятся личными. Записи и цели, которые уже есть в аккаунте, пропускаются, поэтому повторный импорт безопасен. Email аккаунта не меняется.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Импорт аккаунта.",
+                "parameters": [
+                    {
+                        "description": "Архив аккаунта",
+                        "name": "archive",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_account_delivery.Archive"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success import account",
+                        "schema": {
+                            "$ref": "#/definitions/internal_account_delivery.ImportResultOut"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "422": {
+                        "description": "unprocessable entity",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/notifications": {
+            "get": {
+                "description": "Получить настройки email-уведомлений: еженедельной сводки и напоминаний о целях. По умолчанию все уведомления включены.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Получить настройки уведомлений.",
+                "responses": {
+                    "200": {
+                        "description": "success get settings",
+                        "schema": {
+                            "$ref": "#/definitions/internal_notification_delivery.NotificationSettingsOut"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Включить или отключить еженедельную сводку (время по проектам за неделю и прогресс целей) и напоминания о целях, которые отстают от темпа или скоро заканчиваются.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Изменить настройки уведомлений.",
+                "parameters": [
+                    {
+                        "description": "Настройки",
+                        "name": "settings",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_notification_delivery.NotificationSettingsIn"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success update settings",
+                        "schema": {
+                            "$ref": "#/definitions/internal_notification_delivery.NotificationSettingsOut"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "422": {
+                        "description": "unprocessable entity",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/projects": {
+            "get": {
+                "description": "Получить список личных проектов пользователя и проектов его пространств.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Получить список проектов.",
+                "responses": {
+                    "200": {
+                        "description": "success get projects",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/internal_project_delivery.ProjectOut"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/projects/stat": {
+            "get": {
+                "description": "Получить статистику по проектам.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Получить статистику по проектам.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "RFC3339 format",
+                        "name": "time_start",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC3339 format",
+                        "name": "time_end",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success",
+                        "schema": {
+                            "$ref": "#/definitions/internal_project_delivery.ProjectsStatOut"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/projects/stat/export": {
+            "get": {
+                "description": "Выгрузить статистику по проектам в CSV или XLSX. Числа форматируются по локали: из параметра locale или заголовка Accept-Language.",
+                "produces": [
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Выгрузить статистику по проектам.",
+                "parameters": [
+                    {
+                        "enum": [
+                            "csv",
+                            "xlsx"
+                        ],
+                        "type": "string",
+                        "description": "Формат файла",
+                        "name": "format",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC3339 format",
+                        "name": "time_start",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC3339 format",
+                        "name": "time_end",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "ru",
+                            "en"
+                        ],
+                        "type": "string",
+                        "description": "Локаль форматирования",
+                        "name": "locale",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success export stat",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/projects/{id}/stat": {
+            "get": {
+                "description": "Получить статистику по конкретному проекту.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Получить статистику по конкретному проекту.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC3339 format",
+                        "name": "time_start",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC3339 format",
+                        "name": "time_end",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success",
+                        "schema": {
+                            "$ref": "#/definitions/internal_project_delivery.ProjectEntriesStatOut"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/projects/{project_id}/goals": {
+            "get": {
+                "description": "Получить все цели пользователя по проекту.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "goals"
+                ],
+                "summary": "Получить все цели по проекту.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор проекта",
+                        "name": "project_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success get goals",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/internal_goal_delivery.GoalOut"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/timesheets": {
+            "get": {
+                "description": "Получить табели пользователя во всех пространствах.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "timesheets"
+                ],
+                "summary": "Получить свои табели.",
+                "responses": {
+                    "200": {
+                        "description": "success get timesheets",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/internal_timesheet_delivery.TimesheetOut"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Создать черновик недельного табеля в пространстве.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "timesheets"
+                ],
+                "summary": "Создать табель.",
+                "parameters": [
+                    {
+                        "description": "Информация о табеле",
+                        "name": "timesheet",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_timesheet_delivery.CreateTimesheetIn"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success create timesheet",
+                        "schema": {
+                            "$ref": "#/definitions/internal_timesheet_delivery.CreateTimesheetOut"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "409": {
+                        "description": "conflict",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "422": {
+                        "description": "unprocessable entity",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/timesheets/{id}/submit": {
+            "post": {
+                "description": "Перевести свой табель из draft или rejected в submitted.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "timesheets"
+                ],
+                "summary": "Отправить табель на утверждение.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор табеля",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success submit timesheet"
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "404": {
+                        "description": "item is not found",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "409": {
+                        "description": "conflict",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/trash": {
+            "get": {
+                "description": "Получить удаленные записи, проекты и цели пользователя. Записи и цели, удаленные вместе с проектом, восстанавливаются вместе с ним.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Получить корзину.",
+                "responses": {
+                    "200": {
+                        "description": "success get trash",
+                        "schema": {
+                            "$ref": "#/definitions/internal_trash_delivery.TrashOut"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/workspaces": {
+            "get": {
+                "description": "Получить список пространств, в которых состоит пользователь, с его ролью.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workspaces"
+                ],
+                "summary": "Получить список пространств.",
+                "responses": {
+                    "200": {
+                        "description": "success get workspaces",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/internal_workspace_delivery.WorkspaceOut"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/create": {
+            "post": {
+                "description": "Создать проект.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Создать проект.",
+                "parameters": [
+                    {
+                        "description": "project info",
+                        "name": "project",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_project_delivery.CreateProjectIn"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success create project",
+                        "schema": {
+                            "$ref": "#/definitions/internal_project_delivery.CreateProjectOut"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "422": {
+                        "description": "unprocessable entity",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}": {
+            "delete": {
+                "description": "Перенести проект в корзину вместе со всеми его записями и целями. Проект пространства могут удалить admin и owner.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Удалить проект.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор проекта",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Причина удаления записей в заблокированном периоде (только admin и owner)",
+                        "name": "lock_override_reason",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success delete project"
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "404": {
+                        "description": "item is not found",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "409": {
+                        "description": "conflict",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "423": {
+                        "description": "locked",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/restore": {
+            "post": {
+                "description": "Вернуть проект из корзины вместе с записями и целями, удаленными вместе с ним.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Восстановить проект.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор проекта",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success restore project"
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "404": {
+                        "description": "item is not found",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/readyz": {
+            "get": {
+                "description": "Отвечает 200, если сервис запущен и доступны Postgres и Redis (если он настроен). Во время остановки и при недоступной зависимости отвечает 503.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Проверка готовности.",
+                "responses": {
+                    "200": {
+                        "description": "ready",
+                        "schema": {
+                            "$ref": "#/definitions/internal_health_delivery.ReadinessOut"
+                        }
+                    },
+                    "503": {
+                        "description": "not ready",
+                        "schema": {
+                            "$ref": "#/definitions/internal_health_delivery.ReadinessOut"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks": {
+            "get": {
+                "description": "Получить личные подписки пользователя или подписки пространства (доступно admin и owner). Ключи подписи не возвращаются.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Получить подписки.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор пространства",
+                        "name": "workspace_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success get subscriptions",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/internal_webhook_delivery.SubscriptionOut"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Создать подписку на события записей, проектов и целей: entry.created, entry.updated, entry.deleted, entry.restored, entry.imported, project.created, project.deleted, project.restored, goal.created, goal.deleted, goal.restored, goal.achieved. Личная подписка получает события, совершенные пользователем, подписка пространства (доступно admin и owner) — события в проектах пространства. События отправляются POST-запросом с JSON; заголовок X-Webhook-Signature содержит sha256=HMAC-SHA256 от \"\u003cX-Webhook-Timestamp\u003e.\u003cтело\u003e\" ключом подписки, X-Webhook-Id — идентификатор события для отбрасывания дублей. Неудачные доставки повторяются с экспоненциальной задержкой. Ключ возвращается только в ответе на создание.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Подписаться на события.",
+                "parameters": [
+                    {
+                        "description": "Подписка",
+                        "name": "subscription",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_webhook_delivery.SubscriptionIn"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success create subscription",
+                        "schema": {
+                            "$ref": "#/definitions/internal_webhook_delivery.SubscriptionOut"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "422": {
+                        "description": "unprocessable entity",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}": {
+            "delete": {
+                "description": "Удалить подписку вместе с журналом доставок.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Удалить подписку.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор подписки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success delete subscription"
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "404": {
+                        "description": "item is not found",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/deliveries": {
+            "get": {
+                "description": "Постраничный журнал доставок подписки: статус, число попыток, код ответа и ошибка последней попытки.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Журнал доставок.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор подписки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы (по умолчанию 50, максимум 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Вернуть доставки старше указанной",
+                        "name": "before_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success get deliveries",
+                        "schema": {
+                            "$ref": "#/definitions/internal_webhook_delivery.DeliveryPageOut"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "404": {
+                        "description": "item is not found",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/deliveries/{delivery_id}/redeliver": {
+            "post": {
+                "description": "Повторно отправить событие из журнала доставок с тем же идентификатором события. Создает новую доставку.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Повторить доставку.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор подписки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор доставки",
+                        "name": "delivery_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success redeliver",
+                        "schema": {
+                            "$ref": "#/definitions/internal_webhook_delivery.RedeliverOut"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "404": {
+                        "description": "item is not found",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/create": {
+            "post": {
+                "description": "Создать общее пространство, текущий пользователь становится его владельцем.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workspaces"
+                ],
+                "summary": "Создать пространство.",
+                "parameters": [
+                    {
+                        "description": "workspace info",
+                        "name": "workspace",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_workspace_delivery.CreateWorkspaceIn"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success create workspace",
+                        "schema": {
+                            "$ref": "#/definitions/internal_workspace_delivery.CreateWorkspaceOut"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "422": {
+                        "description": "unprocessable entity",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspace_id}/audit": {
+            "get": {
+                "description": "Постраничная история изменений в проектах пространства. Доступно admin и owner.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "audit"
+                ],
+                "summary": "История изменений пространства.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор пространства",
+                        "name": "workspace_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы (по умолчанию 50, максимум 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Вернуть события старше указанного",
+                        "name": "before_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success get audit",
+                        "schema": {
+                            "$ref": "#/definitions/internal_audit_delivery.AuditPageOut"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspace_id}/lock": {
+            "get": {
+                "description": "Получить текущую границу заблокированного периода пространства.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "period lock"
+                ],
+                "summary": "Получить блокировку периода.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор пространства",
+                        "name": "workspace_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success get lock",
+                        "schema": {
+                            "$ref": "#/definitions/internal_periodlock_delivery.LockOut"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "404": {
+                        "description": "item is not found",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Запретить создание, изменение и удаление записей пространства, начавшихся раньше указанного времени. Доступно admin и owner.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "period lock"
+                ],
+                "summary": "Заблокировать период.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор пространства",
+                        "name": "workspace_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Граница блокировки",
+                        "name": "lock",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_periodlock_delivery.SetLockIn"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success set lock"
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "422": {
+                        "description": "unprocessable entity",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Снять блокировку периода пространства. Доступно admin и owner.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "period lock"
+                ],
+                "summary": "Снять блокировку периода.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор пространства",
+                        "name": "workspace_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success delete lock"
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "404": {
+                        "description": "item is not found",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspace_id}/lock/overrides": {
+            "get": {
+                "description": "Получить изменения записей, выполненные администраторами в обход блокировки периода. Доступно admin и owner.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "period lock"
+                ],
+                "summary": "Журнал изменений в заблокированном периоде.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор пространства",
+                        "name": "workspace_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success get overrides",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/internal_periodlock_delivery.OverrideOut"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspace_id}/members": {
+            "get": {
+                "description": "Получить участников пространства, доступно любому участнику.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workspaces"
+                ],
+                "summary": "Получить участников пространства.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор пространства",
+                        "name": "workspace_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success get members",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/internal_workspace_delivery.MemberOut"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Добавить в пространство зарегистрированного пользователя по email. Доступно admin и owner, роль admin выдает только owner.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workspaces"
+                ],
+                "summary": "Пригласить участника.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор пространства",
+                        "name": "workspace_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Информация об участнике",
+                        "name": "member",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_workspace_delivery.InviteMemberIn"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success invite member",
+                        "schema": {
+                            "$ref": "#/definitions/internal_workspace_delivery.InviteMemberOut"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "404": {
+                        "description": "item is not found",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "409": {
+                        "description": "conflict",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "422": {
+                        "description": "unprocessable entity",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspace_id}/members/{user_id}": {
+            "put": {
+                "description": "Изменить роль участника пространства. Роль владельца не меняется, администраторов меняет только owner.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workspaces"
+                ],
+                "summary": "Изменить роль участника.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор пространства",
+                        "name": "workspace_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор участника",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новая роль",
+                        "name": "member",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_workspace_delivery.UpdateMemberIn"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success update member"
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "404": {
+                        "description": "item is not found",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "422": {
+                        "description": "unprocessable entity",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Удалить участника из пространства. Участник может удалить сам себя, владелец пространство не покидает.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workspaces"
+                ],
+                "summary": "Удалить участника.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор пространства",
+                        "name": "workspace_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор участника",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success remove member"
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "404": {
+                        "description": "item is not found",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspace_id}/projects": {
+            "get": {
+                "description": "Получить список проектов пространства, доступно любому участнику.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Получить список проектов пространства.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор пространства",
+                        "name": "workspace_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success get projects",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/internal_project_delivery.ProjectOut"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspace_id}/reports": {
+            "get": {
+                "description": "Время по проектам пространства за интервал. Для admin и owner отчет содержит разбивку по участникам, остальным доступны только итоги.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Получить отчет по пространству.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор пространства",
+                        "name": "workspace_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC3339 format",
+                        "name": "time_start",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC3339 format",
+                        "name": "time_end",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success",
+                        "schema": {
+                            "$ref": "#/definitions/internal_report_delivery.WorkspaceReportOut"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspace_id}/reports/members/{user_id}/entries": {
+            "get": {
+                "description": "Записи участника по проектам пространства за интервал. Чужие записи доступны только admin и owner.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Получить записи участника пространства.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор пространства",
+                        "name": "workspace_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор участника",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC3339 format",
+                        "name": "time_start",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC3339 format",
+                        "name": "time_end",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/internal_report_delivery.MemberEntryOut"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspace_id}/timesheets": {
+            "get": {
+                "description": "Получить табели участников пространства, доступно admin и owner.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "timesheets"
+                ],
+                "summary": "Получить табели пространства.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор пространства",
+                        "name": "workspace_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Фильтр по состоянию: draft, submitted, approved, rejected",
+                        "name": "state",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success get timesheets",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/internal_timesheet_delivery.TimesheetOut"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspace_id}/timesheets/{id}/approve": {
+            "post": {
+                "description": "Утвердить отправленный табель участника. После утверждения записи недели в проектах пространства становятся только для чтения.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "timesheets"
+                ],
+                "summary": "Утвердить табель.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор пространства",
+                        "name": "workspace_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор табеля",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Комментарий",
+                        "name": "review",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/internal_timesheet_delivery.ReviewTimesheetIn"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success approve timesheet"
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "404": {
+                        "description": "item is not found",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "409": {
+                        "description": "conflict",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "422": {
+                        "description": "unprocessable entity",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspace_id}/timesheets/{id}/reject": {
+            "post": {
+                "description": "Отклонить отправленный табель участника с комментарием.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "timesheets"
+                ],
+                "summary": "Отклонить табель.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор пространства",
+                        "name": "workspace_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор табеля",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Комментарий",
+                        "name": "review",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_timesheet_delivery.ReviewTimesheetIn"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success reject timesheet"
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "404": {
+                        "description": "item is not found",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "409": {
+                        "description": "conflict",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "422": {
+                        "description": "unprocessable entity",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "Error": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Машиночитаемый код ошибки.",
+                    "type": "string",
+                    "example": "validation_failed"
+                },
+                "details": {
+                    "description": "Ошибки в полях запроса.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/FieldError"
+                    }
+                },
+                "message": {
+                    "description": "Описание для человека, может меняться.",
+                    "type": "string",
+                    "example": "bad request"
+                },
+                "request_id": {
+                    "description": "Идентификатор запроса из X-Request-ID.",
+                    "type": "string",
+                    "example": "8f14e45fceea167a5a36dedd"
+                },
+                "status": {
+                    "description": "HTTP-статус.",
+                    "type": "integer",
+                    "example": 400
+                }
+            }
+        },
+        "FieldError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "description": "Поле в JSON или имя параметра.",
+                    "type": "string",
+                    "example": "time_end"
+                },
+                "param": {
+                    "description": "Параметр правила: для min=1 это 1.",
+                    "type": "string"
+                },
+                "rule": {
+                    "description": "Нарушенное правило: required, min, oneof, int...",
+                    "type": "string",
+                    "example": "required"
+                }
+            }
+        },
+        "internal_account_delivery.Archive": {
+            "type": "object",
+            "required": [
+                "version"
+            ],
+            "properties": {
+                "entries": {
+                    "description": "Записи времени.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_account_delivery.ArchiveEntry"
+                    }
+                },
+                "exported_at": {
+                    "description": "Время выгрузки.",
+                    "type": "string",
+                    "example": "2024-03-23T15:04:05Z"
+                },
+                "goals": {
+                    "description": "Цели.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_account_delivery.ArchiveGoal"
+                    }
+                },
+                "profile": {
+                    "description": "Профиль.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/internal_account_delivery.ArchiveProfile"
+                        }
+                    ]
+                },
+                "projects": {
+                    "description": "Проекты.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_account_delivery.ArchiveProject"
+                    }
+                },
+                "version": {
+                    "description": "Версия формата архива.",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "internal_account_delivery.ArchiveEntry": {
+            "type": "object",
+            "required": [
+                "project_id",
+                "time_end",
+                "time_start"
+            ],
+            "properties": {
+                "billable": {
+                    "description": "Оплачиваемое время.",
+                    "type": "boolean",
+                    "example": true
+                },
+                "external_id": {
+                    "description": "Идентификатор записи в трекере.",
+                    "type": "string",
+                    "example": "42"
+                },
+                "external_source": {
+                    "description": "Трекер, из которого запись импортирована.",
+                    "type": "string",
+                    "example": "toggl"
+                },
+                "id": {
+                    "description": "Идентификатор записи в архиве.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "name": {
+                    "description": "Название записи.",
+                    "type": "string",
+                    "example": "task1"
+                },
+                "project_id": {
+                    "description": "Идентификатор проекта в архиве.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "tags": {
+                    "description": "Теги записи.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "meeting"
+                    ]
+                },
+                "time_end": {
+                    "description": "Время окончания записи.",
+                    "type": "string",
+                    "example": "2024-03-23T19:04:05Z"
+                },
+                "time_start": {
+                    "description": "Время начала записи.",
+                    "type": "string",
+                    "example": "2024-03-23T15:04:05Z"
+                }
+            }
+        },
+        "internal_account_delivery.ArchiveGoal": {
+            "type": "object",
+            "required": [
+                "date_end",
+                "date_start",
+                "name",
+                "project_id",
+                "time_seconds"
+            ],
+            "properties": {
+                "date_end": {
+                    "description": "Окончание периода цели.",
+                    "type": "string",
+                    "example": "2024-03-31T00:00:00Z"
+                },
+                "date_start": {
+                    "description": "Начало периода цели.",
+                    "type": "string",
+                    "example": "2024-03-01T00:00:00Z"
+                },
+                "id": {
+                    "description": "Идентификатор цели в архиве.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "name": {
+                    "description": "Название цели.",
+                    "type": "string",
+                    "example": "Диплом"
+                },
+                "project_id": {
+                    "description": "Идентификатор проекта в архиве.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "time_seconds": {
+                    "description": "Целевое время в секундах.",
+                    "type": "integer",
+                    "example": 36000
+                }
+            }
+        },
+        "internal_account_delivery.ArchiveProfile": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "description": "Email, при импорте не меняется.",
+                    "type": "string",
+                    "example": "ivan@example.com"
+                },
+                "name": {
+                    "description": "Имя пользователя.",
+                    "type": "string",
+                    "example": "Иван"
+                }
+            }
+        },
+        "internal_account_delivery.ArchiveProject": {
+            "type": "object",
+            "required": [
+                "id",
+                "name"
+            ],
+            "properties": {
+                "client": {
+                    "description": "Клиент проекта.",
+                    "type": "string",
+                    "example": "ООО Ромашка"
+                },
+                "id": {
+                    "description": "Идентификатор проекта в архиве.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "name": {
+                    "description": "Название проекта.",
+                    "type": "string",
+                    "example": "Работа"
+                },
+                "workspace_id": {
+                    "description": "Пространство в исходной инсталляции, при импорте проект становится личным.",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "internal_account_delivery.ImportResultOut": {
+            "type": "object",
+            "properties": {
+                "created_projects": {
+                    "description": "Созданные проекты.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Работа"
+                    ]
+                },
+                "imported_entries": {
+                    "description": "Добавлено записей.",
+                    "type": "integer",
+                    "example": 120
+                },
+                "imported_goals": {
+                    "description": "Добавлено целей.",
+                    "type": "integer",
+                    "example": 2
+                },
+                "matched_projects": {
+                    "description": "Проекты, сопоставленные с существующими по названию.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Учеба"
+                    ]
+                },
+                "skipped_entries": {
+                    "description": "Записей, которые уже были в аккаунте.",
+                    "type": "integer",
+                    "example": 3
+                },
+                "skipped_goals": {
+                    "description": "Целей, которые уже были в аккаунте.",
+                    "type": "integer",
+                    "example": 0
+                }
+            }
+        },
+        "internal_audit_delivery.AuditEventOut": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "Действие: create, update, delete, restore, import, achieve.",
+                    "type": "string",
+                    "example": "update"
+                },
+                "actor_id": {
+                    "description": "Кто изменил данные.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "after": {
+                    "description": "Состояние после изменения.",
+                    "type": "object"
+                },
+                "before": {
+                    "description": "Состояние до изменения.",
+                    "type": "object"
+                },
+                "created_at": {
+                    "description": "Время события.",
+                    "type": "string",
+                    "example": "2024-03-23T15:04:05Z"
+                },
+                "entity_id": {
+                    "description": "Идентификатор сущности.",
+                    "type": "integer",
+                    "example": 10
+                },
+                "entity_type": {
+                    "description": "Тип сущности: entry, project, goal, user_data.",
+                    "type": "string",
+                    "example": "entry"
+                },
+                "id": {
+                    "description": "Идентификатор события.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "request_id": {
+                    "description": "Идентификатор запроса.",
+                    "type": "string",
+                    "example": "3f0c1b7e-2d4f-4a39-9f0e-8c6d"
+                },
+                "workspace_id": {
+                    "description": "Пространство, если данные общие.",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "internal_audit_delivery.AuditPageOut": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "description": "События, начиная с самых новых.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_audit_delivery.AuditEventOut"
+                    }
+                },
+                "next_before_id": {
+                    "description": "Значение before_id для следующей страницы.",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "internal_calendar_delivery.CalendarTokenOut": {
+            "type": "object",
+            "properties": {
+                "token": {
+                    "description": "Секретный токен ленты, показывается один раз.",
+                    "type": "string",
+                    "example": "3f2a9c..."
+                },
+                "url": {
+                    "description": "Адрес ленты для подписки в календаре.",
+                    "type": "string",
+                    "example": "https://tracker.example.com/ical/3f2a9c....ics"
+                }
+            }
+        },
+        "internal_entry_delivery.CreateEntryIn": {
+            "type": "object",
+            "required": [
+                "project_id",
+                "time_end",
+                "time_start"
+            ],
+            "properties": {
+                "billable": {
+                    "description": "Оплачиваемое время.",
+                    "type": "boolean",
+                    "example": true
+                },
+                "name": {
+                    "description": "Название записи.",
+                    "type": "string",
+                    "example": "task1"
+                },
+                "project_id": {
+                    "description": "Идентификатор проекта.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "tags": {
+                    "description": "Теги записи.",
+                    "type": "array",
+                    "maxItems": 20,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "meeting",
+                        "backend"
+                    ]
+                },
+                "time_end": {
+                    "description": "Время окончания записи.",
+                    "type": "string",
+                    "example": "2024-03-23T19:04:05Z"
+                },
+                "time_start": {
+                    "description": "Время начала записи.",
+                    "type": "string",
+                    "example": "2024-03-23T15:04:05Z"
+                }
+            }
+        },
+        "internal_entry_delivery.CreateEntryOut": {
+            "type": "object",
+            "required": [
+                "id"
+            ],
+            "properties": {
+                "id": {
+                    "description": "Идентификатор записи.",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "internal_entry_delivery.EntryOut": {
+            "type": "object",
+            "properties": {
+                "billable": {
+                    "description": "Оплачиваемое время.",
+                    "type": "boolean",
+                    "example": true
+                },
+                "id": {
+                    "description": "Идентификатор записи.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "name": {
+                    "description": "Название записи.",
+                    "type": "string",
+                    "example": "task1"
+                },
+                "project_id": {
+                    "description": "Идентификатор проекта.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "project_name": {
+                    "description": "Название проекта.",
+                    "type": "string",
+                    "example": "work"
+                },
+                "tags": {
+                    "description": "Теги записи.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "meeting",
+                        "backend"
+                    ]
+                },
+                "time_end": {
+                    "description": "Время окончания записи.",
+                    "type": "string",
+                    "example": "2024-03-23T19:04:05Z"
+                },
+                "time_start": {
+                    "description": "Время начала записи.",
+                    "type": "string",
+                    "example": "2024-03-23T15:04:05Z"
+                }
+            }
+        },
+        "internal_entry_delivery.ImportResultOut": {
+            "type": "object",
+            "properties": {
+                "created_projects": {
+                    "description": "Созданные (или будущие) проекты.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Работа",
+                        "Учеба"
+                    ]
+                },
+                "dry_run": {
+                    "description": "Пробный запуск, ничего не сохранено.",
+                    "type": "boolean",
+                    "example": true
+                },
+                "errors": {
+                    "description": "Ошибки по строкам.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_entry_delivery.ImportRowErrorOut"
+                    }
+                },
+                "imported_rows": {
+                    "description": "Сохранено записей.",
+                    "type": "integer",
+                    "example": 0
+                },
+                "skipped": {
+                    "description": "Пропущенные строки выгрузки другого трекера.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_entry_delivery.ImportRowErrorOut"
+                    }
+                },
+                "total_rows": {
+                    "description": "Строк в файле без заголовка.",
+                    "type": "integer",
+                    "example": 120
+                },
+                "valid_rows": {
+                    "description": "Строк без ошибок.",
+                    "type": "integer",
+                    "example": 118
+                }
+            }
+        },
+        "internal_entry_delivery.ImportRowErrorOut": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "description": "Описание ошибки.",
+                    "type": "string",
+                    "example": "time end must be after time start"
+                },
+                "row": {
+                    "description": "Номер строки файла, заголовок — строка 1.",
+                    "type": "integer",
+                    "example": 5
+                }
+            }
+        },
+        "internal_entry_delivery.UpdateEntryIn": {
+            "type": "object",
+            "required": [
+                "project_id",
+                "time_end",
+                "time_start"
+            ],
+            "properties": {
+                "billable": {
+                    "description": "Оплачиваемое время.",
+                    "type": "boolean",
+                    "example": true
+                },
+                "name": {
+                    "description": "Название записи.",
+                    "type": "string",
+                    "example": "task1"
+                },
+                "project_id": {
+                    "description": "Идентификатор проекта.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "tags": {
+                    "description": "Теги записи.",
+                    "type": "array",
+                    "maxItems": 20,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "meeting",
+                        "backend"
+                    ]
+                },
+                "time_end": {
+                    "description": "Время окончания записи.",
+                    "type": "string",
+                    "example": "2024-03-23T19:04:05Z"
+                },
+                "time_start": {
+                    "description": "Время начала записи.",
+                    "type": "string",
+                    "example": "2024-03-23T15:04:05Z"
+                }
+            }
+        },
+        "internal_goal_delivery.CreateGoalIn": {
+            "type": "object",
+            "required": [
+                "date_end",
+                "date_start",
+                "name",
+                "project_id",
+                "time_seconds"
+            ],
+            "properties": {
+                "date_end": {
+                    "description": "Дата окончания цели.",
+                    "type": "string",
+                    "example": "2024-04-23T00:00:00Z"
+                },
+                "date_start": {
+                    "description": "Дата начала цели.",
+                    "type": "string",
+                    "example": "2024-03-23T00:00:00Z"
+                },
+                "name": {
+                    "description": "Название цели.",
+                    "type": "string",
+                    "example": "Потратить 100часов на разработку"
+                },
+                "project_id": {
+                    "description": "Идентификатор проекта.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "time_seconds": {
+                    "description": "Требуемое(целевое) время в секундах.",
+                    "type": "integer",
+                    "example": 360000
+                }
+            }
+        },
+        "internal_goal_delivery.CreateGoalOut": {
+            "type": "object",
+            "required": [
+                "id"
+            ],
+            "properties": {
+                "id": {
+                    "description": "Идентификатор цели.",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "internal_goal_delivery.GoalOut": {
+            "type": "object",
+            "properties": {
+                "achieved_at": {
+                    "description": "Когда цель впервые достигнута.",
+                    "type": "string",
+                    "example": "2024-04-01T15:04:05Z"
+                },
+                "date_end": {
+                    "description": "Дата окончания цели.",
+                    "type": "string",
+                    "example": "2024-04-23T00:00:00Z"
+                },
+                "date_start": {
+                    "description": "Дата начала цели.",
+                    "type": "string",
+                    "example": "2024-03-23T00:00:00Z"
+                },
+                "duration_seconds": {
+                    "description": "Прогресс: количество секунд потреченных на эту цель",
+                    "type": "number",
+                    "example": 36000
+                },
+                "id": {
+                    "description": "Идентификатор цели.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "name": {
+                    "description": "Название цели.",
+                    "type": "string",
+                    "example": "Потратить 100часов на разработку"
+                },
+                "percent": {
+                    "description": "Прогресс: процент выполнения цели.",
+                    "type": "number",
+                    "example": 10
+                },
+                "project_id": {
+                    "description": "Идентификатор проекта.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "time_seconds": {
+                    "description": "Требуемое(целевое) время в секундах.",
+                    "type": "integer",
+                    "example": 360000
+                },
+                "user_id": {
+                    "description": "Идентификатор пользователя.",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "internal_health_delivery.HealthOut": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "description": "Всегда ok.",
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "internal_health_delivery.ReadinessOut": {
+            "type": "object",
+            "properties": {
+                "checks": {
+                    "description": "Статус каждой зависимости: ok или текст ошибки.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    },
+                    "example": {
+                        "postgres": "ok",
+                        "redis": "ok"
+                    }
+                },
+                "status": {
+                    "description": "ok или unavailable.",
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "internal_loglevel_delivery.LogLevelIn": {
+            "type": "object",
+            "required": [
+                "level"
+            ],
+            "properties": {
+                "level": {
+                    "description": "debug, info, warn, error или off.",
+                    "type": "string",
+                    "example": "debug"
+                }
+            }
+        },
+        "internal_loglevel_delivery.LogLevelOut": {
+            "type": "object",
+            "properties": {
+                "level": {
+                    "description": "debug, info, warn, error или off.",
+                    "type": "string",
+                    "example": "info"
+                }
+            }
+        },
+        "internal_notification_delivery.NotificationSettingsIn": {
+            "type": "object",
+            "required": [
+                "goal_reminders",
+                "weekly_digest"
+            ],
+            "properties": {
+                "goal_reminders": {
+                    "description": "Получать напоминания о целях.",
+                    "type": "boolean",
+                    "example": false
+                },
+                "weekly_digest": {
+                    "description": "Получать еженедельную сводку.",
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "internal_notification_delivery.NotificationSettingsOut": {
+            "type": "object",
+            "properties": {
+                "goal_reminders": {
+                    "description": "Получать напоминания о целях.",
+                    "type": "boolean",
+                    "example": false
+                },
+                "weekly_digest": {
+                    "description": "Получать еженедельную сводку.",
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "internal_periodlock_delivery.LockOut": {
+            "type": "object",
+            "properties": {
+                "locked_before": {
+                    "description": "Граница заблокированного периода.",
+                    "type": "string",
+                    "example": "2024-03-01T00:00:00Z"
+                },
+                "locked_by": {
+                    "description": "Кто установил блокировку.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "updated_at": {
+                    "description": "Время установки блокировки.",
+                    "type": "string",
+                    "example": "2024-03-02T10:00:00Z"
+                },
+                "workspace_id": {
+                    "description": "Идентификатор пространства.",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "internal_periodlock_delivery.OverrideOut": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "Действие: create, update, delete.",
+                    "type": "string",
+                    "example": "update"
+                },
+                "created_at": {
+                    "description": "Время изменения.",
+                    "type": "string",
+                    "example": "2024-03-05T10:00:00Z"
+                },
+                "entry_id": {
+                    "description": "Идентификатор измененной записи времени.",
+                    "type": "integer",
+                    "example": 10
+                },
+                "id": {
+                    "description": "Идентификатор записи журнала.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "reason": {
+                    "description": "Причина изменения.",
+                    "type": "string",
+                    "example": "исправление после аудита"
+                },
+                "user_id": {
+                    "description": "Кто изменил запись.",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "internal_periodlock_delivery.SetLockIn": {
+            "type": "object",
+            "required": [
+                "locked_before"
+            ],
+            "properties": {
+                "locked_before": {
+                    "description": "Записи, начавшиеся раньше этого времени, изменять нельзя.",
+                    "type": "string",
+                    "example": "2024-03-01T00:00:00Z"
+                }
+            }
+        },
+        "internal_project_delivery.CreateProjectIn": {
             "type": "object",
+            "required": [
+                "name"
+            ],
             "properties": {
-                "message": {}
+                "name": {
+                    "description": "Название проекта.",
+                    "type": "string",
+                    "example": "Работа"
+                },
+                "workspace_id": {
+                    "description": "Идентификатор пространства, если проект общий.",
+                    "type": "integer",
+                    "example": 1
+                }
             }
         },
-        "internal_entry_delivery.CreateEntryIn": {
+        "internal_project_delivery.CreateProjectOut": {
             "type": "object",
             "required": [
-                "project_id",
-                "time_end",
-                "time_start"
+                "id"
             ],
             "properties": {
+                "id": {
+                    "description": "Идентификатор проекта.",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "internal_project_delivery.ProjectEntriesStat": {
+            "type": "object",
+            "properties": {
+                "duration_in_sec": {
+                    "description": "Суммарное время (в сек.) потраченное на запись.",
+                    "type": "number",
+                    "example": 360
+                },
+                "name": {
+                    "description": "Название записи.",
+                    "type": "string",
+                    "example": "task1"
+                },
+                "percent_duration": {
+                    "description": "Доля (в процентах) длительности записи от длительности проекта.",
+                    "type": "number"
+                }
+            }
+        },
+        "internal_project_delivery.ProjectEntriesStatOut": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "description": "Записи времени.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_project_delivery.ProjectEntriesStat"
+                    }
+                },
+                "total_duration_in_sec": {
+                    "description": "Суммарное время (в сек.) потраченное на проект.",
+                    "type": "number",
+                    "example": 60
+                }
+            }
+        },
+        "internal_project_delivery.ProjectOut": {
+            "type": "object",
+            "properties": {
+                "client": {
+                    "description": "Клиент проекта.",
+                    "type": "string",
+                    "example": "ООО Ромашка"
+                },
+                "id": {
+                    "description": "Идентификатор проекта.                         // Идентификатор проекта.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "name": {
+                    "description": "Название проекта.",
+                    "type": "string",
+                    "example": "Работа"
+                },
+                "workspace_id": {
+                    "description": "Идентификатор пространства, если проект общий.",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "internal_project_delivery.ProjectStat": {
+            "type": "object",
+            "properties": {
+                "duration_in_sec": {
+                    "description": "Суммарное время (в сек.) потраченное на проект.",
+                    "type": "number",
+                    "example": 360
+                },
+                "id": {
+                    "description": "Идентификатор проекта.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "name": {
+                    "description": "Название проекта.",
+                    "type": "string",
+                    "example": "Работа"
+                },
+                "percent_duration": {
+                    "description": "Доля (в процентах) длительности проекта от суммарной длительности.",
+                    "type": "number",
+                    "example": 10
+                }
+            }
+        },
+        "internal_project_delivery.ProjectsStatOut": {
+            "type": "object",
+            "properties": {
+                "projects": {
+                    "description": "Список проектов.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_project_delivery.ProjectStat"
+                    }
+                },
+                "total_duration_in_sec": {
+                    "description": "Суммарное время (в сек.) потраченное на все проекты.",
+                    "type": "number",
+                    "example": 3600
+                }
+            }
+        },
+        "internal_report_delivery.MemberEntryOut": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "Идентификатор записи.",
+                    "type": "integer",
+                    "example": 1
+                },
                 "name": {
                     "description": "Название записи.",
                     "type": "string",
@@ -465,6 +4265,11 @@ const docTemplate = `{
                     "type": "integer",
                     "example": 1
                 },
+                "project_name": {
+                    "description": "Название проекта.",
+                    "type": "string",
+                    "example": "work"
+                },
                 "time_end": {
                     "description": "Время окончания записи.",
                     "type": "string",
@@ -477,22 +4282,197 @@ const docTemplate = `{
                 }
             }
         },
-        "internal_entry_delivery.CreateEntryOut": {
+        "internal_report_delivery.ReportMemberOut": {
+            "type": "object",
+            "properties": {
+                "duration_in_sec": {
+                    "description": "Суммарное время (в сек.) участника.",
+                    "type": "number",
+                    "example": 360
+                },
+                "name": {
+                    "description": "Имя участника.",
+                    "type": "string",
+                    "example": "Иван"
+                },
+                "percent_duration": {
+                    "description": "Доля (в процентах) от времени проекта или от суммарного времени.",
+                    "type": "number",
+                    "example": 10
+                },
+                "user_id": {
+                    "description": "Идентификатор участника.",
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "internal_report_delivery.ReportProjectOut": {
+            "type": "object",
+            "properties": {
+                "duration_in_sec": {
+                    "description": "Суммарное время (в сек.) потраченное на проект.",
+                    "type": "number",
+                    "example": 360
+                },
+                "id": {
+                    "description": "Идентификатор проекта.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "members": {
+                    "description": "Время участников по проекту, только для admin и owner.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_report_delivery.ReportMemberOut"
+                    }
+                },
+                "name": {
+                    "description": "Название проекта.",
+                    "type": "string",
+                    "example": "Работа"
+                },
+                "percent_duration": {
+                    "description": "Доля (в процентах) длительности проекта от суммарной длительности.",
+                    "type": "number",
+                    "example": 10
+                }
+            }
+        },
+        "internal_report_delivery.WorkspaceReportOut": {
+            "type": "object",
+            "properties": {
+                "members": {
+                    "description": "Статистика по участникам, только для admin и owner.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_report_delivery.ReportMemberOut"
+                    }
+                },
+                "projects": {
+                    "description": "Статистика по проектам.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_report_delivery.ReportProjectOut"
+                    }
+                },
+                "total_duration_in_sec": {
+                    "description": "Суммарное время (в сек.) по всем проектам пространства.",
+                    "type": "number",
+                    "example": 3600
+                }
+            }
+        },
+        "internal_timesheet_delivery.CreateTimesheetIn": {
+            "type": "object",
+            "required": [
+                "week_start",
+                "workspace_id"
+            ],
+            "properties": {
+                "week_start": {
+                    "description": "Любой день недели в формате YYYY-MM-DD.",
+                    "type": "string",
+                    "example": "2024-03-18"
+                },
+                "workspace_id": {
+                    "description": "Идентификатор пространства.",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "internal_timesheet_delivery.CreateTimesheetOut": {
             "type": "object",
             "required": [
                 "id"
             ],
             "properties": {
                 "id": {
-                    "description": "Идентификатор записи.",
+                    "description": "Идентификатор табеля.",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "internal_timesheet_delivery.ReviewTimesheetIn": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "description": "Комментарий рецензента.",
+                    "type": "string",
+                    "example": "Не хватает записей за пятницу"
+                }
+            }
+        },
+        "internal_timesheet_delivery.TimesheetOut": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "description": "Комментарий рецензента.",
+                    "type": "string",
+                    "example": ""
+                },
+                "id": {
+                    "description": "Идентификатор табеля.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "reviewed_at": {
+                    "description": "Время рецензии.",
+                    "type": "string",
+                    "example": "2024-03-25T15:04:05Z"
+                },
+                "reviewer_id": {
+                    "description": "Идентификатор рецензента.",
+                    "type": "integer",
+                    "example": 2
+                },
+                "state": {
+                    "description": "Состояние: draft, submitted, approved, rejected.",
+                    "type": "string",
+                    "example": "submitted"
+                },
+                "submitted_at": {
+                    "description": "Время отправки на утверждение.",
+                    "type": "string",
+                    "example": "2024-03-24T15:04:05Z"
+                },
+                "total_duration_in_sec": {
+                    "description": "Суммарное время (в сек.) за неделю по проектам пространства.",
+                    "type": "number",
+                    "example": 144000
+                },
+                "user_id": {
+                    "description": "Идентификатор пользователя.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "user_name": {
+                    "description": "Имя пользователя.",
+                    "type": "string",
+                    "example": "Иван"
+                },
+                "week_start": {
+                    "description": "Понедельник недели табеля.",
+                    "type": "string",
+                    "example": "2024-03-18"
+                },
+                "workspace_id": {
+                    "description": "Идентификатор пространства.",
                     "type": "integer",
                     "example": 1
                 }
             }
         },
-        "internal_entry_delivery.EntryOut": {
+        "internal_trash_delivery.DeletedEntryOut": {
             "type": "object",
             "properties": {
+                "deleted_at": {
+                    "description": "Время удаления.",
+                    "type": "string",
+                    "example": "2024-03-24T10:00:00Z"
+                },
                 "id": {
                     "description": "Идентификатор записи.",
                     "type": "integer",
@@ -501,7 +4481,7 @@ const docTemplate = `{
                 "name": {
                     "description": "Название записи.",
                     "type": "string",
-                    "example": "task1"
+                    "example": "Созвон"
                 },
                 "project_id": {
                     "description": "Идентификатор проекта.",
@@ -511,236 +4491,428 @@ const docTemplate = `{
                 "project_name": {
                     "description": "Название проекта.",
                     "type": "string",
-                    "example": "work"
+                    "example": "Работа"
+                },
+                "purge_at": {
+                    "description": "Время окончательного удаления.",
+                    "type": "string",
+                    "example": "2024-04-23T10:00:00Z"
                 },
                 "time_end": {
-                    "description": "Время окончания записи.",
+                    "description": "Время окончания.",
                     "type": "string",
-                    "example": "2024-03-23T19:04:05Z"
+                    "example": "2024-03-23T16:04:05Z"
                 },
                 "time_start": {
-                    "description": "Время начала записи.",
+                    "description": "Время начала.",
                     "type": "string",
                     "example": "2024-03-23T15:04:05Z"
                 }
             }
         },
-        "internal_goal_delivery.CreateGoalIn": {
+        "internal_trash_delivery.DeletedGoalOut": {
             "type": "object",
-            "required": [
-                "date_end",
-                "date_start",
-                "name",
-                "project_id",
-                "time_seconds"
-            ],
             "properties": {
                 "date_end": {
-                    "description": "Дата окончания цели.",
+                    "description": "Окончание цели.",
                     "type": "string",
-                    "example": "2024-04-23T00:00:00Z"
+                    "example": "2024-03-31T23:59:59Z"
                 },
                 "date_start": {
-                    "description": "Дата начала цели.",
+                    "description": "Начало цели.",
                     "type": "string",
-                    "example": "2024-03-23T00:00:00Z"
+                    "example": "2024-03-01T00:00:00Z"
+                },
+                "deleted_at": {
+                    "description": "Время удаления.",
+                    "type": "string",
+                    "example": "2024-03-24T10:00:00Z"
+                },
+                "id": {
+                    "description": "Идентификатор цели.",
+                    "type": "integer",
+                    "example": 1
                 },
                 "name": {
                     "description": "Название цели.",
                     "type": "string",
-                    "example": "Потратить 100часов на разработку"
+                    "example": "Выучить Go"
                 },
                 "project_id": {
                     "description": "Идентификатор проекта.",
                     "type": "integer",
                     "example": 1
                 },
+                "project_name": {
+                    "description": "Название проекта.",
+                    "type": "string",
+                    "example": "Работа"
+                },
+                "purge_at": {
+                    "description": "Время окончательного удаления.",
+                    "type": "string",
+                    "example": "2024-04-23T10:00:00Z"
+                },
                 "time_seconds": {
-                    "description": "Требуемое(целевое) время в секундах.",
+                    "description": "Запланированное время (в сек.).",
                     "type": "integer",
-                    "example": 360000
+                    "example": 36000
                 }
             }
         },
-        "internal_goal_delivery.CreateGoalOut": {
+        "internal_trash_delivery.DeletedProjectOut": {
             "type": "object",
-            "required": [
-                "id"
-            ],
             "properties": {
+                "deleted_at": {
+                    "description": "Время удаления.",
+                    "type": "string",
+                    "example": "2024-03-24T10:00:00Z"
+                },
                 "id": {
-                    "description": "Идентификатор цели.",
+                    "description": "Идентификатор проекта.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "name": {
+                    "description": "Название проекта.",
+                    "type": "string",
+                    "example": "Работа"
+                },
+                "purge_at": {
+                    "description": "Время окончательного удаления.",
+                    "type": "string",
+                    "example": "2024-04-23T10:00:00Z"
+                },
+                "workspace_id": {
+                    "description": "Идентификатор пространства, если проект общий.",
                     "type": "integer",
                     "example": 1
                 }
             }
         },
-        "internal_goal_delivery.GoalOut": {
+        "internal_trash_delivery.TrashOut": {
             "type": "object",
             "properties": {
-                "date_end": {
-                    "description": "Дата окончания цели.",
+                "entries": {
+                    "description": "Удаленные записи времени.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_trash_delivery.DeletedEntryOut"
+                    }
+                },
+                "goals": {
+                    "description": "Удаленные цели.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_trash_delivery.DeletedGoalOut"
+                    }
+                },
+                "projects": {
+                    "description": "Удаленные проекты.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_trash_delivery.DeletedProjectOut"
+                    }
+                }
+            }
+        },
+        "internal_webhook_delivery.DeliveryOut": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "description": "Сколько было попыток.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "created_at": {
+                    "description": "Время события.",
                     "type": "string",
-                    "example": "2024-04-23T00:00:00Z"
+                    "example": "2024-03-23T15:04:05Z"
                 },
-                "date_start": {
-                    "description": "Дата начала цели.",
+                "delivered_at": {
+                    "description": "Время успешной доставки.",
                     "type": "string",
-                    "example": "2024-03-23T00:00:00Z"
+                    "example": "2024-03-23T15:04:06Z"
                 },
-                "duration_seconds": {
-                    "description": "Прогресс: количество секунд потреченных на эту цель",
-                    "type": "number",
-                    "example": 36000
+                "event_id": {
+                    "description": "Идентификатор события, одинаковый для повторных доставок.",
+                    "type": "string",
+                    "example": "9b2e4f1c0a7d4e3b8c6f5a2d1e0b9c8a"
+                },
+                "event_type": {
+                    "description": "Тип события.",
+                    "type": "string",
+                    "example": "entry.created"
                 },
                 "id": {
-                    "description": "Идентификатор цели.",
+                    "description": "Идентификатор доставки.",
                     "type": "integer",
                     "example": 1
                 },
-                "name": {
-                    "description": "Название цели.",
+                "last_error": {
+                    "description": "Ошибка последней попытки.",
                     "type": "string",
-                    "example": "Потратить 100часов на разработку"
+                    "example": "500 Internal Server Error"
                 },
-                "percent": {
-                    "description": "Прогресс: процент выполнения цели.",
-                    "type": "number",
-                    "example": 10
+                "next_attempt_at": {
+                    "description": "Время следующей попытки для pending.",
+                    "type": "string",
+                    "example": "2024-03-23T15:05:05Z"
                 },
-                "project_id": {
-                    "description": "Идентификатор проекта.",
+                "payload": {
+                    "description": "Тело запроса.",
+                    "type": "object"
+                },
+                "response_status": {
+                    "description": "Код последнего ответа получателя.",
+                    "type": "integer",
+                    "example": 200
+                },
+                "status": {
+                    "description": "Статус: pending, succeeded, failed.",
+                    "type": "string",
+                    "example": "succeeded"
+                }
+            }
+        },
+        "internal_webhook_delivery.DeliveryPageOut": {
+            "type": "object",
+            "properties": {
+                "deliveries": {
+                    "description": "Доставки, начиная с самых новых.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_webhook_delivery.DeliveryOut"
+                    }
+                },
+                "next_before_id": {
+                    "description": "Значение before_id для следующей страницы.",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "internal_webhook_delivery.RedeliverOut": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "Идентификатор новой доставки.",
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "internal_webhook_delivery.SubscriptionIn": {
+            "type": "object",
+            "required": [
+                "events",
+                "url"
+            ],
+            "properties": {
+                "events": {
+                    "description": "Типы событий.",
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "entry.created",
+                        "goal.achieved"
+                    ]
+                },
+                "secret": {
+                    "description": "Ключ подписи, по умолчанию генерируется.",
+                    "type": "string",
+                    "maxLength": 128,
+                    "minLength": 16,
+                    "example": "d1f0c3e9a7b84c2f"
+                },
+                "url": {
+                    "description": "Адрес получателя, http или https.",
+                    "type": "string",
+                    "maxLength": 2048,
+                    "example": "https://example.com/hooks/timetracker"
+                },
+                "workspace_id": {
+                    "description": "Пространство, 0 — личная подписка.",
                     "type": "integer",
                     "example": 1
+                }
+            }
+        },
+        "internal_webhook_delivery.SubscriptionOut": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "Время создания.",
+                    "type": "string",
+                    "example": "2024-03-23T15:04:05Z"
                 },
-                "time_seconds": {
-                    "description": "Требуемое(целевое) время в секундах.",
+                "events": {
+                    "description": "Типы событий.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "entry.created",
+                        "goal.achieved"
+                    ]
+                },
+                "id": {
+                    "description": "Идентификатор подписки.",
                     "type": "integer",
-                    "example": 360000
+                    "example": 1
+                },
+                "secret": {
+                    "description": "Ключ подписи, возвращается только при создании.",
+                    "type": "string",
+                    "example": "d1f0c3e9a7b84c2f"
+                },
+                "url": {
+                    "description": "Адрес получателя.",
+                    "type": "string",
+                    "example": "https://example.com/hooks/timetracker"
                 },
                 "user_id": {
-                    "description": "Идентификатор пользователя.",
+                    "description": "Кто создал подписку.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "workspace_id": {
+                    "description": "Пространство подписки.",
                     "type": "integer",
                     "example": 1
                 }
             }
         },
-        "internal_project_delivery.CreateProjectIn": {
+        "internal_workspace_delivery.CreateWorkspaceIn": {
             "type": "object",
             "required": [
                 "name"
             ],
             "properties": {
                 "name": {
-                    "description": "Название проекта.",
+                    "description": "Название пространства.",
                     "type": "string",
-                    "example": "Работа"
+                    "maxLength": 35,
+                    "example": "Команда"
                 }
             }
         },
-        "internal_project_delivery.CreateProjectOut": {
+        "internal_workspace_delivery.CreateWorkspaceOut": {
             "type": "object",
             "required": [
                 "id"
             ],
             "properties": {
                 "id": {
-                    "description": "Идентификатор проекта.",
+                    "description": "Идентификатор пространства.",
                     "type": "integer",
                     "example": 1
                 }
             }
         },
-        "internal_project_delivery.ProjectEntriesStat": {
+        "internal_workspace_delivery.InviteMemberIn": {
             "type": "object",
+            "required": [
+                "email",
+                "role"
+            ],
             "properties": {
-                "duration_in_sec": {
-                    "description": "Суммарное время (в сек.) потраченное на запись.",
-                    "type": "number",
-                    "example": 360
-                },
-                "name": {
-                    "description": "Название записи.",
+                "email": {
+                    "description": "Email приглашаемого пользователя.",
                     "type": "string",
-                    "example": "task1"
+                    "example": "user@mail.ru"
                 },
-                "percent_duration": {
-                    "description": "Доля (в процентах) длительности записи от длительности проекта.",
-                    "type": "number"
+                "role": {
+                    "description": "Роль в пространстве.",
+                    "type": "string",
+                    "enum": [
+                        "admin",
+                        "member",
+                        "viewer"
+                    ],
+                    "example": "member"
                 }
             }
         },
-        "internal_project_delivery.ProjectEntriesStatOut": {
+        "internal_workspace_delivery.InviteMemberOut": {
             "type": "object",
             "properties": {
-                "entries": {
-                    "description": "Записи времени.",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/internal_project_delivery.ProjectEntriesStat"
-                    }
-                },
-                "total_duration_in_sec": {
-                    "description": "Суммарное время (в сек.) потраченное на проект.",
-                    "type": "number",
-                    "example": 60
+                "user_id": {
+                    "description": "Идентификатор добавленного пользователя.",
+                    "type": "integer",
+                    "example": 2
                 }
             }
         },
-        "internal_project_delivery.ProjectOut": {
+        "internal_workspace_delivery.MemberOut": {
             "type": "object",
             "properties": {
-                "id": {
-                    "description": "Идентификатор проекта.                         // Идентификатор проекта.",
-                    "type": "integer",
-                    "example": 1
+                "email": {
+                    "description": "Email пользователя.",
+                    "type": "string",
+                    "example": "user@mail.ru"
                 },
                 "name": {
-                    "description": "Название проекта.",
+                    "description": "Имя пользователя.",
                     "type": "string",
-                    "example": "Работа"
+                    "example": "Иван"
+                },
+                "role": {
+                    "description": "Роль в пространстве.",
+                    "type": "string",
+                    "example": "member"
+                },
+                "user_id": {
+                    "description": "Идентификатор пользователя.",
+                    "type": "integer",
+                    "example": 2
                 }
             }
         },
-        "internal_project_delivery.ProjectStat": {
+        "internal_workspace_delivery.UpdateMemberIn": {
+            "type": "object",
+            "required": [
+                "role"
+            ],
+            "properties": {
+                "role": {
+                    "description": "Новая роль в пространстве.",
+                    "type": "string",
+                    "enum": [
+                        "admin",
+                        "member",
+                        "viewer"
+                    ],
+                    "example": "viewer"
+                }
+            }
+        },
+        "internal_workspace_delivery.WorkspaceOut": {
             "type": "object",
             "properties": {
-                "duration_in_sec": {
-                    "description": "Суммарное время (в сек.) потраченное на проект.",
-                    "type": "number",
-                    "example": 360
-                },
                 "id": {
-                    "description": "Идентификатор проекта.",
+                    "description": "Идентификатор пространства.",
                     "type": "integer",
                     "example": 1
                 },
                 "name": {
-                    "description": "Название проекта.",
+                    "description": "Название пространства.",
                     "type": "string",
-                    "example": "Работа"
+                    "example": "Команда"
                 },
-                "percent_duration": {
-                    "description": "Доля (в процентах) длительности проекта от суммарной длительности.",
-                    "type": "number",
-                    "example": 10
-                }
-            }
-        },
-        "internal_project_delivery.ProjectsStatOut": {
-            "type": "object",
-            "properties": {
-                "projects": {
-                    "description": "Список проектов.",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/internal_project_delivery.ProjectStat"
-                    }
+                "owner_id": {
+                    "description": "Идентификатор владельца.",
+                    "type": "integer",
+                    "example": 1
                 },
-                "total_duration_in_sec": {
-                    "description": "Суммарное время (в сек.) потраченное на все проекты.",
-                    "type": "number",
-                    "example": 3600
+                "role": {
+                    "description": "Роль текущего пользователя в пространстве.",
+                    "type": "string",
+                    "example": "owner"
                 }
             }
         }
@@ -0,0 +1,55 @@
+package delivery
+
+import "time"
+
+// Archive архив аккаунта: ответ выгрузки и тело импорта.
+type Archive struct {
+	Version    int              `json:"version" validate:"required" example:"1"`    // Версия формата архива.
+	ExportedAt time.Time        `json:"exported_at" example:"2024-03-23T15:04:05Z"` // Время выгрузки.
+	Profile    ArchiveProfile   `json:"profile"`                                    // Профиль.
+	Projects   []ArchiveProject `json:"projects" validate:"dive"`                   // Проекты.
+	Entries    []ArchiveEntry   `json:"entries" validate:"dive"`                    // Записи времени.
+	Goals      []ArchiveGoal    `json:"goals" validate:"dive"`                      // Цели.
+}
+
+type ArchiveProfile struct {
+	Name  string `json:"name" example:"Иван"`              // Имя пользователя.
+	Email string `json:"email" example:"ivan@example.com"` // Email, при импорте не меняется.
+}
+
+type ArchiveProject struct {
+	ID          int64  `json:"id" validate:"required" example:"1"`        // Идентификатор проекта в архиве.
+	Name        string `json:"name" validate:"required" example:"Работа"` // Название проекта.
+	WorkspaceID int64  `json:"workspace_id,omitempty" example:"1"`        // Пространство в исходной инсталляции, при импорте проект становится личным.
+	Client      string `json:"client,omitempty" example:"ООО Ромашка"`    // Клиент проекта.
+}
+
+type ArchiveEntry struct {
+	ID             int64     `json:"id" example:"1"`                                                // Идентификатор записи в архиве.
+	ProjectID      int64     `json:"project_id" validate:"required" example:"1"`                    // Идентификатор проекта в архиве.
+	Name           string    `json:"name" example:"task1"`                                          // Название записи.
+	TimeStart      time.Time `json:"time_start" validate:"required" example:"2024-03-23T15:04:05Z"` // Время начала записи.
+	TimeEnd        time.Time `json:"time_end" validate:"required" example:"2024-03-23T19:04:05Z"`   // Время окончания записи.
+	Tags           []string  `json:"tags" example:"meeting"`                                        // Теги записи.
+	Billable       bool      `json:"billable" example:"true"`                                       // Оплачиваемое время.
+	ExternalSource string    `json:"external_source,omitempty" example:"toggl"`                     // Трекер, из которого запись импортирована.
+	ExternalID     string    `json:"external_id,omitempty" example:"42"`                            // Идентификатор записи в трекере.
+}
+
+type ArchiveGoal struct {
+	ID          int64     `json:"id" example:"1"`                                                // Идентификатор цели в архиве.
+	ProjectID   int64     `json:"project_id" validate:"required" example:"1"`                    // Идентификатор проекта в архиве.
+	Name        string    `json:"name" validate:"required" example:"Диплом"`                     // Название цели.
+	TimeSeconds int64     `json:"time_seconds" validate:"required" example:"36000"`              // Целевое время в секундах.
+	DateStart   time.Time `json:"date_start" validate:"required" example:"2024-03-01T00:00:00Z"` // Начало периода цели.
+	DateEnd     time.Time `json:"date_end" validate:"required" example:"2024-03-31T00:00:00Z"`   // Окончание периода цели.
+}
+
+type ImportResultOut struct {
+	CreatedProjects []string `json:"created_projects" example:"Работа"` // Созданные проекты.
+	MatchedProjects []string `json:"matched_projects" example:"Учеба"`  // Проекты, сопоставленные с существующими по названию.
+	ImportedEntries int      `json:"imported_entries" example:"120"`    // Добавлено записей.
+	SkippedEntries  int      `json:"skipped_entries" example:"3"`       // Записей, которые уже были в аккаунте.
+	ImportedGoals   int      `json:"imported_goals" example:"2"`        // Добавлено целей.
+	SkippedGoals    int      `json:"skipped_goals" example:"0"`         // Целей, которые уже были в аккаунте.
+}
@@ -0,0 +1,243 @@
+package delivery
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	usecaseDto "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/account/usecase"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/response"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/validator"
+)
+
+type usecase interface {
+	Export(ctx context.Context, userID int64) (usecaseDto.Archive, error)
+	Import(ctx context.Context, userID int64, archive usecaseDto.Archive) (usecaseDto.ImportResult, error)
+}
+
+// Максимальный размер импортируемого архива.
+const maxArchiveSize = 100 << 20
+
+type Delivery struct {
+	usecase usecase
+
+	logger echo.Logger
+}
+
+func RegisterHandlers(
+	e *echo.Echo,
+	usecase usecase,
+	logger echo.Logger,
+) {
+	handler := &Delivery{
+		usecase: usecase,
+
+		logger: logger,
+	}
+
+	e.GET("/me/export", handler.ExportAccount)
+	e.POST("/me/import", handler.ImportAccount)
+}
+
+// ExportAccount godoc
+// @Summary      Выгрузка аккаунта.
+// @Description  Выгрузить профиль, проекты, записи и цели пользователя в версионированный JSON-архив для переноса в другую инсталляцию. Рекомендуется перед /me/clear_data.
+// @Tags     	 user
+// @Accept	 	application/json
+// @Produce  	application/json
+// @Success  200 {object} Archive "success export account"
+// @Failure 500 {object} response.Error "internal server error"
+// @Failure 404 {object} response.Error "item is not found"
+// @Router   /me/export [get]
+func (d *Delivery) ExportAccount(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, ok := c.Get("user_id").(int64)
+	if !ok {
+		c.Logger().Error("can't parse context user_id")
+		return response.Status(http.StatusInternalServerError)
+	}
+
+	archive, err := d.usecase.Export(ctx, userID)
+	if err != nil {
+		c.Logger().Errorf("usecase: %v", err)
+		return handleUsecaseError(err)
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition,
+		fmt.Sprintf(`attachment; filename="timetracker-%s.json"`, archive.ExportedAt.Format("2006-01-02")))
+
+	return c.JSON(http.StatusOK, convertFromUsecaseArchive(archive))
+}
+
+// ImportAccount godoc
+// @Summary      Импорт аккаунта.
+// @Description  Восстановить архив из /me/export в пустой или существующий аккаунт. Идентификаторы переназначаются: проекты сопоставляются с личными проектами по названию или создаются, проекты пространств становятся личными. Записи и цели, которые уже есть в аккаунте, пропускаются, поэтому повторный импорт безопасен. Email аккаунта не меняется.
+// @Tags     	 user
+// @Accept	 application/json
+// @Produce  application/json
+// @Param    archive body Archive true "Архив аккаунта"
+// @Success  200 {object} ImportResultOut "success import account"
+// @Failure 500 {object} response.Error "internal server error"
+// @Failure 400 {object} response.Error "bad request"
+// @Failure 422 {object} response.Error "unprocessable entity"
+// @Router   /me/import [post]
+func (d *Delivery) ImportAccount(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, ok := c.Get("user_id").(int64)
+	if !ok {
+		c.Logger().Error("can't parse context user_id")
+		return response.Status(http.StatusInternalServerError)
+	}
+
+	var in Archive
+	body := http.MaxBytesReader(c.Response(), c.Request().Body, maxArchiveSize)
+	if err := json.NewDecoder(body).Decode(&in); err != nil {
+		c.Logger().Errorf("decode archive: %v", err)
+		return response.Status(http.StatusUnprocessableEntity)
+	}
+
+	if ok, err := validator.IsRequestValid(&in); !ok {
+		c.Logger().Errorf("validation: %v", err)
+		return response.ValidationError(err)
+	}
+
+	result, err := d.usecase.Import(ctx, userID, convertToUsecaseArchive(in))
+	if err != nil {
+		c.Logger().Errorf("usecase: %v", err)
+		return handleUsecaseError(err)
+	}
+
+	out := ImportResultOut{
+		CreatedProjects: result.CreatedProjects,
+		MatchedProjects: result.MatchedProjects,
+		ImportedEntries: result.ImportedEntries,
+		SkippedEntries:  result.SkippedEntries,
+		ImportedGoals:   result.ImportedGoals,
+		SkippedGoals:    result.SkippedGoals,
+	}
+
+	return c.JSON(http.StatusOK, out)
+}
+
+func convertFromUsecaseArchive(archive usecaseDto.Archive) Archive {
+	out := Archive{
+		Version:    archive.Version,
+		ExportedAt: archive.ExportedAt,
+		Profile: ArchiveProfile{
+			Name:  archive.Profile.Name,
+			Email: archive.Profile.Email,
+		},
+		Projects: make([]ArchiveProject, 0, len(archive.Projects)),
+		Entries:  make([]ArchiveEntry, 0, len(archive.Entries)),
+		Goals:    make([]ArchiveGoal, 0, len(archive.Goals)),
+	}
+
+	for _, p := range archive.Projects {
+		out.Projects = append(out.Projects, ArchiveProject{
+			ID:          p.ID,
+			Name:        p.Name,
+			WorkspaceID: p.WorkspaceID,
+			Client:      p.Client,
+		})
+	}
+
+	for _, e := range archive.Entries {
+		out.Entries = append(out.Entries, ArchiveEntry{
+			ID:             e.ID,
+			ProjectID:      e.ProjectID,
+			Name:           e.Name,
+			TimeStart:      e.TimeStart,
+			TimeEnd:        e.TimeEnd,
+			Tags:           e.Tags,
+			Billable:       e.Billable,
+			ExternalSource: e.ExternalSource,
+			ExternalID:     e.ExternalID,
+		})
+	}
+
+	for _, g := range archive.Goals {
+		out.Goals = append(out.Goals, ArchiveGoal{
+			ID:          g.ID,
+			ProjectID:   g.ProjectID,
+			Name:        g.Name,
+			TimeSeconds: g.TimeSeconds,
+			DateStart:   g.DateStart,
+			DateEnd:     g.DateEnd,
+		})
+	}
+
+	return out
+}
+
+func convertToUsecaseArchive(in Archive) usecaseDto.Archive {
+	archive := usecaseDto.Archive{
+		Version:    in.Version,
+		ExportedAt: in.ExportedAt,
+		Profile: usecaseDto.Profile{
+			Name:  in.Profile.Name,
+			Email: in.Profile.Email,
+		},
+		Projects: make([]usecaseDto.Project, 0, len(in.Projects)),
+		Entries:  make([]usecaseDto.Entry, 0, len(in.Entries)),
+		Goals:    make([]usecaseDto.Goal, 0, len(in.Goals)),
+	}
+
+	for _, p := range in.Projects {
+		archive.Projects = append(archive.Projects, usecaseDto.Project{
+			ID:          p.ID,
+			Name:        p.Name,
+			WorkspaceID: p.WorkspaceID,
+			Client:      p.Client,
+		})
+	}
+
+	for _, e := range in.Entries {
+		archive.Entries = append(archive.Entries, usecaseDto.Entry{
+			ID:             e.ID,
+			ProjectID:      e.ProjectID,
+			Name:           e.Name,
+			TimeStart:      e.TimeStart,
+			TimeEnd:        e.TimeEnd,
+			Tags:           e.Tags,
+			Billable:       e.Billable,
+			ExternalSource: e.ExternalSource,
+			ExternalID:     e.ExternalID,
+		})
+	}
+
+	for _, g := range in.Goals {
+		archive.Goals = append(archive.Goals, usecaseDto.Goal{
+			ID:          g.ID,
+			ProjectID:   g.ProjectID,
+			Name:        g.Name,
+			TimeSeconds: g.TimeSeconds,
+			DateStart:   g.DateStart,
+			DateEnd:     g.DateEnd,
+		})
+	}
+
+	return archive
+}
+
+func handleUsecaseError(err error) *response.Error {
+	// Запрос не уложился в таймаут или был отменен.
+	if problem := response.ContextError(err); problem != nil {
+		return problem
+	}
+
+	if errors.Is(err, usecaseDto.ErrUserNotFound) {
+		return response.NotFound("user")
+	}
+	if errors.Is(err, usecaseDto.ErrInvalidArchive) || errors.Is(err, usecaseDto.ErrUnsupportedArchiveVersion) {
+		return response.NewError(http.StatusBadRequest, "invalid_archive", response.ErrorMsgsByCode[http.StatusBadRequest])
+	}
+
+	// По дефолту пятисотим.
+	return response.Status(http.StatusInternalServerError)
+}
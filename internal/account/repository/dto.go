@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+)
+
+type Profile struct {
+	Name  string `db:"name"`
+	Email string `db:"email"`
+}
+
+type Project struct {
+	ID          int64          `db:"id"`
+	WorkspaceID sql.NullInt64  `db:"workspace_id"`
+	Name        string         `db:"name"`
+	Client      sql.NullString `db:"client"`
+}
+
+type Entry struct {
+	ID             int64          `db:"id"`
+	ProjectID      int64          `db:"project_id"`
+	Name           string         `db:"name"`
+	TimeStart      time.Time      `db:"time_start"`
+	TimeEnd        time.Time      `db:"time_end"`
+	Tags           []string       `db:"tags"`
+	Billable       bool           `db:"billable"`
+	ExternalSource sql.NullString `db:"external_source"`
+	ExternalID     sql.NullString `db:"external_id"`
+}
+
+type Goal struct {
+	ID          int64     `db:"id"`
+	ProjectID   int64     `db:"project_id"`
+	Name        string    `db:"name"`
+	TimeSeconds int64     `db:"time_seconds"`
+	DateStart   time.Time `db:"date_start"`
+	DateEnd     time.Time `db:"date_end"`
+}
+
+// Archive данные для восстановления в аккаунт. Идентификаторы проектов — из архива,
+// ProjectID записей и целей ссылаются на них.
+type Archive struct {
+	ProfileName string
+	Projects    []Project
+	Entries     []Entry
+	Goals       []Goal
+}
+
+// ImportStats итог восстановления архива.
+type ImportStats struct {
+	CreatedProjects []string
+	MatchedProjects []string
+	ImportedEntries int
+	SkippedEntries  int
+	ImportedGoals   int
+	SkippedGoals    int
+}
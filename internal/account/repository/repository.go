@@ -0,0 +1,406 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/transaction"
+)
+
+var ErrUserNotFound = errors.New("user not found")
+
+type Repository struct {
+	db    *sqlx.DB
+	close func() error
+}
+
+func NewRepository(db *sqlx.DB) *Repository {
+	return &Repository{
+		db: db,
+		close: func() error {
+			return db.Close()
+		},
+	}
+}
+
+// Close закрывает пул соединений с базой.
+func (r *Repository) Close() error {
+	return r.close()
+}
+
+func (r *Repository) GetProfile(ctx context.Context, userID int64) (Profile, error) {
+	var profile Profile
+	err := r.db.QueryRowContext(ctx, `SELECT name, email FROM users WHERE id = $1`, userID).
+		Scan(&profile.Name, &profile.Email)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Profile{}, ErrUserNotFound
+		}
+
+		return Profile{}, fmt.Errorf("scan: %w", err)
+	}
+
+	return profile, nil
+}
+
+// GetProjects возвращает личные проекты пользователя и проекты пространств,
+// в которых у него есть записи или цели. Проекты в корзине не выгружаются.
+func (r *Repository) GetProjects(ctx context.Context, userID int64) ([]Project, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT
+			id,
+			workspace_id,
+			name,
+			client
+		FROM projects p
+		WHERE p.deleted_at IS NULL
+		  AND ((p.workspace_id IS NULL AND p.user_id = $1)
+		   OR EXISTS (SELECT 1 FROM entries e WHERE e.project_id = p.id AND e.user_id = $1 AND e.deleted_at IS NULL)
+		   OR EXISTS (SELECT 1 FROM goals g WHERE g.project_id = p.id AND g.user_id = $1 AND g.deleted_at IS NULL))
+		ORDER BY id`, userID)
+
+	if err != nil {
+		return nil, fmt.Errorf("query context: %w", err)
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	projects := []Project{}
+	for rows.Next() {
+		var project Project
+		if err = rows.Scan(&project.ID, &project.WorkspaceID, &project.Name, &project.Client); err != nil {
+			return nil, fmt.Errorf("scan: %w", err)
+		}
+
+		projects = append(projects, project)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("rows err: %w", rows.Err())
+	}
+
+	return projects, nil
+}
+
+// GetEntries возвращает записи пользователя вне корзины.
+func (r *Repository) GetEntries(ctx context.Context, userID int64) ([]Entry, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT
+			e.id,
+			e.project_id,
+			e.name,
+			e.time_start,
+			e.time_end,
+			e.tags,
+			e.billable,
+			e.external_source,
+			e.external_id
+		FROM entries e
+		JOIN projects p ON p.id = e.project_id
+		WHERE e.user_id = $1 AND e.deleted_at IS NULL AND p.deleted_at IS NULL
+		ORDER BY e.time_start, e.id`, userID)
+
+	if err != nil {
+		return nil, fmt.Errorf("query context: %w", err)
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	entries := []Entry{}
+	for rows.Next() {
+		var entry Entry
+		if err = rows.Scan(
+			&entry.ID,
+			&entry.ProjectID,
+			&entry.Name,
+			&entry.TimeStart,
+			&entry.TimeEnd,
+			pq.Array(&entry.Tags),
+			&entry.Billable,
+			&entry.ExternalSource,
+			&entry.ExternalID,
+		); err != nil {
+			return nil, fmt.Errorf("scan: %w", err)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("rows err: %w", rows.Err())
+	}
+
+	return entries, nil
+}
+
+// GetGoals возвращает цели пользователя вне корзины.
+func (r *Repository) GetGoals(ctx context.Context, userID int64) ([]Goal, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT
+			g.id,
+			g.project_id,
+			g.name,
+			g.time_seconds,
+			g.date_start,
+			g.date_end
+		FROM goals g
+		JOIN projects p ON p.id = g.project_id
+		WHERE g.user_id = $1 AND g.deleted_at IS NULL AND p.deleted_at IS NULL
+		ORDER BY g.id`, userID)
+
+	if err != nil {
+		return nil, fmt.Errorf("query context: %w", err)
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	goals := []Goal{}
+	for rows.Next() {
+		var goal Goal
+		if err = rows.Scan(
+			&goal.ID,
+			&goal.ProjectID,
+			&goal.Name,
+			&goal.TimeSeconds,
+			&goal.DateStart,
+			&goal.DateEnd,
+		); err != nil {
+			return nil, fmt.Errorf("scan: %w", err)
+		}
+
+		goals = append(goals, goal)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("rows err: %w", rows.Err())
+	}
+
+	return goals, nil
+}
+
+// Import восстанавливает архив в аккаунт пользователя. Вызывается в транзакции из контекста,
+// чтобы архив и журнал импорта сохранились атомарно. Все проекты архива становятся личными: проект сопоставляется с личным проектом
+// по названию или создается. Записи и цели, которые уже есть в аккаунте
+// (тот же проект, название и время), пропускаются, поэтому повторный импорт не создает дублей.
+func (r *Repository) Import(ctx context.Context, userID int64, archive Archive) (ImportStats, error) {
+	tx := transaction.GetExecutor(ctx, r.db)
+
+	stats := ImportStats{
+		CreatedProjects: []string{},
+		MatchedProjects: []string{},
+	}
+
+	if archive.ProfileName != "" {
+		_, err := tx.ExecContext(ctx, `UPDATE users SET name = $2 WHERE id = $1`, userID, archive.ProfileName)
+		if err != nil {
+			return ImportStats{}, fmt.Errorf("update user: %w", err)
+		}
+	}
+
+	existing, err := r.getPersonalProjectIDs(ctx, tx, userID)
+	if err != nil {
+		return ImportStats{}, err
+	}
+
+	plan := planProjects(archive.Projects, existing)
+	stats.MatchedProjects = append(stats.MatchedProjects, plan.matched...)
+
+	for _, project := range plan.create {
+		var id int64
+		err = tx.QueryRowContext(ctx,
+			`INSERT INTO projects (user_id, name, client) VALUES ($1, $2, $3) RETURNING id;`,
+			userID, project.Name, project.Client).Scan(&id)
+		if err != nil {
+			return ImportStats{}, fmt.Errorf("insert project: %w", err)
+		}
+
+		plan.ids[project.Name] = id
+		stats.CreatedProjects = append(stats.CreatedProjects, project.Name)
+	}
+
+	// В INSERT ... SELECT типы параметров не выводятся из колонок, поэтому приводятся явно.
+	entryStmt, err := tx.PrepareContext(ctx,
+		`INSERT INTO entries (user_id, project_id, name, time_start, time_end, tags, billable, external_source, external_id)
+		SELECT $1::int, $2::int, $3::text, $4::timestamp, $5::timestamp, $6::text[], $7::boolean, $8::varchar, $9::varchar
+		WHERE NOT EXISTS (
+			SELECT 1 FROM entries
+			WHERE user_id = $1 AND project_id = $2 AND name = $3 AND time_start = $4 AND time_end = $5
+			  AND deleted_at IS NULL
+		)
+		ON CONFLICT (user_id, external_source, external_id) WHERE external_source IS NOT NULL
+		DO NOTHING;`)
+	if err != nil {
+		return ImportStats{}, fmt.Errorf("prepare context: %w", err)
+	}
+
+	defer func() {
+		_ = entryStmt.Close()
+	}()
+
+	for _, entry := range archive.Entries {
+		tags := entry.Tags
+		if tags == nil {
+			tags = []string{}
+		}
+
+		res, err := entryStmt.ExecContext(ctx,
+			userID,
+			plan.projectID(entry.ProjectID),
+			entry.Name,
+			entry.TimeStart,
+			entry.TimeEnd,
+			pq.Array(tags),
+			entry.Billable,
+			entry.ExternalSource,
+			entry.ExternalID,
+		)
+		if err != nil {
+			return ImportStats{}, fmt.Errorf("insert entry: %w", err)
+		}
+
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return ImportStats{}, fmt.Errorf("rows affected: %w", err)
+		}
+
+		if affected == 0 {
+			stats.SkippedEntries++
+		} else {
+			stats.ImportedEntries++
+		}
+	}
+
+	goalStmt, err := tx.PrepareContext(ctx,
+		`INSERT INTO goals (user_id, project_id, name, time_seconds, date_start, date_end)
+		SELECT $1::int, $2::int, $3::varchar, $4::bigint, $5::timestamp, $6::timestamp
+		WHERE NOT EXISTS (
+			SELECT 1 FROM goals
+			WHERE user_id = $1 AND project_id = $2 AND name = $3 AND date_start = $5 AND date_end = $6
+			  AND deleted_at IS NULL
+		);`)
+	if err != nil {
+		return ImportStats{}, fmt.Errorf("prepare context: %w", err)
+	}
+
+	defer func() {
+		_ = goalStmt.Close()
+	}()
+
+	for _, goal := range archive.Goals {
+		res, err := goalStmt.ExecContext(ctx,
+			userID,
+			plan.projectID(goal.ProjectID),
+			goal.Name,
+			goal.TimeSeconds,
+			goal.DateStart,
+			goal.DateEnd,
+		)
+		if err != nil {
+			return ImportStats{}, fmt.Errorf("insert goal: %w", err)
+		}
+
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return ImportStats{}, fmt.Errorf("rows affected: %w", err)
+		}
+
+		if affected == 0 {
+			stats.SkippedGoals++
+		} else {
+			stats.ImportedGoals++
+		}
+	}
+
+	return stats, nil
+}
+
+// getPersonalProjectIDs возвращает личные проекты пользователя по названию. Из проектов
+// с одинаковым названием выбирается созданный первым.
+func (r *Repository) getPersonalProjectIDs(ctx context.Context, db transaction.Executor, userID int64) (map[string]int64, error) {
+	rows, err := db.QueryContext(ctx,
+		`SELECT DISTINCT ON (name) name, id FROM projects
+		WHERE user_id = $1 AND workspace_id IS NULL AND deleted_at IS NULL
+		ORDER BY name, id`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("query context: %w", err)
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	projects := make(map[string]int64)
+	for rows.Next() {
+		var (
+			name string
+			id   int64
+		)
+		if err = rows.Scan(&name, &id); err != nil {
+			return nil, fmt.Errorf("scan: %w", err)
+		}
+
+		projects[name] = id
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("rows err: %w", rows.Err())
+	}
+
+	return projects, nil
+}
+
+// projectPlan сопоставление проектов архива с личными проектами аккаунта.
+type projectPlan struct {
+	// Названия проектов по идентификатору в архиве.
+	names map[int64]string
+	// Идентификаторы личных проектов аккаунта по названию. Созданные при импорте проекты
+	// добавляются после вставки.
+	ids map[string]int64
+	// Проекты, которых нет в аккаунте, по одному на название.
+	create []Project
+	// Названия проектов, найденных в аккаунте.
+	matched []string
+}
+
+// planProjects сопоставляет проекты архива с личными проектами аккаунта existing по названию.
+// Проекты с одинаковым названием из разных пространств сливаются в один личный.
+func planProjects(projects []Project, existing map[string]int64) projectPlan {
+	plan := projectPlan{
+		names: make(map[int64]string, len(projects)),
+		ids:   make(map[string]int64, len(projects)),
+	}
+
+	for _, project := range projects {
+		plan.names[project.ID] = project.Name
+		if _, ok := plan.ids[project.Name]; ok {
+			continue
+		}
+
+		id, ok := existing[project.Name]
+		if ok {
+			plan.matched = append(plan.matched, project.Name)
+		} else {
+			plan.create = append(plan.create, project)
+		}
+		plan.ids[project.Name] = id
+	}
+
+	return plan
+}
+
+// projectID возвращает идентификатор проекта аккаунта по идентификатору проекта в архиве.
+func (p projectPlan) projectID(archiveID int64) int64 {
+	return p.ids[p.names[archiveID]]
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+)
+
+func TestPlanProjects(t *testing.T) {
+	archive := []Project{
+		{ID: 1, Name: "Website"},
+		// Проект пространства с тем же названием сливается с личным.
+		{ID: 2, Name: "Website", WorkspaceID: sql.NullInt64{Int64: 5, Valid: true}},
+		{ID: 3, Name: "Research", Client: sql.NullString{String: "Acme", Valid: true}},
+		{ID: 4, Name: "Research"},
+	}
+	existing := map[string]int64{"Website": 40, "Internal": 41}
+
+	plan := planProjects(archive, existing)
+
+	if !reflect.DeepEqual(plan.matched, []string{"Website"}) {
+		t.Errorf("matched %v, want [Website]", plan.matched)
+	}
+	if len(plan.create) != 1 || plan.create[0].ID != 3 {
+		t.Fatalf("create %+v, want only the first Research", plan.create)
+	}
+
+	// Вставка созданного проекта.
+	plan.ids["Research"] = 42
+
+	want := map[int64]int64{1: 40, 2: 40, 3: 42, 4: 42}
+	for archiveID, accountID := range want {
+		if got := plan.projectID(archiveID); got != accountID {
+			t.Errorf("archive project %d: got account project %d, want %d", archiveID, got, accountID)
+		}
+	}
+}
+
+func TestPlanProjectsIntoEmptyAccount(t *testing.T) {
+	archive := []Project{{ID: 10, Name: "Website"}, {ID: 11, Name: "Internal"}}
+
+	plan := planProjects(archive, map[string]int64{})
+
+	if len(plan.matched) != 0 {
+		t.Errorf("matched %v in an empty account", plan.matched)
+	}
+	if !reflect.DeepEqual(plan.create, archive) {
+		t.Errorf("create %+v, want all archive projects", plan.create)
+	}
+}
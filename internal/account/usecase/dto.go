@@ -0,0 +1,59 @@
+package usecase
+
+import "time"
+
+// Archive данные аккаунта для переноса между инсталляциями.
+type Archive struct {
+	Version    int
+	ExportedAt time.Time
+	Profile    Profile
+	Projects   []Project
+	Entries    []Entry
+	Goals      []Goal
+}
+
+type Profile struct {
+	Name  string
+	Email string
+}
+
+type Project struct {
+	ID   int64
+	Name string
+	// Идентификатор пространства в исходной инсталляции, 0 для личного проекта.
+	WorkspaceID int64
+	Client      string
+}
+
+type Entry struct {
+	ID             int64
+	ProjectID      int64
+	Name           string
+	TimeStart      time.Time
+	TimeEnd        time.Time
+	Tags           []string
+	Billable       bool
+	ExternalSource string
+	ExternalID     string
+}
+
+type Goal struct {
+	ID          int64
+	ProjectID   int64
+	Name        string
+	TimeSeconds int64
+	DateStart   time.Time
+	DateEnd     time.Time
+}
+
+type ImportResult struct {
+	// Проекты, созданные при импорте.
+	CreatedProjects []string
+	// Проекты архива, сопоставленные с существующими личными проектами по названию.
+	MatchedProjects []string
+	ImportedEntries int
+	// Записи и цели, которые уже есть в аккаунте.
+	SkippedEntries int
+	ImportedGoals  int
+	SkippedGoals   int
+}
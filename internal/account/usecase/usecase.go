@@ -0,0 +1,302 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	repo "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/account/repository"
+	auditUC "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/audit/usecase"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/tracing"
+)
+
+// ArchiveVersion версия формата архива. При несовместимом изменении формата версия
+// увеличивается, а Import продолжает принимать старые версии.
+const ArchiveVersion = 1
+
+var (
+	ErrUserNotFound              = errors.New("user not found")
+	ErrInvalidArchive            = errors.New("invalid archive")
+	ErrUnsupportedArchiveVersion = errors.New("unsupported archive version")
+)
+
+// Ограничения колонок users.name, projects.name, projects.client, goals.name и entries.external_*.
+const (
+	maxNameLen           = 35
+	maxClientLen         = 64
+	maxExternalSourceLen = 16
+	maxExternalIDLen     = 64
+)
+
+type repository interface {
+	GetProfile(ctx context.Context, userID int64) (repo.Profile, error)
+	GetProjects(ctx context.Context, userID int64) ([]repo.Project, error)
+	GetEntries(ctx context.Context, userID int64) ([]repo.Entry, error)
+	GetGoals(ctx context.Context, userID int64) ([]repo.Goal, error)
+	Import(ctx context.Context, userID int64, archive repo.Archive) (repo.ImportStats, error)
+}
+
+type auditLogger interface {
+	Record(ctx context.Context, event auditUC.Event) error
+}
+
+type txManager interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type statsCache interface {
+	InvalidateUser(ctx context.Context, userID int64)
+}
+
+type Usecase struct {
+	repository  repository
+	auditLogger auditLogger
+	txManager   txManager
+	statsCache  statsCache
+}
+
+func NewUsecase(repository repository, auditLogger auditLogger, txManager txManager, statsCache statsCache) *Usecase {
+	return &Usecase{
+		repository:  repository,
+		auditLogger: auditLogger,
+		txManager:   txManager,
+		statsCache:  statsCache,
+	}
+}
+
+// Export собирает архив аккаунта: профиль, личные проекты и проекты пространств
+// с записями или целями пользователя, его записи и цели. Корзина не выгружается.
+func (u *Usecase) Export(ctx context.Context, userID int64) (Archive, error) {
+	ctx, span := tracing.Start(ctx, "account.Export")
+	defer span.End()
+
+	profile, err := u.repository.GetProfile(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repo.ErrUserNotFound) {
+			return Archive{}, ErrUserNotFound
+		}
+
+		return Archive{}, fmt.Errorf("repo get profile: %w", err)
+	}
+
+	projects, err := u.repository.GetProjects(ctx, userID)
+	if err != nil {
+		return Archive{}, fmt.Errorf("repo get projects: %w", err)
+	}
+
+	entries, err := u.repository.GetEntries(ctx, userID)
+	if err != nil {
+		return Archive{}, fmt.Errorf("repo get entries: %w", err)
+	}
+
+	goals, err := u.repository.GetGoals(ctx, userID)
+	if err != nil {
+		return Archive{}, fmt.Errorf("repo get goals: %w", err)
+	}
+
+	archive := Archive{
+		Version:    ArchiveVersion,
+		ExportedAt: time.Now().UTC(),
+		Profile: Profile{
+			Name:  profile.Name,
+			Email: profile.Email,
+		},
+		Projects: make([]Project, 0, len(projects)),
+		Entries:  make([]Entry, 0, len(entries)),
+		Goals:    make([]Goal, 0, len(goals)),
+	}
+
+	for _, p := range projects {
+		archive.Projects = append(archive.Projects, Project{
+			ID:          p.ID,
+			Name:        p.Name,
+			WorkspaceID: p.WorkspaceID.Int64,
+			Client:      p.Client.String,
+		})
+	}
+
+	for _, e := range entries {
+		archive.Entries = append(archive.Entries, Entry{
+			ID:             e.ID,
+			ProjectID:      e.ProjectID,
+			Name:           e.Name,
+			TimeStart:      e.TimeStart,
+			TimeEnd:        e.TimeEnd,
+			Tags:           e.Tags,
+			Billable:       e.Billable,
+			ExternalSource: e.ExternalSource.String,
+			ExternalID:     e.ExternalID.String,
+		})
+	}
+
+	for _, g := range goals {
+		archive.Goals = append(archive.Goals, Goal{
+			ID:          g.ID,
+			ProjectID:   g.ProjectID,
+			Name:        g.Name,
+			TimeSeconds: g.TimeSeconds,
+			DateStart:   g.DateStart,
+			DateEnd:     g.DateEnd,
+		})
+	}
+
+	return archive, nil
+}
+
+// Import восстанавливает архив в пустой или существующий аккаунт. Идентификаторы из архива
+// не сохраняются: проекты сопоставляются с личными проектами по названию или создаются,
+// записи и цели получают новые идентификаторы. Проекты пространств становятся личными,
+// так как пространства исходной инсталляции здесь нет. Email аккаунта не меняется.
+func (u *Usecase) Import(ctx context.Context, userID int64, archive Archive) (ImportResult, error) {
+	ctx, span := tracing.Start(ctx, "account.Import")
+	defer span.End()
+
+	if archive.Version < 1 || archive.Version > ArchiveVersion {
+		return ImportResult{}, fmt.Errorf("%w: %d", ErrUnsupportedArchiveVersion, archive.Version)
+	}
+
+	if err := validateArchive(archive); err != nil {
+		return ImportResult{}, fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+	}
+
+	var result ImportResult
+
+	// Архив, журнал и outbox сохраняются атомарно.
+	err := u.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		stats, err := u.repository.Import(ctx, userID, convertToRepoArchive(archive))
+		if err != nil {
+			return fmt.Errorf("repo import: %w", err)
+		}
+
+		result = ImportResult{
+			CreatedProjects: stats.CreatedProjects,
+			MatchedProjects: stats.MatchedProjects,
+			ImportedEntries: stats.ImportedEntries,
+			SkippedEntries:  stats.SkippedEntries,
+			ImportedGoals:   stats.ImportedGoals,
+			SkippedGoals:    stats.SkippedGoals,
+		}
+
+		err = u.auditLogger.Record(ctx, auditUC.Event{
+			ActorID:    userID,
+			EntityType: auditUC.EntityUserData,
+			Action:     auditUC.ActionImport,
+			After: map[string]interface{}{
+				"archive_version":  archive.Version,
+				"created_projects": result.CreatedProjects,
+				"imported_entries": result.ImportedEntries,
+				"imported_goals":   result.ImportedGoals,
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("audit record: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return ImportResult{}, err
+	}
+
+	u.statsCache.InvalidateUser(ctx, userID)
+
+	return result, nil
+}
+
+func validateArchive(archive Archive) error {
+	if utf8.RuneCountInString(archive.Profile.Name) > maxNameLen {
+		return fmt.Errorf("profile name is longer than %d characters", maxNameLen)
+	}
+
+	projectIDs := make(map[int64]struct{}, len(archive.Projects))
+	for _, p := range archive.Projects {
+		if _, ok := projectIDs[p.ID]; ok {
+			return fmt.Errorf("project %d: duplicate id", p.ID)
+		}
+		projectIDs[p.ID] = struct{}{}
+
+		if p.Name == "" || utf8.RuneCountInString(p.Name) > maxNameLen {
+			return fmt.Errorf("project %d: name must be 1 to %d characters", p.ID, maxNameLen)
+		}
+		if utf8.RuneCountInString(p.Client) > maxClientLen {
+			return fmt.Errorf("project %d: client is longer than %d characters", p.ID, maxClientLen)
+		}
+	}
+
+	for _, e := range archive.Entries {
+		if _, ok := projectIDs[e.ProjectID]; !ok {
+			return fmt.Errorf("entry %d: unknown project %d", e.ID, e.ProjectID)
+		}
+		if !e.TimeEnd.After(e.TimeStart) {
+			return fmt.Errorf("entry %d: time end must be after time start", e.ID)
+		}
+		if (e.ExternalSource == "") != (e.ExternalID == "") {
+			return fmt.Errorf("entry %d: external source and id must be set together", e.ID)
+		}
+		if len(e.ExternalSource) > maxExternalSourceLen || len(e.ExternalID) > maxExternalIDLen {
+			return fmt.Errorf("entry %d: external source or id is too long", e.ID)
+		}
+	}
+
+	for _, g := range archive.Goals {
+		if _, ok := projectIDs[g.ProjectID]; !ok {
+			return fmt.Errorf("goal %d: unknown project %d", g.ID, g.ProjectID)
+		}
+		if g.Name == "" || utf8.RuneCountInString(g.Name) > maxNameLen {
+			return fmt.Errorf("goal %d: name must be 1 to %d characters", g.ID, maxNameLen)
+		}
+		if g.TimeSeconds <= 0 {
+			return fmt.Errorf("goal %d: time must be positive", g.ID)
+		}
+		if g.DateEnd.Before(g.DateStart) {
+			return fmt.Errorf("goal %d: date end must not be before date start", g.ID)
+		}
+	}
+
+	return nil
+}
+
+func convertToRepoArchive(archive Archive) repo.Archive {
+	out := repo.Archive{
+		ProfileName: archive.Profile.Name,
+		Projects:    make([]repo.Project, 0, len(archive.Projects)),
+		Entries:     make([]repo.Entry, 0, len(archive.Entries)),
+		Goals:       make([]repo.Goal, 0, len(archive.Goals)),
+	}
+
+	for _, p := range archive.Projects {
+		out.Projects = append(out.Projects, repo.Project{
+			ID:     p.ID,
+			Name:   p.Name,
+			Client: sql.NullString{String: p.Client, Valid: p.Client != ""},
+		})
+	}
+
+	for _, e := range archive.Entries {
+		out.Entries = append(out.Entries, repo.Entry{
+			ProjectID:      e.ProjectID,
+			Name:           e.Name,
+			TimeStart:      e.TimeStart,
+			TimeEnd:        e.TimeEnd,
+			Tags:           e.Tags,
+			Billable:       e.Billable,
+			ExternalSource: sql.NullString{String: e.ExternalSource, Valid: e.ExternalSource != ""},
+			ExternalID:     sql.NullString{String: e.ExternalID, Valid: e.ExternalID != ""},
+		})
+	}
+
+	for _, g := range archive.Goals {
+		out.Goals = append(out.Goals, repo.Goal{
+			ProjectID:   g.ProjectID,
+			Name:        g.Name,
+			TimeSeconds: g.TimeSeconds,
+			DateStart:   g.DateStart,
+			DateEnd:     g.DateEnd,
+		})
+	}
+
+	return out
+}
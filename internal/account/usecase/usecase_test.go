@@ -0,0 +1,258 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	repo "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/account/repository"
+	auditUC "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/audit/usecase"
+)
+
+type txKey struct{}
+
+func inTx(ctx context.Context) bool {
+	_, ok := ctx.Value(txKey{}).(struct{})
+	return ok
+}
+
+type fakeTxManager struct{}
+
+func (fakeTxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(context.WithValue(ctx, txKey{}, struct{}{}))
+}
+
+// fakeRepository отдает данные аккаунта для выгрузки и запоминает восстановленный архив.
+type fakeRepository struct {
+	profile  repo.Profile
+	projects []repo.Project
+	entries  []repo.Entry
+	goals    []repo.Goal
+
+	imported     []repo.Archive
+	importedInTx bool
+}
+
+func (r *fakeRepository) GetProfile(context.Context, int64) (repo.Profile, error) {
+	return r.profile, nil
+}
+
+func (r *fakeRepository) GetProjects(context.Context, int64) ([]repo.Project, error) {
+	return r.projects, nil
+}
+
+func (r *fakeRepository) GetEntries(context.Context, int64) ([]repo.Entry, error) {
+	return r.entries, nil
+}
+
+func (r *fakeRepository) GetGoals(context.Context, int64) ([]repo.Goal, error) {
+	return r.goals, nil
+}
+
+func (r *fakeRepository) Import(ctx context.Context, _ int64, archive repo.Archive) (repo.ImportStats, error) {
+	r.imported = append(r.imported, archive)
+	r.importedInTx = inTx(ctx)
+
+	return repo.ImportStats{ImportedEntries: len(archive.Entries), ImportedGoals: len(archive.Goals)}, nil
+}
+
+type fakeAuditLogger struct {
+	events []auditUC.Event
+	// Сколько событий записано вне транзакции.
+	outsideTx int
+}
+
+func (l *fakeAuditLogger) Record(ctx context.Context, event auditUC.Event) error {
+	if !inTx(ctx) {
+		l.outsideTx++
+	}
+	l.events = append(l.events, event)
+	return nil
+}
+
+type fakeStatsCache struct{}
+
+func (fakeStatsCache) InvalidateUser(context.Context, int64) {}
+
+var day = time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: true}
+}
+
+func newSourceAccount() *fakeRepository {
+	return &fakeRepository{
+		profile: repo.Profile{Name: "Анна", Email: "anna@example.com"},
+		projects: []repo.Project{
+			{ID: 10, Name: "Website", Client: nullString("Acme")},
+			{ID: 11, Name: "Website", WorkspaceID: sql.NullInt64{Int64: 3, Valid: true}},
+			{ID: 12, Name: "Research"},
+		},
+		entries: []repo.Entry{
+			{
+				ID:        100,
+				ProjectID: 10,
+				Name:      "Landing page",
+				TimeStart: day.Add(9 * time.Hour),
+				TimeEnd:   day.Add(10 * time.Hour),
+				Tags:      []string{"design"},
+				Billable:  true,
+			},
+			{
+				ID:             101,
+				ProjectID:      11,
+				Name:           "Review",
+				TimeStart:      day.Add(11 * time.Hour),
+				TimeEnd:        day.Add(12 * time.Hour),
+				Tags:           []string{},
+				ExternalSource: nullString("toggl"),
+				ExternalID:     nullString("3104458001"),
+			},
+		},
+		goals: []repo.Goal{
+			{ID: 200, ProjectID: 12, Name: "Read papers", TimeSeconds: 36000, DateStart: day, DateEnd: day.AddDate(0, 1, 0)},
+		},
+	}
+}
+
+// Выгруженный архив восстанавливается без изменений: записи и цели ссылаются на проекты архива,
+// а сопоставление с проектами аккаунта остается репозиторию.
+func TestExportImportRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	source := newSourceAccount()
+
+	archive, err := NewUsecase(source, nil, fakeTxManager{}, fakeStatsCache{}).Export(ctx, 1)
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	if archive.Version != ArchiveVersion || archive.Profile.Email != "anna@example.com" {
+		t.Errorf("archive header %d %+v", archive.Version, archive.Profile)
+	}
+	if archive.Projects[1].WorkspaceID != 3 {
+		t.Errorf("workspace of project 11 lost: %+v", archive.Projects[1])
+	}
+
+	target := &fakeRepository{}
+	audit := &fakeAuditLogger{}
+	result, err := NewUsecase(target, audit, fakeTxManager{}, fakeStatsCache{}).Import(ctx, 2, archive)
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if result.ImportedEntries != 2 || result.ImportedGoals != 1 {
+		t.Errorf("result %+v, want 2 entries and 1 goal", result)
+	}
+
+	if len(target.imported) != 1 {
+		t.Fatalf("archive imported %d times", len(target.imported))
+	}
+	got := target.imported[0]
+
+	if got.ProfileName != "Анна" {
+		t.Errorf("profile name %q", got.ProfileName)
+	}
+
+	// Проекты пространств становятся личными.
+	wantProjects := []repo.Project{
+		{ID: 10, Name: "Website", Client: nullString("Acme")},
+		{ID: 11, Name: "Website"},
+		{ID: 12, Name: "Research"},
+	}
+	if !reflect.DeepEqual(got.Projects, wantProjects) {
+		t.Errorf("projects:\ngot  %+v\nwant %+v", got.Projects, wantProjects)
+	}
+
+	// Идентификаторы записей и целей не переносятся, у восстановленных будут новые.
+	wantEntries := make([]repo.Entry, 0, len(source.entries))
+	for _, e := range source.entries {
+		e.ID = 0
+		wantEntries = append(wantEntries, e)
+	}
+	if !reflect.DeepEqual(got.Entries, wantEntries) {
+		t.Errorf("entries:\ngot  %+v\nwant %+v", got.Entries, wantEntries)
+	}
+
+	wantGoals := []repo.Goal{source.goals[0]}
+	wantGoals[0].ID = 0
+	if !reflect.DeepEqual(got.Goals, wantGoals) {
+		t.Errorf("goals:\ngot  %+v\nwant %+v", got.Goals, wantGoals)
+	}
+
+	if !target.importedInTx || audit.outsideTx != 0 {
+		t.Errorf("archive and audit must be saved in one transaction: repo in tx %v, %d events outside tx",
+			target.importedInTx, audit.outsideTx)
+	}
+	if len(audit.events) != 1 || audit.events[0].Action != auditUC.ActionImport {
+		t.Errorf("audit events %+v, want one import", audit.events)
+	}
+}
+
+func TestImportRejectsUnsupportedVersion(t *testing.T) {
+	uc := NewUsecase(&fakeRepository{}, &fakeAuditLogger{}, fakeTxManager{}, fakeStatsCache{})
+
+	for _, version := range []int{0, ArchiveVersion + 1} {
+		_, err := uc.Import(context.Background(), 1, Archive{Version: version})
+		if !errors.Is(err, ErrUnsupportedArchiveVersion) {
+			t.Errorf("version %d: got error %v, want %v", version, err, ErrUnsupportedArchiveVersion)
+		}
+	}
+}
+
+func TestValidateArchive(t *testing.T) {
+	valid := func() Archive {
+		return Archive{
+			Version:  ArchiveVersion,
+			Profile:  Profile{Name: "Анна"},
+			Projects: []Project{{ID: 1, Name: "Website"}},
+			Entries: []Entry{
+				{ID: 1, ProjectID: 1, Name: "Landing", TimeStart: day, TimeEnd: day.Add(time.Hour)},
+			},
+			Goals: []Goal{
+				{ID: 1, ProjectID: 1, Name: "Ship", TimeSeconds: 3600, DateStart: day, DateEnd: day},
+			},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		modify func(a *Archive)
+		want   string
+	}{
+		{"valid", func(*Archive) {}, ""},
+		{"empty archive", func(a *Archive) { *a = Archive{Version: ArchiveVersion} }, ""},
+		{"long profile name", func(a *Archive) { a.Profile.Name = strings.Repeat("я", maxNameLen+1) }, "profile name"},
+		{"duplicate project id", func(a *Archive) { a.Projects = append(a.Projects, Project{ID: 1, Name: "Other"}) }, "duplicate id"},
+		{"empty project name", func(a *Archive) { a.Projects[0].Name = "" }, "project 1: name"},
+		{"long project name", func(a *Archive) { a.Projects[0].Name = strings.Repeat("я", maxNameLen+1) }, "project 1: name"},
+		{"long client", func(a *Archive) { a.Projects[0].Client = strings.Repeat("a", maxClientLen+1) }, "client"},
+		{"entry of unknown project", func(a *Archive) { a.Entries[0].ProjectID = 2 }, "entry 1: unknown project 2"},
+		{"entry ends before start", func(a *Archive) { a.Entries[0].TimeEnd = day }, "entry 1: time end"},
+		{"external id without source", func(a *Archive) { a.Entries[0].ExternalID = "42" }, "set together"},
+		{"long external id", func(a *Archive) {
+			a.Entries[0].ExternalSource = "toggl"
+			a.Entries[0].ExternalID = strings.Repeat("1", maxExternalIDLen+1)
+		}, "too long"},
+		{"goal of unknown project", func(a *Archive) { a.Goals[0].ProjectID = 2 }, "goal 1: unknown project 2"},
+		{"empty goal name", func(a *Archive) { a.Goals[0].Name = "" }, "goal 1: name"},
+		{"goal without time", func(a *Archive) { a.Goals[0].TimeSeconds = 0 }, "time must be positive"},
+		{"goal ends before start", func(a *Archive) { a.Goals[0].DateEnd = day.AddDate(0, 0, -1) }, "date end"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			archive := valid()
+			tt.modify(&archive)
+
+			err := validateArchive(archive)
+			switch {
+			case tt.want == "" && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)):
+				t.Errorf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package delivery
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type AuditEventOut struct {
+	ID          int64           `json:"id" example:"1"`                                              // Идентификатор события.
+	ActorID     int64           `json:"actor_id" example:"1"`                                        // Кто изменил данные.
+	WorkspaceID int64           `json:"workspace_id,omitempty" example:"1"`                          // Пространство, если данные общие.
+	EntityType  string          `json:"entity_type" example:"entry"`                                 // Тип сущности: entry, project, goal, user_data.
+	EntityID    int64           `json:"entity_id" example:"10"`                                      // Идентификатор сущности.
+	Action      string          `json:"action" example:"update"`                                     // Действие: create, update, delete, restore, import, achieve.
+	Before      json.RawMessage `json:"before,omitempty" swaggertype:"object"`                       // Состояние до изменения.
+	After       json.RawMessage `json:"after,omitempty" swaggertype:"object"`                        // Состояние после изменения.
+	RequestID   string          `json:"request_id,omitempty" example:"3f0c1b7e-2d4f-4a39-9f0e-8c6d"` // Идентификатор запроса.
+	CreatedAt   time.Time       `json:"created_at" example:"2024-03-23T15:04:05Z"`                   // Время события.
+}
+
+type AuditPageOut struct {
+	Events       []AuditEventOut `json:"events"`                               // События, начиная с самых новых.
+	NextBeforeID int64           `json:"next_before_id,omitempty" example:"1"` // Значение before_id для следующей страницы.
+}
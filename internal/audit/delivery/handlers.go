@@ -0,0 +1,189 @@
+package delivery
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	usecaseDto "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/audit/usecase"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/response"
+)
+
+const (
+	defaultPageLimit = 50
+	maxPageLimit     = 200
+)
+
+type usecase interface {
+	GetUserEvents(ctx context.Context, userID, beforeID int64, limit int) ([]usecaseDto.RecordedEvent, error)
+	GetWorkspaceEvents(ctx context.Context, workspaceID, actorID, beforeID int64, limit int) ([]usecaseDto.RecordedEvent, error)
+}
+
+type Delivery struct {
+	usecase usecase
+
+	logger echo.Logger
+}
+
+func RegisterHandlers(
+	e *echo.Echo,
+	usecase usecase,
+	logger echo.Logger,
+) {
+	handler := &Delivery{
+		usecase: usecase,
+
+		logger: logger,
+	}
+
+	e.GET("/me/audit", handler.GetMyAudit)
+	e.GET("/workspaces/:workspace_id/audit", handler.GetWorkspaceAudit)
+}
+
+// GetMyAudit godoc
+// @Summary      История изменений пользователя.
+// @Description  Постраничная история изменений записей, проектов и целей, совершенных пользователем.
+// @Tags     	 audit
+// @Accept	 	application/json
+// @Produce  	application/json
+// @Param    limit query int false "Размер страницы (по умолчанию 50, максимум 200)"
+// @Param    before_id query int false "Вернуть события старше указанного"
+// @Success  200 {object} AuditPageOut "success get audit"
+// @Failure 500 {object} response.Error "internal server error"
+// @Failure 400 {object} response.Error "bad request"
+// @Router   /me/audit [get]
+func (d *Delivery) GetMyAudit(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	limit, beforeID, err := parsePage(c)
+	if err != nil {
+		c.Logger().Errorf("parse page: %v", err)
+		return response.Status(http.StatusBadRequest)
+	}
+
+	userID, ok := c.Get("user_id").(int64)
+	if !ok {
+		c.Logger().Error("can't parse context user_id")
+		return response.Status(http.StatusInternalServerError)
+	}
+
+	events, err := d.usecase.GetUserEvents(ctx, userID, beforeID, limit)
+	if err != nil {
+		c.Logger().Errorf("usecase: %v", err)
+		return handleUsecaseError(err)
+	}
+
+	return c.JSON(http.StatusOK, convertFromUsecaseEvents(events, limit))
+}
+
+// GetWorkspaceAudit godoc
+// @Summary      История изменений пространства.
+// @Description  Постраничная история изменений в проектах пространства. Доступно admin и owner.
+// @Tags     	 audit
+// @Accept	 	application/json
+// @Produce  	application/json
+// @Param    workspace_id path int true "Идентификатор пространства"
+// @Param    limit query int false "Размер страницы (по умолчанию 50, максимум 200)"
+// @Param    before_id query int false "Вернуть события старше указанного"
+// @Success  200 {object} AuditPageOut "success get audit"
+// @Failure 500 {object} response.Error "internal server error"
+// @Failure 400 {object} response.Error "bad request"
+// @Failure 403 {object} response.Error "forbidden"
+// @Router   /workspaces/{workspace_id}/audit [get]
+func (d *Delivery) GetWorkspaceAudit(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	workspaceID, err := strconv.ParseInt(c.Param("workspace_id"), 10, 64)
+	if err != nil {
+		c.Logger().Errorf("parse int: %v", err)
+		return response.InvalidParam("workspace_id", "int")
+	}
+
+	limit, beforeID, err := parsePage(c)
+	if err != nil {
+		c.Logger().Errorf("parse page: %v", err)
+		return response.Status(http.StatusBadRequest)
+	}
+
+	userID, ok := c.Get("user_id").(int64)
+	if !ok {
+		c.Logger().Error("can't parse context user_id")
+		return response.Status(http.StatusInternalServerError)
+	}
+
+	events, err := d.usecase.GetWorkspaceEvents(ctx, workspaceID, userID, beforeID, limit)
+	if err != nil {
+		c.Logger().Errorf("usecase: %v", err)
+		return handleUsecaseError(err)
+	}
+
+	return c.JSON(http.StatusOK, convertFromUsecaseEvents(events, limit))
+}
+
+func parsePage(c echo.Context) (int, int64, error) {
+	limit := defaultPageLimit
+	if limitStr := c.QueryParam("limit"); limitStr != "" {
+		var err error
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		if limit <= 0 || limit > maxPageLimit {
+			return 0, 0, errors.New("limit out of range")
+		}
+	}
+
+	var beforeID int64
+	if beforeIDStr := c.QueryParam("before_id"); beforeIDStr != "" {
+		var err error
+		beforeID, err = strconv.ParseInt(beforeIDStr, 10, 64)
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+
+	return limit, beforeID, nil
+}
+
+func handleUsecaseError(err error) *response.Error {
+	// Запрос не уложился в таймаут или был отменен.
+	if problem := response.ContextError(err); problem != nil {
+		return problem
+	}
+
+	if errors.Is(err, usecaseDto.ErrForbidden) {
+		return response.Status(http.StatusForbidden)
+	}
+
+	// По дефолту пятисотим.
+	return response.Status(http.StatusInternalServerError)
+}
+
+func convertFromUsecaseEvents(events []usecaseDto.RecordedEvent, limit int) AuditPageOut {
+	out := AuditPageOut{Events: make([]AuditEventOut, 0, len(events))}
+	for _, e := range events {
+		out.Events = append(out.Events, AuditEventOut{
+			ID:          e.ID,
+			ActorID:     e.ActorID,
+			WorkspaceID: e.WorkspaceID,
+			EntityType:  e.EntityType,
+			EntityID:    e.EntityID,
+			Action:      e.Action,
+			Before:      e.Before,
+			After:       e.After,
+			RequestID:   e.RequestID,
+			CreatedAt:   e.CreatedAt,
+		})
+	}
+
+	// Полная страница означает, что могут быть более старые события.
+	if len(events) == limit {
+		out.NextBeforeID = events[len(events)-1].ID
+	}
+
+	return out
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+)
+
+type Event struct {
+	ID          int64         `db:"id"`
+	ActorID     int64         `db:"actor_id"`
+	WorkspaceID sql.NullInt64 `db:"workspace_id"`
+	EntityType  string        `db:"entity_type"`
+	EntityID    int64         `db:"entity_id"`
+	Action      string        `db:"action"`
+	Before      []byte        `db:"before"`
+	After       []byte        `db:"after"`
+	RequestID   string        `db:"request_id"`
+	CreatedAt   time.Time     `db:"created_at"`
+}
@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/transaction"
+)
+
+var (
+	ErrEventNotFound = errors.New("audit event not found")
+)
+
+type Repository struct {
+	db    *sqlx.DB
+	close func() error
+}
+
+func NewRepository(db *sqlx.DB) *Repository {
+	return &Repository{
+		db: db,
+		close: func() error {
+			return db.Close()
+		},
+	}
+}
+
+// Close закрывает пул соединений с базой.
+func (r *Repository) Close() error {
+	return r.close()
+}
+
+func (r *Repository) CreateEvent(ctx context.Context, event Event) error {
+	_, err := transaction.GetExecutor(ctx, r.db).ExecContext(ctx,
+		`INSERT INTO audit_events
+				(
+					actor_id,
+					workspace_id,
+					entity_type,
+					entity_id,
+					action,
+					before,
+					after,
+					request_id
+				) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		event.ActorID,
+		event.WorkspaceID,
+		event.EntityType,
+		event.EntityID,
+		event.Action,
+		nullJSON(event.Before),
+		nullJSON(event.After),
+		event.RequestID,
+	)
+
+	if err != nil {
+		return fmt.Errorf("exec: %w", err)
+	}
+
+	return nil
+}
+
+// GetUserEvents возвращает страницу событий, совершенных пользователем, начиная с самых новых.
+// Нулевой beforeID означает первую страницу.
+func (r *Repository) GetUserEvents(ctx context.Context, userID, beforeID int64, limit int) ([]Event, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT
+			id,
+			actor_id,
+			workspace_id,
+			entity_type,
+			entity_id,
+			action,
+			before,
+			after,
+			request_id,
+			created_at
+		FROM audit_events
+		WHERE actor_id = $1 AND ($2 = 0 OR id < $2)
+		ORDER BY id DESC
+		LIMIT $3`, userID, beforeID, limit)
+
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	return scanEvents(rows)
+}
+
+// GetWorkspaceEvents возвращает страницу событий пространства, начиная с самых новых.
+// Нулевой beforeID означает первую страницу.
+func (r *Repository) GetWorkspaceEvents(ctx context.Context, workspaceID, beforeID int64, limit int) ([]Event, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT
+			id,
+			actor_id,
+			workspace_id,
+			entity_type,
+			entity_id,
+			action,
+			before,
+			after,
+			request_id,
+			created_at
+		FROM audit_events
+		WHERE workspace_id = $1 AND ($2 = 0 OR id < $2)
+		ORDER BY id DESC
+		LIMIT $3`, workspaceID, beforeID, limit)
+
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	return scanEvents(rows)
+}
+
+func scanEvents(rows *sql.Rows) ([]Event, error) {
+	var events []Event
+	for rows.Next() {
+		var event Event
+		if err := rows.Scan(
+			&event.ID,
+			&event.ActorID,
+			&event.WorkspaceID,
+			&event.EntityType,
+			&event.EntityID,
+			&event.Action,
+			&event.Before,
+			&event.After,
+			&event.RequestID,
+			&event.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan: %w", err)
+		}
+
+		events = append(events, event)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("rows err: %w", rows.Err())
+	}
+
+	if len(events) == 0 {
+		return nil, ErrEventNotFound
+	}
+
+	return events, nil
+}
+
+// nullJSON передает JSON строкой, так как []byte драйвер отправляет как bytea.
+func nullJSON(data []byte) interface{} {
+	if data == nil {
+		return nil
+	}
+
+	return string(data)
+}
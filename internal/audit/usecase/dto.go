@@ -0,0 +1,80 @@
+package usecase
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Типы сущностей в журнале.
+const (
+	EntityEntry    = "entry"
+	EntityProject  = "project"
+	EntityGoal     = "goal"
+	EntityUserData = "user_data"
+)
+
+// Действия в журнале.
+const (
+	ActionCreate  = "create"
+	ActionUpdate  = "update"
+	ActionDelete  = "delete"
+	ActionRestore = "restore"
+	ActionImport  = "import"
+	// Цель впервые достигнута.
+	ActionAchieve = "achieve"
+)
+
+// Event событие изменения данных. Before и After сохраняются в журнал как JSON.
+type Event struct {
+	ActorID     int64
+	WorkspaceID int64
+	EntityType  string
+	EntityID    int64
+	Action      string
+	Before      interface{}
+	After       interface{}
+}
+
+type RecordedEvent struct {
+	ID          int64
+	ActorID     int64
+	WorkspaceID int64
+	EntityType  string
+	EntityID    int64
+	Action      string
+	Before      json.RawMessage
+	After       json.RawMessage
+	RequestID   string
+	CreatedAt   time.Time
+}
+
+// EntryState состояние записи времени в журнале.
+type EntryState struct {
+	ID        int64     `json:"id"`
+	UserID    int64     `json:"user_id"`
+	ProjectID int64     `json:"project_id"`
+	Name      string    `json:"name"`
+	TimeStart time.Time `json:"time_start"`
+	TimeEnd   time.Time `json:"time_end"`
+	Tags      []string  `json:"tags,omitempty"`
+	Billable  bool      `json:"billable"`
+}
+
+// ProjectState состояние проекта в журнале.
+type ProjectState struct {
+	ID          int64  `json:"id"`
+	UserID      int64  `json:"user_id"`
+	WorkspaceID int64  `json:"workspace_id,omitempty"`
+	Name        string `json:"name"`
+}
+
+// GoalState состояние цели в журнале.
+type GoalState struct {
+	ID          int64     `json:"id"`
+	UserID      int64     `json:"user_id"`
+	ProjectID   int64     `json:"project_id"`
+	Name        string    `json:"name"`
+	TimeSeconds int64     `json:"time_seconds"`
+	DateStart   time.Time `json:"date_start"`
+	DateEnd     time.Time `json:"date_end"`
+}
@@ -0,0 +1,171 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	repo "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/audit/repository"
+	outboxRepoDto "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/outbox/repository"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/requestid"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/roles"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/tracing"
+	workspaceRepoDto "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/workspace/repository"
+)
+
+var ErrForbidden = errors.New("forbidden")
+
+type repository interface {
+	CreateEvent(ctx context.Context, event repo.Event) error
+	GetUserEvents(ctx context.Context, userID, beforeID int64, limit int) ([]repo.Event, error)
+	GetWorkspaceEvents(ctx context.Context, workspaceID, beforeID int64, limit int) ([]repo.Event, error)
+}
+
+type workspaceRepository interface {
+	GetMemberRole(ctx context.Context, workspaceID, userID int64) (string, error)
+}
+
+type outboxRepository interface {
+	Write(ctx context.Context, msg outboxRepoDto.Message) error
+}
+
+type Usecase struct {
+	repository          repository
+	workspaceRepository workspaceRepository
+	outboxRepository    outboxRepository
+}
+
+func NewUsecase(repository repository, workspaceRepository workspaceRepository, outboxRepository outboxRepository) *Usecase {
+	return &Usecase{
+		repository:          repository,
+		workspaceRepository: workspaceRepository,
+		outboxRepository:    outboxRepository,
+	}
+}
+
+// Record добавляет событие в журнал и в outbox для публикации подписчикам.
+// Вызванный внутри транзакции изменения, сохраняется только вместе с ним.
+// Других мест записи в outbox нет: изменение записи, проекта или цели, которое
+// не вызвало Record в своей транзакции, подписчикам не публикуется.
+// Идентификатор запроса берется из контекста.
+func (u *Usecase) Record(ctx context.Context, event Event) error {
+	ctx, span := tracing.Start(ctx, "audit.Record")
+	defer span.End()
+
+	before, err := marshalState(event.Before)
+	if err != nil {
+		return fmt.Errorf("marshal before: %w", err)
+	}
+
+	after, err := marshalState(event.After)
+	if err != nil {
+		return fmt.Errorf("marshal after: %w", err)
+	}
+
+	workspaceID := sql.NullInt64{Int64: event.WorkspaceID, Valid: event.WorkspaceID != 0}
+	requestID := requestid.FromContext(ctx)
+
+	err = u.repository.CreateEvent(ctx, repo.Event{
+		ActorID:     event.ActorID,
+		WorkspaceID: workspaceID,
+		EntityType:  event.EntityType,
+		EntityID:    event.EntityID,
+		Action:      event.Action,
+		Before:      before,
+		After:       after,
+		RequestID:   requestID,
+	})
+	if err != nil {
+		return fmt.Errorf("repo create event: %w", err)
+	}
+
+	err = u.outboxRepository.Write(ctx, outboxRepoDto.Message{
+		ActorID:     event.ActorID,
+		WorkspaceID: workspaceID,
+		EntityType:  event.EntityType,
+		EntityID:    event.EntityID,
+		Action:      event.Action,
+		Before:      before,
+		After:       after,
+		RequestID:   requestID,
+	})
+	if err != nil {
+		return fmt.Errorf("repo write outbox: %w", err)
+	}
+
+	return nil
+}
+
+// GetUserEvents возвращает историю изменений, совершенных пользователем.
+func (u *Usecase) GetUserEvents(ctx context.Context, userID, beforeID int64, limit int) ([]RecordedEvent, error) {
+	ctx, span := tracing.Start(ctx, "audit.GetUserEvents")
+	defer span.End()
+
+	events, err := u.repository.GetUserEvents(ctx, userID, beforeID, limit)
+	if err != nil {
+		if errors.Is(err, repo.ErrEventNotFound) {
+			return []RecordedEvent{}, nil
+		}
+		return nil, fmt.Errorf("repo get user events: %w", err)
+	}
+
+	return convertToRecordedEvents(events), nil
+}
+
+// GetWorkspaceEvents возвращает историю изменений в пространстве, доступно admin и owner.
+func (u *Usecase) GetWorkspaceEvents(ctx context.Context, workspaceID, actorID, beforeID int64, limit int) ([]RecordedEvent, error) {
+	ctx, span := tracing.Start(ctx, "audit.GetWorkspaceEvents")
+	defer span.End()
+
+	role, err := u.workspaceRepository.GetMemberRole(ctx, workspaceID, actorID)
+	if err != nil {
+		if errors.Is(err, workspaceRepoDto.ErrMemberNotFound) {
+			return nil, ErrForbidden
+		}
+		return nil, fmt.Errorf("repo get member role: %w", err)
+	}
+
+	if !roles.Role(role).AtLeast(roles.Admin) {
+		return nil, ErrForbidden
+	}
+
+	events, err := u.repository.GetWorkspaceEvents(ctx, workspaceID, beforeID, limit)
+	if err != nil {
+		if errors.Is(err, repo.ErrEventNotFound) {
+			return []RecordedEvent{}, nil
+		}
+		return nil, fmt.Errorf("repo get workspace events: %w", err)
+	}
+
+	return convertToRecordedEvents(events), nil
+}
+
+func marshalState(state interface{}) ([]byte, error) {
+	if state == nil {
+		return nil, nil
+	}
+
+	return json.Marshal(state)
+}
+
+func convertToRecordedEvents(events []repo.Event) []RecordedEvent {
+	res := make([]RecordedEvent, 0, len(events))
+	for _, e := range events {
+		res = append(res, RecordedEvent{
+			ID:          e.ID,
+			ActorID:     e.ActorID,
+			WorkspaceID: e.WorkspaceID.Int64,
+			EntityType:  e.EntityType,
+			EntityID:    e.EntityID,
+			Action:      e.Action,
+			Before:      e.Before,
+			After:       e.After,
+			RequestID:   e.RequestID,
+			CreatedAt:   e.CreatedAt,
+		})
+	}
+
+	return res
+}
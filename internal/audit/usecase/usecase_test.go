@@ -0,0 +1,199 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+
+	repo "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/audit/repository"
+	outboxRepoDto "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/outbox/repository"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/requestid"
+	workspaceRepoDto "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/workspace/repository"
+)
+
+// txKey отмечает контекст транзакции изменения.
+type txKey struct{}
+
+type fakeRepository struct {
+	repository
+
+	events []repo.Event
+	inTx   []bool
+}
+
+func (r *fakeRepository) CreateEvent(ctx context.Context, event repo.Event) error {
+	r.events = append(r.events, event)
+	r.inTx = append(r.inTx, ctx.Value(txKey{}) != nil)
+	return nil
+}
+
+func (r *fakeRepository) GetUserEvents(_ context.Context, userID, _ int64, _ int) ([]repo.Event, error) {
+	return r.filter(func(e repo.Event) bool { return e.ActorID == userID })
+}
+
+func (r *fakeRepository) GetWorkspaceEvents(_ context.Context, workspaceID, _ int64, _ int) ([]repo.Event, error) {
+	return r.filter(func(e repo.Event) bool { return e.WorkspaceID.Int64 == workspaceID })
+}
+
+func (r *fakeRepository) filter(match func(repo.Event) bool) ([]repo.Event, error) {
+	var events []repo.Event
+	for _, e := range r.events {
+		if match(e) {
+			events = append(events, e)
+		}
+	}
+
+	if len(events) == 0 {
+		return nil, repo.ErrEventNotFound
+	}
+
+	return events, nil
+}
+
+// fakeWorkspaceRepository роли участников пространства.
+type fakeWorkspaceRepository map[int64]string
+
+func (r fakeWorkspaceRepository) GetMemberRole(_ context.Context, _, userID int64) (string, error) {
+	role, ok := r[userID]
+	if !ok {
+		return "", workspaceRepoDto.ErrMemberNotFound
+	}
+
+	return role, nil
+}
+
+type fakeOutboxRepository struct {
+	messages []outboxRepoDto.Message
+	inTx     []bool
+	err      error
+}
+
+func (r *fakeOutboxRepository) Write(ctx context.Context, msg outboxRepoDto.Message) error {
+	if r.err != nil {
+		return r.err
+	}
+
+	r.messages = append(r.messages, msg)
+	r.inTx = append(r.inTx, ctx.Value(txKey{}) != nil)
+	return nil
+}
+
+func TestRecordWritesOutboxInSameTx(t *testing.T) {
+	events, outbox := &fakeRepository{}, &fakeOutboxRepository{}
+	u := NewUsecase(events, nil, outbox)
+
+	ctx := context.WithValue(requestid.NewContext(context.Background(), "req-1"), txKey{}, true)
+	err := u.Record(ctx, Event{
+		ActorID:     7,
+		WorkspaceID: 3,
+		EntityType:  EntityEntry,
+		EntityID:    42,
+		Action:      ActionUpdate,
+		Before:      map[string]string{"name": "old"},
+		After:       map[string]string{"name": "new"},
+	})
+	if err != nil {
+		t.Fatalf("record: %v", err)
+	}
+
+	if len(events.events) != 1 || len(outbox.messages) != 1 {
+		t.Fatalf("audit events %d, outbox messages %d, want one of each", len(events.events), len(outbox.messages))
+	}
+	if !events.inTx[0] || !outbox.inTx[0] {
+		t.Errorf("audit event in tx %v, outbox message in tx %v, want both in the mutation tx", events.inTx[0], outbox.inTx[0])
+	}
+
+	event, msg := events.events[0], outbox.messages[0]
+	want := outboxRepoDto.Message{
+		ActorID:     7,
+		WorkspaceID: sql.NullInt64{Int64: 3, Valid: true},
+		EntityType:  EntityEntry,
+		EntityID:    42,
+		Action:      ActionUpdate,
+		Before:      []byte(`{"name":"old"}`),
+		After:       []byte(`{"name":"new"}`),
+		RequestID:   "req-1",
+	}
+	if !reflect.DeepEqual(msg, want) {
+		t.Errorf("outbox message %+v, want %+v", msg, want)
+	}
+	if !bytes.Equal(event.Before, msg.Before) || !bytes.Equal(event.After, msg.After) || event.RequestID != msg.RequestID {
+		t.Errorf("audit event %+v differs from outbox message %+v", event, msg)
+	}
+}
+
+func TestRecordFailsWhenOutboxWriteFails(t *testing.T) {
+	outboxErr := errors.New("connection reset")
+	u := NewUsecase(&fakeRepository{}, nil, &fakeOutboxRepository{err: outboxErr})
+
+	// Ошибка возвращается вызывающему, и транзакция изменения откатывается вместе с журналом.
+	err := u.Record(context.Background(), Event{ActorID: 7, EntityType: EntityGoal, EntityID: 1, Action: ActionCreate})
+	if !errors.Is(err, outboxErr) {
+		t.Errorf("record: error %v, want %v", err, outboxErr)
+	}
+}
+
+func TestGetUserEvents(t *testing.T) {
+	events := &fakeRepository{events: []repo.Event{
+		{ID: 1, ActorID: 7, EntityType: EntityEntry, EntityID: 42, Action: ActionCreate, RequestID: "req-1"},
+		{ID: 2, ActorID: 8, EntityType: EntityEntry, EntityID: 43, Action: ActionCreate},
+	}}
+	u := NewUsecase(events, nil, nil)
+
+	got, err := u.GetUserEvents(context.Background(), 7, 0, 10)
+	if err != nil {
+		t.Fatalf("get user events: %v", err)
+	}
+
+	want := []RecordedEvent{{ID: 1, ActorID: 7, EntityType: EntityEntry, EntityID: 42, Action: ActionCreate, RequestID: "req-1"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("events %+v, want %+v", got, want)
+	}
+
+	// Пустая история отдается пустым списком, а не ошибкой.
+	got, err = u.GetUserEvents(context.Background(), 9, 0, 10)
+	if err != nil || got == nil || len(got) != 0 {
+		t.Errorf("events of user without history: %v, %v, want empty list", got, err)
+	}
+}
+
+func TestGetWorkspaceEvents(t *testing.T) {
+	events := &fakeRepository{events: []repo.Event{
+		{ID: 1, ActorID: 3, WorkspaceID: sql.NullInt64{Int64: 5, Valid: true}, EntityType: EntityProject, EntityID: 10, Action: ActionUpdate},
+		// Личные изменения участников в историю пространства не попадают.
+		{ID: 2, ActorID: 3, EntityType: EntityEntry, EntityID: 42, Action: ActionCreate},
+	}}
+	members := fakeWorkspaceRepository{1: "owner", 2: "admin", 3: "member", 4: "viewer"}
+	wantEvents := []RecordedEvent{{ID: 1, ActorID: 3, WorkspaceID: 5, EntityType: EntityProject, EntityID: 10, Action: ActionUpdate}}
+
+	tests := []struct {
+		name    string
+		actorID int64
+		want    []RecordedEvent
+		wantErr error
+	}{
+		{name: "owner", actorID: 1, want: wantEvents},
+		{name: "admin", actorID: 2, want: wantEvents},
+		{name: "member", actorID: 3, wantErr: ErrForbidden},
+		{name: "viewer", actorID: 4, wantErr: ErrForbidden},
+		{name: "not a member", actorID: 9, wantErr: ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := NewUsecase(events, members, nil)
+
+			got, err := u.GetWorkspaceEvents(context.Background(), 5, tt.actorID, 0, 10)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error %v, want %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("events %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
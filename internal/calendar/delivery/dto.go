@@ -0,0 +1,6 @@
+package delivery
+
+type CalendarTokenOut struct {
+	Token string `json:"token" example:"3f2a9c..."`                                    // Секретный токен ленты, показывается один раз.
+	URL   string `json:"url" example:"https://tracker.example.com/ical/3f2a9c....ics"` // Адрес ленты для подписки в календаре.
+}
@@ -0,0 +1,201 @@
+package delivery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	usecaseDto "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/calendar/usecase"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/ical"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/response"
+)
+
+type usecase interface {
+	CreateToken(ctx context.Context, userID int64) (string, error)
+	DeleteToken(ctx context.Context, userID int64) error
+	GetFeedVersion(ctx context.Context, token string) (usecaseDto.FeedVersion, error)
+	GetFeed(ctx context.Context, version usecaseDto.FeedVersion) (ical.Calendar, error)
+}
+
+const feedSuffix = ".ics"
+
+type Delivery struct {
+	usecase usecase
+
+	logger echo.Logger
+}
+
+func RegisterHandlers(
+	e *echo.Echo,
+	usecase usecase,
+	logger echo.Logger,
+) {
+	handler := &Delivery{
+		usecase: usecase,
+
+		logger: logger,
+	}
+
+	e.POST("/me/calendar/token", handler.CreateToken)
+	e.DELETE("/me/calendar/token", handler.DeleteToken)
+	// Параметр маршрута echo занимает сегмент целиком, суффикс .ics отрезаем сами.
+	e.GET("/ical/:file", handler.GetFeed)
+}
+
+// CreateToken godoc
+// @Summary      Выпустить ссылку на календарь.
+// @Description  Выпустить секретную ссылку на iCalendar-ленту записей времени. Прежняя ссылка перестает работать. Токен показывается только в этом ответе.
+// @Tags     	 calendar
+// @Accept	 application/json
+// @Produce  application/json
+// @Success  200 {object} CalendarTokenOut "success create token"
+// @Failure 500 {object} response.Error "internal server error"
+// @Router   /me/calendar/token [post]
+func (d *Delivery) CreateToken(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, ok := c.Get("user_id").(int64)
+	if !ok {
+		c.Logger().Error("can't parse context user_id")
+		return response.Status(http.StatusInternalServerError)
+	}
+
+	token, err := d.usecase.CreateToken(ctx, userID)
+	if err != nil {
+		c.Logger().Errorf("usecase: %v", err)
+		return handleUsecaseError(err)
+	}
+
+	out := CalendarTokenOut{
+		Token: token,
+		URL:   fmt.Sprintf("%s://%s/ical/%s%s", c.Scheme(), c.Request().Host, token, feedSuffix),
+	}
+
+	return c.JSON(http.StatusOK, out)
+}
+
+// DeleteToken godoc
+// @Summary      Отключить ссылку на календарь.
+// @Description  Отключить iCalendar-ленту записей времени.
+// @Tags     	 calendar
+// @Accept	 application/json
+// @Produce  application/json
+// @Success  200  "success delete token"
+// @Failure 500 {object} response.Error "internal server error"
+// @Failure 404 {object} response.Error "item is not found"
+// @Router   /me/calendar/token [delete]
+func (d *Delivery) DeleteToken(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, ok := c.Get("user_id").(int64)
+	if !ok {
+		c.Logger().Error("can't parse context user_id")
+		return response.Status(http.StatusInternalServerError)
+	}
+
+	err := d.usecase.DeleteToken(ctx, userID)
+	if err != nil {
+		c.Logger().Errorf("usecase: %v", err)
+		return handleUsecaseError(err)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+// GetFeed godoc
+// @Summary      iCalendar-лента записей времени.
+// @Description  Записи пользователя за скользящее окно в виде событий VEVENT: название записи — SUMMARY, проект — CATEGORIES. Доступна без авторизации по секретному токену. Поддерживает If-None-Match и If-Modified-Since.
+// @Tags     	 calendar
+// @Produce  text/calendar
+// @Param    file path string true "Токен с суффиксом .ics"
+// @Success  200  "success get feed"
+// @Success  304  "not modified"
+// @Failure 500 {object} response.Error "internal server error"
+// @Failure 404 {object} response.Error "item is not found"
+// @Router   /ical/{file} [get]
+func (d *Delivery) GetFeed(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	token, ok := strings.CutSuffix(c.Param("file"), feedSuffix)
+	if !ok || token == "" {
+		return response.Status(http.StatusNotFound)
+	}
+
+	version, err := d.usecase.GetFeedVersion(ctx, token)
+	if err != nil {
+		c.Logger().Errorf("usecase: %v", err)
+		return handleUsecaseError(err)
+	}
+
+	header := c.Response().Header()
+	header.Set("ETag", version.ETag)
+	header.Set("Last-Modified", version.LastModified.Format(http.TimeFormat))
+	// Клиент может хранить ленту, но перед использованием должен ее перепроверить.
+	header.Set("Cache-Control", "private, no-cache")
+
+	if notModified(c.Request(), version) {
+		return c.NoContent(http.StatusNotModified)
+	}
+
+	cal, err := d.usecase.GetFeed(ctx, version)
+	if err != nil {
+		c.Logger().Errorf("usecase: %v", err)
+		return handleUsecaseError(err)
+	}
+
+	header.Set(echo.HeaderContentType, "text/calendar; charset=utf-8")
+	c.Response().WriteHeader(http.StatusOK)
+
+	if err = ical.Write(c.Response(), cal); err != nil {
+		// Заголовки уже отправлены, остается только записать ошибку в лог.
+		c.Logger().Errorf("write calendar: %v", err)
+	}
+
+	return nil
+}
+
+// notModified проверяет условные заголовки запроса. If-None-Match приоритетнее
+// If-Modified-Since (RFC 9110, 13.2.2).
+func notModified(r *http.Request, version usecaseDto.FeedVersion) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		for _, etag := range strings.Split(inm, ",") {
+			etag = strings.TrimPrefix(strings.TrimSpace(etag), "W/")
+			if etag == "*" || etag == version.ETag {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	if ims := r.Header.Get("If-Modified-Since"); ims != "" {
+		since, err := http.ParseTime(ims)
+		if err != nil {
+			return false
+		}
+
+		// Last-Modified передается с точностью до секунды.
+		return !version.LastModified.Truncate(time.Second).After(since)
+	}
+
+	return false
+}
+
+func handleUsecaseError(err error) *response.Error {
+	// Запрос не уложился в таймаут или был отменен.
+	if problem := response.ContextError(err); problem != nil {
+		return problem
+	}
+
+	if errors.Is(err, usecaseDto.ErrTokenNotFound) {
+		return response.NotFound("calendar")
+	}
+
+	// По дефолту пятисотим.
+	return response.Status(http.StatusInternalServerError)
+}
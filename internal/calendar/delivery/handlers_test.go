@@ -0,0 +1,150 @@
+package delivery
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	usecaseDto "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/calendar/usecase"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/ical"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/response"
+)
+
+const (
+	testToken = "0123abcd"
+	testETag  = `"5f2b0c1e9a7d4c3b8e6f1a2d3c4b5a69"`
+)
+
+var lastModified = time.Date(2024, 3, 4, 10, 30, 15, 500_000_000, time.UTC)
+
+// fakeUsecase лента одного токена. feeds считает, сколько раз собиралась лента.
+type fakeUsecase struct {
+	usecase
+
+	feeds int
+}
+
+func (u *fakeUsecase) GetFeedVersion(_ context.Context, token string) (usecaseDto.FeedVersion, error) {
+	if token != testToken {
+		return usecaseDto.FeedVersion{}, usecaseDto.ErrTokenNotFound
+	}
+
+	return usecaseDto.FeedVersion{UserID: 1, ETag: testETag, LastModified: lastModified}, nil
+}
+
+func (u *fakeUsecase) GetFeed(context.Context, usecaseDto.FeedVersion) (ical.Calendar, error) {
+	u.feeds++
+
+	return ical.Calendar{
+		ProdID: "-//test//RU",
+		Events: []ical.Event{{
+			UID:     "entry-1@timetracker",
+			Start:   lastModified.Add(-time.Hour),
+			End:     lastModified,
+			Summary: "Landing page",
+		}},
+	}, nil
+}
+
+func getFeed(t *testing.T, path string, header http.Header) (*httptest.ResponseRecorder, *fakeUsecase) {
+	t.Helper()
+
+	uc := &fakeUsecase{}
+	e := echo.New()
+	e.HTTPErrorHandler = response.ErrorHandler
+	RegisterHandlers(e, uc, e.Logger)
+
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	for key, values := range header {
+		req.Header[key] = values
+	}
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	return rec, uc
+}
+
+func TestGetFeed(t *testing.T) {
+	rec, uc := getFeed(t, "/ical/"+testToken+".ics", nil)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d, want 200", rec.Code)
+	}
+	if got := rec.Header().Get("ETag"); got != testETag {
+		t.Errorf("ETag %q, want %q", got, testETag)
+	}
+	if got := rec.Header().Get("Last-Modified"); got != "Mon, 04 Mar 2024 10:30:15 GMT" {
+		t.Errorf("Last-Modified %q", got)
+	}
+	if got := rec.Header().Get(echo.HeaderContentType); got != "text/calendar; charset=utf-8" {
+		t.Errorf("Content-Type %q", got)
+	}
+	if !strings.Contains(rec.Body.String(), "SUMMARY:Landing page\r\n") || uc.feeds != 1 {
+		t.Errorf("body %q, feed built %d times", rec.Body.String(), uc.feeds)
+	}
+}
+
+func TestGetFeedConditional(t *testing.T) {
+	tests := []struct {
+		name   string
+		header http.Header
+		want   int
+	}{
+		{"etag matches", http.Header{"If-None-Match": {testETag}}, http.StatusNotModified},
+		{"weak etag matches", http.Header{"If-None-Match": {"W/" + testETag}}, http.StatusNotModified},
+		{"etag in list", http.Header{"If-None-Match": {`"old", ` + testETag}}, http.StatusNotModified},
+		{"any etag", http.Header{"If-None-Match": {"*"}}, http.StatusNotModified},
+		{"etag changed", http.Header{"If-None-Match": {`"old"`}}, http.StatusOK},
+		// Доли секунды в Last-Modified не передаются, клиент присылает время с точностью до секунды.
+		{"not modified since", http.Header{"If-Modified-Since": {"Mon, 04 Mar 2024 10:30:15 GMT"}}, http.StatusNotModified},
+		{"modified since", http.Header{"If-Modified-Since": {"Mon, 04 Mar 2024 10:30:14 GMT"}}, http.StatusOK},
+		{"invalid date", http.Header{"If-Modified-Since": {"yesterday"}}, http.StatusOK},
+		{
+			name: "if-none-match wins over if-modified-since",
+			header: http.Header{
+				"If-None-Match":     {`"old"`},
+				"If-Modified-Since": {"Mon, 04 Mar 2024 10:30:15 GMT"},
+			},
+			want: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, uc := getFeed(t, "/ical/"+testToken+".ics", tt.header)
+
+			if rec.Code != tt.want {
+				t.Fatalf("status %d, want %d", rec.Code, tt.want)
+			}
+			if got := rec.Header().Get("ETag"); got != testETag {
+				t.Errorf("ETag %q, want %q", got, testETag)
+			}
+
+			// Ответ 304 не собирает ленту.
+			wantFeeds := 1
+			if tt.want == http.StatusNotModified {
+				wantFeeds = 0
+			}
+			if uc.feeds != wantFeeds {
+				t.Errorf("feed built %d times, want %d", uc.feeds, wantFeeds)
+			}
+			if tt.want == http.StatusNotModified && rec.Body.Len() != 0 {
+				t.Errorf("304 with body %q", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestGetFeedNotFound(t *testing.T) {
+	for _, path := range []string{"/ical/unknown.ics", "/ical/" + testToken, "/ical/.ics"} {
+		if rec, _ := getFeed(t, path, nil); rec.Code != http.StatusNotFound {
+			t.Errorf("%s: status %d, want 404", path, rec.Code)
+		}
+	}
+}
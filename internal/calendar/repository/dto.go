@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+)
+
+type Entry struct {
+	ID          int64     `db:"id"`
+	ProjectName string    `db:"project_name"`
+	Name        string    `db:"name"`
+	TimeStart   time.Time `db:"time_start"`
+	TimeEnd     time.Time `db:"time_end"`
+	UpdatedAt   time.Time `db:"updated_at"`
+}
+
+// FeedVersion сводка записей ленты, по которой определяется, изменилась ли лента.
+type FeedVersion struct {
+	Count int64 `db:"count"`
+	// Последнее изменение или удаление записи ленты, NULL если записей не было.
+	LastModified sql.NullTime `db:"last_modified"`
+}
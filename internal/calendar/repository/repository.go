@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var ErrTokenNotFound = errors.New("calendar token not found")
+
+type Repository struct {
+	db    *sqlx.DB
+	close func() error
+}
+
+func NewRepository(db *sqlx.DB) *Repository {
+	return &Repository{
+		db: db,
+		close: func() error {
+			return db.Close()
+		},
+	}
+}
+
+// Close закрывает пул соединений с базой.
+func (r *Repository) Close() error {
+	return r.close()
+}
+
+// SetToken сохраняет токен ленты пользователя, заменяя прежний.
+func (r *Repository) SetToken(ctx context.Context, userID int64, tokenHash string) error {
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO calendar_tokens (user_id, token_hash)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET token_hash = EXCLUDED.token_hash, created_at = now()`,
+		userID, tokenHash)
+
+	if err != nil {
+		return fmt.Errorf("exec context: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) DeleteToken(ctx context.Context, userID int64) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM calendar_tokens WHERE user_id = $1`, userID)
+	if err != nil {
+		return fmt.Errorf("exec context: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("rows affected: %w", err)
+	}
+
+	if affected == 0 {
+		return ErrTokenNotFound
+	}
+
+	return nil
+}
+
+// GetUserByToken возвращает владельца токена.
+func (r *Repository) GetUserByToken(ctx context.Context, tokenHash string) (int64, error) {
+	var userID int64
+	err := r.db.QueryRowContext(ctx, `SELECT user_id FROM calendar_tokens WHERE token_hash = $1`, tokenHash).
+		Scan(&userID)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, ErrTokenNotFound
+		}
+
+		return 0, fmt.Errorf("scan: %w", err)
+	}
+
+	return userID, nil
+}
+
+// GetFeedVersion возвращает число записей пользователя с началом не раньше from и время
+// последнего изменения среди них. Удаленные записи учитываются во времени изменения,
+// чтобы удаление тоже меняло версию ленты.
+func (r *Repository) GetFeedVersion(ctx context.Context, userID int64, from time.Time) (FeedVersion, error) {
+	var version FeedVersion
+	err := r.db.QueryRowContext(ctx,
+		`SELECT
+			count(*) FILTER (WHERE deleted_at IS NULL),
+			max(GREATEST(updated_at, deleted_at))
+		FROM entries
+		WHERE user_id = $1 AND time_start >= $2`, userID, from).
+		Scan(&version.Count, &version.LastModified)
+
+	if err != nil {
+		return FeedVersion{}, fmt.Errorf("scan: %w", err)
+	}
+
+	return version, nil
+}
+
+// GetFeedEntries возвращает записи пользователя с началом не раньше from.
+func (r *Repository) GetFeedEntries(ctx context.Context, userID int64, from time.Time) ([]Entry, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT
+			e.id,
+			p.name,
+			e.name,
+			e.time_start,
+			e.time_end,
+			e.updated_at
+		FROM entries e
+		JOIN projects p ON p.id = e.project_id
+		WHERE e.user_id = $1 AND e.time_start >= $2 AND e.deleted_at IS NULL
+		ORDER BY e.time_start, e.id`, userID, from)
+
+	if err != nil {
+		return nil, fmt.Errorf("query context: %w", err)
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	entries := []Entry{}
+	for rows.Next() {
+		var entry Entry
+		if err = rows.Scan(
+			&entry.ID,
+			&entry.ProjectName,
+			&entry.Name,
+			&entry.TimeStart,
+			&entry.TimeEnd,
+			&entry.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan: %w", err)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("rows err: %w", rows.Err())
+	}
+
+	return entries, nil
+}
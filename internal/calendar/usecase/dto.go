@@ -0,0 +1,13 @@
+package usecase
+
+import "time"
+
+// FeedVersion версия ленты пользователя.
+type FeedVersion struct {
+	UserID int64
+	// Начало окна ленты, выровненное по суткам, чтобы версия не менялась в течение дня.
+	From time.Time
+	ETag string
+	// Последнее изменение ленты: изменение записи или сдвиг окна.
+	LastModified time.Time
+}
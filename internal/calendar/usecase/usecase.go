@@ -0,0 +1,161 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	repo "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/calendar/repository"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/ical"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/tracing"
+)
+
+var ErrTokenNotFound = errors.New("calendar token not found")
+
+const (
+	tokenBytes = 32
+	// Меняется при изменении формата ленты, чтобы клиенты получили новую версию.
+	feedFormatVersion = 1
+	prodID            = "-//BMSTU TIMETRACKERS//Time Tracker//RU"
+	uidDomain         = "timetracker"
+)
+
+type repository interface {
+	SetToken(ctx context.Context, userID int64, tokenHash string) error
+	DeleteToken(ctx context.Context, userID int64) error
+	GetUserByToken(ctx context.Context, tokenHash string) (int64, error)
+	GetFeedVersion(ctx context.Context, userID int64, from time.Time) (repo.FeedVersion, error)
+	GetFeedEntries(ctx context.Context, userID int64, from time.Time) ([]repo.Entry, error)
+}
+
+type Usecase struct {
+	repository repository
+	// За какой период до текущего момента лента показывает записи.
+	window time.Duration
+}
+
+func NewUsecase(repository repository, window time.Duration) *Usecase {
+	return &Usecase{
+		repository: repository,
+		window:     window,
+	}
+}
+
+// CreateToken выпускает новый секретный токен ленты, прежний перестает работать.
+// Токен возвращается только здесь, в базе хранится его хеш.
+func (u *Usecase) CreateToken(ctx context.Context, userID int64) (string, error) {
+	ctx, span := tracing.Start(ctx, "calendar.CreateToken")
+	defer span.End()
+
+	raw := make([]byte, tokenBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("generate token: %w", err)
+	}
+	token := hex.EncodeToString(raw)
+
+	if err := u.repository.SetToken(ctx, userID, hashToken(token)); err != nil {
+		return "", fmt.Errorf("repo set token: %w", err)
+	}
+
+	return token, nil
+}
+
+// DeleteToken отключает ленту пользователя.
+func (u *Usecase) DeleteToken(ctx context.Context, userID int64) error {
+	ctx, span := tracing.Start(ctx, "calendar.DeleteToken")
+	defer span.End()
+
+	err := u.repository.DeleteToken(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repo.ErrTokenNotFound) {
+			return ErrTokenNotFound
+		}
+
+		return fmt.Errorf("repo delete token: %w", err)
+	}
+
+	return nil
+}
+
+// GetFeedVersion находит владельца токена и вычисляет версию его ленты
+// без выборки самих записей, чтобы ответ 304 был дешевым.
+func (u *Usecase) GetFeedVersion(ctx context.Context, token string) (FeedVersion, error) {
+	ctx, span := tracing.Start(ctx, "calendar.GetFeedVersion")
+	defer span.End()
+
+	userID, err := u.repository.GetUserByToken(ctx, hashToken(token))
+	if err != nil {
+		if errors.Is(err, repo.ErrTokenNotFound) {
+			return FeedVersion{}, ErrTokenNotFound
+		}
+
+		return FeedVersion{}, fmt.Errorf("repo get user by token: %w", err)
+	}
+
+	from := time.Now().UTC().Add(-u.window).Truncate(24 * time.Hour)
+
+	version, err := u.repository.GetFeedVersion(ctx, userID, from)
+	if err != nil {
+		return FeedVersion{}, fmt.Errorf("repo get feed version: %w", err)
+	}
+
+	lastModified := from
+	if version.LastModified.Valid && version.LastModified.Time.After(from) {
+		lastModified = version.LastModified.Time
+	}
+
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%d|%d|%d|%d|%d",
+		feedFormatVersion, userID, from.Unix(), version.Count, lastModified.UnixNano())))
+
+	return FeedVersion{
+		UserID:       userID,
+		From:         from,
+		ETag:         `"` + hex.EncodeToString(sum[:16]) + `"`,
+		LastModified: lastModified.UTC(),
+	}, nil
+}
+
+// GetFeed возвращает календарь записей ленты: название записи — в заголовке события,
+// проект — в категории.
+func (u *Usecase) GetFeed(ctx context.Context, version FeedVersion) (ical.Calendar, error) {
+	ctx, span := tracing.Start(ctx, "calendar.GetFeed")
+	defer span.End()
+
+	entries, err := u.repository.GetFeedEntries(ctx, version.UserID, version.From)
+	if err != nil {
+		return ical.Calendar{}, fmt.Errorf("repo get feed entries: %w", err)
+	}
+
+	cal := ical.Calendar{
+		ProdID: prodID,
+		Name:   "Time Tracker",
+		Events: make([]ical.Event, 0, len(entries)),
+	}
+
+	for _, e := range entries {
+		summary := e.Name
+		if summary == "" {
+			summary = e.ProjectName
+		}
+
+		cal.Events = append(cal.Events, ical.Event{
+			UID:        fmt.Sprintf("entry-%d@%s", e.ID, uidDomain),
+			Stamp:      e.UpdatedAt,
+			Start:      e.TimeStart,
+			End:        e.TimeEnd,
+			Summary:    summary,
+			Categories: []string{e.ProjectName},
+		})
+	}
+
+	return cal, nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
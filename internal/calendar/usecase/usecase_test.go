@@ -0,0 +1,128 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	repo "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/calendar/repository"
+)
+
+const testToken = "0123abcd"
+
+// fakeRepository лента одного пользователя с токеном testToken.
+type fakeRepository struct {
+	repository
+
+	version repo.FeedVersion
+	entries []repo.Entry
+}
+
+func (r *fakeRepository) GetUserByToken(_ context.Context, tokenHash string) (int64, error) {
+	if tokenHash != hashToken(testToken) {
+		return 0, repo.ErrTokenNotFound
+	}
+
+	return 1, nil
+}
+
+func (r *fakeRepository) GetFeedVersion(context.Context, int64, time.Time) (repo.FeedVersion, error) {
+	return r.version, nil
+}
+
+func (r *fakeRepository) GetFeedEntries(context.Context, int64, time.Time) ([]repo.Entry, error) {
+	return r.entries, nil
+}
+
+func TestGetFeedVersion(t *testing.T) {
+	ctx := context.Background()
+	changed := time.Now().UTC().Add(-time.Hour)
+	r := &fakeRepository{version: repo.FeedVersion{Count: 3, LastModified: sql.NullTime{Time: changed, Valid: true}}}
+	uc := NewUsecase(r, 30*24*time.Hour)
+
+	version, err := uc.GetFeedVersion(ctx, testToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(version.ETag, `"`) || !strings.HasSuffix(version.ETag, `"`) || len(version.ETag) != 34 {
+		t.Errorf("ETag %s is not a quoted 128-bit hash", version.ETag)
+	}
+	if !version.LastModified.Equal(changed) {
+		t.Errorf("Last-Modified %v, want %v", version.LastModified, changed)
+	}
+	if !version.From.Equal(version.From.Truncate(24*time.Hour)) || version.UserID != 1 {
+		t.Errorf("version %+v: window must start at midnight", version)
+	}
+
+	// Та же лента — тот же ETag, иначе клиенты будут скачивать ее при каждом опросе.
+	again, _ := uc.GetFeedVersion(ctx, testToken)
+	if again.ETag != version.ETag {
+		t.Errorf("ETag changed without changes: %s != %s", again.ETag, version.ETag)
+	}
+
+	// Удаление записи уменьшает число записей, изменение — сдвигает время последнего изменения.
+	r.version.Count = 2
+	deleted, _ := uc.GetFeedVersion(ctx, testToken)
+	r.version.LastModified.Time = changed.Add(time.Minute)
+	edited, _ := uc.GetFeedVersion(ctx, testToken)
+
+	if deleted.ETag == version.ETag || edited.ETag == deleted.ETag {
+		t.Errorf("ETag did not change: %s, %s, %s", version.ETag, deleted.ETag, edited.ETag)
+	}
+}
+
+// Без изменений в окне ленты последним изменением считается начало окна.
+func TestGetFeedVersionWithoutChanges(t *testing.T) {
+	for _, lastModified := range []sql.NullTime{
+		{},
+		{Time: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), Valid: true},
+	} {
+		uc := NewUsecase(&fakeRepository{version: repo.FeedVersion{LastModified: lastModified}}, 30*24*time.Hour)
+
+		version, err := uc.GetFeedVersion(context.Background(), testToken)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !version.LastModified.Equal(version.From) {
+			t.Errorf("last modified %v, want window start %v", version.LastModified, version.From)
+		}
+	}
+}
+
+func TestGetFeedVersionUnknownToken(t *testing.T) {
+	uc := NewUsecase(&fakeRepository{}, time.Hour)
+
+	if _, err := uc.GetFeedVersion(context.Background(), "unknown"); !errors.Is(err, ErrTokenNotFound) {
+		t.Errorf("got error %v, want %v", err, ErrTokenNotFound)
+	}
+}
+
+func TestGetFeed(t *testing.T) {
+	start := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	r := &fakeRepository{entries: []repo.Entry{
+		{ID: 1, ProjectName: "Website", Name: "Landing page", TimeStart: start, TimeEnd: start.Add(time.Hour)},
+		// Запись без названия показывается названием проекта.
+		{ID: 2, ProjectName: "Internal", TimeStart: start.Add(2 * time.Hour), TimeEnd: start.Add(3 * time.Hour)},
+	}}
+
+	cal, err := NewUsecase(r, time.Hour).GetFeed(context.Background(), FeedVersion{UserID: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(cal.Events) != 2 {
+		t.Fatalf("got %d events, want 2", len(cal.Events))
+	}
+
+	first, second := cal.Events[0], cal.Events[1]
+	if first.UID != "entry-1@timetracker" || first.Summary != "Landing page" || first.Categories[0] != "Website" {
+		t.Errorf("first event %+v", first)
+	}
+	if second.Summary != "Internal" || second.Categories[0] != "Internal" {
+		t.Errorf("second event %+v", second)
+	}
+}
@@ -7,6 +7,17 @@ type CreateEntryIn struct {
 	Name      string    `json:"name" example:"task1"`                                          // Название записи.
 	TimeStart time.Time `json:"time_start" validate:"required" example:"2024-03-23T15:04:05Z"` // Время начала записи.
 	TimeEnd   time.Time `json:"time_end" validate:"required" example:"2024-03-23T19:04:05Z"`   // Время окончания записи.
+	Tags      []string  `json:"tags" validate:"max=20,dive,max=64" example:"meeting,backend"`  // Теги записи.
+	Billable  bool      `json:"billable" example:"true"`                                       // Оплачиваемое время.
+}
+
+type UpdateEntryIn struct {
+	ProjectID int64     `json:"project_id" validate:"required" example:"1"`                    // Идентификатор проекта.
+	Name      string    `json:"name" example:"task1"`                                          // Название записи.
+	TimeStart time.Time `json:"time_start" validate:"required" example:"2024-03-23T15:04:05Z"` // Время начала записи.
+	TimeEnd   time.Time `json:"time_end" validate:"required" example:"2024-03-23T19:04:05Z"`   // Время окончания записи.
+	Tags      []string  `json:"tags" validate:"max=20,dive,max=64" example:"meeting,backend"`  // Теги записи.
+	Billable  bool      `json:"billable" example:"true"`                                       // Оплачиваемое время.
 }
 
 type CreateEntryOut struct {
@@ -20,4 +31,50 @@ type EntryOut struct {
 	Name        string    `json:"name" example:"task1"`                      // Название записи.
 	TimeStart   time.Time `json:"time_start" example:"2024-03-23T15:04:05Z"` // Время начала записи.
 	TimeEnd     time.Time `json:"time_end" example:"2024-03-23T19:04:05Z"`   // Время окончания записи.
+	Tags        []string  `json:"tags" example:"meeting,backend"`            // Теги записи.
+	Billable    bool      `json:"billable" example:"true"`                   // Оплачиваемое время.
+}
+
+type ImportMappingIn struct {
+	Columns    ImportColumnsIn `json:"columns"`                                          // Соответствие полей записи колонкам файла.
+	TimeLayout string          `json:"time_layout" example:"02.01.2006 15:04"`           // Формат времени в нотации Go, по умолчанию RFC3339.
+	Timezone   string          `json:"timezone" example:"Europe/Moscow"`                 // Часовой пояс времени без смещения, по умолчанию UTC.
+	Delimiter  string          `json:"delimiter" validate:"omitempty,len=1" example:";"` // Разделитель полей, по умолчанию запятая.
+}
+
+type ImportColumnsIn struct {
+	Project   string `json:"project" validate:"required" example:"Проект"`    // Колонка с названием проекта.
+	Name      string `json:"name" example:"Описание"`                         // Колонка с названием записи.
+	TimeStart string `json:"time_start" validate:"required" example:"Начало"` // Колонка со временем начала.
+	TimeEnd   string `json:"time_end" example:"Конец"`                        // Колонка со временем окончания.
+	Duration  string `json:"duration" example:"Длительность"`                 // Колонка с длительностью, если нет колонки окончания.
+}
+
+type ImportResultOut struct {
+	DryRun          bool                `json:"dry_run" example:"true"`                  // Пробный запуск, ничего не сохранено.
+	TotalRows       int                 `json:"total_rows" example:"120"`                // Строк в файле без заголовка.
+	ValidRows       int                 `json:"valid_rows" example:"118"`                // Строк без ошибок.
+	ImportedRows    int                 `json:"imported_rows" example:"0"`               // Сохранено записей.
+	CreatedProjects []string            `json:"created_projects" example:"Работа,Учеба"` // Созданные (или будущие) проекты.
+	Errors          []ImportRowErrorOut `json:"errors"`                                  // Ошибки по строкам.
+	Skipped         []ImportRowErrorOut `json:"skipped"`                                 // Пропущенные строки выгрузки другого трекера.
+}
+
+type ImportRowErrorOut struct {
+	Row     int    `json:"row" example:"5"`                                     // Номер строки файла, заголовок — строка 1.
+	Message string `json:"message" example:"time end must be after time start"` // Описание ошибки.
+}
+
+type CalendarMappingIn struct {
+	Rules          []CalendarRuleIn `json:"rules" validate:"dive"`                                      // Правила выбора проекта, применяется первое подходящее.
+	DefaultProject string           `json:"default_project" validate:"max=35" example:"Встречи"`        // Проект для событий без подходящего правила, пустой — такие события пропускаются.
+	AttendeeEmail  string           `json:"attendee_email" validate:"omitempty,email" example:"a@b.ru"` // Участник, отклоненные которым события пропускаются, по умолчанию email аккаунта.
+	Timezone       string           `json:"timezone" example:"Europe/Moscow"`                           // Часовой пояс времени без смещения, по умолчанию UTC.
+}
+
+type CalendarRuleIn struct {
+	Calendar        string `json:"calendar" example:"Работа"`                               // Название календаря (X-WR-CALNAME).
+	Category        string `json:"category" example:"Meeting"`                              // Категория события.
+	SummaryContains string `json:"summary_contains" example:"standup"`                      // Подстрока заголовка события.
+	Project         string `json:"project" validate:"required,max=35" example:"Разработка"` // Проект для подходящих событий.
 }
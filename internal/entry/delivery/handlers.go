@@ -2,24 +2,39 @@ package delivery
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"mime/multipart"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/labstack/echo/v4"
 
 	usecaseDto "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/entry/usecase"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/export"
 	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/response"
 	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/validator"
 )
 
 type usecase interface {
-	CreateEntry(ctx context.Context, e usecaseDto.Entry) (int64, error)
+	CreateEntry(ctx context.Context, e usecaseDto.Entry, opts usecaseDto.WriteOptions) (int64, error)
+	UpdateEntry(ctx context.Context, e usecaseDto.Entry, opts usecaseDto.WriteOptions) error
+	DeleteEntry(ctx context.Context, entryID int64, userID int64, opts usecaseDto.WriteOptions) error
+	RestoreEntry(ctx context.Context, entryID int64, userID int64, opts usecaseDto.WriteOptions) error
 	GetUserEntries(ctx context.Context, userID int64) ([]usecaseDto.Entry, error)
 	GetUserEntriesForDay(ctx context.Context, userID int64, date time.Time) ([]usecaseDto.Entry, error)
+	ExportEntries(ctx context.Context, filter usecaseDto.ExportFilter, fn func(usecaseDto.ExportEntry) error) error
+	ImportEntries(ctx context.Context, userID int64, r io.Reader, spec usecaseDto.ImportSpec, dryRun bool) (usecaseDto.ImportResult, error)
+	ImportFromTracker(ctx context.Context, userID int64, source string, r io.Reader, loc *time.Location, dryRun bool) (usecaseDto.ImportResult, error)
+	ImportFromCalendar(ctx context.Context, userID int64, r io.Reader, spec usecaseDto.CalendarImportSpec, dryRun bool) (usecaseDto.ImportResult, error)
 }
 
+// Максимальный размер импортируемого файла.
+const maxImportFileSize = 10 << 20
+
 type Delivery struct {
 	usecase usecase
 
@@ -38,7 +53,14 @@ func RegisterHandlers(
 	}
 
 	e.POST("/entries/create", handler.CreateEntry)
+	e.PUT("/entries/:id", handler.UpdateEntry)
+	e.DELETE("/entries/:id", handler.DeleteEntry)
+	e.POST("/entries/:id/restore", handler.RestoreEntry)
 	e.GET("/me/entries", handler.GetMyEntries)
+	e.GET("/me/entries/export", handler.ExportMyEntries)
+	e.POST("/me/entries/import", handler.ImportMyEntries)
+	e.POST("/me/entries/import/ical", handler.ImportFromCalendar)
+	e.POST("/me/entries/import/:source", handler.ImportFromTracker)
 }
 
 // CreateEntry godoc
@@ -48,33 +70,37 @@ func RegisterHandlers(
 // @Accept	 application/json
 // @Produce  application/json
 // @Param    entry body CreateEntryIn true "Информация о записи времени"
+// @Param    lock_override_reason query string false "Причина изменения в заблокированном периоде (только admin и owner)"
 // @Success  200 {object} CreateEntryOut "success create entry"
-// @Failure 500 {object} echo.HTTPError "internal server error"
-// @Failure 400 {object} echo.HTTPError "bad request"
-// @Failure 404 {object} echo.HTTPError "item is not found"
-// @Failure 422 {object} echo.HTTPError "unprocessable entity"
+// @Failure 500 {object} response.Error "internal server error"
+// @Failure 400 {object} response.Error "bad request"
+// @Failure 403 {object} response.Error "forbidden"
+// @Failure 404 {object} response.Error "item is not found"
+// @Failure 409 {object} response.Error "conflict"
+// @Failure 422 {object} response.Error "unprocessable entity"
+// @Failure 423 {object} response.Error "locked"
 // @Router   /entries/create [post]
 func (d *Delivery) CreateEntry(c echo.Context) error {
-	ctx := context.Background()
+	ctx := c.Request().Context()
 
 	var in CreateEntryIn
 	err := c.Bind(&in)
 
 	if err != nil {
 		c.Logger().Errorf("bind request: %v", err)
-		return echo.NewHTTPError(http.StatusUnprocessableEntity, response.ErrorMsgsByCode[http.StatusUnprocessableEntity])
+		return response.Status(http.StatusUnprocessableEntity)
 	}
 
 	if ok, err := validator.IsRequestValid(&in); !ok {
 		c.Logger().Errorf("validation: %v", err)
-		return echo.NewHTTPError(http.StatusBadRequest, response.ErrorMsgsByCode[http.StatusBadRequest])
+		return response.ValidationError(err)
 	}
 
 	// Получаем userID (проставляется в auth мидлваре).
 	userID, ok := c.Get("user_id").(int64)
 	if !ok {
 		c.Logger().Error("can't parse context user_id")
-		return echo.NewHTTPError(http.StatusInternalServerError, response.ErrorMsgsByCode[http.StatusInternalServerError])
+		return response.Status(http.StatusInternalServerError)
 	}
 
 	entry := usecaseDto.Entry{
@@ -82,10 +108,14 @@ func (d *Delivery) CreateEntry(c echo.Context) error {
 		Name:      in.Name,
 		TimeStart: in.TimeStart,
 		TimeEnd:   in.TimeEnd,
+		Tags:      in.Tags,
+		Billable:  in.Billable,
 	}
 
 	entry.UserID = userID
-	entryID, err := d.usecase.CreateEntry(ctx, entry)
+	opts := usecaseDto.WriteOptions{LockOverrideReason: c.QueryParam("lock_override_reason")}
+
+	entryID, err := d.usecase.CreateEntry(ctx, entry, opts)
 	if err != nil {
 		c.Logger().Errorf("usecase: %v", err)
 		return handleUsecaseError(err)
@@ -96,6 +126,158 @@ func (d *Delivery) CreateEntry(c echo.Context) error {
 	return c.JSON(http.StatusOK, out)
 }
 
+// UpdateEntry godoc
+// @Summary      Изменение записи времени.
+// @Description  Изменение своей записи времени. Записи недель с утвержденным табелем изменить нельзя.
+// @Tags     	 entries
+// @Accept	 application/json
+// @Produce  application/json
+// @Param    id path int true "Идентификатор записи"
+// @Param    entry body UpdateEntryIn true "Информация о записи времени"
+// @Param    lock_override_reason query string false "Причина изменения в заблокированном периоде (только admin и owner)"
+// @Success  200  "success update entry"
+// @Failure 500 {object} response.Error "internal server error"
+// @Failure 400 {object} response.Error "bad request"
+// @Failure 403 {object} response.Error "forbidden"
+// @Failure 404 {object} response.Error "item is not found"
+// @Failure 409 {object} response.Error "conflict"
+// @Failure 422 {object} response.Error "unprocessable entity"
+// @Failure 423 {object} response.Error "locked"
+// @Router   /entries/{id} [put]
+func (d *Delivery) UpdateEntry(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	entryID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Logger().Errorf("parse int: %v", err)
+		return response.InvalidParam("id", "int")
+	}
+
+	var in UpdateEntryIn
+	err = c.Bind(&in)
+
+	if err != nil {
+		c.Logger().Errorf("bind request: %v", err)
+		return response.Status(http.StatusUnprocessableEntity)
+	}
+
+	if ok, err := validator.IsRequestValid(&in); !ok {
+		c.Logger().Errorf("validation: %v", err)
+		return response.ValidationError(err)
+	}
+
+	userID, ok := c.Get("user_id").(int64)
+	if !ok {
+		c.Logger().Error("can't parse context user_id")
+		return response.Status(http.StatusInternalServerError)
+	}
+
+	entry := usecaseDto.Entry{
+		ID:        entryID,
+		UserID:    userID,
+		ProjectID: in.ProjectID,
+		Name:      in.Name,
+		TimeStart: in.TimeStart,
+		TimeEnd:   in.TimeEnd,
+		Tags:      in.Tags,
+		Billable:  in.Billable,
+	}
+
+	opts := usecaseDto.WriteOptions{LockOverrideReason: c.QueryParam("lock_override_reason")}
+
+	err = d.usecase.UpdateEntry(ctx, entry, opts)
+	if err != nil {
+		c.Logger().Errorf("usecase: %v", err)
+		return handleUsecaseError(err)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+// DeleteEntry godoc
+// @Summary      Удаление записи времени.
+// @Description  Перенос своей записи времени в корзину. Записи недель с утвержденным табелем удалить нельзя.
+// @Tags     	 entries
+// @Accept	 application/json
+// @Produce  application/json
+// @Param    id path int true "Идентификатор записи"
+// @Param    lock_override_reason query string false "Причина изменения в заблокированном периоде (только admin и owner)"
+// @Success  200  "success delete entry"
+// @Failure 500 {object} response.Error "internal server error"
+// @Failure 400 {object} response.Error "bad request"
+// @Failure 403 {object} response.Error "forbidden"
+// @Failure 404 {object} response.Error "item is not found"
+// @Failure 409 {object} response.Error "conflict"
+// @Failure 423 {object} response.Error "locked"
+// @Router   /entries/{id} [delete]
+func (d *Delivery) DeleteEntry(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	entryID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Logger().Errorf("parse int: %v", err)
+		return response.InvalidParam("id", "int")
+	}
+
+	userID, ok := c.Get("user_id").(int64)
+	if !ok {
+		c.Logger().Error("can't parse context user_id")
+		return response.Status(http.StatusInternalServerError)
+	}
+
+	opts := usecaseDto.WriteOptions{LockOverrideReason: c.QueryParam("lock_override_reason")}
+
+	err = d.usecase.DeleteEntry(ctx, entryID, userID, opts)
+	if err != nil {
+		c.Logger().Errorf("usecase: %v", err)
+		return handleUsecaseError(err)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+// RestoreEntry godoc
+// @Summary      Восстановление записи времени.
+// @Description  Восстановление своей записи времени из корзины. Если проект записи тоже в корзине, сначала нужно восстановить проект.
+// @Tags     	 trash
+// @Accept	 application/json
+// @Produce  application/json
+// @Param    id path int true "Идентификатор записи"
+// @Param    lock_override_reason query string false "Причина изменения в заблокированном периоде (только admin и owner)"
+// @Success  200  "success restore entry"
+// @Failure 500 {object} response.Error "internal server error"
+// @Failure 400 {object} response.Error "bad request"
+// @Failure 403 {object} response.Error "forbidden"
+// @Failure 404 {object} response.Error "item is not found"
+// @Failure 409 {object} response.Error "conflict"
+// @Failure 423 {object} response.Error "locked"
+// @Router   /entries/{id}/restore [post]
+func (d *Delivery) RestoreEntry(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	entryID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Logger().Errorf("parse int: %v", err)
+		return response.InvalidParam("id", "int")
+	}
+
+	userID, ok := c.Get("user_id").(int64)
+	if !ok {
+		c.Logger().Error("can't parse context user_id")
+		return response.Status(http.StatusInternalServerError)
+	}
+
+	opts := usecaseDto.WriteOptions{LockOverrideReason: c.QueryParam("lock_override_reason")}
+
+	err = d.usecase.RestoreEntry(ctx, entryID, userID, opts)
+	if err != nil {
+		c.Logger().Errorf("usecase: %v", err)
+		return handleUsecaseError(err)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
 // GetMyEntries godoc
 // @Summary      Получить записи времени.
 // @Description  Получение всех записей времени пользователя.
@@ -104,19 +286,16 @@ func (d *Delivery) CreateEntry(c echo.Context) error {
 // @Produce  	application/json
 // @Param        day    query     string  false  "day for events in YYYY-MM-DD format"
 // @Success  200 {object} []EntryOut "success create entry"
-// @Failure 500 {object} echo.HTTPError "internal server error"
-// @Failure 400 {object} echo.HTTPError "bad request"
+// @Failure 500 {object} response.Error "internal server error"
+// @Failure 400 {object} response.Error "bad request"
 // @Router   /me/entries [get]
 func (d *Delivery) GetMyEntries(c echo.Context) error {
-	ctx := context.Background()
+	ctx := c.Request().Context()
 
 	userID, ok := c.Get("user_id").(int64)
 	if !ok {
 		c.Logger().Error("can't parse context user_id")
-		return echo.NewHTTPError(
-			http.StatusInternalServerError,
-			response.ErrorMsgsByCode[http.StatusInternalServerError],
-		)
+		return response.Status(http.StatusInternalServerError)
 	}
 
 	day := c.QueryParam("day")
@@ -131,10 +310,7 @@ func (d *Delivery) GetMyEntries(c echo.Context) error {
 
 		if err != nil {
 			c.Logger().Errorf("invalid data format, should be YYYY-MM-DD: %v", err)
-			return echo.NewHTTPError(
-				http.StatusBadRequest,
-				response.ErrorMsgsByCode[http.StatusBadRequest],
-			)
+			return response.InvalidParam("day", "date")
 		}
 
 		entries, err = d.usecase.GetUserEntriesForDay(ctx, userID, date)
@@ -150,19 +326,478 @@ func (d *Delivery) GetMyEntries(c echo.Context) error {
 	return c.JSON(http.StatusOK, out)
 }
 
-func handleUsecaseError(err error) *echo.HTTPError {
+// ExportMyEntries godoc
+// @Summary      Выгрузить записи времени.
+// @Description  Выгрузить записи времени пользователя в CSV или XLSX. Даты и числа форматируются по локали: из параметра locale или заголовка Accept-Language.
+// @Tags     	 entries
+// @Produce  	text/csv
+// @Produce  	application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param    format query string true "Формат файла" Enums(csv, xlsx)
+// @Param    from query string false "Начало интервала в формате YYYY-MM-DD"
+// @Param    to query string false "Конец интервала в формате YYYY-MM-DD, включительно"
+// @Param    project query int false "Идентификатор проекта"
+// @Param    locale query string false "Локаль форматирования" Enums(ru, en)
+// @Success  200 {file} file "success export entries"
+// @Failure 500 {object} response.Error "internal server error"
+// @Failure 400 {object} response.Error "bad request"
+// @Router   /me/entries/export [get]
+func (d *Delivery) ExportMyEntries(c echo.Context) error {
+	// Контекст запроса: чтение из базы прерывается, если клиент отключился.
+	ctx := c.Request().Context()
+
+	userID, ok := c.Get("user_id").(int64)
+	if !ok {
+		c.Logger().Error("can't parse context user_id")
+		return response.Status(http.StatusInternalServerError)
+	}
+
+	filter, httpErr := parseExportFilter(c)
+	if httpErr != nil {
+		return httpErr
+	}
+	filter.UserID = userID
+
+	locale, err := export.NegotiateLocale(c.QueryParam("locale"), c.Request().Header.Get("Accept-Language"))
+	if err != nil {
+		c.Logger().Errorf("negotiate locale: %v", err)
+		return response.InvalidParam("locale", "oneof")
+	}
+
+	format := c.QueryParam("format")
+	writer, err := export.NewWriter(c.Response(), format, locale)
+	if err != nil {
+		c.Logger().Errorf("new export writer: %v", err)
+		return response.InvalidParam("format", "oneof")
+	}
+
+	c.Response().Header().Set(echo.HeaderContentType, export.ContentType(format))
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="entries.%s"`, format))
+	c.Response().WriteHeader(http.StatusOK)
+
+	// После начала выгрузки статус ответа уже отправлен, ошибки можно только залогировать.
+	err = writer.WriteRow("project", "entry", "time_start", "time_end", "duration_hours")
+	if err == nil {
+		err = d.usecase.ExportEntries(ctx, filter, func(e usecaseDto.ExportEntry) error {
+			return writer.WriteRow(e.ProjectName, e.Name, e.TimeStart, e.TimeEnd, e.Duration.Hours())
+		})
+	}
+	if err == nil {
+		err = writer.Close()
+	}
+	if err != nil {
+		c.Logger().Errorf("export entries: %v", err)
+	}
+
+	return nil
+}
+
+// ImportMyEntries godoc
+// @Summary      Импорт записей времени из CSV.
+// @Description  Импорт записей из CSV-файла по описанию колонок. Отсутствующие личные проекты создаются. Каждая строка проверяется как при создании записи; если есть ошибки, ничего не сохраняется и возвращается отчет по строкам. При dry_run=true только возвращается отчет.
+// @Tags     	 entries
+// @Accept	 multipart/form-data
+// @Produce  application/json
+// @Param    file formData file true "CSV-файл с заголовком"
+// @Param    mapping formData string true "Описание колонок, JSON ImportMappingIn"
+// @Param    dry_run query bool false "Пробный запуск"
+// @Success  200 {object} ImportResultOut "success import entries"
+// @Failure 500 {object} response.Error "internal server error"
+// @Failure 400 {object} response.Error "bad request"
+// @Failure 422 {object} ImportResultOut "rows with errors, nothing is imported"
+// @Router   /me/entries/import [post]
+func (d *Delivery) ImportMyEntries(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, ok := c.Get("user_id").(int64)
+	if !ok {
+		c.Logger().Error("can't parse context user_id")
+		return response.Status(http.StatusInternalServerError)
+	}
+
+	var mapping ImportMappingIn
+	if err := json.Unmarshal([]byte(c.FormValue("mapping")), &mapping); err != nil {
+		c.Logger().Errorf("unmarshal mapping: %v", err)
+		return response.Status(http.StatusUnprocessableEntity)
+	}
+
+	if ok, err := validator.IsRequestValid(&mapping); !ok {
+		c.Logger().Errorf("validation: %v", err)
+		return response.ValidationError(err)
+	}
+
+	spec, err := convertToImportSpec(mapping)
+	if err != nil {
+		c.Logger().Errorf("import spec: %v", err)
+		return response.Status(http.StatusBadRequest)
+	}
+
+	dryRun, err := parseDryRun(c)
+	if err != nil {
+		c.Logger().Errorf("parse bool: %v", err)
+		return response.InvalidParam("dry_run", "bool")
+	}
+
+	file, httpErr := openImportFile(c)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	result, err := d.usecase.ImportEntries(ctx, userID, file, spec, dryRun)
+	if err != nil {
+		c.Logger().Errorf("usecase: %v", err)
+		return handleUsecaseError(err)
+	}
+
+	out := convertFromUsecaseImportResult(result)
+
+	if !result.DryRun && len(result.Errors) > 0 {
+		return c.JSON(http.StatusUnprocessableEntity, out)
+	}
+
+	return c.JSON(http.StatusOK, out)
+}
+
+// ImportFromTracker godoc
+// @Summary      Импорт записей времени из Toggl Track или Clockify.
+// @Description  Импорт выгрузки другого трекера: toggl — детальный отчет CSV или JSON, clockify — детальный отчет CSV. Клиенты, проекты, теги, описания и признак оплачиваемости переносятся в проекты и записи, отсутствующие личные проекты создаются. Повторный импорт не создает дублей. Строки, которые нельзя импортировать, пропускаются и возвращаются в skipped, остальные сохраняются. При dry_run=true только возвращается отчет.
+// @Tags     	 entries
+// @Accept	 multipart/form-data
+// @Produce  application/json
+// @Param    source path string true "Трекер" Enums(toggl, clockify)
+// @Param    file formData file true "Файл выгрузки"
+// @Param    timezone query string false "Часовой пояс времени без смещения, по умолчанию UTC"
+// @Param    dry_run query bool false "Пробный запуск"
+// @Success  200 {object} ImportResultOut "success import entries"
+// @Failure 500 {object} response.Error "internal server error"
+// @Failure 400 {object} response.Error "bad request"
+// @Router   /me/entries/import/{source} [post]
+func (d *Delivery) ImportFromTracker(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, ok := c.Get("user_id").(int64)
+	if !ok {
+		c.Logger().Error("can't parse context user_id")
+		return response.Status(http.StatusInternalServerError)
+	}
+
+	location := time.UTC
+	if timezone := c.QueryParam("timezone"); timezone != "" {
+		var err error
+		location, err = time.LoadLocation(timezone)
+		if err != nil {
+			c.Logger().Errorf("load location: %v", err)
+			return response.InvalidParam("timezone", "timezone")
+		}
+	}
+
+	dryRun, err := parseDryRun(c)
+	if err != nil {
+		c.Logger().Errorf("parse bool: %v", err)
+		return response.InvalidParam("dry_run", "bool")
+	}
+
+	file, httpErr := openImportFile(c)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	result, err := d.usecase.ImportFromTracker(ctx, userID, c.Param("source"), file, location, dryRun)
+	if err != nil {
+		c.Logger().Errorf("usecase: %v", err)
+		return handleUsecaseError(err)
+	}
+
+	return c.JSON(http.StatusOK, convertFromUsecaseImportResult(result))
+}
+
+// ImportFromCalendar godoc
+// @Summary      Импорт событий календаря.
+// @Description  Импорт событий из .ics-файла как записей времени. Проект события выбирается по правилам mapping (календарь, категория, подстрока заголовка), повторяющиеся события разворачиваются по RRULE с учетом EXDATE и измененных вхождений. События на весь день, отмененные и отклоненные пропускаются. Повторный импорт не создает дублей: вхождение определяется по UID события. По умолчанию импортируются события до текущего момента.
+// @Tags     	 entries
+// @Accept	 multipart/form-data
+// @Produce  application/json
+// @Param    file formData file true "Файл .ics"
+// @Param    mapping formData string false "Правила, JSON CalendarMappingIn"
+// @Param    from query string false "Начало периода, YYYY-MM-DD"
+// @Param    to query string false "Окончание периода включительно, YYYY-MM-DD"
+// @Param    dry_run query bool false "Пробный запуск"
+// @Success  200 {object} ImportResultOut "success import entries"
+// @Failure 500 {object} response.Error "internal server error"
+// @Failure 400 {object} response.Error "bad request"
+// @Failure 422 {object} response.Error "unprocessable entity"
+// @Router   /me/entries/import/ical [post]
+func (d *Delivery) ImportFromCalendar(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, ok := c.Get("user_id").(int64)
+	if !ok {
+		c.Logger().Error("can't parse context user_id")
+		return response.Status(http.StatusInternalServerError)
+	}
+
+	var mapping CalendarMappingIn
+	if mappingStr := c.FormValue("mapping"); mappingStr != "" {
+		if err := json.Unmarshal([]byte(mappingStr), &mapping); err != nil {
+			c.Logger().Errorf("unmarshal mapping: %v", err)
+			return response.Status(http.StatusUnprocessableEntity)
+		}
+	}
+
+	if ok, err := validator.IsRequestValid(&mapping); !ok {
+		c.Logger().Errorf("validation: %v", err)
+		return response.ValidationError(err)
+	}
+
+	spec, err := convertToCalendarImportSpec(mapping)
+	if err != nil {
+		c.Logger().Errorf("calendar import spec: %v", err)
+		return response.Status(http.StatusBadRequest)
+	}
+
+	// Границы периода — даты в часовом поясе импорта, окончание включается целиком.
+	filter, httpErr := parseExportFilter(c)
+	if httpErr != nil {
+		return httpErr
+	}
+	if !filter.From.IsZero() {
+		spec.From = time.Date(filter.From.Year(), filter.From.Month(), filter.From.Day(), 0, 0, 0, 0, spec.Location)
+	}
+	if !filter.To.IsZero() {
+		spec.To = time.Date(filter.To.Year(), filter.To.Month(), filter.To.Day(), 0, 0, 0, 0, spec.Location)
+	}
+
+	dryRun, err := parseDryRun(c)
+	if err != nil {
+		c.Logger().Errorf("parse bool: %v", err)
+		return response.InvalidParam("dry_run", "bool")
+	}
+
+	file, httpErr := openImportFile(c)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	result, err := d.usecase.ImportFromCalendar(ctx, userID, file, spec, dryRun)
+	if err != nil {
+		c.Logger().Errorf("usecase: %v", err)
+		return handleUsecaseError(err)
+	}
+
+	return c.JSON(http.StatusOK, convertFromUsecaseImportResult(result))
+}
+
+func convertToCalendarImportSpec(in CalendarMappingIn) (usecaseDto.CalendarImportSpec, error) {
+	spec := usecaseDto.CalendarImportSpec{
+		Rules:          make([]usecaseDto.CalendarRule, 0, len(in.Rules)),
+		DefaultProject: in.DefaultProject,
+		AttendeeEmail:  in.AttendeeEmail,
+		Location:       time.UTC,
+	}
+
+	for _, rule := range in.Rules {
+		spec.Rules = append(spec.Rules, usecaseDto.CalendarRule{
+			Calendar:        rule.Calendar,
+			Category:        rule.Category,
+			SummaryContains: rule.SummaryContains,
+			Project:         rule.Project,
+		})
+	}
+
+	if in.Timezone != "" {
+		location, err := time.LoadLocation(in.Timezone)
+		if err != nil {
+			return usecaseDto.CalendarImportSpec{}, fmt.Errorf("load location: %v", err)
+		}
+		spec.Location = location
+	}
+
+	return spec, nil
+}
+
+func parseDryRun(c echo.Context) (bool, error) {
+	dryRunStr := c.QueryParam("dry_run")
+	if dryRunStr == "" {
+		return false, nil
+	}
+
+	return strconv.ParseBool(dryRunStr)
+}
+
+// openImportFile открывает файл импорта из поля file, ошибка уже готова для ответа.
+func openImportFile(c echo.Context) (multipart.File, *response.Error) {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.Logger().Errorf("form file: %v", err)
+		return nil, response.InvalidParam("file", "required")
+	}
+
+	if fileHeader.Size > maxImportFileSize {
+		c.Logger().Errorf("import file is too large: %d bytes", fileHeader.Size)
+		return nil, response.InvalidParam("file", "max")
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.Logger().Errorf("open form file: %v", err)
+		return nil, response.Status(http.StatusInternalServerError)
+	}
+
+	return file, nil
+}
+
+func convertToImportSpec(in ImportMappingIn) (usecaseDto.ImportSpec, error) {
+	spec := usecaseDto.ImportSpec{
+		ProjectColumn:   in.Columns.Project,
+		NameColumn:      in.Columns.Name,
+		TimeStartColumn: in.Columns.TimeStart,
+		TimeEndColumn:   in.Columns.TimeEnd,
+		DurationColumn:  in.Columns.Duration,
+		TimeLayout:      in.TimeLayout,
+	}
+
+	if in.Timezone != "" {
+		location, err := time.LoadLocation(in.Timezone)
+		if err != nil {
+			return usecaseDto.ImportSpec{}, fmt.Errorf("load location: %v", err)
+		}
+		spec.Location = location
+	}
+
+	if in.Delimiter != "" {
+		spec.Delimiter = []rune(in.Delimiter)[0]
+	}
+
+	return spec, nil
+}
+
+func convertFromUsecaseImportResult(result usecaseDto.ImportResult) ImportResultOut {
+	out := ImportResultOut{
+		DryRun:          result.DryRun,
+		TotalRows:       result.TotalRows,
+		ValidRows:       result.ValidRows,
+		ImportedRows:    result.ImportedRows,
+		CreatedProjects: result.CreatedProjects,
+		Errors:          make([]ImportRowErrorOut, 0, len(result.Errors)),
+		Skipped:         make([]ImportRowErrorOut, 0, len(result.Skipped)),
+	}
+
+	for _, e := range result.Errors {
+		out.Errors = append(out.Errors, ImportRowErrorOut{
+			Row:     e.Row,
+			Message: e.Message,
+		})
+	}
+
+	for _, e := range result.Skipped {
+		out.Skipped = append(out.Skipped, ImportRowErrorOut{
+			Row:     e.Row,
+			Message: e.Message,
+		})
+	}
+
+	return out
+}
+
+// parseExportFilter читает период и проект из параметров запроса, ошибка уже готова для ответа.
+func parseExportFilter(c echo.Context) (usecaseDto.ExportFilter, *response.Error) {
+	var filter usecaseDto.ExportFilter
+
+	if from := c.QueryParam("from"); from != "" {
+		date, err := time.Parse("2006-01-02", from)
+		if err != nil {
+			c.Logger().Errorf("invalid from, should be YYYY-MM-DD: %v", err)
+			return usecaseDto.ExportFilter{}, response.InvalidParam("from", "date")
+		}
+		filter.From = date
+	}
+
+	if to := c.QueryParam("to"); to != "" {
+		date, err := time.Parse("2006-01-02", to)
+		if err != nil {
+			c.Logger().Errorf("invalid to, should be YYYY-MM-DD: %v", err)
+			return usecaseDto.ExportFilter{}, response.InvalidParam("to", "date")
+		}
+		// Последний день интервала включается целиком.
+		filter.To = date.AddDate(0, 0, 1)
+	}
+
+	if project := c.QueryParam("project"); project != "" {
+		projectID, err := strconv.ParseInt(project, 10, 64)
+		if err != nil {
+			c.Logger().Errorf("parse int: %v", err)
+			return usecaseDto.ExportFilter{}, response.InvalidParam("project", "int")
+		}
+		filter.ProjectID = projectID
+	}
+
+	return filter, nil
+}
+
+func handleUsecaseError(err error) *response.Error {
+	// Запрос не уложился в таймаут или был отменен.
+	if problem := response.ContextError(err); problem != nil {
+		return problem
+	}
+
 	// Не нашли запись времени.
 	if errors.Is(err, usecaseDto.ErrEntryNotFound) {
-		return echo.NewHTTPError(
-			http.StatusNotFound,
-			fmt.Sprintf("%s: %s", response.ErrorMsgsByCode[http.StatusNotFound], "entry"))
+		return response.NotFound("entry")
+	}
+	if errors.Is(err, usecaseDto.ErrProjectNotFound) {
+		return response.NotFound("project")
+	}
+	if errors.Is(err, usecaseDto.ErrForbidden) {
+		return response.Status(http.StatusForbidden)
+	}
+	// Неделя записи утверждена в табеле.
+	if errors.Is(err, usecaseDto.ErrEntryReadOnly) {
+		return response.NewError(
+			http.StatusConflict,
+			"entry_read_only",
+			fmt.Sprintf("%s: %v", response.ErrorMsgsByCode[http.StatusConflict], err))
+	}
+	// Файл импорта не соответствует описанию колонок.
+	if errors.Is(err, usecaseDto.ErrInvalidImportSpec) {
+		return response.NewError(
+			http.StatusBadRequest,
+			"invalid_import_spec",
+			fmt.Sprintf("%s: %v", response.ErrorMsgsByCode[http.StatusBadRequest], err))
+	}
+	if errors.Is(err, usecaseDto.ErrTooManyImportRows) {
+		return response.NewError(
+			http.StatusBadRequest,
+			"too_many_import_rows",
+			fmt.Sprintf("%s: %v", response.ErrorMsgsByCode[http.StatusBadRequest], err))
+	}
+	// Проект записи в корзине.
+	if errors.Is(err, usecaseDto.ErrProjectDeleted) {
+		return response.NewError(
+			http.StatusConflict,
+			"project_deleted",
+			fmt.Sprintf("%s: %v", response.ErrorMsgsByCode[http.StatusConflict], err))
+	}
+	// Запись попадает в заблокированный период.
+	if errors.Is(err, usecaseDto.ErrPeriodLocked) {
+		return response.NewError(
+			http.StatusLocked,
+			"period_locked",
+			fmt.Sprintf("%s: %v", response.ErrorMsgsByCode[http.StatusLocked], err))
 	}
 
 	// По дефолту пятисотим.
-	return echo.NewHTTPError(
-		http.StatusInternalServerError,
-		response.ErrorMsgsByCode[http.StatusInternalServerError],
-	)
+	return response.Status(http.StatusInternalServerError)
 }
 
 func convertFromUsecaseEntries(entries []usecaseDto.Entry) []EntryOut {
@@ -175,6 +810,8 @@ func convertFromUsecaseEntries(entries []usecaseDto.Entry) []EntryOut {
 			Name:        entry.Name,
 			TimeStart:   entry.TimeStart,
 			TimeEnd:     entry.TimeEnd,
+			Tags:        entry.Tags,
+			Billable:    entry.Billable,
 		}
 
 		out = append(out, e)
@@ -0,0 +1,67 @@
+package delivery
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/response"
+)
+
+// fakeUsecase не реализует ни одного метода: запросы с неверными параметрами до него не доходят.
+type fakeUsecase struct {
+	usecase
+}
+
+func TestExportFilterInvalidParams(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		target string
+		field  string
+		rule   string
+	}{
+		{"export from", http.MethodGet, "/me/entries/export?from=2024-13-01", "from", "date"},
+		{"export to", http.MethodGet, "/me/entries/export?from=2024-03-01&to=01.03.2024", "to", "date"},
+		{"export project", http.MethodGet, "/me/entries/export?project=abc", "project", "int"},
+		{"calendar from", http.MethodPost, "/me/entries/import/ical?from=yesterday", "from", "date"},
+		{"calendar to", http.MethodPost, "/me/entries/import/ical?to=2024-02-30", "to", "date"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			e.HTTPErrorHandler = response.ErrorHandler
+			e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+				return func(c echo.Context) error {
+					c.Set("user_id", int64(1))
+					return next(c)
+				}
+			})
+			RegisterHandlers(e, &fakeUsecase{}, e.Logger)
+
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.target, nil))
+
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("status %d, want 400", rec.Code)
+			}
+
+			var problem response.Error
+			if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil {
+				t.Fatalf("unmarshal problem: %v", err)
+			}
+			if problem.Code != response.CodeInvalidParameter {
+				t.Errorf("code %q, want %q", problem.Code, response.CodeInvalidParameter)
+			}
+
+			want := response.FieldError{Field: tt.field, Rule: tt.rule}
+			if len(problem.Details) != 1 || problem.Details[0] != want {
+				t.Errorf("details %+v, want [%+v]", problem.Details, want)
+			}
+		})
+	}
+}
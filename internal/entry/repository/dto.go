@@ -16,8 +16,52 @@ type Entry struct {
 	Name      string    `db:"name"`
 	TimeStart time.Time `db:"time_start"`
 	TimeEnd   time.Time `db:"time_end"`
+	Tags      []string  `db:"tags"`
+	Billable  bool      `db:"billable"`
 }
 
 func (Entry) TableName() string {
 	return "entry"
 }
+
+// ExportFilter условия выгрузки записей пользователя.
+type ExportFilter struct {
+	UserID int64
+	// 0 — все проекты.
+	ProjectID int64
+	// Интервал начала записей [From, To), нулевая граница не ограничивает выборку.
+	From time.Time
+	To   time.Time
+}
+
+type ExportEntry struct {
+	ProjectName string    `db:"project_name"`
+	Name        string    `db:"name"`
+	TimeStart   time.Time `db:"time_start"`
+	TimeEnd     time.Time `db:"time_end"`
+}
+
+// ImportEntry запись для импорта. Если ProjectID не задан, проект ProjectName создается при импорте.
+type ImportEntry struct {
+	ProjectID   int64
+	ProjectName string
+	// Клиент создаваемого проекта.
+	ProjectClient string
+	Name          string
+	TimeStart     time.Time
+	TimeEnd       time.Time
+	Tags          []string
+	Billable      bool
+	// Источник и идентификатор записи во внешнем трекере, пустые для собственного формата.
+	// Запись с уже импортированными источником и идентификатором пропускается.
+	ExternalSource string
+	ExternalID     string
+}
+
+// ImportedEntries результат импорта записей.
+type ImportedEntries struct {
+	// Идентификаторы созданных проектов по названию.
+	ProjectIDs map[string]int64
+	// Идентификаторы записей в порядке импорта, 0 для пропущенной уже импортированной записи.
+	EntryIDs []int64
+}
@@ -2,12 +2,15 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/lib/pq"
+
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/transaction"
 )
 
 var (
@@ -29,44 +32,55 @@ func NewRepository(db *sqlx.DB) *Repository {
 	}
 }
 
-func (r *Repository) CreateEntry(_ context.Context, entry Entry) (int64, error) {
+// Close закрывает пул соединений с базой.
+func (r *Repository) Close() error {
+	return r.close()
+}
+
+func (r *Repository) CreateEntry(ctx context.Context, entry Entry) (int64, error) {
 	query := `INSERT INTO entries
 				(
 					user_id,
 					project_id,
 					name,
 					time_start,
-					time_end
-				) VALUES ($1, $2, $3, $4, $5) RETURNING id;`
+					time_end,
+					tags,
+					billable
+				) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id;`
 
 	var id int64
-	err := r.db.QueryRow(
+	err := transaction.GetExecutor(ctx, r.db).QueryRowContext(ctx,
 		query,
 		entry.UserID,
 		entry.ProjectID,
 		entry.Name,
 		entry.TimeStart,
 		entry.TimeEnd,
+		pq.Array(nonNilTags(entry.Tags)),
+		entry.Billable,
 	).Scan(&id)
 
 	if err != nil {
-		return 0, fmt.Errorf("exec: %v", err)
+		return 0, fmt.Errorf("exec: %w", err)
 	}
 
 	return id, nil
 }
 
-func (r *Repository) GetUserEntries(_ context.Context, userID int64) ([]Entry, error) {
-	rows, err := r.db.Query(
+func (r *Repository) GetUserEntries(ctx context.Context, userID int64) ([]Entry, error) {
+	rows, err := r.db.QueryContext(ctx,
 		`SELECT 
 			id,
 			user_id,
 			project_id,
 			name,
 			time_start,
-			time_end
+			time_end,
+			tags,
+			billable
 		FROM entries
-		WHERE user_id = $1`, userID)
+		WHERE user_id = $1 AND deleted_at IS NULL`, userID)
 
 	if err != nil {
 		return nil, fmt.Errorf("query: %w", err)
@@ -86,6 +100,8 @@ func (r *Repository) GetUserEntries(_ context.Context, userID int64) ([]Entry, e
 			&entry.Name,
 			&entry.TimeStart,
 			&entry.TimeEnd,
+			pq.Array(&entry.Tags),
+			&entry.Billable,
 		); err != nil {
 			return nil, fmt.Errorf("scan: %w", rows.Err())
 		}
@@ -105,20 +121,22 @@ func (r *Repository) GetUserEntries(_ context.Context, userID int64) ([]Entry, e
 }
 
 func (r *Repository) GetUserEntriesForInterval(
-	_ context.Context,
+	ctx context.Context,
 	userID int64,
 	start time.Time,
 	end time.Time) ([]Entry, error) {
-	rows, err := r.db.Query(
+	rows, err := r.db.QueryContext(ctx,
 		`SELECT 
 			id,
 			user_id,
 			project_id,
 			name,
 			time_start,
-			time_end
+			time_end,
+			tags,
+			billable
 		FROM entries
-		WHERE user_id = $1 AND time_start BETWEEN $2 AND $3`, userID, start, end)
+		WHERE user_id = $1 AND deleted_at IS NULL AND time_start BETWEEN $2 AND $3`, userID, start, end)
 
 	if err != nil {
 		return nil, fmt.Errorf("query: %w", err)
@@ -138,6 +156,8 @@ func (r *Repository) GetUserEntriesForInterval(
 			&entry.Name,
 			&entry.TimeStart,
 			&entry.TimeEnd,
+			pq.Array(&entry.Tags),
+			&entry.Billable,
 		); err != nil {
 			return nil, fmt.Errorf("scan: %w", rows.Err())
 		}
@@ -157,20 +177,22 @@ func (r *Repository) GetUserEntriesForInterval(
 }
 
 func (r *Repository) GetProjectEntries(
-	_ context.Context,
+	ctx context.Context,
 	userID int64,
 	projectID int64,
 ) ([]Entry, error) {
-	rows, err := r.db.Query(
+	rows, err := r.db.QueryContext(ctx,
 		`SELECT 
 			id,
 			user_id,
 			project_id,
 			name,
 			time_start,
-			time_end
+			time_end,
+			tags,
+			billable
 		FROM entries
-		WHERE user_id = $1 AND project_id = $2`,
+		WHERE user_id = $1 AND project_id = $2 AND deleted_at IS NULL`,
 		userID, projectID)
 
 	if err != nil {
@@ -191,6 +213,8 @@ func (r *Repository) GetProjectEntries(
 			&entry.Name,
 			&entry.TimeStart,
 			&entry.TimeEnd,
+			pq.Array(&entry.Tags),
+			&entry.Billable,
 		); err != nil {
 			return nil, fmt.Errorf("scan: %w", rows.Err())
 		}
@@ -210,21 +234,23 @@ func (r *Repository) GetProjectEntries(
 }
 
 func (r *Repository) GetProjectEntriesForInterval(
-	_ context.Context,
+	ctx context.Context,
 	userID int64,
 	projectID int64,
 	start time.Time,
 	end time.Time) ([]Entry, error) {
-	rows, err := r.db.Query(
+	rows, err := r.db.QueryContext(ctx,
 		`SELECT 
 			id,
 			user_id,
 			project_id,
 			name,
 			time_start,
-			time_end
+			time_end,
+			tags,
+			billable
 		FROM entries
-		WHERE user_id = $1 AND project_id = $2 AND time_start BETWEEN $3 AND $4`,
+		WHERE user_id = $1 AND project_id = $2 AND deleted_at IS NULL AND time_start BETWEEN $3 AND $4`,
 		userID, projectID, start, end)
 
 	if err != nil {
@@ -245,6 +271,8 @@ func (r *Repository) GetProjectEntriesForInterval(
 			&entry.Name,
 			&entry.TimeStart,
 			&entry.TimeEnd,
+			pq.Array(&entry.Tags),
+			&entry.Billable,
 		); err != nil {
 			return nil, fmt.Errorf("scan: %w", rows.Err())
 		}
@@ -263,8 +291,8 @@ func (r *Repository) GetProjectEntriesForInterval(
 	return entries, nil
 }
 
-func (r *Repository) GetProjectsInfo(_ context.Context, projectIDs []int64) ([]ProjectInfo, error) {
-	rows, err := r.db.Query(
+func (r *Repository) GetProjectsInfo(ctx context.Context, projectIDs []int64) ([]ProjectInfo, error) {
+	rows, err := r.db.QueryContext(ctx,
 		`SELECT 
 			id,
 			name
@@ -302,3 +330,347 @@ func (r *Repository) GetProjectsInfo(_ context.Context, projectIDs []int64) ([]P
 
 	return projectInfos, nil
 }
+
+func (r *Repository) GetEntry(ctx context.Context, entryID int64) (Entry, error) {
+	var entry Entry
+	err := r.db.QueryRowContext(ctx,
+		`SELECT
+			id,
+			user_id,
+			project_id,
+			name,
+			time_start,
+			time_end,
+			tags,
+			billable
+		FROM entries
+		WHERE id = $1 AND deleted_at IS NULL`, entryID).Scan(
+		&entry.ID,
+		&entry.UserID,
+		&entry.ProjectID,
+		&entry.Name,
+		&entry.TimeStart,
+		&entry.TimeEnd,
+		pq.Array(&entry.Tags),
+		&entry.Billable,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Entry{}, ErrEntryNotFound
+		}
+
+		return Entry{}, fmt.Errorf("scan: %w", err)
+	}
+
+	return entry, nil
+}
+
+func (r *Repository) UpdateEntry(ctx context.Context, entry Entry) error {
+	res, err := transaction.GetExecutor(ctx, r.db).ExecContext(ctx,
+		`UPDATE entries
+		SET project_id = $3,
+			name = $4,
+			time_start = $5,
+			time_end = $6,
+			tags = $7,
+			billable = $8,
+			updated_at = now()
+		WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL`,
+		entry.ID,
+		entry.UserID,
+		entry.ProjectID,
+		entry.Name,
+		entry.TimeStart,
+		entry.TimeEnd,
+		pq.Array(nonNilTags(entry.Tags)),
+		entry.Billable,
+	)
+
+	if err != nil {
+		return fmt.Errorf("exec: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("rows affected: %w", err)
+	}
+
+	if affected == 0 {
+		return ErrEntryNotFound
+	}
+
+	return nil
+}
+
+// ImportEntries в одной транзакции создает недостающие личные проекты пользователя и записи.
+// Возвращает идентификаторы созданных проектов и записей.
+func (r *Repository) ImportEntries(ctx context.Context, userID int64, entries []ImportEntry) (ImportedEntries, error) {
+	tx, err := transaction.Begin(ctx, r.db)
+	if err != nil {
+		return ImportedEntries{}, fmt.Errorf("begin tx: %w", err)
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	createdProjects := make(map[string]int64)
+	for _, entry := range entries {
+		if entry.ProjectID != 0 {
+			continue
+		}
+		if _, ok := createdProjects[entry.ProjectName]; ok {
+			continue
+		}
+
+		var projectID int64
+		err = tx.QueryRowContext(ctx,
+			`INSERT INTO projects (user_id, name, client) VALUES ($1, $2, NULLIF($3, '')) RETURNING id;`,
+			userID, entry.ProjectName, entry.ProjectClient).Scan(&projectID)
+		if err != nil {
+			return ImportedEntries{}, fmt.Errorf("insert project: %w", err)
+		}
+
+		createdProjects[entry.ProjectName] = projectID
+	}
+
+	stmt, err := tx.PrepareContext(ctx,
+		`INSERT INTO entries
+				(
+					user_id,
+					project_id,
+					name,
+					time_start,
+					time_end,
+					tags,
+					billable,
+					external_source,
+					external_id
+				) VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''), NULLIF($9, ''))
+				ON CONFLICT (user_id, external_source, external_id) WHERE external_source IS NOT NULL
+				DO NOTHING
+				RETURNING id;`)
+	if err != nil {
+		return ImportedEntries{}, fmt.Errorf("prepare context: %w", err)
+	}
+
+	defer func() {
+		_ = stmt.Close()
+	}()
+
+	entryIDs := make([]int64, len(entries))
+	for i, entry := range entries {
+		projectID := entry.ProjectID
+		if projectID == 0 {
+			projectID = createdProjects[entry.ProjectName]
+		}
+
+		err = stmt.QueryRowContext(ctx,
+			userID,
+			projectID,
+			entry.Name,
+			// Колонки без часового пояса: время с другим смещением сохранилось бы как локальное.
+			entry.TimeStart.UTC(),
+			entry.TimeEnd.UTC(),
+			pq.Array(nonNilTags(entry.Tags)),
+			entry.Billable,
+			entry.ExternalSource,
+			entry.ExternalID,
+		).Scan(&entryIDs[i])
+		// Уже импортированная запись не вставляется и не возвращает идентификатор.
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return ImportedEntries{}, fmt.Errorf("insert entry: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return ImportedEntries{}, fmt.Errorf("commit: %w", err)
+	}
+
+	return ImportedEntries{ProjectIDs: createdProjects, EntryIDs: entryIDs}, nil
+}
+
+// GetImportedExternalIDs возвращает те из externalIDs, записи с которыми уже импортированы пользователем
+// из источника source, включая удаленные.
+func (r *Repository) GetImportedExternalIDs(
+	ctx context.Context,
+	userID int64,
+	source string,
+	externalIDs []string,
+) (map[string]struct{}, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT external_id
+		FROM entries
+		WHERE user_id = $1 AND external_source = $2 AND external_id = ANY($3)`,
+		userID, source, pq.Array(externalIDs))
+
+	if err != nil {
+		return nil, fmt.Errorf("query context: %w", err)
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	imported := make(map[string]struct{})
+	for rows.Next() {
+		var externalID string
+		if err = rows.Scan(&externalID); err != nil {
+			return nil, fmt.Errorf("scan: %w", err)
+		}
+
+		imported[externalID] = struct{}{}
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("rows err: %w", rows.Err())
+	}
+
+	return imported, nil
+}
+
+// StreamUserEntries построчно читает записи пользователя с началом в [From, To) и передает их в fn,
+// нулевая граница интервала не ограничивает выборку. Выборка не загружается в память целиком,
+// ошибка fn прерывает чтение.
+func (r *Repository) StreamUserEntries(ctx context.Context, filter ExportFilter, fn func(ExportEntry) error) error {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT
+			p.name,
+			e.name,
+			e.time_start,
+			e.time_end
+		FROM entries e
+		JOIN projects p ON p.id = e.project_id
+		WHERE e.user_id = $1
+		  AND e.deleted_at IS NULL
+		  AND ($2::timestamp IS NULL OR e.time_start >= $2)
+		  AND ($3::timestamp IS NULL OR e.time_start < $3)
+		  AND ($4 = 0 OR e.project_id = $4)
+		ORDER BY e.time_start, e.id`,
+		filter.UserID,
+		sql.NullTime{Time: filter.From, Valid: !filter.From.IsZero()},
+		sql.NullTime{Time: filter.To, Valid: !filter.To.IsZero()},
+		filter.ProjectID)
+
+	if err != nil {
+		return fmt.Errorf("query context: %w", err)
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	for rows.Next() {
+		var entry ExportEntry
+		if err = rows.Scan(
+			&entry.ProjectName,
+			&entry.Name,
+			&entry.TimeStart,
+			&entry.TimeEnd,
+		); err != nil {
+			return fmt.Errorf("scan: %w", err)
+		}
+
+		if err = fn(entry); err != nil {
+			return err
+		}
+	}
+
+	if rows.Err() != nil {
+		return fmt.Errorf("rows err: %w", rows.Err())
+	}
+
+	return nil
+}
+
+// DeleteEntry переносит запись в корзину.
+func (r *Repository) DeleteEntry(ctx context.Context, entryID int64, userID int64) error {
+	res, err := transaction.GetExecutor(ctx, r.db).ExecContext(ctx,
+		`UPDATE entries
+		SET deleted_at = now()
+		WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL`, entryID, userID)
+
+	if err != nil {
+		return fmt.Errorf("exec: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("rows affected: %w", err)
+	}
+
+	if affected == 0 {
+		return ErrEntryNotFound
+	}
+
+	return nil
+}
+
+// GetDeletedEntry возвращает запись из корзины.
+func (r *Repository) GetDeletedEntry(ctx context.Context, entryID int64) (Entry, error) {
+	var entry Entry
+	err := r.db.QueryRowContext(ctx,
+		`SELECT
+			id,
+			user_id,
+			project_id,
+			name,
+			time_start,
+			time_end,
+			tags,
+			billable
+		FROM entries
+		WHERE id = $1 AND deleted_at IS NOT NULL`, entryID).Scan(
+		&entry.ID,
+		&entry.UserID,
+		&entry.ProjectID,
+		&entry.Name,
+		&entry.TimeStart,
+		&entry.TimeEnd,
+		pq.Array(&entry.Tags),
+		&entry.Billable,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Entry{}, ErrEntryNotFound
+		}
+
+		return Entry{}, fmt.Errorf("scan: %w", err)
+	}
+
+	return entry, nil
+}
+
+// RestoreEntry возвращает запись из корзины.
+func (r *Repository) RestoreEntry(ctx context.Context, entryID int64, userID int64) error {
+	res, err := transaction.GetExecutor(ctx, r.db).ExecContext(ctx,
+		`UPDATE entries
+		SET deleted_at = NULL, updated_at = now()
+		WHERE id = $1 AND user_id = $2 AND deleted_at IS NOT NULL`, entryID, userID)
+
+	if err != nil {
+		return fmt.Errorf("exec: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("rows affected: %w", err)
+	}
+
+	if affected == 0 {
+		return ErrEntryNotFound
+	}
+
+	return nil
+}
+
+// nonNilTags заменяет nil на пустой срез: pq.Array(nil) передает NULL, а колонка tags NOT NULL.
+func nonNilTags(tags []string) []string {
+	if tags == nil {
+		return []string{}
+	}
+
+	return tags
+}
@@ -11,7 +11,94 @@ type Entry struct {
 	Name      string
 	TimeStart time.Time
 	TimeEnd   time.Time
+	Tags      []string
+	Billable  bool
 
 	// Поля только для чтения.
 	ProjectName string
 }
+
+// WriteOptions параметры создания, изменения и удаления записи.
+type WriteOptions struct {
+	// Причина изменения записи в заблокированном периоде.
+	// Учитывается только для admin и owner пространства, изменение попадает в журнал.
+	LockOverrideReason string
+}
+
+// ExportFilter условия выгрузки записей пользователя.
+type ExportFilter struct {
+	UserID int64
+	// 0 — все проекты.
+	ProjectID int64
+	// Интервал начала записей [From, To), нулевая граница не ограничивает выборку.
+	From time.Time
+	To   time.Time
+}
+
+type ExportEntry struct {
+	ProjectName string
+	Name        string
+	TimeStart   time.Time
+	TimeEnd     time.Time
+	Duration    time.Duration
+}
+
+// ImportSpec описание CSV-файла для импорта: названия колонок из заголовка и формат значений.
+type ImportSpec struct {
+	ProjectColumn   string
+	NameColumn      string
+	TimeStartColumn string
+	TimeEndColumn   string
+	// Колонка длительности используется, если не задана колонка окончания.
+	// Значение в формате Go (1h30m) или в часах (1.5 или 1,5).
+	DurationColumn string
+	// Раскладка времени в формате Go, по умолчанию RFC3339.
+	TimeLayout string
+	// Часовой пояс для времени без смещения, по умолчанию UTC.
+	Location  *time.Location
+	Delimiter rune
+}
+
+type ImportRowError struct {
+	// Номер строки файла, заголовок — строка 1.
+	Row     int
+	Message string
+}
+
+type ImportResult struct {
+	DryRun    bool
+	TotalRows int
+	ValidRows int
+	// Сколько записей сохранено, 0 при пробном запуске или ошибках.
+	ImportedRows int
+	// Проекты, которые созданы (или будут созданы при пробном запуске).
+	CreatedProjects []string
+	Errors          []ImportRowError
+	// Строки выгрузки другого трекера, которые не импортированы: дубли, запущенные таймеры,
+	// записи без проекта и нарушающие правила создания записей.
+	Skipped []ImportRowError
+}
+
+// CalendarImportSpec правила импорта событий календаря.
+type CalendarImportSpec struct {
+	// Применяется первое подходящее правило.
+	Rules []CalendarRule
+	// Проект для событий без подходящего правила; пустой — такие события пропускаются.
+	DefaultProject string
+	// Участник, отклоненные которым события пропускаются, по умолчанию email аккаунта.
+	AttendeeEmail string
+	// Импортируются вхождения с началом в [From, To). Нулевой From не ограничивает выборку,
+	// нулевой To — текущий момент, чтобы будущие встречи не попадали в учет времени.
+	From time.Time
+	To   time.Time
+	// Часовой пояс времени без смещения, по умолчанию UTC.
+	Location *time.Location
+}
+
+// CalendarRule условие выбора проекта события, пустое условие выполняется для любого события.
+type CalendarRule struct {
+	Calendar        string
+	Category        string
+	SummaryContains string
+	Project         string
+}
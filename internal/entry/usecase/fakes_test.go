@@ -0,0 +1,205 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	auditUC "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/audit/usecase"
+	repo "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/entry/repository"
+	periodLockRepoDto "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/periodlock/repository"
+	projectRepoDto "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/project/repository"
+)
+
+// fakeRepository хранит импортированные записи в памяти. Методы, которые тесты не вызывают,
+// достаются от nil-интерфейса и паникуют.
+type fakeRepository struct {
+	repository
+
+	mu       sync.Mutex
+	entries  []repo.Entry
+	deleted  []int64
+	imported []repo.ImportEntry
+}
+
+func (r *fakeRepository) CreateEntry(_ context.Context, entry repo.Entry) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry.ID = int64(len(r.entries) + 1)
+	r.entries = append(r.entries, entry)
+
+	return entry.ID, nil
+}
+
+func (r *fakeRepository) GetEntry(_ context.Context, entryID int64) (repo.Entry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if entryID < 1 || entryID > int64(len(r.entries)) {
+		return repo.Entry{}, repo.ErrEntryNotFound
+	}
+
+	return r.entries[entryID-1], nil
+}
+
+func (r *fakeRepository) DeleteEntry(_ context.Context, entryID int64, _ int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.deleted = append(r.deleted, entryID)
+	return nil
+}
+
+// GetDeletedEntry отдает записи, перенесенные в корзину через DeleteEntry.
+func (r *fakeRepository) GetDeletedEntry(_ context.Context, entryID int64) (repo.Entry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, id := range r.deleted {
+		if id == entryID {
+			return r.entries[id-1], nil
+		}
+	}
+
+	return repo.Entry{}, repo.ErrEntryNotFound
+}
+
+func (r *fakeRepository) RestoreEntry(_ context.Context, entryID int64, _ int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, id := range r.deleted {
+		if id == entryID {
+			r.deleted = append(r.deleted[:i], r.deleted[i+1:]...)
+			return nil
+		}
+	}
+
+	return repo.ErrEntryNotFound
+}
+
+func (r *fakeRepository) ImportEntries(_ context.Context, _ int64, entries []repo.ImportEntry) (repo.ImportedEntries, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	res := repo.ImportedEntries{ProjectIDs: make(map[string]int64)}
+	for _, entry := range entries {
+		if _, ok := res.ProjectIDs[entry.ProjectName]; entry.ProjectID == 0 && !ok {
+			res.ProjectIDs[entry.ProjectName] = int64(len(res.ProjectIDs) + 100)
+		}
+
+		r.imported = append(r.imported, entry)
+		res.EntryIDs = append(res.EntryIDs, int64(len(r.imported)))
+	}
+
+	return res, nil
+}
+
+func (r *fakeRepository) GetImportedExternalIDs(_ context.Context, _ int64, source string, externalIDs []string) (map[string]struct{}, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	wanted := make(map[string]struct{}, len(externalIDs))
+	for _, id := range externalIDs {
+		wanted[id] = struct{}{}
+	}
+
+	res := make(map[string]struct{})
+	for _, entry := range r.imported {
+		if _, ok := wanted[entry.ExternalID]; ok && entry.ExternalSource == source {
+			res[entry.ExternalID] = struct{}{}
+		}
+	}
+
+	return res, nil
+}
+
+// fakeProjectRepository личные проекты пользователя по названию.
+type fakeProjectRepository struct {
+	projectRepository
+
+	byName map[string]projectRepoDto.Project
+	access map[int64]projectRepoDto.ProjectAccess
+}
+
+func (r *fakeProjectRepository) GetProjectByName(_ context.Context, _ int64, name string) (projectRepoDto.Project, error) {
+	project, ok := r.byName[name]
+	if !ok {
+		return projectRepoDto.Project{}, projectRepoDto.ErrProjectNotFound
+	}
+
+	return project, nil
+}
+
+func (r *fakeProjectRepository) GetProjectAccess(_ context.Context, projectID, _ int64) (projectRepoDto.ProjectAccess, error) {
+	access, ok := r.access[projectID]
+	if !ok {
+		return projectRepoDto.ProjectAccess{}, projectRepoDto.ErrProjectNotFound
+	}
+
+	return access, nil
+}
+
+// fakeTimesheetRepository утвержденные недели и число запросов к ним.
+type fakeTimesheetRepository struct {
+	approved map[time.Time]bool
+	calls    int
+}
+
+func (r *fakeTimesheetRepository) IsWeekApproved(_ context.Context, _, _ int64, weekStart time.Time) (bool, error) {
+	r.calls++
+	return r.approved[weekStart], nil
+}
+
+type fakePeriodLockRepository struct {
+	periodLockRepository
+
+	lockedBefore time.Time
+	calls        int
+	overrides    []periodLockRepoDto.Override
+}
+
+func (r *fakePeriodLockRepository) GetLock(_ context.Context, workspaceID int64) (periodLockRepoDto.Lock, error) {
+	r.calls++
+	if r.lockedBefore.IsZero() {
+		return periodLockRepoDto.Lock{}, periodLockRepoDto.ErrLockNotFound
+	}
+
+	return periodLockRepoDto.Lock{WorkspaceID: workspaceID, LockedBefore: r.lockedBefore}, nil
+}
+
+func (r *fakePeriodLockRepository) CreateOverride(_ context.Context, override periodLockRepoDto.Override) error {
+	r.overrides = append(r.overrides, override)
+	return nil
+}
+
+type fakeTxManager struct{}
+
+func (fakeTxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type fakeAuditLogger struct {
+	events []auditUC.Event
+}
+
+func (l *fakeAuditLogger) Record(_ context.Context, event auditUC.Event) error {
+	l.events = append(l.events, event)
+	return nil
+}
+
+type fakeGoalTracker struct {
+	checks int
+}
+
+func (t *fakeGoalTracker) CheckAchievements(context.Context, int64, int64) error {
+	t.checks++
+	return nil
+}
+
+type fakeStatsCache struct{}
+
+func (fakeStatsCache) InvalidateProject(context.Context, int64, int64) {}
+
+func (fakeStatsCache) InvalidateUser(context.Context, int64) {}
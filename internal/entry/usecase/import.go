@@ -0,0 +1,503 @@
+package usecase
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	auditUC "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/audit/usecase"
+	repo "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/entry/repository"
+	periodLockRepoDto "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/periodlock/repository"
+	projectRepoDto "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/project/repository"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/roles"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/tracing"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/utils"
+)
+
+var (
+	ErrInvalidImportSpec = errors.New("invalid import spec")
+	ErrTooManyImportRows = errors.New("too many rows to import")
+)
+
+const (
+	maxImportRows = 50000
+	// Ограничение колонки projects.name.
+	maxProjectNameLen = 35
+)
+
+// importColumns индексы колонок файла, -1 если колонка не используется.
+type importColumns struct {
+	project   int
+	name      int
+	timeStart int
+	timeEnd   int
+	duration  int
+}
+
+// ImportEntries импортирует записи пользователя из CSV. Каждая строка проверяется так же,
+// как при создании записи; отсутствующие личные проекты создаются. Если хотя бы одна строка
+// с ошибкой, ничего не сохраняется. При пробном запуске только возвращается отчет.
+func (u *Usecase) ImportEntries(ctx context.Context, userID int64, r io.Reader, spec ImportSpec, dryRun bool) (ImportResult, error) {
+	ctx, span := tracing.Start(ctx, "entry.ImportEntries")
+	defer span.End()
+
+	if spec.TimeLayout == "" {
+		spec.TimeLayout = time.RFC3339
+	}
+	if spec.Location == nil {
+		spec.Location = time.UTC
+	}
+
+	reader := csv.NewReader(r)
+	if spec.Delimiter != 0 {
+		reader.Comma = spec.Delimiter
+	}
+	// Число полей проверяем сами, чтобы вернуть ошибку по строке.
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return ImportResult{}, fmt.Errorf("%w: read header: %v", ErrInvalidImportSpec, err)
+	}
+
+	columns, err := resolveImportColumns(header, spec)
+	if err != nil {
+		return ImportResult{}, err
+	}
+
+	result := ImportResult{
+		DryRun:          dryRun,
+		CreatedProjects: []string{},
+		Errors:          []ImportRowError{},
+		Skipped:         []ImportRowError{},
+	}
+
+	// Идентификаторы проектов по названию, 0 — проект будет создан.
+	projectIDs := make(map[string]int64)
+	checker := u.newImportChecker(userID)
+	var entries []repo.ImportEntry
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return ImportResult{}, fmt.Errorf("read csv: %w", err)
+			}
+
+			result.TotalRows++
+			result.Errors = append(result.Errors, ImportRowError{Row: parseErr.StartLine, Message: parseErr.Err.Error()})
+			continue
+		}
+
+		line, _ := reader.FieldPos(0)
+		result.TotalRows++
+		if result.TotalRows > maxImportRows {
+			return ImportResult{}, fmt.Errorf("%w: limit is %d", ErrTooManyImportRows, maxImportRows)
+		}
+
+		entry, err := parseImportRow(record, columns, spec)
+		if err != nil {
+			result.Errors = append(result.Errors, ImportRowError{Row: line, Message: err.Error()})
+			continue
+		}
+
+		entry.ProjectID, err = u.resolveImportProject(ctx, userID, entry.ProjectName, projectIDs, &result)
+		if err != nil {
+			return ImportResult{}, err
+		}
+
+		if err = checker.check(ctx, entry); err != nil {
+			if !isEntryRuleError(err) {
+				return ImportResult{}, err
+			}
+
+			result.Errors = append(result.Errors, ImportRowError{Row: line, Message: err.Error()})
+			continue
+		}
+
+		entries = append(entries, entry)
+	}
+
+	result.ValidRows = len(entries)
+	if dryRun || len(result.Errors) > 0 || len(entries) == 0 {
+		return result, nil
+	}
+
+	if err = u.saveImport(ctx, userID, entries, checker, &result, ""); err != nil {
+		return ImportResult{}, err
+	}
+
+	return result, nil
+}
+
+// resolveImportProject возвращает идентификатор личного проекта пользователя по названию
+// или 0, если проект будет создан при импорте. Новые проекты добавляются в result.CreatedProjects.
+func (u *Usecase) resolveImportProject(
+	ctx context.Context,
+	userID int64,
+	name string,
+	projectIDs map[string]int64,
+	result *ImportResult,
+) (int64, error) {
+	if projectID, ok := projectIDs[name]; ok {
+		return projectID, nil
+	}
+
+	project, err := u.projectRepository.GetProjectByName(ctx, userID, name)
+	if err != nil && !errors.Is(err, projectRepoDto.ErrProjectNotFound) {
+		return 0, fmt.Errorf("repo get project by name: %w", err)
+	}
+
+	projectIDs[name] = project.ID
+	if project.ID == 0 {
+		result.CreatedProjects = append(result.CreatedProjects, name)
+	}
+
+	return project.ID, nil
+}
+
+// importChecker проверяет строки импорта по тем же правилам, что и checkWritable, но загружает
+// доступ к проекту и блокировку один раз на проект, а утверждение табеля — один раз на неделю:
+// в файле могут быть десятки тысяч строк по нескольким проектам.
+type importChecker struct {
+	u      *Usecase
+	userID int64
+
+	projects map[int64]importProject
+	weeks    map[importWeek]bool
+}
+
+// importProject результат проверки проекта. err — ошибка, с которой отклоняется любая запись проекта.
+type importProject struct {
+	workspaceID  int64
+	lockedBefore time.Time
+	err          error
+}
+
+type importWeek struct {
+	workspaceID int64
+	weekStart   time.Time
+}
+
+func (u *Usecase) newImportChecker(userID int64) *importChecker {
+	return &importChecker{
+		u:        u,
+		userID:   userID,
+		projects: make(map[int64]importProject),
+		weeks:    make(map[importWeek]bool),
+	}
+}
+
+// check проверяет запись в существующем проекте так же, как при создании записи.
+// Новый проект будет личным проектом пользователя, проверять в нем нечего.
+// Блокировку периода при импорте обойти нельзя.
+func (c *importChecker) check(ctx context.Context, entry repo.ImportEntry) error {
+	if entry.ProjectID == 0 {
+		return nil
+	}
+
+	project, err := c.project(ctx, entry.ProjectID)
+	if err != nil {
+		return err
+	}
+	if project.err != nil || project.workspaceID == 0 {
+		return project.err
+	}
+
+	lastWeek := utils.GetWeekStart(entry.TimeEnd)
+	for week := utils.GetWeekStart(entry.TimeStart); !week.After(lastWeek); week = week.AddDate(0, 0, 7) {
+		approved, err := c.weekApproved(ctx, project.workspaceID, week)
+		if err != nil {
+			return err
+		}
+
+		if approved {
+			return ErrEntryReadOnly
+		}
+	}
+
+	if entry.TimeStart.Before(project.lockedBefore) {
+		return ErrPeriodLocked
+	}
+
+	return nil
+}
+
+// workspaceID возвращает пространство проверенного проекта, 0 для личного и нового проекта.
+func (c *importChecker) workspaceID(projectID int64) int64 {
+	return c.projects[projectID].workspaceID
+}
+
+func (c *importChecker) project(ctx context.Context, projectID int64) (importProject, error) {
+	if project, ok := c.projects[projectID]; ok {
+		return project, nil
+	}
+
+	var project importProject
+
+	access, err := c.u.projectRepository.GetProjectAccess(ctx, projectID, c.userID)
+	switch {
+	case errors.Is(err, projectRepoDto.ErrProjectNotFound):
+		project.err = ErrProjectNotFound
+	case err != nil:
+		return importProject{}, fmt.Errorf("repo get project access: %w", err)
+	case !roles.ProjectRole(access.OwnerID, c.userID, access.WorkspaceID.Valid, access.MemberRole.String).AtLeast(roles.Member):
+		project.err = ErrForbidden
+	case access.WorkspaceID.Valid:
+		project.workspaceID = access.WorkspaceID.Int64
+
+		lock, err := c.u.periodLockRepository.GetLock(ctx, project.workspaceID)
+		if err != nil && !errors.Is(err, periodLockRepoDto.ErrLockNotFound) {
+			return importProject{}, fmt.Errorf("repo get lock: %w", err)
+		}
+		project.lockedBefore = lock.LockedBefore
+	}
+
+	c.projects[projectID] = project
+
+	return project, nil
+}
+
+func (c *importChecker) weekApproved(ctx context.Context, workspaceID int64, weekStart time.Time) (bool, error) {
+	key := importWeek{workspaceID: workspaceID, weekStart: weekStart}
+	if approved, ok := c.weeks[key]; ok {
+		return approved, nil
+	}
+
+	approved, err := c.u.timesheetRepository.IsWeekApproved(ctx, c.userID, workspaceID, weekStart)
+	if err != nil {
+		return false, fmt.Errorf("repo is week approved: %w", err)
+	}
+	c.weeks[key] = approved
+
+	return approved, nil
+}
+
+// saveImport сохраняет записи и созданные проекты и журналирует создание каждой записи и проекта,
+// как при создании по одной, и итог импорта. source — трекер, из которого импортированы записи,
+// пустой для собственного формата.
+func (u *Usecase) saveImport(
+	ctx context.Context,
+	userID int64,
+	entries []repo.ImportEntry,
+	checker *importChecker,
+	result *ImportResult,
+	source string,
+) error {
+	// Записи, созданные проекты, журнал и outbox сохраняются атомарно.
+	err := u.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		imported, err := u.repository.ImportEntries(ctx, userID, entries)
+		if err != nil {
+			return fmt.Errorf("repo import entries: %w", err)
+		}
+		createdProjects := imported.ProjectIDs
+
+		for _, name := range result.CreatedProjects {
+			err = u.auditLogger.Record(ctx, auditUC.Event{
+				ActorID:    userID,
+				EntityType: auditUC.EntityProject,
+				EntityID:   createdProjects[name],
+				Action:     auditUC.ActionCreate,
+				After: auditUC.ProjectState{
+					ID:     createdProjects[name],
+					UserID: userID,
+					Name:   name,
+				},
+			})
+			if err != nil {
+				return fmt.Errorf("audit record: %w", err)
+			}
+		}
+
+		for i, id := range imported.EntryIDs {
+			// Запись импортировали параллельным запросом.
+			if id == 0 {
+				continue
+			}
+			result.ImportedRows++
+
+			entry := Entry{
+				ID:        id,
+				UserID:    userID,
+				ProjectID: entries[i].ProjectID,
+				Name:      entries[i].Name,
+				TimeStart: entries[i].TimeStart,
+				TimeEnd:   entries[i].TimeEnd,
+				Tags:      entries[i].Tags,
+				Billable:  entries[i].Billable,
+			}
+			if entry.ProjectID == 0 {
+				entry.ProjectID = createdProjects[entries[i].ProjectName]
+			}
+
+			err = u.recordAudit(ctx, checker.workspaceID(entries[i].ProjectID), userID, id, auditUC.ActionCreate, nil, &entry)
+			if err != nil {
+				return err
+			}
+		}
+
+		summary := map[string]interface{}{
+			"imported_rows":    result.ImportedRows,
+			"created_projects": result.CreatedProjects,
+		}
+		if source != "" {
+			summary["source"] = source
+			summary["skipped_rows"] = len(result.Skipped)
+		}
+
+		err = u.auditLogger.Record(ctx, auditUC.Event{
+			ActorID:    userID,
+			EntityType: auditUC.EntityEntry,
+			Action:     auditUC.ActionImport,
+			After:      summary,
+		})
+		if err != nil {
+			return fmt.Errorf("audit record: %w", err)
+		}
+
+		// Импорт затрагивает цели по всем проектам пользователя.
+		return u.checkAchievements(ctx, userID, 0)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Импорт затрагивает сразу много проектов и целей пользователя.
+	u.statsCache.InvalidateUser(ctx, userID)
+
+	return nil
+}
+
+func resolveImportColumns(header []string, spec ImportSpec) (importColumns, error) {
+	indexes := make(map[string]int, len(header))
+	for i, name := range header {
+		// Excel добавляет BOM в начало UTF-8 файла.
+		if i == 0 {
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		indexes[strings.TrimSpace(name)] = i
+	}
+
+	find := func(name string, required bool) (int, error) {
+		if name == "" {
+			if required {
+				return -1, fmt.Errorf("%w: required column is not mapped", ErrInvalidImportSpec)
+			}
+			return -1, nil
+		}
+
+		idx, ok := indexes[name]
+		if !ok {
+			return -1, fmt.Errorf("%w: column %q not found in header", ErrInvalidImportSpec, name)
+		}
+		return idx, nil
+	}
+
+	var columns importColumns
+	var err error
+
+	if columns.project, err = find(spec.ProjectColumn, true); err != nil {
+		return importColumns{}, err
+	}
+	if columns.name, err = find(spec.NameColumn, false); err != nil {
+		return importColumns{}, err
+	}
+	if columns.timeStart, err = find(spec.TimeStartColumn, true); err != nil {
+		return importColumns{}, err
+	}
+	if columns.timeEnd, err = find(spec.TimeEndColumn, false); err != nil {
+		return importColumns{}, err
+	}
+	if columns.duration, err = find(spec.DurationColumn, false); err != nil {
+		return importColumns{}, err
+	}
+
+	if columns.timeEnd < 0 && columns.duration < 0 {
+		return importColumns{}, fmt.Errorf("%w: either time end or duration column is required", ErrInvalidImportSpec)
+	}
+
+	return columns, nil
+}
+
+func parseImportRow(record []string, columns importColumns, spec ImportSpec) (repo.ImportEntry, error) {
+	field := func(idx int) string {
+		if idx < 0 || idx >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[idx])
+	}
+
+	entry := repo.ImportEntry{
+		ProjectName: field(columns.project),
+		Name:        field(columns.name),
+	}
+
+	if entry.ProjectName == "" {
+		return repo.ImportEntry{}, errors.New("project is empty")
+	}
+	if utf8.RuneCountInString(entry.ProjectName) > maxProjectNameLen {
+		return repo.ImportEntry{}, fmt.Errorf("project name is longer than %d characters", maxProjectNameLen)
+	}
+
+	var err error
+	entry.TimeStart, err = time.ParseInLocation(spec.TimeLayout, field(columns.timeStart), spec.Location)
+	if err != nil {
+		return repo.ImportEntry{}, fmt.Errorf("invalid time start: %w", err)
+	}
+
+	if columns.timeEnd >= 0 && field(columns.timeEnd) != "" {
+		entry.TimeEnd, err = time.ParseInLocation(spec.TimeLayout, field(columns.timeEnd), spec.Location)
+		if err != nil {
+			return repo.ImportEntry{}, fmt.Errorf("invalid time end: %w", err)
+		}
+	} else {
+		duration, err := parseImportDuration(field(columns.duration))
+		if err != nil {
+			return repo.ImportEntry{}, fmt.Errorf("invalid duration: %w", err)
+		}
+		entry.TimeEnd = entry.TimeStart.Add(duration)
+	}
+
+	if !entry.TimeEnd.After(entry.TimeStart) {
+		return repo.ImportEntry{}, errors.New("time end must be after time start")
+	}
+
+	return entry, nil
+}
+
+// parseImportDuration разбирает длительность в формате Go (1h30m) или в часах (1.5, 1,5).
+func parseImportDuration(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, errors.New("duration is empty")
+	}
+
+	if d, err := time.ParseDuration(s); err == nil {
+		return d, nil
+	}
+
+	hours, err := strconv.ParseFloat(strings.Replace(s, ",", ".", 1), 64)
+	if err != nil {
+		return 0, fmt.Errorf("parse %q: expected 1h30m or hours", s)
+	}
+
+	return time.Duration(hours * float64(time.Hour)), nil
+}
+
+// isEntryRuleError сообщает, что запись нарушает правила создания, а не что произошел сбой.
+func isEntryRuleError(err error) bool {
+	return errors.Is(err, ErrProjectNotFound) ||
+		errors.Is(err, ErrForbidden) ||
+		errors.Is(err, ErrEntryReadOnly) ||
+		errors.Is(err, ErrPeriodLocked)
+}
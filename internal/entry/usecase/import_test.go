@@ -0,0 +1,152 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	auditUC "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/audit/usecase"
+	projectRepoDto "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/project/repository"
+)
+
+const (
+	testWorkspaceID = 5
+	// Проект пространства testWorkspaceID, пользователь в нем участник.
+	testWorkspaceProjectID = 9
+)
+
+var testImportSpec = ImportSpec{
+	ProjectColumn:   "Project",
+	NameColumn:      "Name",
+	TimeStartColumn: "Start",
+	TimeEndColumn:   "End",
+}
+
+func newWorkspaceImportUsecase(
+	entries *fakeRepository,
+	timesheets *fakeTimesheetRepository,
+	locks *fakePeriodLockRepository,
+	audit *fakeAuditLogger,
+) *Usecase {
+	projects := &fakeProjectRepository{
+		byName: map[string]projectRepoDto.Project{
+			"Website": {ID: 7, Name: "Website", UserID: testUserID},
+			"Client":  {ID: testWorkspaceProjectID, Name: "Client", UserID: 2},
+		},
+		access: map[int64]projectRepoDto.ProjectAccess{
+			7: {ProjectID: 7, OwnerID: testUserID},
+			testWorkspaceProjectID: {
+				ProjectID:   testWorkspaceProjectID,
+				OwnerID:     2,
+				WorkspaceID: sql.NullInt64{Int64: testWorkspaceID, Valid: true},
+				MemberRole:  sql.NullString{String: "member", Valid: true},
+			},
+		},
+	}
+
+	return NewUsecase(entries, projects, timesheets, locks, audit, nil, &fakeGoalTracker{}, fakeTxManager{}, fakeStatsCache{})
+}
+
+// Доступ к проекту и блокировка загружаются один раз на проект, утверждение табеля — один раз на неделю.
+func TestImportEntriesLoadsChecksOncePerProjectAndWeek(t *testing.T) {
+	timesheets := &fakeTimesheetRepository{
+		approved: map[time.Time]bool{time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC): true},
+	}
+	locks := &fakePeriodLockRepository{lockedBefore: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)}
+	uc := newWorkspaceImportUsecase(&fakeRepository{}, timesheets, locks, &fakeAuditLogger{})
+
+	csv := `Project,Name,Start,End
+Client,Monday,2024-03-04T09:00:00Z,2024-03-04T10:00:00Z
+Client,Tuesday,2024-03-05T09:00:00Z,2024-03-05T10:00:00Z
+Client,Approved week,2024-03-11T09:00:00Z,2024-03-11T10:00:00Z
+Client,Approved week again,2024-03-12T09:00:00Z,2024-03-12T10:00:00Z
+Client,Locked,2024-02-29T09:00:00Z,2024-02-29T10:00:00Z
+Website,Personal,2024-03-11T09:00:00Z,2024-03-11T10:00:00Z
+`
+
+	result, err := uc.ImportEntries(context.Background(), testUserID, strings.NewReader(csv), testImportSpec, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[int]string{
+		4: ErrEntryReadOnly.Error(),
+		5: ErrEntryReadOnly.Error(),
+		6: ErrPeriodLocked.Error(),
+	}
+	got := make(map[int]string, len(result.Errors))
+	for _, rowErr := range result.Errors {
+		got[rowErr.Row] = rowErr.Message
+	}
+	if len(got) != len(want) {
+		t.Fatalf("row errors %v, want %v", got, want)
+	}
+	for row, message := range want {
+		if got[row] != message {
+			t.Errorf("row %d: error %q, want %q", row, got[row], message)
+		}
+	}
+
+	// Недели 26 февраля, 4 и 11 марта.
+	if timesheets.calls != 3 {
+		t.Errorf("week approval loaded %d times, want 3", timesheets.calls)
+	}
+	if locks.calls != 1 {
+		t.Errorf("period lock loaded %d times, want 1", locks.calls)
+	}
+}
+
+// Каждая импортированная запись журналируется и попадает в outbox как при создании по одной.
+func TestImportEntriesRecordsEntryEvents(t *testing.T) {
+	audit := &fakeAuditLogger{}
+	uc := newWorkspaceImportUsecase(&fakeRepository{}, &fakeTimesheetRepository{}, &fakePeriodLockRepository{}, audit)
+
+	csv := `Project,Name,Start,End
+Client,Review,2024-03-04T09:00:00Z,2024-03-04T10:00:00Z
+Research,Reading,2024-03-04T11:00:00Z,2024-03-04T12:00:00Z
+`
+
+	result, err := uc.ImportEntries(context.Background(), testUserID, strings.NewReader(csv), testImportSpec, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.ImportedRows != 2 {
+		t.Fatalf("imported %d rows, want 2: %+v", result.ImportedRows, result)
+	}
+
+	type event struct {
+		entityType  string
+		action      string
+		workspaceID int64
+		projectID   int64
+	}
+	var got []event
+	for _, e := range audit.events {
+		ev := event{entityType: e.EntityType, action: e.Action, workspaceID: e.WorkspaceID}
+		if state, ok := e.After.(auditUC.EntryState); ok {
+			ev.projectID = state.ProjectID
+			if state.ID != e.EntityID || state.ID == 0 {
+				t.Errorf("entry event for %d has state of entry %d", e.EntityID, state.ID)
+			}
+		}
+		got = append(got, ev)
+	}
+
+	// Новому проекту Research фейковый репозиторий выдает идентификатор 100.
+	want := []event{
+		{auditUC.EntityProject, auditUC.ActionCreate, 0, 0},
+		{auditUC.EntityEntry, auditUC.ActionCreate, testWorkspaceID, testWorkspaceProjectID},
+		{auditUC.EntityEntry, auditUC.ActionCreate, 0, 100},
+		{auditUC.EntityEntry, auditUC.ActionImport, 0, 0},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("audit events %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("event %d: got %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
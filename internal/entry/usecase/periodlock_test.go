@@ -0,0 +1,167 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	auditUC "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/audit/usecase"
+	repo "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/entry/repository"
+	periodLockRepoDto "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/periodlock/repository"
+	projectRepoDto "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/project/repository"
+)
+
+// Период до testLockedBefore заблокирован в пространстве testWorkspaceID.
+var testLockedBefore = time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+// newLockUsecase usecase, в котором пользователь состоит в пространстве проекта
+// testWorkspaceProjectID с ролью role.
+func newLockUsecase(role string, entries *fakeRepository, locks *fakePeriodLockRepository, audit *fakeAuditLogger) *Usecase {
+	projects := &fakeProjectRepository{
+		access: map[int64]projectRepoDto.ProjectAccess{
+			testWorkspaceProjectID: {
+				ProjectID:   testWorkspaceProjectID,
+				OwnerID:     2,
+				WorkspaceID: sql.NullInt64{Int64: testWorkspaceID, Valid: true},
+				MemberRole:  sql.NullString{String: role, Valid: true},
+			},
+		},
+	}
+
+	return NewUsecase(entries, projects, &fakeTimesheetRepository{}, locks, audit, nil, &fakeGoalTracker{}, fakeTxManager{}, fakeStatsCache{})
+}
+
+func lockTestEntry(start time.Time) Entry {
+	return Entry{
+		UserID:    testUserID,
+		ProjectID: testWorkspaceProjectID,
+		Name:      "Report",
+		TimeStart: start,
+		TimeEnd:   start.Add(time.Hour),
+	}
+}
+
+func TestCreateEntryInLockedPeriod(t *testing.T) {
+	locked := testLockedBefore.AddDate(0, 0, -3)
+
+	tests := []struct {
+		name         string
+		role         string
+		start        time.Time
+		reason       string
+		wantErr      error
+		wantOverride bool
+	}{
+		{name: "member without reason", role: "member", start: locked, wantErr: ErrPeriodLocked},
+		{name: "member with reason", role: "member", start: locked, reason: "late invoice", wantErr: ErrPeriodLocked},
+		{name: "admin without reason", role: "admin", start: locked, wantErr: ErrPeriodLocked},
+		{name: "admin with reason", role: "admin", start: locked, reason: "late invoice", wantOverride: true},
+		{name: "owner with reason", role: "owner", start: locked, reason: "late invoice", wantOverride: true},
+		// Запись после начала открытого периода не требует разрешения, причина не сохраняется.
+		{name: "open period", role: "admin", start: testLockedBefore, reason: "late invoice"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries := &fakeRepository{}
+			locks := &fakePeriodLockRepository{lockedBefore: testLockedBefore}
+			audit := &fakeAuditLogger{}
+			uc := newLockUsecase(tt.role, entries, locks, audit)
+
+			id, err := uc.CreateEntry(context.Background(), lockTestEntry(tt.start), WriteOptions{LockOverrideReason: tt.reason})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("create entry: error %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr != nil {
+				if len(entries.entries) != 0 || len(locks.overrides) != 0 || len(audit.events) != 0 {
+					t.Errorf("locked entry was written: entries %v, overrides %v, audit %v",
+						entries.entries, locks.overrides, audit.events)
+				}
+				return
+			}
+
+			var wantOverrides []periodLockRepoDto.Override
+			if tt.wantOverride {
+				wantOverrides = []periodLockRepoDto.Override{{
+					WorkspaceID: testWorkspaceID,
+					UserID:      sql.NullInt64{Int64: testUserID, Valid: true},
+					EntryID:     id,
+					Action:      actionCreate,
+					Reason:      tt.reason,
+				}}
+			}
+			if !equalOverrides(locks.overrides, wantOverrides) {
+				t.Errorf("overrides %+v, want %+v", locks.overrides, wantOverrides)
+			}
+
+			if len(audit.events) != 1 {
+				t.Fatalf("audit events %+v, want one", audit.events)
+			}
+			event := audit.events[0]
+			if event.Action != auditUC.ActionCreate || event.EntityID != id || event.WorkspaceID != testWorkspaceID {
+				t.Errorf("audit event %+v, want create of entry %d in workspace %d", event, id, testWorkspaceID)
+			}
+		})
+	}
+}
+
+func TestDeleteEntryInLockedPeriodByAdmin(t *testing.T) {
+	start := testLockedBefore.AddDate(0, 0, -10)
+	entries := &fakeRepository{entries: []repo.Entry{{
+		ID:        1,
+		UserID:    testUserID,
+		ProjectID: testWorkspaceProjectID,
+		Name:      "Report",
+		TimeStart: start,
+		TimeEnd:   start.Add(time.Hour),
+	}}}
+	locks := &fakePeriodLockRepository{lockedBefore: testLockedBefore}
+	audit := &fakeAuditLogger{}
+	uc := newLockUsecase("admin", entries, locks, audit)
+
+	err := uc.DeleteEntry(context.Background(), 1, testUserID, WriteOptions{})
+	if !errors.Is(err, ErrPeriodLocked) {
+		t.Fatalf("delete without reason: error %v, want %v", err, ErrPeriodLocked)
+	}
+
+	err = uc.DeleteEntry(context.Background(), 1, testUserID, WriteOptions{LockOverrideReason: "duplicate"})
+	if err != nil {
+		t.Fatalf("delete with reason: %v", err)
+	}
+
+	if len(entries.deleted) != 1 || entries.deleted[0] != 1 {
+		t.Errorf("deleted entries %v, want [1]", entries.deleted)
+	}
+
+	wantOverrides := []periodLockRepoDto.Override{{
+		WorkspaceID: testWorkspaceID,
+		UserID:      sql.NullInt64{Int64: testUserID, Valid: true},
+		EntryID:     1,
+		Action:      actionDelete,
+		Reason:      "duplicate",
+	}}
+	if !equalOverrides(locks.overrides, wantOverrides) {
+		t.Errorf("overrides %+v, want %+v", locks.overrides, wantOverrides)
+	}
+
+	if len(audit.events) != 1 || audit.events[0].Action != auditUC.ActionDelete || audit.events[0].Before == nil {
+		t.Errorf("audit events %+v, want one delete with the entry before deletion", audit.events)
+	}
+}
+
+func equalOverrides(got, want []periodLockRepoDto.Override) bool {
+	if len(got) != len(want) {
+		return false
+	}
+
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,222 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+	"unicode/utf8"
+
+	repo "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/entry/repository"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/tracing"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/trackerimport"
+)
+
+// Ограничения колонок entries.external_id и projects.client.
+const (
+	maxExternalIDLen = 64
+	maxClientLen     = 64
+)
+
+// ImportFromTracker импортирует записи пользователя из выгрузки Toggl Track или Clockify.
+// Проекты сопоставляются с личными проектами пользователя по названию, отсутствующие создаются
+// вместе с клиентом. В отличие от ImportEntries строки, которые нельзя импортировать
+// (уже импортированные, запущенные таймеры, без проекта, в заблокированном периоде), пропускаются
+// и попадают в отчет, остальные сохраняются. При пробном запуске только возвращается отчет.
+func (u *Usecase) ImportFromTracker(
+	ctx context.Context,
+	userID int64,
+	source string,
+	r io.Reader,
+	loc *time.Location,
+	dryRun bool,
+) (ImportResult, error) {
+	ctx, span := tracing.Start(ctx, "entry.ImportFromTracker")
+	defer span.End()
+
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	records, skipped, err := trackerimport.Parse(source, r, loc)
+	if err != nil {
+		if errors.Is(err, trackerimport.ErrUnknownSource) || errors.Is(err, trackerimport.ErrInvalidFile) {
+			return ImportResult{}, fmt.Errorf("%w: %v", ErrInvalidImportSpec, err)
+		}
+
+		return ImportResult{}, fmt.Errorf("parse %s export: %w", source, err)
+	}
+
+	return u.importRecords(ctx, userID, source, records, skipped, dryRun)
+}
+
+// ImportFromCalendar импортирует события календаря iCalendar как записи пользователя.
+// Проект события выбирается по правилам spec, повторяющиеся события разворачиваются в окне
+// [From, To). Пропуски и сохранение — как в ImportFromTracker.
+func (u *Usecase) ImportFromCalendar(
+	ctx context.Context,
+	userID int64,
+	r io.Reader,
+	spec CalendarImportSpec,
+	dryRun bool,
+) (ImportResult, error) {
+	ctx, span := tracing.Start(ctx, "entry.ImportFromCalendar")
+	defer span.End()
+
+	if spec.To.IsZero() {
+		spec.To = time.Now()
+	}
+
+	// По умолчанию отклоненные события ищем среди участников по email аккаунта.
+	if spec.AttendeeEmail == "" {
+		profile, err := u.userRepository.GetProfile(ctx, userID)
+		if err != nil {
+			return ImportResult{}, fmt.Errorf("repo get profile: %w", err)
+		}
+		spec.AttendeeEmail = profile.Email
+	}
+
+	icalSpec := trackerimport.ICalSpec{
+		Rules:          make([]trackerimport.ICalRule, 0, len(spec.Rules)),
+		DefaultProject: spec.DefaultProject,
+		AttendeeEmail:  spec.AttendeeEmail,
+		From:           spec.From,
+		To:             spec.To,
+		Location:       spec.Location,
+	}
+	for _, rule := range spec.Rules {
+		icalSpec.Rules = append(icalSpec.Rules, trackerimport.ICalRule{
+			Calendar:        rule.Calendar,
+			Category:        rule.Category,
+			SummaryContains: rule.SummaryContains,
+			Project:         rule.Project,
+		})
+	}
+
+	records, skipped, err := trackerimport.ParseICal(r, icalSpec)
+	if err != nil {
+		if errors.Is(err, trackerimport.ErrInvalidFile) {
+			return ImportResult{}, fmt.Errorf("%w: %v", ErrInvalidImportSpec, err)
+		}
+
+		return ImportResult{}, fmt.Errorf("parse calendar: %w", err)
+	}
+
+	return u.importRecords(ctx, userID, trackerimport.SourceICal, records, skipped, dryRun)
+}
+
+// importRecords сохраняет разобранные записи другого источника, пропуская уже импортированные,
+// повторы внутри файла и нарушающие правила создания записей.
+func (u *Usecase) importRecords(
+	ctx context.Context,
+	userID int64,
+	source string,
+	records []trackerimport.Record,
+	skipped []trackerimport.Skip,
+	dryRun bool,
+) (ImportResult, error) {
+	result := ImportResult{
+		DryRun:          dryRun,
+		TotalRows:       len(records) + len(skipped),
+		CreatedProjects: []string{},
+		Errors:          []ImportRowError{},
+		Skipped:         make([]ImportRowError, 0, len(skipped)),
+	}
+
+	if result.TotalRows > maxImportRows {
+		return ImportResult{}, fmt.Errorf("%w: limit is %d", ErrTooManyImportRows, maxImportRows)
+	}
+
+	for _, skip := range skipped {
+		result.Skipped = append(result.Skipped, ImportRowError{Row: skip.Row, Message: skip.Reason})
+	}
+
+	externalIDs := make([]string, 0, len(records))
+	for _, record := range records {
+		externalIDs = append(externalIDs, record.ExternalID)
+	}
+
+	imported, err := u.repository.GetImportedExternalIDs(ctx, userID, source, externalIDs)
+	if err != nil {
+		return ImportResult{}, fmt.Errorf("repo get imported external ids: %w", err)
+	}
+
+	// Идентификаторы проектов по названию, 0 — проект будет создан.
+	projectIDs := make(map[string]int64)
+	seen := make(map[string]struct{}, len(records))
+	checker := u.newImportChecker(userID)
+	var entries []repo.ImportEntry
+
+	for _, record := range records {
+		skip := func(message string) {
+			result.Skipped = append(result.Skipped, ImportRowError{Row: record.Row, Message: message})
+		}
+
+		if _, ok := imported[record.ExternalID]; ok {
+			skip("already imported")
+			continue
+		}
+		if _, ok := seen[record.ExternalID]; ok {
+			skip("duplicate of another row in the file")
+			continue
+		}
+		seen[record.ExternalID] = struct{}{}
+
+		if utf8.RuneCountInString(record.Project) > maxProjectNameLen {
+			skip(fmt.Sprintf("project name is longer than %d characters", maxProjectNameLen))
+			continue
+		}
+		if len(record.ExternalID) > maxExternalIDLen {
+			skip(fmt.Sprintf("external id is longer than %d characters", maxExternalIDLen))
+			continue
+		}
+
+		entry := repo.ImportEntry{
+			ProjectName:    record.Project,
+			ProjectClient:  truncateRunes(record.Client, maxClientLen),
+			Name:           record.Description,
+			TimeStart:      record.Start,
+			TimeEnd:        record.End,
+			Tags:           record.Tags,
+			Billable:       record.Billable,
+			ExternalSource: source,
+			ExternalID:     record.ExternalID,
+		}
+
+		entry.ProjectID, err = u.resolveImportProject(ctx, userID, entry.ProjectName, projectIDs, &result)
+		if err != nil {
+			return ImportResult{}, err
+		}
+
+		if err = checker.check(ctx, entry); err != nil {
+			if !isEntryRuleError(err) {
+				return ImportResult{}, err
+			}
+
+			skip(err.Error())
+			continue
+		}
+
+		entries = append(entries, entry)
+	}
+
+	result.ValidRows = len(entries)
+	if dryRun || len(entries) == 0 {
+		return result, nil
+	}
+
+	if err = u.saveImport(ctx, userID, entries, checker, &result, source); err != nil {
+		return ImportResult{}, err
+	}
+
+	return result, nil
+}
+
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+
+	return string([]rune(s)[:n])
+}
@@ -0,0 +1,120 @@
+package usecase
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	projectRepoDto "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/project/repository"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/trackerimport"
+)
+
+const testUserID = 1
+
+const togglExport = `Client,Project,Description,Start date,Start time,End date,End time,Tags
+Acme,Website,Landing page,2024-03-04,09:00:00,2024-03-04,10:30:00,"design, frontend"
+,Internal,Standup,2024-03-04,11:00:00,2024-03-04,11:15:00,
+Acme,Website,Running timer,2024-03-04,12:00:00,,,
+,Internal,Standup,2024-03-04,11:00:00,2024-03-04,11:15:00,
+`
+
+func newImportUsecase(entries *fakeRepository, audit *fakeAuditLogger, goals *fakeGoalTracker) *Usecase {
+	projects := &fakeProjectRepository{
+		byName: map[string]projectRepoDto.Project{
+			"Website": {ID: 7, Name: "Website", UserID: testUserID},
+		},
+		access: map[int64]projectRepoDto.ProjectAccess{
+			7: {ProjectID: 7, OwnerID: testUserID},
+		},
+	}
+
+	return NewUsecase(entries, projects, nil, nil, audit, nil, goals, fakeTxManager{}, fakeStatsCache{})
+}
+
+func skipMessages(skipped []ImportRowError) map[int]string {
+	res := make(map[int]string, len(skipped))
+	for _, skip := range skipped {
+		res[skip.Row] = skip.Message
+	}
+
+	return res
+}
+
+func TestImportFromTrackerDeduplicates(t *testing.T) {
+	ctx := context.Background()
+	entries := &fakeRepository{}
+	uc := newImportUsecase(entries, &fakeAuditLogger{}, &fakeGoalTracker{})
+
+	first, err := uc.ImportFromTracker(ctx, testUserID, trackerimport.SourceToggl, strings.NewReader(togglExport), time.UTC, false)
+	if err != nil {
+		t.Fatalf("first import: %v", err)
+	}
+
+	if first.TotalRows != 4 || first.ImportedRows != 2 {
+		t.Errorf("first import: got %d of %d rows imported, want 2 of 4", first.ImportedRows, first.TotalRows)
+	}
+	if !reflect.DeepEqual(first.CreatedProjects, []string{"Internal"}) {
+		t.Errorf("first import: created projects %v, want [Internal]", first.CreatedProjects)
+	}
+
+	wantSkipped := map[int]string{
+		4: "entry is still running",
+		5: "duplicate of another row in the file",
+	}
+	if got := skipMessages(first.Skipped); !reflect.DeepEqual(got, wantSkipped) {
+		t.Errorf("first import: skipped %v, want %v", got, wantSkipped)
+	}
+
+	if len(entries.imported) != 2 {
+		t.Fatalf("saved %d entries, want 2", len(entries.imported))
+	}
+	for _, entry := range entries.imported {
+		if entry.ExternalSource != trackerimport.SourceToggl || entry.ExternalID == "" {
+			t.Errorf("entry %q saved without external id: %q %q", entry.Name, entry.ExternalSource, entry.ExternalID)
+		}
+	}
+	if entries.imported[0].ProjectID != 7 || entries.imported[1].ProjectID != 0 {
+		t.Errorf("got project ids %d and %d, want existing 7 and new 0",
+			entries.imported[0].ProjectID, entries.imported[1].ProjectID)
+	}
+
+	second, err := uc.ImportFromTracker(ctx, testUserID, trackerimport.SourceToggl, strings.NewReader(togglExport), time.UTC, false)
+	if err != nil {
+		t.Fatalf("second import: %v", err)
+	}
+
+	if second.ImportedRows != 0 || second.ValidRows != 0 {
+		t.Errorf("second import: got %d rows imported, want 0", second.ImportedRows)
+	}
+
+	wantSkipped = map[int]string{
+		2: "already imported",
+		3: "already imported",
+		4: "entry is still running",
+		5: "already imported",
+	}
+	if got := skipMessages(second.Skipped); !reflect.DeepEqual(got, wantSkipped) {
+		t.Errorf("second import: skipped %v, want %v", got, wantSkipped)
+	}
+
+	if len(entries.imported) != 2 {
+		t.Errorf("re-import saved %d more entries", len(entries.imported)-2)
+	}
+}
+
+func TestImportFromTrackerDryRun(t *testing.T) {
+	entries := &fakeRepository{}
+	uc := newImportUsecase(entries, &fakeAuditLogger{}, &fakeGoalTracker{})
+
+	result, err := uc.ImportFromTracker(context.Background(), testUserID, trackerimport.SourceToggl,
+		strings.NewReader(togglExport), time.UTC, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.ValidRows != 2 || result.ImportedRows != 0 || len(entries.imported) != 0 {
+		t.Errorf("dry run: got %d valid and %d imported rows, %d saved", result.ValidRows, result.ImportedRows, len(entries.imported))
+	}
+}
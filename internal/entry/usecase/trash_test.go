@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	auditUC "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/audit/usecase"
+	repo "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/entry/repository"
+	projectRepoDto "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/project/repository"
+)
+
+func newTrashUsecase(entries *fakeRepository, projects *fakeProjectRepository, audit *fakeAuditLogger) *Usecase {
+	return NewUsecase(entries, projects, &fakeTimesheetRepository{}, &fakePeriodLockRepository{}, audit, nil,
+		&fakeGoalTracker{}, fakeTxManager{}, fakeStatsCache{})
+}
+
+func TestRestoreEntry(t *testing.T) {
+	start := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	entries := &fakeRepository{
+		entries: []repo.Entry{
+			{ID: 1, UserID: testUserID, ProjectID: 7, Name: "Landing page", TimeStart: start, TimeEnd: start.Add(time.Hour)},
+			{ID: 2, UserID: 2, ProjectID: 7, Name: "Other user", TimeStart: start, TimeEnd: start.Add(time.Hour)},
+		},
+		deleted: []int64{1, 2},
+	}
+	projects := &fakeProjectRepository{access: map[int64]projectRepoDto.ProjectAccess{
+		7: {ProjectID: 7, OwnerID: testUserID},
+	}}
+	audit := &fakeAuditLogger{}
+	uc := newTrashUsecase(entries, projects, audit)
+
+	// Чужая запись в корзине не видна.
+	if err := uc.RestoreEntry(context.Background(), 2, testUserID, WriteOptions{}); !errors.Is(err, ErrEntryNotFound) {
+		t.Errorf("restore of another user's entry: error %v, want %v", err, ErrEntryNotFound)
+	}
+
+	if err := uc.RestoreEntry(context.Background(), 1, testUserID, WriteOptions{}); err != nil {
+		t.Fatalf("restore entry: %v", err)
+	}
+	if len(entries.deleted) != 1 || entries.deleted[0] != 2 {
+		t.Errorf("entries in trash %v, want [2]", entries.deleted)
+	}
+	if len(audit.events) != 1 || audit.events[0].Action != auditUC.ActionRestore || audit.events[0].After == nil {
+		t.Errorf("audit events %+v, want one restore with the restored entry", audit.events)
+	}
+
+	// Запись не в корзине восстановить нельзя.
+	if err := uc.RestoreEntry(context.Background(), 1, testUserID, WriteOptions{}); !errors.Is(err, ErrEntryNotFound) {
+		t.Errorf("second restore: error %v, want %v", err, ErrEntryNotFound)
+	}
+}
+
+func TestRestoreEntryOfDeletedProject(t *testing.T) {
+	start := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	entries := &fakeRepository{
+		entries: []repo.Entry{{ID: 1, UserID: testUserID, ProjectID: 7, TimeStart: start, TimeEnd: start.Add(time.Hour)}},
+		deleted: []int64{1},
+	}
+	// Проект записи тоже в корзине: доступ к нему не находится.
+	audit := &fakeAuditLogger{}
+	uc := newTrashUsecase(entries, &fakeProjectRepository{}, audit)
+
+	err := uc.RestoreEntry(context.Background(), 1, testUserID, WriteOptions{})
+	if !errors.Is(err, ErrProjectDeleted) {
+		t.Fatalf("restore entry: error %v, want %v", err, ErrProjectDeleted)
+	}
+	if len(entries.deleted) != 1 || len(audit.events) != 0 {
+		t.Errorf("entry restored without its project: trash %v, audit %+v", entries.deleted, audit.events)
+	}
+}
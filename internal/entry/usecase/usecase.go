@@ -2,83 +2,541 @@ package usecase
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"time"
 
+	accountRepoDto "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/account/repository"
+	auditUC "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/audit/usecase"
 	repo "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/entry/repository"
+	periodLockRepoDto "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/periodlock/repository"
+	projectRepoDto "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/project/repository"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/roles"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/tracing"
 	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/utils"
 )
 
-var ErrEntryNotFound = errors.New("entry not found")
+var (
+	ErrEntryNotFound   = errors.New("entry not found")
+	ErrProjectNotFound = errors.New("project not found")
+	ErrForbidden       = errors.New("forbidden")
+	ErrEntryReadOnly   = errors.New("entry is read-only: timesheet for the week is approved")
+	ErrPeriodLocked    = errors.New("entry is in a locked period")
+	ErrProjectDeleted  = errors.New("project of the entry is deleted")
+)
+
+// Действия с записью для журнала изменений в заблокированном периоде.
+const (
+	actionCreate  = "create"
+	actionUpdate  = "update"
+	actionDelete  = "delete"
+	actionRestore = "restore"
+)
 
 type repository interface {
 	CreateEntry(ctx context.Context, entry repo.Entry) (int64, error)
 	GetUserEntries(ctx context.Context, userID int64) ([]repo.Entry, error)
 	GetUserEntriesForInterval(ctx context.Context, userID int64, start time.Time, end time.Time) ([]repo.Entry, error)
+	GetEntry(ctx context.Context, entryID int64) (repo.Entry, error)
+	UpdateEntry(ctx context.Context, entry repo.Entry) error
+	DeleteEntry(ctx context.Context, entryID int64, userID int64) error
+	GetDeletedEntry(ctx context.Context, entryID int64) (repo.Entry, error)
+	RestoreEntry(ctx context.Context, entryID int64, userID int64) error
 
 	GetProjectsInfo(ctx context.Context, projectIDs []int64) ([]repo.ProjectInfo, error)
+	StreamUserEntries(ctx context.Context, filter repo.ExportFilter, fn func(repo.ExportEntry) error) error
+	ImportEntries(ctx context.Context, userID int64, entries []repo.ImportEntry) (repo.ImportedEntries, error)
+	GetImportedExternalIDs(ctx context.Context, userID int64, source string, externalIDs []string) (map[string]struct{}, error)
+}
+
+type projectRepository interface {
+	GetProjectAccess(ctx context.Context, projectID, userID int64) (projectRepoDto.ProjectAccess, error)
+	GetProjectByName(ctx context.Context, userID int64, projectName string) (projectRepoDto.Project, error)
+}
+
+type timesheetRepository interface {
+	IsWeekApproved(ctx context.Context, userID, workspaceID int64, weekStart time.Time) (bool, error)
+}
+
+type periodLockRepository interface {
+	GetLock(ctx context.Context, workspaceID int64) (periodLockRepoDto.Lock, error)
+	CreateOverride(ctx context.Context, override periodLockRepoDto.Override) error
+}
+
+type userRepository interface {
+	GetProfile(ctx context.Context, userID int64) (accountRepoDto.Profile, error)
+}
+
+type txManager interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type goalTracker interface {
+	CheckAchievements(ctx context.Context, userID, projectID int64) error
+}
+
+type auditLogger interface {
+	Record(ctx context.Context, event auditUC.Event) error
+}
+
+type statsCache interface {
+	InvalidateProject(ctx context.Context, userID, projectID int64)
+	InvalidateUser(ctx context.Context, userID int64)
 }
 
 type Usecase struct {
-	repository repository
+	repository           repository
+	projectRepository    projectRepository
+	timesheetRepository  timesheetRepository
+	periodLockRepository periodLockRepository
+	auditLogger          auditLogger
+	userRepository       userRepository
+	goalTracker          goalTracker
+	txManager            txManager
+	statsCache           statsCache
 }
 
-func NewUsecase(repository repository) *Usecase {
+func NewUsecase(
+	repository repository,
+	projectRepository projectRepository,
+	timesheetRepository timesheetRepository,
+	periodLockRepository periodLockRepository,
+	auditLogger auditLogger,
+	userRepository userRepository,
+	goalTracker goalTracker,
+	txManager txManager,
+	statsCache statsCache,
+) *Usecase {
 	return &Usecase{
-		repository: repository,
+		repository:           repository,
+		projectRepository:    projectRepository,
+		timesheetRepository:  timesheetRepository,
+		periodLockRepository: periodLockRepository,
+		auditLogger:          auditLogger,
+		userRepository:       userRepository,
+		goalTracker:          goalTracker,
+		txManager:            txManager,
+		statsCache:           statsCache,
+	}
+}
+
+// writeCheck результат проверки прав на изменение записи.
+type writeCheck struct {
+	// Пространство проекта записи, 0 для личного проекта.
+	workspaceID int64
+	// Администратор обходит блокировку периода.
+	lockOverridden bool
+}
+
+// CreateEntry создает запись времени. Писать время в проект пространства
+// могут участники с ролью не ниже member.
+func (u *Usecase) CreateEntry(ctx context.Context, entry Entry, opts WriteOptions) (int64, error) {
+	ctx, span := tracing.Start(ctx, "entry.CreateEntry")
+	defer span.End()
+
+	check, err := u.checkWritable(ctx, entry, opts)
+	if err != nil {
+		return 0, err
+	}
+
+	// Запись, журнал и outbox сохраняются атомарно.
+	err = u.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		id, err := u.repository.CreateEntry(ctx, convertToRepoEntry(entry))
+		if err != nil {
+			return fmt.Errorf("repo create entry: %w", err)
+		}
+		entry.ID = id
+
+		err = u.recordLockOverride(ctx, check, entry.UserID, id, actionCreate, opts)
+		if err != nil {
+			return err
+		}
+
+		err = u.recordAudit(ctx, check.workspaceID, entry.UserID, id, auditUC.ActionCreate, nil, &entry)
+		if err != nil {
+			return err
+		}
+
+		return u.checkAchievements(ctx, entry.UserID, entry.ProjectID)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	u.statsCache.InvalidateProject(ctx, entry.UserID, entry.ProjectID)
+
+	return entry.ID, nil
+}
+
+// UpdateEntry изменяет запись пользователя. Запись нельзя изменить,
+// если старая или новая неделя записи уже утверждена в табеле
+// или запись попадает в заблокированный период.
+func (u *Usecase) UpdateEntry(ctx context.Context, entry Entry, opts WriteOptions) error {
+	ctx, span := tracing.Start(ctx, "entry.UpdateEntry")
+	defer span.End()
+
+	oldEntry, err := u.getUserEntry(ctx, entry.ID, entry.UserID)
+	if err != nil {
+		return err
+	}
+
+	oldCheck, err := u.checkWritable(ctx, oldEntry, opts)
+	if err != nil {
+		return err
+	}
+
+	check, err := u.checkWritable(ctx, entry, opts)
+	if err != nil {
+		return err
+	}
+
+	err = u.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		err := u.repository.UpdateEntry(ctx, convertToRepoEntry(entry))
+		if err != nil {
+			if errors.Is(err, repo.ErrEntryNotFound) {
+				return ErrEntryNotFound
+			}
+			return fmt.Errorf("repo update entry: %w", err)
+		}
+
+		// Запись могли перенести между проектами разных пространств.
+		if oldCheck != check {
+			err = u.recordLockOverride(ctx, oldCheck, entry.UserID, entry.ID, actionUpdate, opts)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = u.recordLockOverride(ctx, check, entry.UserID, entry.ID, actionUpdate, opts)
+		if err != nil {
+			return err
+		}
+
+		// Если запись вынесли из пространства в личный проект, событие остается в истории пространства.
+		auditWorkspaceID := check.workspaceID
+		if auditWorkspaceID == 0 {
+			auditWorkspaceID = oldCheck.workspaceID
+		}
+
+		err = u.recordAudit(ctx, auditWorkspaceID, entry.UserID, entry.ID, auditUC.ActionUpdate, &oldEntry, &entry)
+		if err != nil {
+			return err
+		}
+
+		return u.checkAchievements(ctx, entry.UserID, entry.ProjectID)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Запись могли перенести в другой проект, меняется статистика обоих.
+	u.statsCache.InvalidateProject(ctx, entry.UserID, oldEntry.ProjectID)
+	if entry.ProjectID != oldEntry.ProjectID {
+		u.statsCache.InvalidateProject(ctx, entry.UserID, entry.ProjectID)
+	}
+
+	return nil
+}
+
+// DeleteEntry переносит запись пользователя в корзину.
+func (u *Usecase) DeleteEntry(ctx context.Context, entryID int64, userID int64, opts WriteOptions) error {
+	ctx, span := tracing.Start(ctx, "entry.DeleteEntry")
+	defer span.End()
+
+	entry, err := u.getUserEntry(ctx, entryID, userID)
+	if err != nil {
+		return err
+	}
+
+	check, err := u.checkWritable(ctx, entry, opts)
+	if err != nil {
+		return err
+	}
+
+	err = u.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		err := u.repository.DeleteEntry(ctx, entryID, userID)
+		if err != nil {
+			if errors.Is(err, repo.ErrEntryNotFound) {
+				return ErrEntryNotFound
+			}
+			return fmt.Errorf("repo delete entry: %w", err)
+		}
+
+		err = u.recordLockOverride(ctx, check, userID, entryID, actionDelete, opts)
+		if err != nil {
+			return err
+		}
+
+		return u.recordAudit(ctx, check.workspaceID, userID, entryID, auditUC.ActionDelete, &entry, nil)
+	})
+	if err != nil {
+		return err
 	}
+
+	u.statsCache.InvalidateProject(ctx, userID, entry.ProjectID)
+
+	return nil
 }
 
-func (u *Usecase) CreateEntry(ctx context.Context, entry Entry) (int64, error) {
-	id, err := u.repository.CreateEntry(ctx, convertToRepoEntry(entry))
+// RestoreEntry возвращает запись пользователя из корзины. Проверки те же, что при создании:
+// восстановить запись в утвержденную неделю или заблокированный период нельзя.
+func (u *Usecase) RestoreEntry(ctx context.Context, entryID int64, userID int64, opts WriteOptions) error {
+	ctx, span := tracing.Start(ctx, "entry.RestoreEntry")
+	defer span.End()
+
+	repoEntry, err := u.repository.GetDeletedEntry(ctx, entryID)
+	if err != nil {
+		if errors.Is(err, repo.ErrEntryNotFound) {
+			return ErrEntryNotFound
+		}
+		return fmt.Errorf("repo get deleted entry: %w", err)
+	}
+
+	if repoEntry.UserID != userID {
+		return ErrEntryNotFound
+	}
+	entry := convertToEntry(repoEntry)
+
+	check, err := u.checkWritable(ctx, entry, opts)
+	if err != nil {
+		// Проект записи тоже в корзине, сначала нужно восстановить его.
+		if errors.Is(err, ErrProjectNotFound) {
+			return ErrProjectDeleted
+		}
+		return err
+	}
+
+	err = u.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		err := u.repository.RestoreEntry(ctx, entryID, userID)
+		if err != nil {
+			if errors.Is(err, repo.ErrEntryNotFound) {
+				return ErrEntryNotFound
+			}
+			return fmt.Errorf("repo restore entry: %w", err)
+		}
+
+		err = u.recordLockOverride(ctx, check, userID, entryID, actionRestore, opts)
+		if err != nil {
+			return err
+		}
+
+		err = u.recordAudit(ctx, check.workspaceID, userID, entryID, auditUC.ActionRestore, nil, &entry)
+		if err != nil {
+			return err
+		}
 
+		return u.checkAchievements(ctx, userID, entry.ProjectID)
+	})
 	if err != nil {
-		return 0, fmt.Errorf("repo create entry: %v", err)
+		return err
 	}
 
-	return id, err
+	u.statsCache.InvalidateProject(ctx, userID, entry.ProjectID)
+
+	return nil
 }
 
 func (u *Usecase) GetUserEntries(ctx context.Context, userID int64) ([]Entry, error) {
+	ctx, span := tracing.Start(ctx, "entry.GetUserEntries")
+	defer span.End()
+
 	repoEntries, err := u.repository.GetUserEntries(ctx, userID)
 	if err != nil {
 		if errors.Is(err, repo.ErrEntryNotFound) {
 			return []Entry{}, nil
 		}
-		return nil, fmt.Errorf("repo get user entries: %v", err)
+		return nil, fmt.Errorf("repo get user entries: %w", err)
 	}
 
 	entries := convertToEntries(repoEntries)
 
 	err = u.enrichEntries(ctx, entries)
 	if err != nil {
-		return nil, fmt.Errorf("enrich parcels: %v", err)
+		return nil, fmt.Errorf("enrich parcels: %w", err)
 	}
 
 	return entries, nil
 }
 
 func (u *Usecase) GetUserEntriesForDay(ctx context.Context, userID int64, date time.Time) ([]Entry, error) {
+	ctx, span := tracing.Start(ctx, "entry.GetUserEntriesForDay")
+	defer span.End()
+
 	startDay, endDay := utils.GetDayInterval(date)
 	repoEntries, err := u.repository.GetUserEntriesForInterval(ctx, userID, startDay, endDay)
 	if err != nil {
 		if errors.Is(err, repo.ErrEntryNotFound) {
 			return []Entry{}, nil
 		}
-		return nil, fmt.Errorf("repo get user entries for day: %v", err)
+		return nil, fmt.Errorf("repo get user entries for day: %w", err)
 	}
 
 	entries := convertToEntries(repoEntries)
 
 	err = u.enrichEntries(ctx, entries)
 	if err != nil {
-		return nil, fmt.Errorf("enrich parcels: %v", err)
+		return nil, fmt.Errorf("enrich parcels: %w", err)
 	}
 
 	return entries, nil
 }
 
+// ExportEntries построчно передает в fn записи пользователя для выгрузки.
+func (u *Usecase) ExportEntries(ctx context.Context, filter ExportFilter, fn func(ExportEntry) error) error {
+	ctx, span := tracing.Start(ctx, "entry.ExportEntries")
+	defer span.End()
+
+	repoFilter := repo.ExportFilter{
+		UserID:    filter.UserID,
+		ProjectID: filter.ProjectID,
+		From:      filter.From,
+		To:        filter.To,
+	}
+
+	err := u.repository.StreamUserEntries(ctx, repoFilter, func(e repo.ExportEntry) error {
+		return fn(ExportEntry{
+			ProjectName: e.ProjectName,
+			Name:        e.Name,
+			TimeStart:   e.TimeStart,
+			TimeEnd:     e.TimeEnd,
+			Duration:    e.TimeEnd.Sub(e.TimeStart),
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("repo stream user entries: %w", err)
+	}
+
+	return nil
+}
+
+// getUserEntry возвращает запись, если она принадлежит пользователю.
+func (u *Usecase) getUserEntry(ctx context.Context, entryID, userID int64) (Entry, error) {
+	entry, err := u.repository.GetEntry(ctx, entryID)
+	if err != nil {
+		if errors.Is(err, repo.ErrEntryNotFound) {
+			return Entry{}, ErrEntryNotFound
+		}
+		return Entry{}, fmt.Errorf("repo get entry: %w", err)
+	}
+
+	if entry.UserID != userID {
+		return Entry{}, ErrEntryNotFound
+	}
+
+	return convertToEntry(entry), nil
+}
+
+// checkWritable проверяет, что пользователь может писать время в проект записи,
+// что ни одна неделя записи не утверждена в табеле пространства и что запись
+// не попадает в заблокированный период. Администратор может обойти блокировку,
+// указав причину.
+func (u *Usecase) checkWritable(ctx context.Context, entry Entry, opts WriteOptions) (writeCheck, error) {
+	access, err := u.projectRepository.GetProjectAccess(ctx, entry.ProjectID, entry.UserID)
+	if err != nil {
+		if errors.Is(err, projectRepoDto.ErrProjectNotFound) {
+			return writeCheck{}, ErrProjectNotFound
+		}
+		return writeCheck{}, fmt.Errorf("repo get project access: %w", err)
+	}
+
+	role := roles.ProjectRole(access.OwnerID, entry.UserID, access.WorkspaceID.Valid, access.MemberRole.String)
+	if !role.AtLeast(roles.Member) {
+		return writeCheck{}, ErrForbidden
+	}
+
+	// Табели и блокировки ведутся только по проектам пространств.
+	if !access.WorkspaceID.Valid {
+		return writeCheck{}, nil
+	}
+	check := writeCheck{workspaceID: access.WorkspaceID.Int64}
+
+	lastWeek := utils.GetWeekStart(entry.TimeEnd)
+	for week := utils.GetWeekStart(entry.TimeStart); !week.After(lastWeek); week = week.AddDate(0, 0, 7) {
+		approved, err := u.timesheetRepository.IsWeekApproved(ctx, entry.UserID, check.workspaceID, week)
+		if err != nil {
+			return writeCheck{}, fmt.Errorf("repo is week approved: %w", err)
+		}
+
+		if approved {
+			return writeCheck{}, ErrEntryReadOnly
+		}
+	}
+
+	lock, err := u.periodLockRepository.GetLock(ctx, check.workspaceID)
+	if err != nil {
+		if errors.Is(err, periodLockRepoDto.ErrLockNotFound) {
+			return check, nil
+		}
+		return writeCheck{}, fmt.Errorf("repo get lock: %w", err)
+	}
+
+	if !entry.TimeStart.Before(lock.LockedBefore) {
+		return check, nil
+	}
+
+	if opts.LockOverrideReason == "" || !role.AtLeast(roles.Admin) {
+		return writeCheck{}, ErrPeriodLocked
+	}
+
+	check.lockOverridden = true
+	return check, nil
+}
+
+// recordLockOverride записывает в журнал изменение записи в заблокированном периоде.
+func (u *Usecase) recordLockOverride(ctx context.Context, check writeCheck, userID, entryID int64, action string, opts WriteOptions) error {
+	if !check.lockOverridden {
+		return nil
+	}
+
+	err := u.periodLockRepository.CreateOverride(ctx, periodLockRepoDto.Override{
+		WorkspaceID: check.workspaceID,
+		UserID:      sql.NullInt64{Int64: userID, Valid: true},
+		EntryID:     entryID,
+		Action:      action,
+		Reason:      opts.LockOverrideReason,
+	})
+	if err != nil {
+		return fmt.Errorf("repo create override: %w", err)
+	}
+
+	return nil
+}
+
+// recordAudit записывает изменение записи в журнал аудита.
+func (u *Usecase) recordAudit(ctx context.Context, workspaceID, actorID, entryID int64, action string, before, after *Entry) error {
+	event := auditUC.Event{
+		ActorID:     actorID,
+		WorkspaceID: workspaceID,
+		EntityType:  auditUC.EntityEntry,
+		EntityID:    entryID,
+		Action:      action,
+	}
+	if before != nil {
+		event.Before = convertToAuditState(*before)
+	}
+	if after != nil {
+		event.After = convertToAuditState(*after)
+	}
+
+	if err := u.auditLogger.Record(ctx, event); err != nil {
+		return fmt.Errorf("audit record: %w", err)
+	}
+
+	return nil
+}
+
+// checkAchievements отмечает цели пользователя по проекту, достигнутые после записи времени.
+// Удаление записи не может привести к достижению цели, поэтому вызывается только после
+// создания, изменения, восстановления и импорта записей. Нулевой projectID — все проекты.
+func (u *Usecase) checkAchievements(ctx context.Context, userID, projectID int64) error {
+	if err := u.goalTracker.CheckAchievements(ctx, userID, projectID); err != nil {
+		return fmt.Errorf("check goal achievements: %w", err)
+	}
+
+	return nil
+}
+
 func (u *Usecase) enrichEntries(ctx context.Context, entries []Entry) error {
 	var projectIDs []int64
 	for _, e := range entries {
@@ -119,6 +577,8 @@ func convertToRepoEntry(entry Entry) repo.Entry {
 		Name:      entry.Name,
 		TimeStart: entry.TimeStart,
 		TimeEnd:   entry.TimeEnd,
+		Tags:      entry.Tags,
+		Billable:  entry.Billable,
 	}
 }
 
@@ -130,10 +590,25 @@ func convertToEntry(e repo.Entry) Entry {
 		Name:        e.Name,
 		TimeStart:   e.TimeStart,
 		TimeEnd:     e.TimeEnd,
+		Tags:        e.Tags,
+		Billable:    e.Billable,
 		ProjectName: "",
 	}
 }
 
+func convertToAuditState(entry Entry) auditUC.EntryState {
+	return auditUC.EntryState{
+		ID:        entry.ID,
+		UserID:    entry.UserID,
+		ProjectID: entry.ProjectID,
+		Name:      entry.Name,
+		TimeStart: entry.TimeStart,
+		TimeEnd:   entry.TimeEnd,
+		Tags:      entry.Tags,
+		Billable:  entry.Billable,
+	}
+}
+
 func convertToEntries(entries []repo.Entry) []Entry {
 	repoEntries := make([]Entry, 0, len(entries))
 	for _, e := range entries {
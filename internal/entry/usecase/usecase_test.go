@@ -0,0 +1,51 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	auditUC "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/audit/usecase"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/trackerimport"
+)
+
+func TestCreateEntryRecordsAuditAndChecksGoals(t *testing.T) {
+	audit := &fakeAuditLogger{}
+	goals := &fakeGoalTracker{}
+	uc := newImportUsecase(&fakeRepository{}, audit, goals)
+
+	start := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	id, err := uc.CreateEntry(context.Background(), Entry{
+		UserID:    testUserID,
+		ProjectID: 7,
+		Name:      "Landing page",
+		TimeStart: start,
+		TimeEnd:   start.Add(time.Hour),
+	}, WriteOptions{})
+	if err != nil {
+		t.Fatalf("create entry: %v", err)
+	}
+
+	if len(audit.events) != 1 || audit.events[0].Action != auditUC.ActionCreate || audit.events[0].EntityID != id {
+		t.Errorf("audit events %+v, want one create of entry %d", audit.events, id)
+	}
+	if goals.checks != 1 {
+		t.Errorf("goal achievements checked %d times, want 1", goals.checks)
+	}
+}
+
+func TestImportChecksGoalsOnce(t *testing.T) {
+	goals := &fakeGoalTracker{}
+	uc := newImportUsecase(&fakeRepository{}, &fakeAuditLogger{}, goals)
+
+	_, err := uc.ImportFromTracker(context.Background(), testUserID, trackerimport.SourceToggl,
+		strings.NewReader(togglExport), time.UTC, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if goals.checks != 1 {
+		t.Errorf("goal achievements checked %d times, want 1", goals.checks)
+	}
+}
@@ -0,0 +1,81 @@
+package export
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrUnknownLocale = errors.New("unknown locale")
+
+// Locale правила форматирования чисел и дат в выгрузке.
+type Locale struct {
+	// Раскладка даты и времени для CSV.
+	DateTimeLayout string
+	// Формат даты и времени для ячеек XLSX.
+	ExcelDateTimeFormat string
+	// Разделитель целой и дробной части.
+	DecimalSeparator string
+	// Разделитель полей CSV. Если дробная часть отделяется запятой, поля разделяются точкой с запятой.
+	CSVDelimiter rune
+}
+
+const DefaultLocale = "ru"
+
+var locales = map[string]Locale{
+	"ru": {
+		DateTimeLayout:      "02.01.2006 15:04:05",
+		ExcelDateTimeFormat: "dd.mm.yyyy hh:mm:ss",
+		DecimalSeparator:    ",",
+		CSVDelimiter:        ';',
+	},
+	"en": {
+		DateTimeLayout:      "2006-01-02 15:04:05",
+		ExcelDateTimeFormat: "yyyy-mm-dd hh:mm:ss",
+		DecimalSeparator:    ".",
+		CSVDelimiter:        ',',
+	},
+}
+
+// ParseLocale возвращает правила форматирования по коду языка: ru, en или ru-RU, en-US.
+// Пустой код означает локаль по умолчанию.
+func ParseLocale(code string) (Locale, error) {
+	if code == "" {
+		code = DefaultLocale
+	}
+
+	lang, _, _ := strings.Cut(strings.ToLower(code), "-")
+	locale, ok := locales[lang]
+	if !ok {
+		return Locale{}, ErrUnknownLocale
+	}
+
+	return locale, nil
+}
+
+// NegotiateLocale выбирает локаль по явно указанному коду, а если он пуст — по заголовку Accept-Language.
+// Неизвестный язык из заголовка заменяется локалью по умолчанию.
+func NegotiateLocale(code, acceptLanguage string) (Locale, error) {
+	if code != "" {
+		return ParseLocale(code)
+	}
+
+	tag, _, _ := strings.Cut(acceptLanguage, ",")
+	tag, _, _ = strings.Cut(tag, ";")
+
+	locale, err := ParseLocale(strings.TrimSpace(tag))
+	if err != nil {
+		return ParseLocale(DefaultLocale)
+	}
+
+	return locale, nil
+}
+
+func (l Locale) formatTime(t time.Time) string {
+	return t.Format(l.DateTimeLayout)
+}
+
+func (l Locale) formatFloat(f float64) string {
+	return strings.Replace(strconv.FormatFloat(f, 'f', 2, 64), ".", l.DecimalSeparator, 1)
+}
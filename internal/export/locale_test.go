@@ -0,0 +1,88 @@
+package export
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseLocale(t *testing.T) {
+	tests := []struct {
+		code      string
+		want      Locale
+		wantError error
+	}{
+		{code: "", want: locales["ru"]},
+		{code: "ru", want: locales["ru"]},
+		{code: "ru-RU", want: locales["ru"]},
+		{code: "en", want: locales["en"]},
+		{code: "EN-us", want: locales["en"]},
+		{code: "de", wantError: ErrUnknownLocale},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			got, err := ParseLocale(tt.code)
+			if !errors.Is(err, tt.wantError) {
+				t.Fatalf("got error %v, want %v", err, tt.wantError)
+			}
+			if got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNegotiateLocale(t *testing.T) {
+	tests := []struct {
+		name           string
+		code           string
+		acceptLanguage string
+		want           Locale
+		wantError      error
+	}{
+		{name: "default", want: locales["ru"]},
+		{name: "header", acceptLanguage: "en-US,en;q=0.9,ru;q=0.8", want: locales["en"]},
+		{name: "header with weight", acceptLanguage: "en;q=0.9", want: locales["en"]},
+		{name: "unknown header language", acceptLanguage: "de-DE,de", want: locales["ru"]},
+		{name: "code wins over header", code: "ru", acceptLanguage: "en-US", want: locales["ru"]},
+		{name: "unknown code", code: "de", acceptLanguage: "en-US", wantError: ErrUnknownLocale},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NegotiateLocale(tt.code, tt.acceptLanguage)
+			if !errors.Is(err, tt.wantError) {
+				t.Fatalf("got error %v, want %v", err, tt.wantError)
+			}
+			if got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocaleFormat(t *testing.T) {
+	at := time.Date(2024, 3, 5, 14, 7, 9, 0, time.UTC)
+
+	tests := []struct {
+		code      string
+		wantTime  string
+		wantFloat string
+	}{
+		{code: "ru", wantTime: "05.03.2024 14:07:09", wantFloat: "1234,50"},
+		{code: "en", wantTime: "2024-03-05 14:07:09", wantFloat: "1234.50"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			locale := locales[tt.code]
+			if got := locale.formatTime(at); got != tt.wantTime {
+				t.Errorf("formatTime: got %q, want %q", got, tt.wantTime)
+			}
+			if got := locale.formatFloat(1234.5); got != tt.wantFloat {
+				t.Errorf("formatFloat: got %q, want %q", got, tt.wantFloat)
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package export
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Форматы выгрузки.
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+var ErrUnknownFormat = errors.New("unknown export format")
+
+// Writer построчно пишет таблицу. Поддерживаются значения string, int64, float64 и time.Time.
+type Writer interface {
+	WriteRow(values ...interface{}) error
+	// Close дописывает буферизованные данные. Writer после Close использовать нельзя.
+	Close() error
+}
+
+// NewWriter создает Writer для формата csv или xlsx.
+func NewWriter(w io.Writer, format string, locale Locale) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w, locale), nil
+	case FormatXLSX:
+		return newXLSXWriter(w, locale)
+	default:
+		return nil, ErrUnknownFormat
+	}
+}
+
+// ContentType возвращает MIME-тип файла выгрузки.
+func ContentType(format string) string {
+	if format == FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+
+	return "text/csv; charset=utf-8"
+}
+
+type csvWriter struct {
+	w      *csv.Writer
+	locale Locale
+	record []string
+}
+
+func newCSVWriter(w io.Writer, locale Locale) *csvWriter {
+	cw := csv.NewWriter(w)
+	cw.Comma = locale.CSVDelimiter
+
+	return &csvWriter{w: cw, locale: locale}
+}
+
+func (c *csvWriter) WriteRow(values ...interface{}) error {
+	c.record = c.record[:0]
+	for _, v := range values {
+		switch v := v.(type) {
+		case string:
+			c.record = append(c.record, v)
+		case int64:
+			c.record = append(c.record, strconv.FormatInt(v, 10))
+		case float64:
+			c.record = append(c.record, c.locale.formatFloat(v))
+		case time.Time:
+			c.record = append(c.record, c.locale.formatTime(v))
+		default:
+			return fmt.Errorf("unsupported value type %T", v)
+		}
+	}
+
+	if err := c.w.Write(c.record); err != nil {
+		return fmt.Errorf("csv write: %v", err)
+	}
+
+	return nil
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	if err := c.w.Error(); err != nil {
+		return fmt.Errorf("csv flush: %v", err)
+	}
+
+	return nil
+}
+
+// xlsxWriter пишет лист через потоковый writer excelize: строки сбрасываются
+// во временный файл, а не копятся в памяти.
+type xlsxWriter struct {
+	w io.Writer
+
+	file   *excelize.File
+	stream *excelize.StreamWriter
+	row    int
+
+	dateStyle  int
+	floatStyle int
+}
+
+const xlsxSheet = "Sheet1"
+
+func newXLSXWriter(w io.Writer, locale Locale) (*xlsxWriter, error) {
+	file := excelize.NewFile()
+
+	dateFormat := locale.ExcelDateTimeFormat
+	dateStyle, err := file.NewStyle(&excelize.Style{CustomNumFmt: &dateFormat})
+	if err != nil {
+		return nil, fmt.Errorf("new date style: %v", err)
+	}
+
+	floatFormat := "0.00"
+	floatStyle, err := file.NewStyle(&excelize.Style{CustomNumFmt: &floatFormat})
+	if err != nil {
+		return nil, fmt.Errorf("new float style: %v", err)
+	}
+
+	stream, err := file.NewStreamWriter(xlsxSheet)
+	if err != nil {
+		return nil, fmt.Errorf("new stream writer: %v", err)
+	}
+
+	return &xlsxWriter{
+		w:          w,
+		file:       file,
+		stream:     stream,
+		dateStyle:  dateStyle,
+		floatStyle: floatStyle,
+	}, nil
+}
+
+func (x *xlsxWriter) WriteRow(values ...interface{}) error {
+	x.row++
+
+	cells := make([]interface{}, 0, len(values))
+	for _, v := range values {
+		switch v := v.(type) {
+		case string, int64:
+			cells = append(cells, v)
+		case float64:
+			cells = append(cells, excelize.Cell{StyleID: x.floatStyle, Value: v})
+		case time.Time:
+			cells = append(cells, excelize.Cell{StyleID: x.dateStyle, Value: v})
+		default:
+			return fmt.Errorf("unsupported value type %T", v)
+		}
+	}
+
+	cell, err := excelize.CoordinatesToCellName(1, x.row)
+	if err != nil {
+		return fmt.Errorf("cell name: %v", err)
+	}
+
+	if err = x.stream.SetRow(cell, cells); err != nil {
+		return fmt.Errorf("set row: %v", err)
+	}
+
+	return nil
+}
+
+func (x *xlsxWriter) Close() error {
+	defer func() {
+		_ = x.file.Close()
+	}()
+
+	if err := x.stream.Flush(); err != nil {
+		return fmt.Errorf("flush: %v", err)
+	}
+
+	if err := x.file.Write(x.w); err != nil {
+		return fmt.Errorf("write: %v", err)
+	}
+
+	return nil
+}
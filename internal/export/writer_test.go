@@ -0,0 +1,208 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+var (
+	testHeader = []interface{}{"id", "project", "description", "start", "hours"}
+	testRows   = [][]interface{}{
+		{int64(1), "Backend", "Code review", time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC), 1.5},
+		{int64(2), "Backend", "Fix; then deploy, \"carefully\"", time.Date(2024, 3, 4, 11, 30, 0, 0, time.UTC), 0.25},
+		{int64(3), "Мобильное приложение", "Созвон\nс заказчиком", time.Date(2024, 3, 5, 14, 0, 0, 0, time.UTC), 2.0},
+	}
+)
+
+func writeTable(t *testing.T, format, code string) []byte {
+	t.Helper()
+
+	locale, err := ParseLocale(code)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, format, locale)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = w.WriteRow(testHeader...); err != nil {
+		t.Fatal(err)
+	}
+	for _, row := range testRows {
+		if err = w.WriteRow(row...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func TestCSVGolden(t *testing.T) {
+	for _, code := range []string{"ru", "en"} {
+		t.Run(code, func(t *testing.T) {
+			got := writeTable(t, FormatCSV, code)
+
+			golden := filepath.Join("testdata", "entries_"+code+".csv")
+			if *update {
+				if err := os.WriteFile(golden, got, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("output differs from %s:\ngot:\n%s\nwant:\n%s", golden, got, want)
+			}
+		})
+	}
+}
+
+// Дробные числа в ru пишутся с запятой, поэтому поля разделяются точкой с запятой,
+// и при чтении с тем же разделителем значения не распадаются на лишние колонки.
+func TestCSVDelimiter(t *testing.T) {
+	tests := []struct {
+		code      string
+		delimiter rune
+		wantHours []string
+	}{
+		{code: "ru", delimiter: ';', wantHours: []string{"hours", "1,50", "0,25", "2,00"}},
+		{code: "en", delimiter: ',', wantHours: []string{"hours", "1.50", "0.25", "2.00"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			r := csv.NewReader(bytes.NewReader(writeTable(t, FormatCSV, tt.code)))
+			r.Comma = tt.delimiter
+
+			records, err := r.ReadAll()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var hours []string
+			for _, record := range records {
+				if len(record) != len(testHeader) {
+					t.Fatalf("got %d fields in %q, want %d", len(record), record, len(testHeader))
+				}
+				hours = append(hours, record[4])
+			}
+			if !reflect.DeepEqual(hours, tt.wantHours) {
+				t.Errorf("got hours %q, want %q", hours, tt.wantHours)
+			}
+			if got := records[2][2]; got != testRows[1][2] {
+				t.Errorf("got description %q, want %q", got, testRows[1][2])
+			}
+		})
+	}
+}
+
+func TestXLSXReadBack(t *testing.T) {
+	tests := []struct {
+		code       string
+		dateFormat string
+		wantStart  []string
+	}{
+		{
+			code:       "ru",
+			dateFormat: "dd.mm.yyyy hh:mm:ss",
+			wantStart:  []string{"04.03.2024 09:00:00", "04.03.2024 11:30:00", "05.03.2024 14:00:00"},
+		},
+		{
+			code:       "en",
+			dateFormat: "yyyy-mm-dd hh:mm:ss",
+			wantStart:  []string{"2024-03-04 09:00:00", "2024-03-04 11:30:00", "2024-03-05 14:00:00"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			file, err := excelize.OpenReader(bytes.NewReader(writeTable(t, FormatXLSX, tt.code)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer file.Close()
+
+			rows, err := file.GetRows(xlsxSheet)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := [][]string{
+				{"id", "project", "description", "start", "hours"},
+				{"1", "Backend", "Code review", tt.wantStart[0], "1.50"},
+				{"2", "Backend", "Fix; then deploy, \"carefully\"", tt.wantStart[1], "0.25"},
+				{"3", "Мобильное приложение", "Созвон\nс заказчиком", tt.wantStart[2], "2.00"},
+			}
+			if !reflect.DeepEqual(rows, want) {
+				t.Errorf("got rows %q, want %q", rows, want)
+			}
+
+			// Даты и часы хранятся числами со стилем, а не строками, чтобы их можно было считать в таблице.
+			hours, err := file.GetCellValue(xlsxSheet, "E2", excelize.Options{RawCellValue: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if hours != "1.5" {
+				t.Errorf("got raw hours %q, want %q", hours, "1.5")
+			}
+
+			cellType, err := file.GetCellType(xlsxSheet, "D2")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cellType == excelize.CellTypeSharedString || cellType == excelize.CellTypeInlineString {
+				t.Errorf("start is written as a string")
+			}
+
+			styleID, err := file.GetCellStyle(xlsxSheet, "D2")
+			if err != nil {
+				t.Fatal(err)
+			}
+			style, err := file.GetStyle(styleID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if style.CustomNumFmt == nil || *style.CustomNumFmt != tt.dateFormat {
+				t.Errorf("got date format %v, want %q", style.CustomNumFmt, tt.dateFormat)
+			}
+		})
+	}
+}
+
+func TestWriterErrors(t *testing.T) {
+	if _, err := NewWriter(&bytes.Buffer{}, "pdf", locales["en"]); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("got error %v, want %v", err, ErrUnknownFormat)
+	}
+
+	for _, format := range []string{FormatCSV, FormatXLSX} {
+		t.Run(format, func(t *testing.T) {
+			w, err := NewWriter(&bytes.Buffer{}, format, locales["en"])
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer w.Close()
+
+			if err = w.WriteRow(true); err == nil {
+				t.Error("expected error for unsupported value type")
+			}
+		})
+	}
+}
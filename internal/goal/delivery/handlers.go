@@ -17,6 +17,8 @@ import (
 type usecase interface {
 	CreateGoal(ctx context.Context, e usecaseDto.Goal) (int64, error)
 	GetGoals(ctx context.Context, userID, projectID int64) ([]usecaseDto.Goal, error)
+	DeleteGoal(ctx context.Context, goalID, userID int64) error
+	RestoreGoal(ctx context.Context, goalID, userID int64) error
 }
 
 type Delivery struct {
@@ -38,6 +40,8 @@ func RegisterHandlers(
 
 	e.POST("/goals/create", handler.CreateGoal)
 	e.GET("/me/projects/:project_id/goals", handler.GetMyGoals)
+	e.DELETE("/goals/:id", handler.DeleteGoal)
+	e.POST("/goals/:id/restore", handler.RestoreGoal)
 }
 
 // CreateGoal godoc
@@ -48,32 +52,33 @@ func RegisterHandlers(
 // @Produce  application/json
 // @Param    goal body CreateGoalIn true "Информация о цели"
 // @Success  200 {object} CreateGoalOut "success create goal"
-// @Failure 500 {object} echo.HTTPError "internal server error"
-// @Failure 400 {object} echo.HTTPError "bad request"
-// @Failure 404 {object} echo.HTTPError "item is not found"
-// @Failure 422 {object} echo.HTTPError "unprocessable entity"
+// @Failure 500 {object} response.Error "internal server error"
+// @Failure 400 {object} response.Error "bad request"
+// @Failure 403 {object} response.Error "forbidden"
+// @Failure 404 {object} response.Error "item is not found"
+// @Failure 422 {object} response.Error "unprocessable entity"
 // @Router   /goals/create [post]
 func (d *Delivery) CreateGoal(c echo.Context) error {
-	ctx := context.Background()
+	ctx := c.Request().Context()
 
 	var in CreateGoalIn
 	err := c.Bind(&in)
 
 	if err != nil {
 		c.Logger().Errorf("bind request: %v", err)
-		return echo.NewHTTPError(http.StatusUnprocessableEntity, response.ErrorMsgsByCode[http.StatusUnprocessableEntity])
+		return response.Status(http.StatusUnprocessableEntity)
 	}
 
 	if ok, err := validator.IsRequestValid(&in); !ok {
 		c.Logger().Errorf("validation: %v", err)
-		return echo.NewHTTPError(http.StatusBadRequest, response.ErrorMsgsByCode[http.StatusBadRequest])
+		return response.ValidationError(err)
 	}
 
 	// Получаем userID (проставляется в auth мидлваре).
 	userID, ok := c.Get("user_id").(int64)
 	if !ok {
 		c.Logger().Error("can't parse context user_id")
-		return echo.NewHTTPError(http.StatusInternalServerError, response.ErrorMsgsByCode[http.StatusInternalServerError])
+		return response.Status(http.StatusInternalServerError)
 	}
 
 	goal := usecaseDto.Goal{
@@ -104,26 +109,23 @@ func (d *Delivery) CreateGoal(c echo.Context) error {
 // @Produce  	application/json
 // @Param    project_id path int true "Идентификатор проекта"
 // @Success  200 {object} []GoalOut "success get goals"
-// @Failure 500 {object} echo.HTTPError "internal server error"
-// @Failure 400 {object} echo.HTTPError "bad request"
+// @Failure 500 {object} response.Error "internal server error"
+// @Failure 400 {object} response.Error "bad request"
 // @Router   /me/projects/{project_id}/goals [get]
 func (d *Delivery) GetMyGoals(c echo.Context) error {
-	ctx := context.Background()
+	ctx := c.Request().Context()
 
 	projectIDStr := c.Param("project_id")
 	projectID, err := strconv.ParseInt(projectIDStr, 10, 64)
 	if err != nil {
 		c.Logger().Errorf("parse int: %v", err)
-		return echo.NewHTTPError(http.StatusBadRequest, response.ErrorMsgsByCode[http.StatusBadRequest])
+		return response.InvalidParam("project_id", "int")
 	}
 
 	userID, ok := c.Get("user_id").(int64)
 	if !ok {
 		c.Logger().Error("can't parse context user_id")
-		return echo.NewHTTPError(
-			http.StatusInternalServerError,
-			response.ErrorMsgsByCode[http.StatusInternalServerError],
-		)
+		return response.Status(http.StatusInternalServerError)
 	}
 
 	var goals []usecaseDto.Goal
@@ -140,19 +142,106 @@ func (d *Delivery) GetMyGoals(c echo.Context) error {
 	return c.JSON(http.StatusOK, out)
 }
 
-func handleUsecaseError(err error) *echo.HTTPError {
+// DeleteGoal godoc
+// @Summary      Удаление цели.
+// @Description  Перенос своей цели в корзину.
+// @Tags     	 goals
+// @Accept	 application/json
+// @Produce  application/json
+// @Param    id path int true "Идентификатор цели"
+// @Success  200  "success delete goal"
+// @Failure 500 {object} response.Error "internal server error"
+// @Failure 400 {object} response.Error "bad request"
+// @Failure 404 {object} response.Error "item is not found"
+// @Router   /goals/{id} [delete]
+func (d *Delivery) DeleteGoal(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	goalID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Logger().Errorf("parse int: %v", err)
+		return response.InvalidParam("id", "int")
+	}
+
+	userID, ok := c.Get("user_id").(int64)
+	if !ok {
+		c.Logger().Error("can't parse context user_id")
+		return response.Status(http.StatusInternalServerError)
+	}
+
+	err = d.usecase.DeleteGoal(ctx, goalID, userID)
+	if err != nil {
+		c.Logger().Errorf("usecase: %v", err)
+		return handleUsecaseError(err)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+// RestoreGoal godoc
+// @Summary      Восстановление цели.
+// @Description  Восстановление своей цели из корзины. Если проект цели тоже в корзине, сначала нужно восстановить проект.
+// @Tags     	 trash
+// @Accept	 application/json
+// @Produce  application/json
+// @Param    id path int true "Идентификатор цели"
+// @Success  200  "success restore goal"
+// @Failure 500 {object} response.Error "internal server error"
+// @Failure 400 {object} response.Error "bad request"
+// @Failure 403 {object} response.Error "forbidden"
+// @Failure 404 {object} response.Error "item is not found"
+// @Failure 409 {object} response.Error "conflict"
+// @Router   /goals/{id}/restore [post]
+func (d *Delivery) RestoreGoal(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	goalID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Logger().Errorf("parse int: %v", err)
+		return response.InvalidParam("id", "int")
+	}
+
+	userID, ok := c.Get("user_id").(int64)
+	if !ok {
+		c.Logger().Error("can't parse context user_id")
+		return response.Status(http.StatusInternalServerError)
+	}
+
+	err = d.usecase.RestoreGoal(ctx, goalID, userID)
+	if err != nil {
+		c.Logger().Errorf("usecase: %v", err)
+		return handleUsecaseError(err)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+func handleUsecaseError(err error) *response.Error {
+	// Запрос не уложился в таймаут или был отменен.
+	if problem := response.ContextError(err); problem != nil {
+		return problem
+	}
+
 	// Не нашли цель.
 	if errors.Is(err, usecaseDto.ErrGoalNotFound) {
-		return echo.NewHTTPError(
-			http.StatusNotFound,
-			fmt.Sprintf("%s: %s", response.ErrorMsgsByCode[http.StatusNotFound], "goal"))
+		return response.NotFound("goal")
+	}
+	if errors.Is(err, usecaseDto.ErrProjectNotFound) {
+		return response.NotFound("project")
+	}
+	// Проект цели в корзине.
+	if errors.Is(err, usecaseDto.ErrProjectDeleted) {
+		return response.NewError(
+			http.StatusConflict,
+			"project_deleted",
+			fmt.Sprintf("%s: %v", response.ErrorMsgsByCode[http.StatusConflict], err))
+	}
+	if errors.Is(err, usecaseDto.ErrForbidden) {
+		return response.Status(http.StatusForbidden)
 	}
 
 	// По дефолту пятисотим.
-	return echo.NewHTTPError(
-		http.StatusInternalServerError,
-		response.ErrorMsgsByCode[http.StatusInternalServerError],
-	)
+	return response.Status(http.StatusInternalServerError)
 }
 
 func convertFromUsecaseEntries(goals []usecaseDto.Goal) []GoalOut {
@@ -168,6 +257,7 @@ func convertFromUsecaseEntries(goals []usecaseDto.Goal) []GoalOut {
 			DateEnd:         goal.DateEnd,
 			DurationSeconds: goal.DurationSeconds,
 			Percent:         goal.Percent,
+			AchievedAt:      goal.AchievedAt,
 		}
 
 		out = append(out, g)
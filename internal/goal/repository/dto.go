@@ -1,6 +1,9 @@
 package delivery
 
-import "time"
+import (
+	"database/sql"
+	"time"
+)
 
 type Entry struct {
 	TimeStart time.Time `json:"entry_start"`
@@ -8,13 +11,14 @@ type Entry struct {
 }
 
 type Goal struct {
-	ID          int64     `db:"id"`
-	ProjectID   int64     `db:"project_id"`
-	UserID      int64     `db:"user_id"`
-	TimeSeconds int64     `db:"time_seconds"`
-	Name        string    `db:"name"`
-	DateStart   time.Time `db:"date_start"`
-	DateEnd     time.Time `db:"date_end"`
+	ID          int64        `db:"id"`
+	ProjectID   int64        `db:"project_id"`
+	UserID      int64        `db:"user_id"`
+	TimeSeconds int64        `db:"time_seconds"`
+	Name        string       `db:"name"`
+	DateStart   time.Time    `db:"date_start"`
+	DateEnd     time.Time    `db:"date_end"`
+	AchievedAt  sql.NullTime `db:"achieved_at"`
 
 	Entries []Entry `db:"entries"`
 }
@@ -2,11 +2,14 @@ package delivery
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
 
 	"github.com/jmoiron/sqlx"
+
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/transaction"
 )
 
 var (
@@ -27,7 +30,12 @@ func NewRepository(db *sqlx.DB) *Repository {
 	}
 }
 
-func (r *Repository) CreateGoal(_ context.Context, goal Goal) (int64, error) {
+// Close закрывает пул соединений с базой.
+func (r *Repository) Close() error {
+	return r.close()
+}
+
+func (r *Repository) CreateGoal(ctx context.Context, goal Goal) (int64, error) {
 	query := `INSERT INTO goals
 				(
 				 	project_id,
@@ -39,7 +47,7 @@ func (r *Repository) CreateGoal(_ context.Context, goal Goal) (int64, error) {
 				) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id;`
 
 	var id int64
-	err := r.db.QueryRow(
+	err := transaction.GetExecutor(ctx, r.db).QueryRowContext(ctx,
 		query,
 		goal.ProjectID,
 		goal.UserID,
@@ -50,14 +58,14 @@ func (r *Repository) CreateGoal(_ context.Context, goal Goal) (int64, error) {
 	).Scan(&id)
 
 	if err != nil {
-		return 0, fmt.Errorf("exec: %v", err)
+		return 0, fmt.Errorf("exec: %w", err)
 	}
 
 	return id, nil
 }
 
-func (r *Repository) GetGoals(_ context.Context, userID, projectID int64) ([]Goal, error) {
-	rows, err := r.db.Query(`
+func (r *Repository) GetGoals(ctx context.Context, userID, projectID int64) ([]Goal, error) {
+	rows, err := r.db.QueryContext(ctx, `
 SELECT g.id,
        g.project_id,
        g.user_id,
@@ -65,6 +73,7 @@ SELECT g.id,
        g.time_seconds,
        g.date_start,
        g.date_end,
+       g.achieved_at,
        COALESCE((SELECT JSON_AGG(
                        JSON_BUILD_OBJECT(
                                'entry_start', e.time_start::timestamptz,
@@ -73,11 +82,13 @@ SELECT g.id,
                )
         FROM entries e
         WHERE e.project_id = g.project_id
+          AND e.user_id = g.user_id
+          AND e.deleted_at IS NULL
           AND (e.time_end::date <= g.date_end AND e.time_end::date >= g.date_start OR
                e.time_start::date >= g.date_start AND e.time_start::date <= g.date_end OR
                e.time_start::date < g.date_start AND e.time_end::date > g.date_end)), JSON_ARRAY()) AS entries
 FROM goals g
-WHERE g.user_id = $1 AND g.project_id = $2
+WHERE g.user_id = $1 AND g.project_id = $2 AND g.deleted_at IS NULL
 ORDER BY g.date_start`, userID, projectID)
 
 	if err != nil {
@@ -101,6 +112,7 @@ ORDER BY g.date_start`, userID, projectID)
 			&goal.TimeSeconds,
 			&goal.DateStart,
 			&goal.DateEnd,
+			&goal.AchievedAt,
 			&entriesJSON,
 		); err != nil {
 			return nil, fmt.Errorf("scan: %w", rows.Err())
@@ -124,3 +136,150 @@ ORDER BY g.date_start`, userID, projectID)
 
 	return goals, nil
 }
+
+// GetGoal возвращает цель без учета записей времени.
+func (r *Repository) GetGoal(ctx context.Context, goalID int64) (Goal, error) {
+	return r.getGoal(ctx, `SELECT
+			id,
+			project_id,
+			user_id,
+			name,
+			time_seconds,
+			date_start,
+			date_end
+		FROM goals
+		WHERE id = $1 AND deleted_at IS NULL`, goalID)
+}
+
+// GetDeletedGoal возвращает цель из корзины.
+func (r *Repository) GetDeletedGoal(ctx context.Context, goalID int64) (Goal, error) {
+	return r.getGoal(ctx, `SELECT
+			id,
+			project_id,
+			user_id,
+			name,
+			time_seconds,
+			date_start,
+			date_end
+		FROM goals
+		WHERE id = $1 AND deleted_at IS NOT NULL`, goalID)
+}
+
+// DeleteGoal переносит цель в корзину.
+func (r *Repository) DeleteGoal(ctx context.Context, goalID, userID int64) error {
+	return r.execGoal(ctx,
+		`UPDATE goals
+		SET deleted_at = now()
+		WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL`, goalID, userID)
+}
+
+// RestoreGoal возвращает цель из корзины.
+func (r *Repository) RestoreGoal(ctx context.Context, goalID, userID int64) error {
+	return r.execGoal(ctx,
+		`UPDATE goals
+		SET deleted_at = NULL
+		WHERE id = $1 AND user_id = $2 AND deleted_at IS NOT NULL`, goalID, userID)
+}
+
+// MarkAchievedGoals отмечает достигнутыми цели пользователя, время по которым набрано впервые,
+// и возвращает их. Нулевой projectID означает все проекты пользователя.
+func (r *Repository) MarkAchievedGoals(ctx context.Context, userID, projectID int64) ([]Goal, error) {
+	rows, err := transaction.GetExecutor(ctx, r.db).QueryContext(ctx,
+		`UPDATE goals g
+		SET achieved_at = now()
+		WHERE g.user_id = $1
+		  AND ($2 = 0 OR g.project_id = $2)
+		  AND g.deleted_at IS NULL
+		  AND g.achieved_at IS NULL
+		  AND (SELECT COALESCE(SUM(EXTRACT(EPOCH FROM
+		                LEAST(e.time_end, g.date_end) - GREATEST(e.time_start, g.date_start))), 0)
+		       FROM entries e
+		       WHERE e.project_id = g.project_id
+		         AND e.user_id = g.user_id
+		         AND e.deleted_at IS NULL
+		         AND e.time_start < g.date_end
+		         AND e.time_end > g.date_start) >= g.time_seconds
+		RETURNING
+			g.id,
+			g.project_id,
+			g.user_id,
+			g.name,
+			g.time_seconds,
+			g.date_start,
+			g.date_end,
+			g.achieved_at`, userID, projectID)
+
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var goals []Goal
+	for rows.Next() {
+		var goal Goal
+		if err = rows.Scan(
+			&goal.ID,
+			&goal.ProjectID,
+			&goal.UserID,
+			&goal.Name,
+			&goal.TimeSeconds,
+			&goal.DateStart,
+			&goal.DateEnd,
+			&goal.AchievedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan: %w", err)
+		}
+
+		goals = append(goals, goal)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows: %w", err)
+	}
+
+	return goals, nil
+}
+
+func (r *Repository) getGoal(ctx context.Context, query string, goalID int64) (Goal, error) {
+	var goal Goal
+	err := r.db.QueryRowContext(ctx, query, goalID).Scan(
+		&goal.ID,
+		&goal.ProjectID,
+		&goal.UserID,
+		&goal.Name,
+		&goal.TimeSeconds,
+		&goal.DateStart,
+		&goal.DateEnd,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Goal{}, ErrGoalNotFound
+		}
+
+		return Goal{}, fmt.Errorf("scan: %w", err)
+	}
+
+	return goal, nil
+}
+
+func (r *Repository) execGoal(ctx context.Context, query string, goalID, userID int64) error {
+	res, err := transaction.GetExecutor(ctx, r.db).ExecContext(ctx, query, goalID, userID)
+	if err != nil {
+		return fmt.Errorf("exec: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("rows affected: %w", err)
+	}
+
+	if affected == 0 {
+		return ErrGoalNotFound
+	}
+
+	return nil
+}
@@ -10,6 +10,8 @@ type Goal struct {
 	Name        string
 	DateStart   time.Time
 	DateEnd     time.Time
+	// Когда цель впервые достигнута, nil — еще не достигнута.
+	AchievedAt *time.Time
 
 	DurationSeconds float64
 	Percent         float64
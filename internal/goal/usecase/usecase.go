@@ -6,44 +6,304 @@ import (
 	"fmt"
 	"time"
 
+	auditUC "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/audit/usecase"
 	repo "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/goal/repository"
+	projectRepoDto "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/project/repository"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/roles"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/statscache"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/tracing"
 )
 
-var ErrGoalNotFound = errors.New("goal not found")
+var (
+	ErrGoalNotFound    = errors.New("goal not found")
+	ErrProjectNotFound = errors.New("project not found")
+	ErrForbidden       = errors.New("forbidden")
+	ErrProjectDeleted  = errors.New("project of the goal is deleted")
+)
 
 type repository interface {
 	CreateGoal(ctx context.Context, goal repo.Goal) (int64, error)
 	GetGoals(ctx context.Context, userID, projectID int64) ([]repo.Goal, error)
+	GetGoal(ctx context.Context, goalID int64) (repo.Goal, error)
+	GetDeletedGoal(ctx context.Context, goalID int64) (repo.Goal, error)
+	DeleteGoal(ctx context.Context, goalID, userID int64) error
+	RestoreGoal(ctx context.Context, goalID, userID int64) error
+	MarkAchievedGoals(ctx context.Context, userID, projectID int64) ([]repo.Goal, error)
+}
+
+type projectRepository interface {
+	GetProjectAccess(ctx context.Context, projectID, userID int64) (projectRepoDto.ProjectAccess, error)
+}
+
+type auditLogger interface {
+	Record(ctx context.Context, event auditUC.Event) error
+}
+
+type txManager interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type statsCache interface {
+	Fetch(ctx context.Context, key statscache.Key, dst interface{}, load func(ctx context.Context) error) error
+	InvalidateGoals(ctx context.Context, userID, projectID int64)
 }
 
 type Usecase struct {
-	repository repository
+	repository        repository
+	projectRepository projectRepository
+	auditLogger       auditLogger
+	txManager         txManager
+	statsCache        statsCache
 }
 
-func NewUsecase(repository repository) *Usecase {
+func NewUsecase(
+	repository repository,
+	projectRepository projectRepository,
+	auditLogger auditLogger,
+	txManager txManager,
+	statsCache statsCache,
+) *Usecase {
 	return &Usecase{
-		repository: repository,
+		repository:        repository,
+		projectRepository: projectRepository,
+		auditLogger:       auditLogger,
+		txManager:         txManager,
+		statsCache:        statsCache,
 	}
 }
 
+// CreateGoal создает личную цель пользователя по проекту.
+// Ставить цели по проекту пространства могут участники с ролью не ниже member.
 func (u *Usecase) CreateGoal(ctx context.Context, goal Goal) (int64, error) {
-	id, err := u.repository.CreateGoal(ctx, convertToRepoGoal(goal))
+	ctx, span := tracing.Start(ctx, "goal.CreateGoal")
+	defer span.End()
 
+	access, err := u.checkProjectAccess(ctx, goal.ProjectID, goal.UserID)
 	if err != nil {
-		return 0, fmt.Errorf("repo create goal: %v", err)
+		return 0, err
 	}
 
-	return id, err
+	repoGoal := convertToRepoGoal(goal)
+	err = u.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		id, err := u.repository.CreateGoal(ctx, repoGoal)
+		if err != nil {
+			return fmt.Errorf("repo create goal: %w", err)
+		}
+		repoGoal.ID = id
+
+		err = u.auditLogger.Record(ctx, auditUC.Event{
+			ActorID:     goal.UserID,
+			WorkspaceID: access.WorkspaceID.Int64,
+			EntityType:  auditUC.EntityGoal,
+			EntityID:    id,
+			Action:      auditUC.ActionCreate,
+			After:       convertToAuditState(repoGoal),
+		})
+		if err != nil {
+			return fmt.Errorf("audit record: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	u.statsCache.InvalidateGoals(ctx, goal.UserID, goal.ProjectID)
+
+	return repoGoal.ID, nil
 }
 
+// DeleteGoal переносит цель пользователя в корзину.
+func (u *Usecase) DeleteGoal(ctx context.Context, goalID, userID int64) error {
+	ctx, span := tracing.Start(ctx, "goal.DeleteGoal")
+	defer span.End()
+
+	goal, err := u.repository.GetGoal(ctx, goalID)
+	if err != nil {
+		if errors.Is(err, repo.ErrGoalNotFound) {
+			return ErrGoalNotFound
+		}
+		return fmt.Errorf("repo get goal: %w", err)
+	}
+
+	if goal.UserID != userID {
+		return ErrGoalNotFound
+	}
+
+	access, err := u.projectRepository.GetProjectAccess(ctx, goal.ProjectID, userID)
+	if err != nil && !errors.Is(err, projectRepoDto.ErrProjectNotFound) {
+		return fmt.Errorf("repo get project access: %w", err)
+	}
+
+	err = u.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		err := u.repository.DeleteGoal(ctx, goalID, userID)
+		if err != nil {
+			if errors.Is(err, repo.ErrGoalNotFound) {
+				return ErrGoalNotFound
+			}
+			return fmt.Errorf("repo delete goal: %w", err)
+		}
+
+		err = u.auditLogger.Record(ctx, auditUC.Event{
+			ActorID:     userID,
+			WorkspaceID: access.WorkspaceID.Int64,
+			EntityType:  auditUC.EntityGoal,
+			EntityID:    goalID,
+			Action:      auditUC.ActionDelete,
+			Before:      convertToAuditState(goal),
+		})
+		if err != nil {
+			return fmt.Errorf("audit record: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	u.statsCache.InvalidateGoals(ctx, userID, goal.ProjectID)
+
+	return nil
+}
+
+// RestoreGoal возвращает цель пользователя из корзины.
+// Если проект цели тоже в корзине, сначала нужно восстановить проект.
+func (u *Usecase) RestoreGoal(ctx context.Context, goalID, userID int64) error {
+	ctx, span := tracing.Start(ctx, "goal.RestoreGoal")
+	defer span.End()
+
+	goal, err := u.repository.GetDeletedGoal(ctx, goalID)
+	if err != nil {
+		if errors.Is(err, repo.ErrGoalNotFound) {
+			return ErrGoalNotFound
+		}
+		return fmt.Errorf("repo get deleted goal: %w", err)
+	}
+
+	if goal.UserID != userID {
+		return ErrGoalNotFound
+	}
+
+	access, err := u.checkProjectAccess(ctx, goal.ProjectID, userID)
+	if err != nil {
+		if errors.Is(err, ErrProjectNotFound) {
+			return ErrProjectDeleted
+		}
+		return err
+	}
+
+	err = u.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		err := u.repository.RestoreGoal(ctx, goalID, userID)
+		if err != nil {
+			if errors.Is(err, repo.ErrGoalNotFound) {
+				return ErrGoalNotFound
+			}
+			return fmt.Errorf("repo restore goal: %w", err)
+		}
+
+		err = u.auditLogger.Record(ctx, auditUC.Event{
+			ActorID:     userID,
+			WorkspaceID: access.WorkspaceID.Int64,
+			EntityType:  auditUC.EntityGoal,
+			EntityID:    goalID,
+			Action:      auditUC.ActionRestore,
+			After:       convertToAuditState(goal),
+		})
+		if err != nil {
+			return fmt.Errorf("audit record: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	u.statsCache.InvalidateGoals(ctx, userID, goal.ProjectID)
+
+	return nil
+}
+
+// CheckAchievements отмечает впервые достигнутые цели пользователя по проекту и записывает
+// их достижение в журнал. Вызывается после изменения записей; нулевой projectID — все проекты.
+func (u *Usecase) CheckAchievements(ctx context.Context, userID, projectID int64) error {
+	ctx, span := tracing.Start(ctx, "goal.CheckAchievements")
+	defer span.End()
+
+	// Вне транзакции записей отметка целей и журнал тоже сохраняются атомарно.
+	return u.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		goals, err := u.repository.MarkAchievedGoals(ctx, userID, projectID)
+		if err != nil {
+			return fmt.Errorf("repo mark achieved goals: %w", err)
+		}
+
+		for _, goal := range goals {
+			access, err := u.projectRepository.GetProjectAccess(ctx, goal.ProjectID, userID)
+			if err != nil && !errors.Is(err, projectRepoDto.ErrProjectNotFound) {
+				return fmt.Errorf("repo get project access: %w", err)
+			}
+
+			err = u.auditLogger.Record(ctx, auditUC.Event{
+				ActorID:     userID,
+				WorkspaceID: access.WorkspaceID.Int64,
+				EntityType:  auditUC.EntityGoal,
+				EntityID:    goal.ID,
+				Action:      auditUC.ActionAchieve,
+				After:       convertToAuditState(goal),
+			})
+			if err != nil {
+				return fmt.Errorf("audit record: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// checkProjectAccess проверяет, что пользователь может ставить цели по проекту.
+func (u *Usecase) checkProjectAccess(ctx context.Context, projectID, userID int64) (projectRepoDto.ProjectAccess, error) {
+	access, err := u.projectRepository.GetProjectAccess(ctx, projectID, userID)
+	if err != nil {
+		if errors.Is(err, projectRepoDto.ErrProjectNotFound) {
+			return projectRepoDto.ProjectAccess{}, ErrProjectNotFound
+		}
+		return projectRepoDto.ProjectAccess{}, fmt.Errorf("repo get project access: %w", err)
+	}
+
+	role := roles.ProjectRole(access.OwnerID, userID, access.WorkspaceID.Valid, access.MemberRole.String)
+	if !role.AtLeast(roles.Member) {
+		return projectRepoDto.ProjectAccess{}, ErrForbidden
+	}
+
+	return access, nil
+}
+
+// GetGoals возвращает цели пользователя по проекту с прогрессом. Результат кешируется
+// до изменения целей или записей пользователя в проекте.
 func (u *Usecase) GetGoals(ctx context.Context, userID, projectID int64) ([]Goal, error) {
+	ctx, span := tracing.Start(ctx, "goal.GetGoals")
+	defer span.End()
+
+	var goals []Goal
+	err := u.statsCache.Fetch(ctx, statscache.GoalsKey(userID, projectID), &goals, func(ctx context.Context) error {
+		var err error
+		goals, err = u.getGoals(ctx, userID, projectID)
+		return err
+	})
+
+	return goals, err
+}
+
+func (u *Usecase) getGoals(ctx context.Context, userID, projectID int64) ([]Goal, error) {
 	goals, err := u.repository.GetGoals(ctx, userID, projectID)
 	if err != nil {
 		if errors.Is(err, repo.ErrGoalNotFound) {
 			return []Goal{}, nil
 		}
 
-		return nil, fmt.Errorf("repo get goals: %v", err)
+		return nil, fmt.Errorf("repo get goals: %w", err)
 	}
 
 	var res []Goal
@@ -70,6 +330,12 @@ func (u *Usecase) GetGoals(ctx context.Context, userID, projectID int64) ([]Goal
 
 		durationSeconds := duration.Seconds()
 		percent := durationSeconds / float64(goal.TimeSeconds) * 100
+
+		var achievedAt *time.Time
+		if goal.AchievedAt.Valid {
+			achievedAt = &goal.AchievedAt.Time
+		}
+
 		res = append(res, Goal{
 			ID:              goal.ID,
 			ProjectID:       goal.ProjectID,
@@ -80,6 +346,7 @@ func (u *Usecase) GetGoals(ctx context.Context, userID, projectID int64) ([]Goal
 			DateEnd:         goal.DateEnd,
 			DurationSeconds: duration.Seconds(),
 			Percent:         percent,
+			AchievedAt:      achievedAt,
 		})
 	}
 
@@ -97,3 +364,15 @@ func convertToRepoGoal(goal Goal) repo.Goal {
 		DateEnd:     time.Date(goal.DateEnd.Year(), goal.DateEnd.Month(), goal.DateEnd.Day(), 23, 59, 59, 0, goal.DateEnd.Location()),
 	}
 }
+
+func convertToAuditState(goal repo.Goal) auditUC.GoalState {
+	return auditUC.GoalState{
+		ID:          goal.ID,
+		UserID:      goal.UserID,
+		ProjectID:   goal.ProjectID,
+		Name:        goal.Name,
+		TimeSeconds: goal.TimeSeconds,
+		DateStart:   goal.DateStart,
+		DateEnd:     goal.DateEnd,
+	}
+}
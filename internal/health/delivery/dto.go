@@ -0,0 +1,10 @@
+package delivery
+
+type HealthOut struct {
+	Status string `json:"status" example:"ok"` // Всегда ok.
+}
+
+type ReadinessOut struct {
+	Status string            `json:"status" example:"ok"`                   // ok или unavailable.
+	Checks map[string]string `json:"checks" example:"postgres:ok,redis:ok"` // Статус каждой зависимости: ok или текст ошибки.
+}
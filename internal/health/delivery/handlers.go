@@ -0,0 +1,70 @@
+package delivery
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	usecaseDto "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/health/usecase"
+)
+
+type usecase interface {
+	Readiness(ctx context.Context) usecaseDto.Readiness
+}
+
+type Delivery struct {
+	usecase usecase
+
+	logger echo.Logger
+}
+
+func RegisterHandlers(
+	e *echo.Echo,
+	usecase usecase,
+	logger echo.Logger,
+) {
+	handler := &Delivery{
+		usecase: usecase,
+
+		logger: logger,
+	}
+
+	e.GET("/healthz", handler.Health)
+	e.GET("/readyz", handler.Ready)
+}
+
+// Health godoc
+// @Summary      Проверка живости.
+// @Description  Отвечает 200, пока процесс обрабатывает запросы. Зависимости не проверяются: их недоступность не лечится перезапуском.
+// @Tags     	 health
+// @Produce  application/json
+// @Success  200 {object} HealthOut "alive"
+// @Router   /healthz [get]
+func (d *Delivery) Health(c echo.Context) error {
+	return c.JSON(http.StatusOK, HealthOut{Status: usecaseDto.StatusOK})
+}
+
+// Ready godoc
+// @Summary      Проверка готовности.
+// @Description  Отвечает 200, если сервис запущен и доступны Postgres и Redis (если он настроен). Во время остановки и при недоступной зависимости отвечает 503.
+// @Tags     	 health
+// @Produce  application/json
+// @Success  200 {object} ReadinessOut "ready"
+// @Failure  503 {object} ReadinessOut "not ready"
+// @Router   /readyz [get]
+func (d *Delivery) Ready(c echo.Context) error {
+	readiness := d.usecase.Readiness(c.Request().Context())
+
+	out := ReadinessOut{
+		Status: usecaseDto.StatusOK,
+		Checks: readiness.Checks,
+	}
+	if !readiness.Ready {
+		out.Status = usecaseDto.StatusUnavailable
+		c.Logger().Warnf("not ready: %v", readiness.Checks)
+		return c.JSON(http.StatusServiceUnavailable, out)
+	}
+
+	return c.JSON(http.StatusOK, out)
+}
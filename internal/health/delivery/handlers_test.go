@@ -0,0 +1,97 @@
+package delivery
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	usecaseDto "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/health/usecase"
+)
+
+type fakePinger struct {
+	err error
+}
+
+func (p *fakePinger) Ping(context.Context) error {
+	return p.err
+}
+
+func get(t *testing.T, e *echo.Echo, path string) (int, ReadinessOut) {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+	var out ReadinessOut
+	if err := json.Unmarshal(rec.Body.Bytes(), &out); err != nil {
+		t.Fatalf("unmarshal %s: %v", path, err)
+	}
+
+	return rec.Code, out
+}
+
+func checkReadiness(t *testing.T, e *echo.Echo, wantCode int, want ReadinessOut) {
+	t.Helper()
+
+	code, out := get(t, e, "/readyz")
+	if code != wantCode {
+		t.Errorf("/readyz status %d, want %d", code, wantCode)
+	}
+	if !reflect.DeepEqual(out, want) {
+		t.Errorf("/readyz %+v, want %+v", out, want)
+	}
+}
+
+func checkAlive(t *testing.T, e *echo.Echo) {
+	t.Helper()
+
+	code, out := get(t, e, "/healthz")
+	if code != http.StatusOK || out.Status != usecaseDto.StatusOK {
+		t.Errorf("/healthz %d %+v, want 200 ok", code, out)
+	}
+}
+
+func TestHealthAndReadiness(t *testing.T) {
+	postgres, redis := &fakePinger{}, &fakePinger{}
+
+	uc := usecaseDto.NewUsecase(time.Second)
+	uc.AddCheck("postgres", postgres)
+	uc.AddCheck("redis", redis)
+
+	e := echo.New()
+	RegisterHandlers(e, uc, e.Logger)
+
+	allOK := map[string]string{"postgres": usecaseDto.StatusOK, "redis": usecaseDto.StatusOK}
+
+	// До запуска сервера.
+	checkReadiness(t, e, http.StatusServiceUnavailable, ReadinessOut{Status: usecaseDto.StatusUnavailable, Checks: allOK})
+	checkAlive(t, e)
+
+	uc.SetReady(true)
+	checkReadiness(t, e, http.StatusOK, ReadinessOut{Status: usecaseDto.StatusOK, Checks: allOK})
+
+	// Зависимость недоступна: экземпляр убирается из балансировки, но жив.
+	redis.err = errors.New("i/o timeout")
+	checkReadiness(t, e, http.StatusServiceUnavailable, ReadinessOut{
+		Status: usecaseDto.StatusUnavailable,
+		Checks: map[string]string{"postgres": usecaseDto.StatusOK, "redis": "i/o timeout"},
+	})
+	checkAlive(t, e)
+
+	// Остановка: зависимости доступны, но /readyz уже отвечает 503.
+	redis.err = nil
+	uc.SetReady(false)
+	checkReadiness(t, e, http.StatusServiceUnavailable, ReadinessOut{Status: usecaseDto.StatusUnavailable, Checks: allOK})
+	checkAlive(t, e)
+
+	// Живость не зависит от базы.
+	postgres.err = errors.New("connection refused")
+	checkAlive(t, e)
+}
@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/redis/go-redis/v9"
+)
+
+// PostgresRepository проверяет доступность Postgres.
+type PostgresRepository struct {
+	db *sqlx.DB
+}
+
+func NewPostgresRepository(db *sqlx.DB) *PostgresRepository {
+	return &PostgresRepository{
+		db: db,
+	}
+}
+
+func (r *PostgresRepository) Ping(ctx context.Context) error {
+	return r.db.PingContext(ctx)
+}
+
+// RedisRepository проверяет доступность Redis.
+type RedisRepository struct {
+	client *redis.Client
+}
+
+func NewRedisRepository(client *redis.Client) *RedisRepository {
+	return &RedisRepository{
+		client: client,
+	}
+}
+
+func (r *RedisRepository) Ping(ctx context.Context) error {
+	return r.client.Ping(ctx).Err()
+}
@@ -0,0 +1,14 @@
+package usecase
+
+// Статусы проверки.
+const (
+	StatusOK          = "ok"
+	StatusUnavailable = "unavailable"
+)
+
+// Readiness результат проверки готовности: общий статус и статус каждой зависимости.
+type Readiness struct {
+	Ready bool
+	// Ключ — имя зависимости, значение — StatusOK или текст ошибки.
+	Checks map[string]string
+}
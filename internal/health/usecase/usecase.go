@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type pinger interface {
+	Ping(ctx context.Context) error
+}
+
+type Usecase struct {
+	checks  map[string]pinger
+	timeout time.Duration
+	// Сервис готов принимать запросы: выставляется после запуска и снимается при остановке.
+	ready atomic.Bool
+}
+
+// NewUsecase создает проверку готовности. timeout ограничивает проверку каждой
+// зависимости, чтобы зависшая база не задерживала ответ.
+func NewUsecase(timeout time.Duration) *Usecase {
+	return &Usecase{
+		checks:  make(map[string]pinger),
+		timeout: timeout,
+	}
+}
+
+// AddCheck добавляет зависимость, без которой сервис не может обслуживать запросы.
+// Вызывается при запуске, до регистрации обработчиков.
+func (u *Usecase) AddCheck(name string, p pinger) {
+	u.checks[name] = p
+}
+
+// SetReady отмечает, готов ли сервис принимать запросы.
+func (u *Usecase) SetReady(ready bool) {
+	u.ready.Store(ready)
+}
+
+// Readiness параллельно проверяет все зависимости. Сервис готов, если он запущен,
+// не останавливается и все зависимости доступны.
+func (u *Usecase) Readiness(ctx context.Context) Readiness {
+	if u.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, u.timeout)
+		defer cancel()
+	}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+
+	res := Readiness{
+		Ready:  u.ready.Load(),
+		Checks: make(map[string]string, len(u.checks)),
+	}
+
+	for name, p := range u.checks {
+		wg.Add(1)
+		go func(name string, p pinger) {
+			defer wg.Done()
+
+			status := StatusOK
+			if err := p.Ping(ctx); err != nil {
+				status = err.Error()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			res.Checks[name] = status
+			if status != StatusOK {
+				res.Ready = false
+			}
+		}(name, p)
+	}
+
+	wg.Wait()
+
+	return res
+}
@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type fakePinger struct {
+	err error
+	// Ждать отмены контекста, как зависшее соединение.
+	hang bool
+}
+
+func (p fakePinger) Ping(ctx context.Context) error {
+	if p.hang {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	return p.err
+}
+
+func TestReadiness(t *testing.T) {
+	tests := []struct {
+		name       string
+		ready      bool
+		checks     map[string]fakePinger
+		wantReady  bool
+		wantChecks map[string]string
+	}{
+		{
+			name:       "all checks pass",
+			ready:      true,
+			checks:     map[string]fakePinger{"postgres": {}, "redis": {}},
+			wantReady:  true,
+			wantChecks: map[string]string{"postgres": StatusOK, "redis": StatusOK},
+		},
+		{
+			name:       "not started or stopping",
+			ready:      false,
+			checks:     map[string]fakePinger{"postgres": {}},
+			wantReady:  false,
+			wantChecks: map[string]string{"postgres": StatusOK},
+		},
+		{
+			name:       "postgres unavailable",
+			ready:      true,
+			checks:     map[string]fakePinger{"postgres": {err: errors.New("connection refused")}, "redis": {}},
+			wantReady:  false,
+			wantChecks: map[string]string{"postgres": "connection refused", "redis": StatusOK},
+		},
+		{
+			name:       "redis hangs",
+			ready:      true,
+			checks:     map[string]fakePinger{"postgres": {}, "redis": {hang: true}},
+			wantReady:  false,
+			wantChecks: map[string]string{"postgres": StatusOK, "redis": context.DeadlineExceeded.Error()},
+		},
+		{
+			name:       "no dependencies",
+			ready:      true,
+			wantReady:  true,
+			wantChecks: map[string]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := NewUsecase(50 * time.Millisecond)
+			for name, p := range tt.checks {
+				u.AddCheck(name, p)
+			}
+			u.SetReady(tt.ready)
+
+			got := u.Readiness(context.Background())
+			if got.Ready != tt.wantReady {
+				t.Errorf("ready %v, want %v", got.Ready, tt.wantReady)
+			}
+			if !reflect.DeepEqual(got.Checks, tt.wantChecks) {
+				t.Errorf("checks %v, want %v", got.Checks, tt.wantChecks)
+			}
+		})
+	}
+}
+
+func TestSetReady(t *testing.T) {
+	u := NewUsecase(time.Second)
+	u.AddCheck("postgres", fakePinger{})
+
+	if u.Readiness(context.Background()).Ready {
+		t.Error("ready before start")
+	}
+
+	u.SetReady(true)
+	if !u.Readiness(context.Background()).Ready {
+		t.Error("not ready after start")
+	}
+
+	u.SetReady(false)
+	if u.Readiness(context.Background()).Ready {
+		t.Error("ready after shutdown began")
+	}
+}
@@ -0,0 +1,369 @@
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidCalendar = errors.New("invalid calendar")
+
+const (
+	timeLayoutLocal = "20060102T150405"
+	dateLayout      = "20060102"
+)
+
+// ParsedCalendar календарь, прочитанный из файла.
+type ParsedCalendar struct {
+	Name   string
+	Events []ParsedEvent
+}
+
+// ParsedEvent событие VEVENT. Для повторяющегося события Start и End относятся к первому
+// вхождению, остальные вычисляются по RRule.
+type ParsedEvent struct {
+	// Порядковый номер VEVENT в файле, начиная с 1.
+	Index      int
+	UID        string
+	Summary    string
+	Categories []string
+	Status     string
+	Start      time.Time
+	End        time.Time
+	// Событие на весь день (DTSTART;VALUE=DATE).
+	AllDay  bool
+	RRule   string
+	ExDates []time.Time
+	// Для измененного вхождения повторяющегося события — исходное время вхождения.
+	RecurrenceID time.Time
+	Attendees    []Attendee
+
+	// DURATION может идти раньше DTSTART, поэтому End вычисляется в конце события.
+	duration time.Duration
+}
+
+type Attendee struct {
+	Email    string
+	PartStat string
+}
+
+// property строка содержимого: NAME;PARAM=VALUE:value.
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// Parse читает календарь. Время без часового пояса и с неизвестным TZID считается временем loc.
+func Parse(r io.Reader, loc *time.Location) (ParsedCalendar, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return ParsedCalendar{}, fmt.Errorf("%w: %v", ErrInvalidCalendar, err)
+	}
+
+	var cal ParsedCalendar
+	var event *ParsedEvent
+	// Вложенные в VEVENT компоненты (VALARM) пропускаются.
+	nested := 0
+	seenCalendar := false
+
+	for i, line := range lines {
+		if line == "" {
+			continue
+		}
+
+		prop, err := parseProperty(line)
+		if err != nil {
+			return ParsedCalendar{}, fmt.Errorf("%w: line %d: %v", ErrInvalidCalendar, i+1, err)
+		}
+
+		switch {
+		case prop.name == "BEGIN" && strings.EqualFold(prop.value, "VCALENDAR"):
+			seenCalendar = true
+		case prop.name == "BEGIN" && strings.EqualFold(prop.value, "VEVENT") && event == nil:
+			event = &ParsedEvent{Index: len(cal.Events) + 1}
+		case prop.name == "BEGIN" && event != nil:
+			nested++
+		case prop.name == "END" && event != nil && nested > 0:
+			nested--
+		case prop.name == "END" && strings.EqualFold(prop.value, "VEVENT") && event != nil:
+			if event.Start.IsZero() {
+				return ParsedCalendar{}, fmt.Errorf("%w: event %d has no DTSTART", ErrInvalidCalendar, event.Index)
+			}
+			event.finish()
+			cal.Events = append(cal.Events, *event)
+			event = nil
+		case event != nil && nested == 0:
+			if err = event.apply(prop, loc); err != nil {
+				return ParsedCalendar{}, fmt.Errorf("%w: line %d: %v", ErrInvalidCalendar, i+1, err)
+			}
+		case event == nil && prop.name == "X-WR-CALNAME":
+			cal.Name = unescapeText(prop.value)
+		}
+	}
+
+	if !seenCalendar {
+		return ParsedCalendar{}, fmt.Errorf("%w: VCALENDAR not found", ErrInvalidCalendar)
+	}
+
+	return cal, nil
+}
+
+func (e *ParsedEvent) apply(prop property, loc *time.Location) error {
+	var err error
+
+	switch prop.name {
+	case "UID":
+		e.UID = prop.value
+	case "SUMMARY":
+		e.Summary = unescapeText(prop.value)
+	case "CATEGORIES":
+		for _, c := range splitText(prop.value) {
+			if c = strings.TrimSpace(c); c != "" {
+				e.Categories = append(e.Categories, c)
+			}
+		}
+	case "STATUS":
+		e.Status = strings.ToUpper(prop.value)
+	case "DTSTART":
+		e.Start, e.AllDay, err = parseDateTime(prop, loc)
+	case "DTEND":
+		e.End, _, err = parseDateTime(prop, loc)
+	case "DURATION":
+		e.duration, err = parseDuration(prop.value)
+	case "RRULE":
+		e.RRule = prop.value
+	case "EXDATE":
+		for _, v := range strings.Split(prop.value, ",") {
+			t, _, err := parseDateTime(property{name: prop.name, params: prop.params, value: v}, loc)
+			if err != nil {
+				return err
+			}
+			e.ExDates = append(e.ExDates, t)
+		}
+	case "RECURRENCE-ID":
+		e.RecurrenceID, _, err = parseDateTime(prop, loc)
+	case "ATTENDEE":
+		e.Attendees = append(e.Attendees, Attendee{
+			Email:    strings.ToLower(strings.TrimPrefix(strings.TrimPrefix(prop.value, "mailto:"), "MAILTO:")),
+			PartStat: strings.ToUpper(prop.params["PARTSTAT"]),
+		})
+	}
+
+	if err != nil {
+		return fmt.Errorf("%s: %v", prop.name, err)
+	}
+
+	return nil
+}
+
+// finish вычисляет окончание события без DTEND (RFC 5545, 3.6.1).
+func (e *ParsedEvent) finish() {
+	if !e.End.IsZero() {
+		return
+	}
+
+	switch {
+	case e.duration != 0:
+		e.End = e.Start.Add(e.duration)
+	case e.AllDay:
+		e.End = e.Start.AddDate(0, 0, 1)
+	default:
+		e.End = e.Start
+	}
+}
+
+// unfold склеивает перенесенные строки: продолжение начинается с пробела или табуляции.
+func unfold(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var lines []string
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(lines) == 0 {
+			line = strings.TrimPrefix(line, "\ufeff")
+		}
+
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+
+		lines = append(lines, line)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return lines, nil
+}
+
+// parseProperty разбирает строку NAME;PARAM=VALUE;PARAM="V:1":value.
+// Двоеточие и точка с запятой внутри кавычек не разделяют части.
+func parseProperty(line string) (property, error) {
+	prop := property{params: map[string]string{}}
+
+	inQuotes := false
+	start := 0
+	nameDone := false
+	var paramParts []string
+
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '"':
+			inQuotes = !inQuotes
+		case ';', ':':
+			if inQuotes {
+				continue
+			}
+
+			part := line[start:i]
+			if !nameDone {
+				prop.name = strings.ToUpper(part)
+				nameDone = true
+			} else {
+				paramParts = append(paramParts, part)
+			}
+			start = i + 1
+
+			if line[i] == ':' {
+				prop.value = line[i+1:]
+				for _, p := range paramParts {
+					key, value, _ := strings.Cut(p, "=")
+					prop.params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+				}
+
+				return prop, nil
+			}
+		}
+	}
+
+	return property{}, errors.New("property has no value")
+}
+
+// parseDateTime разбирает DATE-TIME (UTC, с TZID или плавающее) и DATE.
+func parseDateTime(prop property, loc *time.Location) (time.Time, bool, error) {
+	value := strings.TrimSpace(prop.value)
+
+	if strings.EqualFold(prop.params["VALUE"], "DATE") || len(value) == len(dateLayout) {
+		t, err := time.ParseInLocation(dateLayout, value, loc)
+		return t, true, err
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse(timeLayoutUTC, value)
+		return t, false, err
+	}
+
+	if tzid := prop.params["TZID"]; tzid != "" {
+		// Windows-названия поясов (Outlook) Go не знает, для них остается loc.
+		if tzLoc, err := time.LoadLocation(tzid); err == nil {
+			loc = tzLoc
+		}
+	}
+
+	t, err := time.ParseInLocation(timeLayoutLocal, value, loc)
+	return t, false, err
+}
+
+// parseDuration разбирает DURATION: [+-]P[nW][nD][T[nH][nM][nS]].
+func parseDuration(s string) (time.Duration, error) {
+	sign := time.Duration(1)
+	switch {
+	case strings.HasPrefix(s, "-"):
+		sign = -1
+		s = s[1:]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+
+	if !strings.HasPrefix(s, "P") {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	s = s[1:]
+
+	var d time.Duration
+	inTime := false
+	num := ""
+	for _, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			num += string(r)
+			continue
+		case r == 'T':
+			inTime = true
+			continue
+		}
+
+		n, err := strconv.Atoi(num)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		num = ""
+
+		switch {
+		case r == 'W':
+			d += time.Duration(n) * 7 * 24 * time.Hour
+		case r == 'D':
+			d += time.Duration(n) * 24 * time.Hour
+		case r == 'H' && inTime:
+			d += time.Duration(n) * time.Hour
+		case r == 'M' && inTime:
+			d += time.Duration(n) * time.Minute
+		case r == 'S' && inTime:
+			d += time.Duration(n) * time.Second
+		default:
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+	}
+
+	if num != "" {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+
+	return sign * d, nil
+}
+
+// splitText делит список значений TEXT по неэкранированным запятым.
+func splitText(s string) []string {
+	var parts []string
+	var b strings.Builder
+
+	escaped := false
+	for _, r := range s {
+		switch {
+		case escaped:
+			b.WriteRune('\\')
+			b.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == ',':
+			parts = append(parts, unescapeText(b.String()))
+			b.Reset()
+		default:
+			b.WriteRune(r)
+		}
+	}
+	parts = append(parts, unescapeText(b.String()))
+
+	return parts
+}
+
+func unescapeText(s string) string {
+	return textUnescaper.Replace(s)
+}
+
+var textUnescaper = strings.NewReplacer(
+	`\\`, `\`,
+	`\;`, `;`,
+	`\,`, `,`,
+	`\n`, "\n",
+	`\N`, "\n",
+)
@@ -0,0 +1,169 @@
+package ical
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+	// Европейские пояса из TZID не должны зависеть от tzdata системы.
+	_ "time/tzdata"
+)
+
+func parseTestdata(t *testing.T, name string, loc *time.Location) ParsedCalendar {
+	t.Helper()
+
+	f, err := os.Open(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	cal, err := Parse(f, loc)
+	if err != nil {
+		t.Fatalf("parse %s: %v", name, err)
+	}
+
+	return cal
+}
+
+func TestParse(t *testing.T) {
+	cal := parseTestdata(t, "events.ics", msk)
+
+	if cal.Name != "Work" {
+		t.Errorf("calendar name %q, want Work", cal.Name)
+	}
+
+	want := []ParsedEvent{
+		{
+			Index:      1,
+			UID:        "standup-1@example.com",
+			Summary:    "Daily standup, team",
+			Categories: []string{"Meetings", "Team, internal"},
+			Start:      time.Date(2024, 3, 4, 7, 0, 0, 0, time.UTC),
+			End:        time.Date(2024, 3, 4, 7, 15, 0, 0, time.UTC),
+			RRule:      "FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR;COUNT=10",
+			ExDates: []time.Time{
+				time.Date(2024, 3, 6, 7, 0, 0, 0, time.UTC),
+				time.Date(2024, 3, 7, 7, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			Index:        2,
+			UID:          "standup-1@example.com",
+			Summary:      "Daily standup (moved)",
+			Start:        time.Date(2024, 3, 5, 9, 0, 0, 0, time.UTC),
+			End:          time.Date(2024, 3, 5, 9, 15, 0, 0, time.UTC),
+			RecurrenceID: time.Date(2024, 3, 5, 7, 0, 0, 0, time.UTC),
+		},
+		{
+			Index:   3,
+			UID:     "holiday@example.com",
+			Summary: "Holiday",
+			Start:   time.Date(2024, 3, 8, 0, 0, 0, 0, msk),
+			End:     time.Date(2024, 3, 9, 0, 0, 0, 0, msk),
+			AllDay:  true,
+		},
+		{
+			Index:   4,
+			UID:     "review@example.com",
+			Summary: "Design review with a very long title that does not fit into one line of the calendar file",
+			Status:  "CONFIRMED",
+			// DURATION указан раньше DTSTART.
+			Start: time.Date(2024, 3, 4, 13, 0, 0, 0, time.UTC),
+			End:   time.Date(2024, 3, 4, 14, 30, 0, 0, time.UTC),
+			Attendees: []Attendee{
+				{Email: "jane@example.com", PartStat: "DECLINED"},
+				{Email: "john@example.com", PartStat: "ACCEPTED"},
+			},
+		},
+		{
+			Index:   5,
+			UID:     "floating@example.com",
+			Summary: "Lunch",
+			// Время без пояса — время loc, событие без DTEND и DURATION длится 0.
+			Start: time.Date(2024, 3, 4, 14, 0, 0, 0, msk),
+			End:   time.Date(2024, 3, 4, 14, 0, 0, 0, msk),
+		},
+	}
+
+	if len(cal.Events) != len(want) {
+		t.Fatalf("got %d events, want %d", len(cal.Events), len(want))
+	}
+
+	for i, w := range want {
+		got := cal.Events[i]
+
+		if !got.Start.Equal(w.Start) || !got.End.Equal(w.End) || !got.RecurrenceID.Equal(w.RecurrenceID) {
+			t.Errorf("event %d: got %v - %v (recurrence %v), want %v - %v (recurrence %v)",
+				i+1, got.Start, got.End, got.RecurrenceID, w.Start, w.End, w.RecurrenceID)
+		}
+		if len(got.ExDates) != len(w.ExDates) {
+			t.Errorf("event %d: exdates %v, want %v", i+1, got.ExDates, w.ExDates)
+		}
+		for j := range w.ExDates {
+			if j < len(got.ExDates) && !got.ExDates[j].Equal(w.ExDates[j]) {
+				t.Errorf("event %d: exdate %d is %v, want %v", i+1, j, got.ExDates[j], w.ExDates[j])
+			}
+		}
+
+		// Время сравнили выше, в остальном события должны совпасть целиком.
+		got.Start, got.End, got.RecurrenceID, got.ExDates = w.Start, w.End, w.RecurrenceID, w.ExDates
+		got.duration = 0
+		if !reflect.DeepEqual(got, w) {
+			t.Errorf("event %d:\ngot  %+v\nwant %+v", i+1, got, w)
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"empty file", "", "VCALENDAR not found"},
+		{"not a calendar", "hello\r\n", "line 1: property has no value"},
+		{"event without start", "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nUID:1\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n", "event 1 has no DTSTART"},
+		{"bad date", "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nDTSTART:2024-03-04\r\nEND:VEVENT\r\n", "line 3: DTSTART"},
+		{"bad duration", "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nDURATION:1H\r\nEND:VEVENT\r\n", "line 3: DURATION"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(tt.input), time.UTC)
+			if !errors.Is(err, ErrInvalidCalendar) || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"PT15M", 15 * time.Minute, false},
+		{"PT1H30M", 90 * time.Minute, false},
+		{"P1DT2H", 26 * time.Hour, false},
+		{"P2W", 14 * 24 * time.Hour, false},
+		{"+PT10S", 10 * time.Second, false},
+		{"-PT10M", -10 * time.Minute, false},
+		{"PT", 0, false},
+		{"1H", 0, true},
+		{"P1H", 0, true},
+		{"PT5", 0, true},
+		{"PTM", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseDuration(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseDuration(%q) = %v, %v; want %v, error %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
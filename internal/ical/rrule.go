@@ -0,0 +1,322 @@
+package ical
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Предел числа периодов при разворачивании правила, защищает от бесконечного цикла
+// на правилах без вхождений (например, BYMONTHDAY=31 с BYMONTH=2).
+const maxRRulePeriods = 100000
+
+// RRule правило повторения (RFC 5545, 3.3.10). Поддерживаются FREQ от DAILY до YEARLY,
+// INTERVAL, COUNT, UNTIL, BYDAY, BYMONTHDAY и BYMONTH; неделя начинается с понедельника.
+type RRule struct {
+	Freq       string
+	Interval   int
+	Count      int
+	Until      time.Time
+	ByDay      []WeekdayNum
+	ByMonthDay []int
+	ByMonth    []int
+}
+
+// WeekdayNum день недели с необязательным номером: 1MO — первый понедельник, -1FR — последняя пятница.
+type WeekdayNum struct {
+	N   int
+	Day time.Weekday
+}
+
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// ParseRRule разбирает значение RRULE. UNTIL без часового пояса считается временем loc.
+func ParseRRule(s string, loc *time.Location) (RRule, error) {
+	rule := RRule{Interval: 1}
+
+	for _, part := range strings.Split(s, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return RRule{}, fmt.Errorf("invalid rule part %q", part)
+		}
+
+		var err error
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			rule.Freq = strings.ToUpper(value)
+		case "INTERVAL":
+			rule.Interval, err = strconv.Atoi(value)
+			if err == nil && rule.Interval < 1 {
+				err = fmt.Errorf("interval must be positive")
+			}
+		case "COUNT":
+			rule.Count, err = strconv.Atoi(value)
+		case "UNTIL":
+			rule.Until, _, err = parseDateTime(property{value: value, params: map[string]string{}}, loc)
+		case "BYDAY":
+			for _, v := range strings.Split(value, ",") {
+				var day WeekdayNum
+				if day, err = parseWeekdayNum(v); err != nil {
+					break
+				}
+				rule.ByDay = append(rule.ByDay, day)
+			}
+		case "BYMONTHDAY":
+			rule.ByMonthDay, err = parseInts(value)
+		case "BYMONTH":
+			rule.ByMonth, err = parseInts(value)
+		}
+
+		if err != nil {
+			return RRule{}, fmt.Errorf("%s: %v", key, err)
+		}
+	}
+
+	switch rule.Freq {
+	case "DAILY", "WEEKLY", "MONTHLY", "YEARLY":
+	default:
+		return RRule{}, fmt.Errorf("unsupported frequency %q", rule.Freq)
+	}
+
+	return rule, nil
+}
+
+// Expand возвращает начала вхождений с началом в [from, to), включая первое вхождение start.
+// COUNT отсчитывается от start, поэтому вхождения раньше from тоже учитываются в лимите.
+func (r RRule) Expand(start, from, to time.Time) []time.Time {
+	var out []time.Time
+
+	seen := 0
+	for period := 0; period < maxRRulePeriods; period++ {
+		candidates := r.periodCandidates(start, period)
+
+		for _, t := range candidates {
+			if t.Before(start) {
+				continue
+			}
+			if !r.Until.IsZero() && t.After(r.Until) {
+				return out
+			}
+			if !t.Before(to) {
+				return out
+			}
+
+			seen++
+			if r.Count > 0 && seen > r.Count {
+				return out
+			}
+
+			if !t.Before(from) {
+				out = append(out, t)
+			}
+		}
+	}
+
+	return out
+}
+
+// periodCandidates вхождения n-го периода правила в порядке времени.
+func (r RRule) periodCandidates(start time.Time, n int) []time.Time {
+	step := n * r.Interval
+	at := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, start.Hour(), start.Minute(), start.Second(), 0, start.Location())
+	}
+
+	var candidates []time.Time
+
+	switch r.Freq {
+	case "DAILY":
+		t := start.AddDate(0, 0, step)
+		if r.matchesDay(t) {
+			candidates = append(candidates, t)
+		}
+	case "WEEKLY":
+		// Понедельник недели start.
+		offset := (int(start.Weekday()) + 6) % 7
+		monday := at(start.Year(), start.Month(), start.Day()-offset).AddDate(0, 0, 7*step)
+
+		days := r.ByDay
+		if len(days) == 0 {
+			days = []WeekdayNum{{Day: start.Weekday()}}
+		}
+		for _, d := range days {
+			t := monday.AddDate(0, 0, (int(d.Day)+6)%7)
+			if r.matchesMonth(t) {
+				candidates = append(candidates, t)
+			}
+		}
+	case "MONTHLY":
+		first := time.Date(start.Year(), start.Month()+time.Month(step), 1, 0, 0, 0, 0, start.Location())
+		if r.matchesMonth(first) {
+			candidates = r.monthCandidates(start, first.Year(), first.Month(), at)
+		}
+	case "YEARLY":
+		year := start.Year() + step
+		months := r.ByMonth
+		if len(months) == 0 {
+			months = []int{int(start.Month())}
+		}
+		for _, m := range months {
+			candidates = append(candidates, r.monthCandidates(start, year, time.Month(m), at)...)
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].Before(candidates[j]) })
+
+	return candidates
+}
+
+// monthCandidates вхождения в месяце по BYDAY и BYMONTHDAY, без них — день месяца start.
+func (r RRule) monthCandidates(start time.Time, year int, month time.Month, at func(int, time.Month, int) time.Time) []time.Time {
+	daysInMonth := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+
+	var days []int
+	for _, d := range r.ByMonthDay {
+		if d < 0 {
+			d = daysInMonth + d + 1
+		}
+		if d >= 1 && d <= daysInMonth {
+			days = append(days, d)
+		}
+	}
+
+	// BYDAY вместе с BYMONTHDAY ограничивает дни месяца (пятница, 13-е), а не добавляет их.
+	if len(days) > 0 && len(r.ByDay) > 0 {
+		filtered := days[:0]
+		for _, d := range days {
+			weekday := time.Date(year, month, d, 0, 0, 0, 0, time.UTC).Weekday()
+			for _, wd := range r.ByDay {
+				if wd.Day == weekday {
+					filtered = append(filtered, d)
+					break
+				}
+			}
+		}
+
+		return r.daysToTimes(year, month, filtered, at)
+	}
+
+	for _, wd := range r.ByDay {
+		// Все дни месяца с этим днем недели.
+		var matching []int
+		for d := 1; d <= daysInMonth; d++ {
+			if time.Date(year, month, d, 0, 0, 0, 0, time.UTC).Weekday() == wd.Day {
+				matching = append(matching, d)
+			}
+		}
+
+		switch {
+		case wd.N == 0:
+			days = append(days, matching...)
+		case wd.N > 0 && wd.N <= len(matching):
+			days = append(days, matching[wd.N-1])
+		case wd.N < 0 && -wd.N <= len(matching):
+			days = append(days, matching[len(matching)+wd.N])
+		}
+	}
+
+	if len(r.ByMonthDay) == 0 && len(r.ByDay) == 0 && start.Day() <= daysInMonth {
+		days = append(days, start.Day())
+	}
+
+	return r.daysToTimes(year, month, days, at)
+}
+
+func (r RRule) daysToTimes(year int, month time.Month, days []int, at func(int, time.Month, int) time.Time) []time.Time {
+	candidates := make([]time.Time, 0, len(days))
+	for _, d := range days {
+		candidates = append(candidates, at(year, month, d))
+	}
+
+	return candidates
+}
+
+func (r RRule) matchesDay(t time.Time) bool {
+	if !r.matchesMonth(t) {
+		return false
+	}
+
+	if len(r.ByDay) > 0 {
+		found := false
+		for _, d := range r.ByDay {
+			if d.Day == t.Weekday() {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if len(r.ByMonthDay) > 0 {
+		daysInMonth := time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+		for _, d := range r.ByMonthDay {
+			if d == t.Day() || daysInMonth+d+1 == t.Day() {
+				return true
+			}
+		}
+		return false
+	}
+
+	return true
+}
+
+func (r RRule) matchesMonth(t time.Time) bool {
+	if len(r.ByMonth) == 0 {
+		return true
+	}
+
+	for _, m := range r.ByMonth {
+		if time.Month(m) == t.Month() {
+			return true
+		}
+	}
+
+	return false
+}
+
+func parseWeekdayNum(s string) (WeekdayNum, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	if len(s) < 2 {
+		return WeekdayNum{}, fmt.Errorf("invalid weekday %q", s)
+	}
+
+	day, ok := weekdays[s[len(s)-2:]]
+	if !ok {
+		return WeekdayNum{}, fmt.Errorf("invalid weekday %q", s)
+	}
+
+	n := 0
+	if prefix := s[:len(s)-2]; prefix != "" {
+		var err error
+		if n, err = strconv.Atoi(prefix); err != nil {
+			return WeekdayNum{}, fmt.Errorf("invalid weekday %q", s)
+		}
+	}
+
+	return WeekdayNum{N: n, Day: day}, nil
+}
+
+func parseInts(s string) ([]int, error) {
+	var out []int
+	for _, v := range strings.Split(s, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, n)
+	}
+
+	return out, nil
+}
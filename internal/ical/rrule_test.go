@@ -0,0 +1,146 @@
+package ical
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseRRule(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    RRule
+		wantErr bool
+	}{
+		{
+			in:   "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,-1FR,2we",
+			want: RRule{Freq: "WEEKLY", Interval: 2, ByDay: []WeekdayNum{{0, time.Monday}, {-1, time.Friday}, {2, time.Wednesday}}},
+		},
+		{
+			in:   "freq=monthly;BYMONTHDAY=1,-1;COUNT=3",
+			want: RRule{Freq: "MONTHLY", Interval: 1, Count: 3, ByMonthDay: []int{1, -1}},
+		},
+		{
+			in:   "FREQ=YEARLY;BYMONTH=3;UNTIL=20260101T000000Z",
+			want: RRule{Freq: "YEARLY", Interval: 1, ByMonth: []int{3}, Until: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+		},
+		{in: "FREQ=HOURLY", wantErr: true},
+		{in: "BYDAY=MO", wantErr: true},
+		{in: "FREQ=DAILY;INTERVAL=0", wantErr: true},
+		{in: "FREQ=DAILY;BYDAY=XX", wantErr: true},
+		{in: "FREQ=DAILY;COUNT", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseRRule(tt.in, time.UTC)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseRRule(%q): want error, got %+v", tt.in, got)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("ParseRRule(%q): %v", tt.in, err)
+			continue
+		}
+		if !got.Until.Equal(tt.want.Until) {
+			t.Errorf("ParseRRule(%q): until %v, want %v", tt.in, got.Until, tt.want.Until)
+		}
+		got.Until = tt.want.Until
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseRRule(%q):\ngot  %+v\nwant %+v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestRRuleExpand(t *testing.T) {
+	// Понедельник.
+	start := time.Date(2024, 3, 4, 10, 0, 0, 0, msk)
+	at := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 10, 0, 0, 0, msk)
+	}
+	farFuture := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		rule string
+		from time.Time
+		to   time.Time
+		want []time.Time
+	}{
+		{
+			name: "daily with count",
+			rule: "FREQ=DAILY;COUNT=3",
+			to:   farFuture,
+			want: []time.Time{at(2024, 3, 4), at(2024, 3, 5), at(2024, 3, 6)},
+		},
+		{
+			name: "workdays skip the weekend",
+			rule: "FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR;COUNT=6",
+			to:   farFuture,
+			want: []time.Time{at(2024, 3, 4), at(2024, 3, 5), at(2024, 3, 6), at(2024, 3, 7), at(2024, 3, 8), at(2024, 3, 11)},
+		},
+		{
+			name: "every other week on monday and thursday until",
+			rule: "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH;UNTIL=20240401T000000Z",
+			to:   farFuture,
+			want: []time.Time{at(2024, 3, 4), at(2024, 3, 7), at(2024, 3, 18), at(2024, 3, 21)},
+		},
+		{
+			name: "window cuts occurrences but count starts at dtstart",
+			rule: "FREQ=WEEKLY;COUNT=4",
+			from: at(2024, 3, 11),
+			to:   at(2024, 3, 25),
+			want: []time.Time{at(2024, 3, 11), at(2024, 3, 18)},
+		},
+		{
+			name: "last friday of the month",
+			rule: "FREQ=MONTHLY;BYDAY=-1FR;COUNT=3",
+			to:   farFuture,
+			want: []time.Time{at(2024, 3, 29), at(2024, 4, 26), at(2024, 5, 31)},
+		},
+		{
+			name: "last day of the month",
+			rule: "FREQ=MONTHLY;BYMONTHDAY=-1;COUNT=3",
+			to:   farFuture,
+			want: []time.Time{at(2024, 3, 31), at(2024, 4, 30), at(2024, 5, 31)},
+		},
+		{
+			name: "friday the 13th",
+			rule: "FREQ=MONTHLY;BYMONTHDAY=13;BYDAY=FR;COUNT=2",
+			to:   farFuture,
+			want: []time.Time{at(2024, 9, 13), at(2024, 12, 13)},
+		},
+		{
+			name: "yearly",
+			rule: "FREQ=YEARLY;COUNT=3",
+			to:   farFuture,
+			want: []time.Time{at(2024, 3, 4), at(2025, 3, 4), at(2026, 3, 4)},
+		},
+		{
+			name: "rule without occurrences stops",
+			rule: "FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=31",
+			to:   farFuture,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := ParseRRule(tt.rule, msk)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := rule.Expand(start, tt.from, tt.to)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for i := range tt.want {
+				if !got[i].Equal(tt.want[i]) {
+					t.Errorf("occurrence %d: got %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
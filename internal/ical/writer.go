@@ -0,0 +1,121 @@
+// Package ical формирует календари в формате iCalendar (RFC 5545).
+package ical
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Максимальная длина строки без CRLF в октетах, длиннее — переносится.
+const maxLineLen = 75
+
+const timeLayoutUTC = "20060102T150405Z"
+
+type Calendar struct {
+	ProdID string
+	Name   string
+	Events []Event
+}
+
+type Event struct {
+	UID        string
+	Stamp      time.Time
+	Start      time.Time
+	End        time.Time
+	Summary    string
+	Categories []string
+}
+
+// Write выводит календарь в w. Время записывается в UTC.
+func Write(w io.Writer, cal Calendar) error {
+	bw := bufio.NewWriter(w)
+	lw := lineWriter{w: bw}
+
+	lw.line("BEGIN", "VCALENDAR")
+	lw.line("VERSION", "2.0")
+	lw.line("PRODID", cal.ProdID)
+	lw.line("CALSCALE", "GREGORIAN")
+	lw.line("METHOD", "PUBLISH")
+	if cal.Name != "" {
+		lw.line("X-WR-CALNAME", escapeText(cal.Name))
+	}
+
+	for _, event := range cal.Events {
+		lw.line("BEGIN", "VEVENT")
+		lw.line("UID", event.UID)
+		lw.line("DTSTAMP", event.Stamp.UTC().Format(timeLayoutUTC))
+		lw.line("DTSTART", event.Start.UTC().Format(timeLayoutUTC))
+		lw.line("DTEND", event.End.UTC().Format(timeLayoutUTC))
+		lw.line("SUMMARY", escapeText(event.Summary))
+		if len(event.Categories) > 0 {
+			categories := make([]string, 0, len(event.Categories))
+			for _, c := range event.Categories {
+				categories = append(categories, escapeText(c))
+			}
+			lw.line("CATEGORIES", strings.Join(categories, ","))
+		}
+		lw.line("TRANSP", "TRANSPARENT")
+		lw.line("END", "VEVENT")
+	}
+
+	lw.line("END", "VCALENDAR")
+
+	if lw.err != nil {
+		return lw.err
+	}
+
+	return bw.Flush()
+}
+
+// lineWriter пишет строки содержимого с переносом длинных строк, первая ошибка сохраняется.
+type lineWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (lw *lineWriter) line(name, value string) {
+	if lw.err != nil {
+		return
+	}
+
+	_, lw.err = lw.w.WriteString(fold(name + ":" + value))
+}
+
+// fold переносит строку по 75 октетов, не разрывая символы UTF-8:
+// продолжение начинается с пробела.
+func fold(s string) string {
+	var b strings.Builder
+
+	lineLen := 0
+	for _, r := range s {
+		size := utf8.RuneLen(r)
+		if lineLen+size > maxLineLen {
+			b.WriteString("\r\n ")
+			// Пробел в начале продолжения занимает октет.
+			lineLen = 1
+		}
+
+		b.WriteRune(r)
+		lineLen += size
+	}
+	b.WriteString("\r\n")
+
+	return b.String()
+}
+
+// escapeText экранирует значение типа TEXT.
+func escapeText(s string) string {
+	return textEscaper.Replace(s)
+}
+
+var textEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`;`, `\;`,
+	`,`, `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+	"\r", `\n`,
+)
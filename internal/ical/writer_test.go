@@ -0,0 +1,111 @@
+package ical
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+var msk = time.FixedZone("MSK", 3*60*60)
+
+func testFeed() Calendar {
+	return Calendar{
+		ProdID: "-//BMSTU TIMETRACKERS//Time Tracker//RU",
+		Name:   "Time Tracker",
+		Events: []Event{
+			{
+				UID:        "entry-1@timetracker",
+				Stamp:      time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC),
+				Start:      time.Date(2024, 3, 4, 9, 0, 0, 0, msk),
+				End:        time.Date(2024, 3, 4, 10, 30, 0, 0, msk),
+				Summary:    "Landing page; review, fixes",
+				Categories: []string{"Website"},
+			},
+			{
+				UID:        "entry-2@timetracker",
+				Stamp:      time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC),
+				Start:      time.Date(2024, 3, 4, 11, 0, 0, 0, time.UTC),
+				End:        time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC),
+				Summary:    "Созвон с заказчиком по требованиям к отчетам и выгрузке данных\nвторая строка",
+				Categories: []string{`Клиент\Проект`},
+			},
+		},
+	}
+}
+
+func TestWriteGolden(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, testFeed()); err != nil {
+		t.Fatal(err)
+	}
+
+	golden := filepath.Join("testdata", "feed.ics")
+	if *update {
+		if err := os.WriteFile(golden, buf.Bytes(), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("feed differs from %s (run with -update to accept):\n%s", golden, buf.String())
+	}
+}
+
+func TestWriteFoldsLongLines(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, testFeed()); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, line := range strings.Split(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n") {
+		if len(line) > maxLineLen {
+			t.Errorf("line %d is %d octets long: %q", i+1, len(line), line)
+		}
+		if !utf8.ValidString(line) {
+			t.Errorf("line %d splits a UTF-8 character: %q", i+1, line)
+		}
+	}
+}
+
+// Лента читается обратно тем же разбором, что и импорт календаря.
+func TestWriteParseRoundTrip(t *testing.T) {
+	feed := testFeed()
+
+	var buf bytes.Buffer
+	if err := Write(&buf, feed); err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := Parse(&buf, time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if parsed.Name != feed.Name || len(parsed.Events) != len(feed.Events) {
+		t.Fatalf("got calendar %q with %d events", parsed.Name, len(parsed.Events))
+	}
+
+	for i, want := range feed.Events {
+		got := parsed.Events[i]
+		if got.UID != want.UID || got.Summary != want.Summary {
+			t.Errorf("event %d: got %q %q, want %q %q", i, got.UID, got.Summary, want.UID, want.Summary)
+		}
+		if !got.Start.Equal(want.Start) || !got.End.Equal(want.End) {
+			t.Errorf("event %d: got %v - %v, want %v - %v", i, got.Start, got.End, want.Start, want.End)
+		}
+		if strings.Join(got.Categories, "|") != strings.Join(want.Categories, "|") {
+			t.Errorf("event %d: categories %q, want %q", i, got.Categories, want.Categories)
+		}
+	}
+}
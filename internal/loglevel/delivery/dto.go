@@ -0,0 +1,9 @@
+package delivery
+
+type LogLevelIn struct {
+	Level string `json:"level" validate:"required" example:"debug"` // debug, info, warn, error или off.
+}
+
+type LogLevelOut struct {
+	Level string `json:"level" example:"info"` // debug, info, warn, error или off.
+}
@@ -0,0 +1,104 @@
+package delivery
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	usecaseDto "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/loglevel/usecase"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/response"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/validator"
+)
+
+type usecase interface {
+	GetLevel() string
+	SetLevel(name string) error
+}
+
+type Delivery struct {
+	usecase usecase
+
+	logger echo.Logger
+}
+
+// RegisterHandlers регистрирует обработчики администратора, доступ к ним проверяет adminMW.
+func RegisterHandlers(
+	e *echo.Echo,
+	usecase usecase,
+	adminMW echo.MiddlewareFunc,
+	logger echo.Logger,
+) {
+	handler := &Delivery{
+		usecase: usecase,
+
+		logger: logger,
+	}
+
+	e.GET("/admin/log-level", handler.GetLevel, adminMW)
+	e.PUT("/admin/log-level", handler.SetLevel, adminMW)
+}
+
+// GetLevel godoc
+// @Summary      Получить уровень логов.
+// @Description  Получить текущий уровень логов сервиса. Нужен токен администратора в заголовке Authorization: Bearer.
+// @Tags     	 admin
+// @Produce  application/json
+// @Success  200 {object} LogLevelOut "success get level"
+// @Failure 401 {object} response.Error "unauthorized"
+// @Failure 404 {object} response.Error "admin api is disabled"
+// @Router   /admin/log-level [get]
+func (d *Delivery) GetLevel(c echo.Context) error {
+	return c.JSON(http.StatusOK, LogLevelOut{Level: d.usecase.GetLevel()})
+}
+
+// SetLevel godoc
+// @Summary      Изменить уровень логов.
+// @Description  Изменить уровень логов без перезапуска, например включить debug на время разбора проблемы. После перезапуска уровень снова берется из конфига. Нужен токен администратора в заголовке Authorization: Bearer.
+// @Tags     	 admin
+// @Accept	 application/json
+// @Produce  application/json
+// @Param    level body LogLevelIn true "Уровень"
+// @Success  200 {object} LogLevelOut "success set level"
+// @Failure 400 {object} response.Error "bad request"
+// @Failure 401 {object} response.Error "unauthorized"
+// @Failure 404 {object} response.Error "admin api is disabled"
+// @Failure 422 {object} response.Error "unprocessable entity"
+// @Router   /admin/log-level [put]
+func (d *Delivery) SetLevel(c echo.Context) error {
+	var in LogLevelIn
+	err := c.Bind(&in)
+
+	if err != nil {
+		c.Logger().Errorf("bind request: %v", err)
+		return response.Status(http.StatusUnprocessableEntity)
+	}
+
+	if ok, err := validator.IsRequestValid(&in); !ok {
+		c.Logger().Errorf("validation: %v", err)
+		return response.ValidationError(err)
+	}
+
+	if err = d.usecase.SetLevel(in.Level); err != nil {
+		c.Logger().Errorf("usecase: %v", err)
+		return handleUsecaseError(err)
+	}
+
+	// Пишем без учета уровня: смена уровня должна остаться в логе, даже если лог выключен.
+	c.Logger().Printf("log level changed to %s", d.usecase.GetLevel())
+
+	return c.JSON(http.StatusOK, LogLevelOut{Level: d.usecase.GetLevel()})
+}
+
+func handleUsecaseError(err error) *response.Error {
+	if errors.Is(err, usecaseDto.ErrUnknownLevel) {
+		return response.NewError(
+			http.StatusBadRequest,
+			"unknown_log_level",
+			fmt.Sprintf("%s: %v", response.ErrorMsgsByCode[http.StatusBadRequest], err))
+	}
+
+	// По дефолту пятисотим.
+	return response.Status(http.StatusInternalServerError)
+}
@@ -0,0 +1,159 @@
+package delivery
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+
+	usecaseDto "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/loglevel/usecase"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/middleware"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/response"
+)
+
+const testAdminToken = "s3cret"
+
+// newLogLevelServer собирает echo с обработчиками как в main. Лог пишется в out.
+func newLogLevelServer(adminToken string) (*echo.Echo, *log.Logger, *bytes.Buffer) {
+	out := &bytes.Buffer{}
+	logger := log.New("test")
+	logger.SetOutput(out)
+	logger.SetLevel(log.INFO)
+
+	e := echo.New()
+	e.Logger = logger
+	e.HTTPErrorHandler = response.ErrorHandler
+
+	adminMW := middleware.NewAdminMiddleware(adminToken)
+	RegisterHandlers(e, usecaseDto.NewUsecase(logger), adminMW.Admin, logger)
+
+	return e, logger, out
+}
+
+func doRequest(e *echo.Echo, method, authorization, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/admin/log-level", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	if authorization != "" {
+		req.Header.Set(echo.HeaderAuthorization, authorization)
+	}
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestLogLevelRequiresAdminToken(t *testing.T) {
+	tests := []struct {
+		name          string
+		adminToken    string
+		authorization string
+		wantStatus    int
+	}{
+		{name: "admin api disabled", authorization: "Bearer " + testAdminToken, wantStatus: http.StatusNotFound},
+		{name: "no header", adminToken: testAdminToken, wantStatus: http.StatusUnauthorized},
+		{name: "wrong token", adminToken: testAdminToken, authorization: "Bearer wrong", wantStatus: http.StatusUnauthorized},
+		{name: "not bearer", adminToken: testAdminToken, authorization: "Basic " + testAdminToken, wantStatus: http.StatusUnauthorized},
+		{name: "token prefix", adminToken: testAdminToken, authorization: "Bearer s3c", wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, logger, _ := newLogLevelServer(tt.adminToken)
+
+			if rec := doRequest(e, http.MethodGet, tt.authorization, ""); rec.Code != tt.wantStatus {
+				t.Errorf("GET: status %d, want %d", rec.Code, tt.wantStatus)
+			}
+
+			if rec := doRequest(e, http.MethodPut, tt.authorization, `{"level":"debug"}`); rec.Code != tt.wantStatus {
+				t.Errorf("PUT: status %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if logger.Level() != log.INFO {
+				t.Errorf("level changed to %d without admin token", logger.Level())
+			}
+		})
+	}
+}
+
+func TestSetLogLevel(t *testing.T) {
+	e, logger, out := newLogLevelServer(testAdminToken)
+	auth := "Bearer " + testAdminToken
+
+	rec := doRequest(e, http.MethodGet, auth, "")
+	if rec.Code != http.StatusOK || strings.TrimSpace(rec.Body.String()) != `{"level":"info"}` {
+		t.Fatalf("GET: %d %s, want 200 info", rec.Code, rec.Body)
+	}
+
+	logger.Debug("before switch")
+
+	rec = doRequest(e, http.MethodPut, auth, `{"level":"debug"}`)
+	if rec.Code != http.StatusOK || strings.TrimSpace(rec.Body.String()) != `{"level":"debug"}` {
+		t.Fatalf("PUT debug: %d %s, want 200 debug", rec.Code, rec.Body)
+	}
+	if logger.Level() != log.DEBUG {
+		t.Errorf("level %d, want debug", logger.Level())
+	}
+
+	// Новый уровень действует сразу, смена уровня остается в логе.
+	logger.Debug("after switch")
+	if strings.Contains(out.String(), "before switch") || !strings.Contains(out.String(), "after switch") {
+		t.Errorf("debug output %q, want only messages after the switch", out)
+	}
+	if !strings.Contains(out.String(), "log level changed to debug") {
+		t.Errorf("log %q does not record the level change", out)
+	}
+
+	// Уровень не зависит от регистра.
+	rec = doRequest(e, http.MethodPut, auth, `{"level":"WARN"}`)
+	if rec.Code != http.StatusOK || logger.Level() != log.WARN {
+		t.Errorf("PUT WARN: %d, level %d, want 200 warn", rec.Code, logger.Level())
+	}
+
+	rec = doRequest(e, http.MethodGet, auth, "")
+	if strings.TrimSpace(rec.Body.String()) != `{"level":"warn"}` {
+		t.Errorf("GET after switch: %s, want warn", rec.Body)
+	}
+}
+
+func TestSetLogLevelInvalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		wantCode int
+		wantErr  string
+	}{
+		{name: "unknown level", body: `{"level":"trace"}`, wantCode: http.StatusBadRequest, wantErr: "unknown_log_level"},
+		{name: "missing level", body: `{}`, wantCode: http.StatusBadRequest, wantErr: response.CodeValidationFailed},
+		{name: "broken json", body: `{"level":`, wantCode: http.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, logger, _ := newLogLevelServer(testAdminToken)
+
+			rec := doRequest(e, http.MethodPut, "Bearer "+testAdminToken, tt.body)
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status %d, want %d", rec.Code, tt.wantCode)
+			}
+
+			if tt.wantErr != "" {
+				var problem response.Error
+				if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil {
+					t.Fatalf("unmarshal problem: %v", err)
+				}
+				if problem.Code != tt.wantErr {
+					t.Errorf("code %q, want %q", problem.Code, tt.wantErr)
+				}
+			}
+
+			if logger.Level() != log.INFO {
+				t.Errorf("level changed to %d by an invalid request", logger.Level())
+			}
+		})
+	}
+}
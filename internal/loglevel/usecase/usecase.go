@@ -0,0 +1,60 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/labstack/gommon/log"
+)
+
+var ErrUnknownLevel = errors.New("unknown log level")
+
+// Уровни по возрастанию важности, off отключает лог.
+var levels = map[string]log.Lvl{
+	"debug": log.DEBUG,
+	"info":  log.INFO,
+	"warn":  log.WARN,
+	"error": log.ERROR,
+	"off":   log.OFF,
+}
+
+type logger interface {
+	Level() log.Lvl
+	SetLevel(v log.Lvl)
+}
+
+type Usecase struct {
+	logger logger
+}
+
+func NewUsecase(logger logger) *Usecase {
+	return &Usecase{
+		logger: logger,
+	}
+}
+
+// GetLevel возвращает текущий уровень логов сервиса.
+func (u *Usecase) GetLevel() string {
+	current := u.logger.Level()
+	for name, level := range levels {
+		if level == current {
+			return name
+		}
+	}
+
+	return fmt.Sprint(current)
+}
+
+// SetLevel меняет уровень логов без перезапуска. Действует до остановки сервиса,
+// после перезапуска уровень снова берется из конфига.
+func (u *Usecase) SetLevel(name string) error {
+	level, ok := levels[strings.ToLower(name)]
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownLevel, name)
+	}
+
+	u.logger.SetLevel(level)
+
+	return nil
+}
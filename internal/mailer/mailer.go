@@ -0,0 +1,251 @@
+// Package mailer отправляет письма через SMTP (RFC 5321) в формате MIME multipart/alternative.
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+// Режимы шифрования соединения с сервером.
+const (
+	// Без шифрования, только для локальных серверов.
+	TLSNone = "none"
+	// Соединение без шифрования повышается командой STARTTLS (обычно порт 587).
+	TLSStartTLS = "starttls"
+	// Соединение сразу по TLS (обычно порт 465).
+	TLSImplicit = "tls"
+)
+
+var ErrUnknownTLSMode = errors.New("unknown smtp tls mode")
+
+// Message письмо с текстовой и HTML-версией. Почтовый клиент показывает HTML,
+// если умеет, иначе текст.
+type Message struct {
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+type Config struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	// Адрес отправителя, можно с именем: "Time Tracker <noreply@example.com>".
+	From    string
+	TLSMode string
+	// Таймаут всей отправки письма, включая соединение.
+	Timeout time.Duration
+}
+
+type Mailer struct {
+	config Config
+	from   *mail.Address
+}
+
+func NewMailer(config Config) (*Mailer, error) {
+	switch config.TLSMode {
+	case "":
+		config.TLSMode = TLSStartTLS
+	case TLSNone, TLSStartTLS, TLSImplicit:
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownTLSMode, config.TLSMode)
+	}
+
+	from, err := mail.ParseAddress(config.From)
+	if err != nil {
+		return nil, fmt.Errorf("parse from: %v", err)
+	}
+
+	return &Mailer{
+		config: config,
+		from:   from,
+	}, nil
+}
+
+// Send отправляет письмо одному получателю.
+func (m *Mailer) Send(ctx context.Context, msg Message) error {
+	to, err := mail.ParseAddress(msg.To)
+	if err != nil {
+		return fmt.Errorf("parse to: %v", err)
+	}
+
+	body, err := m.build(msg, to)
+	if err != nil {
+		return fmt.Errorf("build message: %v", err)
+	}
+
+	if m.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, m.config.Timeout)
+		defer cancel()
+	}
+
+	client, err := m.dial(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = client.Close()
+	}()
+
+	if err = client.Mail(m.from.Address); err != nil {
+		return fmt.Errorf("mail from: %v", err)
+	}
+	if err = client.Rcpt(to.Address); err != nil {
+		return fmt.Errorf("rcpt to: %v", err)
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("data: %v", err)
+	}
+	if _, err = w.Write(body); err != nil {
+		return fmt.Errorf("write data: %v", err)
+	}
+	if err = w.Close(); err != nil {
+		return fmt.Errorf("close data: %v", err)
+	}
+
+	if err = client.Quit(); err != nil {
+		return fmt.Errorf("quit: %v", err)
+	}
+
+	return nil
+}
+
+// dial соединяется с сервером, включает шифрование и авторизуется.
+func (m *Mailer) dial(ctx context.Context) (*smtp.Client, error) {
+	addr := net.JoinHostPort(m.config.Host, fmt.Sprint(m.config.Port))
+	tlsConfig := &tls.Config{ServerName: m.config.Host}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("dial: %v", err)
+	}
+
+	// net/smtp не принимает контекст, поэтому его срок переносится на соединение.
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	if m.config.TLSMode == TLSImplicit {
+		conn = tls.Client(conn, tlsConfig)
+	}
+
+	client, err := smtp.NewClient(conn, m.config.Host)
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("new client: %v", err)
+	}
+
+	if m.config.TLSMode == TLSStartTLS {
+		if err = client.StartTLS(tlsConfig); err != nil {
+			_ = client.Close()
+			return nil, fmt.Errorf("starttls: %v", err)
+		}
+	}
+
+	if m.config.Username != "" {
+		// PlainAuth отказывается передавать пароль без TLS, кроме как на localhost.
+		auth := smtp.PlainAuth("", m.config.Username, m.config.Password, m.config.Host)
+		if err = client.Auth(auth); err != nil {
+			_ = client.Close()
+			return nil, fmt.Errorf("auth: %v", err)
+		}
+	}
+
+	return client, nil
+}
+
+// build формирует письмо: заголовки и multipart/alternative с текстовой и HTML-частью.
+func (m *Mailer) build(msg Message, to *mail.Address) ([]byte, error) {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+
+	messageID, err := m.messageID()
+	if err != nil {
+		return nil, err
+	}
+
+	header := []string{
+		"From: " + m.from.String(),
+		"To: " + to.String(),
+		"Subject: " + mime.QEncoding.Encode("utf-8", msg.Subject),
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		"Message-ID: " + messageID,
+		"MIME-Version: 1.0",
+		`Content-Type: multipart/alternative; boundary="` + mw.Boundary() + `"`,
+	}
+	buf.WriteString(strings.Join(header, "\r\n"))
+	buf.WriteString("\r\n\r\n")
+
+	// Сначала менее предпочтительная версия (RFC 2046, 5.1.4).
+	if err = writePart(mw, "text/plain", msg.Text); err != nil {
+		return nil, err
+	}
+	if msg.HTML != "" {
+		if err = writePart(mw, "text/html", msg.HTML); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = mw.Close(); err != nil {
+		return nil, fmt.Errorf("close multipart: %v", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (m *Mailer) messageID() (string, error) {
+	raw := make([]byte, 16)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("generate message id: %v", err)
+	}
+
+	domain := m.config.Host
+	if at := strings.LastIndex(m.from.Address, "@"); at >= 0 {
+		domain = m.from.Address[at+1:]
+	}
+
+	return "<" + hex.EncodeToString(raw) + "@" + domain + ">", nil
+}
+
+// writePart добавляет часть в quoted-printable: строки письма не длиннее 76 символов.
+func writePart(mw *multipart.Writer, contentType, body string) error {
+	w, err := mw.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType + "; charset=utf-8"},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return fmt.Errorf("create part: %v", err)
+	}
+
+	qp := quotedprintable.NewWriter(w)
+	if _, err = qp.Write([]byte(body)); err != nil {
+		return fmt.Errorf("write part: %v", err)
+	}
+
+	if err = qp.Close(); err != nil {
+		return fmt.Errorf("close part: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+package collector
+
+import (
+	"context"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// За какой период считать созданные записи.
+const createdEntriesPeriod = time.Minute
+
+type repository interface {
+	CountActiveEntries(ctx context.Context, now time.Time) (int64, error)
+	CountCreatedEntries(ctx context.Context, period time.Duration) (int64, error)
+}
+
+// Collector в фоне пересчитывает бизнес-метрики по базе. Значения общие для всех экземпляров
+// сервиса, поэтому их не нужно суммировать по экземплярам.
+type Collector struct {
+	repository repository
+	interval   time.Duration
+
+	activeEntries  prometheus.Gauge
+	createdEntries prometheus.Gauge
+
+	logger echo.Logger
+}
+
+func NewCollector(
+	repository repository,
+	registerer prometheus.Registerer,
+	namespace string,
+	interval time.Duration,
+	logger echo.Logger,
+) *Collector {
+	c := &Collector{
+		repository: repository,
+		interval:   interval,
+
+		activeEntries: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "active_timers",
+			Help:      "Number of time entries in progress right now.",
+		}),
+		createdEntries: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "entries_created_last_minute",
+			Help:      "Number of time entries created during the last minute.",
+		}),
+
+		logger: logger,
+	}
+
+	registerer.MustRegister(c.activeEntries, c.createdEntries)
+
+	return c
+}
+
+// Run пересчитывает метрики сразу и затем раз в interval, пока не отменен контекст.
+func (c *Collector) Run(ctx context.Context) {
+	if c.interval <= 0 {
+		c.logger.Warn("business metrics are disabled: interval is not set")
+		return
+	}
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		c.collect(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *Collector) collect(ctx context.Context) {
+	// Запрос не должен пережить следующий пересчет.
+	ctx, cancel := context.WithTimeout(ctx, c.interval)
+	defer cancel()
+
+	active, err := c.repository.CountActiveEntries(ctx, time.Now())
+	if err != nil {
+		c.logger.Errorf("count active entries: %v", err)
+	} else {
+		c.activeEntries.Set(float64(active))
+	}
+
+	created, err := c.repository.CountCreatedEntries(ctx, createdEntriesPeriod)
+	if err != nil {
+		c.logger.Errorf("count created entries: %v", err)
+	} else {
+		c.createdEntries.Set(float64(created))
+	}
+}
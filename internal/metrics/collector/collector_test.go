@@ -0,0 +1,62 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/labstack/gommon/log"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+type fakeRepository struct {
+	active, created int64
+	err             error
+	period          time.Duration
+}
+
+func (r *fakeRepository) CountActiveEntries(context.Context, time.Time) (int64, error) {
+	return r.active, r.err
+}
+
+func (r *fakeRepository) CountCreatedEntries(_ context.Context, period time.Duration) (int64, error) {
+	r.period = period
+	return r.created, r.err
+}
+
+func gaugeValue(t *testing.T, g prometheus.Gauge) float64 {
+	t.Helper()
+
+	var m dto.Metric
+	if err := g.Write(&m); err != nil {
+		t.Fatal(err)
+	}
+
+	return m.GetGauge().GetValue()
+}
+
+func TestCollect(t *testing.T) {
+	logger := log.New("metrics")
+	logger.SetOutput(io.Discard)
+
+	r := &fakeRepository{active: 3, created: 12}
+	c := NewCollector(r, prometheus.NewRegistry(), "test", time.Minute, logger)
+
+	c.collect(context.Background())
+	if active, created := gaugeValue(t, c.activeEntries), gaugeValue(t, c.createdEntries); active != 3 || created != 12 {
+		t.Errorf("active %v, created %v, want 3 and 12", active, created)
+	}
+	if r.period != createdEntriesPeriod {
+		t.Errorf("created entries counted for %s, want %s", r.period, createdEntriesPeriod)
+	}
+
+	// Пока база недоступна, метрики сохраняют последние посчитанные значения.
+	r.active, r.created, r.err = 0, 0, errors.New("connection refused")
+	c.collect(context.Background())
+	if active, created := gaugeValue(t, c.activeEntries), gaugeValue(t, c.createdEntries); active != 3 || created != 12 {
+		t.Errorf("after error: active %v, created %v, want the previous 3 and 12", active, created)
+	}
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type Repository struct {
+	db    *sqlx.DB
+	close func() error
+}
+
+func NewRepository(db *sqlx.DB) *Repository {
+	return &Repository{
+		db: db,
+		close: func() error {
+			return db.Close()
+		},
+	}
+}
+
+// Close закрывает пул соединений с базой.
+func (r *Repository) Close() error {
+	return r.close()
+}
+
+// CountActiveEntries возвращает число записей, идущих в момент now: запущенных таймеров.
+func (r *Repository) CountActiveEntries(ctx context.Context, now time.Time) (int64, error) {
+	var count int64
+	err := r.db.QueryRowContext(ctx,
+		`SELECT count(*)
+		FROM entries
+		WHERE time_end > $1 AND time_start <= $1 AND deleted_at IS NULL`, now).
+		Scan(&count)
+
+	if err != nil {
+		return 0, fmt.Errorf("scan: %w", err)
+	}
+
+	return count, nil
+}
+
+// CountCreatedEntries возвращает число записей, созданных за последний period, по журналу
+// действий. Импортированные записи журналируются итогом импорта и здесь не учитываются.
+func (r *Repository) CountCreatedEntries(ctx context.Context, period time.Duration) (int64, error) {
+	var count int64
+	err := r.db.QueryRowContext(ctx,
+		`SELECT count(*)
+		FROM audit_events
+		WHERE entity_type = 'entry' AND action = 'create'
+		  AND created_at > now() - make_interval(secs => $1)`, period.Seconds()).
+		Scan(&count)
+
+	if err != nil {
+		return 0, fmt.Errorf("scan: %w", err)
+	}
+
+	return count, nil
+}
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/response"
+)
+
+type AdminMiddleware struct {
+	token string
+}
+
+// NewAdminMiddleware создает проверку токена администратора. С пустым токеном
+// обработчики администратора отключены.
+func NewAdminMiddleware(token string) *AdminMiddleware {
+	return &AdminMiddleware{
+		token: token,
+	}
+}
+
+// Admin пропускает запрос, только если в заголовке Authorization: Bearer передан токен администратора.
+func (m *AdminMiddleware) Admin(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if m.token == "" {
+			return response.Status(http.StatusNotFound)
+		}
+
+		token, ok := strings.CutPrefix(c.Request().Header.Get(echo.HeaderAuthorization), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(m.token)) != 1 {
+			return response.Status(http.StatusUnauthorized)
+		}
+
+		return next(c)
+	}
+}
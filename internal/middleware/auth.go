@@ -1,28 +1,77 @@
 package middleware
 
 import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
 	"github.com/labstack/echo/v4"
+
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/response"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/roles"
+	workspaceUsecase "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/workspace/usecase"
 )
 
+type workspaceUC interface {
+	GetMemberRole(ctx context.Context, workspaceID, userID int64) (roles.Role, error)
+}
+
 type AuthMiddleware struct {
 	// authUC authUsecase.UsecaseI
+	workspaceUC workspaceUC
 }
 
-func NewAuthMiddleware() *AuthMiddleware {
-	return &AuthMiddleware{}
+func NewAuthMiddleware(workspaceUC workspaceUC) *AuthMiddleware {
+	return &AuthMiddleware{
+		workspaceUC: workspaceUC,
+	}
 }
 
 func (m *AuthMiddleware) Auth(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		if c.Request().URL.Path == "/signup" || c.Request().URL.Path == "/signin" ||
 			c.Request().URL.Path == "/auth" || c.Request().URL.Path == "/prometheus" ||
-			c.Request().URL.Path == "/favicon.ico" {
+			c.Request().URL.Path == "/favicon.ico" ||
+			c.Request().URL.Path == "/healthz" || c.Request().URL.Path == "/readyz" ||
+			// Календарные клиенты не умеют авторизоваться, лента защищена токеном в адресе.
+			strings.HasPrefix(c.Request().URL.Path, "/ical/") ||
+			// Обработчики администратора проверяют свой токен.
+			strings.HasPrefix(c.Request().URL.Path, "/admin/") {
 			return next(c)
 		}
 
 		// TODO логика с авторизацией
 
-		c.Set("user_id", int64(1))
+		userID := int64(1)
+		c.Set("user_id", userID)
+
+		// Для маршрутов пространства проверяем, что пользователь его участник,
+		// и прокидываем роль дальше. Права на конкретные действия проверяются в usecase.
+		if workspaceIDStr := c.Param("workspace_id"); workspaceIDStr != "" {
+			workspaceID, err := strconv.ParseInt(workspaceIDStr, 10, 64)
+			if err != nil {
+				c.Logger().Errorf("parse int: %v", err)
+				return response.InvalidParam("workspace_id", "int")
+			}
+
+			role, err := m.workspaceUC.GetMemberRole(c.Request().Context(), workspaceID, userID)
+			if err != nil {
+				if errors.Is(err, workspaceUsecase.ErrForbidden) {
+					return response.Status(http.StatusForbidden)
+				}
+
+				c.Logger().Errorf("get member role: %v", err)
+				if problem := response.ContextError(err); problem != nil {
+					return problem
+				}
+				return response.Status(http.StatusInternalServerError)
+			}
+
+			c.Set("workspace_role", role)
+		}
+
 		return next(c)
 	}
 }
@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/requestid"
+)
+
+// Теги заголовка лога, которые подставляет RequestLogger. В общем логе сервиса они пустые.
+const (
+	requestIDTag = "${request_id}"
+	userIDTag    = "${user_id}"
+)
+
+type LoggerMiddleware struct {
+	logger echo.Logger
+	header string
+}
+
+// NewLoggerMiddleware создает мидлвару логов запроса. header — заголовок общего логгера
+// с тегами ${request_id} и ${user_id}.
+func NewLoggerMiddleware(logger echo.Logger, header string) *LoggerMiddleware {
+	return &LoggerMiddleware{
+		logger: logger,
+		header: header,
+	}
+}
+
+// RequestLogger подменяет логгер запроса: ошибки, которые пишут обработчики через
+// c.Logger(), содержат идентификатор запроса и пользователя. Уровень берется из общего
+// логгера в момент запроса. Регистрируется после авторизации.
+func (m *LoggerMiddleware) RequestLogger(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if !strings.Contains(m.header, requestIDTag) && !strings.Contains(m.header, userIDTag) {
+			return next(c)
+		}
+
+		var userID string
+		if id, ok := c.Get("user_id").(int64); ok {
+			userID = strconv.FormatInt(id, 10)
+		}
+
+		// Идентификатор запроса проверен мидлварой RequestID, экранировать его не нужно.
+		header := strings.NewReplacer(
+			requestIDTag, requestid.FromContext(c.Request().Context()),
+			userIDTag, userID,
+		).Replace(m.header)
+
+		logger := log.New(m.logger.Prefix())
+		logger.SetOutput(m.logger.Output())
+		logger.SetLevel(m.logger.Level())
+		logger.SetHeader(header)
+		c.SetLogger(logger)
+
+		return next(c)
+	}
+}
+
+// AccessLogUserID пишет в access-лог поле user_id для тега ${custom}, если запрос авторизован.
+func AccessLogUserID(c echo.Context, buf *bytes.Buffer) (int, error) {
+	userID, ok := c.Get("user_id").(int64)
+	if !ok {
+		return 0, nil
+	}
+
+	return buf.WriteString(`"user_id":"` + strconv.FormatInt(userID, 10) + `",`)
+}
@@ -0,0 +1,109 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+)
+
+const testLogHeader = `{"level":"${level}","request_id":"${request_id}","user_id":"${user_id}"}`
+
+// newLoggerServer собирает echo с мидлварами как в main: RequestID, авторизация, RequestLogger.
+// Обработчик пишет ошибку в c.Logger().
+func newLoggerServer(out *bytes.Buffer) *echo.Echo {
+	logger := log.New("test")
+	logger.SetOutput(out)
+	logger.SetLevel(log.INFO)
+	logger.SetHeader(testLogHeader)
+
+	auth := func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if c.Request().Header.Get("X-Test-User") != "" {
+				c.Set("user_id", int64(7))
+			}
+			return next(c)
+		}
+	}
+
+	e := echo.New()
+	e.Logger = logger
+	e.Use(RequestID, auth, NewLoggerMiddleware(logger, testLogHeader).RequestLogger)
+
+	e.GET("/me/entries", func(c echo.Context) error {
+		c.Logger().Debug("hidden")
+		c.Logger().Error("repo get entries")
+		return c.NoContent(http.StatusOK)
+	})
+
+	return e
+}
+
+type logLine struct {
+	Level     string `json:"level"`
+	RequestID string `json:"request_id"`
+	UserID    string `json:"user_id"`
+	Message   string `json:"message"`
+}
+
+func TestRequestLoggerAddsIDs(t *testing.T) {
+	tests := []struct {
+		name          string
+		requestID     string
+		user          bool
+		wantRequestID string
+		wantUserID    string
+	}{
+		{name: "client request id", requestID: "abc-123", user: true, wantRequestID: "abc-123", wantUserID: "7"},
+		{name: "anonymous", requestID: "abc-123", wantRequestID: "abc-123"},
+		// Идентификатор со спецсимволами сломал бы JSON лога, вместо него генерируется новый.
+		{name: "unsafe request id", requestID: `x","level":"fake`, user: true, wantUserID: "7"},
+		{name: "generated request id", user: true, wantUserID: "7"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			e := newLoggerServer(out)
+
+			req := httptest.NewRequest(http.MethodGet, "/me/entries", nil)
+			if tt.requestID != "" {
+				req.Header.Set(echo.HeaderXRequestID, tt.requestID)
+			}
+			if tt.user {
+				req.Header.Set("X-Test-User", "1")
+			}
+
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			responseID := rec.Header().Get(echo.HeaderXRequestID)
+			if tt.wantRequestID != "" && responseID != tt.wantRequestID {
+				t.Errorf("response request id %q, want %q", responseID, tt.wantRequestID)
+			}
+			if tt.wantRequestID == "" && (len(responseID) != 32 || responseID == tt.requestID) {
+				t.Errorf("response request id %q, want a generated one", responseID)
+			}
+
+			lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+			if len(lines) != 1 {
+				t.Fatalf("log %q, want one error line", out)
+			}
+
+			var line logLine
+			if err := json.Unmarshal([]byte(lines[0]), &line); err != nil {
+				t.Fatalf("log line %q is not JSON: %v", lines[0], err)
+			}
+
+			want := logLine{Level: "ERROR", RequestID: responseID, UserID: tt.wantUserID, Message: "repo get entries"}
+			if line != want {
+				t.Errorf("log line %+v, want %+v", line, want)
+			}
+		})
+	}
+}
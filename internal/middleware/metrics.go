@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/response"
+)
+
+// Метка маршрута для запросов, не попавших ни в один маршрут: адрес запроса в метку
+// не пишем, чтобы сканеры не раздували число временных рядов.
+const unmatchedRoute = "unmatched"
+
+type MetricsMiddleware struct {
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+// NewMetricsMiddleware регистрирует метрики HTTP-запросов: число запросов и гистограмму
+// длительности по методу, шаблону маршрута и коду ответа.
+func NewMetricsMiddleware(registerer prometheus.Registerer, namespace string, buckets []float64) *MetricsMiddleware {
+	if len(buckets) == 0 {
+		buckets = prometheus.DefBuckets
+	}
+
+	labels := []string{"method", "route", "status"}
+	m := &MetricsMiddleware{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "Number of HTTP requests by method, route and status.",
+		}, labels),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "HTTP request latency by method, route and status.",
+			Buckets:   buckets,
+		}, labels),
+	}
+
+	registerer.MustRegister(m.requests, m.duration)
+
+	return m
+}
+
+func (m *MetricsMiddleware) Metrics(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		start := time.Now()
+
+		err := next(c)
+
+		status := responseStatus(c, err)
+
+		route := c.Path()
+		if route == "" {
+			route = unmatchedRoute
+		}
+
+		labels := prometheus.Labels{
+			"method": c.Request().Method,
+			"route":  route,
+			"status": strconv.Itoa(status),
+		}
+		m.requests.With(labels).Inc()
+		m.duration.With(labels).Observe(time.Since(start).Seconds())
+
+		return err
+	}
+}
+
+// responseStatus возвращает код ответа. Ошибка обработчика еще не записана в ответ:
+// код берется из нее так же, как это сделает HTTPErrorHandler.
+func responseStatus(c echo.Context, err error) int {
+	if err == nil {
+		return c.Response().Status
+	}
+
+	var problem *response.Error
+	if errors.As(err, &problem) {
+		return problem.Status
+	}
+
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.Code
+	}
+
+	return http.StatusInternalServerError
+}
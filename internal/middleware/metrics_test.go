@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/response"
+)
+
+// gatherCounts возвращает значения метрики по меткам "method route status".
+// Для гистограммы берется число наблюдений.
+func gatherCounts(t *testing.T, registry *prometheus.Registry, name string) map[string]uint64 {
+	t.Helper()
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("gather: %v", err)
+	}
+
+	counts := make(map[string]uint64)
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+
+		for _, metric := range family.GetMetric() {
+			labels := make(map[string]string)
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+
+			key := labels["method"] + " " + labels["route"] + " " + labels["status"]
+			if metric.GetCounter() != nil {
+				counts[key] = uint64(metric.GetCounter().GetValue())
+			} else {
+				counts[key] = metric.GetHistogram().GetSampleCount()
+			}
+		}
+	}
+
+	return counts
+}
+
+func TestMetricsUseRouteTemplate(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	mw := NewMetricsMiddleware(registry, "test", nil)
+
+	e := echo.New()
+	e.HTTPErrorHandler = response.ErrorHandler
+	e.Use(mw.Metrics)
+
+	e.GET("/me/projects/:id/stat", func(c echo.Context) error {
+		if c.Param("id") == "404" {
+			return response.NotFound("project")
+		}
+		return c.NoContent(http.StatusOK)
+	})
+	e.GET("/me/entries", func(c echo.Context) error {
+		return errors.New("connection refused")
+	})
+
+	for _, path := range []string{
+		"/me/projects/1/stat",
+		"/me/projects/2/stat",
+		"/me/projects/3/stat?time_end=2024-03-01",
+		"/me/projects/404/stat",
+		"/me/entries",
+		// Адреса без маршрута сливаются в одну метку.
+		"/wp-login.php",
+		"/.env",
+	} {
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	want := map[string]uint64{
+		"GET /me/projects/:id/stat 200": 3,
+		"GET /me/projects/:id/stat 404": 1,
+		"GET /me/entries 500":           1,
+		"GET unmatched 404":             2,
+	}
+
+	if got := gatherCounts(t, registry, "test_http_requests_total"); !reflect.DeepEqual(got, want) {
+		t.Errorf("requests_total %v, want %v", got, want)
+	}
+	if got := gatherCounts(t, registry, "test_http_request_duration_seconds"); !reflect.DeepEqual(got, want) {
+		t.Errorf("request_duration_seconds counts %v, want %v", got, want)
+	}
+}
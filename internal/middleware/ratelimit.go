@@ -0,0 +1,104 @@
+package middleware
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/ratelimit"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/response"
+)
+
+type rateLimitStore interface {
+	Take(ctx context.Context, key string, limit ratelimit.Limit) (ratelimit.Result, error)
+}
+
+type RateLimitMiddleware struct {
+	store        rateLimitStore
+	defaultGroup ratelimit.Group
+	routes       map[string]ratelimit.Group
+}
+
+// NewRateLimitMiddleware создает мидлвару лимитов. Маршрут попадает в группу из groups,
+// в списке маршрутов которой он есть, остальные маршруты — в defaultGroup.
+func NewRateLimitMiddleware(store rateLimitStore, defaultGroup ratelimit.Group, groups []ratelimit.Group) *RateLimitMiddleware {
+	routes := make(map[string]ratelimit.Group)
+	for _, group := range groups {
+		for _, route := range group.Routes {
+			routes[route] = group
+		}
+	}
+
+	return &RateLimitMiddleware{
+		store:        store,
+		defaultGroup: defaultGroup,
+		routes:       routes,
+	}
+}
+
+// ByIP ограничивает запросы групп с ключом ip. Ставится до авторизации, чтобы
+// лимит работал и для маршрутов без нее, например /admin/log-level.
+func (m *RateLimitMiddleware) ByIP(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		group := m.group(c)
+		if group.Key != ratelimit.KeyIP {
+			return next(c)
+		}
+
+		return m.limit(c, next, group, c.RealIP())
+	}
+}
+
+// ByUser ограничивает запросы групп с ключом user. Ставится после авторизации,
+// запросы без пользователя не ограничивает.
+func (m *RateLimitMiddleware) ByUser(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		group := m.group(c)
+		if group.Key != ratelimit.KeyUser {
+			return next(c)
+		}
+
+		userID, ok := c.Get("user_id").(int64)
+		if !ok {
+			return next(c)
+		}
+
+		return m.limit(c, next, group, strconv.FormatInt(userID, 10))
+	}
+}
+
+func (m *RateLimitMiddleware) group(c echo.Context) ratelimit.Group {
+	if group, ok := m.routes[c.Request().Method+" "+c.Path()]; ok {
+		return group
+	}
+
+	return m.defaultGroup
+}
+
+func (m *RateLimitMiddleware) limit(c echo.Context, next echo.HandlerFunc, group ratelimit.Group, id string) error {
+	if group.Limit.Unlimited() {
+		return next(c)
+	}
+
+	key := group.Name + ":" + string(group.Key) + ":" + id
+
+	res, err := m.store.Take(c.Request().Context(), key, group.Limit)
+	if err != nil {
+		// Лимиты защищают сервис, а не являются его частью: без хранилища пропускаем запрос.
+		c.Logger().Errorf("take rate limit token: %v", err)
+		return next(c)
+	}
+
+	if !res.Allowed {
+		c.Logger().Warnf("rate limit exceeded: group %s, %s %s", group.Name, group.Key, id)
+
+		retryAfter := int64(math.Ceil(res.RetryAfter.Seconds()))
+		c.Response().Header().Set("Retry-After", strconv.FormatInt(max(retryAfter, 1), 10))
+		return response.Status(http.StatusTooManyRequests)
+	}
+
+	return next(c)
+}
@@ -0,0 +1,187 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/ratelimit"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/response"
+)
+
+type failingStore struct{}
+
+func (failingStore) Take(context.Context, string, ratelimit.Limit) (ratelimit.Result, error) {
+	return ratelimit.Result{}, errors.New("redis is down")
+}
+
+// newRateLimitServer собирает echo с лимитами как в main: ByIP до авторизации, ByUser после.
+func newRateLimitServer(store rateLimitStore) *echo.Echo {
+	mw := NewRateLimitMiddleware(store,
+		ratelimit.Group{Name: "default", Key: ratelimit.KeyUser, Limit: ratelimit.Limit{Rate: 1, Burst: 2}},
+		[]ratelimit.Group{
+			{
+				Name:   "auth",
+				Key:    ratelimit.KeyIP,
+				Limit:  ratelimit.Limit{Rate: 0.1, Burst: 1},
+				Routes: []string{"POST /signin"},
+			},
+			{
+				Name:   "unlimited",
+				Key:    ratelimit.KeyUser,
+				Routes: []string{"GET /healthz"},
+			},
+		})
+
+	auth := func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if userID := c.Request().Header.Get("X-Test-User"); userID != "" {
+				id := int64(1)
+				if userID == "2" {
+					id = 2
+				}
+				c.Set("user_id", id)
+			}
+			return next(c)
+		}
+	}
+
+	e := echo.New()
+	e.HTTPErrorHandler = response.ErrorHandler
+	e.IPExtractor = echo.ExtractIPDirect()
+	e.Use(mw.ByIP, auth, mw.ByUser)
+
+	ok := func(c echo.Context) error { return c.NoContent(http.StatusOK) }
+	e.GET("/me/entries", ok)
+	e.POST("/signin", ok)
+	e.GET("/healthz", ok)
+
+	return e
+}
+
+func doRequest(e *echo.Echo, method, path, user, ip string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	req.RemoteAddr = ip + ":12345"
+	if user != "" {
+		req.Header.Set("X-Test-User", user)
+	}
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestRateLimitByUser(t *testing.T) {
+	e := newRateLimitServer(ratelimit.NewMemoryStore())
+
+	for i := 0; i < 2; i++ {
+		if rec := doRequest(e, http.MethodGet, "/me/entries", "1", "10.0.0.1"); rec.Code != http.StatusOK {
+			t.Fatalf("request %d: status %d", i+1, rec.Code)
+		}
+	}
+
+	// Смена адреса не помогает: лимит считается по пользователю.
+	rec := doRequest(e, http.MethodGet, "/me/entries", "1", "10.0.0.2")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("request over burst: status %d, want 429", rec.Code)
+	}
+
+	// Rate 1 в секунду: токен появится не позже чем через секунду.
+	if got := rec.Header().Get("Retry-After"); got != "1" {
+		t.Errorf("Retry-After %q, want 1", got)
+	}
+
+	var problem response.Error
+	if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("decode body %q: %v", rec.Body.String(), err)
+	}
+	if problem.Code != response.CodeRateLimited || problem.Status != http.StatusTooManyRequests {
+		t.Errorf("problem %+v, want code %s", problem, response.CodeRateLimited)
+	}
+	if ct := rec.Header().Get(echo.HeaderContentType); ct != response.MIMEProblemJSON {
+		t.Errorf("content type %q", ct)
+	}
+
+	if rec = doRequest(e, http.MethodGet, "/me/entries", "2", "10.0.0.1"); rec.Code != http.StatusOK {
+		t.Errorf("another user: status %d, want 200", rec.Code)
+	}
+}
+
+func TestRateLimitByIP(t *testing.T) {
+	e := newRateLimitServer(ratelimit.NewMemoryStore())
+
+	if rec := doRequest(e, http.MethodPost, "/signin", "", "10.0.0.1"); rec.Code != http.StatusOK {
+		t.Fatalf("first signin: status %d", rec.Code)
+	}
+
+	rec := doRequest(e, http.MethodPost, "/signin", "", "10.0.0.1")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("second signin: status %d, want 429", rec.Code)
+	}
+	// Rate 0.1 в секунду: следующий токен через 10 секунд.
+	if got := rec.Header().Get("Retry-After"); got != "10" {
+		t.Errorf("Retry-After %q, want 10", got)
+	}
+
+	if rec = doRequest(e, http.MethodPost, "/signin", "", "10.0.0.2"); rec.Code != http.StatusOK {
+		t.Errorf("signin from another address: status %d, want 200", rec.Code)
+	}
+
+	// Группа auth ограничивает только свои маршруты, /me/entries живет по лимиту default.
+	if rec = doRequest(e, http.MethodGet, "/me/entries", "1", "10.0.0.1"); rec.Code != http.StatusOK {
+		t.Errorf("other route from the limited address: status %d, want 200", rec.Code)
+	}
+}
+
+// Клиент не может получить новую корзину, подставляя адрес в заголовки прокси.
+func TestRateLimitByIPIgnoresSpoofedHeaders(t *testing.T) {
+	e := newRateLimitServer(ratelimit.NewMemoryStore())
+
+	for i, header := range []string{echo.HeaderXForwardedFor, echo.HeaderXRealIP} {
+		ip := "10.0.0." + strconv.Itoa(i+1)
+		if rec := doRequest(e, http.MethodPost, "/signin", "", ip); rec.Code != http.StatusOK {
+			t.Fatalf("first signin: status %d", rec.Code)
+		}
+
+		req := httptest.NewRequest(http.MethodPost, "/signin", nil)
+		req.RemoteAddr = ip + ":12345"
+		req.Header.Set(header, "203.0.113."+strconv.Itoa(i+1))
+
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		if rec.Code != http.StatusTooManyRequests {
+			t.Errorf("signin with spoofed %s: status %d, want 429", header, rec.Code)
+		}
+	}
+}
+
+func TestRateLimitSkips(t *testing.T) {
+	e := newRateLimitServer(ratelimit.NewMemoryStore())
+
+	for i := 0; i < 5; i++ {
+		if rec := doRequest(e, http.MethodGet, "/healthz", "1", "10.0.0.1"); rec.Code != http.StatusOK {
+			t.Fatalf("unlimited group, request %d: status %d", i+1, rec.Code)
+		}
+		// Без пользователя ограничивать по пользователю нечего.
+		if rec := doRequest(e, http.MethodGet, "/me/entries", "", "10.0.0.1"); rec.Code != http.StatusOK {
+			t.Fatalf("anonymous request %d: status %d", i+1, rec.Code)
+		}
+	}
+}
+
+func TestRateLimitStoreError(t *testing.T) {
+	e := newRateLimitServer(failingStore{})
+
+	for i := 0; i < 5; i++ {
+		if rec := doRequest(e, http.MethodGet, "/me/entries", "1", "10.0.0.1"); rec.Code != http.StatusOK {
+			t.Fatalf("request %d with broken store: status %d, want 200", i+1, rec.Code)
+		}
+	}
+}
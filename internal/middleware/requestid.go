@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"regexp"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/requestid"
+)
+
+// Идентификатор от клиента принимается, только если он короткий и без спецсимволов:
+// он попадает в логи и в журнал аудита.
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// RequestID берет идентификатор запроса из заголовка X-Request-ID или генерирует новый,
+// возвращает его в ответе и кладет в контекст запроса.
+func RequestID(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id := c.Request().Header.Get(echo.HeaderXRequestID)
+		if !validRequestID.MatchString(id) {
+			id = newRequestID()
+		}
+
+		// Заголовок запроса тоже заменяем: из него идентификатор берет access-лог.
+		c.Request().Header.Set(echo.HeaderXRequestID, id)
+		c.Response().Header().Set(echo.HeaderXRequestID, id)
+		c.SetRequest(c.Request().WithContext(requestid.NewContext(c.Request().Context(), id)))
+
+		return next(c)
+	}
+}
+
+func newRequestID() string {
+	raw := make([]byte, 16)
+	_, _ = rand.Read(raw)
+
+	return hex.EncodeToString(raw)
+}
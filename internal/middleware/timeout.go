@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type TimeoutMiddleware struct {
+	defaultTimeout time.Duration
+	routes         map[string]time.Duration
+}
+
+// NewTimeoutMiddleware создает мидлвару таймаутов. routes задает таймауты маршрутов
+// по ключу "METHOD path", для остальных маршрутов действует defaultTimeout.
+func NewTimeoutMiddleware(defaultTimeout time.Duration, routes map[string]time.Duration) *TimeoutMiddleware {
+	return &TimeoutMiddleware{
+		defaultTimeout: defaultTimeout,
+		routes:         routes,
+	}
+}
+
+// Timeout ограничивает время обработки запроса через его контекст: по истечении
+// таймаута прерываются запросы к базе, и обработчик отвечает 504.
+func (m *TimeoutMiddleware) Timeout(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		timeout, ok := m.routes[c.Request().Method+" "+c.Path()]
+		if !ok {
+			timeout = m.defaultTimeout
+		}
+
+		if timeout <= 0 {
+			return next(c)
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request().Context(), timeout)
+		defer cancel()
+
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		return next(c)
+	}
+}
@@ -0,0 +1,131 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/response"
+)
+
+// slowHandler ждет отмены контекста запроса, как долгий запрос к базе, и отвечает
+// так же, как обработчики сервиса. В ctxErr попадает причина отмены.
+func slowHandler(ctxErr *error) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		select {
+		case <-c.Request().Context().Done():
+		case <-time.After(5 * time.Second):
+			return c.NoContent(http.StatusOK)
+		}
+
+		*ctxErr = c.Request().Context().Err()
+		return response.ContextError(*ctxErr)
+	}
+}
+
+// deadlineHandler отвечает, через сколько истечет контекст запроса.
+func deadlineHandler(c echo.Context) error {
+	deadline, ok := c.Request().Context().Deadline()
+	if !ok {
+		return c.String(http.StatusOK, "none")
+	}
+
+	return c.String(http.StatusOK, time.Until(deadline).Round(time.Hour).String())
+}
+
+func newTimeoutServer(ctxErr *error) *echo.Echo {
+	mw := NewTimeoutMiddleware(time.Hour, map[string]time.Duration{
+		"GET /me/projects/:id/stat": 20 * time.Millisecond,
+		"GET /me/entries/export":    0,
+		"GET /me/reports":           3 * time.Hour,
+	})
+
+	e := echo.New()
+	e.HTTPErrorHandler = response.ErrorHandler
+	e.Use(mw.Timeout)
+
+	e.GET("/me/projects/:id/stat", slowHandler(ctxErr))
+	e.GET("/me/projects", deadlineHandler)
+	e.GET("/me/entries/export", deadlineHandler)
+	e.GET("/me/reports", deadlineHandler)
+
+	return e
+}
+
+func TestTimeoutCancelsContext(t *testing.T) {
+	var ctxErr error
+	e := newTimeoutServer(&ctxErr)
+
+	// Таймаут маршрута ищется по шаблону пути, а не по адресу запроса.
+	started := time.Now()
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/me/projects/5/stat", nil))
+
+	if elapsed := time.Since(started); elapsed > time.Second {
+		t.Errorf("request took %s, want the route timeout", elapsed)
+	}
+	if !errors.Is(ctxErr, context.DeadlineExceeded) {
+		t.Errorf("handler context error %v, want %v", ctxErr, context.DeadlineExceeded)
+	}
+
+	if rec.Code != http.StatusGatewayTimeout {
+		t.Fatalf("status %d, want 504", rec.Code)
+	}
+
+	var problem response.Error
+	if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("unmarshal problem: %v", err)
+	}
+	if want := response.Status(http.StatusGatewayTimeout).Code; problem.Code != want {
+		t.Errorf("code %q, want %q", problem.Code, want)
+	}
+}
+
+func TestTimeoutClientGone(t *testing.T) {
+	var ctxErr error
+	e := newTimeoutServer(&ctxErr)
+
+	// Клиент отключился раньше таймаута: отмена приходит из контекста запроса.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/me/projects/5/stat", nil).WithContext(ctx))
+
+	if !errors.Is(ctxErr, context.Canceled) {
+		t.Errorf("handler context error %v, want %v", ctxErr, context.Canceled)
+	}
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("status %d, want 503", rec.Code)
+	}
+}
+
+func TestTimeoutPerRoute(t *testing.T) {
+	var ctxErr error
+	e := newTimeoutServer(&ctxErr)
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/me/projects", "1h0m0s"},
+		{"/me/reports", "3h0m0s"},
+		// Нулевой таймаут маршрута снимает ограничение.
+		{"/me/entries/export", "none"},
+	}
+
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+		if got := rec.Body.String(); rec.Code != http.StatusOK || got != tt.want {
+			t.Errorf("%s: %d %q, want deadline in %s", tt.path, rec.Code, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/response"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/tracing"
+)
+
+// newTracingServer подменяет глобальный провайдер трейсов на запись в память
+// и собирает echo с мидлварой Tracing. Обработчики создают span'ы usecase через tracing.Start.
+func newTracingServer(t *testing.T) (*echo.Echo, *tracetest.SpanRecorder) {
+	t.Helper()
+
+	prevProvider, prevPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prevProvider)
+		otel.SetTextMapPropagator(prevPropagator)
+	})
+
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	e := echo.New()
+	e.HTTPErrorHandler = response.ErrorHandler
+	e.Use(Tracing)
+
+	e.GET("/me/projects/:id/stat", func(c echo.Context) error {
+		_, span := tracing.Start(c.Request().Context(), "project.GetProjectStat")
+		defer span.End()
+
+		return c.NoContent(http.StatusOK)
+	})
+	e.GET("/me/entries", func(c echo.Context) error {
+		return errors.New("connection refused")
+	})
+
+	return e, recorder
+}
+
+func spanAttribute(span sdktrace.ReadOnlySpan, key attribute.Key) attribute.Value {
+	for _, kv := range span.Attributes() {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+
+	return attribute.Value{}
+}
+
+func TestTracingContinuesTrace(t *testing.T) {
+	e, recorder := newTracingServer(t)
+
+	const (
+		traceID      = "4bf92f3577b34da6a3ce929d0e0e4736"
+		parentSpanID = "00f067aa0ba902b7"
+	)
+
+	req := httptest.NewRequest(http.MethodGet, "/me/projects/12/stat", nil)
+	req.Header.Set("traceparent", "00-"+traceID+"-"+parentSpanID+"-01")
+	e.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("ended %d spans, want usecase and request spans", len(spans))
+	}
+
+	usecaseSpan, requestSpan := spans[0], spans[1]
+	if requestSpan.Name() != "GET /me/projects/:id/stat" {
+		t.Errorf("request span name %q, want the route template", requestSpan.Name())
+	}
+	if route := spanAttribute(requestSpan, "http.route").AsString(); route != "/me/projects/:id/stat" {
+		t.Errorf("http.route %q, want the route template", route)
+	}
+
+	// Span запроса продолжает трейс клиента, span usecase вложен в span запроса.
+	if got := requestSpan.SpanContext().TraceID().String(); got != traceID {
+		t.Errorf("request span trace id %s, want %s from traceparent", got, traceID)
+	}
+	if got := requestSpan.Parent().SpanID().String(); got != parentSpanID {
+		t.Errorf("request span parent %s, want %s from traceparent", got, parentSpanID)
+	}
+	if usecaseSpan.Name() != "project.GetProjectStat" || usecaseSpan.Parent().SpanID() != requestSpan.SpanContext().SpanID() {
+		t.Errorf("usecase span %q has parent %s, want child of request span %s",
+			usecaseSpan.Name(), usecaseSpan.Parent().SpanID(), requestSpan.SpanContext().SpanID())
+	}
+}
+
+func TestTracingStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		wantName   string
+		wantStatus int64
+		wantCode   codes.Code
+	}{
+		{name: "ok", path: "/me/projects/1/stat", wantName: "GET /me/projects/:id/stat", wantStatus: http.StatusOK, wantCode: codes.Unset},
+		{name: "internal error", path: "/me/entries", wantName: "GET /me/entries", wantStatus: http.StatusInternalServerError, wantCode: codes.Error},
+		// Клиентские ошибки не отмечают span ошибкой.
+		{name: "unmatched", path: "/wp-login.php", wantName: "GET " + unmatchedRoute, wantStatus: http.StatusNotFound, wantCode: codes.Unset},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, recorder := newTracingServer(t)
+			e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			spans := recorder.Ended()
+			span := spans[len(spans)-1]
+			if span.Name() != tt.wantName {
+				t.Errorf("span name %q, want %q", span.Name(), tt.wantName)
+			}
+			if status := spanAttribute(span, "http.response.status_code").AsInt64(); status != tt.wantStatus {
+				t.Errorf("status attribute %d, want %d", status, tt.wantStatus)
+			}
+			if span.Status().Code != tt.wantCode {
+				t.Errorf("span status %v, want %v", span.Status().Code, tt.wantCode)
+			}
+		})
+	}
+}
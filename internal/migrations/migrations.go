@@ -0,0 +1,395 @@
+// Package migrations применяет версионированные миграции схемы базы и запоминает
+// примененные версии в таблице schema_migrations.
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	ErrInvalidMigration = errors.New("invalid migration")
+	ErrNoDownMigration  = errors.New("migration has no down script")
+	ErrUnknownVersion   = errors.New("unknown migration version")
+)
+
+// Ключ advisory-блокировки, общий для всех экземпляров сервиса: пока один экземпляр
+// применяет миграции, остальные ждут.
+const lockKey = 7_462_831_905
+
+var fileNameRe = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	// Пустой, если миграцию нельзя откатить.
+	Down string
+}
+
+type Status struct {
+	Version int64
+	Name    string
+	// nil, если миграция еще не применена.
+	AppliedAt *time.Time
+	// Миграция применена, но ее файла нет: база новее бинарника.
+	Missing bool
+}
+
+type Migrator struct {
+	db         *sqlx.DB
+	migrations []Migration
+}
+
+// NewMigrator читает миграции из fsys: файлы NNN_name.up.sql и необязательные NNN_name.down.sql.
+func NewMigrator(db *sqlx.DB, fsys fs.FS) (*Migrator, error) {
+	migrations, err := load(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{
+		db:         db,
+		migrations: migrations,
+	}, nil
+}
+
+// Up применяет все непримененные миграции по возрастанию версии, каждую в своей транзакции.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var done []Migration
+
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+
+			err = inTx(ctx, conn, func(tx *sql.Tx) error {
+				if _, err := tx.ExecContext(ctx, migration.Up); err != nil {
+					return err
+				}
+
+				_, err := tx.ExecContext(ctx,
+					`INSERT INTO schema_migrations (version, name) VALUES ($1, $2);`,
+					migration.Version, migration.Name)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("apply %d_%s: %w", migration.Version, migration.Name, err)
+			}
+
+			done = append(done, migration)
+		}
+
+		return nil
+	})
+
+	return done, err
+}
+
+// Down откатывает steps последних примененных миграций.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var done []Migration
+
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		versions := make([]int64, 0, len(applied))
+		for version := range applied {
+			versions = append(versions, version)
+		}
+		sort.Slice(versions, func(i, j int) bool { return versions[i] > versions[j] })
+
+		if steps < len(versions) {
+			versions = versions[:steps]
+		}
+
+		for _, version := range versions {
+			migration, ok := m.find(version)
+			if !ok {
+				return fmt.Errorf("%w: %d", ErrUnknownVersion, version)
+			}
+			if migration.Down == "" {
+				return fmt.Errorf("%w: %d_%s", ErrNoDownMigration, migration.Version, migration.Name)
+			}
+
+			err = inTx(ctx, conn, func(tx *sql.Tx) error {
+				if _, err := tx.ExecContext(ctx, migration.Down); err != nil {
+					return err
+				}
+
+				_, err := tx.ExecContext(ctx, `DELETE FROM schema_migrations WHERE version = $1;`, migration.Version)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("revert %d_%s: %w", migration.Version, migration.Name, err)
+			}
+
+			done = append(done, migration)
+		}
+
+		return nil
+	})
+
+	return done, err
+}
+
+// Baseline отмечает миграции до version включительно примененными, не выполняя их.
+// Нужен для баз, созданных до появления schema_migrations.
+func (m *Migrator) Baseline(ctx context.Context, version int64) ([]Migration, error) {
+	if _, ok := m.find(version); !ok {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownVersion, version)
+	}
+
+	var done []Migration
+
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		return inTx(ctx, conn, func(tx *sql.Tx) error {
+			for _, migration := range m.migrations {
+				if migration.Version > version {
+					break
+				}
+
+				res, err := tx.ExecContext(ctx,
+					`INSERT INTO schema_migrations (version, name) VALUES ($1, $2) ON CONFLICT DO NOTHING;`,
+					migration.Version, migration.Name)
+				if err != nil {
+					return fmt.Errorf("mark %d_%s: %w", migration.Version, migration.Name, err)
+				}
+
+				if rows, _ := res.RowsAffected(); rows > 0 {
+					done = append(done, migration)
+				}
+			}
+
+			return nil
+		})
+	})
+
+	return done, err
+}
+
+// Status возвращает все известные и примененные миграции по возрастанию версии.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	var res []Status
+
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		res = mergeStatus(m.migrations, applied)
+		return nil
+	})
+
+	return res, err
+}
+
+// mergeStatus сопоставляет известные миграции с примененными. Примененные версии без файла
+// миграции попадают в результат с Missing.
+func mergeStatus(migrations []Migration, applied map[int64]appliedMigration) []Status {
+	res := make([]Status, 0, len(migrations))
+	known := make(map[int64]struct{}, len(migrations))
+
+	for _, migration := range migrations {
+		known[migration.Version] = struct{}{}
+
+		status := Status{
+			Version: migration.Version,
+			Name:    migration.Name,
+		}
+		if a, ok := applied[migration.Version]; ok {
+			appliedAt := a.appliedAt
+			status.AppliedAt = &appliedAt
+		}
+
+		res = append(res, status)
+	}
+
+	for version, a := range applied {
+		if _, ok := known[version]; ok {
+			continue
+		}
+
+		appliedAt := a.appliedAt
+		res = append(res, Status{
+			Version:   version,
+			Name:      a.name,
+			AppliedAt: &appliedAt,
+			Missing:   true,
+		})
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].Version < res[j].Version })
+
+	return res
+}
+
+func (m *Migrator) find(version int64) (Migration, bool) {
+	for _, migration := range m.migrations {
+		if migration.Version == version {
+			return migration, true
+		}
+	}
+
+	return Migration{}, false
+}
+
+// withLock выполняет fn на отдельном соединении под advisory-блокировкой. Блокировка
+// сессионная, поэтому все запросы идут через одно соединение.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("get conn: %w", err)
+	}
+
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	if _, err = conn.ExecContext(ctx, `SELECT pg_advisory_lock($1);`, lockKey); err != nil {
+		return fmt.Errorf("lock: %w", err)
+	}
+
+	defer func() {
+		// Блокировку снимаем и при отмененном контексте, иначе она живет до закрытия соединения.
+		_, _ = conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1);`, lockKey)
+	}()
+
+	query := `CREATE TABLE IF NOT EXISTS schema_migrations
+				(
+					version    BIGINT PRIMARY KEY,
+					name       TEXT      NOT NULL,
+					applied_at TIMESTAMP NOT NULL DEFAULT now()
+				);`
+	if _, err = conn.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("create schema_migrations: %w", err)
+	}
+
+	return fn(conn)
+}
+
+type appliedMigration struct {
+	name      string
+	appliedAt time.Time
+}
+
+func appliedVersions(ctx context.Context, conn *sql.Conn) (map[int64]appliedMigration, error) {
+	rows, err := conn.QueryContext(ctx, `SELECT version, name, applied_at FROM schema_migrations;`)
+	if err != nil {
+		return nil, fmt.Errorf("select schema_migrations: %w", err)
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	applied := make(map[int64]appliedMigration)
+	for rows.Next() {
+		var (
+			version int64
+			a       appliedMigration
+		)
+		if err = rows.Scan(&version, &a.name, &a.appliedAt); err != nil {
+			return nil, fmt.Errorf("scan: %w", err)
+		}
+
+		applied[version] = a
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows err: %w", err)
+	}
+
+	return applied, nil
+}
+
+func inTx(ctx context.Context, conn *sql.Conn, fn func(tx *sql.Tx) error) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if err = fn(tx); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// load читает миграции из корня fsys и сортирует их по версии.
+func load(fsys fs.FS) ([]Migration, error) {
+	files, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return nil, fmt.Errorf("glob: %w", err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, file := range files {
+		match := fileNameRe.FindStringSubmatch(path.Base(file))
+		if match == nil {
+			return nil, fmt.Errorf("%w: bad file name %q", ErrInvalidMigration, file)
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: bad version in %q", ErrInvalidMigration, file)
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		}
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("%w: version %d used by %s and %s", ErrInvalidMigration, version, migration.Name, match[2])
+		}
+
+		body, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", file, err)
+		}
+
+		if match[3] == "up" {
+			migration.Up = string(body)
+		} else {
+			migration.Down = string(body)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("%w: %d_%s has no up script", ErrInvalidMigration, migration.Version, migration.Name)
+		}
+
+		migrations = append(migrations, *migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	return migrations, nil
+}
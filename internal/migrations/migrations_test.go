@@ -0,0 +1,190 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/db"
+)
+
+func loadEmbedded(t *testing.T) []Migration {
+	t.Helper()
+
+	files, err := fs.Sub(db.Migrations, "migrations")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	migrations, err := load(files)
+	if err != nil {
+		t.Fatalf("load embedded migrations: %v", err)
+	}
+
+	return migrations
+}
+
+// Миграции, встроенные в бинарник, загружаются, идут подряд с 1 и откатываются.
+func TestEmbeddedMigrations(t *testing.T) {
+	migrations := loadEmbedded(t)
+	if len(migrations) == 0 {
+		t.Fatal("no embedded migrations")
+	}
+
+	for i, migration := range migrations {
+		if want := int64(i + 1); migration.Version != want {
+			t.Fatalf("migration %d_%s: version gap, want %d", migration.Version, migration.Name, want)
+		}
+		if strings.TrimSpace(migration.Up) == "" {
+			t.Errorf("migration %d_%s: empty up script", migration.Version, migration.Name)
+		}
+		if strings.TrimSpace(migration.Down) == "" {
+			t.Errorf("migration %d_%s: no down script", migration.Version, migration.Name)
+		}
+	}
+
+	// Все .sql файлы каталога — миграции: файл с опечаткой в имени не должен потеряться.
+	names, err := fs.Glob(db.Migrations, "migrations/*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 2*len(migrations) {
+		t.Errorf("got %d files for %d migrations, want up and down for each", len(names), len(migrations))
+	}
+}
+
+func TestLoad(t *testing.T) {
+	file := func(body string) *fstest.MapFile {
+		return &fstest.MapFile{Data: []byte(body)}
+	}
+
+	tests := []struct {
+		name    string
+		fsys    fstest.MapFS
+		want    []Migration
+		wantErr string
+	}{
+		{
+			name: "ordered by numeric version",
+			fsys: fstest.MapFS{
+				"10_ten.up.sql":   file("ten"),
+				"9_nine.up.sql":   file("nine"),
+				"9_nine.down.sql": file("drop nine"),
+				"README.md":       file("not a migration"),
+			},
+			want: []Migration{
+				{Version: 9, Name: "nine", Up: "nine", Down: "drop nine"},
+				{Version: 10, Name: "ten", Up: "ten"},
+			},
+		},
+		{
+			name: "empty directory",
+			fsys: fstest.MapFS{},
+			want: []Migration{},
+		},
+		{
+			name:    "bad file name",
+			fsys:    fstest.MapFS{"001-init.up.sql": file("")},
+			wantErr: "bad file name",
+		},
+		{
+			name: "version used twice",
+			fsys: fstest.MapFS{
+				"001_init.up.sql":  file("a"),
+				"001_other.up.sql": file("b"),
+			},
+			wantErr: "version 1 used by",
+		},
+		{
+			name:    "down without up",
+			fsys:    fstest.MapFS{"001_init.down.sql": file("drop")},
+			wantErr: "has no up script",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := load(tt.fsys)
+
+			if tt.wantErr != "" {
+				if !errors.Is(err, ErrInvalidMigration) || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("got error %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeStatus(t *testing.T) {
+	migrations := []Migration{
+		{Version: 1, Name: "init"},
+		{Version: 2, Name: "add_goals"},
+		{Version: 3, Name: "add_workspaces"},
+	}
+	appliedAt := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	applied := map[int64]appliedMigration{
+		1: {name: "init", appliedAt: appliedAt},
+		2: {name: "add_goals", appliedAt: appliedAt.Add(time.Minute)},
+		// База новее бинарника.
+		5: {name: "from_future", appliedAt: appliedAt.Add(time.Hour)},
+	}
+
+	got := mergeStatus(migrations, applied)
+
+	type status struct {
+		version   int64
+		name      string
+		appliedAt time.Time
+		missing   bool
+	}
+	want := []status{
+		{1, "init", appliedAt, false},
+		{2, "add_goals", appliedAt.Add(time.Minute), false},
+		{3, "add_workspaces", time.Time{}, false},
+		{5, "from_future", appliedAt.Add(time.Hour), true},
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("got %d statuses, want %d: %+v", len(got), len(want), got)
+	}
+	for i, w := range want {
+		g := status{version: got[i].Version, name: got[i].Name, missing: got[i].Missing}
+		if got[i].AppliedAt != nil {
+			g.appliedAt = *got[i].AppliedAt
+		}
+
+		if g != w {
+			t.Errorf("status %d: got %+v, want %+v", i, g, w)
+		}
+	}
+
+	// Статус не меняет карту примененных миграций.
+	if len(applied) != 3 {
+		t.Errorf("applied versions changed: %v", applied)
+	}
+}
+
+// Неизвестная версия отклоняется до обращения к базе.
+func TestBaselineUnknownVersion(t *testing.T) {
+	migrator := &Migrator{migrations: loadEmbedded(t)}
+
+	for _, version := range []int64{0, int64(len(migrator.migrations) + 1)} {
+		_, err := migrator.Baseline(context.Background(), version)
+		if !errors.Is(err, ErrUnknownVersion) {
+			t.Errorf("baseline %d: got error %v, want %v", version, err, ErrUnknownVersion)
+		}
+	}
+}
@@ -0,0 +1,11 @@
+package delivery
+
+type NotificationSettingsIn struct {
+	WeeklyDigest  *bool `json:"weekly_digest" validate:"required" example:"true"`   // Получать еженедельную сводку.
+	GoalReminders *bool `json:"goal_reminders" validate:"required" example:"false"` // Получать напоминания о целях.
+}
+
+type NotificationSettingsOut struct {
+	WeeklyDigest  bool `json:"weekly_digest" example:"true"`   // Получать еженедельную сводку.
+	GoalReminders bool `json:"goal_reminders" example:"false"` // Получать напоминания о целях.
+}
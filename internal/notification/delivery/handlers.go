@@ -0,0 +1,128 @@
+package delivery
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	usecaseDto "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/notification/usecase"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/response"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/validator"
+)
+
+type usecase interface {
+	GetSettings(ctx context.Context, userID int64) (usecaseDto.Settings, error)
+	UpdateSettings(ctx context.Context, userID int64, settings usecaseDto.Settings) error
+}
+
+type Delivery struct {
+	usecase usecase
+
+	logger echo.Logger
+}
+
+func RegisterHandlers(
+	e *echo.Echo,
+	usecase usecase,
+	logger echo.Logger,
+) {
+	handler := &Delivery{
+		usecase: usecase,
+
+		logger: logger,
+	}
+
+	e.GET("/me/notifications", handler.GetSettings)
+	e.PUT("/me/notifications", handler.UpdateSettings)
+}
+
+// GetSettings godoc
+// @Summary      Получить настройки уведомлений.
+// @Description  Получить настройки email-уведомлений: еженедельной сводки и напоминаний о целях. По умолчанию все уведомления включены.
+// @Tags     	 notifications
+// @Accept	 application/json
+// @Produce  application/json
+// @Success  200 {object} NotificationSettingsOut "success get settings"
+// @Failure 500 {object} response.Error "internal server error"
+// @Router   /me/notifications [get]
+func (d *Delivery) GetSettings(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, ok := c.Get("user_id").(int64)
+	if !ok {
+		c.Logger().Error("can't parse context user_id")
+		return response.Status(http.StatusInternalServerError)
+	}
+
+	settings, err := d.usecase.GetSettings(ctx, userID)
+	if err != nil {
+		c.Logger().Errorf("usecase: %v", err)
+		return handleUsecaseError(err)
+	}
+
+	return c.JSON(http.StatusOK, NotificationSettingsOut{
+		WeeklyDigest:  settings.WeeklyDigest,
+		GoalReminders: settings.GoalReminders,
+	})
+}
+
+// UpdateSettings godoc
+// @Summary      Изменить настройки уведомлений.
+// @Description  Включить или отключить еженедельную сводку (время по проектам за неделю и прогресс целей) и напоминания о целях, которые отстают от темпа или скоро заканчиваются.
+// @Tags     	 notifications
+// @Accept	 application/json
+// @Produce  application/json
+// @Param    settings body NotificationSettingsIn true "Настройки"
+// @Success  200 {object} NotificationSettingsOut "success update settings"
+// @Failure 500 {object} response.Error "internal server error"
+// @Failure 400 {object} response.Error "bad request"
+// @Failure 422 {object} response.Error "unprocessable entity"
+// @Router   /me/notifications [put]
+func (d *Delivery) UpdateSettings(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var in NotificationSettingsIn
+	err := c.Bind(&in)
+
+	if err != nil {
+		c.Logger().Errorf("bind request: %v", err)
+		return response.Status(http.StatusUnprocessableEntity)
+	}
+
+	if ok, err := validator.IsRequestValid(&in); !ok {
+		c.Logger().Errorf("validation: %v", err)
+		return response.ValidationError(err)
+	}
+
+	userID, ok := c.Get("user_id").(int64)
+	if !ok {
+		c.Logger().Error("can't parse context user_id")
+		return response.Status(http.StatusInternalServerError)
+	}
+
+	settings := usecaseDto.Settings{
+		WeeklyDigest:  *in.WeeklyDigest,
+		GoalReminders: *in.GoalReminders,
+	}
+
+	if err = d.usecase.UpdateSettings(ctx, userID, settings); err != nil {
+		c.Logger().Errorf("usecase: %v", err)
+		return handleUsecaseError(err)
+	}
+
+	return c.JSON(http.StatusOK, NotificationSettingsOut{
+		WeeklyDigest:  settings.WeeklyDigest,
+		GoalReminders: settings.GoalReminders,
+	})
+}
+
+func handleUsecaseError(err error) *response.Error {
+	// Запрос не уложился в таймаут или был отменен.
+	if problem := response.ContextError(err); problem != nil {
+		return problem
+	}
+
+	// По дефолту пятисотим.
+	return response.Status(http.StatusInternalServerError)
+}
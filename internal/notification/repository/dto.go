@@ -0,0 +1,13 @@
+package repository
+
+type Settings struct {
+	WeeklyDigest  bool `db:"weekly_digest"`
+	GoalReminders bool `db:"goal_reminders"`
+}
+
+// Recipient пользователь, которому можно отправлять уведомления.
+type Recipient struct {
+	UserID int64  `db:"user_id"`
+	Name   string `db:"name"`
+	Email  string `db:"email"`
+}
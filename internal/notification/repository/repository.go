@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Виды уведомлений, совпадают с колонками notification_settings.
+const (
+	KindWeeklyDigest  = "weekly_digest"
+	KindGoalReminders = "goal_reminders"
+)
+
+type Repository struct {
+	db    *sqlx.DB
+	close func() error
+}
+
+func NewRepository(db *sqlx.DB) *Repository {
+	return &Repository{
+		db: db,
+		close: func() error {
+			return db.Close()
+		},
+	}
+}
+
+// Close закрывает пул соединений с базой.
+func (r *Repository) Close() error {
+	return r.close()
+}
+
+// GetSettings возвращает настройки пользователя; если он их не менял — все уведомления включены.
+func (r *Repository) GetSettings(ctx context.Context, userID int64) (Settings, error) {
+	var settings Settings
+	err := r.db.QueryRowContext(ctx,
+		`SELECT weekly_digest, goal_reminders FROM notification_settings WHERE user_id = $1`, userID).
+		Scan(&settings.WeeklyDigest, &settings.GoalReminders)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Settings{WeeklyDigest: true, GoalReminders: true}, nil
+		}
+
+		return Settings{}, fmt.Errorf("scan: %w", err)
+	}
+
+	return settings, nil
+}
+
+func (r *Repository) SetSettings(ctx context.Context, userID int64, settings Settings) error {
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO notification_settings (user_id, weekly_digest, goal_reminders)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO UPDATE
+		SET weekly_digest = EXCLUDED.weekly_digest,
+			goal_reminders = EXCLUDED.goal_reminders,
+			updated_at = now()`,
+		userID, settings.WeeklyDigest, settings.GoalReminders)
+
+	if err != nil {
+		return fmt.Errorf("exec context: %w", err)
+	}
+
+	return nil
+}
+
+// GetRecipients возвращает порцию пользователей с идентификатором больше afterID,
+// не отключивших уведомления вида kind.
+func (r *Repository) GetRecipients(ctx context.Context, kind string, afterID int64, limit int) ([]Recipient, error) {
+	var column string
+	switch kind {
+	case KindWeeklyDigest:
+		column = "s.weekly_digest"
+	case KindGoalReminders:
+		column = "s.goal_reminders"
+	default:
+		return nil, fmt.Errorf("unknown notification kind %q", kind)
+	}
+
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT u.id, u.name, u.email
+		FROM users u
+		LEFT JOIN notification_settings s ON s.user_id = u.id
+		WHERE u.id > $1 AND COALESCE(`+column+`, true)
+		ORDER BY u.id
+		LIMIT $2`, afterID, limit)
+
+	if err != nil {
+		return nil, fmt.Errorf("query context: %w", err)
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var recipients []Recipient
+	for rows.Next() {
+		var recipient Recipient
+		if err = rows.Scan(&recipient.UserID, &recipient.Name, &recipient.Email); err != nil {
+			return nil, fmt.Errorf("scan: %w", err)
+		}
+
+		recipients = append(recipients, recipient)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("rows err: %w", rows.Err())
+	}
+
+	return recipients, nil
+}
+
+// ClaimNotification отмечает уведомление отправляемым. Возвращает false, если оно уже
+// отправлено или его отправляет другой экземпляр.
+func (r *Repository) ClaimNotification(ctx context.Context, userID int64, kind, key string) (bool, error) {
+	res, err := r.db.ExecContext(ctx,
+		`INSERT INTO notification_log (user_id, kind, key) VALUES ($1, $2, $3)
+		ON CONFLICT DO NOTHING`, userID, kind, key)
+
+	if err != nil {
+		return false, fmt.Errorf("exec context: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("rows affected: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+// ReleaseNotification снимает отметку, если письмо отправить не удалось, чтобы повторить позже.
+func (r *Repository) ReleaseNotification(ctx context.Context, userID int64, kind, key string) error {
+	_, err := r.db.ExecContext(ctx,
+		`DELETE FROM notification_log WHERE user_id = $1 AND kind = $2 AND key = $3`, userID, kind, key)
+
+	if err != nil {
+		return fmt.Errorf("exec context: %w", err)
+	}
+
+	return nil
+}
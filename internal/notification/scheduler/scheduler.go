@@ -0,0 +1,67 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type usecase interface {
+	SendDigests(ctx context.Context, now time.Time) (int, error)
+	SendReminders(ctx context.Context, now time.Time) (int, error)
+}
+
+// Scheduler периодически отправляет еженедельные сводки и напоминания о целях.
+type Scheduler struct {
+	usecase  usecase
+	interval time.Duration
+
+	logger echo.Logger
+}
+
+func NewScheduler(usecase usecase, interval time.Duration, logger echo.Logger) *Scheduler {
+	return &Scheduler{
+		usecase:  usecase,
+		interval: interval,
+
+		logger: logger,
+	}
+}
+
+// Run проверяет, кому пора отправить письмо, сразу и затем раз в interval, пока не отменен контекст.
+func (s *Scheduler) Run(ctx context.Context) {
+	if s.interval <= 0 {
+		s.logger.Warn("email notifications are disabled: check interval is not set")
+		return
+	}
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		now := time.Now()
+
+		sent, err := s.usecase.SendDigests(ctx, now)
+		if err != nil {
+			s.logger.Errorf("send digests: %v", err)
+		}
+		if sent > 0 {
+			s.logger.Infof("sent %d weekly digests", sent)
+		}
+
+		sent, err = s.usecase.SendReminders(ctx, now)
+		if err != nil {
+			s.logger.Errorf("send goal reminders: %v", err)
+		}
+		if sent > 0 {
+			s.logger.Infof("sent %d goal reminders", sent)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package usecase
+
+import "time"
+
+type Settings struct {
+	// Еженедельная сводка: время по проектам и прогресс целей.
+	WeeklyDigest bool
+	// Напоминания об отстающих от темпа и скоро заканчивающихся целях.
+	GoalReminders bool
+}
+
+// Schedule когда и о чем уведомлять.
+type Schedule struct {
+	// Часовой пояс расписания и дат в письмах.
+	Location *time.Location
+	// День недели и час, начиная с которого отправляется сводка.
+	DigestWeekday time.Weekday
+	DigestHour    int
+	// За сколько до окончания цели напоминать о ней.
+	EndingSoon time.Duration
+	// На сколько процентных пунктов цель может отставать от равномерного темпа без напоминания.
+	PaceTolerance float64
+	// Адрес приложения для ссылок в письмах.
+	AppURL string
+}
+
+// digestData данные шаблона еженедельной сводки.
+type digestData struct {
+	Name          string
+	From          time.Time
+	To            time.Time
+	TotalDuration time.Duration
+	Projects      []digestProject
+	Goals         []goalProgress
+	AppURL        string
+}
+
+type digestProject struct {
+	Name     string
+	Duration time.Duration
+	Percent  float64
+}
+
+type goalProgress struct {
+	Name        string
+	ProjectName string
+	DateEnd     time.Time
+	Duration    time.Duration
+	Target      time.Duration
+	Percent     float64
+	// Сколько процентов должно быть выполнено к этому моменту при равномерном темпе.
+	ExpectedPercent float64
+	// Отстает от темпа больше допустимого.
+	Behind bool
+	// Заканчивается в ближайшее время.
+	EndingSoon bool
+}
+
+// reminderData данные шаблона напоминания о целях.
+type reminderData struct {
+	Name   string
+	Goals  []goalProgress
+	AppURL string
+}
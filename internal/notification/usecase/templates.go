@@ -0,0 +1,62 @@
+package usecase
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmlTemplate "html/template"
+	textTemplate "text/template"
+	"time"
+)
+
+//go:embed templates
+var templatesFS embed.FS
+
+var templateFuncs = map[string]interface{}{
+	"duration": formatDuration,
+	"date":     formatDate,
+	"percent":  formatPercent,
+}
+
+var (
+	textTemplates = textTemplate.Must(textTemplate.New("").Funcs(templateFuncs).ParseFS(templatesFS, "templates/*.txt"))
+	htmlTemplates = htmlTemplate.Must(htmlTemplate.New("").Funcs(templateFuncs).ParseFS(templatesFS, "templates/*.html"))
+)
+
+// render заполняет текстовую и HTML-версию письма из шаблонов name.txt и name.html.
+func render(name string, data interface{}) (string, string, error) {
+	var text, html bytes.Buffer
+
+	if err := textTemplates.ExecuteTemplate(&text, name+".txt", data); err != nil {
+		return "", "", fmt.Errorf("execute text template: %w", err)
+	}
+
+	if err := htmlTemplates.ExecuteTemplate(&html, name+".html", data); err != nil {
+		return "", "", fmt.Errorf("execute html template: %w", err)
+	}
+
+	return text.String(), html.String(), nil
+}
+
+// formatDuration форматирует длительность с точностью до минуты: 12 ч 5 мин.
+func formatDuration(d time.Duration) string {
+	minutes := int64(d.Round(time.Minute) / time.Minute)
+	hours, minutes := minutes/60, minutes%60
+
+	switch {
+	case hours == 0:
+		return fmt.Sprintf("%d мин", minutes)
+	case minutes == 0:
+		return fmt.Sprintf("%d ч", hours)
+	default:
+		return fmt.Sprintf("%d ч %d мин", hours, minutes)
+	}
+}
+
+func formatDate(t time.Time) string {
+	return t.Format("02.01.2006")
+}
+
+func formatPercent(p float64) string {
+	return fmt.Sprintf("%.0f%%", p)
+}
@@ -0,0 +1,388 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	goalUC "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/goal/usecase"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/mailer"
+	repo "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/notification/repository"
+	projectUC "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/project/usecase"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/tracing"
+)
+
+const (
+	// Сколько пользователей обрабатывать за один запрос к базе.
+	recipientsBatchSize = 100
+	// Сколько проектов показывать в сводке.
+	digestTopProjects = 5
+
+	digestSubject   = "Ваша неделя в трекере времени"
+	reminderSubject = "Напоминание о целях"
+)
+
+type repository interface {
+	GetSettings(ctx context.Context, userID int64) (repo.Settings, error)
+	SetSettings(ctx context.Context, userID int64, settings repo.Settings) error
+	GetRecipients(ctx context.Context, kind string, afterID int64, limit int) ([]repo.Recipient, error)
+	ClaimNotification(ctx context.Context, userID int64, kind, key string) (bool, error)
+	ReleaseNotification(ctx context.Context, userID int64, kind, key string) error
+}
+
+type projectUsecase interface {
+	GetUserProjects(ctx context.Context, userID int64) ([]projectUC.Project, error)
+	ProjectsStats(ctx context.Context, userID int64, timeStart, timeEnd time.Time) (projectUC.AllProjectsStat, error)
+}
+
+type goalUsecase interface {
+	GetGoals(ctx context.Context, userID, projectID int64) ([]goalUC.Goal, error)
+}
+
+type sender interface {
+	Send(ctx context.Context, msg mailer.Message) error
+}
+
+type Usecase struct {
+	repository     repository
+	projectUsecase projectUsecase
+	goalUsecase    goalUsecase
+	sender         sender
+	schedule       Schedule
+}
+
+func NewUsecase(
+	repository repository,
+	projectUsecase projectUsecase,
+	goalUsecase goalUsecase,
+	sender sender,
+	schedule Schedule,
+) *Usecase {
+	if schedule.Location == nil {
+		schedule.Location = time.UTC
+	}
+
+	return &Usecase{
+		repository:     repository,
+		projectUsecase: projectUsecase,
+		goalUsecase:    goalUsecase,
+		sender:         sender,
+		schedule:       schedule,
+	}
+}
+
+func (u *Usecase) GetSettings(ctx context.Context, userID int64) (Settings, error) {
+	ctx, span := tracing.Start(ctx, "notification.GetSettings")
+	defer span.End()
+
+	settings, err := u.repository.GetSettings(ctx, userID)
+	if err != nil {
+		return Settings{}, fmt.Errorf("repo get settings: %w", err)
+	}
+
+	return Settings(settings), nil
+}
+
+// UpdateSettings включает и отключает уведомления пользователя.
+func (u *Usecase) UpdateSettings(ctx context.Context, userID int64, settings Settings) error {
+	ctx, span := tracing.Start(ctx, "notification.UpdateSettings")
+	defer span.End()
+
+	if err := u.repository.SetSettings(ctx, userID, repo.Settings(settings)); err != nil {
+		return fmt.Errorf("repo set settings: %w", err)
+	}
+
+	return nil
+}
+
+// SendDigests отправляет еженедельные сводки за 7 дней до начала текущего дня, если наступили
+// день и час отправки. Каждый пользователь получает сводку за неделю один раз. Ошибка отправки
+// одному пользователю не мешает остальным; сводка ему будет отправлена при следующей проверке.
+func (u *Usecase) SendDigests(ctx context.Context, now time.Time) (int, error) {
+	ctx, span := tracing.Start(ctx, "notification.SendDigests")
+	defer span.End()
+
+	local := now.In(u.schedule.Location)
+	if local.Weekday() != u.schedule.DigestWeekday || local.Hour() < u.schedule.DigestHour {
+		return 0, nil
+	}
+
+	to := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, u.schedule.Location)
+	from := to.AddDate(0, 0, -7)
+	key := from.Format(time.DateOnly)
+
+	return u.forEachRecipient(ctx, repo.KindWeeklyDigest, func(recipient repo.Recipient) (bool, error) {
+		claimed, err := u.repository.ClaimNotification(ctx, recipient.UserID, repo.KindWeeklyDigest, key)
+		if err != nil {
+			return false, fmt.Errorf("repo claim notification: %w", err)
+		}
+		if !claimed {
+			return false, nil
+		}
+
+		sent, err := u.sendDigest(ctx, recipient, from, to, now)
+		if err != nil {
+			if releaseErr := u.repository.ReleaseNotification(ctx, recipient.UserID, repo.KindWeeklyDigest, key); releaseErr != nil {
+				return false, fmt.Errorf("%v; repo release notification: %v", err, releaseErr)
+			}
+			return false, err
+		}
+
+		return sent, nil
+	})
+}
+
+// SendReminders напоминает о незавершенных целях, которые отстают от равномерного темпа больше
+// допустимого или скоро заканчиваются. Об отставании напоминается не чаще раза в неделю,
+// об окончании — один раз. Все цели пользователя собираются в одно письмо.
+func (u *Usecase) SendReminders(ctx context.Context, now time.Time) (int, error) {
+	ctx, span := tracing.Start(ctx, "notification.SendReminders")
+	defer span.End()
+
+	year, week := now.In(u.schedule.Location).ISOWeek()
+
+	return u.forEachRecipient(ctx, repo.KindGoalReminders, func(recipient repo.Recipient) (bool, error) {
+		goals, err := u.getActiveGoals(ctx, recipient.UserID, now)
+		if err != nil {
+			return false, err
+		}
+
+		var (
+			due     []goalProgress
+			claimed []string
+		)
+		release := func() error {
+			for _, key := range claimed {
+				if err := u.repository.ReleaseNotification(ctx, recipient.UserID, repo.KindGoalReminders, key); err != nil {
+					return fmt.Errorf("repo release notification: %w", err)
+				}
+			}
+			return nil
+		}
+
+		for _, goal := range goals {
+			if goal.achieved || !goal.Behind && !goal.EndingSoon {
+				continue
+			}
+
+			key := fmt.Sprintf("%d:behind:%d-W%02d", goal.id, year, week)
+			if goal.EndingSoon {
+				key = fmt.Sprintf("%d:ending", goal.id)
+			}
+
+			ok, err := u.repository.ClaimNotification(ctx, recipient.UserID, repo.KindGoalReminders, key)
+			if err != nil {
+				if releaseErr := release(); releaseErr != nil {
+					return false, fmt.Errorf("repo claim notification: %v; %v", err, releaseErr)
+				}
+				return false, fmt.Errorf("repo claim notification: %w", err)
+			}
+			if !ok {
+				continue
+			}
+
+			claimed = append(claimed, key)
+			due = append(due, goal.goalProgress)
+		}
+
+		if len(due) == 0 {
+			return false, nil
+		}
+
+		err = u.send(ctx, recipient, reminderSubject, "reminder", reminderData{
+			Name:   recipient.Name,
+			Goals:  due,
+			AppURL: u.schedule.AppURL,
+		})
+		if err != nil {
+			if releaseErr := release(); releaseErr != nil {
+				return false, fmt.Errorf("%v; %v", err, releaseErr)
+			}
+			return false, err
+		}
+
+		return true, nil
+	})
+}
+
+// forEachRecipient вызывает fn для всех пользователей, не отключивших уведомления вида kind,
+// и возвращает число отправленных писем. Ошибки по отдельным пользователям собираются.
+func (u *Usecase) forEachRecipient(
+	ctx context.Context,
+	kind string,
+	fn func(recipient repo.Recipient) (bool, error),
+) (int, error) {
+	var (
+		sent    int
+		errs    []error
+		afterID int64
+	)
+
+	for {
+		recipients, err := u.repository.GetRecipients(ctx, kind, afterID, recipientsBatchSize)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("repo get recipients: %w", err))
+			break
+		}
+
+		for _, recipient := range recipients {
+			if ctx.Err() != nil {
+				return sent, errors.Join(append(errs, ctx.Err())...)
+			}
+
+			ok, err := fn(recipient)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("user %d: %w", recipient.UserID, err))
+				continue
+			}
+			if ok {
+				sent++
+			}
+		}
+
+		if len(recipients) < recipientsBatchSize {
+			break
+		}
+		afterID = recipients[len(recipients)-1].UserID
+	}
+
+	return sent, errors.Join(errs...)
+}
+
+// sendDigest собирает и отправляет сводку. Если за неделю нет ни записей, ни активных целей,
+// письмо не отправляется.
+func (u *Usecase) sendDigest(ctx context.Context, recipient repo.Recipient, from, to, now time.Time) (bool, error) {
+	stats, err := u.projectUsecase.ProjectsStats(ctx, recipient.UserID, from, to)
+	if err != nil {
+		return false, fmt.Errorf("projects stats: %w", err)
+	}
+
+	goals, err := u.getActiveGoals(ctx, recipient.UserID, now)
+	if err != nil {
+		return false, err
+	}
+
+	if stats.TotalDurationInSec == 0 && len(goals) == 0 {
+		return false, nil
+	}
+
+	projects := make([]digestProject, 0, len(stats.ProjectsStat))
+	for _, stat := range stats.ProjectsStat {
+		if stat.ProjectDurationInSec == 0 {
+			continue
+		}
+
+		projects = append(projects, digestProject{
+			Name:     stat.ProjectName,
+			Duration: seconds(stat.ProjectDurationInSec),
+			Percent:  stat.ProjectDurationPercent,
+		})
+	}
+
+	sort.SliceStable(projects, func(i, j int) bool {
+		return projects[i].Duration > projects[j].Duration
+	})
+	if len(projects) > digestTopProjects {
+		projects = projects[:digestTopProjects]
+	}
+
+	data := digestData{
+		Name:          recipient.Name,
+		From:          from,
+		To:            to.AddDate(0, 0, -1),
+		TotalDuration: seconds(stats.TotalDurationInSec),
+		Projects:      projects,
+		Goals:         make([]goalProgress, 0, len(goals)),
+		AppURL:        u.schedule.AppURL,
+	}
+	for _, goal := range goals {
+		data.Goals = append(data.Goals, goal.goalProgress)
+	}
+
+	if err = u.send(ctx, recipient, digestSubject, "digest", data); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// activeGoal цель, которая уже началась и еще не закончилась.
+type activeGoal struct {
+	goalProgress
+
+	id       int64
+	achieved bool
+}
+
+// getActiveGoals возвращает идущие цели пользователя по всем его проектам с оценкой темпа.
+func (u *Usecase) getActiveGoals(ctx context.Context, userID int64, now time.Time) ([]activeGoal, error) {
+	projects, err := u.projectUsecase.GetUserProjects(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get user projects: %w", err)
+	}
+
+	var active []activeGoal
+	for _, project := range projects {
+		goals, err := u.goalUsecase.GetGoals(ctx, userID, project.ID)
+		if err != nil {
+			return nil, fmt.Errorf("get goals: %w", err)
+		}
+
+		for _, goal := range goals {
+			if now.Before(goal.DateStart) || !now.Before(goal.DateEnd) {
+				continue
+			}
+
+			total := goal.DateEnd.Sub(goal.DateStart)
+			expected := float64(now.Sub(goal.DateStart)) / float64(total) * 100
+			achieved := goal.AchievedAt != nil || goal.Percent >= 100
+
+			active = append(active, activeGoal{
+				goalProgress: goalProgress{
+					Name:            goal.Name,
+					ProjectName:     project.Name,
+					DateEnd:         goal.DateEnd.In(u.schedule.Location),
+					Duration:        seconds(goal.DurationSeconds),
+					Target:          time.Duration(goal.TimeSeconds) * time.Second,
+					Percent:         goal.Percent,
+					ExpectedPercent: expected,
+					Behind:          !achieved && goal.Percent+u.schedule.PaceTolerance < expected,
+					EndingSoon:      !achieved && goal.DateEnd.Sub(now) <= u.schedule.EndingSoon,
+				},
+				id:       goal.ID,
+				achieved: achieved,
+			})
+		}
+	}
+
+	sort.SliceStable(active, func(i, j int) bool {
+		return active[i].DateEnd.Before(active[j].DateEnd)
+	})
+
+	return active, nil
+}
+
+func (u *Usecase) send(ctx context.Context, recipient repo.Recipient, subject, template string, data interface{}) error {
+	text, html, err := render(template, data)
+	if err != nil {
+		return fmt.Errorf("render %s: %w", template, err)
+	}
+
+	err = u.sender.Send(ctx, mailer.Message{
+		To:      recipient.Email,
+		Subject: subject,
+		Text:    text,
+		HTML:    html,
+	})
+	if err != nil {
+		return fmt.Errorf("send %s: %w", template, err)
+	}
+
+	return nil
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
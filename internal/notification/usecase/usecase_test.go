@@ -0,0 +1,420 @@
+package usecase
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	goalUC "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/goal/usecase"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/mailer"
+	repo "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/notification/repository"
+	projectUC "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/project/usecase"
+)
+
+// smtpMessage письмо, принятое заглушкой SMTP-сервера.
+type smtpMessage struct {
+	from string
+	to   []string
+	data string
+}
+
+// smtpStub минимальный SMTP-сервер: принимает любые письма и запоминает их.
+type smtpStub struct {
+	listener net.Listener
+
+	mu       sync.Mutex
+	messages []smtpMessage
+}
+
+func startSMTPStub(t *testing.T) *smtpStub {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+
+	s := &smtpStub{listener: listener}
+	t.Cleanup(func() {
+		_ = listener.Close()
+	})
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+
+	return s
+}
+
+func (s *smtpStub) serve(conn net.Conn) {
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	r := bufio.NewReader(conn)
+	reply := func(line string) {
+		_, _ = io.WriteString(conn, line+"\r\n")
+	}
+
+	reply("220 localhost ESMTP stub")
+
+	var msg smtpMessage
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		verb := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+
+		switch verb {
+		case "EHLO", "HELO":
+			reply("250 localhost")
+		case "MAIL":
+			msg = smtpMessage{from: addressArg(line)}
+			reply("250 OK")
+		case "RCPT":
+			msg.to = append(msg.to, addressArg(line))
+			reply("250 OK")
+		case "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+
+			var data strings.Builder
+			for {
+				dataLine, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if dataLine == ".\r\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(dataLine, "."))
+			}
+			msg.data = data.String()
+
+			s.mu.Lock()
+			s.messages = append(s.messages, msg)
+			s.mu.Unlock()
+
+			reply("250 OK")
+		case "RSET", "NOOP":
+			reply("250 OK")
+		case "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("502 Command not implemented")
+		}
+	}
+}
+
+// addressArg возвращает адрес из "MAIL FROM:<a@b>" или "RCPT TO:<a@b>".
+func addressArg(line string) string {
+	start, end := strings.IndexByte(line, '<'), strings.IndexByte(line, '>')
+	if start < 0 || end < start {
+		return ""
+	}
+
+	return line[start+1 : end]
+}
+
+func (s *smtpStub) take() []smtpMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	messages := s.messages
+	s.messages = nil
+
+	return messages
+}
+
+func (s *smtpStub) mailer(t *testing.T) *mailer.Mailer {
+	t.Helper()
+
+	addr := s.listener.Addr().(*net.TCPAddr)
+	m, err := mailer.NewMailer(mailer.Config{
+		Host:    "127.0.0.1",
+		Port:    addr.Port,
+		From:    "Time Tracker <noreply@timetracker.test>",
+		TLSMode: mailer.TLSNone,
+		Timeout: 5 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("new mailer: %v", err)
+	}
+
+	return m
+}
+
+// fakeRepository настройки пользователей и отметки об отправке в памяти.
+type fakeRepository struct {
+	recipients []repo.Recipient
+	settings   map[int64]repo.Settings
+	sent       map[string]struct{}
+}
+
+func (r *fakeRepository) GetSettings(_ context.Context, userID int64) (repo.Settings, error) {
+	return r.settings[userID], nil
+}
+
+func (r *fakeRepository) SetSettings(_ context.Context, userID int64, settings repo.Settings) error {
+	r.settings[userID] = settings
+	return nil
+}
+
+// GetRecipients как и запрос в базу, отдает только пользователей с включенными уведомлениями вида kind.
+func (r *fakeRepository) GetRecipients(_ context.Context, kind string, afterID int64, limit int) ([]repo.Recipient, error) {
+	var res []repo.Recipient
+	for _, recipient := range r.recipients {
+		settings := r.settings[recipient.UserID]
+		enabled := kind == repo.KindWeeklyDigest && settings.WeeklyDigest ||
+			kind == repo.KindGoalReminders && settings.GoalReminders
+		if !enabled || recipient.UserID <= afterID {
+			continue
+		}
+
+		res = append(res, recipient)
+		if len(res) == limit {
+			break
+		}
+	}
+
+	return res, nil
+}
+
+func (r *fakeRepository) ClaimNotification(_ context.Context, userID int64, kind, key string) (bool, error) {
+	k := fmt.Sprintf("%d|%s|%s", userID, kind, key)
+	if _, ok := r.sent[k]; ok {
+		return false, nil
+	}
+
+	r.sent[k] = struct{}{}
+	return true, nil
+}
+
+func (r *fakeRepository) ReleaseNotification(_ context.Context, userID int64, kind, key string) error {
+	delete(r.sent, fmt.Sprintf("%d|%s|%s", userID, kind, key))
+	return nil
+}
+
+type fakeProjectUsecase struct{}
+
+func (fakeProjectUsecase) GetUserProjects(context.Context, int64) ([]projectUC.Project, error) {
+	return []projectUC.Project{{ID: 10, Name: "Website"}}, nil
+}
+
+func (fakeProjectUsecase) ProjectsStats(context.Context, int64, time.Time, time.Time) (projectUC.AllProjectsStat, error) {
+	return projectUC.AllProjectsStat{
+		TotalDurationInSec: 9000,
+		ProjectsStat: []projectUC.ProjectStatInfo{
+			{ProjectID: 10, ProjectName: "Website", ProjectDurationInSec: 9000, ProjectDurationPercent: 100},
+		},
+	}, nil
+}
+
+// fakeGoalUsecase у каждого пользователя одна цель на март, сильно отстающая от темпа.
+type fakeGoalUsecase struct{}
+
+func (fakeGoalUsecase) GetGoals(_ context.Context, userID, projectID int64) ([]goalUC.Goal, error) {
+	return []goalUC.Goal{{
+		ID:              100 + userID,
+		ProjectID:       projectID,
+		UserID:          userID,
+		Name:            "Сдать лендинг",
+		TimeSeconds:     100 * 60 * 60,
+		DateStart:       time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		DateEnd:         time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC),
+		DurationSeconds: 9000,
+		Percent:         2.5,
+	}}, nil
+}
+
+func newTestUsecase(t *testing.T) (*Usecase, *smtpStub) {
+	t.Helper()
+
+	stub := startSMTPStub(t)
+	r := &fakeRepository{
+		recipients: []repo.Recipient{
+			{UserID: 1, Name: "Анна", Email: "anna@example.com"},
+			{UserID: 2, Name: "Борис", Email: "boris@example.com"},
+			{UserID: 3, Name: "Вера", Email: "vera@example.com"},
+		},
+		settings: map[int64]repo.Settings{
+			1: {WeeklyDigest: true, GoalReminders: true},
+			2: {WeeklyDigest: false, GoalReminders: true},
+			3: {WeeklyDigest: false, GoalReminders: false},
+		},
+		sent: make(map[string]struct{}),
+	}
+
+	u := NewUsecase(r, fakeProjectUsecase{}, fakeGoalUsecase{}, stub.mailer(t), Schedule{
+		Location:      time.UTC,
+		DigestWeekday: time.Monday,
+		DigestHour:    9,
+		EndingSoon:    72 * time.Hour,
+		PaceTolerance: 10,
+		AppURL:        "https://tracker.example.com",
+	})
+
+	return u, stub
+}
+
+// parseParts разбирает письмо и возвращает тему и тела частей по типу содержимого.
+func parseParts(t *testing.T, data string) (string, map[string]string) {
+	t.Helper()
+
+	msg, err := mail.ReadMessage(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("read message: %v", err)
+	}
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil {
+		t.Fatalf("decode subject: %v", err)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("content type %q, want multipart/alternative", msg.Header.Get("Content-Type"))
+	}
+
+	parts := make(map[string]string)
+	mr := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("next part: %v", err)
+		}
+
+		// multipart.Reader сам декодирует quoted-printable.
+		body, err := io.ReadAll(part)
+		if err != nil {
+			t.Fatalf("read part: %v", err)
+		}
+
+		partType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		parts[partType] = string(body)
+	}
+
+	return subject, parts
+}
+
+func recipientsOf(messages []smtpMessage) []string {
+	var res []string
+	for _, msg := range messages {
+		res = append(res, msg.to...)
+	}
+
+	return res
+}
+
+func TestSendDigests(t *testing.T) {
+	u, stub := newTestUsecase(t)
+	ctx := context.Background()
+
+	// Воскресенье: сводки еще не отправляются.
+	sent, err := u.SendDigests(ctx, time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC))
+	if err != nil || sent != 0 {
+		t.Fatalf("sunday: sent %d, err %v", sent, err)
+	}
+
+	monday := time.Date(2024, 3, 11, 10, 0, 0, 0, time.UTC)
+	sent, err = u.SendDigests(ctx, monday)
+	if err != nil {
+		t.Fatalf("send digests: %v", err)
+	}
+
+	messages := stub.take()
+	// Борис и Вера отключили сводку.
+	if sent != 1 || len(messages) != 1 || strings.Join(recipientsOf(messages), ",") != "anna@example.com" {
+		t.Fatalf("sent %d to %v, want only anna@example.com", sent, recipientsOf(messages))
+	}
+	if messages[0].from != "noreply@timetracker.test" {
+		t.Errorf("mail from %q", messages[0].from)
+	}
+
+	subject, parts := parseParts(t, messages[0].data)
+	if subject != digestSubject {
+		t.Errorf("subject %q, want %q", subject, digestSubject)
+	}
+
+	for _, contentType := range []string{"text/plain", "text/html"} {
+		body, ok := parts[contentType]
+		if !ok {
+			t.Errorf("no %s part", contentType)
+			continue
+		}
+
+		for _, want := range []string{"Анна", "04.03", "10.03", "Website", "Сдать лендинг", "https://tracker.example.com"} {
+			if !strings.Contains(body, want) {
+				t.Errorf("%s part does not contain %q:\n%s", contentType, want, body)
+			}
+		}
+	}
+	if !strings.Contains(parts["text/html"], "<table") {
+		t.Errorf("html part is not html:\n%s", parts["text/html"])
+	}
+
+	// Сводка за неделю отправляется один раз.
+	sent, err = u.SendDigests(ctx, monday.Add(time.Hour))
+	if err != nil || sent != 0 || len(stub.take()) != 0 {
+		t.Errorf("second run: sent %d, err %v", sent, err)
+	}
+}
+
+func TestSendReminders(t *testing.T) {
+	u, stub := newTestUsecase(t)
+	ctx := context.Background()
+	now := time.Date(2024, 3, 11, 10, 0, 0, 0, time.UTC)
+
+	sent, err := u.SendReminders(ctx, now)
+	if err != nil {
+		t.Fatalf("send reminders: %v", err)
+	}
+
+	messages := stub.take()
+	// Вера отключила напоминания, Борис отключил только сводку.
+	if got := strings.Join(recipientsOf(messages), ","); sent != 2 || got != "anna@example.com,boris@example.com" {
+		t.Fatalf("sent %d to %v, want anna and boris", sent, got)
+	}
+
+	subject, parts := parseParts(t, messages[1].data)
+	if subject != reminderSubject {
+		t.Errorf("subject %q, want %q", subject, reminderSubject)
+	}
+	if text := parts["text/plain"]; !strings.Contains(text, "Борис") || !strings.Contains(text, "По плану к этому времени") {
+		t.Errorf("text part:\n%s", text)
+	}
+	if html := parts["text/html"]; !strings.Contains(html, "Сдать лендинг") {
+		t.Errorf("html part:\n%s", html)
+	}
+
+	// Об отставании напоминается не чаще раза в неделю.
+	sent, err = u.SendReminders(ctx, now.AddDate(0, 0, 2))
+	if err != nil || sent != 0 || len(stub.take()) != 0 {
+		t.Errorf("same week: sent %d, err %v", sent, err)
+	}
+
+	sent, err = u.SendReminders(ctx, now.AddDate(0, 0, 7))
+	if err != nil || sent != 2 {
+		t.Errorf("next week: sent %d, err %v", sent, err)
+	}
+}
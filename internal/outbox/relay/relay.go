@@ -0,0 +1,74 @@
+package relay
+
+import (
+	"context"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Как часто удалять отправленные события с истекшим сроком хранения.
+const purgeInterval = time.Hour
+
+type usecase interface {
+	Relay(ctx context.Context) (int, error)
+	PurgeSent(ctx context.Context) (int64, error)
+}
+
+// Relay в фоне публикует события из outbox.
+type Relay struct {
+	usecase  usecase
+	interval time.Duration
+
+	logger echo.Logger
+}
+
+func NewRelay(usecase usecase, interval time.Duration, logger echo.Logger) *Relay {
+	return &Relay{
+		usecase:  usecase,
+		interval: interval,
+
+		logger: logger,
+	}
+}
+
+// Run публикует события порциями, пока они есть, затем ждет interval. Работает до отмены контекста.
+func (r *Relay) Run(ctx context.Context) {
+	if r.interval <= 0 {
+		r.logger.Warn("outbox relay is disabled: poll interval is not set")
+		return
+	}
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	var purgedAt time.Time
+	for {
+		for {
+			relayed, err := r.usecase.Relay(ctx)
+			if err != nil {
+				r.logger.Errorf("relay outbox: %v", err)
+				break
+			}
+			if relayed == 0 || ctx.Err() != nil {
+				break
+			}
+		}
+
+		if time.Since(purgedAt) >= purgeInterval {
+			purged, err := r.usecase.PurgeSent(ctx)
+			if err != nil {
+				r.logger.Errorf("purge outbox: %v", err)
+			} else if purged > 0 {
+				r.logger.Infof("purged %d sent outbox events", purged)
+			}
+			purgedAt = time.Now()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package relay
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/labstack/gommon/log"
+)
+
+// fakeUsecase отдает размеры порций из batches, затем пустые порции.
+// После stopAfter вызовов Relay отменяет контекст relay.
+type fakeUsecase struct {
+	batches   []int
+	relayErr  error
+	stopAfter int
+	cancel    context.CancelFunc
+
+	relayCalls int
+	purgeCalls int
+}
+
+func (u *fakeUsecase) Relay(context.Context) (int, error) {
+	u.relayCalls++
+	if u.relayCalls == u.stopAfter {
+		u.cancel()
+	}
+	if u.relayErr != nil {
+		return 0, u.relayErr
+	}
+	if len(u.batches) == 0 {
+		return 0, nil
+	}
+
+	relayed := u.batches[0]
+	u.batches = u.batches[1:]
+	return relayed, nil
+}
+
+func (u *fakeUsecase) PurgeSent(context.Context) (int64, error) {
+	u.purgeCalls++
+	return 0, nil
+}
+
+func newTestRelay(u *fakeUsecase, interval time.Duration) *Relay {
+	logger := log.New("outbox")
+	logger.SetOutput(io.Discard)
+
+	return NewRelay(u, interval, logger)
+}
+
+func runWithTimeout(t *testing.T, ctx context.Context, r *Relay) {
+	t.Helper()
+
+	done := make(chan struct{})
+	go func() {
+		r.Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("relay did not stop after context cancel")
+	}
+}
+
+func TestRunRelaysUntilEmpty(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Три непустые порции подряд, пустая, затем еще одна пустая порция по таймеру.
+	u := &fakeUsecase{batches: []int{100, 100, 3}, stopAfter: 5, cancel: cancel}
+	runWithTimeout(t, ctx, newTestRelay(u, time.Millisecond))
+
+	if u.relayCalls != 5 {
+		t.Errorf("relay called %d times, want 5", u.relayCalls)
+	}
+	// Отправленные события удаляются не чаще раза в purgeInterval.
+	if u.purgeCalls != 1 {
+		t.Errorf("purge called %d times, want 1", u.purgeCalls)
+	}
+}
+
+func TestRunContinuesAfterRelayError(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	u := &fakeUsecase{relayErr: errors.New("connection refused"), stopAfter: 3, cancel: cancel}
+	runWithTimeout(t, ctx, newTestRelay(u, time.Millisecond))
+
+	// Ошибка прерывает порцию, следующая попытка будет по таймеру.
+	if u.relayCalls != 3 {
+		t.Errorf("relay called %d times, want 3", u.relayCalls)
+	}
+}
+
+func TestRunDisabled(t *testing.T) {
+	u := &fakeUsecase{}
+	runWithTimeout(t, context.Background(), newTestRelay(u, 0))
+
+	if u.relayCalls != 0 {
+		t.Errorf("relay called %d times, want 0", u.relayCalls)
+	}
+}
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+)
+
+type Message struct {
+	ID             int64          `db:"id"`
+	IdempotencyKey string         `db:"idempotency_key"`
+	ActorID        int64          `db:"actor_id"`
+	WorkspaceID    sql.NullInt64  `db:"workspace_id"`
+	EntityType     string         `db:"entity_type"`
+	EntityID       int64          `db:"entity_id"`
+	Action         string         `db:"action"`
+	Before         []byte         `db:"before"`
+	After          []byte         `db:"after"`
+	RequestID      string         `db:"request_id"`
+	CreatedAt      time.Time      `db:"created_at"`
+	Attempts       int            `db:"attempts"`
+	LastError      sql.NullString `db:"last_error"`
+}
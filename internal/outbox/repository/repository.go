@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/transaction"
+)
+
+type Repository struct {
+	db    *sqlx.DB
+	close func() error
+}
+
+func NewRepository(db *sqlx.DB) *Repository {
+	return &Repository{
+		db: db,
+		close: func() error {
+			return db.Close()
+		},
+	}
+}
+
+// Close закрывает пул соединений с базой.
+func (r *Repository) Close() error {
+	return r.close()
+}
+
+// Write добавляет событие в outbox. Внутри транзакции из контекста событие сохраняется
+// только вместе с изменением, которое его породило. Вызывается из audit.Record, чтобы
+// журнал изменений и публикуемые события совпадали.
+func (r *Repository) Write(ctx context.Context, msg Message) error {
+	_, err := transaction.GetExecutor(ctx, r.db).ExecContext(ctx,
+		`INSERT INTO outbox
+				(
+					actor_id,
+					workspace_id,
+					entity_type,
+					entity_id,
+					action,
+					before,
+					after,
+					request_id
+				) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		msg.ActorID,
+		msg.WorkspaceID,
+		msg.EntityType,
+		msg.EntityID,
+		msg.Action,
+		nullJSON(msg.Before),
+		nullJSON(msg.After),
+		msg.RequestID,
+	)
+
+	if err != nil {
+		return fmt.Errorf("exec: %w", err)
+	}
+
+	return nil
+}
+
+// Claim забирает в работу до limit неотправленных событий в порядке записи.
+// Взятые события откладываются на lease: другие экземпляры relay их не берут,
+// а если процесс упадет до отметки об отправке, событие будет опубликовано повторно.
+func (r *Repository) Claim(ctx context.Context, limit int, lease time.Duration) ([]Message, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`WITH due AS (
+			SELECT id
+			FROM outbox
+			WHERE sent_at IS NULL AND available_at <= now()
+			ORDER BY id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE outbox o
+		SET available_at = now() + make_interval(secs => $2)
+		FROM due
+		WHERE o.id = due.id
+		RETURNING
+			o.id,
+			o.idempotency_key,
+			o.actor_id,
+			o.workspace_id,
+			o.entity_type,
+			o.entity_id,
+			o.action,
+			o.before,
+			o.after,
+			o.request_id,
+			o.created_at,
+			o.attempts,
+			o.last_error`, limit, lease.Seconds())
+
+	if err != nil {
+		return nil, fmt.Errorf("query context: %w", err)
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var messages []Message
+	for rows.Next() {
+		var msg Message
+		if err = rows.Scan(
+			&msg.ID,
+			&msg.IdempotencyKey,
+			&msg.ActorID,
+			&msg.WorkspaceID,
+			&msg.EntityType,
+			&msg.EntityID,
+			&msg.Action,
+			&msg.Before,
+			&msg.After,
+			&msg.RequestID,
+			&msg.CreatedAt,
+			&msg.Attempts,
+			&msg.LastError,
+		); err != nil {
+			return nil, fmt.Errorf("scan: %w", err)
+		}
+
+		messages = append(messages, msg)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows: %w", err)
+	}
+
+	// RETURNING не сохраняет порядок подзапроса.
+	sort.Slice(messages, func(i, j int) bool { return messages[i].ID < messages[j].ID })
+
+	return messages, nil
+}
+
+func (r *Repository) MarkSent(ctx context.Context, id int64) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE outbox
+		SET sent_at = now(), attempts = attempts + 1, last_error = NULL
+		WHERE id = $1`, id)
+
+	if err != nil {
+		return fmt.Errorf("exec context: %w", err)
+	}
+
+	return nil
+}
+
+// MarkFailed сохраняет ошибку публикации и откладывает следующую попытку на delay.
+// Время считается по часам базы, как в Claim и в значениях по умолчанию колонок.
+func (r *Repository) MarkFailed(ctx context.Context, id int64, errText string, delay time.Duration) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE outbox
+		SET attempts = attempts + 1, last_error = $2, available_at = now() + make_interval(secs => $3)
+		WHERE id = $1`, id, errText, delay.Seconds())
+
+	if err != nil {
+		return fmt.Errorf("exec context: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteSent удаляет события, отправленные раньше, чем age назад.
+func (r *Repository) DeleteSent(ctx context.Context, age time.Duration) (int64, error) {
+	res, err := r.db.ExecContext(ctx,
+		`DELETE FROM outbox WHERE sent_at IS NOT NULL AND sent_at < now() - make_interval(secs => $1)`, age.Seconds())
+	if err != nil {
+		return 0, fmt.Errorf("exec context: %w", err)
+	}
+
+	deleted, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("rows affected: %w", err)
+	}
+
+	return deleted, nil
+}
+
+func nullJSON(data []byte) interface{} {
+	if len(data) == 0 {
+		return nil
+	}
+
+	return string(data)
+}
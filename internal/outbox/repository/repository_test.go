@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/testdb"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/transaction"
+)
+
+func newTestRepository(t *testing.T) (*Repository, *sqlx.DB) {
+	t.Helper()
+
+	client := testdb.Open(t)
+	testdb.Exec(t, client, `TRUNCATE outbox`)
+
+	return NewRepository(client), client
+}
+
+func writeMessages(t *testing.T, r *Repository, n int) {
+	t.Helper()
+
+	for i := 0; i < n; i++ {
+		err := r.Write(context.Background(), Message{
+			ActorID:    1,
+			EntityType: "entry",
+			EntityID:   int64(i + 1),
+			Action:     "create",
+			After:      []byte(`{"id":1}`),
+		})
+		if err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+}
+
+func claim(t *testing.T, r *Repository, limit int, lease time.Duration, want int) []Message {
+	t.Helper()
+
+	messages, err := r.Claim(context.Background(), limit, lease)
+	if err != nil {
+		t.Fatalf("claim: %v", err)
+	}
+	if len(messages) != want {
+		t.Fatalf("claimed %d messages, want %d", len(messages), want)
+	}
+
+	return messages
+}
+
+// expire делает события снова доступными, как будто срок захвата или задержка истекли.
+func expire(t *testing.T, client *sqlx.DB) {
+	t.Helper()
+
+	testdb.Exec(t, client, `UPDATE outbox SET available_at = now() - interval '1 second' WHERE sent_at IS NULL`)
+}
+
+func TestWriteInRolledBackTx(t *testing.T) {
+	r, client := newTestRepository(t)
+	writeMessages(t, r, 1)
+
+	errRollback := context.Canceled
+	err := transaction.NewManager(client).WithinTx(context.Background(), func(ctx context.Context) error {
+		if err := r.Write(ctx, Message{ActorID: 1, EntityType: "entry", EntityID: 1, Action: "delete"}); err != nil {
+			return err
+		}
+
+		return errRollback
+	})
+	if err != errRollback {
+		t.Fatalf("within tx: error %v, want %v", err, errRollback)
+	}
+
+	// Событие из откаченной транзакции не публикуется, записанное вне ее — публикуется.
+	claimed := claim(t, r, 10, time.Minute, 1)
+	if claimed[0].Action != "create" {
+		t.Errorf("claimed %q event, want create", claimed[0].Action)
+	}
+	if len(claimed[0].IdempotencyKey) != 32 {
+		t.Errorf("idempotency key %q, want 32 hex digits", claimed[0].IdempotencyKey)
+	}
+}
+
+func TestClaimLease(t *testing.T) {
+	r, client := newTestRepository(t)
+	writeMessages(t, r, 3)
+
+	first := claim(t, r, 2, time.Hour, 2)
+
+	// Захваченные события не достаются другому экземпляру до истечения срока.
+	second := claim(t, r, 10, time.Hour, 1)
+	if second[0].ID <= first[1].ID {
+		t.Errorf("second claim got message %d, want one after %d", second[0].ID, first[1].ID)
+	}
+	claim(t, r, 10, time.Hour, 0)
+
+	// Relay упал, не отметив события: после срока захвата они публикуются снова с тем же ключом.
+	expire(t, client)
+	again := claim(t, r, 10, time.Hour, 3)
+	if again[0].IdempotencyKey != first[0].IdempotencyKey {
+		t.Errorf("redelivered key %q, want %q", again[0].IdempotencyKey, first[0].IdempotencyKey)
+	}
+	if again[0].Attempts != 0 {
+		t.Errorf("attempts %d, want 0: claim is not a failed attempt", again[0].Attempts)
+	}
+}
+
+func TestClaimSkipsLockedRows(t *testing.T) {
+	r, client := newTestRepository(t)
+	writeMessages(t, r, 2)
+
+	// Другой экземпляр relay держит блокировку первой строки в незавершенной транзакции.
+	ctx := context.Background()
+	tx, err := client.BeginTxx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var lockedID int64
+	if err = tx.GetContext(ctx, &lockedID, `SELECT id FROM outbox ORDER BY id LIMIT 1 FOR UPDATE`); err != nil {
+		t.Fatal(err)
+	}
+
+	claimCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	claimed, err := r.Claim(claimCtx, 10, time.Hour)
+	if err != nil {
+		t.Fatalf("claim must not wait for locked rows: %v", err)
+	}
+	if len(claimed) != 1 || claimed[0].ID == lockedID {
+		t.Errorf("claimed %+v, want only the unlocked message", claimed)
+	}
+}
+
+func TestMarkFailedAndSent(t *testing.T) {
+	r, client := newTestRepository(t)
+	ctx := context.Background()
+	writeMessages(t, r, 1)
+
+	id := claim(t, r, 10, time.Minute, 1)[0].ID
+
+	if err := r.MarkFailed(ctx, id, "subscriber unavailable", time.Hour); err != nil {
+		t.Fatalf("mark failed: %v", err)
+	}
+
+	// Задержка отсчитывается от текущего момента, а не от срока захвата.
+	var delay float64
+	if err := client.Get(&delay, `SELECT EXTRACT(EPOCH FROM available_at - now()) FROM outbox WHERE id = $1`, id); err != nil {
+		t.Fatal(err)
+	}
+	if delay < time.Hour.Seconds()-5 || delay > time.Hour.Seconds() {
+		t.Errorf("next attempt in %.0fs, want about an hour", delay)
+	}
+	claim(t, r, 10, time.Minute, 0)
+
+	expire(t, client)
+	failed := claim(t, r, 10, time.Minute, 1)[0]
+	if failed.Attempts != 1 || failed.LastError.String != "subscriber unavailable" {
+		t.Errorf("attempts %d, last error %q, want 1 and the publish error", failed.Attempts, failed.LastError.String)
+	}
+
+	if err := r.MarkSent(ctx, id); err != nil {
+		t.Fatalf("mark sent: %v", err)
+	}
+	expire(t, client)
+	claim(t, r, 10, time.Minute, 0)
+
+	// Свежие отправленные события хранятся, старше срока хранения — удаляются.
+	deleted, err := r.DeleteSent(ctx, time.Hour)
+	if err != nil || deleted != 0 {
+		t.Errorf("delete sent: %d, %v, want nothing deleted", deleted, err)
+	}
+
+	testdb.Exec(t, client, `UPDATE outbox SET sent_at = now() - interval '2 hours' WHERE id = $1`, id)
+	deleted, err = r.DeleteSent(ctx, time.Hour)
+	if err != nil || deleted != 1 {
+		t.Errorf("delete sent: %d, %v, want 1 deleted", deleted, err)
+	}
+}
@@ -0,0 +1,22 @@
+package usecase
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Message событие из outbox: изменение записи, проекта или цели.
+type Message struct {
+	ID int64
+	// Уникальный ключ события, одинаковый при повторных публикациях.
+	Key         string
+	ActorID     int64
+	WorkspaceID int64
+	EntityType  string
+	EntityID    int64
+	Action      string
+	Before      json.RawMessage
+	After       json.RawMessage
+	RequestID   string
+	CreatedAt   time.Time
+}
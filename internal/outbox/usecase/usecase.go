@@ -0,0 +1,120 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	repo "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/outbox/repository"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/tracing"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/utils"
+)
+
+const (
+	// Пока порция событий в работе, другие экземпляры relay ее не берут.
+	claimLease = 5 * time.Minute
+	// Задержки повторной публикации после ошибки: от retryBase, удваиваясь, до maxRetryDelay.
+	retryBase     = time.Second
+	maxRetryDelay = 10 * time.Minute
+)
+
+type repository interface {
+	Claim(ctx context.Context, limit int, lease time.Duration) ([]repo.Message, error)
+	MarkSent(ctx context.Context, id int64) error
+	MarkFailed(ctx context.Context, id int64, errText string, delay time.Duration) error
+	DeleteSent(ctx context.Context, age time.Duration) (int64, error)
+}
+
+// Handler получатель событий outbox. Событие может прийти повторно, если relay упал
+// после публикации или ошибся другой получатель, поэтому повторы отбрасываются по Key.
+type Handler interface {
+	Publish(ctx context.Context, msg Message) error
+}
+
+type Usecase struct {
+	repository repository
+	handlers   []Handler
+	// Сколько событий публиковать за один вызов Relay.
+	batchSize int
+	// Сколько хранить отправленные события.
+	retention time.Duration
+}
+
+func NewUsecase(repository repository, batchSize int, retention time.Duration, handlers ...Handler) *Usecase {
+	return &Usecase{
+		repository: repository,
+		handlers:   handlers,
+		batchSize:  batchSize,
+		retention:  retention,
+	}
+}
+
+// Relay публикует очередную порцию событий всем получателям и возвращает размер порции.
+// Событие отмечается отправленным, только когда его приняли все получатели; иначе
+// публикация повторяется с растущей задержкой.
+func (u *Usecase) Relay(ctx context.Context) (int, error) {
+	ctx, span := tracing.Start(ctx, "outbox.Relay")
+	defer span.End()
+
+	messages, err := u.repository.Claim(ctx, u.batchSize, claimLease)
+	if err != nil {
+		return 0, fmt.Errorf("repo claim: %w", err)
+	}
+
+	for _, m := range messages {
+		msg := convertFromRepoMessage(m)
+
+		if err = u.publish(ctx, msg); err != nil {
+			if err = u.repository.MarkFailed(ctx, m.ID, err.Error(), utils.RetryDelay(retryBase, maxRetryDelay, m.Attempts+1)); err != nil {
+				return 0, fmt.Errorf("repo mark failed: %w", err)
+			}
+
+			continue
+		}
+
+		if err = u.repository.MarkSent(ctx, m.ID); err != nil {
+			return 0, fmt.Errorf("repo mark sent: %w", err)
+		}
+	}
+
+	return len(messages), nil
+}
+
+// PurgeSent удаляет отправленные события старше срока хранения.
+func (u *Usecase) PurgeSent(ctx context.Context) (int64, error) {
+	ctx, span := tracing.Start(ctx, "outbox.PurgeSent")
+	defer span.End()
+
+	deleted, err := u.repository.DeleteSent(ctx, u.retention)
+	if err != nil {
+		return 0, fmt.Errorf("repo delete sent: %w", err)
+	}
+
+	return deleted, nil
+}
+
+func (u *Usecase) publish(ctx context.Context, msg Message) error {
+	for _, h := range u.handlers {
+		if err := h.Publish(ctx, msg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func convertFromRepoMessage(m repo.Message) Message {
+	return Message{
+		ID:          m.ID,
+		Key:         m.IdempotencyKey,
+		ActorID:     m.ActorID,
+		WorkspaceID: m.WorkspaceID.Int64,
+		EntityType:  m.EntityType,
+		EntityID:    m.EntityID,
+		Action:      m.Action,
+		Before:      m.Before,
+		After:       m.After,
+		RequestID:   m.RequestID,
+		CreatedAt:   m.CreatedAt,
+	}
+}
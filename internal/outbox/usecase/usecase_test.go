@@ -0,0 +1,214 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	repo "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/outbox/repository"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/utils"
+)
+
+// fakeMessage событие в памяти с полями, которые в таблице outbox меняют Claim и Mark*.
+type fakeMessage struct {
+	msg         repo.Message
+	availableAt time.Time
+	sent        bool
+}
+
+// fakeRepository повторяет правила таблицы outbox: Claim берет неотправленные события,
+// срок которых наступил, и откладывает их на lease. Время задается полем now.
+type fakeRepository struct {
+	now      time.Time
+	messages []*fakeMessage
+
+	// Ошибка MarkSent имитирует падение relay между публикацией и отметкой об отправке.
+	markSentErr error
+	delays      []time.Duration
+}
+
+func (r *fakeRepository) add(key string) {
+	r.messages = append(r.messages, &fakeMessage{
+		msg:         repo.Message{ID: int64(len(r.messages) + 1), IdempotencyKey: key},
+		availableAt: r.now,
+	})
+}
+
+func (r *fakeRepository) get(id int64) *fakeMessage {
+	return r.messages[id-1]
+}
+
+func (r *fakeRepository) Claim(_ context.Context, limit int, lease time.Duration) ([]repo.Message, error) {
+	var claimed []repo.Message
+	for _, m := range r.messages {
+		if len(claimed) == limit {
+			break
+		}
+		if m.sent || m.availableAt.After(r.now) {
+			continue
+		}
+
+		m.availableAt = r.now.Add(lease)
+		claimed = append(claimed, m.msg)
+	}
+
+	return claimed, nil
+}
+
+func (r *fakeRepository) MarkSent(_ context.Context, id int64) error {
+	if r.markSentErr != nil {
+		return r.markSentErr
+	}
+
+	m := r.get(id)
+	m.msg.Attempts++
+	m.sent = true
+	return nil
+}
+
+func (r *fakeRepository) MarkFailed(_ context.Context, id int64, errText string, delay time.Duration) error {
+	m := r.get(id)
+	m.msg.Attempts++
+	m.msg.LastError.String, m.msg.LastError.Valid = errText, true
+	m.availableAt = r.now.Add(delay)
+	r.delays = append(r.delays, delay)
+	return nil
+}
+
+func (r *fakeRepository) DeleteSent(context.Context, time.Duration) (int64, error) {
+	return 0, nil
+}
+
+// fakeHandler запоминает ключи опубликованных событий и отклоняет события из fail.
+type fakeHandler struct {
+	published []string
+	fail      map[string]bool
+}
+
+func (h *fakeHandler) Publish(_ context.Context, msg Message) error {
+	h.published = append(h.published, msg.Key)
+	if h.fail[msg.Key] {
+		return errors.New("subscriber unavailable")
+	}
+
+	return nil
+}
+
+// relay вызывает Relay и проверяет размер порции.
+func relay(t *testing.T, u *Usecase, want int) {
+	t.Helper()
+
+	relayed, err := u.Relay(context.Background())
+	if err != nil {
+		t.Fatalf("relay: %v", err)
+	}
+	if relayed != want {
+		t.Fatalf("relayed %d, want %d", relayed, want)
+	}
+}
+
+func checkPublished(t *testing.T, h *fakeHandler, want []string) {
+	t.Helper()
+
+	if !reflect.DeepEqual(h.published, want) {
+		t.Errorf("published %v, want %v", h.published, want)
+	}
+}
+
+func TestRelayMarksSentWhenAllHandlersAccept(t *testing.T) {
+	r := &fakeRepository{now: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)}
+	r.add("a")
+	r.add("b")
+	r.add("c")
+
+	first, second := &fakeHandler{}, &fakeHandler{}
+	u := NewUsecase(r, 2, time.Hour, first, second)
+
+	relay(t, u, 2)
+	relay(t, u, 1)
+	relay(t, u, 0)
+
+	checkPublished(t, first, []string{"a", "b", "c"})
+	checkPublished(t, second, []string{"a", "b", "c"})
+	for _, m := range r.messages {
+		if !m.sent {
+			t.Errorf("message %s is not marked sent", m.msg.IdempotencyKey)
+		}
+	}
+}
+
+func TestRelayRetriesWithBackoff(t *testing.T) {
+	r := &fakeRepository{now: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)}
+	r.add("a")
+	r.add("b")
+
+	// Второй получатель отклоняет a: событие не отмечается отправленным, хотя первый его принял.
+	first, second := &fakeHandler{}, &fakeHandler{fail: map[string]bool{"a": true}}
+	u := NewUsecase(r, 10, time.Hour, first, second)
+
+	for attempt := 1; attempt <= 3; attempt++ {
+		// В первой порции вместе с a публикуется b, дальше повторяется только a.
+		wantRelayed := 1
+		if attempt == 1 {
+			wantRelayed = 2
+		}
+		relay(t, u, wantRelayed)
+
+		a := r.get(1)
+		if a.sent || a.msg.Attempts != attempt || a.msg.LastError.String != "subscriber unavailable" {
+			t.Errorf("attempt %d: sent %v, attempts %d, last error %q", attempt, a.sent, a.msg.Attempts, a.msg.LastError.String)
+		}
+
+		wantDelay := utils.RetryDelay(retryBase, maxRetryDelay, attempt)
+		if r.delays[attempt-1] != wantDelay {
+			t.Errorf("attempt %d: delay %s, want %s", attempt, r.delays[attempt-1], wantDelay)
+		}
+
+		// До истечения задержки событие не публикуется.
+		r.now = r.now.Add(wantDelay - time.Millisecond)
+		relay(t, u, 0)
+
+		r.now = r.now.Add(time.Millisecond)
+	}
+
+	if !r.get(2).sent {
+		t.Error("message b is not marked sent")
+	}
+	checkPublished(t, first, []string{"a", "b", "a", "a"})
+
+	second.fail = nil
+	relay(t, u, 1)
+	if !r.get(1).sent {
+		t.Error("message a is not marked sent after the subscriber recovered")
+	}
+}
+
+func TestRelayRedeliversAfterCrashBeforeAck(t *testing.T) {
+	r := &fakeRepository{now: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)}
+	r.add("a")
+
+	h := &fakeHandler{}
+	u := NewUsecase(r, 10, time.Hour, h)
+
+	r.markSentErr = errors.New("connection reset")
+	if _, err := u.Relay(context.Background()); !errors.Is(err, r.markSentErr) {
+		t.Fatalf("relay: error %v, want %v", err, r.markSentErr)
+	}
+	checkPublished(t, h, []string{"a"})
+
+	// Пока не истек срок захвата, событие не видно ни этому, ни другим экземплярам relay.
+	r.markSentErr = nil
+	r.now = r.now.Add(claimLease - time.Second)
+	relay(t, u, 0)
+
+	r.now = r.now.Add(time.Second)
+	relay(t, u, 1)
+
+	// Повтор приходит с тем же ключом, по нему получатель отбрасывает дубликат.
+	checkPublished(t, h, []string{"a", "a"})
+	if !r.get(1).sent {
+		t.Error("message a is not marked sent after redelivery")
+	}
+}
@@ -0,0 +1,23 @@
+package delivery
+
+import "time"
+
+type SetLockIn struct {
+	LockedBefore time.Time `json:"locked_before" validate:"required" example:"2024-03-01T00:00:00Z"` // Записи, начавшиеся раньше этого времени, изменять нельзя.
+}
+
+type LockOut struct {
+	WorkspaceID  int64     `json:"workspace_id" example:"1"`                     // Идентификатор пространства.
+	LockedBefore time.Time `json:"locked_before" example:"2024-03-01T00:00:00Z"` // Граница заблокированного периода.
+	LockedBy     int64     `json:"locked_by" example:"1"`                        // Кто установил блокировку.
+	UpdatedAt    time.Time `json:"updated_at" example:"2024-03-02T10:00:00Z"`    // Время установки блокировки.
+}
+
+type OverrideOut struct {
+	ID        int64     `json:"id" example:"1"`                            // Идентификатор записи журнала.
+	UserID    int64     `json:"user_id" example:"1"`                       // Кто изменил запись.
+	EntryID   int64     `json:"entry_id" example:"10"`                     // Идентификатор измененной записи времени.
+	Action    string    `json:"action" example:"update"`                   // Действие: create, update, delete.
+	Reason    string    `json:"reason" example:"исправление после аудита"` // Причина изменения.
+	CreatedAt time.Time `json:"created_at" example:"2024-03-05T10:00:00Z"` // Время изменения.
+}
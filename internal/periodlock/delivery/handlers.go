@@ -0,0 +1,243 @@
+package delivery
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	usecaseDto "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/periodlock/usecase"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/response"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/validator"
+)
+
+type usecase interface {
+	SetLock(ctx context.Context, workspaceID, actorID int64, lockedBefore time.Time) error
+	GetLock(ctx context.Context, workspaceID, actorID int64) (usecaseDto.Lock, error)
+	DeleteLock(ctx context.Context, workspaceID, actorID int64) error
+	GetOverrides(ctx context.Context, workspaceID, actorID int64) ([]usecaseDto.Override, error)
+}
+
+type Delivery struct {
+	usecase usecase
+
+	logger echo.Logger
+}
+
+func RegisterHandlers(
+	e *echo.Echo,
+	usecase usecase,
+	logger echo.Logger,
+) {
+	handler := &Delivery{
+		usecase: usecase,
+
+		logger: logger,
+	}
+
+	e.PUT("/workspaces/:workspace_id/lock", handler.SetLock)
+	e.GET("/workspaces/:workspace_id/lock", handler.GetLock)
+	e.DELETE("/workspaces/:workspace_id/lock", handler.DeleteLock)
+	e.GET("/workspaces/:workspace_id/lock/overrides", handler.GetOverrides)
+}
+
+// SetLock godoc
+// @Summary      Заблокировать период.
+// @Description  Запретить создание, изменение и удаление записей пространства, начавшихся раньше указанного времени. Доступно admin и owner.
+// @Tags     	 period lock
+// @Accept	 application/json
+// @Produce  application/json
+// @Param    workspace_id path int true "Идентификатор пространства"
+// @Param    lock body SetLockIn true "Граница блокировки"
+// @Success  200  "success set lock"
+// @Failure 500 {object} response.Error "internal server error"
+// @Failure 400 {object} response.Error "bad request"
+// @Failure 403 {object} response.Error "forbidden"
+// @Failure 422 {object} response.Error "unprocessable entity"
+// @Router   /workspaces/{workspace_id}/lock [put]
+func (d *Delivery) SetLock(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	workspaceID, err := strconv.ParseInt(c.Param("workspace_id"), 10, 64)
+	if err != nil {
+		c.Logger().Errorf("parse int: %v", err)
+		return response.InvalidParam("workspace_id", "int")
+	}
+
+	var in SetLockIn
+	err = c.Bind(&in)
+
+	if err != nil {
+		c.Logger().Errorf("bind request: %v", err)
+		return response.Status(http.StatusUnprocessableEntity)
+	}
+
+	if ok, err := validator.IsRequestValid(&in); !ok {
+		c.Logger().Errorf("validation: %v", err)
+		return response.ValidationError(err)
+	}
+
+	userID, ok := c.Get("user_id").(int64)
+	if !ok {
+		c.Logger().Error("can't parse context user_id")
+		return response.Status(http.StatusInternalServerError)
+	}
+
+	err = d.usecase.SetLock(ctx, workspaceID, userID, in.LockedBefore)
+	if err != nil {
+		c.Logger().Errorf("usecase: %v", err)
+		return handleUsecaseError(err)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+// GetLock godoc
+// @Summary      Получить блокировку периода.
+// @Description  Получить текущую границу заблокированного периода пространства.
+// @Tags     	 period lock
+// @Accept	 	application/json
+// @Produce  	application/json
+// @Param    workspace_id path int true "Идентификатор пространства"
+// @Success  200 {object} LockOut "success get lock"
+// @Failure 500 {object} response.Error "internal server error"
+// @Failure 400 {object} response.Error "bad request"
+// @Failure 403 {object} response.Error "forbidden"
+// @Failure 404 {object} response.Error "item is not found"
+// @Router   /workspaces/{workspace_id}/lock [get]
+func (d *Delivery) GetLock(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	workspaceID, err := strconv.ParseInt(c.Param("workspace_id"), 10, 64)
+	if err != nil {
+		c.Logger().Errorf("parse int: %v", err)
+		return response.InvalidParam("workspace_id", "int")
+	}
+
+	userID, ok := c.Get("user_id").(int64)
+	if !ok {
+		c.Logger().Error("can't parse context user_id")
+		return response.Status(http.StatusInternalServerError)
+	}
+
+	lock, err := d.usecase.GetLock(ctx, workspaceID, userID)
+	if err != nil {
+		c.Logger().Errorf("usecase: %v", err)
+		return handleUsecaseError(err)
+	}
+
+	out := LockOut{
+		WorkspaceID:  lock.WorkspaceID,
+		LockedBefore: lock.LockedBefore,
+		LockedBy:     lock.LockedBy,
+		UpdatedAt:    lock.UpdatedAt,
+	}
+
+	return c.JSON(http.StatusOK, out)
+}
+
+// DeleteLock godoc
+// @Summary      Снять блокировку периода.
+// @Description  Снять блокировку периода пространства. Доступно admin и owner.
+// @Tags     	 period lock
+// @Accept	 application/json
+// @Produce  application/json
+// @Param    workspace_id path int true "Идентификатор пространства"
+// @Success  200  "success delete lock"
+// @Failure 500 {object} response.Error "internal server error"
+// @Failure 400 {object} response.Error "bad request"
+// @Failure 403 {object} response.Error "forbidden"
+// @Failure 404 {object} response.Error "item is not found"
+// @Router   /workspaces/{workspace_id}/lock [delete]
+func (d *Delivery) DeleteLock(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	workspaceID, err := strconv.ParseInt(c.Param("workspace_id"), 10, 64)
+	if err != nil {
+		c.Logger().Errorf("parse int: %v", err)
+		return response.InvalidParam("workspace_id", "int")
+	}
+
+	userID, ok := c.Get("user_id").(int64)
+	if !ok {
+		c.Logger().Error("can't parse context user_id")
+		return response.Status(http.StatusInternalServerError)
+	}
+
+	err = d.usecase.DeleteLock(ctx, workspaceID, userID)
+	if err != nil {
+		c.Logger().Errorf("usecase: %v", err)
+		return handleUsecaseError(err)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+// GetOverrides godoc
+// @Summary      Журнал изменений в заблокированном периоде.
+// @Description  Получить изменения записей, выполненные администраторами в обход блокировки периода. Доступно admin и owner.
+// @Tags     	 period lock
+// @Accept	 	application/json
+// @Produce  	application/json
+// @Param    workspace_id path int true "Идентификатор пространства"
+// @Success  200 {object} []OverrideOut "success get overrides"
+// @Failure 500 {object} response.Error "internal server error"
+// @Failure 400 {object} response.Error "bad request"
+// @Failure 403 {object} response.Error "forbidden"
+// @Router   /workspaces/{workspace_id}/lock/overrides [get]
+func (d *Delivery) GetOverrides(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	workspaceID, err := strconv.ParseInt(c.Param("workspace_id"), 10, 64)
+	if err != nil {
+		c.Logger().Errorf("parse int: %v", err)
+		return response.InvalidParam("workspace_id", "int")
+	}
+
+	userID, ok := c.Get("user_id").(int64)
+	if !ok {
+		c.Logger().Error("can't parse context user_id")
+		return response.Status(http.StatusInternalServerError)
+	}
+
+	overrides, err := d.usecase.GetOverrides(ctx, workspaceID, userID)
+	if err != nil {
+		c.Logger().Errorf("usecase: %v", err)
+		return handleUsecaseError(err)
+	}
+
+	out := make([]OverrideOut, 0, len(overrides))
+	for _, o := range overrides {
+		out = append(out, OverrideOut{
+			ID:        o.ID,
+			UserID:    o.UserID,
+			EntryID:   o.EntryID,
+			Action:    o.Action,
+			Reason:    o.Reason,
+			CreatedAt: o.CreatedAt,
+		})
+	}
+
+	return c.JSON(http.StatusOK, out)
+}
+
+func handleUsecaseError(err error) *response.Error {
+	// Запрос не уложился в таймаут или был отменен.
+	if problem := response.ContextError(err); problem != nil {
+		return problem
+	}
+
+	// Блокировка не установлена.
+	if errors.Is(err, usecaseDto.ErrLockNotFound) {
+		return response.NotFound("lock")
+	}
+	if errors.Is(err, usecaseDto.ErrForbidden) {
+		return response.Status(http.StatusForbidden)
+	}
+
+	// По дефолту пятисотим.
+	return response.Status(http.StatusInternalServerError)
+}
@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+)
+
+type Lock struct {
+	WorkspaceID  int64         `db:"workspace_id"`
+	LockedBefore time.Time     `db:"locked_before"`
+	LockedBy     sql.NullInt64 `db:"locked_by"`
+	UpdatedAt    time.Time     `db:"updated_at"`
+}
+
+// Override изменение записи в заблокированном периоде по разрешению администратора.
+type Override struct {
+	ID          int64         `db:"id"`
+	WorkspaceID int64         `db:"workspace_id"`
+	UserID      sql.NullInt64 `db:"user_id"`
+	EntryID     int64         `db:"entry_id"`
+	Action      string        `db:"action"`
+	Reason      string        `db:"reason"`
+	CreatedAt   time.Time     `db:"created_at"`
+}
@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/transaction"
+)
+
+var (
+	ErrLockNotFound     = errors.New("period lock not found")
+	ErrOverrideNotFound = errors.New("period lock override not found")
+)
+
+type Repository struct {
+	db    *sqlx.DB
+	close func() error
+}
+
+func NewRepository(db *sqlx.DB) *Repository {
+	return &Repository{
+		db: db,
+		close: func() error {
+			return db.Close()
+		},
+	}
+}
+
+// Close закрывает пул соединений с базой.
+func (r *Repository) Close() error {
+	return r.close()
+}
+
+// SetLock устанавливает или сдвигает дату блокировки пространства.
+func (r *Repository) SetLock(ctx context.Context, lock Lock) error {
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO period_locks
+				(
+					workspace_id,
+					locked_before,
+					locked_by
+				) VALUES ($1, $2, $3)
+		ON CONFLICT (workspace_id) DO UPDATE
+		SET locked_before = EXCLUDED.locked_before,
+			locked_by = EXCLUDED.locked_by,
+			updated_at = now()`,
+		lock.WorkspaceID,
+		lock.LockedBefore,
+		lock.LockedBy,
+	)
+
+	if err != nil {
+		return fmt.Errorf("exec: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) GetLock(ctx context.Context, workspaceID int64) (Lock, error) {
+	var lock Lock
+	err := r.db.QueryRowContext(ctx,
+		`SELECT
+			workspace_id,
+			locked_before,
+			locked_by,
+			updated_at
+		FROM period_locks
+		WHERE workspace_id = $1`, workspaceID).
+		Scan(&lock.WorkspaceID, &lock.LockedBefore, &lock.LockedBy, &lock.UpdatedAt)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Lock{}, ErrLockNotFound
+		}
+
+		return Lock{}, fmt.Errorf("scan: %w", err)
+	}
+
+	return lock, nil
+}
+
+func (r *Repository) DeleteLock(ctx context.Context, workspaceID int64) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM period_locks WHERE workspace_id = $1`, workspaceID)
+	if err != nil {
+		return fmt.Errorf("exec: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("rows affected: %w", err)
+	}
+
+	if affected == 0 {
+		return ErrLockNotFound
+	}
+
+	return nil
+}
+
+// CreateOverride записывает обход блокировки. Пишется в транзакции изменения записи, если она есть.
+func (r *Repository) CreateOverride(ctx context.Context, override Override) error {
+	_, err := transaction.GetExecutor(ctx, r.db).ExecContext(ctx,
+		`INSERT INTO period_lock_overrides
+				(
+					workspace_id,
+					user_id,
+					entry_id,
+					action,
+					reason
+				) VALUES ($1, $2, $3, $4, $5)`,
+		override.WorkspaceID,
+		override.UserID,
+		override.EntryID,
+		override.Action,
+		override.Reason,
+	)
+
+	if err != nil {
+		return fmt.Errorf("exec: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) GetOverrides(ctx context.Context, workspaceID int64) ([]Override, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT
+			id,
+			workspace_id,
+			user_id,
+			entry_id,
+			action,
+			reason,
+			created_at
+		FROM period_lock_overrides
+		WHERE workspace_id = $1
+		ORDER BY created_at DESC`, workspaceID)
+
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var overrides []Override
+	for rows.Next() {
+		var o Override
+		if err = rows.Scan(
+			&o.ID,
+			&o.WorkspaceID,
+			&o.UserID,
+			&o.EntryID,
+			&o.Action,
+			&o.Reason,
+			&o.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan: %w", err)
+		}
+
+		overrides = append(overrides, o)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("rows err: %w", rows.Err())
+	}
+
+	if len(overrides) == 0 {
+		return nil, ErrOverrideNotFound
+	}
+
+	return overrides, nil
+}
@@ -0,0 +1,20 @@
+package usecase
+
+import "time"
+
+type Lock struct {
+	WorkspaceID  int64
+	LockedBefore time.Time
+	LockedBy     int64
+	UpdatedAt    time.Time
+}
+
+type Override struct {
+	ID          int64
+	WorkspaceID int64
+	UserID      int64
+	EntryID     int64
+	Action      string
+	Reason      string
+	CreatedAt   time.Time
+}
@@ -0,0 +1,155 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	repo "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/periodlock/repository"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/roles"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/tracing"
+	workspaceRepoDto "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/workspace/repository"
+)
+
+var (
+	ErrLockNotFound = errors.New("period lock not found")
+	ErrForbidden    = errors.New("forbidden")
+)
+
+type repository interface {
+	SetLock(ctx context.Context, lock repo.Lock) error
+	GetLock(ctx context.Context, workspaceID int64) (repo.Lock, error)
+	DeleteLock(ctx context.Context, workspaceID int64) error
+	GetOverrides(ctx context.Context, workspaceID int64) ([]repo.Override, error)
+}
+
+type workspaceRepository interface {
+	GetMemberRole(ctx context.Context, workspaceID, userID int64) (string, error)
+}
+
+type Usecase struct {
+	repository          repository
+	workspaceRepository workspaceRepository
+}
+
+func NewUsecase(repository repository, workspaceRepository workspaceRepository) *Usecase {
+	return &Usecase{
+		repository:          repository,
+		workspaceRepository: workspaceRepository,
+	}
+}
+
+// SetLock блокирует изменение записей пространства, начавшихся раньше lockedBefore.
+func (u *Usecase) SetLock(ctx context.Context, workspaceID, actorID int64, lockedBefore time.Time) error {
+	ctx, span := tracing.Start(ctx, "periodlock.SetLock")
+	defer span.End()
+
+	if err := u.requireRole(ctx, workspaceID, actorID, roles.Admin); err != nil {
+		return err
+	}
+
+	err := u.repository.SetLock(ctx, repo.Lock{
+		WorkspaceID:  workspaceID,
+		LockedBefore: lockedBefore,
+		LockedBy:     sql.NullInt64{Int64: actorID, Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("repo set lock: %w", err)
+	}
+
+	return nil
+}
+
+func (u *Usecase) GetLock(ctx context.Context, workspaceID, actorID int64) (Lock, error) {
+	ctx, span := tracing.Start(ctx, "periodlock.GetLock")
+	defer span.End()
+
+	if err := u.requireRole(ctx, workspaceID, actorID, roles.Viewer); err != nil {
+		return Lock{}, err
+	}
+
+	lock, err := u.repository.GetLock(ctx, workspaceID)
+	if err != nil {
+		if errors.Is(err, repo.ErrLockNotFound) {
+			return Lock{}, ErrLockNotFound
+		}
+		return Lock{}, fmt.Errorf("repo get lock: %w", err)
+	}
+
+	return Lock{
+		WorkspaceID:  lock.WorkspaceID,
+		LockedBefore: lock.LockedBefore,
+		LockedBy:     lock.LockedBy.Int64,
+		UpdatedAt:    lock.UpdatedAt,
+	}, nil
+}
+
+func (u *Usecase) DeleteLock(ctx context.Context, workspaceID, actorID int64) error {
+	ctx, span := tracing.Start(ctx, "periodlock.DeleteLock")
+	defer span.End()
+
+	if err := u.requireRole(ctx, workspaceID, actorID, roles.Admin); err != nil {
+		return err
+	}
+
+	err := u.repository.DeleteLock(ctx, workspaceID)
+	if err != nil {
+		if errors.Is(err, repo.ErrLockNotFound) {
+			return ErrLockNotFound
+		}
+		return fmt.Errorf("repo delete lock: %w", err)
+	}
+
+	return nil
+}
+
+// GetOverrides возвращает журнал изменений в заблокированном периоде, доступно admin и owner.
+func (u *Usecase) GetOverrides(ctx context.Context, workspaceID, actorID int64) ([]Override, error) {
+	ctx, span := tracing.Start(ctx, "periodlock.GetOverrides")
+	defer span.End()
+
+	if err := u.requireRole(ctx, workspaceID, actorID, roles.Admin); err != nil {
+		return nil, err
+	}
+
+	repoOverrides, err := u.repository.GetOverrides(ctx, workspaceID)
+	if err != nil {
+		if errors.Is(err, repo.ErrOverrideNotFound) {
+			return []Override{}, nil
+		}
+		return nil, fmt.Errorf("repo get overrides: %w", err)
+	}
+
+	overrides := make([]Override, 0, len(repoOverrides))
+	for _, o := range repoOverrides {
+		overrides = append(overrides, Override{
+			ID:          o.ID,
+			WorkspaceID: o.WorkspaceID,
+			UserID:      o.UserID.Int64,
+			EntryID:     o.EntryID,
+			Action:      o.Action,
+			Reason:      o.Reason,
+			CreatedAt:   o.CreatedAt,
+		})
+	}
+
+	return overrides, nil
+}
+
+func (u *Usecase) requireRole(ctx context.Context, workspaceID, userID int64, min roles.Role) error {
+	role, err := u.workspaceRepository.GetMemberRole(ctx, workspaceID, userID)
+	if err != nil {
+		if errors.Is(err, workspaceRepoDto.ErrMemberNotFound) {
+			return ErrForbidden
+		}
+		return fmt.Errorf("repo get member role: %w", err)
+	}
+
+	if !roles.Role(role).AtLeast(min) {
+		return ErrForbidden
+	}
+
+	return nil
+}
@@ -0,0 +1,163 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	repo "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/periodlock/repository"
+	workspaceRepoDto "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/workspace/repository"
+)
+
+const testWorkspaceID = 5
+
+// fakeRepository блокировка одного пространства и журнал изменений в ней.
+type fakeRepository struct {
+	repository
+
+	lock      *repo.Lock
+	overrides []repo.Override
+}
+
+func (r *fakeRepository) SetLock(_ context.Context, lock repo.Lock) error {
+	r.lock = &lock
+	return nil
+}
+
+func (r *fakeRepository) GetLock(context.Context, int64) (repo.Lock, error) {
+	if r.lock == nil {
+		return repo.Lock{}, repo.ErrLockNotFound
+	}
+
+	return *r.lock, nil
+}
+
+func (r *fakeRepository) GetOverrides(context.Context, int64) ([]repo.Override, error) {
+	if len(r.overrides) == 0 {
+		return nil, repo.ErrOverrideNotFound
+	}
+
+	return r.overrides, nil
+}
+
+// fakeWorkspaceRepository роли участников пространства testWorkspaceID.
+type fakeWorkspaceRepository struct {
+	roles map[int64]string
+}
+
+func (r *fakeWorkspaceRepository) GetMemberRole(_ context.Context, _, userID int64) (string, error) {
+	role, ok := r.roles[userID]
+	if !ok {
+		return "", workspaceRepoDto.ErrMemberNotFound
+	}
+
+	return role, nil
+}
+
+// Пользователи с ролями: 1 owner, 2 admin, 3 member, 4 viewer, 5 не участник.
+func newTestUsecase(r *fakeRepository) *Usecase {
+	return NewUsecase(r, &fakeWorkspaceRepository{roles: map[int64]string{
+		1: "owner",
+		2: "admin",
+		3: "member",
+		4: "viewer",
+	}})
+}
+
+func TestSetLockRequiresAdmin(t *testing.T) {
+	lockedBefore := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		actorID int64
+		wantErr error
+	}{
+		{actorID: 1},
+		{actorID: 2},
+		{actorID: 3, wantErr: ErrForbidden},
+		{actorID: 4, wantErr: ErrForbidden},
+		{actorID: 5, wantErr: ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		r := &fakeRepository{}
+		u := newTestUsecase(r)
+
+		err := u.SetLock(context.Background(), testWorkspaceID, tt.actorID, lockedBefore)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("user %d: set lock error %v, want %v", tt.actorID, err, tt.wantErr)
+			continue
+		}
+
+		if tt.wantErr != nil {
+			if r.lock != nil {
+				t.Errorf("user %d: lock %+v set without permission", tt.actorID, *r.lock)
+			}
+			continue
+		}
+
+		want := repo.Lock{
+			WorkspaceID:  testWorkspaceID,
+			LockedBefore: lockedBefore,
+			LockedBy:     sql.NullInt64{Int64: tt.actorID, Valid: true},
+		}
+		if r.lock == nil || *r.lock != want {
+			t.Errorf("user %d: lock %+v, want %+v", tt.actorID, r.lock, want)
+		}
+	}
+}
+
+func TestGetLockVisibleToMembers(t *testing.T) {
+	lockedBefore := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	u := newTestUsecase(&fakeRepository{lock: &repo.Lock{WorkspaceID: testWorkspaceID, LockedBefore: lockedBefore}})
+
+	lock, err := u.GetLock(context.Background(), testWorkspaceID, 4)
+	if err != nil {
+		t.Fatalf("viewer: get lock: %v", err)
+	}
+	if !lock.LockedBefore.Equal(lockedBefore) {
+		t.Errorf("locked before %s, want %s", lock.LockedBefore, lockedBefore)
+	}
+
+	if _, err = u.GetLock(context.Background(), testWorkspaceID, 5); !errors.Is(err, ErrForbidden) {
+		t.Errorf("non-member: get lock error %v, want %v", err, ErrForbidden)
+	}
+
+	u = newTestUsecase(&fakeRepository{})
+	if _, err = u.GetLock(context.Background(), testWorkspaceID, 4); !errors.Is(err, ErrLockNotFound) {
+		t.Errorf("no lock: error %v, want %v", err, ErrLockNotFound)
+	}
+}
+
+func TestGetOverrides(t *testing.T) {
+	r := &fakeRepository{overrides: []repo.Override{{
+		ID:          1,
+		WorkspaceID: testWorkspaceID,
+		UserID:      sql.NullInt64{Int64: 3, Valid: true},
+		EntryID:     42,
+		Action:      "update",
+		Reason:      "late invoice",
+	}}}
+	u := newTestUsecase(r)
+
+	// Журнал изменений в заблокированном периоде видят только admin и owner.
+	if _, err := u.GetOverrides(context.Background(), testWorkspaceID, 3); !errors.Is(err, ErrForbidden) {
+		t.Errorf("member: error %v, want %v", err, ErrForbidden)
+	}
+
+	overrides, err := u.GetOverrides(context.Background(), testWorkspaceID, 2)
+	if err != nil {
+		t.Fatalf("admin: get overrides: %v", err)
+	}
+	want := Override{ID: 1, WorkspaceID: testWorkspaceID, UserID: 3, EntryID: 42, Action: "update", Reason: "late invoice"}
+	if len(overrides) != 1 || overrides[0] != want {
+		t.Errorf("overrides %+v, want [%+v]", overrides, want)
+	}
+
+	r.overrides = nil
+	overrides, err = u.GetOverrides(context.Background(), testWorkspaceID, 1)
+	if err != nil || overrides == nil || len(overrides) != 0 {
+		t.Errorf("empty journal: %v, %v, want an empty list", overrides, err)
+	}
+}
@@ -1,7 +1,8 @@
 package delivery
 
 type CreateProjectIn struct {
-	Name string `json:"name" validate:"required" example:"Работа"` // Название проекта.
+	Name        string `json:"name" validate:"required" example:"Работа"` // Название проекта.
+	WorkspaceID int64  `json:"workspace_id,omitempty" example:"1"`        // Идентификатор пространства, если проект общий.
 }
 
 type CreateProjectOut struct {
@@ -9,8 +10,10 @@ type CreateProjectOut struct {
 }
 
 type ProjectOut struct {
-	ID   int64  `json:"id" example:"1"`        // Идентификатор проекта.                         // Идентификатор проекта.
-	Name string `json:"name" example:"Работа"` // Название проекта.
+	ID          int64  `json:"id" example:"1"`                         // Идентификатор проекта.                         // Идентификатор проекта.
+	Name        string `json:"name" example:"Работа"`                  // Название проекта.
+	WorkspaceID int64  `json:"workspace_id,omitempty" example:"1"`     // Идентификатор пространства, если проект общий.
+	Client      string `json:"client,omitempty" example:"ООО Ромашка"` // Клиент проекта.
 }
 
 type ProjectsStatOut struct {
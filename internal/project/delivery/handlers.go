@@ -10,6 +10,7 @@ import (
 
 	"github.com/labstack/echo/v4"
 
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/export"
 	usecaseDto "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/project/usecase"
 	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/response"
 	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/validator"
@@ -18,9 +19,12 @@ import (
 type usecase interface {
 	CreateProject(ctx context.Context, project usecaseDto.Project) (int64, error)
 	GetUserProjects(ctx context.Context, userID int64) ([]usecaseDto.Project, error)
+	GetWorkspaceProjects(ctx context.Context, workspaceID, userID int64) ([]usecaseDto.Project, error)
 	ProjectsStats(ctx context.Context, userID int64, timeStart, timeEnd time.Time) (usecaseDto.AllProjectsStat, error)
 	ProjectStat(ctx context.Context, projectID int64, userID int64, timeStart, timeEnd time.Time) (usecaseDto.AllProjectEntriesStat, error)
-	ClearUserData(ctx context.Context, userID int64) error
+	ClearUserData(ctx context.Context, userID int64, opts usecaseDto.DeleteOptions) error
+	DeleteProject(ctx context.Context, projectID, userID int64, opts usecaseDto.DeleteOptions) error
+	RestoreProject(ctx context.Context, projectID, userID int64) error
 }
 
 type Delivery struct {
@@ -42,8 +46,12 @@ func RegisterHandlers(
 
 	e.POST("/projects/create", handler.CreateProject)
 	e.GET("/me/projects", handler.GetMyProjects)
+	e.GET("/workspaces/:workspace_id/projects", handler.GetWorkspaceProjects)
 	e.GET("/me/projects/stat", handler.GetProjectsStat)
+	e.GET("/me/projects/stat/export", handler.ExportProjectsStat)
 	e.GET("/me/projects/:id/stat", handler.GetProjectStat)
+	e.DELETE("/projects/:id", handler.DeleteProject)
+	e.POST("/projects/:id/restore", handler.RestoreProject)
 	e.DELETE("/me/clear_data", handler.ClearData)
 }
 
@@ -55,34 +63,36 @@ func RegisterHandlers(
 // @Produce  application/json
 // @Param    project body CreateProjectIn true "project info"
 // @Success  200 {object} CreateProjectOut "success create project"
-// @Failure 500 {object} echo.HTTPError "internal server error"
-// @Failure 400 {object} echo.HTTPError "bad request"
-// @Failure 422 {object} echo.HTTPError "unprocessable entity"
+// @Failure 500 {object} response.Error "internal server error"
+// @Failure 400 {object} response.Error "bad request"
+// @Failure 403 {object} response.Error "forbidden"
+// @Failure 422 {object} response.Error "unprocessable entity"
 // @Router   /projects/create [post]
 func (d *Delivery) CreateProject(c echo.Context) error {
-	ctx := context.Background()
+	ctx := c.Request().Context()
 
 	var in CreateProjectIn
 	err := c.Bind(&in)
 
 	if err != nil {
 		c.Logger().Errorf("bind request: %v", err)
-		return echo.NewHTTPError(http.StatusUnprocessableEntity, response.ErrorMsgsByCode[http.StatusUnprocessableEntity])
+		return response.Status(http.StatusUnprocessableEntity)
 	}
 
 	if ok, err := validator.IsRequestValid(&in); !ok {
 		c.Logger().Errorf("validation: %v", err)
-		return echo.NewHTTPError(http.StatusBadRequest, response.ErrorMsgsByCode[http.StatusBadRequest])
+		return response.ValidationError(err)
 	}
 
 	userID, ok := c.Get("user_id").(int64)
 	if !ok {
 		c.Logger().Error("can't parse context user_id")
-		return echo.NewHTTPError(http.StatusInternalServerError, response.ErrorMsgsByCode[http.StatusInternalServerError])
+		return response.Status(http.StatusInternalServerError)
 	}
 
 	project := usecaseDto.Project{
-		Name: in.Name,
+		Name:        in.Name,
+		WorkspaceID: in.WorkspaceID,
 	}
 
 	project.UserID = userID
@@ -99,24 +109,21 @@ func (d *Delivery) CreateProject(c echo.Context) error {
 
 // GetMyProjects godoc
 // @Summary      Получить список проектов.
-// @Description  Получить список проектов пользователя.
+// @Description  Получить список личных проектов пользователя и проектов его пространств.
 // @Tags     	 projects
 // @Accept	 	application/json
 // @Produce  	application/json
 // @Success  200 {object} []ProjectOut "success get projects"
-// @Failure 500 {object} echo.HTTPError "internal server error"
-// @Failure 400 {object} echo.HTTPError "bad request"
+// @Failure 500 {object} response.Error "internal server error"
+// @Failure 400 {object} response.Error "bad request"
 // @Router   /me/projects [get]
 func (d *Delivery) GetMyProjects(c echo.Context) error {
-	ctx := context.Background()
+	ctx := c.Request().Context()
 
 	userID, ok := c.Get("user_id").(int64)
 	if !ok {
 		c.Logger().Error("can't parse context user_id")
-		return echo.NewHTTPError(
-			http.StatusInternalServerError,
-			response.ErrorMsgsByCode[http.StatusInternalServerError],
-		)
+		return response.Status(http.StatusInternalServerError)
 	}
 
 	var projects []usecaseDto.Project
@@ -134,6 +141,44 @@ func (d *Delivery) GetMyProjects(c echo.Context) error {
 	return c.JSON(http.StatusOK, out)
 }
 
+// GetWorkspaceProjects godoc
+// @Summary      Получить список проектов пространства.
+// @Description  Получить список проектов пространства, доступно любому участнику.
+// @Tags     	 projects
+// @Accept	 	application/json
+// @Produce  	application/json
+// @Param    workspace_id path int true "Идентификатор пространства"
+// @Success  200 {object} []ProjectOut "success get projects"
+// @Failure 500 {object} response.Error "internal server error"
+// @Failure 400 {object} response.Error "bad request"
+// @Failure 403 {object} response.Error "forbidden"
+// @Router   /workspaces/{workspace_id}/projects [get]
+func (d *Delivery) GetWorkspaceProjects(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	workspaceID, err := strconv.ParseInt(c.Param("workspace_id"), 10, 64)
+	if err != nil {
+		c.Logger().Errorf("parse int: %v", err)
+		return response.InvalidParam("workspace_id", "int")
+	}
+
+	userID, ok := c.Get("user_id").(int64)
+	if !ok {
+		c.Logger().Error("can't parse context user_id")
+		return response.Status(http.StatusInternalServerError)
+	}
+
+	projects, err := d.usecase.GetWorkspaceProjects(ctx, workspaceID, userID)
+	if err != nil {
+		c.Logger().Errorf("usecase: %v", err)
+		return handleUsecaseError(err)
+	}
+
+	out := convertFromUsecaseProjects(projects)
+
+	return c.JSON(http.StatusOK, out)
+}
+
 // GetProjectsStat godoc
 // @Summary      Получить статистику по проектам.
 // @Description  Получить статистику по проектам.
@@ -143,18 +188,15 @@ func (d *Delivery) GetMyProjects(c echo.Context) error {
 // @Param        time_start    query     string  false  "RFC3339 format"
 // @Param        time_end    query     string  false  "RFC3339 format"
 // @Success  200 {object} ProjectsStatOut "success"
-// @Failure 500 {object} echo.HTTPError "internal server error"
+// @Failure 500 {object} response.Error "internal server error"
 // @Router   /me/projects/stat [get]
 func (d *Delivery) GetProjectsStat(c echo.Context) error {
-	ctx := context.Background()
+	ctx := c.Request().Context()
 
 	userID, ok := c.Get("user_id").(int64)
 	if !ok {
 		c.Logger().Error("can't parse context user_id")
-		return echo.NewHTTPError(
-			http.StatusInternalServerError,
-			response.ErrorMsgsByCode[http.StatusInternalServerError],
-		)
+		return response.Status(http.StatusInternalServerError)
 	}
 
 	timeStartStr := c.QueryParam("time_start")
@@ -185,6 +227,85 @@ func (d *Delivery) GetProjectsStat(c echo.Context) error {
 	return c.JSON(http.StatusOK, out)
 }
 
+// ExportProjectsStat godoc
+// @Summary      Выгрузить статистику по проектам.
+// @Description  Выгрузить статистику по проектам в CSV или XLSX. Числа форматируются по локали: из параметра locale или заголовка Accept-Language.
+// @Tags     	 projects
+// @Produce  	text/csv
+// @Produce  	application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param    format query string true "Формат файла" Enums(csv, xlsx)
+// @Param        time_start    query     string  false  "RFC3339 format"
+// @Param        time_end    query     string  false  "RFC3339 format"
+// @Param    locale query string false "Локаль форматирования" Enums(ru, en)
+// @Success  200 {file} file "success export stat"
+// @Failure 500 {object} response.Error "internal server error"
+// @Failure 400 {object} response.Error "bad request"
+// @Router   /me/projects/stat/export [get]
+func (d *Delivery) ExportProjectsStat(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, ok := c.Get("user_id").(int64)
+	if !ok {
+		c.Logger().Error("can't parse context user_id")
+		return response.Status(http.StatusInternalServerError)
+	}
+
+	timeStart := time.Time{}
+	timeEnd := time.Now()
+
+	if timeStartStr := c.QueryParam("time_start"); timeStartStr != "" {
+		// Намеренный скип ошибки, как в GetProjectsStat.
+		timeStart, _ = time.Parse(time.RFC3339, timeStartStr)
+	}
+
+	if timeEndStr := c.QueryParam("time_end"); timeEndStr != "" {
+		// Намеренный скип ошибки, как в GetProjectsStat.
+		timeEnd, _ = time.Parse(time.RFC3339, timeEndStr)
+	}
+
+	locale, err := export.NegotiateLocale(c.QueryParam("locale"), c.Request().Header.Get("Accept-Language"))
+	if err != nil {
+		c.Logger().Errorf("negotiate locale: %v", err)
+		return response.InvalidParam("locale", "oneof")
+	}
+
+	format := c.QueryParam("format")
+	writer, err := export.NewWriter(c.Response(), format, locale)
+	if err != nil {
+		c.Logger().Errorf("new export writer: %v", err)
+		return response.InvalidParam("format", "oneof")
+	}
+
+	// Агрегат небольшой, поэтому считаем его до отправки заголовков и можем вернуть ошибку статусом.
+	projectsStat, err := d.usecase.ProjectsStats(ctx, userID, timeStart, timeEnd)
+	if err != nil {
+		c.Logger().Errorf("usecase: %v", err)
+		return handleUsecaseError(err)
+	}
+
+	c.Response().Header().Set(echo.HeaderContentType, export.ContentType(format))
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="projects_stat.%s"`, format))
+	c.Response().WriteHeader(http.StatusOK)
+
+	err = writer.WriteRow("project", "duration_hours", "percent_duration")
+	for i := 0; err == nil && i < len(projectsStat.ProjectsStat); i++ {
+		stat := projectsStat.ProjectsStat[i]
+		err = writer.WriteRow(
+			stat.ProjectName,
+			stat.ProjectDurationInSec/time.Hour.Seconds(),
+			stat.ProjectDurationPercent,
+		)
+	}
+	if err == nil {
+		err = writer.Close()
+	}
+	if err != nil {
+		c.Logger().Errorf("export projects stat: %v", err)
+	}
+
+	return nil
+}
+
 // GetProjectStat godoc
 // @Summary      Получить статистику по конкретному проекту.
 // @Description  Получить статистику по конкретному проекту.
@@ -195,29 +316,23 @@ func (d *Delivery) GetProjectsStat(c echo.Context) error {
 // @Param        time_start    query     string  false  "RFC3339 format"
 // @Param        time_end    query     string  false  "RFC3339 format"
 // @Success  200 {object}  ProjectEntriesStatOut "success"
-// @Failure 500 {object} echo.HTTPError "internal server error"
-// @Failure 400 {object} echo.HTTPError "bad request"
+// @Failure 500 {object} response.Error "internal server error"
+// @Failure 400 {object} response.Error "bad request"
 // @Router   /me/projects/{id}/stat [get]
 func (d *Delivery) GetProjectStat(c echo.Context) error {
-	ctx := context.Background()
+	ctx := c.Request().Context()
 
 	userID, ok := c.Get("user_id").(int64)
 	if !ok {
 		c.Logger().Error("can't parse context user_id")
-		return echo.NewHTTPError(
-			http.StatusInternalServerError,
-			response.ErrorMsgsByCode[http.StatusInternalServerError],
-		)
+		return response.Status(http.StatusInternalServerError)
 	}
 
 	projectID, err := strconv.ParseInt(c.Param("id"), 10, 64)
 
 	if err != nil {
 		c.Logger().Errorf("parse int: %v", err)
-		return echo.NewHTTPError(
-			http.StatusBadRequest,
-			response.ErrorMsgsByCode[http.StatusBadRequest],
-		)
+		return response.InvalidParam("id", "int")
 	}
 
 	timeStartStr := c.QueryParam("time_start")
@@ -248,25 +363,107 @@ func (d *Delivery) GetProjectStat(c echo.Context) error {
 	return c.JSON(http.StatusOK, out)
 }
 
+// DeleteProject godoc
+// @Summary      Удалить проект.
+// @Description  Перенести проект в корзину вместе со всеми его записями и целями. Проект пространства могут удалить admin и owner.
+// @Tags     	 projects
+// @Accept	 application/json
+// @Produce  application/json
+// @Param    id path int true "Идентификатор проекта"
+// @Param    lock_override_reason query string false "Причина удаления записей в заблокированном периоде (только admin и owner)"
+// @Success  200  "success delete project"
+// @Failure 500 {object} response.Error "internal server error"
+// @Failure 400 {object} response.Error "bad request"
+// @Failure 403 {object} response.Error "forbidden"
+// @Failure 404 {object} response.Error "item is not found"
+// @Failure 409 {object} response.Error "conflict"
+// @Failure 423 {object} response.Error "locked"
+// @Router   /projects/{id} [delete]
+func (d *Delivery) DeleteProject(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	projectID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Logger().Errorf("parse int: %v", err)
+		return response.InvalidParam("id", "int")
+	}
+
+	userID, ok := c.Get("user_id").(int64)
+	if !ok {
+		c.Logger().Error("can't parse context user_id")
+		return response.Status(http.StatusInternalServerError)
+	}
+
+	opts := usecaseDto.DeleteOptions{LockOverrideReason: c.QueryParam("lock_override_reason")}
+	err = d.usecase.DeleteProject(ctx, projectID, userID, opts)
+	if err != nil {
+		c.Logger().Errorf("usecase: %v", err)
+		return handleUsecaseError(err)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+// RestoreProject godoc
+// @Summary      Восстановить проект.
+// @Description  Вернуть проект из корзины вместе с записями и целями, удаленными вместе с ним.
+// @Tags     	 trash
+// @Accept	 application/json
+// @Produce  application/json
+// @Param    id path int true "Идентификатор проекта"
+// @Success  200  "success restore project"
+// @Failure 500 {object} response.Error "internal server error"
+// @Failure 400 {object} response.Error "bad request"
+// @Failure 403 {object} response.Error "forbidden"
+// @Failure 404 {object} response.Error "item is not found"
+// @Router   /projects/{id}/restore [post]
+func (d *Delivery) RestoreProject(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	projectID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Logger().Errorf("parse int: %v", err)
+		return response.InvalidParam("id", "int")
+	}
+
+	userID, ok := c.Get("user_id").(int64)
+	if !ok {
+		c.Logger().Error("can't parse context user_id")
+		return response.Status(http.StatusInternalServerError)
+	}
+
+	err = d.usecase.RestoreProject(ctx, projectID, userID)
+	if err != nil {
+		c.Logger().Errorf("usecase: %v", err)
+		return handleUsecaseError(err)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
 // ClearData godoc
 // @Summary      Очистить все пользовательские данные.
-// @Description  Очистить все пользовательские данные.
+// @Description  Перенести в корзину все записи, цели и личные проекты пользователя. Перед очисткой данные можно сохранить через GET /me/export.
 // @Tags     	 user
 // @Accept	 application/json
 // @Produce  application/json
+// @Param    lock_override_reason query string false "Причина удаления записей в заблокированном периоде (только admin и owner)"
 // @Success  200  "success clear user data"
-// @Failure 500 {object} echo.HTTPError "internal server error"
+// @Failure 500 {object} response.Error "internal server error"
+// @Failure 409 {object} response.Error "conflict"
+// @Failure 423 {object} response.Error "locked"
 // @Router   /me/clear_data [delete]
 func (d *Delivery) ClearData(c echo.Context) error {
-	ctx := context.Background()
+	ctx := c.Request().Context()
 
 	userID, ok := c.Get("user_id").(int64)
 	if !ok {
 		c.Logger().Error("can't parse context user_id")
-		return echo.NewHTTPError(http.StatusInternalServerError, response.ErrorMsgsByCode[http.StatusInternalServerError])
+		return response.Status(http.StatusInternalServerError)
 	}
 
-	err := d.usecase.ClearUserData(ctx, userID)
+	opts := usecaseDto.DeleteOptions{LockOverrideReason: c.QueryParam("lock_override_reason")}
+	err := d.usecase.ClearUserData(ctx, userID, opts)
 	if err != nil {
 		c.Logger().Errorf("usecase: %v", err)
 		return handleUsecaseError(err)
@@ -275,22 +472,39 @@ func (d *Delivery) ClearData(c echo.Context) error {
 	return c.NoContent(http.StatusOK)
 }
 
-func handleUsecaseError(err error) *echo.HTTPError {
+func handleUsecaseError(err error) *response.Error {
+	// Запрос не уложился в таймаут или был отменен.
+	if problem := response.ContextError(err); problem != nil {
+		return problem
+	}
+
 	// Не нашли проект.
 	if errors.Is(err, usecaseDto.ErrProjectNotFound) {
-		return echo.NewHTTPError(
-			http.StatusNotFound,
-			fmt.Sprintf("%s: %s", response.ErrorMsgsByCode[http.StatusNotFound], "project"))
+		return response.NotFound("project")
 	}
 	if errors.Is(err, usecaseDto.ErrProjectExists) {
-		return echo.NewHTTPError(http.StatusBadRequest, response.ErrorMsgsByCode[http.StatusBadRequest])
+		return response.NewError(http.StatusBadRequest, "project_exists", response.ErrorMsgsByCode[http.StatusBadRequest])
+	}
+	if errors.Is(err, usecaseDto.ErrForbidden) {
+		return response.Status(http.StatusForbidden)
+	}
+	// Среди удаляемых записей есть записи в утвержденных неделях.
+	if errors.Is(err, usecaseDto.ErrEntryReadOnly) {
+		return response.NewError(
+			http.StatusConflict,
+			"entry_read_only",
+			fmt.Sprintf("%s: %v", response.ErrorMsgsByCode[http.StatusConflict], err))
+	}
+	// Среди удаляемых записей есть записи в заблокированном периоде.
+	if errors.Is(err, usecaseDto.ErrPeriodLocked) {
+		return response.NewError(
+			http.StatusLocked,
+			"period_locked",
+			fmt.Sprintf("%s: %v", response.ErrorMsgsByCode[http.StatusLocked], err))
 	}
 
 	// По дефолту пятисотим.
-	return echo.NewHTTPError(
-		http.StatusInternalServerError,
-		response.ErrorMsgsByCode[http.StatusInternalServerError],
-	)
+	return response.Status(http.StatusInternalServerError)
 }
 
 func convertFromUsecaseProjects(projects []usecaseDto.Project) []ProjectOut {
@@ -337,7 +551,9 @@ func convertFromUsecaseProjectEntriesStat(stat usecaseDto.AllProjectEntriesStat)
 
 func convertFromUsecaseProject(project usecaseDto.Project) ProjectOut {
 	return ProjectOut{
-		ID:   project.ID,
-		Name: project.Name,
+		ID:          project.ID,
+		Name:        project.Name,
+		WorkspaceID: project.WorkspaceID,
+		Client:      project.Client,
 	}
 }
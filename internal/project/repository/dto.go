@@ -1,7 +1,37 @@
 package repository
 
+import "database/sql"
+
 type Project struct {
-	ID     int64  `db:"id"`
-	Name   string `db:"name"`
-	UserID int64  `db:"user_id"`
+	ID          int64          `db:"id"`
+	Name        string         `db:"name"`
+	UserID      int64          `db:"user_id"`
+	WorkspaceID sql.NullInt64  `db:"workspace_id"`
+	Client      sql.NullString `db:"client"`
+}
+
+// ProjectAccess информация для проверки прав пользователя на проект.
+type ProjectAccess struct {
+	ProjectID   int64          `db:"id"`
+	OwnerID     int64          `db:"user_id"`
+	WorkspaceID sql.NullInt64  `db:"workspace_id"`
+	MemberRole  sql.NullString `db:"role"`
+}
+
+// EntryFilter выбирает живые записи проекта или пользователя. Нулевое поле не ограничивает выборку.
+type EntryFilter struct {
+	ProjectID int64
+	UserID    int64
+}
+
+// ProtectedEntry запись в проекте пространства, которую нельзя удалить вместе с проектом
+// или данными пользователя без проверок.
+type ProtectedEntry struct {
+	ID          int64 `db:"id"`
+	UserID      int64 `db:"user_id"`
+	WorkspaceID int64 `db:"workspace_id"`
+	// Одна из недель записи утверждена в табеле.
+	Approved bool `db:"approved"`
+	// Запись начинается раньше границы блокировки пространства.
+	Locked bool `db:"locked"`
 }
@@ -7,6 +7,8 @@ import (
 	"fmt"
 
 	"github.com/jmoiron/sqlx"
+
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/transaction"
 )
 
 var (
@@ -27,35 +29,47 @@ func NewRepository(db *sqlx.DB) *Repository {
 	}
 }
 
+// Close закрывает пул соединений с базой.
+func (r *Repository) Close() error {
+	return r.close()
+}
+
 func (r *Repository) CreateProject(ctx context.Context, project Project) (int64, error) {
 	query := `INSERT INTO projects
 				(
 					user_id,
+					workspace_id,
 					name
-				) VALUES ($1, $2) RETURNING id;`
+				) VALUES ($1, $2, $3) RETURNING id;`
 
 	var id int64
-	err := r.db.QueryRow(
+	err := transaction.GetExecutor(ctx, r.db).QueryRowContext(ctx,
 		query,
 		project.UserID,
+		project.WorkspaceID,
 		project.Name,
 	).Scan(&id)
 
 	if err != nil {
-		return 0, fmt.Errorf("query row: %v", err)
+		return 0, fmt.Errorf("query row: %w", err)
 	}
 
 	return id, nil
 }
 
+// GetUserProjects возвращает личные проекты пользователя и проекты пространств, в которых он состоит.
 func (r *Repository) GetUserProjects(ctx context.Context, userID int64) ([]Project, error) {
-	rows, err := r.db.Query(
-		`SELECT 
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT
 			id,
 			user_id,
-			name
+			workspace_id,
+			name,
+			client
 		FROM projects
-		WHERE user_id = $1`, userID)
+		WHERE deleted_at IS NULL
+		  AND ((workspace_id IS NULL AND user_id = $1)
+		   OR workspace_id IN (SELECT workspace_id FROM workspace_members WHERE user_id = $1))`, userID)
 
 	if err != nil {
 		return nil, fmt.Errorf("query: %w", err)
@@ -65,54 +79,250 @@ func (r *Repository) GetUserProjects(ctx context.Context, userID int64) ([]Proje
 		_ = rows.Close()
 	}()
 
-	var projects []Project
+	return scanProjects(rows)
+}
+
+func (r *Repository) GetWorkspaceProjects(ctx context.Context, workspaceID int64) ([]Project, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT
+			id,
+			user_id,
+			workspace_id,
+			name,
+			client
+		FROM projects
+		WHERE workspace_id = $1 AND deleted_at IS NULL`, workspaceID)
+
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	return scanProjects(rows)
+}
+
+// GetProtectedEntries возвращает живые записи проектов пространств, попадающие в недели
+// с утвержденным табелем или в заблокированный период. Неделя считается так же,
+// как utils.GetWeekStart: с понедельника.
+func (r *Repository) GetProtectedEntries(ctx context.Context, filter EntryFilter) ([]ProtectedEntry, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT * FROM (
+			SELECT
+				e.id,
+				e.user_id,
+				p.workspace_id,
+				EXISTS(
+					SELECT 1
+					FROM timesheets t
+					WHERE t.user_id = e.user_id
+					  AND t.workspace_id = p.workspace_id
+					  AND t.state = 'approved'
+					  AND t.week_start BETWEEN date_trunc('week', e.time_start)::date
+					                       AND date_trunc('week', e.time_end)::date
+				) AS approved,
+				COALESCE(e.time_start < l.locked_before, false) AS locked
+			FROM entries e
+			JOIN projects p ON p.id = e.project_id
+			LEFT JOIN period_locks l ON l.workspace_id = p.workspace_id
+			WHERE p.workspace_id IS NOT NULL
+			  AND e.deleted_at IS NULL
+			  AND ($1::int = 0 OR e.project_id = $1)
+			  AND ($2::int = 0 OR e.user_id = $2)
+		) entries
+		WHERE approved OR locked
+		ORDER BY id`, filter.ProjectID, filter.UserID)
+
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var entries []ProtectedEntry
 	for rows.Next() {
-		var project Project
-		if err = rows.Scan(
-			&project.ID,
-			&project.UserID,
-			&project.Name,
-		); err != nil {
-			return nil, fmt.Errorf("scan: %w", rows.Err())
+		var entry ProtectedEntry
+		if err = rows.Scan(&entry.ID, &entry.UserID, &entry.WorkspaceID, &entry.Approved, &entry.Locked); err != nil {
+			return nil, fmt.Errorf("scan: %w", err)
 		}
+		entries = append(entries, entry)
+	}
 
-		projects = append(projects, project)
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows: %w", err)
 	}
 
-	if rows.Err() != nil {
-		return nil, fmt.Errorf("rows err: %w", rows.Err())
+	return entries, nil
+}
+
+// ClearUserData переносит в корзину записи, цели и личные проекты пользователя.
+// Проекты пространств принадлежат пространству и не удаляются.
+// Все строки получают один deleted_at (время начала транзакции), поэтому восстановление
+// личного проекта вернет и его записи с целями.
+func (r *Repository) ClearUserData(ctx context.Context, userID int64) error {
+	tx, err := transaction.Begin(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
 	}
 
-	if len(projects) == 0 {
-		return nil, ErrProjectNotFound
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	queries := []string{
+		`UPDATE entries SET deleted_at = now() WHERE user_id = $1 AND deleted_at IS NULL;`,
+		`UPDATE goals SET deleted_at = now() WHERE user_id = $1 AND deleted_at IS NULL;`,
+		`UPDATE projects SET deleted_at = now(), deleted_by = $1
+		WHERE user_id = $1 AND workspace_id IS NULL AND deleted_at IS NULL;`,
 	}
 
-	return projects, nil
+	for _, query := range queries {
+		if _, err = tx.ExecContext(ctx, query, userID); err != nil {
+			return fmt.Errorf("exec context: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+
+	return nil
 }
 
-func (r *Repository) ClearUserData(ctx context.Context, userID int64) error {
-	query := `
-				DELETE FROM entries;
-				DELETE FROM goals;
-				DELETE FROM projects;`
+// DeleteProject переносит в корзину проект вместе со всеми его записями и целями.
+func (r *Repository) DeleteProject(ctx context.Context, projectID, deletedBy int64) error {
+	tx, err := transaction.Begin(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	res, err := tx.ExecContext(ctx,
+		`UPDATE projects
+		SET deleted_at = now(),
+			deleted_by = $2
+		WHERE id = $1 AND deleted_at IS NULL`, projectID, deletedBy)
+	if err != nil {
+		return fmt.Errorf("exec context: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("rows affected: %w", err)
+	}
+
+	if affected == 0 {
+		return ErrProjectNotFound
+	}
+
+	queries := []string{
+		`UPDATE entries SET deleted_at = now() WHERE project_id = $1 AND deleted_at IS NULL;`,
+		`UPDATE goals SET deleted_at = now() WHERE project_id = $1 AND deleted_at IS NULL;`,
+	}
+
+	for _, query := range queries {
+		if _, err = tx.ExecContext(ctx, query, projectID); err != nil {
+			return fmt.Errorf("exec context: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+
+	return nil
+}
+
+// RestoreProject возвращает проект из корзины вместе с записями и целями,
+// удаленными вместе с ним. Удаленные раньше проекта записи и цели остаются в корзине.
+func (r *Repository) RestoreProject(ctx context.Context, projectID int64) error {
+	tx, err := transaction.Begin(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
 
-	_, err := r.db.ExecContext(ctx, query)
+	var id int64
+	err = tx.QueryRowContext(ctx,
+		`SELECT id FROM projects WHERE id = $1 AND deleted_at IS NOT NULL FOR UPDATE`, projectID).
+		Scan(&id)
 	if err != nil {
-		return fmt.Errorf("exec context: %v", err)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrProjectNotFound
+		}
+
+		return fmt.Errorf("scan: %w", err)
+	}
+
+	// Проект восстанавливается последним: до этого по его deleted_at отбираются записи и цели.
+	queries := []string{
+		`UPDATE entries SET deleted_at = NULL, updated_at = now()
+		WHERE project_id = $1 AND deleted_at = (SELECT deleted_at FROM projects WHERE id = $1);`,
+		`UPDATE goals SET deleted_at = NULL
+		WHERE project_id = $1 AND deleted_at = (SELECT deleted_at FROM projects WHERE id = $1);`,
+		`UPDATE projects SET deleted_at = NULL, deleted_by = NULL WHERE id = $1;`,
+	}
+
+	for _, query := range queries {
+		if _, err = tx.ExecContext(ctx, query, projectID); err != nil {
+			return fmt.Errorf("exec context: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
 	}
 
 	return nil
 }
 
+// GetProject возвращает проект по идентификатору.
+func (r *Repository) GetProject(ctx context.Context, projectID int64) (Project, error) {
+	return r.getProject(ctx, `SELECT
+			id,
+			user_id,
+			workspace_id,
+			name,
+			client
+		FROM projects
+		WHERE id = $1 AND deleted_at IS NULL`, projectID)
+}
+
+// GetDeletedProject возвращает проект из корзины.
+func (r *Repository) GetDeletedProject(ctx context.Context, projectID int64) (Project, error) {
+	return r.getProject(ctx, `SELECT
+			id,
+			user_id,
+			workspace_id,
+			name,
+			client
+		FROM projects
+		WHERE id = $1 AND deleted_at IS NOT NULL`, projectID)
+}
+
+// GetProjectByName ищет личный проект пользователя по названию.
 func (r *Repository) GetProjectByName(ctx context.Context, userID int64, projectName string) (Project, error) {
 	var project Project
-	err := r.db.QueryRow(
-		`SELECT 
+	err := r.db.QueryRowContext(ctx,
+		`SELECT
 			id,
 			user_id,
-			name
+			workspace_id,
+			name,
+			client
 		FROM projects
-		WHERE user_id = $1 AND name = $2 LIMIT 1`, userID, projectName).Scan(&project.ID, &project.UserID, &project.Name)
+		WHERE user_id = $1 AND workspace_id IS NULL AND name = $2 AND deleted_at IS NULL LIMIT 1`, userID, projectName).
+		Scan(&project.ID, &project.UserID, &project.WorkspaceID, &project.Name, &project.Client)
 
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -124,3 +334,96 @@ func (r *Repository) GetProjectByName(ctx context.Context, userID int64, project
 
 	return project, nil
 }
+
+func (r *Repository) GetWorkspaceProjectByName(ctx context.Context, workspaceID int64, projectName string) (Project, error) {
+	var project Project
+	err := r.db.QueryRowContext(ctx,
+		`SELECT
+			id,
+			user_id,
+			workspace_id,
+			name,
+			client
+		FROM projects
+		WHERE workspace_id = $1 AND name = $2 AND deleted_at IS NULL LIMIT 1`, workspaceID, projectName).
+		Scan(&project.ID, &project.UserID, &project.WorkspaceID, &project.Name, &project.Client)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Project{}, ErrProjectNotFound
+		}
+
+		return Project{}, fmt.Errorf("scan: %w", err)
+	}
+
+	return project, nil
+}
+
+// GetProjectAccess возвращает владельца проекта и роль пользователя в пространстве проекта.
+func (r *Repository) GetProjectAccess(ctx context.Context, projectID, userID int64) (ProjectAccess, error) {
+	var access ProjectAccess
+	err := r.db.QueryRowContext(ctx,
+		`SELECT
+			p.id,
+			p.user_id,
+			p.workspace_id,
+			wm.role
+		FROM projects p
+		LEFT JOIN workspace_members wm ON wm.workspace_id = p.workspace_id AND wm.user_id = $2
+		WHERE p.id = $1 AND p.deleted_at IS NULL`, projectID, userID).
+		Scan(&access.ProjectID, &access.OwnerID, &access.WorkspaceID, &access.MemberRole)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ProjectAccess{}, ErrProjectNotFound
+		}
+
+		return ProjectAccess{}, fmt.Errorf("scan: %w", err)
+	}
+
+	return access, nil
+}
+
+func (r *Repository) getProject(ctx context.Context, query string, projectID int64) (Project, error) {
+	var project Project
+	err := r.db.QueryRowContext(ctx, query, projectID).
+		Scan(&project.ID, &project.UserID, &project.WorkspaceID, &project.Name, &project.Client)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Project{}, ErrProjectNotFound
+		}
+
+		return Project{}, fmt.Errorf("scan: %w", err)
+	}
+
+	return project, nil
+}
+
+func scanProjects(rows *sql.Rows) ([]Project, error) {
+	var projects []Project
+	for rows.Next() {
+		var project Project
+		if err := rows.Scan(
+			&project.ID,
+			&project.UserID,
+			&project.WorkspaceID,
+			&project.Name,
+			&project.Client,
+		); err != nil {
+			return nil, fmt.Errorf("scan: %w", err)
+		}
+
+		projects = append(projects, project)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("rows err: %w", rows.Err())
+	}
+
+	if len(projects) == 0 {
+		return nil, ErrProjectNotFound
+	}
+
+	return projects, nil
+}
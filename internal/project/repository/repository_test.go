@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/testdb"
+)
+
+// fixture данные одного пользователя в тестовой базе.
+type fixture struct {
+	t      *testing.T
+	client *sqlx.DB
+	userID int64
+}
+
+func newFixture(t *testing.T) (*Repository, *fixture) {
+	t.Helper()
+
+	client := testdb.Open(t)
+	f := &fixture{t: t, client: client}
+
+	// У каждого теста свой пользователь, данные других тестов ему не видны.
+	email := fmt.Sprintf("%s-%d@test", t.Name(), time.Now().UnixNano())
+	f.userID = f.insert(`INSERT INTO users (name, email, password) VALUES ('test', $1, '') RETURNING id`, email)
+	t.Cleanup(func() {
+		testdb.Exec(t, client, `DELETE FROM users WHERE id = $1`, f.userID)
+	})
+
+	return NewRepository(client), f
+}
+
+func (f *fixture) insert(query string, args ...interface{}) int64 {
+	f.t.Helper()
+
+	var id int64
+	if err := f.client.Get(&id, query, args...); err != nil {
+		f.t.Fatalf("insert %q: %v", query, err)
+	}
+
+	return id
+}
+
+func (f *fixture) project(name string) int64 {
+	return f.insert(`INSERT INTO projects (user_id, name) VALUES ($1, $2) RETURNING id`, f.userID, name)
+}
+
+func (f *fixture) entry(projectID int64) int64 {
+	start := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	return f.insert(`INSERT INTO entries (user_id, project_id, time_start, time_end) VALUES ($1, $2, $3, $4) RETURNING id`,
+		f.userID, projectID, start, start.Add(time.Hour))
+}
+
+func (f *fixture) goal(projectID int64) int64 {
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	return f.insert(`INSERT INTO goals (user_id, project_id, name, time_seconds, date_start, date_end)
+		VALUES ($1, $2, 'goal', 3600, $3, $4) RETURNING id`, f.userID, projectID, start, start.AddDate(0, 1, 0))
+}
+
+// deleteEarlier переносит строку в корзину за час до остальных, как отдельное удаление.
+func (f *fixture) deleteEarlier(table string, id int64) {
+	testdb.Exec(f.t, f.client, `UPDATE `+table+` SET deleted_at = now() - interval '1 hour' WHERE id = $1`, id)
+}
+
+// checkDeleted проверяет, какие строки таблицы лежат в корзине.
+func (f *fixture) checkDeleted(table string, want map[int64]bool) {
+	f.t.Helper()
+
+	for id, wantDeleted := range want {
+		var deleted bool
+		if err := f.client.Get(&deleted, `SELECT deleted_at IS NOT NULL FROM `+table+` WHERE id = $1`, id); err != nil {
+			f.t.Fatalf("%s %d: %v", table, id, err)
+		}
+		if deleted != wantDeleted {
+			f.t.Errorf("%s %d: deleted %v, want %v", table, id, deleted, wantDeleted)
+		}
+	}
+}
+
+func TestRestoreProjectRestoresRowsDeletedWithIt(t *testing.T) {
+	r, f := newFixture(t)
+	ctx := context.Background()
+
+	projectID := f.project("Website")
+	entries := []int64{f.entry(projectID), f.entry(projectID), f.entry(projectID)}
+	goals := []int64{f.goal(projectID), f.goal(projectID)}
+
+	// Запись и цель удалены раньше проекта.
+	f.deleteEarlier("entries", entries[2])
+	f.deleteEarlier("goals", goals[1])
+
+	if err := r.DeleteProject(ctx, projectID, f.userID); err != nil {
+		t.Fatalf("delete project: %v", err)
+	}
+	f.checkDeleted("projects", map[int64]bool{projectID: true})
+	f.checkDeleted("entries", map[int64]bool{entries[0]: true, entries[1]: true, entries[2]: true})
+	f.checkDeleted("goals", map[int64]bool{goals[0]: true, goals[1]: true})
+
+	if err := r.RestoreProject(ctx, projectID); err != nil {
+		t.Fatalf("restore project: %v", err)
+	}
+
+	// Возвращается только то, что удалено вместе с проектом: deleted_at совпадает с проектом.
+	f.checkDeleted("projects", map[int64]bool{projectID: false})
+	f.checkDeleted("entries", map[int64]bool{entries[0]: false, entries[1]: false, entries[2]: true})
+	f.checkDeleted("goals", map[int64]bool{goals[0]: false, goals[1]: true})
+
+	if err := r.RestoreProject(ctx, projectID); !errors.Is(err, ErrProjectNotFound) {
+		t.Errorf("restore of a project not in trash: error %v, want %v", err, ErrProjectNotFound)
+	}
+}
+
+func TestRestoreProjectAfterClearUserData(t *testing.T) {
+	r, f := newFixture(t)
+	ctx := context.Background()
+
+	website, research := f.project("Website"), f.project("Research")
+	websiteEntry, researchEntry := f.entry(website), f.entry(research)
+	websiteGoal := f.goal(website)
+
+	if err := r.ClearUserData(ctx, f.userID); err != nil {
+		t.Fatalf("clear user data: %v", err)
+	}
+	f.checkDeleted("projects", map[int64]bool{website: true, research: true})
+	f.checkDeleted("entries", map[int64]bool{websiteEntry: true, researchEntry: true})
+
+	// Все строки очистки получили один deleted_at, но восстанавливаются только строки проекта.
+	if err := r.RestoreProject(ctx, website); err != nil {
+		t.Fatalf("restore project: %v", err)
+	}
+
+	f.checkDeleted("projects", map[int64]bool{website: false, research: true})
+	f.checkDeleted("entries", map[int64]bool{websiteEntry: false, researchEntry: true})
+	f.checkDeleted("goals", map[int64]bool{websiteGoal: false})
+}
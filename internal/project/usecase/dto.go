@@ -4,6 +4,18 @@ type Project struct {
 	ID     int64
 	Name   string
 	UserID int64
+
+	// Идентификатор пространства, 0 для личного проекта.
+	WorkspaceID int64
+	// Клиент, заполняется при импорте из других трекеров.
+	Client string
+}
+
+// DeleteOptions параметры удаления проекта и очистки данных пользователя.
+type DeleteOptions struct {
+	// Причина удаления записей в заблокированном периоде. Учитывается только
+	// для admin и owner пространства, каждая такая запись попадает в журнал.
+	LockOverrideReason string
 }
 
 type ProjectStatInfo struct {
@@ -2,85 +2,191 @@ package usecase
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"time"
 
+	auditUC "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/audit/usecase"
 	entryRepoDto "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/entry/repository"
+	periodLockRepoDto "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/periodlock/repository"
 	repo "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/project/repository"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/roles"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/statscache"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/tracing"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/utils"
+	workspaceRepoDto "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/workspace/repository"
 )
 
 var (
 	ErrProjectNotFound = errors.New("project not found")
 	ErrProjectExists   = errors.New("project with that name already exists")
+	ErrForbidden       = errors.New("forbidden")
+	ErrEntryReadOnly   = errors.New("entries are read-only: timesheet for the week is approved")
+	ErrPeriodLocked    = errors.New("entries are in a locked period")
 )
 
+// Действие с записью для журнала изменений в заблокированном периоде, как при удалении записи.
+const lockOverrideAction = "delete"
+
 type repository interface {
 	CreateProject(ctx context.Context, project repo.Project) (int64, error)
 	GetUserProjects(ctx context.Context, userID int64) ([]repo.Project, error)
 	ClearUserData(ctx context.Context, userID int64) error
 	GetProjectByName(ctx context.Context, userID int64, projectName string) (repo.Project, error)
+	GetWorkspaceProjects(ctx context.Context, workspaceID int64) ([]repo.Project, error)
+	GetWorkspaceProjectByName(ctx context.Context, workspaceID int64, projectName string) (repo.Project, error)
+	GetProject(ctx context.Context, projectID int64) (repo.Project, error)
+	GetDeletedProject(ctx context.Context, projectID int64) (repo.Project, error)
+	DeleteProject(ctx context.Context, projectID, deletedBy int64) error
+	RestoreProject(ctx context.Context, projectID int64) error
+	GetProtectedEntries(ctx context.Context, filter repo.EntryFilter) ([]repo.ProtectedEntry, error)
+}
+
+type workspaceRepository interface {
+	GetMemberRole(ctx context.Context, workspaceID, userID int64) (string, error)
+	GetMembers(ctx context.Context, workspaceID int64) ([]workspaceRepoDto.Member, error)
 }
 
 type entryRepository interface {
 	GetProjectEntriesForInterval(
-		_ context.Context,
+		ctx context.Context,
 		userID int64,
 		projectID int64,
 		start time.Time,
 		end time.Time) ([]entryRepoDto.Entry, error)
 	GetProjectEntries(
-		_ context.Context,
+		ctx context.Context,
 		userID int64,
 		projectID int64,
 	) ([]entryRepoDto.Entry, error)
 }
 
+type periodLockRepository interface {
+	CreateOverride(ctx context.Context, override periodLockRepoDto.Override) error
+}
+
+type txManager interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type auditLogger interface {
+	Record(ctx context.Context, event auditUC.Event) error
+}
+
+type statsCache interface {
+	Fetch(ctx context.Context, key statscache.Key, dst interface{}, load func(ctx context.Context) error) error
+	InvalidateProject(ctx context.Context, userID, projectID int64)
+	InvalidateUser(ctx context.Context, userID int64)
+}
+
 type Usecase struct {
-	repository      repository
-	entryRepository entryRepository
+	repository           repository
+	entryRepository      entryRepository
+	workspaceRepository  workspaceRepository
+	periodLockRepository periodLockRepository
+	auditLogger          auditLogger
+	txManager            txManager
+	statsCache           statsCache
 }
 
-func NewUsecase(repository repository, entryRepository entryRepository) *Usecase {
+func NewUsecase(
+	repository repository,
+	entryRepository entryRepository,
+	workspaceRepository workspaceRepository,
+	periodLockRepository periodLockRepository,
+	auditLogger auditLogger,
+	txManager txManager,
+	statsCache statsCache,
+) *Usecase {
 	return &Usecase{
-		repository:      repository,
-		entryRepository: entryRepository,
+		repository:           repository,
+		entryRepository:      entryRepository,
+		workspaceRepository:  workspaceRepository,
+		periodLockRepository: periodLockRepository,
+		auditLogger:          auditLogger,
+		txManager:            txManager,
+		statsCache:           statsCache,
 	}
 }
 
+// CreateProject создает личный проект или, если указан WorkspaceID, проект пространства.
+// Проекты в пространстве могут создавать только admin и owner.
 func (u *Usecase) CreateProject(ctx context.Context, project Project) (int64, error) {
-	oldProject, err := u.repository.GetProjectByName(ctx, project.UserID, project.Name)
-	if err != nil && !errors.Is(err, repo.ErrProjectNotFound) {
-		return 0, fmt.Errorf("repo get project by name: %v", err)
+	ctx, span := tracing.Start(ctx, "project.CreateProject")
+	defer span.End()
+
+	if project.WorkspaceID != 0 {
+		if err := u.requireWorkspaceRole(ctx, project.WorkspaceID, project.UserID, roles.Admin); err != nil {
+			return 0, err
+		}
 	}
-	if oldProject.ID != 0 {
-		return 0, ErrProjectExists
+
+	if err := u.checkNameFree(ctx, project); err != nil {
+		return 0, err
+	}
+
+	viewers, err := u.viewers(ctx, project)
+	if err != nil {
+		return 0, err
 	}
 
-	id, err := u.repository.CreateProject(ctx, convertToRepoProject(project))
+	// Проект, журнал и outbox сохраняются атомарно.
+	err = u.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		id, err := u.repository.CreateProject(ctx, convertToRepoProject(project))
+		if err != nil {
+			return fmt.Errorf("repo create project: %w", err)
+		}
+		project.ID = id
+
+		err = u.auditLogger.Record(ctx, auditUC.Event{
+			ActorID:     project.UserID,
+			WorkspaceID: project.WorkspaceID,
+			EntityType:  auditUC.EntityProject,
+			EntityID:    id,
+			Action:      auditUC.ActionCreate,
+			After:       convertToAuditState(project),
+		})
+		if err != nil {
+			return fmt.Errorf("audit record: %w", err)
+		}
 
+		return nil
+	})
 	if err != nil {
-		return 0, fmt.Errorf("repo create project: %v", err)
+		return 0, err
 	}
 
-	return id, err
+	// Новый проект появляется в статистике всех, кто его видит.
+	for _, viewerID := range viewers {
+		u.statsCache.InvalidateProject(ctx, viewerID, project.ID)
+	}
+
+	return project.ID, nil
 }
 
 func convertToRepoProject(project Project) repo.Project {
 	return repo.Project{
 		ID:     project.ID,
 		UserID: project.UserID,
-		Name:   project.Name,
+		WorkspaceID: sql.NullInt64{
+			Int64: project.WorkspaceID,
+			Valid: project.WorkspaceID != 0,
+		},
+		Name: project.Name,
 	}
 }
 
 func (u *Usecase) GetUserProjects(ctx context.Context, userID int64) ([]Project, error) {
+	ctx, span := tracing.Start(ctx, "project.GetUserProjects")
+	defer span.End()
+
 	repoProjects, err := u.repository.GetUserProjects(ctx, userID)
 	if err != nil {
 		if errors.Is(err, repo.ErrProjectNotFound) {
 			return []Project{}, nil
 		}
-		return nil, fmt.Errorf("repo get user projects: %v", err)
+		return nil, fmt.Errorf("repo get user projects: %w", err)
 	}
 
 	projects := convertToProjects(repoProjects)
@@ -88,7 +194,44 @@ func (u *Usecase) GetUserProjects(ctx context.Context, userID int64) ([]Project,
 	return projects, nil
 }
 
+// GetWorkspaceProjects возвращает проекты пространства, доступно любому участнику.
+func (u *Usecase) GetWorkspaceProjects(ctx context.Context, workspaceID, userID int64) ([]Project, error) {
+	ctx, span := tracing.Start(ctx, "project.GetWorkspaceProjects")
+	defer span.End()
+
+	if err := u.requireWorkspaceRole(ctx, workspaceID, userID, roles.Viewer); err != nil {
+		return nil, err
+	}
+
+	repoProjects, err := u.repository.GetWorkspaceProjects(ctx, workspaceID)
+	if err != nil {
+		if errors.Is(err, repo.ErrProjectNotFound) {
+			return []Project{}, nil
+		}
+		return nil, fmt.Errorf("repo get workspace projects: %w", err)
+	}
+
+	return convertToProjects(repoProjects), nil
+}
+
+// ProjectStat возвращает статистику пользователя по проекту за период. Результат кешируется
+// до изменения записей пользователя в проекте.
 func (u *Usecase) ProjectStat(ctx context.Context, projectID int64, userID int64, timeStart, timeEnd time.Time) (AllProjectEntriesStat, error) {
+	ctx, span := tracing.Start(ctx, "project.ProjectStat")
+	defer span.End()
+
+	var stat AllProjectEntriesStat
+	key := statscache.ProjectStatKey(userID, projectID, timeStart, timeEnd)
+	err := u.statsCache.Fetch(ctx, key, &stat, func(ctx context.Context) error {
+		var err error
+		stat, err = u.projectStat(ctx, projectID, userID, timeStart, timeEnd)
+		return err
+	})
+
+	return stat, err
+}
+
+func (u *Usecase) projectStat(ctx context.Context, projectID int64, userID int64, timeStart, timeEnd time.Time) (AllProjectEntriesStat, error) {
 	projectEntries, err := u.entryRepository.GetProjectEntriesForInterval(ctx, userID, projectID, timeStart, timeEnd)
 	if err != nil {
 		return AllProjectEntriesStat{}, fmt.Errorf("get project entries error: %w", err)
@@ -116,7 +259,7 @@ func (u *Usecase) ProjectStat(ctx context.Context, projectID int64, userID int64
 		entriesStat = append(entriesStat, ProjectEntrieInfo{
 			EntryName:            v.EntryName,
 			EntryDurationInSec:   v.EntryDurationInSec,
-			EntryDurationPercent: calculatePercentDuration(v.EntryDurationInSec, totalDurationSec),
+			EntryDurationPercent: utils.CalculatePercentDuration(v.EntryDurationInSec, totalDurationSec),
 		})
 	}
 
@@ -126,13 +269,30 @@ func (u *Usecase) ProjectStat(ctx context.Context, projectID int64, userID int64
 	}, nil
 }
 
+// ProjectsStats возвращает статистику пользователя по всем его проектам за период. Результат
+// кешируется до изменения записей или проектов пользователя.
 func (u *Usecase) ProjectsStats(ctx context.Context, userID int64, timeStart, timeEnd time.Time) (AllProjectsStat, error) {
+	ctx, span := tracing.Start(ctx, "project.ProjectsStats")
+	defer span.End()
+
+	var stats AllProjectsStat
+	key := statscache.ProjectsStatsKey(userID, timeStart, timeEnd)
+	err := u.statsCache.Fetch(ctx, key, &stats, func(ctx context.Context) error {
+		var err error
+		stats, err = u.projectsStats(ctx, userID, timeStart, timeEnd)
+		return err
+	})
+
+	return stats, err
+}
+
+func (u *Usecase) projectsStats(ctx context.Context, userID int64, timeStart, timeEnd time.Time) (AllProjectsStat, error) {
 	repoProjects, err := u.repository.GetUserProjects(ctx, userID)
 	if err != nil {
 		if errors.Is(err, repo.ErrProjectNotFound) {
 			return AllProjectsStat{}, nil
 		}
-		return AllProjectsStat{}, fmt.Errorf("repo get user projects: %v", err)
+		return AllProjectsStat{}, fmt.Errorf("repo get user projects: %w", err)
 	}
 
 	generalStat := AllProjectsStat{
@@ -147,7 +307,7 @@ func (u *Usecase) ProjectsStats(ctx context.Context, userID int64, timeStart, ti
 			if errors.Is(err, entryRepoDto.ErrEntryNotFound) {
 				continue
 			}
-			return AllProjectsStat{}, fmt.Errorf("get project stat: %v", err)
+			return AllProjectsStat{}, fmt.Errorf("get project stat: %w", err)
 		}
 		projectStats = append(projectStats, stat)
 		generalStat.TotalDurationInSec += stat.ProjectDurationInSec
@@ -156,7 +316,7 @@ func (u *Usecase) ProjectsStats(ctx context.Context, userID int64, timeStart, ti
 	generalStat.ProjectsStat = projectStats
 
 	for idx := range generalStat.ProjectsStat {
-		generalStat.ProjectsStat[idx].ProjectDurationPercent = calculatePercentDuration(
+		generalStat.ProjectsStat[idx].ProjectDurationPercent = utils.CalculatePercentDuration(
 			generalStat.ProjectsStat[idx].ProjectDurationInSec,
 			generalStat.TotalDurationInSec,
 		)
@@ -165,9 +325,323 @@ func (u *Usecase) ProjectsStats(ctx context.Context, userID int64, timeStart, ti
 	return generalStat, nil
 }
 
-func (u *Usecase) ClearUserData(ctx context.Context, userID int64) error {
-	if err := u.repository.ClearUserData(ctx, userID); err != nil {
-		return fmt.Errorf("repo clear user data: %v", err)
+// ClearUserData переносит данные пользователя в корзину. Записи в проектах пространств
+// проверяются так же, как при удалении по одной.
+func (u *Usecase) ClearUserData(ctx context.Context, userID int64, opts DeleteOptions) error {
+	ctx, span := tracing.Start(ctx, "project.ClearUserData")
+	defer span.End()
+
+	overrides, err := u.checkEntriesDeletable(ctx, repo.EntryFilter{UserID: userID}, userID, opts)
+	if err != nil {
+		return err
+	}
+
+	err = u.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := u.repository.ClearUserData(ctx, userID); err != nil {
+			return fmt.Errorf("repo clear user data: %w", err)
+		}
+
+		if err := u.recordLockOverrides(ctx, overrides, userID, opts); err != nil {
+			return err
+		}
+
+		err := u.auditLogger.Record(ctx, auditUC.Event{
+			ActorID:    userID,
+			EntityType: auditUC.EntityUserData,
+			EntityID:   userID,
+			Action:     auditUC.ActionDelete,
+		})
+		if err != nil {
+			return fmt.Errorf("audit record: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	u.statsCache.InvalidateUser(ctx, userID)
+
+	return nil
+}
+
+// DeleteProject переносит проект в корзину вместе со всеми его записями и целями.
+// Личный проект может удалить только владелец, проект пространства — admin и owner.
+// Записи проекта пространства проверяются так же, как при удалении по одной.
+func (u *Usecase) DeleteProject(ctx context.Context, projectID, userID int64, opts DeleteOptions) error {
+	ctx, span := tracing.Start(ctx, "project.DeleteProject")
+	defer span.End()
+
+	repoProject, err := u.repository.GetProject(ctx, projectID)
+	if err != nil {
+		if errors.Is(err, repo.ErrProjectNotFound) {
+			return ErrProjectNotFound
+		}
+		return fmt.Errorf("repo get project: %w", err)
+	}
+	project := convertToProject(repoProject)
+
+	if err = u.checkManageable(ctx, project, userID); err != nil {
+		return err
+	}
+
+	var overrides []repo.ProtectedEntry
+	if project.WorkspaceID != 0 {
+		overrides, err = u.checkEntriesDeletable(ctx, repo.EntryFilter{ProjectID: projectID}, userID, opts)
+		if err != nil {
+			return err
+		}
+	}
+
+	viewers, err := u.viewers(ctx, project)
+	if err != nil {
+		return err
+	}
+
+	err = u.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		err := u.repository.DeleteProject(ctx, projectID, userID)
+		if err != nil {
+			if errors.Is(err, repo.ErrProjectNotFound) {
+				return ErrProjectNotFound
+			}
+			return fmt.Errorf("repo delete project: %w", err)
+		}
+
+		if err = u.recordLockOverrides(ctx, overrides, userID, opts); err != nil {
+			return err
+		}
+
+		err = u.auditLogger.Record(ctx, auditUC.Event{
+			ActorID:     userID,
+			WorkspaceID: project.WorkspaceID,
+			EntityType:  auditUC.EntityProject,
+			EntityID:    projectID,
+			Action:      auditUC.ActionDelete,
+			Before:      convertToAuditState(project),
+		})
+		if err != nil {
+			return fmt.Errorf("audit record: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, viewerID := range viewers {
+		u.statsCache.InvalidateProject(ctx, viewerID, projectID)
+	}
+
+	return nil
+}
+
+// RestoreProject возвращает проект из корзины вместе с записями и целями, удаленными вместе с ним.
+// Если название уже занято другим проектом, восстановить проект нельзя.
+func (u *Usecase) RestoreProject(ctx context.Context, projectID, userID int64) error {
+	ctx, span := tracing.Start(ctx, "project.RestoreProject")
+	defer span.End()
+
+	repoProject, err := u.repository.GetDeletedProject(ctx, projectID)
+	if err != nil {
+		if errors.Is(err, repo.ErrProjectNotFound) {
+			return ErrProjectNotFound
+		}
+		return fmt.Errorf("repo get deleted project: %w", err)
+	}
+	project := convertToProject(repoProject)
+
+	if err = u.checkManageable(ctx, project, userID); err != nil {
+		return err
+	}
+
+	if err = u.checkNameFree(ctx, project); err != nil {
+		return err
+	}
+
+	viewers, err := u.viewers(ctx, project)
+	if err != nil {
+		return err
+	}
+
+	err = u.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		err := u.repository.RestoreProject(ctx, projectID)
+		if err != nil {
+			if errors.Is(err, repo.ErrProjectNotFound) {
+				return ErrProjectNotFound
+			}
+			return fmt.Errorf("repo restore project: %w", err)
+		}
+
+		err = u.auditLogger.Record(ctx, auditUC.Event{
+			ActorID:     userID,
+			WorkspaceID: project.WorkspaceID,
+			EntityType:  auditUC.EntityProject,
+			EntityID:    projectID,
+			Action:      auditUC.ActionRestore,
+			After:       convertToAuditState(project),
+		})
+		if err != nil {
+			return fmt.Errorf("audit record: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, viewerID := range viewers {
+		u.statsCache.InvalidateProject(ctx, viewerID, projectID)
+	}
+
+	return nil
+}
+
+// checkManageable проверяет, что пользователь может удалять и восстанавливать проект.
+func (u *Usecase) checkManageable(ctx context.Context, project Project, userID int64) error {
+	if project.WorkspaceID != 0 {
+		return u.requireWorkspaceRole(ctx, project.WorkspaceID, userID, roles.Admin)
+	}
+
+	// Чужие личные проекты не раскрываем.
+	if project.UserID != userID {
+		return ErrProjectNotFound
+	}
+
+	return nil
+}
+
+// checkEntriesDeletable проверяет удаляемые записи в проектах пространств по тем же правилам,
+// что и удаление записи: записи в неделях с утвержденным табелем удалить нельзя, в заблокированном
+// периоде — только admin и owner пространства с причиной. Возвращает записи, для которых
+// блокировка обойдена.
+func (u *Usecase) checkEntriesDeletable(ctx context.Context, filter repo.EntryFilter, userID int64, opts DeleteOptions) ([]repo.ProtectedEntry, error) {
+	entries, err := u.repository.GetProtectedEntries(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("repo get protected entries: %w", err)
+	}
+
+	for _, entry := range entries {
+		if entry.Approved {
+			return nil, ErrEntryReadOnly
+		}
+	}
+
+	var overrides []repo.ProtectedEntry
+	checked := make(map[int64]bool)
+	for _, entry := range entries {
+		if !entry.Locked {
+			continue
+		}
+
+		allowed, ok := checked[entry.WorkspaceID]
+		if !ok {
+			allowed, err = u.canOverrideLock(ctx, entry.WorkspaceID, userID, opts)
+			if err != nil {
+				return nil, err
+			}
+			checked[entry.WorkspaceID] = allowed
+		}
+
+		if !allowed {
+			return nil, ErrPeriodLocked
+		}
+		overrides = append(overrides, entry)
+	}
+
+	return overrides, nil
+}
+
+// canOverrideLock проверяет, что пользователь может обойти блокировку периода в пространстве.
+func (u *Usecase) canOverrideLock(ctx context.Context, workspaceID, userID int64, opts DeleteOptions) (bool, error) {
+	if opts.LockOverrideReason == "" {
+		return false, nil
+	}
+
+	err := u.requireWorkspaceRole(ctx, workspaceID, userID, roles.Admin)
+	if errors.Is(err, ErrForbidden) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// recordLockOverrides записывает в журнал удаление записей в заблокированном периоде.
+func (u *Usecase) recordLockOverrides(ctx context.Context, entries []repo.ProtectedEntry, userID int64, opts DeleteOptions) error {
+	for _, entry := range entries {
+		err := u.periodLockRepository.CreateOverride(ctx, periodLockRepoDto.Override{
+			WorkspaceID: entry.WorkspaceID,
+			UserID:      sql.NullInt64{Int64: userID, Valid: true},
+			EntryID:     entry.ID,
+			Action:      lockOverrideAction,
+			Reason:      opts.LockOverrideReason,
+		})
+		if err != nil {
+			return fmt.Errorf("repo create override: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// checkNameFree проверяет, что название проекта не занято в пространстве или среди личных проектов.
+func (u *Usecase) checkNameFree(ctx context.Context, project Project) error {
+	var oldProject repo.Project
+	var err error
+
+	if project.WorkspaceID != 0 {
+		oldProject, err = u.repository.GetWorkspaceProjectByName(ctx, project.WorkspaceID, project.Name)
+	} else {
+		oldProject, err = u.repository.GetProjectByName(ctx, project.UserID, project.Name)
+	}
+
+	if err != nil && !errors.Is(err, repo.ErrProjectNotFound) {
+		return fmt.Errorf("repo get project by name: %w", err)
+	}
+	if oldProject.ID != 0 {
+		return ErrProjectExists
+	}
+
+	return nil
+}
+
+// viewers возвращает пользователей, в статистике которых есть проект: владельца личного
+// проекта или участников пространства.
+func (u *Usecase) viewers(ctx context.Context, project Project) ([]int64, error) {
+	if project.WorkspaceID == 0 {
+		return []int64{project.UserID}, nil
+	}
+
+	members, err := u.workspaceRepository.GetMembers(ctx, project.WorkspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("repo get members: %w", err)
+	}
+
+	userIDs := make([]int64, 0, len(members))
+	for _, member := range members {
+		userIDs = append(userIDs, member.UserID)
+	}
+
+	return userIDs, nil
+}
+
+// requireWorkspaceRole проверяет, что пользователь участник пространства с ролью не ниже min.
+func (u *Usecase) requireWorkspaceRole(ctx context.Context, workspaceID, userID int64, min roles.Role) error {
+	role, err := u.workspaceRepository.GetMemberRole(ctx, workspaceID, userID)
+	if err != nil {
+		if errors.Is(err, workspaceRepoDto.ErrMemberNotFound) {
+			return ErrForbidden
+		}
+		return fmt.Errorf("repo get member role: %w", err)
+	}
+
+	if !roles.Role(role).AtLeast(min) {
+		return ErrForbidden
 	}
 
 	return nil
@@ -198,10 +672,6 @@ func calculateProjectDuration(entries []entryRepoDto.Entry) time.Duration {
 	return totalDuration
 }
 
-func calculatePercentDuration(duration float64, totalDuration float64) float64 {
-	return float64(duration) / float64(totalDuration) * 100
-}
-
 func convertToProjects(projects []repo.Project) []Project {
 	repoProjects := make([]Project, 0, len(projects))
 	for _, project := range projects {
@@ -213,8 +683,19 @@ func convertToProjects(projects []repo.Project) []Project {
 
 func convertToProject(e repo.Project) Project {
 	return Project{
-		ID:     e.ID,
-		UserID: e.UserID,
-		Name:   e.Name,
+		ID:          e.ID,
+		UserID:      e.UserID,
+		WorkspaceID: e.WorkspaceID.Int64,
+		Name:        e.Name,
+		Client:      e.Client.String,
+	}
+}
+
+func convertToAuditState(project Project) auditUC.ProjectState {
+	return auditUC.ProjectState{
+		ID:          project.ID,
+		UserID:      project.UserID,
+		WorkspaceID: project.WorkspaceID,
+		Name:        project.Name,
 	}
 }
@@ -0,0 +1,241 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	auditUC "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/audit/usecase"
+	periodLockRepoDto "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/periodlock/repository"
+	repo "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/project/repository"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/statscache"
+	workspaceRepoDto "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/workspace/repository"
+)
+
+const (
+	testUserID      = int64(1)
+	testWorkspaceID = int64(10)
+	testProjectID   = int64(100)
+)
+
+// fakeRepository один проект пространства и записи, которые попадают под проверки.
+// Методы, которые тесты не вызывают, достаются от nil-интерфейса и паникуют.
+type fakeRepository struct {
+	repository
+
+	protected []repo.ProtectedEntry
+	filters   []repo.EntryFilter
+	deleted   bool
+	cleared   bool
+}
+
+func (r *fakeRepository) GetProject(_ context.Context, projectID int64) (repo.Project, error) {
+	if projectID != testProjectID {
+		return repo.Project{}, repo.ErrProjectNotFound
+	}
+
+	return repo.Project{
+		ID:          testProjectID,
+		Name:        "Backend",
+		UserID:      testUserID,
+		WorkspaceID: sql.NullInt64{Int64: testWorkspaceID, Valid: true},
+	}, nil
+}
+
+func (r *fakeRepository) GetProtectedEntries(_ context.Context, filter repo.EntryFilter) ([]repo.ProtectedEntry, error) {
+	r.filters = append(r.filters, filter)
+	return r.protected, nil
+}
+
+func (r *fakeRepository) DeleteProject(context.Context, int64, int64) error {
+	r.deleted = true
+	return nil
+}
+
+func (r *fakeRepository) ClearUserData(context.Context, int64) error {
+	r.cleared = true
+	return nil
+}
+
+// fakeWorkspaceRepository роли участников единственного пространства.
+type fakeWorkspaceRepository struct {
+	roles map[int64]string
+}
+
+func (r *fakeWorkspaceRepository) GetMemberRole(_ context.Context, _ int64, userID int64) (string, error) {
+	role, ok := r.roles[userID]
+	if !ok {
+		return "", workspaceRepoDto.ErrMemberNotFound
+	}
+
+	return role, nil
+}
+
+func (r *fakeWorkspaceRepository) GetMembers(context.Context, int64) ([]workspaceRepoDto.Member, error) {
+	members := make([]workspaceRepoDto.Member, 0, len(r.roles))
+	for userID, role := range r.roles {
+		members = append(members, workspaceRepoDto.Member{UserID: userID, Role: role})
+	}
+
+	return members, nil
+}
+
+type fakeAuditLogger struct {
+	events []auditUC.Event
+}
+
+func (l *fakeAuditLogger) Record(_ context.Context, event auditUC.Event) error {
+	l.events = append(l.events, event)
+	return nil
+}
+
+type fakeTxManager struct{}
+
+func (fakeTxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type fakeStatsCache struct{}
+
+func (fakeStatsCache) Fetch(ctx context.Context, _ statscache.Key, _ interface{}, load func(ctx context.Context) error) error {
+	return load(ctx)
+}
+
+func (fakeStatsCache) InvalidateProject(context.Context, int64, int64) {}
+
+func (fakeStatsCache) InvalidateUser(context.Context, int64) {}
+
+// fakePeriodLockRepository запоминает записанные обходы блокировки.
+type fakePeriodLockRepository struct {
+	overrides []periodLockRepoDto.Override
+}
+
+func (r *fakePeriodLockRepository) CreateOverride(_ context.Context, override periodLockRepoDto.Override) error {
+	r.overrides = append(r.overrides, override)
+	return nil
+}
+
+func newTestUsecase(r *fakeRepository, locks *fakePeriodLockRepository, audit *fakeAuditLogger, role string) *Usecase {
+	workspaces := &fakeWorkspaceRepository{roles: map[int64]string{testUserID: role}}
+	return NewUsecase(r, nil, workspaces, locks, audit, fakeTxManager{}, fakeStatsCache{})
+}
+
+var (
+	approvedEntry = repo.ProtectedEntry{ID: 1, UserID: testUserID, WorkspaceID: testWorkspaceID, Approved: true}
+	lockedEntry   = repo.ProtectedEntry{ID: 2, UserID: testUserID, WorkspaceID: testWorkspaceID, Locked: true}
+	lockedEntry2  = repo.ProtectedEntry{ID: 3, UserID: 2, WorkspaceID: testWorkspaceID, Locked: true}
+)
+
+// Записи в проектах пространств проверяются так же, как при удалении записи: утвержденные недели
+// не удаляются никогда, заблокированный период — только admin с причиной, и каждая такая запись
+// попадает в журнал обходов блокировки.
+type deleteTest struct {
+	name          string
+	role          string
+	protected     []repo.ProtectedEntry
+	opts          DeleteOptions
+	wantErr       error
+	wantOverrides []int64
+}
+
+var deleteTests = []deleteTest{
+	{name: "no protected entries", role: "admin"},
+	{name: "entry in approved week", role: "admin", protected: []repo.ProtectedEntry{approvedEntry}, wantErr: ErrEntryReadOnly},
+	{
+		name:      "approved week with override reason",
+		role:      "admin",
+		protected: []repo.ProtectedEntry{approvedEntry, lockedEntry},
+		opts:      DeleteOptions{LockOverrideReason: "cleanup"},
+		wantErr:   ErrEntryReadOnly,
+	},
+	{name: "locked period", role: "admin", protected: []repo.ProtectedEntry{lockedEntry}, wantErr: ErrPeriodLocked},
+	{
+		name:          "locked period with override reason",
+		role:          "admin",
+		protected:     []repo.ProtectedEntry{lockedEntry, lockedEntry2},
+		opts:          DeleteOptions{LockOverrideReason: "cleanup"},
+		wantOverrides: []int64{2, 3},
+	},
+}
+
+func TestDeleteProjectWithProtectedEntries(t *testing.T) {
+	for _, tt := range deleteTests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &fakeRepository{protected: tt.protected}
+			locks := &fakePeriodLockRepository{}
+			audit := &fakeAuditLogger{}
+
+			err := newTestUsecase(r, locks, audit, tt.role).DeleteProject(context.Background(), testProjectID, testUserID, tt.opts)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			}
+
+			if len(r.filters) != 1 || r.filters[0] != (repo.EntryFilter{ProjectID: testProjectID}) {
+				t.Errorf("checked entries %+v, want entries of the project", r.filters)
+			}
+
+			wantDeleted := tt.wantErr == nil
+			if r.deleted != wantDeleted {
+				t.Errorf("deleted %v, want %v", r.deleted, wantDeleted)
+			}
+			if wantDeleted && len(audit.events) != 1 {
+				t.Errorf("got %d audit events, want 1", len(audit.events))
+			}
+			checkOverrides(t, locks.overrides, tt.wantOverrides, tt.opts.LockOverrideReason)
+		})
+	}
+}
+
+func TestClearUserDataWithProtectedEntries(t *testing.T) {
+	tests := append([]deleteTest{}, deleteTests...)
+	tests = append(tests, deleteTest{
+		// Очистить данные может и участник, но обойти блокировку — только admin и owner.
+		name:      "locked period with override reason by member",
+		role:      "member",
+		protected: []repo.ProtectedEntry{lockedEntry},
+		opts:      DeleteOptions{LockOverrideReason: "cleanup"},
+		wantErr:   ErrPeriodLocked,
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &fakeRepository{protected: tt.protected}
+			locks := &fakePeriodLockRepository{}
+
+			err := newTestUsecase(r, locks, &fakeAuditLogger{}, tt.role).ClearUserData(context.Background(), testUserID, tt.opts)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			}
+
+			if len(r.filters) != 1 || r.filters[0] != (repo.EntryFilter{UserID: testUserID}) {
+				t.Errorf("checked entries %+v, want entries of the user", r.filters)
+			}
+			if r.cleared != (tt.wantErr == nil) {
+				t.Errorf("cleared %v, want %v", r.cleared, tt.wantErr == nil)
+			}
+			checkOverrides(t, locks.overrides, tt.wantOverrides, tt.opts.LockOverrideReason)
+		})
+	}
+}
+
+func checkOverrides(t *testing.T, overrides []periodLockRepoDto.Override, wantEntryIDs []int64, reason string) {
+	t.Helper()
+
+	if len(overrides) != len(wantEntryIDs) {
+		t.Fatalf("got %d lock overrides, want %d", len(overrides), len(wantEntryIDs))
+	}
+
+	for i, override := range overrides {
+		want := periodLockRepoDto.Override{
+			WorkspaceID: testWorkspaceID,
+			UserID:      sql.NullInt64{Int64: testUserID, Valid: true},
+			EntryID:     wantEntryIDs[i],
+			Action:      "delete",
+			Reason:      reason,
+		}
+		if override != want {
+			t.Errorf("override %d: got %+v, want %+v", i, override, want)
+		}
+	}
+}
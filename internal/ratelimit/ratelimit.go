@@ -0,0 +1,96 @@
+// Package ratelimit ограничивает частоту запросов алгоритмом token bucket: корзина
+// вмещает Burst токенов и пополняется со скоростью Rate токенов в секунду, каждый
+// запрос забирает из нее один токен.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Key по чему заводится отдельная корзина.
+type Key string
+
+const (
+	// KeyUser корзина на пользователя, работает после авторизации.
+	KeyUser Key = "user"
+	// KeyIP корзина на адрес клиента, работает и для маршрутов без авторизации.
+	KeyIP Key = "ip"
+)
+
+type Limit struct {
+	// Сколько токенов добавляется в секунду. Ноль — без ограничения.
+	Rate  float64
+	Burst int
+}
+
+// Unlimited сообщает, что лимит не задан и запросы не ограничиваются.
+func (l Limit) Unlimited() bool {
+	return l.Rate <= 0
+}
+
+// Group лимит для набора маршрутов.
+type Group struct {
+	Name  string
+	Key   Key
+	Limit Limit
+	// Маршруты группы по ключу "METHOD path", как при регистрации обработчика.
+	Routes []string
+}
+
+type Result struct {
+	Allowed bool
+	// Через сколько в корзине появится токен. Заполняется, только если запрос отклонен.
+	RetryAfter time.Duration
+}
+
+// take забирает токен из корзины, в которой было tokens токенов elapsed назад,
+// и возвращает новое число токенов.
+func take(tokens float64, elapsed time.Duration, limit Limit) (float64, Result) {
+	tokens = math.Min(float64(limit.Burst), tokens+elapsed.Seconds()*limit.Rate)
+	if tokens >= 1 {
+		return tokens - 1, Result{Allowed: true}
+	}
+
+	wait := (1 - tokens) / limit.Rate
+	return tokens, Result{RetryAfter: time.Duration(math.Ceil(wait * float64(time.Second)))}
+}
+
+// Store хранилище корзин.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// FallbackStore берет токены из основного хранилища, а когда оно недоступно — из запасного.
+// Так лимиты продолжают работать, пока Redis лежит, хотя и на каждый экземпляр отдельно.
+type FallbackStore struct {
+	primary  Store
+	fallback Store
+	onError  func(err error)
+}
+
+// NewFallbackStore создает хранилище с запасным. onError вызывается на каждую ошибку primary.
+func NewFallbackStore(primary, fallback Store, onError func(err error)) *FallbackStore {
+	return &FallbackStore{
+		primary:  primary,
+		fallback: fallback,
+		onError:  onError,
+	}
+}
+
+func (s *FallbackStore) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	res, err := s.primary.Take(ctx, key, limit)
+	if err == nil {
+		return res, nil
+	}
+
+	// Запрос клиента отменен, запасное хранилище тут не поможет.
+	if ctx.Err() != nil {
+		return Result{}, err
+	}
+
+	s.onError(err)
+
+	return s.fallback.Take(ctx, key, limit)
+}
@@ -0,0 +1,211 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func TestTake(t *testing.T) {
+	limit := Limit{Rate: 2, Burst: 5}
+
+	tests := []struct {
+		name       string
+		tokens     float64
+		elapsed    time.Duration
+		wantTokens float64
+		want       Result
+	}{
+		{"full bucket", 5, 0, 4, Result{Allowed: true}},
+		{"last token", 1, 0, 0, Result{Allowed: true}},
+		{"refill does not exceed burst", 5, time.Hour, 4, Result{Allowed: true}},
+		{"refill half a second gives a token", 0, 500 * time.Millisecond, 0, Result{Allowed: true}},
+		{"empty bucket", 0, 0, 0, Result{RetryAfter: 500 * time.Millisecond}},
+		{"partly refilled bucket", 0.5, 0, 0.5, Result{RetryAfter: 250 * time.Millisecond}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens, res := take(tt.tokens, tt.elapsed, limit)
+			if tokens != tt.wantTokens || res != tt.want {
+				t.Errorf("got %v tokens, %+v; want %v tokens, %+v", tokens, res, tt.wantTokens, tt.want)
+			}
+		})
+	}
+}
+
+// newTestMemoryStore возвращает хранилище с часами, которые двигает тест.
+func newTestMemoryStore() (*MemoryStore, func(d time.Duration)) {
+	now := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+
+	s := NewMemoryStore()
+	s.lastSweep = now
+	s.now = func() time.Time { return now }
+
+	return s, func(d time.Duration) { now = now.Add(d) }
+}
+
+func takeN(t *testing.T, s Store, key string, limit Limit, n int) []Result {
+	t.Helper()
+
+	res := make([]Result, 0, n)
+	for i := 0; i < n; i++ {
+		r, err := s.Take(context.Background(), key, limit)
+		if err != nil {
+			t.Fatalf("take %d: %v", i, err)
+		}
+		res = append(res, r)
+	}
+
+	return res
+}
+
+func TestMemoryStoreBurstAndRefill(t *testing.T) {
+	s, advance := newTestMemoryStore()
+	limit := Limit{Rate: 1, Burst: 3}
+
+	// Сначала можно сделать burst запросов подряд.
+	for i, res := range takeN(t, s, "user:1", limit, 3) {
+		if !res.Allowed {
+			t.Fatalf("request %d of burst rejected", i+1)
+		}
+	}
+
+	res := takeN(t, s, "user:1", limit, 1)[0]
+	if res.Allowed || res.RetryAfter != time.Second {
+		t.Fatalf("request over burst: got %+v, want rejected with retry after 1s", res)
+	}
+
+	// У другого ключа своя корзина.
+	if !takeN(t, s, "user:2", limit, 1)[0].Allowed {
+		t.Error("request of another user rejected")
+	}
+
+	advance(400 * time.Millisecond)
+	if res = takeN(t, s, "user:1", limit, 1)[0]; res.Allowed || res.RetryAfter != 600*time.Millisecond {
+		t.Errorf("after 400ms: got %+v, want rejected with retry after 600ms", res)
+	}
+
+	advance(600 * time.Millisecond)
+	if !takeN(t, s, "user:1", limit, 1)[0].Allowed {
+		t.Error("after refill of one token: request rejected")
+	}
+	if takeN(t, s, "user:1", limit, 1)[0].Allowed {
+		t.Error("refilled token used twice")
+	}
+
+	// Простой не копит токенов больше burst.
+	advance(time.Hour)
+	results := takeN(t, s, "user:1", limit, 4)
+	for i, res := range results[:3] {
+		if !res.Allowed {
+			t.Errorf("after idle: request %d rejected", i+1)
+		}
+	}
+	if results[3].Allowed {
+		t.Error("after idle: more than burst requests allowed")
+	}
+}
+
+func TestMemoryStoreSweepsFullBuckets(t *testing.T) {
+	s, advance := newTestMemoryStore()
+	limit := Limit{Rate: 1, Burst: 100}
+
+	takeN(t, s, "idle", limit, 1)
+	takeN(t, s, "busy", limit, 100)
+
+	// За минуту idle наполнилась, а busy нет: на ней 60 токенов из 100.
+	advance(sweepInterval)
+	takeN(t, s, "other", limit, 1)
+
+	if _, ok := s.buckets["idle"]; ok {
+		t.Error("full bucket is not swept")
+	}
+	if _, ok := s.buckets["busy"]; !ok {
+		t.Error("partly empty bucket is swept")
+	}
+}
+
+type errorStore struct {
+	err   error
+	calls int
+}
+
+func (s *errorStore) Take(context.Context, string, Limit) (Result, error) {
+	s.calls++
+	return Result{}, s.err
+}
+
+func TestFallbackStore(t *testing.T) {
+	limit := Limit{Rate: 1, Burst: 1}
+	primaryErr := errors.New("connection refused")
+
+	t.Run("primary error", func(t *testing.T) {
+		primary := &errorStore{err: primaryErr}
+		fallback, _ := newTestMemoryStore()
+
+		var reported []error
+		s := NewFallbackStore(primary, fallback, func(err error) { reported = append(reported, err) })
+
+		results := takeN(t, s, "ip:1", limit, 2)
+		if !results[0].Allowed || results[1].Allowed {
+			t.Errorf("got %+v, want the fallback limit: first allowed, second rejected", results)
+		}
+		if len(reported) != 2 || !errors.Is(reported[0], primaryErr) {
+			t.Errorf("reported errors %v, want primary error twice", reported)
+		}
+	})
+
+	t.Run("primary ok", func(t *testing.T) {
+		primary, _ := newTestMemoryStore()
+		fallback := &errorStore{err: errors.New("must not be called")}
+		s := NewFallbackStore(primary, fallback, func(err error) { t.Errorf("unexpected error %v", err) })
+
+		takeN(t, s, "ip:1", limit, 2)
+		if fallback.calls != 0 {
+			t.Errorf("fallback called %d times", fallback.calls)
+		}
+	})
+
+	t.Run("canceled request", func(t *testing.T) {
+		fallback := &errorStore{}
+		s := NewFallbackStore(&errorStore{err: context.Canceled}, fallback, func(error) {})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		if _, err := s.Take(ctx, "ip:1", limit); !errors.Is(err, context.Canceled) {
+			t.Errorf("got %v, want context.Canceled", err)
+		}
+		if fallback.calls != 0 {
+			t.Error("fallback used for a canceled request")
+		}
+	})
+}
+
+func TestFallbackStoreWithUnavailableRedis(t *testing.T) {
+	// На этом порту никто не слушает: Redis недоступен.
+	client := redis.NewClient(&redis.Options{
+		Addr:        "127.0.0.1:1",
+		DialTimeout: 100 * time.Millisecond,
+		MaxRetries:  -1,
+	})
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+
+	fallback, _ := newTestMemoryStore()
+	var reported int
+	s := NewFallbackStore(NewRedisStore(client), fallback, func(error) { reported++ })
+
+	results := takeN(t, s, "user:1", Limit{Rate: 1, Burst: 2}, 3)
+	if !results[0].Allowed || !results[1].Allowed || results[2].Allowed {
+		t.Errorf("got %+v, want the memory limit of 2 requests", results)
+	}
+	if reported != 3 {
+		t.Errorf("reported %d redis errors, want 3", reported)
+	}
+}
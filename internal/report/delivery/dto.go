@@ -0,0 +1,33 @@
+package delivery
+
+import "time"
+
+type WorkspaceReportOut struct {
+	TotalDurationInSec float64            `json:"total_duration_in_sec" example:"3600"` // Суммарное время (в сек.) по всем проектам пространства.
+	Projects           []ReportProjectOut `json:"projects"`                             // Статистика по проектам.
+	Members            []ReportMemberOut  `json:"members,omitempty"`                    // Статистика по участникам, только для admin и owner.
+}
+
+type ReportProjectOut struct {
+	ID              int64             `json:"id" example:"1"`                // Идентификатор проекта.
+	Name            string            `json:"name" example:"Работа"`         // Название проекта.
+	DurationInSec   float64           `json:"duration_in_sec" example:"360"` // Суммарное время (в сек.) потраченное на проект.
+	PercentDuration float64           `json:"percent_duration" example:"10"` // Доля (в процентах) длительности проекта от суммарной длительности.
+	Members         []ReportMemberOut `json:"members,omitempty"`             // Время участников по проекту, только для admin и owner.
+}
+
+type ReportMemberOut struct {
+	UserID          int64   `json:"user_id" example:"2"`           // Идентификатор участника.
+	Name            string  `json:"name" example:"Иван"`           // Имя участника.
+	DurationInSec   float64 `json:"duration_in_sec" example:"360"` // Суммарное время (в сек.) участника.
+	PercentDuration float64 `json:"percent_duration" example:"10"` // Доля (в процентах) от времени проекта или от суммарного времени.
+}
+
+type MemberEntryOut struct {
+	ID          int64     `json:"id" example:"1"`                            // Идентификатор записи.
+	ProjectID   int64     `json:"project_id" example:"1"`                    // Идентификатор проекта.
+	ProjectName string    `json:"project_name" example:"work"`               // Название проекта.
+	Name        string    `json:"name" example:"task1"`                      // Название записи.
+	TimeStart   time.Time `json:"time_start" example:"2024-03-23T15:04:05Z"` // Время начала записи.
+	TimeEnd     time.Time `json:"time_end" example:"2024-03-23T19:04:05Z"`   // Время окончания записи.
+}
@@ -0,0 +1,214 @@
+package delivery
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	usecaseDto "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/report/usecase"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/response"
+)
+
+type usecase interface {
+	WorkspaceReport(ctx context.Context, workspaceID, actorID int64, timeStart, timeEnd time.Time) (usecaseDto.WorkspaceReport, error)
+	MemberEntries(ctx context.Context, workspaceID, actorID, userID int64, timeStart, timeEnd time.Time) ([]usecaseDto.Entry, error)
+}
+
+type Delivery struct {
+	usecase usecase
+
+	logger echo.Logger
+}
+
+func RegisterHandlers(
+	e *echo.Echo,
+	usecase usecase,
+	logger echo.Logger,
+) {
+	handler := &Delivery{
+		usecase: usecase,
+
+		logger: logger,
+	}
+
+	e.GET("/workspaces/:workspace_id/reports", handler.GetWorkspaceReport)
+	e.GET("/workspaces/:workspace_id/reports/members/:user_id/entries", handler.GetMemberEntries)
+}
+
+// GetWorkspaceReport godoc
+// @Summary      Получить отчет по пространству.
+// @Description  Время по проектам пространства за интервал. Для admin и owner отчет содержит разбивку по участникам, остальным доступны только итоги.
+// @Tags     	 reports
+// @Accept	 	application/json
+// @Produce  	application/json
+// @Param    workspace_id path int true "Идентификатор пространства"
+// @Param        time_start    query     string  false  "RFC3339 format"
+// @Param        time_end    query     string  false  "RFC3339 format"
+// @Success  200 {object} WorkspaceReportOut "success"
+// @Failure 500 {object} response.Error "internal server error"
+// @Failure 400 {object} response.Error "bad request"
+// @Failure 403 {object} response.Error "forbidden"
+// @Router   /workspaces/{workspace_id}/reports [get]
+func (d *Delivery) GetWorkspaceReport(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	workspaceID, err := strconv.ParseInt(c.Param("workspace_id"), 10, 64)
+	if err != nil {
+		c.Logger().Errorf("parse int: %v", err)
+		return response.InvalidParam("workspace_id", "int")
+	}
+
+	userID, ok := c.Get("user_id").(int64)
+	if !ok {
+		c.Logger().Error("can't parse context user_id")
+		return response.Status(http.StatusInternalServerError)
+	}
+
+	timeStart, timeEnd := parseInterval(c)
+
+	report, err := d.usecase.WorkspaceReport(ctx, workspaceID, userID, timeStart, timeEnd)
+	if err != nil {
+		c.Logger().Errorf("usecase: %v", err)
+		return handleUsecaseError(err)
+	}
+
+	out := convertFromUsecaseReport(report)
+
+	return c.JSON(http.StatusOK, out)
+}
+
+// GetMemberEntries godoc
+// @Summary      Получить записи участника пространства.
+// @Description  Записи участника по проектам пространства за интервал. Чужие записи доступны только admin и owner.
+// @Tags     	 reports
+// @Accept	 	application/json
+// @Produce  	application/json
+// @Param    workspace_id path int true "Идентификатор пространства"
+// @Param    user_id path int true "Идентификатор участника"
+// @Param        time_start    query     string  false  "RFC3339 format"
+// @Param        time_end    query     string  false  "RFC3339 format"
+// @Success  200 {object} []MemberEntryOut "success"
+// @Failure 500 {object} response.Error "internal server error"
+// @Failure 400 {object} response.Error "bad request"
+// @Failure 403 {object} response.Error "forbidden"
+// @Router   /workspaces/{workspace_id}/reports/members/{user_id}/entries [get]
+func (d *Delivery) GetMemberEntries(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	workspaceID, err := strconv.ParseInt(c.Param("workspace_id"), 10, 64)
+	if err != nil {
+		c.Logger().Errorf("parse int: %v", err)
+		return response.InvalidParam("workspace_id", "int")
+	}
+
+	memberID, err := strconv.ParseInt(c.Param("user_id"), 10, 64)
+	if err != nil {
+		c.Logger().Errorf("parse int: %v", err)
+		return response.InvalidParam("user_id", "int")
+	}
+
+	userID, ok := c.Get("user_id").(int64)
+	if !ok {
+		c.Logger().Error("can't parse context user_id")
+		return response.Status(http.StatusInternalServerError)
+	}
+
+	timeStart, timeEnd := parseInterval(c)
+
+	entries, err := d.usecase.MemberEntries(ctx, workspaceID, userID, memberID, timeStart, timeEnd)
+	if err != nil {
+		c.Logger().Errorf("usecase: %v", err)
+		return handleUsecaseError(err)
+	}
+
+	out := make([]MemberEntryOut, 0, len(entries))
+	for _, e := range entries {
+		out = append(out, MemberEntryOut{
+			ID:          e.ID,
+			ProjectID:   e.ProjectID,
+			ProjectName: e.ProjectName,
+			Name:        e.Name,
+			TimeStart:   e.TimeStart,
+			TimeEnd:     e.TimeEnd,
+		})
+	}
+
+	return c.JSON(http.StatusOK, out)
+}
+
+// parseInterval разбирает интервал отчета так же, как статистика по проектам:
+// по умолчанию с начала времен до текущего момента.
+func parseInterval(c echo.Context) (time.Time, time.Time) {
+	timeStartStr := c.QueryParam("time_start")
+	timeEndStr := c.QueryParam("time_end")
+
+	timeStart := time.Time{}
+	timeEnd := time.Now()
+
+	if timeStartStr != "" {
+		// Намеренный скип ошибки.
+		timeStart, _ = time.Parse(time.RFC3339, timeStartStr)
+	}
+
+	if timeEndStr != "" {
+		// Намеренный скип ошибки.
+		timeEnd, _ = time.Parse(time.RFC3339, timeEndStr)
+	}
+
+	return timeStart, timeEnd
+}
+
+func handleUsecaseError(err error) *response.Error {
+	// Запрос не уложился в таймаут или был отменен.
+	if problem := response.ContextError(err); problem != nil {
+		return problem
+	}
+
+	if errors.Is(err, usecaseDto.ErrForbidden) {
+		return response.Status(http.StatusForbidden)
+	}
+
+	// По дефолту пятисотим.
+	return response.Status(http.StatusInternalServerError)
+}
+
+func convertFromUsecaseReport(report usecaseDto.WorkspaceReport) WorkspaceReportOut {
+	projectsOut := make([]ReportProjectOut, 0, len(report.ProjectsStat))
+	for _, p := range report.ProjectsStat {
+		projectsOut = append(projectsOut, ReportProjectOut{
+			ID:              p.ProjectID,
+			Name:            p.ProjectName,
+			DurationInSec:   p.DurationInSec,
+			PercentDuration: p.DurationPercent,
+			Members:         convertFromUsecaseMembersStat(p.MembersStat),
+		})
+	}
+
+	return WorkspaceReportOut{
+		TotalDurationInSec: report.TotalDurationInSec,
+		Projects:           projectsOut,
+		Members:            convertFromUsecaseMembersStat(report.MembersStat),
+	}
+}
+
+func convertFromUsecaseMembersStat(stat []usecaseDto.MemberStat) []ReportMemberOut {
+	if len(stat) == 0 {
+		return nil
+	}
+
+	out := make([]ReportMemberOut, 0, len(stat))
+	for _, s := range stat {
+		out = append(out, ReportMemberOut{
+			UserID:          s.UserID,
+			Name:            s.UserName,
+			DurationInSec:   s.DurationInSec,
+			PercentDuration: s.DurationPercent,
+		})
+	}
+
+	return out
+}
@@ -0,0 +1,22 @@
+package repository
+
+import "time"
+
+// MemberProjectDuration суммарное время участника по проекту пространства.
+type MemberProjectDuration struct {
+	ProjectID     int64   `db:"project_id"`
+	ProjectName   string  `db:"project_name"`
+	UserID        int64   `db:"user_id"`
+	UserName      string  `db:"user_name"`
+	DurationInSec float64 `db:"duration_in_sec"`
+}
+
+type Entry struct {
+	ID          int64     `db:"id"`
+	UserID      int64     `db:"user_id"`
+	ProjectID   int64     `db:"project_id"`
+	ProjectName string    `db:"project_name"`
+	Name        string    `db:"name"`
+	TimeStart   time.Time `db:"time_start"`
+	TimeEnd     time.Time `db:"time_end"`
+}
@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	ErrEntryNotFound = errors.New("entry not found")
+)
+
+type Repository struct {
+	db    *sqlx.DB
+	close func() error
+}
+
+func NewRepository(db *sqlx.DB) *Repository {
+	return &Repository{
+		db: db,
+		close: func() error {
+			return db.Close()
+		},
+	}
+}
+
+// Close закрывает пул соединений с базой.
+func (r *Repository) Close() error {
+	return r.close()
+}
+
+// GetWorkspaceDurations возвращает суммарное время каждого участника по каждому проекту пространства.
+// Записи отбираются так же, как для статистики по проектам пользователя: по времени начала в интервале.
+func (r *Repository) GetWorkspaceDurations(
+	ctx context.Context,
+	workspaceID int64,
+	start time.Time,
+	end time.Time) ([]MemberProjectDuration, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT
+			p.id,
+			p.name,
+			u.id,
+			u.name,
+			SUM(EXTRACT(EPOCH FROM e.time_end - e.time_start))::float8
+		FROM entries e
+		JOIN projects p ON p.id = e.project_id
+		JOIN users u ON u.id = e.user_id
+		WHERE p.workspace_id = $1 AND e.deleted_at IS NULL AND e.time_start BETWEEN $2 AND $3
+		GROUP BY p.id, p.name, u.id, u.name
+		ORDER BY p.id, u.id`, workspaceID, start, end)
+
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var durations []MemberProjectDuration
+	for rows.Next() {
+		var d MemberProjectDuration
+		if err = rows.Scan(
+			&d.ProjectID,
+			&d.ProjectName,
+			&d.UserID,
+			&d.UserName,
+			&d.DurationInSec,
+		); err != nil {
+			return nil, fmt.Errorf("scan: %w", err)
+		}
+
+		durations = append(durations, d)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("rows err: %w", rows.Err())
+	}
+
+	if len(durations) == 0 {
+		return nil, ErrEntryNotFound
+	}
+
+	return durations, nil
+}
+
+// GetWorkspaceMemberEntries возвращает записи участника по проектам пространства.
+func (r *Repository) GetWorkspaceMemberEntries(
+	ctx context.Context,
+	workspaceID int64,
+	userID int64,
+	start time.Time,
+	end time.Time) ([]Entry, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT
+			e.id,
+			e.user_id,
+			e.project_id,
+			p.name,
+			e.name,
+			e.time_start,
+			e.time_end
+		FROM entries e
+		JOIN projects p ON p.id = e.project_id
+		WHERE p.workspace_id = $1 AND e.user_id = $2 AND e.deleted_at IS NULL AND e.time_start BETWEEN $3 AND $4
+		ORDER BY e.time_start`, workspaceID, userID, start, end)
+
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var entries []Entry
+	for rows.Next() {
+		var entry Entry
+		if err = rows.Scan(
+			&entry.ID,
+			&entry.UserID,
+			&entry.ProjectID,
+			&entry.ProjectName,
+			&entry.Name,
+			&entry.TimeStart,
+			&entry.TimeEnd,
+		); err != nil {
+			return nil, fmt.Errorf("scan: %w", err)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("rows err: %w", rows.Err())
+	}
+
+	if len(entries) == 0 {
+		return nil, ErrEntryNotFound
+	}
+
+	return entries, nil
+}
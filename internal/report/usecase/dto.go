@@ -0,0 +1,38 @@
+package usecase
+
+import "time"
+
+type MemberStat struct {
+	UserID          int64
+	UserName        string
+	DurationInSec   float64
+	DurationPercent float64
+}
+
+type ProjectStat struct {
+	ProjectID       int64
+	ProjectName     string
+	DurationInSec   float64
+	DurationPercent float64
+
+	// Время участников по проекту, заполняется только для admin и owner.
+	MembersStat []MemberStat
+}
+
+type WorkspaceReport struct {
+	TotalDurationInSec float64
+	ProjectsStat       []ProjectStat
+
+	// Суммарное время участников по всем проектам, заполняется только для admin и owner.
+	MembersStat []MemberStat
+}
+
+type Entry struct {
+	ID          int64
+	UserID      int64
+	ProjectID   int64
+	ProjectName string
+	Name        string
+	TimeStart   time.Time
+	TimeEnd     time.Time
+}
@@ -0,0 +1,171 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	repo "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/report/repository"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/roles"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/tracing"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/utils"
+	workspaceRepoDto "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/workspace/repository"
+)
+
+var ErrForbidden = errors.New("forbidden")
+
+type repository interface {
+	GetWorkspaceDurations(ctx context.Context, workspaceID int64, start, end time.Time) ([]repo.MemberProjectDuration, error)
+	GetWorkspaceMemberEntries(ctx context.Context, workspaceID, userID int64, start, end time.Time) ([]repo.Entry, error)
+}
+
+type workspaceRepository interface {
+	GetMemberRole(ctx context.Context, workspaceID, userID int64) (string, error)
+}
+
+type Usecase struct {
+	repository          repository
+	workspaceRepository workspaceRepository
+}
+
+func NewUsecase(repository repository, workspaceRepository workspaceRepository) *Usecase {
+	return &Usecase{
+		repository:          repository,
+		workspaceRepository: workspaceRepository,
+	}
+}
+
+// WorkspaceReport строит отчет по времени в проектах пространства.
+// Разбивку по участникам видят только admin и owner, остальным доступны только итоги по проектам.
+func (u *Usecase) WorkspaceReport(ctx context.Context, workspaceID, actorID int64, timeStart, timeEnd time.Time) (WorkspaceReport, error) {
+	ctx, span := tracing.Start(ctx, "report.WorkspaceReport")
+	defer span.End()
+
+	role, err := u.getMemberRole(ctx, workspaceID, actorID)
+	if err != nil {
+		return WorkspaceReport{}, err
+	}
+
+	durations, err := u.repository.GetWorkspaceDurations(ctx, workspaceID, timeStart, timeEnd)
+	if err != nil {
+		if errors.Is(err, repo.ErrEntryNotFound) {
+			return WorkspaceReport{ProjectsStat: []ProjectStat{}}, nil
+		}
+		return WorkspaceReport{}, fmt.Errorf("repo get workspace durations: %w", err)
+	}
+
+	withMembers := role.AtLeast(roles.Admin)
+
+	report := WorkspaceReport{}
+	projectIdx := make(map[int64]int)
+	memberIdx := make(map[int64]int)
+	for _, d := range durations {
+		// Строки отсортированы по проекту, поэтому проекты добавляются по порядку.
+		idx, ok := projectIdx[d.ProjectID]
+		if !ok {
+			idx = len(report.ProjectsStat)
+			projectIdx[d.ProjectID] = idx
+			report.ProjectsStat = append(report.ProjectsStat, ProjectStat{
+				ProjectID:   d.ProjectID,
+				ProjectName: d.ProjectName,
+			})
+		}
+
+		report.ProjectsStat[idx].DurationInSec += d.DurationInSec
+		report.TotalDurationInSec += d.DurationInSec
+
+		if !withMembers {
+			continue
+		}
+
+		report.ProjectsStat[idx].MembersStat = append(report.ProjectsStat[idx].MembersStat, MemberStat{
+			UserID:        d.UserID,
+			UserName:      d.UserName,
+			DurationInSec: d.DurationInSec,
+		})
+
+		mIdx, ok := memberIdx[d.UserID]
+		if !ok {
+			mIdx = len(report.MembersStat)
+			memberIdx[d.UserID] = mIdx
+			report.MembersStat = append(report.MembersStat, MemberStat{
+				UserID:   d.UserID,
+				UserName: d.UserName,
+			})
+		}
+		report.MembersStat[mIdx].DurationInSec += d.DurationInSec
+	}
+
+	for i := range report.ProjectsStat {
+		project := &report.ProjectsStat[i]
+		project.DurationPercent = utils.CalculatePercentDuration(project.DurationInSec, report.TotalDurationInSec)
+
+		for j := range project.MembersStat {
+			project.MembersStat[j].DurationPercent = utils.CalculatePercentDuration(
+				project.MembersStat[j].DurationInSec,
+				project.DurationInSec,
+			)
+		}
+	}
+
+	for i := range report.MembersStat {
+		report.MembersStat[i].DurationPercent = utils.CalculatePercentDuration(
+			report.MembersStat[i].DurationInSec,
+			report.TotalDurationInSec,
+		)
+	}
+
+	return report, nil
+}
+
+// MemberEntries возвращает записи участника по проектам пространства.
+// Чужие записи видят только admin и owner, свои записи видит любой участник.
+func (u *Usecase) MemberEntries(ctx context.Context, workspaceID, actorID, userID int64, timeStart, timeEnd time.Time) ([]Entry, error) {
+	ctx, span := tracing.Start(ctx, "report.MemberEntries")
+	defer span.End()
+
+	role, err := u.getMemberRole(ctx, workspaceID, actorID)
+	if err != nil {
+		return nil, err
+	}
+
+	if actorID != userID && !role.AtLeast(roles.Admin) {
+		return nil, ErrForbidden
+	}
+
+	repoEntries, err := u.repository.GetWorkspaceMemberEntries(ctx, workspaceID, userID, timeStart, timeEnd)
+	if err != nil {
+		if errors.Is(err, repo.ErrEntryNotFound) {
+			return []Entry{}, nil
+		}
+		return nil, fmt.Errorf("repo get workspace member entries: %w", err)
+	}
+
+	entries := make([]Entry, 0, len(repoEntries))
+	for _, e := range repoEntries {
+		entries = append(entries, Entry{
+			ID:          e.ID,
+			UserID:      e.UserID,
+			ProjectID:   e.ProjectID,
+			ProjectName: e.ProjectName,
+			Name:        e.Name,
+			TimeStart:   e.TimeStart,
+			TimeEnd:     e.TimeEnd,
+		})
+	}
+
+	return entries, nil
+}
+
+func (u *Usecase) getMemberRole(ctx context.Context, workspaceID, userID int64) (roles.Role, error) {
+	role, err := u.workspaceRepository.GetMemberRole(ctx, workspaceID, userID)
+	if err != nil {
+		if errors.Is(err, workspaceRepoDto.ErrMemberNotFound) {
+			return "", ErrForbidden
+		}
+		return "", fmt.Errorf("repo get member role: %w", err)
+	}
+
+	return roles.Role(role), nil
+}
@@ -0,0 +1,167 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	repo "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/report/repository"
+	workspaceRepoDto "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/workspace/repository"
+)
+
+const testWorkspaceID = 5
+
+type fakeRepository struct {
+	durations []repo.MemberProjectDuration
+	entries   []repo.Entry
+}
+
+func (r *fakeRepository) GetWorkspaceDurations(context.Context, int64, time.Time, time.Time) ([]repo.MemberProjectDuration, error) {
+	if len(r.durations) == 0 {
+		return nil, repo.ErrEntryNotFound
+	}
+
+	return r.durations, nil
+}
+
+func (r *fakeRepository) GetWorkspaceMemberEntries(_ context.Context, _, userID int64, _, _ time.Time) ([]repo.Entry, error) {
+	var entries []repo.Entry
+	for _, e := range r.entries {
+		if e.UserID == userID {
+			entries = append(entries, e)
+		}
+	}
+
+	if len(entries) == 0 {
+		return nil, repo.ErrEntryNotFound
+	}
+
+	return entries, nil
+}
+
+// fakeWorkspaceRepository роли участников пространства testWorkspaceID.
+type fakeWorkspaceRepository map[int64]string
+
+func (r fakeWorkspaceRepository) GetMemberRole(_ context.Context, _, userID int64) (string, error) {
+	role, ok := r[userID]
+	if !ok {
+		return "", workspaceRepoDto.ErrMemberNotFound
+	}
+
+	return role, nil
+}
+
+var testMembers = fakeWorkspaceRepository{1: "owner", 2: "admin", 3: "member", 4: "viewer"}
+
+// Строки отсортированы по проекту, как их возвращает репозиторий.
+var testDurations = []repo.MemberProjectDuration{
+	{ProjectID: 10, ProjectName: "api", UserID: 2, UserName: "alice", DurationInSec: 100},
+	{ProjectID: 10, ProjectName: "api", UserID: 3, UserName: "bob", DurationInSec: 100},
+	{ProjectID: 20, ProjectName: "web", UserID: 3, UserName: "bob", DurationInSec: 600},
+}
+
+func TestWorkspaceReport(t *testing.T) {
+	withMembers := WorkspaceReport{
+		TotalDurationInSec: 800,
+		ProjectsStat: []ProjectStat{
+			{
+				ProjectID: 10, ProjectName: "api", DurationInSec: 200, DurationPercent: 25,
+				MembersStat: []MemberStat{
+					{UserID: 2, UserName: "alice", DurationInSec: 100, DurationPercent: 50},
+					{UserID: 3, UserName: "bob", DurationInSec: 100, DurationPercent: 50},
+				},
+			},
+			{
+				ProjectID: 20, ProjectName: "web", DurationInSec: 600, DurationPercent: 75,
+				MembersStat: []MemberStat{
+					{UserID: 3, UserName: "bob", DurationInSec: 600, DurationPercent: 100},
+				},
+			},
+		},
+		MembersStat: []MemberStat{
+			{UserID: 2, UserName: "alice", DurationInSec: 100, DurationPercent: 12.5},
+			{UserID: 3, UserName: "bob", DurationInSec: 700, DurationPercent: 87.5},
+		},
+	}
+
+	projectsOnly := WorkspaceReport{
+		TotalDurationInSec: 800,
+		ProjectsStat: []ProjectStat{
+			{ProjectID: 10, ProjectName: "api", DurationInSec: 200, DurationPercent: 25},
+			{ProjectID: 20, ProjectName: "web", DurationInSec: 600, DurationPercent: 75},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		actorID   int64
+		durations []repo.MemberProjectDuration
+		want      WorkspaceReport
+		wantErr   error
+	}{
+		{name: "owner", actorID: 1, durations: testDurations, want: withMembers},
+		{name: "admin", actorID: 2, durations: testDurations, want: withMembers},
+		{name: "member", actorID: 3, durations: testDurations, want: projectsOnly},
+		{name: "viewer", actorID: 4, durations: testDurations, want: projectsOnly},
+		{name: "not a member", actorID: 9, durations: testDurations, wantErr: ErrForbidden},
+		{name: "no entries", actorID: 1, want: WorkspaceReport{ProjectsStat: []ProjectStat{}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := NewUsecase(&fakeRepository{durations: tt.durations}, testMembers)
+
+			report, err := u.WorkspaceReport(context.Background(), testWorkspaceID, tt.actorID, time.Time{}, time.Now())
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error %v, want %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(report, tt.want) {
+				t.Errorf("report %+v, want %+v", report, tt.want)
+			}
+		})
+	}
+}
+
+func TestMemberEntries(t *testing.T) {
+	timeStart := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	r := &fakeRepository{entries: []repo.Entry{
+		{ID: 1, UserID: 3, ProjectID: 10, ProjectName: "api", Name: "review", TimeStart: timeStart, TimeEnd: timeStart.Add(time.Hour)},
+	}}
+	bobEntries := []Entry{
+		{ID: 1, UserID: 3, ProjectID: 10, ProjectName: "api", Name: "review", TimeStart: timeStart, TimeEnd: timeStart.Add(time.Hour)},
+	}
+
+	tests := []struct {
+		name    string
+		actorID int64
+		userID  int64
+		want    []Entry
+		wantErr error
+	}{
+		{name: "own entries", actorID: 3, userID: 3, want: bobEntries},
+		{name: "admin reads member", actorID: 2, userID: 3, want: bobEntries},
+		{name: "owner reads member", actorID: 1, userID: 3, want: bobEntries},
+		{name: "member reads other", actorID: 3, userID: 2, wantErr: ErrForbidden},
+		{name: "viewer reads other", actorID: 4, userID: 3, wantErr: ErrForbidden},
+		{name: "not a member", actorID: 9, userID: 9, wantErr: ErrForbidden},
+		{name: "no entries", actorID: 4, userID: 4, want: []Entry{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := NewUsecase(r, testMembers)
+
+			entries, err := u.MemberEntries(context.Background(), testWorkspaceID, tt.actorID, tt.userID, time.Time{}, time.Now())
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error %v, want %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(entries, tt.want) {
+				t.Errorf("entries %+v, want %+v", entries, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,16 @@
+package requestid
+
+import "context"
+
+type ctxKey struct{}
+
+// NewContext возвращает контекст с идентификатором запроса.
+func NewContext(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, requestID)
+}
+
+// FromContext возвращает идентификатор запроса или пустую строку, если его нет.
+func FromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(ctxKey{}).(string)
+	return requestID
+}
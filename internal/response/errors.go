@@ -0,0 +1,191 @@
+package response
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"gopkg.in/go-playground/validator.v9"
+)
+
+// MIMEProblemJSON тип ответа с ошибкой (RFC 7807).
+const MIMEProblemJSON = "application/problem+json"
+
+// Общие коды ошибок. Обработчики добавляют свои коды для ошибок предметной области:
+// entry_not_found, period_locked и т.п.
+const (
+	CodeBadRequest          = "bad_request"
+	CodeValidationFailed    = "validation_failed"
+	CodeInvalidParameter    = "invalid_parameter"
+	CodeUnauthorized        = "unauthorized"
+	CodeForbidden           = "forbidden"
+	CodeNotFound            = "not_found"
+	CodeConflict            = "conflict"
+	CodeLocked              = "locked"
+	CodeUnprocessableEntity = "unprocessable_entity"
+	CodeRateLimited         = "rate_limited"
+	CodeInternal            = "internal_error"
+	CodeUnavailable         = "service_unavailable"
+	CodeTimeout             = "timeout"
+)
+
+var codesByStatus = map[int]string{
+	http.StatusBadRequest:          CodeBadRequest,
+	http.StatusUnauthorized:        CodeUnauthorized,
+	http.StatusForbidden:           CodeForbidden,
+	http.StatusNotFound:            CodeNotFound,
+	http.StatusConflict:            CodeConflict,
+	http.StatusLocked:              CodeLocked,
+	http.StatusUnprocessableEntity: CodeUnprocessableEntity,
+	http.StatusTooManyRequests:     CodeRateLimited,
+	http.StatusInternalServerError: CodeInternal,
+	http.StatusServiceUnavailable:  CodeUnavailable,
+	http.StatusGatewayTimeout:      CodeTimeout,
+}
+
+// Error ответ с ошибкой в духе RFC 7807 (problem details). По коду клиент отличает ошибки
+// с одинаковым HTTP-статусом, details указывает на неверные поля запроса.
+type Error struct {
+	Status    int          `json:"status" example:"400"`                                    // HTTP-статус.
+	Code      string       `json:"code" example:"validation_failed"`                        // Машиночитаемый код ошибки.
+	Message   string       `json:"message" example:"bad request"`                           // Описание для человека, может меняться.
+	Details   []FieldError `json:"details,omitempty"`                                       // Ошибки в полях запроса.
+	RequestID string       `json:"request_id,omitempty" example:"8f14e45fceea167a5a36dedd"` // Идентификатор запроса из X-Request-ID.
+} // @name Error
+
+// FieldError ошибка в поле тела или параметре запроса.
+type FieldError struct {
+	Field string `json:"field" example:"time_end"` // Поле в JSON или имя параметра.
+	Rule  string `json:"rule" example:"required"`  // Нарушенное правило: required, min, oneof, int...
+	Param string `json:"param,omitempty"`          // Параметр правила: для min=1 это 1.
+} // @name FieldError
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("code=%d, %s: %s", e.Status, e.Code, e.Message)
+}
+
+func NewError(status int, code, message string) *Error {
+	return &Error{
+		Status:  status,
+		Code:    code,
+		Message: message,
+	}
+}
+
+// Status возвращает ошибку с общим кодом и сообщением для HTTP-статуса.
+func Status(status int) *Error {
+	return NewError(status, codeByStatus(status), messageByStatus(status))
+}
+
+// NotFound возвращает 404 с кодом <entity>_not_found.
+func NotFound(entity string) *Error {
+	return NewError(
+		http.StatusNotFound,
+		entity+"_not_found",
+		fmt.Sprintf("%s: %s", ErrorMsgsByCode[http.StatusNotFound], entity))
+}
+
+// ValidationError возвращает 400 с ошибками валидатора по полям. Имена полей берутся
+// из json-тегов, вложенные поля записываются через точку: period.start.
+func ValidationError(err error) *Error {
+	problem := NewError(http.StatusBadRequest, CodeValidationFailed, ErrorMsgsByCode[http.StatusBadRequest])
+
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return problem
+	}
+
+	for _, fieldErr := range validationErrs {
+		field := fieldErr.Namespace()
+		// Первый элемент — имя структуры запроса, клиенту оно ни о чем не говорит.
+		if i := strings.IndexByte(field, '.'); i >= 0 {
+			field = field[i+1:]
+		}
+
+		problem.Details = append(problem.Details, FieldError{
+			Field: field,
+			Rule:  fieldErr.Tag(),
+			Param: fieldErr.Param(),
+		})
+	}
+
+	return problem
+}
+
+// InvalidParam возвращает 400 для параметра пути или запроса, который не удалось разобрать.
+func InvalidParam(name, rule string) *Error {
+	problem := NewError(
+		http.StatusBadRequest,
+		CodeInvalidParameter,
+		fmt.Sprintf("%s: %s", ErrorMsgsByCode[http.StatusBadRequest], name))
+	problem.Details = []FieldError{{Field: name, Rule: rule}}
+
+	return problem
+}
+
+// ErrorHandler отвечает на ошибки обработчиков в формате Error. Ошибки echo
+// (*echo.HTTPError, в том числе 404 и 405 маршрутизатора) получают общий код по статусу.
+func ErrorHandler(err error, c echo.Context) {
+	if c.Response().Committed {
+		return
+	}
+
+	problem := fromError(err)
+	problem.RequestID = c.Response().Header().Get(echo.HeaderXRequestID)
+
+	if c.Request().Method == http.MethodHead {
+		err = c.NoContent(problem.Status)
+	} else {
+		var body []byte
+		if body, err = json.Marshal(problem); err == nil {
+			err = c.Blob(problem.Status, MIMEProblemJSON, body)
+		}
+	}
+
+	if err != nil {
+		c.Logger().Errorf("write error response: %v", err)
+	}
+}
+
+func fromError(err error) *Error {
+	var problem *Error
+	if errors.As(err, &problem) {
+		res := *problem
+		return &res
+	}
+
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) {
+		message, ok := httpErr.Message.(string)
+		if !ok {
+			message = fmt.Sprint(httpErr.Message)
+		}
+		// Стандартный текст echo ("Not Found") заменяем нашим, как у остальных ошибок.
+		if message == http.StatusText(httpErr.Code) {
+			message = messageByStatus(httpErr.Code)
+		}
+
+		return NewError(httpErr.Code, codeByStatus(httpErr.Code), message)
+	}
+
+	return Status(http.StatusInternalServerError)
+}
+
+func codeByStatus(status int) string {
+	if code, ok := codesByStatus[status]; ok {
+		return code
+	}
+
+	return strings.ToLower(strings.ReplaceAll(http.StatusText(status), " ", "_"))
+}
+
+func messageByStatus(status int) string {
+	if message, ok := ErrorMsgsByCode[status]; ok {
+		return message
+	}
+
+	return strings.ToLower(http.StatusText(status))
+}
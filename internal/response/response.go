@@ -1,8 +1,43 @@
 package response
 
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/lib/pq"
+)
+
 var ErrorMsgsByCode = map[int]string{
+	504: "gateway timeout",
+	503: "service unavailable",
 	500: "internal server error",
+	409: "conflict",
 	404: "item is not found",
+	403: "forbidden",
+	401: "unauthorized",
+	429: "too many requests",
+	423: "locked",
 	422: "unprocessable entity",
 	400: "bad request",
 }
+
+// Код ошибки Postgres, с которым прерывается запрос по отмене или statement_timeout.
+const pqQueryCanceled = "57014"
+
+// ContextError возвращает ошибку для запроса, прерванного по контексту: 504, если он
+// не уложился в таймаут, 503, если его отменили (клиент отключился или сервер
+// останавливается). Для остальных ошибок возвращает nil.
+func ContextError(err error) *Error {
+	var pqErr *pq.Error
+	if errors.Is(err, context.DeadlineExceeded) ||
+		errors.As(err, &pqErr) && pqErr.Code == pqQueryCanceled {
+		return Status(http.StatusGatewayTimeout)
+	}
+
+	if errors.Is(err, context.Canceled) {
+		return Status(http.StatusServiceUnavailable)
+	}
+
+	return nil
+}
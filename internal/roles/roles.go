@@ -0,0 +1,42 @@
+package roles
+
+type Role string
+
+const (
+	Owner  Role = "owner"
+	Admin  Role = "admin"
+	Member Role = "member"
+	Viewer Role = "viewer"
+)
+
+// Чем больше ранг, тем больше прав у роли.
+var ranks = map[Role]int{
+	Viewer: 1,
+	Member: 2,
+	Admin:  3,
+	Owner:  4,
+}
+
+func (r Role) IsValid() bool {
+	_, ok := ranks[r]
+	return ok
+}
+
+// AtLeast проверяет, что роль дает не меньше прав, чем min.
+func (r Role) AtLeast(min Role) bool {
+	return r.IsValid() && ranks[r] >= ranks[min]
+}
+
+// ProjectRole возвращает роль пользователя в проекте.
+// Владелец личного проекта считается его Owner, в проекте пространства
+// пользователь получает свою роль участника (пустую, если он не участник).
+func ProjectRole(ownerID, userID int64, inWorkspace bool, memberRole string) Role {
+	if !inWorkspace {
+		if ownerID == userID {
+			return Owner
+		}
+		return ""
+	}
+
+	return Role(memberRole)
+}
@@ -0,0 +1,303 @@
+// Package statscache кеширует в Redis статистику пользователя: по всем проектам, по проекту
+// и прогресс целей.
+//
+// Ключ значения содержит версии данных, от которых оно зависит. Изменение данных заменяет
+// версию на новую случайную, и старые значения становятся недостижимы, а потом истекают по TTL.
+// Версии читаются до запроса в базу, поэтому значение, посчитанное до изменения, но
+// записанное после него, ляжет под старой версией и не будет прочитано.
+package statscache
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+)
+
+// Сколько хранится версия. Истекшая версия создается заново с новым значением,
+// так что это стоит только промахов, но не устаревших ответов.
+const versionTTL = 24 * time.Hour
+
+const (
+	resultHit   = "hit"
+	resultMiss  = "miss"
+	resultError = "error"
+)
+
+// Key кешируемое значение пользователя.
+type Key struct {
+	// Имя значения, оно же метка метрики.
+	name   string
+	userID int64
+	// 0, если значение не относится к одному проекту.
+	projectID int64
+	params    string
+}
+
+// ProjectsStatsKey статистика пользователя по всем проектам за период.
+func ProjectsStatsKey(userID int64, timeStart, timeEnd time.Time) Key {
+	return Key{
+		name:   "projects_stats",
+		userID: userID,
+		params: rangeParams(timeStart, timeEnd),
+	}
+}
+
+// ProjectStatKey статистика пользователя по проекту за период.
+func ProjectStatKey(userID, projectID int64, timeStart, timeEnd time.Time) Key {
+	return Key{
+		name:      "project_stat",
+		userID:    userID,
+		projectID: projectID,
+		params:    rangeParams(timeStart, timeEnd),
+	}
+}
+
+// GoalsKey цели пользователя по проекту с прогрессом.
+func GoalsKey(userID, projectID int64) Key {
+	return Key{
+		name:      "goals",
+		userID:    userID,
+		projectID: projectID,
+	}
+}
+
+func rangeParams(timeStart, timeEnd time.Time) string {
+	return strconv.FormatInt(timeStart.UnixNano(), 10) + ":" + strconv.FormatInt(timeEnd.UnixNano(), 10)
+}
+
+// Версии данных пользователя:
+// projects — состав и статистика всех проектов, меняется при любом изменении записей и проектов;
+// user — все данные сразу, меняется при импорте и очистке;
+// project — записи пользователя в проекте и сам проект;
+// goals — цели пользователя по проекту.
+func projectsVersionKey(userID int64) string {
+	return fmt.Sprintf("stats:%d:version:projects", userID)
+}
+
+func userVersionKey(userID int64) string {
+	return fmt.Sprintf("stats:%d:version", userID)
+}
+
+func projectVersionKey(userID, projectID int64) string {
+	return fmt.Sprintf("stats:%d:version:project:%d", userID, projectID)
+}
+
+func goalsVersionKey(userID, projectID int64) string {
+	return fmt.Sprintf("stats:%d:version:goals:%d", userID, projectID)
+}
+
+// versionKeys возвращает версии, от которых зависит значение.
+func (k Key) versionKeys() []string {
+	switch k.name {
+	case "projects_stats":
+		return []string{projectsVersionKey(k.userID)}
+	case "project_stat":
+		return []string{userVersionKey(k.userID), projectVersionKey(k.userID, k.projectID)}
+	default:
+		return []string{
+			userVersionKey(k.userID),
+			projectVersionKey(k.userID, k.projectID),
+			goalsVersionKey(k.userID, k.projectID),
+		}
+	}
+}
+
+func (k Key) valueKey(versions []string) string {
+	key := fmt.Sprintf("stats:%d:%s", k.userID, k.name)
+	if k.projectID != 0 {
+		key += ":" + strconv.FormatInt(k.projectID, 10)
+	}
+	if k.params != "" {
+		key += ":" + k.params
+	}
+	for _, version := range versions {
+		key += ":" + version
+	}
+
+	return key
+}
+
+type Cache struct {
+	client   *redis.Client
+	ttl      time.Duration
+	requests *prometheus.CounterVec
+	logger   echo.Logger
+}
+
+// NewCache создает кеш и регистрирует метрику попаданий и промахов. С nil client кеш
+// выключен: значения всегда считаются заново.
+func NewCache(
+	client *redis.Client,
+	ttl time.Duration,
+	registerer prometheus.Registerer,
+	namespace string,
+	logger echo.Logger,
+) *Cache {
+	c := &Cache{
+		client: client,
+		ttl:    ttl,
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "stats_cache",
+			Name:      "requests_total",
+			Help:      "Number of statistics cache lookups by value and result: hit, miss or error.",
+		}, []string{"cache", "result"}),
+		logger: logger,
+	}
+
+	registerer.MustRegister(c.requests)
+
+	return c
+}
+
+// Fetch читает значение key в dst. Если его нет в кеше, dst заполняет load, и результат
+// сохраняется. Ошибки Redis не прерывают запрос: значение просто считается заново.
+func (c *Cache) Fetch(ctx context.Context, key Key, dst interface{}, load func(ctx context.Context) error) error {
+	if c.client == nil {
+		return load(ctx)
+	}
+
+	versions, ok, err := c.versions(ctx, key)
+	if err != nil {
+		c.fail(key, "get versions", err)
+		return load(ctx)
+	}
+	// Версии только что созданы, сохранять значение под ними рано: их мог создать
+	// и параллельный запрос.
+	if !ok {
+		c.requests.WithLabelValues(key.name, resultMiss).Inc()
+		return load(ctx)
+	}
+
+	valueKey := key.valueKey(versions)
+
+	data, err := c.client.Get(ctx, valueKey).Bytes()
+	switch {
+	case err == nil:
+		if err = json.Unmarshal(data, dst); err == nil {
+			c.requests.WithLabelValues(key.name, resultHit).Inc()
+			return nil
+		}
+		c.logger.Errorf("stats cache: unmarshal %s: %v", valueKey, err)
+	case !errors.Is(err, redis.Nil):
+		c.fail(key, "get value", err)
+		return load(ctx)
+	}
+
+	c.requests.WithLabelValues(key.name, resultMiss).Inc()
+
+	if err = load(ctx); err != nil {
+		return err
+	}
+
+	if data, err = json.Marshal(dst); err != nil {
+		c.logger.Errorf("stats cache: marshal %s: %v", valueKey, err)
+		return nil
+	}
+
+	if err = c.client.Set(ctx, valueKey, data, c.ttl).Err(); err != nil {
+		c.logger.Errorf("stats cache: set %s: %v", valueKey, err)
+	}
+
+	return nil
+}
+
+// versions возвращает версии, от которых зависит key. Недостающие версии создаются,
+// и тогда ok равен false.
+func (c *Cache) versions(ctx context.Context, key Key) ([]string, bool, error) {
+	keys := key.versionKeys()
+
+	values, err := c.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, false, err
+	}
+
+	versions := make([]string, len(keys))
+	var missing []string
+	for i, value := range values {
+		version, ok := value.(string)
+		if !ok {
+			missing = append(missing, keys[i])
+			continue
+		}
+		versions[i] = version
+	}
+
+	if len(missing) == 0 {
+		return versions, true, nil
+	}
+
+	_, err = c.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, versionKey := range missing {
+			pipe.SetNX(ctx, versionKey, newVersion(), versionTTL)
+		}
+		return nil
+	})
+
+	return nil, false, err
+}
+
+// InvalidateProjects сбрасывает статистику по всем проектам: у пользователя изменился
+// состав проектов, например его добавили в пространство.
+func (c *Cache) InvalidateProjects(ctx context.Context, userID int64) {
+	c.invalidate(ctx, projectsVersionKey(userID))
+}
+
+// InvalidateProject сбрасывает статистику и цели пользователя по проекту:
+// изменились записи пользователя в проекте или сам проект.
+func (c *Cache) InvalidateProject(ctx context.Context, userID, projectID int64) {
+	c.invalidate(ctx, projectsVersionKey(userID), projectVersionKey(userID, projectID))
+}
+
+// InvalidateGoals сбрасывает цели пользователя по проекту.
+func (c *Cache) InvalidateGoals(ctx context.Context, userID, projectID int64) {
+	c.invalidate(ctx, goalsVersionKey(userID, projectID))
+}
+
+// InvalidateUser сбрасывает все значения пользователя: после импорта или очистки данных.
+func (c *Cache) InvalidateUser(ctx context.Context, userID int64) {
+	c.invalidate(ctx, projectsVersionKey(userID), userVersionKey(userID))
+}
+
+// invalidate заменяет версии новыми. Вызывается после фиксации изменений, ошибка только
+// логируется: данные уже сохранены, а устаревшие значения истекут по TTL.
+func (c *Cache) invalidate(ctx context.Context, versionKeys ...string) {
+	if c.client == nil {
+		return
+	}
+
+	// Ответ уже не зависит от клиента: сбрасываем кеш, даже если запрос отменили.
+	ctx = context.WithoutCancel(ctx)
+
+	_, err := c.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, versionKey := range versionKeys {
+			pipe.Set(ctx, versionKey, newVersion(), versionTTL)
+		}
+		return nil
+	})
+	if err != nil {
+		c.logger.Errorf("stats cache: invalidate %v: %v", versionKeys, err)
+	}
+}
+
+func (c *Cache) fail(key Key, op string, err error) {
+	c.requests.WithLabelValues(key.name, resultError).Inc()
+	c.logger.Errorf("stats cache: %s %s: %v", op, key.name, err)
+}
+
+func newVersion() string {
+	b := make([]byte, 8)
+	// crypto/rand не возвращает ошибок на поддерживаемых платформах.
+	_, _ = rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
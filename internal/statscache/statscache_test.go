@@ -0,0 +1,302 @@
+package statscache
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/labstack/gommon/log"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/redis/go-redis/v9"
+)
+
+const testTTL = 10 * time.Minute
+
+var (
+	testStart = time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	testEnd   = time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+)
+
+func newTestCache(t *testing.T) (*Cache, *miniredis.Miniredis) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+
+	return newCacheWithClient(client), mr
+}
+
+func newCacheWithClient(client *redis.Client) *Cache {
+	logger := log.New("statscache")
+	logger.SetOutput(io.Discard)
+
+	return NewCache(client, testTTL, prometheus.NewRegistry(), "test", logger)
+}
+
+// loader считает, сколько раз значение пришлось посчитать заново.
+type loader struct {
+	calls int
+	value int
+}
+
+func (l *loader) fetch(t *testing.T, c *Cache, key Key) int {
+	t.Helper()
+
+	var dst int
+	err := c.Fetch(context.Background(), key, &dst, func(context.Context) error {
+		l.calls++
+		dst = l.value
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return dst
+}
+
+func requests(t *testing.T, c *Cache, name, result string) float64 {
+	t.Helper()
+
+	var m dto.Metric
+	if err := c.requests.WithLabelValues(name, result).Write(&m); err != nil {
+		t.Fatal(err)
+	}
+
+	return m.GetCounter().GetValue()
+}
+
+// storedValueKey возвращает ключ, под которым сейчас лежит значение key.
+func storedValueKey(t *testing.T, mr *miniredis.Miniredis, key Key) string {
+	t.Helper()
+
+	var versions []string
+	for _, versionKey := range key.versionKeys() {
+		version, err := mr.Get(versionKey)
+		if err != nil {
+			t.Fatalf("get %s: %v", versionKey, err)
+		}
+		versions = append(versions, version)
+	}
+
+	return key.valueKey(versions)
+}
+
+func TestFetchMissThenHit(t *testing.T) {
+	c, mr := newTestCache(t)
+	key := ProjectStatKey(1, 5, testStart, testEnd)
+	l := &loader{value: 42}
+
+	// Первый запрос создает версии и не сохраняет значение, второй сохраняет.
+	for i := 1; i <= 2; i++ {
+		if got := l.fetch(t, c, key); got != 42 || l.calls != i {
+			t.Fatalf("fetch %d: got %d with %d loads, want 42 with %d", i, got, l.calls, i)
+		}
+	}
+
+	l.value = 7
+	if got := l.fetch(t, c, key); got != 42 || l.calls != 2 {
+		t.Errorf("cached fetch: got %d with %d loads, want 42 with 2", got, l.calls)
+	}
+
+	if hits, misses := requests(t, c, "project_stat", resultHit), requests(t, c, "project_stat", resultMiss); hits != 1 || misses != 2 {
+		t.Errorf("got %v hits and %v misses, want 1 and 2", hits, misses)
+	}
+
+	valueKey := storedValueKey(t, mr, key)
+	if ttl := mr.TTL(valueKey); ttl != testTTL {
+		t.Errorf("value %s ttl %v, want %v", valueKey, ttl, testTTL)
+	}
+}
+
+func TestFetchLoadError(t *testing.T) {
+	c, _ := newTestCache(t)
+	key := GoalsKey(1, 5)
+	errLoad := errors.New("db is down")
+
+	for i := 0; i < 2; i++ {
+		var dst int
+		err := c.Fetch(context.Background(), key, &dst, func(context.Context) error {
+			return errLoad
+		})
+		if !errors.Is(err, errLoad) {
+			t.Fatalf("fetch %d: got error %v, want %v", i+1, err, errLoad)
+		}
+	}
+
+	// Ошибка не кешируется.
+	l := &loader{value: 3}
+	if got := l.fetch(t, c, key); got != 3 || l.calls != 1 {
+		t.Errorf("got %d with %d loads, want 3 with 1", got, l.calls)
+	}
+}
+
+func TestInvalidate(t *testing.T) {
+	keys := map[string]Key{
+		"projects":             ProjectsStatsKey(1, testStart, testEnd),
+		"project 5":            ProjectStatKey(1, 5, testStart, testEnd),
+		"project 6":            ProjectStatKey(1, 6, testStart, testEnd),
+		"goals 5":              GoalsKey(1, 5),
+		"other user":           ProjectsStatsKey(2, testStart, testEnd),
+		"other user project 5": ProjectStatKey(2, 5, testStart, testEnd),
+	}
+
+	tests := []struct {
+		name       string
+		invalidate func(c *Cache)
+		recomputed []string
+	}{
+		{
+			name:       "project",
+			invalidate: func(c *Cache) { c.InvalidateProject(context.Background(), 1, 5) },
+			recomputed: []string{"projects", "project 5", "goals 5"},
+		},
+		{
+			name:       "user",
+			invalidate: func(c *Cache) { c.InvalidateUser(context.Background(), 1) },
+			recomputed: []string{"projects", "project 5", "project 6", "goals 5"},
+		},
+		{
+			name:       "projects",
+			invalidate: func(c *Cache) { c.InvalidateProjects(context.Background(), 1) },
+			recomputed: []string{"projects"},
+		},
+		{
+			name:       "goals",
+			invalidate: func(c *Cache) { c.InvalidateGoals(context.Background(), 1, 5) },
+			recomputed: []string{"goals 5"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := newTestCache(t)
+
+			loaders := make(map[string]*loader, len(keys))
+			for name, key := range keys {
+				loaders[name] = &loader{value: 1}
+				// Два запроса: первый создает версии, второй сохраняет значение.
+				loaders[name].fetch(t, c, key)
+				loaders[name].fetch(t, c, key)
+			}
+
+			tt.invalidate(c)
+
+			want := make(map[string]bool, len(tt.recomputed))
+			for _, name := range tt.recomputed {
+				want[name] = true
+			}
+
+			for name, key := range keys {
+				l := loaders[name]
+				l.calls, l.value = 0, 2
+
+				got := l.fetch(t, c, key)
+				if recomputed := l.calls == 1; recomputed != want[name] {
+					t.Errorf("%s: recomputed %v, want %v", name, recomputed, want[name])
+				}
+				if want[name] && got != 2 {
+					t.Errorf("%s: got stale value %d", name, got)
+				}
+			}
+		})
+	}
+}
+
+// Значение, посчитанное до изменения данных, но записанное после сброса, не читается.
+func TestFetchInvalidatedDuringLoad(t *testing.T) {
+	c, _ := newTestCache(t)
+	key := ProjectStatKey(1, 5, testStart, testEnd)
+
+	l := &loader{value: 1}
+	l.fetch(t, c, key)
+
+	var dst int
+	err := c.Fetch(context.Background(), key, &dst, func(ctx context.Context) error {
+		dst = 1
+		c.InvalidateProject(ctx, 1, 5)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l.calls, l.value = 0, 2
+	if got := l.fetch(t, c, key); got != 2 || l.calls != 1 {
+		t.Errorf("got %d with %d loads, want 2 with 1", got, l.calls)
+	}
+}
+
+func TestFetchRedisErrors(t *testing.T) {
+	c, mr := newTestCache(t)
+	key := ProjectsStatsKey(1, testStart, testEnd)
+
+	l := &loader{value: 1}
+	l.fetch(t, c, key)
+	l.fetch(t, c, key)
+
+	mr.SetError("server is down")
+
+	l.value = 2
+	for i := 1; i <= 2; i++ {
+		if got := l.fetch(t, c, key); got != 2 || l.calls != 2+i {
+			t.Fatalf("fetch %d with broken redis: got %d with %d loads", i, got, l.calls)
+		}
+	}
+	if errs := requests(t, c, "projects_stats", resultError); errs != 2 {
+		t.Errorf("got %v errors, want 2", errs)
+	}
+
+	// Сброс без Redis только логируется.
+	c.InvalidateUser(context.Background(), 1)
+}
+
+func TestFetchCorruptValue(t *testing.T) {
+	c, mr := newTestCache(t)
+	key := GoalsKey(1, 5)
+
+	l := &loader{value: 1}
+	l.fetch(t, c, key)
+	l.fetch(t, c, key)
+
+	valueKey := storedValueKey(t, mr, key)
+	if !mr.Exists(valueKey) {
+		t.Fatalf("value %s is not cached", valueKey)
+	}
+	if err := mr.Set(valueKey, "{not json"); err != nil {
+		t.Fatal(err)
+	}
+
+	l.calls, l.value = 0, 3
+	if got := l.fetch(t, c, key); got != 3 || l.calls != 1 {
+		t.Fatalf("got %d with %d loads, want 3 with 1", got, l.calls)
+	}
+	// Испорченное значение перезаписано.
+	if got := l.fetch(t, c, key); got != 3 || l.calls != 1 {
+		t.Errorf("got %d with %d loads, want cached 3", got, l.calls)
+	}
+}
+
+func TestDisabledCache(t *testing.T) {
+	c := newCacheWithClient(nil)
+	key := ProjectsStatsKey(1, testStart, testEnd)
+	l := &loader{value: 1}
+
+	for i := 1; i <= 3; i++ {
+		if got := l.fetch(t, c, key); got != 1 || l.calls != i {
+			t.Fatalf("fetch %d: got %d with %d loads", i, got, l.calls)
+		}
+	}
+
+	c.InvalidateUser(context.Background(), 1)
+	c.InvalidateProject(context.Background(), 1, 5)
+	c.InvalidateProjects(context.Background(), 1)
+	c.InvalidateGoals(context.Background(), 1, 5)
+}
@@ -0,0 +1,62 @@
+// Package testdb подключает тесты репозиториев к Postgres.
+package testdb
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/db"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/migrations"
+)
+
+// DSNEnv переменная окружения с DSN тестовой базы.
+const DSNEnv = "TIME_TRACKER_TEST_POSTGRES_DSN"
+
+// Open подключается к базе из DSNEnv и применяет к ней миграции. Без переменной тест
+// пропускается. База должна быть отдельной: тесты меняют и удаляют ее данные.
+func Open(t *testing.T) *sqlx.DB {
+	t.Helper()
+
+	dsn := os.Getenv(DSNEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", DSNEnv)
+	}
+
+	ctx := context.Background()
+
+	client, err := sqlx.ConnectContext(ctx, "postgres", dsn)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+
+	files, err := fs.Sub(db.Migrations, "migrations")
+	if err != nil {
+		t.Fatal(err)
+	}
+	migrator, err := migrations.NewMigrator(client, files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = migrator.Up(ctx); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	return client
+}
+
+// Exec выполняет запрос подготовки данных и останавливает тест при ошибке.
+func Exec(t *testing.T, client *sqlx.DB, query string, args ...interface{}) {
+	t.Helper()
+
+	if _, err := client.Exec(query, args...); err != nil {
+		t.Fatalf("exec %q: %v", query, err)
+	}
+}
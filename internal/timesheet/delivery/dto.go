@@ -0,0 +1,30 @@
+package delivery
+
+import "time"
+
+type CreateTimesheetIn struct {
+	WorkspaceID int64  `json:"workspace_id" validate:"required" example:"1"`        // Идентификатор пространства.
+	WeekStart   string `json:"week_start" validate:"required" example:"2024-03-18"` // Любой день недели в формате YYYY-MM-DD.
+}
+
+type CreateTimesheetOut struct {
+	ID int64 `json:"id" validate:"required" example:"1"` // Идентификатор табеля.
+}
+
+type ReviewTimesheetIn struct {
+	Comment string `json:"comment" example:"Не хватает записей за пятницу"` // Комментарий рецензента.
+}
+
+type TimesheetOut struct {
+	ID                 int64      `json:"id" example:"1"`                                        // Идентификатор табеля.
+	UserID             int64      `json:"user_id" example:"1"`                                   // Идентификатор пользователя.
+	UserName           string     `json:"user_name" example:"Иван"`                              // Имя пользователя.
+	WorkspaceID        int64      `json:"workspace_id" example:"1"`                              // Идентификатор пространства.
+	WeekStart          string     `json:"week_start" example:"2024-03-18"`                       // Понедельник недели табеля.
+	State              string     `json:"state" example:"submitted"`                             // Состояние: draft, submitted, approved, rejected.
+	Comment            string     `json:"comment" example:""`                                    // Комментарий рецензента.
+	ReviewerID         int64      `json:"reviewer_id,omitempty" example:"2"`                     // Идентификатор рецензента.
+	SubmittedAt        *time.Time `json:"submitted_at,omitempty" example:"2024-03-24T15:04:05Z"` // Время отправки на утверждение.
+	ReviewedAt         *time.Time `json:"reviewed_at,omitempty" example:"2024-03-25T15:04:05Z"`  // Время рецензии.
+	TotalDurationInSec float64    `json:"total_duration_in_sec" example:"144000"`                // Суммарное время (в сек.) за неделю по проектам пространства.
+}
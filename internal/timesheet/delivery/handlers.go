@@ -0,0 +1,359 @@
+package delivery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/response"
+	usecaseDto "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/timesheet/usecase"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/validator"
+)
+
+// Формат даты недели табеля.
+const dateLayout = "2006-01-02"
+
+type usecase interface {
+	CreateTimesheet(ctx context.Context, timesheet usecaseDto.Timesheet) (int64, error)
+	GetUserTimesheets(ctx context.Context, userID int64) ([]usecaseDto.Timesheet, error)
+	GetWorkspaceTimesheets(ctx context.Context, workspaceID, actorID int64, state usecaseDto.State) ([]usecaseDto.Timesheet, error)
+	Submit(ctx context.Context, timesheetID, userID int64) error
+	Review(ctx context.Context, workspaceID, timesheetID, reviewerID int64, state usecaseDto.State, comment string) error
+}
+
+type Delivery struct {
+	usecase usecase
+
+	logger echo.Logger
+}
+
+func RegisterHandlers(
+	e *echo.Echo,
+	usecase usecase,
+	logger echo.Logger,
+) {
+	handler := &Delivery{
+		usecase: usecase,
+
+		logger: logger,
+	}
+
+	e.POST("/me/timesheets", handler.CreateTimesheet)
+	e.GET("/me/timesheets", handler.GetMyTimesheets)
+	e.POST("/me/timesheets/:id/submit", handler.SubmitTimesheet)
+	e.GET("/workspaces/:workspace_id/timesheets", handler.GetWorkspaceTimesheets)
+	e.POST("/workspaces/:workspace_id/timesheets/:id/approve", handler.ApproveTimesheet)
+	e.POST("/workspaces/:workspace_id/timesheets/:id/reject", handler.RejectTimesheet)
+}
+
+// CreateTimesheet godoc
+// @Summary      Создать табель.
+// @Description  Создать черновик недельного табеля в пространстве.
+// @Tags     	 timesheets
+// @Accept	 application/json
+// @Produce  application/json
+// @Param    timesheet body CreateTimesheetIn true "Информация о табеле"
+// @Success  200 {object} CreateTimesheetOut "success create timesheet"
+// @Failure 500 {object} response.Error "internal server error"
+// @Failure 400 {object} response.Error "bad request"
+// @Failure 403 {object} response.Error "forbidden"
+// @Failure 409 {object} response.Error "conflict"
+// @Failure 422 {object} response.Error "unprocessable entity"
+// @Router   /me/timesheets [post]
+func (d *Delivery) CreateTimesheet(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var in CreateTimesheetIn
+	err := c.Bind(&in)
+
+	if err != nil {
+		c.Logger().Errorf("bind request: %v", err)
+		return response.Status(http.StatusUnprocessableEntity)
+	}
+
+	if ok, err := validator.IsRequestValid(&in); !ok {
+		c.Logger().Errorf("validation: %v", err)
+		return response.ValidationError(err)
+	}
+
+	weekStart, err := time.Parse(dateLayout, in.WeekStart)
+	if err != nil {
+		c.Logger().Errorf("invalid data format, should be YYYY-MM-DD: %v", err)
+		return response.InvalidParam("week_start", "date")
+	}
+
+	userID, ok := c.Get("user_id").(int64)
+	if !ok {
+		c.Logger().Error("can't parse context user_id")
+		return response.Status(http.StatusInternalServerError)
+	}
+
+	timesheetID, err := d.usecase.CreateTimesheet(ctx, usecaseDto.Timesheet{
+		UserID:      userID,
+		WorkspaceID: in.WorkspaceID,
+		WeekStart:   weekStart,
+	})
+	if err != nil {
+		c.Logger().Errorf("usecase: %v", err)
+		return handleUsecaseError(err)
+	}
+
+	out := CreateTimesheetOut{ID: timesheetID}
+
+	return c.JSON(http.StatusOK, out)
+}
+
+// GetMyTimesheets godoc
+// @Summary      Получить свои табели.
+// @Description  Получить табели пользователя во всех пространствах.
+// @Tags     	 timesheets
+// @Accept	 	application/json
+// @Produce  	application/json
+// @Success  200 {object} []TimesheetOut "success get timesheets"
+// @Failure 500 {object} response.Error "internal server error"
+// @Router   /me/timesheets [get]
+func (d *Delivery) GetMyTimesheets(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, ok := c.Get("user_id").(int64)
+	if !ok {
+		c.Logger().Error("can't parse context user_id")
+		return response.Status(http.StatusInternalServerError)
+	}
+
+	timesheets, err := d.usecase.GetUserTimesheets(ctx, userID)
+	if err != nil {
+		c.Logger().Errorf("usecase: %v", err)
+		return handleUsecaseError(err)
+	}
+
+	out := convertFromUsecaseTimesheets(timesheets)
+
+	return c.JSON(http.StatusOK, out)
+}
+
+// SubmitTimesheet godoc
+// @Summary      Отправить табель на утверждение.
+// @Description  Перевести свой табель из draft или rejected в submitted.
+// @Tags     	 timesheets
+// @Accept	 application/json
+// @Produce  application/json
+// @Param    id path int true "Идентификатор табеля"
+// @Success  200  "success submit timesheet"
+// @Failure 500 {object} response.Error "internal server error"
+// @Failure 400 {object} response.Error "bad request"
+// @Failure 404 {object} response.Error "item is not found"
+// @Failure 409 {object} response.Error "conflict"
+// @Router   /me/timesheets/{id}/submit [post]
+func (d *Delivery) SubmitTimesheet(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	timesheetID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Logger().Errorf("parse int: %v", err)
+		return response.InvalidParam("id", "int")
+	}
+
+	userID, ok := c.Get("user_id").(int64)
+	if !ok {
+		c.Logger().Error("can't parse context user_id")
+		return response.Status(http.StatusInternalServerError)
+	}
+
+	err = d.usecase.Submit(ctx, timesheetID, userID)
+	if err != nil {
+		c.Logger().Errorf("usecase: %v", err)
+		return handleUsecaseError(err)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+// GetWorkspaceTimesheets godoc
+// @Summary      Получить табели пространства.
+// @Description  Получить табели участников пространства, доступно admin и owner.
+// @Tags     	 timesheets
+// @Accept	 	application/json
+// @Produce  	application/json
+// @Param    workspace_id path int true "Идентификатор пространства"
+// @Param    state query string false "Фильтр по состоянию: draft, submitted, approved, rejected"
+// @Success  200 {object} []TimesheetOut "success get timesheets"
+// @Failure 500 {object} response.Error "internal server error"
+// @Failure 400 {object} response.Error "bad request"
+// @Failure 403 {object} response.Error "forbidden"
+// @Router   /workspaces/{workspace_id}/timesheets [get]
+func (d *Delivery) GetWorkspaceTimesheets(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	workspaceID, err := strconv.ParseInt(c.Param("workspace_id"), 10, 64)
+	if err != nil {
+		c.Logger().Errorf("parse int: %v", err)
+		return response.InvalidParam("workspace_id", "int")
+	}
+
+	state := usecaseDto.State(c.QueryParam("state"))
+	switch state {
+	case "", usecaseDto.StateDraft, usecaseDto.StateSubmitted, usecaseDto.StateApproved, usecaseDto.StateRejected:
+	default:
+		c.Logger().Errorf("invalid state: %s", state)
+		return response.InvalidParam("state", "oneof")
+	}
+
+	userID, ok := c.Get("user_id").(int64)
+	if !ok {
+		c.Logger().Error("can't parse context user_id")
+		return response.Status(http.StatusInternalServerError)
+	}
+
+	timesheets, err := d.usecase.GetWorkspaceTimesheets(ctx, workspaceID, userID, state)
+	if err != nil {
+		c.Logger().Errorf("usecase: %v", err)
+		return handleUsecaseError(err)
+	}
+
+	out := convertFromUsecaseTimesheets(timesheets)
+
+	return c.JSON(http.StatusOK, out)
+}
+
+// ApproveTimesheet godoc
+// @Summary      Утвердить табель.
+// @Description  Утвердить отправленный табель участника. После утверждения записи недели в проектах пространства становятся только для чтения.
+// @Tags     	 timesheets
+// @Accept	 application/json
+// @Produce  application/json
+// @Param    workspace_id path int true "Идентификатор пространства"
+// @Param    id path int true "Идентификатор табеля"
+// @Param    review body ReviewTimesheetIn false "Комментарий"
+// @Success  200  "success approve timesheet"
+// @Failure 500 {object} response.Error "internal server error"
+// @Failure 400 {object} response.Error "bad request"
+// @Failure 403 {object} response.Error "forbidden"
+// @Failure 404 {object} response.Error "item is not found"
+// @Failure 409 {object} response.Error "conflict"
+// @Failure 422 {object} response.Error "unprocessable entity"
+// @Router   /workspaces/{workspace_id}/timesheets/{id}/approve [post]
+func (d *Delivery) ApproveTimesheet(c echo.Context) error {
+	return d.review(c, usecaseDto.StateApproved)
+}
+
+// RejectTimesheet godoc
+// @Summary      Отклонить табель.
+// @Description  Отклонить отправленный табель участника с комментарием.
+// @Tags     	 timesheets
+// @Accept	 application/json
+// @Produce  application/json
+// @Param    workspace_id path int true "Идентификатор пространства"
+// @Param    id path int true "Идентификатор табеля"
+// @Param    review body ReviewTimesheetIn true "Комментарий"
+// @Success  200  "success reject timesheet"
+// @Failure 500 {object} response.Error "internal server error"
+// @Failure 400 {object} response.Error "bad request"
+// @Failure 403 {object} response.Error "forbidden"
+// @Failure 404 {object} response.Error "item is not found"
+// @Failure 409 {object} response.Error "conflict"
+// @Failure 422 {object} response.Error "unprocessable entity"
+// @Router   /workspaces/{workspace_id}/timesheets/{id}/reject [post]
+func (d *Delivery) RejectTimesheet(c echo.Context) error {
+	return d.review(c, usecaseDto.StateRejected)
+}
+
+func (d *Delivery) review(c echo.Context, state usecaseDto.State) error {
+	ctx := c.Request().Context()
+
+	workspaceID, err := strconv.ParseInt(c.Param("workspace_id"), 10, 64)
+	if err != nil {
+		c.Logger().Errorf("parse int: %v", err)
+		return response.InvalidParam("workspace_id", "int")
+	}
+
+	timesheetID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Logger().Errorf("parse int: %v", err)
+		return response.InvalidParam("id", "int")
+	}
+
+	var in ReviewTimesheetIn
+	err = c.Bind(&in)
+
+	if err != nil {
+		c.Logger().Errorf("bind request: %v", err)
+		return response.Status(http.StatusUnprocessableEntity)
+	}
+
+	// Отклонять табель без объяснения причины нельзя.
+	if state == usecaseDto.StateRejected && in.Comment == "" {
+		c.Logger().Error("validation: reject comment is required")
+		return response.InvalidParam("comment", "required")
+	}
+
+	userID, ok := c.Get("user_id").(int64)
+	if !ok {
+		c.Logger().Error("can't parse context user_id")
+		return response.Status(http.StatusInternalServerError)
+	}
+
+	err = d.usecase.Review(ctx, workspaceID, timesheetID, userID, state, in.Comment)
+	if err != nil {
+		c.Logger().Errorf("usecase: %v", err)
+		return handleUsecaseError(err)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+func handleUsecaseError(err error) *response.Error {
+	// Запрос не уложился в таймаут или был отменен.
+	if problem := response.ContextError(err); problem != nil {
+		return problem
+	}
+
+	// Не нашли табель.
+	if errors.Is(err, usecaseDto.ErrTimesheetNotFound) {
+		return response.NotFound("timesheet")
+	}
+	if errors.Is(err, usecaseDto.ErrForbidden) {
+		return response.Status(http.StatusForbidden)
+	}
+	if errors.Is(err, usecaseDto.ErrTimesheetExists) {
+		return response.NewError(
+			http.StatusConflict,
+			"timesheet_exists",
+			fmt.Sprintf("%s: %v", response.ErrorMsgsByCode[http.StatusConflict], err))
+	}
+	if errors.Is(err, usecaseDto.ErrInvalidTransition) {
+		return response.NewError(
+			http.StatusConflict,
+			"invalid_transition",
+			fmt.Sprintf("%s: %v", response.ErrorMsgsByCode[http.StatusConflict], err))
+	}
+
+	// По дефолту пятисотим.
+	return response.Status(http.StatusInternalServerError)
+}
+
+func convertFromUsecaseTimesheets(timesheets []usecaseDto.Timesheet) []TimesheetOut {
+	out := make([]TimesheetOut, 0, len(timesheets))
+	for _, t := range timesheets {
+		out = append(out, TimesheetOut{
+			ID:                 t.ID,
+			UserID:             t.UserID,
+			UserName:           t.UserName,
+			WorkspaceID:        t.WorkspaceID,
+			WeekStart:          t.WeekStart.Format(dateLayout),
+			State:              string(t.State),
+			Comment:            t.Comment,
+			ReviewerID:         t.ReviewerID,
+			SubmittedAt:        t.SubmittedAt,
+			ReviewedAt:         t.ReviewedAt,
+			TotalDurationInSec: t.TotalDurationInSec,
+		})
+	}
+
+	return out
+}
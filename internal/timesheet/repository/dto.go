@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+)
+
+type Timesheet struct {
+	ID          int64         `db:"id"`
+	UserID      int64         `db:"user_id"`
+	WorkspaceID int64         `db:"workspace_id"`
+	WeekStart   time.Time     `db:"week_start"`
+	State       string        `db:"state"`
+	Comment     string        `db:"comment"`
+	ReviewerID  sql.NullInt64 `db:"reviewer_id"`
+	SubmittedAt sql.NullTime  `db:"submitted_at"`
+	ReviewedAt  sql.NullTime  `db:"reviewed_at"`
+
+	// Поля только для чтения.
+	UserName           string  `db:"user_name"`
+	TotalDurationInSec float64 `db:"total_duration_in_sec"`
+}
+
+// StateChange переход табеля из одного состояния в другое.
+type StateChange struct {
+	TimesheetID int64
+	From        string
+	To          string
+	ReviewerID  sql.NullInt64
+	Comment     string
+}
@@ -0,0 +1,225 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrTimesheetNotFound = errors.New("timesheet not found")
+	ErrTimesheetExists   = errors.New("timesheet already exists")
+	ErrStateConflict     = errors.New("timesheet state was changed")
+)
+
+// Код ошибки postgres при нарушении уникальности.
+const uniqueViolationCode = "23505"
+
+// Формат даты для колонки week_start.
+const dateLayout = "2006-01-02"
+
+// Выборка табеля вместе с суммарным временем по проектам пространства за неделю.
+const selectTimesheets = `SELECT
+			t.id,
+			t.user_id,
+			t.workspace_id,
+			t.week_start,
+			t.state,
+			t.comment,
+			t.reviewer_id,
+			t.submitted_at,
+			t.reviewed_at,
+			u.name,
+			COALESCE((SELECT SUM(EXTRACT(EPOCH FROM e.time_end - e.time_start))
+				FROM entries e
+				JOIN projects p ON p.id = e.project_id
+				WHERE e.user_id = t.user_id
+				  AND p.workspace_id = t.workspace_id
+				  AND e.deleted_at IS NULL
+				  AND e.time_start >= t.week_start
+				  AND e.time_start < t.week_start + 7), 0)::float8
+		FROM timesheets t
+		JOIN users u ON u.id = t.user_id`
+
+type Repository struct {
+	db    *sqlx.DB
+	close func() error
+}
+
+func NewRepository(db *sqlx.DB) *Repository {
+	return &Repository{
+		db: db,
+		close: func() error {
+			return db.Close()
+		},
+	}
+}
+
+// Close закрывает пул соединений с базой.
+func (r *Repository) Close() error {
+	return r.close()
+}
+
+func (r *Repository) CreateTimesheet(ctx context.Context, timesheet Timesheet) (int64, error) {
+	query := `INSERT INTO timesheets
+				(
+					user_id,
+					workspace_id,
+					week_start
+				) VALUES ($1, $2, $3::date) RETURNING id;`
+
+	var id int64
+	err := r.db.QueryRowContext(ctx,
+		query,
+		timesheet.UserID,
+		timesheet.WorkspaceID,
+		timesheet.WeekStart.Format(dateLayout),
+	).Scan(&id)
+
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == uniqueViolationCode {
+			return 0, ErrTimesheetExists
+		}
+
+		return 0, fmt.Errorf("query row: %w", err)
+	}
+
+	return id, nil
+}
+
+func (r *Repository) GetTimesheet(ctx context.Context, timesheetID int64) (Timesheet, error) {
+	rows, err := r.db.QueryContext(ctx, selectTimesheets+` WHERE t.id = $1`, timesheetID)
+	if err != nil {
+		return Timesheet{}, fmt.Errorf("query: %w", err)
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	timesheets, err := scanTimesheets(rows)
+	if err != nil {
+		return Timesheet{}, err
+	}
+
+	return timesheets[0], nil
+}
+
+func (r *Repository) GetUserTimesheets(ctx context.Context, userID int64) ([]Timesheet, error) {
+	rows, err := r.db.QueryContext(ctx, selectTimesheets+` WHERE t.user_id = $1 ORDER BY t.week_start DESC`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	return scanTimesheets(rows)
+}
+
+// GetWorkspaceTimesheets возвращает табели пространства, пустой state означает любое состояние.
+func (r *Repository) GetWorkspaceTimesheets(ctx context.Context, workspaceID int64, state string) ([]Timesheet, error) {
+	rows, err := r.db.QueryContext(ctx, selectTimesheets+`
+		WHERE t.workspace_id = $1 AND ($2 = '' OR t.state::text = $2)
+		ORDER BY t.week_start DESC, t.user_id`, workspaceID, state)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	return scanTimesheets(rows)
+}
+
+// ChangeState переводит табель в новое состояние, только если он все еще в состоянии From.
+func (r *Repository) ChangeState(ctx context.Context, change StateChange) error {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE timesheets
+		SET state = $3::timesheet_state,
+			comment = $4,
+			reviewer_id = $5,
+			submitted_at = CASE WHEN $3::timesheet_state = 'submitted' THEN now() ELSE submitted_at END,
+			reviewed_at = CASE WHEN $3::timesheet_state IN ('approved', 'rejected') THEN now() ELSE reviewed_at END
+		WHERE id = $1 AND state = $2::timesheet_state`,
+		change.TimesheetID,
+		change.From,
+		change.To,
+		change.Comment,
+		change.ReviewerID,
+	)
+
+	if err != nil {
+		return fmt.Errorf("exec: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("rows affected: %w", err)
+	}
+
+	if affected == 0 {
+		return ErrStateConflict
+	}
+
+	return nil
+}
+
+// IsWeekApproved проверяет, утвержден ли табель пользователя в пространстве за неделю.
+func (r *Repository) IsWeekApproved(ctx context.Context, userID, workspaceID int64, weekStart time.Time) (bool, error) {
+	var approved bool
+	err := r.db.QueryRowContext(ctx,
+		`SELECT EXISTS(
+			SELECT 1
+			FROM timesheets
+			WHERE user_id = $1 AND workspace_id = $2 AND week_start = $3::date AND state = 'approved'
+		)`, userID, workspaceID, weekStart.Format(dateLayout)).Scan(&approved)
+
+	if err != nil {
+		return false, fmt.Errorf("scan: %w", err)
+	}
+
+	return approved, nil
+}
+
+func scanTimesheets(rows *sql.Rows) ([]Timesheet, error) {
+	var timesheets []Timesheet
+	for rows.Next() {
+		var t Timesheet
+		if err := rows.Scan(
+			&t.ID,
+			&t.UserID,
+			&t.WorkspaceID,
+			&t.WeekStart,
+			&t.State,
+			&t.Comment,
+			&t.ReviewerID,
+			&t.SubmittedAt,
+			&t.ReviewedAt,
+			&t.UserName,
+			&t.TotalDurationInSec,
+		); err != nil {
+			return nil, fmt.Errorf("scan: %w", err)
+		}
+
+		timesheets = append(timesheets, t)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("rows err: %w", rows.Err())
+	}
+
+	if len(timesheets) == 0 {
+		return nil, ErrTimesheetNotFound
+	}
+
+	return timesheets, nil
+}
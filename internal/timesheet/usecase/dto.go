@@ -0,0 +1,28 @@
+package usecase
+
+import "time"
+
+type State string
+
+const (
+	StateDraft     State = "draft"
+	StateSubmitted State = "submitted"
+	StateApproved  State = "approved"
+	StateRejected  State = "rejected"
+)
+
+type Timesheet struct {
+	ID          int64
+	UserID      int64
+	WorkspaceID int64
+	WeekStart   time.Time
+	State       State
+	Comment     string
+	ReviewerID  int64
+	SubmittedAt *time.Time
+	ReviewedAt  *time.Time
+
+	// Поля только для чтения.
+	UserName           string
+	TotalDurationInSec float64
+}
@@ -0,0 +1,251 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/roles"
+	repo "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/timesheet/repository"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/tracing"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/utils"
+	workspaceRepoDto "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/workspace/repository"
+)
+
+var (
+	ErrTimesheetNotFound = errors.New("timesheet not found")
+	ErrTimesheetExists   = errors.New("timesheet already exists")
+	ErrInvalidTransition = errors.New("invalid timesheet state transition")
+	ErrForbidden         = errors.New("forbidden")
+)
+
+// Допустимые переходы между состояниями табеля.
+var transitions = map[State][]State{
+	StateDraft:     {StateSubmitted},
+	StateSubmitted: {StateApproved, StateRejected},
+	StateRejected:  {StateSubmitted},
+}
+
+type repository interface {
+	CreateTimesheet(ctx context.Context, timesheet repo.Timesheet) (int64, error)
+	GetTimesheet(ctx context.Context, timesheetID int64) (repo.Timesheet, error)
+	GetUserTimesheets(ctx context.Context, userID int64) ([]repo.Timesheet, error)
+	GetWorkspaceTimesheets(ctx context.Context, workspaceID int64, state string) ([]repo.Timesheet, error)
+	ChangeState(ctx context.Context, change repo.StateChange) error
+}
+
+type workspaceRepository interface {
+	GetMemberRole(ctx context.Context, workspaceID, userID int64) (string, error)
+}
+
+type Usecase struct {
+	repository          repository
+	workspaceRepository workspaceRepository
+}
+
+func NewUsecase(repository repository, workspaceRepository workspaceRepository) *Usecase {
+	return &Usecase{
+		repository:          repository,
+		workspaceRepository: workspaceRepository,
+	}
+}
+
+// CreateTimesheet создает черновик табеля на неделю, в которую попадает WeekStart.
+func (u *Usecase) CreateTimesheet(ctx context.Context, timesheet Timesheet) (int64, error) {
+	ctx, span := tracing.Start(ctx, "timesheet.CreateTimesheet")
+	defer span.End()
+
+	if err := u.requireRole(ctx, timesheet.WorkspaceID, timesheet.UserID, roles.Member); err != nil {
+		return 0, err
+	}
+
+	id, err := u.repository.CreateTimesheet(ctx, repo.Timesheet{
+		UserID:      timesheet.UserID,
+		WorkspaceID: timesheet.WorkspaceID,
+		WeekStart:   utils.GetWeekStart(timesheet.WeekStart),
+	})
+	if err != nil {
+		if errors.Is(err, repo.ErrTimesheetExists) {
+			return 0, ErrTimesheetExists
+		}
+		return 0, fmt.Errorf("repo create timesheet: %w", err)
+	}
+
+	return id, nil
+}
+
+func (u *Usecase) GetUserTimesheets(ctx context.Context, userID int64) ([]Timesheet, error) {
+	ctx, span := tracing.Start(ctx, "timesheet.GetUserTimesheets")
+	defer span.End()
+
+	repoTimesheets, err := u.repository.GetUserTimesheets(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repo.ErrTimesheetNotFound) {
+			return []Timesheet{}, nil
+		}
+		return nil, fmt.Errorf("repo get user timesheets: %w", err)
+	}
+
+	return convertToTimesheets(repoTimesheets), nil
+}
+
+// GetWorkspaceTimesheets возвращает табели участников пространства, доступно admin и owner.
+func (u *Usecase) GetWorkspaceTimesheets(ctx context.Context, workspaceID, actorID int64, state State) ([]Timesheet, error) {
+	ctx, span := tracing.Start(ctx, "timesheet.GetWorkspaceTimesheets")
+	defer span.End()
+
+	if err := u.requireRole(ctx, workspaceID, actorID, roles.Admin); err != nil {
+		return nil, err
+	}
+
+	repoTimesheets, err := u.repository.GetWorkspaceTimesheets(ctx, workspaceID, string(state))
+	if err != nil {
+		if errors.Is(err, repo.ErrTimesheetNotFound) {
+			return []Timesheet{}, nil
+		}
+		return nil, fmt.Errorf("repo get workspace timesheets: %w", err)
+	}
+
+	return convertToTimesheets(repoTimesheets), nil
+}
+
+// Submit отправляет свой табель на утверждение.
+func (u *Usecase) Submit(ctx context.Context, timesheetID, userID int64) error {
+	ctx, span := tracing.Start(ctx, "timesheet.Submit")
+	defer span.End()
+
+	timesheet, err := u.getTimesheet(ctx, timesheetID)
+	if err != nil {
+		return err
+	}
+
+	if timesheet.UserID != userID {
+		return ErrTimesheetNotFound
+	}
+
+	return u.changeState(ctx, timesheet, StateSubmitted, 0, timesheet.Comment)
+}
+
+// Review утверждает или отклоняет табель участника пространства.
+// Рецензировать могут admin и owner, но не собственный табель.
+func (u *Usecase) Review(ctx context.Context, workspaceID, timesheetID, reviewerID int64, state State, comment string) error {
+	ctx, span := tracing.Start(ctx, "timesheet.Review")
+	defer span.End()
+
+	if state != StateApproved && state != StateRejected {
+		return ErrInvalidTransition
+	}
+
+	if err := u.requireRole(ctx, workspaceID, reviewerID, roles.Admin); err != nil {
+		return err
+	}
+
+	timesheet, err := u.getTimesheet(ctx, timesheetID)
+	if err != nil {
+		return err
+	}
+
+	if timesheet.WorkspaceID != workspaceID {
+		return ErrTimesheetNotFound
+	}
+
+	if timesheet.UserID == reviewerID {
+		return ErrForbidden
+	}
+
+	return u.changeState(ctx, timesheet, state, reviewerID, comment)
+}
+
+func (u *Usecase) changeState(ctx context.Context, timesheet repo.Timesheet, to State, reviewerID int64, comment string) error {
+	if !canTransit(State(timesheet.State), to) {
+		return ErrInvalidTransition
+	}
+
+	err := u.repository.ChangeState(ctx, repo.StateChange{
+		TimesheetID: timesheet.ID,
+		From:        timesheet.State,
+		To:          string(to),
+		ReviewerID:  sql.NullInt64{Int64: reviewerID, Valid: reviewerID != 0},
+		Comment:     comment,
+	})
+	if err != nil {
+		// Табель успели перевести в другое состояние.
+		if errors.Is(err, repo.ErrStateConflict) {
+			return ErrInvalidTransition
+		}
+		return fmt.Errorf("repo change state: %w", err)
+	}
+
+	return nil
+}
+
+func (u *Usecase) getTimesheet(ctx context.Context, timesheetID int64) (repo.Timesheet, error) {
+	timesheet, err := u.repository.GetTimesheet(ctx, timesheetID)
+	if err != nil {
+		if errors.Is(err, repo.ErrTimesheetNotFound) {
+			return repo.Timesheet{}, ErrTimesheetNotFound
+		}
+		return repo.Timesheet{}, fmt.Errorf("repo get timesheet: %w", err)
+	}
+
+	return timesheet, nil
+}
+
+func (u *Usecase) requireRole(ctx context.Context, workspaceID, userID int64, min roles.Role) error {
+	role, err := u.workspaceRepository.GetMemberRole(ctx, workspaceID, userID)
+	if err != nil {
+		if errors.Is(err, workspaceRepoDto.ErrMemberNotFound) {
+			return ErrForbidden
+		}
+		return fmt.Errorf("repo get member role: %w", err)
+	}
+
+	if !roles.Role(role).AtLeast(min) {
+		return ErrForbidden
+	}
+
+	return nil
+}
+
+func canTransit(from, to State) bool {
+	for _, s := range transitions[from] {
+		if s == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+func convertToTimesheets(timesheets []repo.Timesheet) []Timesheet {
+	res := make([]Timesheet, 0, len(timesheets))
+	for _, t := range timesheets {
+		res = append(res, convertToTimesheet(t))
+	}
+
+	return res
+}
+
+func convertToTimesheet(t repo.Timesheet) Timesheet {
+	timesheet := Timesheet{
+		ID:                 t.ID,
+		UserID:             t.UserID,
+		WorkspaceID:        t.WorkspaceID,
+		WeekStart:          t.WeekStart,
+		State:              State(t.State),
+		Comment:            t.Comment,
+		ReviewerID:         t.ReviewerID.Int64,
+		UserName:           t.UserName,
+		TotalDurationInSec: t.TotalDurationInSec,
+	}
+
+	if t.SubmittedAt.Valid {
+		timesheet.SubmittedAt = &t.SubmittedAt.Time
+	}
+	if t.ReviewedAt.Valid {
+		timesheet.ReviewedAt = &t.ReviewedAt.Time
+	}
+
+	return timesheet
+}
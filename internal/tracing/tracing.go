@@ -0,0 +1,17 @@
+// Package tracing создает span'ы слоя usecase. Span'ы HTTP-запросов создает мидлвара,
+// span'ы SQL-запросов — драйвер базы.
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/tracing"
+
+// Start начинает дочерний span операции name. Вызывающий обязан завершить его через span.End.
+func Start(ctx context.Context, name string) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name)
+}
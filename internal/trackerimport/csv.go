@@ -0,0 +1,160 @@
+package trackerimport
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Колонки детального отчета. Toggl Track и Clockify называют их одинаково
+// с точностью до регистра ("Start date" и "Start Date").
+const (
+	columnClient      = "client"
+	columnProject     = "project"
+	columnTask        = "task"
+	columnDescription = "description"
+	columnTags        = "tags"
+	columnBillable    = "billable"
+	columnStartDate   = "start date"
+	columnStartTime   = "start time"
+	columnEndDate     = "end date"
+	columnEndTime     = "end time"
+)
+
+var requiredColumns = []string{columnProject, columnStartDate, columnStartTime, columnEndDate, columnEndTime}
+
+// Форматы даты и времени зависят от настроек пользователя в трекере.
+// Дата через слэш — американский формат месяц/день, как по умолчанию в обоих трекерах.
+var (
+	dateLayouts = []string{"2006-01-02", "01/02/2006", "02.01.2006"}
+	timeLayouts = []string{"15:04:05", "15:04", "3:04:05 PM", "3:04 PM", "03:04:05 PM", "03:04 PM"}
+)
+
+// parseCSV разбирает CSV детального отчета Toggl Track или Clockify.
+func parseCSV(r io.Reader, loc *time.Location) ([]Record, []Skip, error) {
+	br := bufio.NewReader(r)
+
+	reader := csv.NewReader(br)
+	reader.Comma = detectDelimiter(br)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: read header: %v", ErrInvalidFile, err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		// Excel добавляет BOM в начало UTF-8 файла.
+		if i == 0 {
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	for _, name := range requiredColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, nil, fmt.Errorf("%w: column %q not found in header", ErrInvalidFile, name)
+		}
+	}
+
+	var records []Record
+	skipped := []Skip{}
+
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, nil, fmt.Errorf("read csv: %v", err)
+			}
+
+			skipped = append(skipped, Skip{Row: parseErr.StartLine, Reason: parseErr.Err.Error()})
+			continue
+		}
+
+		line, _ := reader.FieldPos(0)
+		field := func(name string) string {
+			idx, ok := columns[name]
+			if !ok || idx >= len(row) {
+				return ""
+			}
+			return strings.TrimSpace(row[idx])
+		}
+
+		record := Record{
+			Row:         line,
+			Client:      field(columnClient),
+			Project:     field(columnProject),
+			Description: field(columnDescription),
+			Tags:        splitTags(field(columnTags)),
+			Billable:    parseBillable(field(columnBillable)),
+		}
+		if record.Description == "" {
+			record.Description = field(columnTask)
+		}
+
+		record.Start, err = parseDateTime(field(columnStartDate), field(columnStartTime), loc)
+		if err != nil {
+			skipped = append(skipped, Skip{Row: line, Reason: fmt.Sprintf("invalid time start: %v", err)})
+			continue
+		}
+
+		// У запущенного таймера нет окончания.
+		if field(columnEndDate) != "" || field(columnEndTime) != "" {
+			record.End, err = parseDateTime(field(columnEndDate), field(columnEndTime), loc)
+			if err != nil {
+				skipped = append(skipped, Skip{Row: line, Reason: fmt.Sprintf("invalid time end: %v", err)})
+				continue
+			}
+		}
+
+		if reason := validate(record); reason != "" {
+			skipped = append(skipped, Skip{Row: line, Reason: reason})
+			continue
+		}
+
+		record.ExternalID = contentID(record)
+		records = append(records, record)
+	}
+
+	return records, skipped, nil
+}
+
+// detectDelimiter определяет разделитель по первой строке: при русской локали
+// выгрузки сохраняются через точку с запятой.
+func detectDelimiter(br *bufio.Reader) rune {
+	// Ошибку не проверяем: короткий файл вернет все, что есть.
+	head, _ := br.Peek(4096)
+	if idx := bytes.IndexByte(head, '\n'); idx >= 0 {
+		head = head[:idx]
+	}
+
+	if bytes.Count(head, []byte{';'}) > bytes.Count(head, []byte{','}) {
+		return ';'
+	}
+
+	return ','
+}
+
+func parseDateTime(date, clock string, loc *time.Location) (time.Time, error) {
+	for _, dateLayout := range dateLayouts {
+		for _, timeLayout := range timeLayouts {
+			t, err := time.ParseInLocation(dateLayout+" "+timeLayout, date+" "+clock, loc)
+			if err == nil {
+				return t, nil
+			}
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("unsupported date and time %q %q", date, clock)
+}
@@ -0,0 +1,219 @@
+package trackerimport
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/ical"
+)
+
+// SourceICal источник записей, импортированных из календаря.
+const SourceICal = "ical"
+
+// ICalRule правило выбора проекта для события. Пустое условие выполняется для любого события,
+// сравнение без учета регистра.
+type ICalRule struct {
+	// Название календаря (X-WR-CALNAME).
+	Calendar string
+	// Одна из категорий события.
+	Category string
+	// Подстрока заголовка события.
+	SummaryContains string
+	Project         string
+}
+
+type ICalSpec struct {
+	// Применяется первое подходящее правило.
+	Rules []ICalRule
+	// Проект для событий, к которым не подошло ни одно правило; пустой — такие события пропускаются.
+	DefaultProject string
+	// Участник, от лица которого импортируются события: отклоненные им события пропускаются.
+	AttendeeEmail string
+	// Импортируются вхождения с началом в [From, To), нулевой From не ограничивает выборку.
+	From time.Time
+	To   time.Time
+	// Часовой пояс времени без смещения и с неизвестным TZID.
+	Location *time.Location
+}
+
+// ParseICal разбирает календарь и разворачивает повторяющиеся события в записи.
+// Каждое вхождение получает идентификатор из UID события и исходного времени вхождения,
+// поэтому повторный импорт того же календаря не создает дублей. События на весь день,
+// отмененные и отклоненные пропускаются.
+func ParseICal(r io.Reader, spec ICalSpec) ([]Record, []Skip, error) {
+	loc := spec.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	cal, err := ical.Parse(r, loc)
+	if err != nil {
+		if errors.Is(err, ical.ErrInvalidCalendar) {
+			return nil, nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+		}
+		return nil, nil, err
+	}
+
+	// Измененные вхождения повторяющихся событий: UID -> исходное время вхождения.
+	overridden := make(map[string]map[int64]struct{})
+	for _, event := range cal.Events {
+		if event.RecurrenceID.IsZero() {
+			continue
+		}
+		if overridden[event.UID] == nil {
+			overridden[event.UID] = make(map[int64]struct{})
+		}
+		overridden[event.UID][event.RecurrenceID.Unix()] = struct{}{}
+	}
+
+	var records []Record
+	skipped := []Skip{}
+
+	for _, event := range cal.Events {
+		skip := func(reason string) {
+			skipped = append(skipped, Skip{Row: event.Index, Reason: reason})
+		}
+
+		if reason := eventSkipReason(event, spec.AttendeeEmail); reason != "" {
+			skip(reason)
+			continue
+		}
+
+		project := matchProject(cal.Name, event, spec)
+		if project == "" {
+			skip("no rule matches the event")
+			continue
+		}
+
+		var starts []time.Time
+		switch {
+		case !event.RecurrenceID.IsZero() || event.RRule == "":
+			if inWindow(event.Start, spec) {
+				starts = []time.Time{event.Start}
+			}
+		default:
+			rule, err := ical.ParseRRule(event.RRule, loc)
+			if err != nil {
+				skip(fmt.Sprintf("unsupported recurrence rule: %v", err))
+				continue
+			}
+
+			starts = rule.Expand(event.Start, spec.From, spec.To)
+		}
+
+		duration := event.End.Sub(event.Start)
+		for _, start := range starts {
+			// Вхождение отменено (EXDATE) или изменено отдельным событием с RECURRENCE-ID.
+			if event.RecurrenceID.IsZero() && event.RRule != "" {
+				if _, ok := overridden[event.UID][start.Unix()]; ok || isExcluded(start, event.ExDates) {
+					continue
+				}
+			}
+
+			record := Record{
+				Row:         event.Index,
+				ExternalID:  occurrenceID(event, start),
+				Project:     project,
+				Description: event.Summary,
+				Tags:        append([]string{}, event.Categories...),
+				Start:       start,
+				End:         start.Add(duration),
+			}
+
+			if reason := validate(record); reason != "" {
+				skip(reason)
+				continue
+			}
+
+			records = append(records, record)
+		}
+	}
+
+	return records, skipped, nil
+}
+
+func eventSkipReason(event ical.ParsedEvent, attendeeEmail string) string {
+	if event.UID == "" {
+		return "event has no UID"
+	}
+	if event.AllDay {
+		return "all-day event"
+	}
+	if event.Status == "CANCELLED" {
+		return "event is cancelled"
+	}
+
+	if attendeeEmail != "" {
+		for _, a := range event.Attendees {
+			if strings.EqualFold(a.Email, attendeeEmail) && a.PartStat == "DECLINED" {
+				return "event is declined"
+			}
+		}
+	}
+
+	return ""
+}
+
+func matchProject(calendarName string, event ical.ParsedEvent, spec ICalSpec) string {
+	for _, rule := range spec.Rules {
+		if rule.Calendar != "" && !strings.EqualFold(rule.Calendar, calendarName) {
+			continue
+		}
+		if rule.SummaryContains != "" &&
+			!strings.Contains(strings.ToLower(event.Summary), strings.ToLower(rule.SummaryContains)) {
+			continue
+		}
+		if rule.Category != "" && !hasCategory(event.Categories, rule.Category) {
+			continue
+		}
+
+		return rule.Project
+	}
+
+	return spec.DefaultProject
+}
+
+func hasCategory(categories []string, category string) bool {
+	for _, c := range categories {
+		if strings.EqualFold(c, category) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func inWindow(t time.Time, spec ICalSpec) bool {
+	return !t.Before(spec.From) && t.Before(spec.To)
+}
+
+func isExcluded(t time.Time, exDates []time.Time) bool {
+	for _, ex := range exDates {
+		if ex.Equal(t) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// occurrenceID идентификатор вхождения: UID длиннее колонки external_id, поэтому хешируется.
+// Для вхождения повторяющегося события добавляется его исходное время.
+func occurrenceID(event ical.ParsedEvent, start time.Time) string {
+	key := event.UID
+	switch {
+	case !event.RecurrenceID.IsZero():
+		key += "|" + strconv.FormatInt(event.RecurrenceID.Unix(), 10)
+	case event.RRule != "":
+		key += "|" + strconv.FormatInt(start.Unix(), 10)
+	}
+
+	sum := sha1.Sum([]byte(key))
+	return "sha1:" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,172 @@
+package trackerimport
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func utc(day, hour, minute int) time.Time {
+	return time.Date(2024, 3, day, hour, minute, 0, 0, time.UTC)
+}
+
+func TestParseICal(t *testing.T) {
+	rules := []ICalRule{
+		{Category: "meetings", Project: "Meetings"},
+		{Calendar: "Personal", Project: "Personal"},
+		{Calendar: "work", SummaryContains: "ACME", Project: "Acme"},
+	}
+
+	type occurrence struct {
+		row     int
+		project string
+		summary string
+		start   time.Time
+		end     time.Time
+	}
+
+	tests := []struct {
+		name    string
+		spec    ICalSpec
+		want    []occurrence
+		skipped []Skip
+	}{
+		{
+			name: "rules and attendee",
+			spec: ICalSpec{Rules: rules, AttendeeEmail: "anna@example.com"},
+			want: []occurrence{
+				// 6 марта исключено EXDATE, 8 марта перенесено отдельным событием.
+				{1, "Meetings", "Standup", utc(4, 7, 0), utc(4, 7, 15)},
+				{1, "Meetings", "Standup", utc(11, 7, 0), utc(11, 7, 15)},
+				{1, "Meetings", "Standup", utc(13, 7, 0), utc(13, 7, 15)},
+				{1, "Meetings", "Standup", utc(15, 7, 0), utc(15, 7, 15)},
+				{2, "Meetings", "Standup (moved)", utc(8, 9, 0), utc(8, 9, 30)},
+				{6, "Acme", "Acme: weekly call", utc(5, 14, 0), utc(5, 15, 0)},
+			},
+			skipped: []Skip{
+				{Row: 3, Reason: "all-day event"},
+				{Row: 4, Reason: "event is declined"},
+				{Row: 5, Reason: "event is cancelled"},
+				{Row: 7, Reason: "time end must be after time start"},
+				{Row: 8, Reason: "event has no UID"},
+				// Правила проверяются раньше окна, поэтому старое событие тоже попадает в пропуски.
+				{Row: 9, Reason: "no rule matches the event"},
+			},
+		},
+		{
+			name: "default project without attendee",
+			spec: ICalSpec{DefaultProject: "Other"},
+			want: []occurrence{
+				{1, "Other", "Standup", utc(4, 7, 0), utc(4, 7, 15)},
+				{1, "Other", "Standup", utc(11, 7, 0), utc(11, 7, 15)},
+				{1, "Other", "Standup", utc(13, 7, 0), utc(13, 7, 15)},
+				{1, "Other", "Standup", utc(15, 7, 0), utc(15, 7, 15)},
+				{2, "Other", "Standup (moved)", utc(8, 9, 0), utc(8, 9, 30)},
+				// Отклоненные события пропускаются, только если известен участник.
+				{4, "Other", "Sales sync", utc(5, 10, 0), utc(5, 11, 0)},
+				{6, "Other", "Acme: weekly call", utc(5, 14, 0), utc(5, 15, 0)},
+			},
+			skipped: []Skip{
+				{Row: 3, Reason: "all-day event"},
+				{Row: 5, Reason: "event is cancelled"},
+				{Row: 7, Reason: "time end must be after time start"},
+				{Row: 8, Reason: "event has no UID"},
+			},
+		},
+		{
+			name: "no matching rule",
+			spec: ICalSpec{Rules: []ICalRule{{Calendar: "Personal", Project: "Personal"}}, AttendeeEmail: "anna@example.com"},
+			skipped: []Skip{
+				{Row: 1, Reason: "no rule matches the event"},
+				{Row: 2, Reason: "no rule matches the event"},
+				{Row: 3, Reason: "all-day event"},
+				{Row: 4, Reason: "event is declined"},
+				{Row: 5, Reason: "event is cancelled"},
+				{Row: 6, Reason: "no rule matches the event"},
+				{Row: 7, Reason: "no rule matches the event"},
+				{Row: 8, Reason: "event has no UID"},
+				{Row: 9, Reason: "no rule matches the event"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Событие 9 раньше окна и с проектом не попадает ни в записи, ни в пропуски.
+			tt.spec.From = utc(1, 0, 0)
+			tt.spec.To = utc(31, 0, 0)
+
+			records, skipped, err := ParseICal(openTestdata(t, "calendar.ics"), tt.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []occurrence
+			for _, r := range records {
+				got = append(got, occurrence{r.Row, r.Project, r.Description, r.Start, r.End})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("records:\ngot  %+v\nwant %+v", got, tt.want)
+			}
+			if !reflect.DeepEqual(skipped, tt.skipped) {
+				t.Errorf("skipped:\ngot  %+v\nwant %+v", skipped, tt.skipped)
+			}
+		})
+	}
+}
+
+// Идентификаторы вхождений стабильны, а перенесенное вхождение сохраняет идентификатор исходного,
+// поэтому повторный импорт календаря не создает дублей.
+func TestParseICalOccurrenceIDs(t *testing.T) {
+	spec := ICalSpec{DefaultProject: "Other", From: utc(1, 0, 0), To: utc(31, 0, 0)}
+
+	first, _, err := ParseICal(openTestdata(t, "calendar.ics"), spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	seen := make(map[string]struct{}, len(first))
+	for _, r := range first {
+		if _, ok := seen[r.ExternalID]; ok {
+			t.Errorf("duplicate external id %q", r.ExternalID)
+		}
+		seen[r.ExternalID] = struct{}{}
+	}
+
+	// Календарь без переноса: вхождение 8 марта на исходном месте.
+	spec.To = utc(9, 0, 0)
+	original := strings.NewReader("BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nUID:standup@example.com\r\n" +
+		"DTSTART:20240304T070000Z\r\nDTEND:20240304T071500Z\r\nRRULE:FREQ=WEEKLY;BYDAY=MO,WE,FR;COUNT=6\r\n" +
+		"END:VEVENT\r\nEND:VCALENDAR\r\n")
+	second, _, err := ParseICal(original, spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var moved, before Record
+	for _, r := range first {
+		if r.Description == "Standup (moved)" {
+			moved = r
+		}
+	}
+	for _, r := range second {
+		if r.Start.Equal(utc(8, 7, 0)) {
+			before = r
+		}
+	}
+	if moved.ExternalID == "" || moved.ExternalID != before.ExternalID {
+		t.Errorf("moved occurrence id %q, original occurrence id %q", moved.ExternalID, before.ExternalID)
+	}
+	if _, ok := seen[second[0].ExternalID]; !ok {
+		t.Errorf("first occurrence got a new id %q on re-import", second[0].ExternalID)
+	}
+}
+
+func TestParseICalInvalid(t *testing.T) {
+	_, _, err := ParseICal(strings.NewReader("not a calendar"), ICalSpec{})
+	if !errors.Is(err, ErrInvalidFile) {
+		t.Errorf("got error %v, want %v", err, ErrInvalidFile)
+	}
+}
@@ -0,0 +1,112 @@
+package trackerimport
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Поддерживаемые трекеры, значения сохраняются в entries.external_source.
+const (
+	SourceToggl    = "toggl"
+	SourceClockify = "clockify"
+)
+
+var (
+	ErrUnknownSource = errors.New("unknown import source")
+	ErrInvalidFile   = errors.New("invalid export file")
+)
+
+// Record запись времени из выгрузки другого трекера.
+type Record struct {
+	// Номер строки CSV (заголовок — строка 1) или порядковый номер записи JSON, начиная с 1.
+	Row int
+	// Идентификатор записи в трекере. Если выгрузка его не содержит, вычисляется
+	// по содержимому записи, чтобы повторный импорт того же файла не создавал дублей.
+	ExternalID  string
+	Client      string
+	Project     string
+	Description string
+	Tags        []string
+	Billable    bool
+	Start       time.Time
+	End         time.Time
+}
+
+// Skip строка выгрузки, которая не может быть импортирована.
+type Skip struct {
+	Row    int
+	Reason string
+}
+
+// Parse разбирает выгрузку трекера source. Время без смещения считается временем loc.
+// Строки, которые нельзя импортировать, возвращаются в skipped, ошибка — только если файл не разобрать.
+func Parse(source string, r io.Reader, loc *time.Location) (records []Record, skipped []Skip, err error) {
+	switch source {
+	case SourceToggl:
+		return parseToggl(r, loc)
+	case SourceClockify:
+		return parseCSV(r, loc)
+	default:
+		return nil, nil, fmt.Errorf("%w: %q", ErrUnknownSource, source)
+	}
+}
+
+// contentID идентификатор записи по ее содержимому для выгрузок без идентификаторов.
+func contentID(record Record) string {
+	h := sha1.New()
+	for _, part := range []string{
+		record.Client,
+		record.Project,
+		record.Description,
+		strconv.FormatInt(record.Start.Unix(), 10),
+		strconv.FormatInt(record.End.Unix(), 10),
+	} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+
+	return "sha1:" + hex.EncodeToString(h.Sum(nil))
+}
+
+// splitTags разбирает список тегов через запятую.
+func splitTags(s string) []string {
+	tags := []string{}
+	for _, tag := range strings.Split(s, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+
+	return tags
+}
+
+// parseBillable разбирает признак оплачиваемости: Toggl и Clockify пишут Yes/No.
+func parseBillable(s string) bool {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "yes", "true", "1", "да":
+		return true
+	default:
+		return false
+	}
+}
+
+// validate общие для всех выгрузок проверки записи, возвращает причину пропуска.
+func validate(record Record) string {
+	if record.Project == "" {
+		return "entry has no project"
+	}
+	if record.End.IsZero() {
+		return "entry is still running"
+	}
+	if !record.End.After(record.Start) {
+		return "time end must be after time start"
+	}
+
+	return ""
+}
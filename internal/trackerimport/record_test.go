@@ -0,0 +1,297 @@
+package trackerimport
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var msk = time.FixedZone("MSK", 3*60*60)
+
+func openTestdata(t *testing.T, name string) *os.File {
+	t.Helper()
+
+	f, err := os.Open(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("open %s: %v", name, err)
+	}
+	t.Cleanup(func() {
+		_ = f.Close()
+	})
+
+	return f
+}
+
+func parseTestdata(t *testing.T, source, name string) ([]Record, []Skip) {
+	t.Helper()
+
+	records, skipped, err := Parse(source, openTestdata(t, name), msk)
+	if err != nil {
+		t.Fatalf("parse %s: %v", name, err)
+	}
+
+	return records, skipped
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		file    string
+		want    []Record
+		skipped []Skip
+	}{
+		{
+			name:   "toggl detailed csv",
+			source: SourceToggl,
+			file:   "toggl_detailed.csv",
+			want: []Record{
+				{
+					Row:         2,
+					Client:      "Acme",
+					Project:     "Website",
+					Description: "Landing page",
+					Tags:        []string{"design", "frontend"},
+					Billable:    true,
+					Start:       time.Date(2024, 3, 4, 9, 0, 0, 0, msk),
+					End:         time.Date(2024, 3, 4, 10, 30, 0, 0, msk),
+				},
+				{
+					Row:         3,
+					Project:     "Internal",
+					Description: "Standup",
+					Tags:        []string{},
+					Start:       time.Date(2024, 3, 4, 11, 0, 0, 0, msk),
+					End:         time.Date(2024, 3, 4, 11, 15, 0, 0, msk),
+				},
+			},
+			skipped: []Skip{{Row: 4, Reason: "entry is still running"}},
+		},
+		{
+			name:   "clockify csv with semicolons, BOM and 12-hour clock",
+			source: SourceClockify,
+			file:   "clockify_detailed.csv",
+			want: []Record{
+				{
+					Row:         2,
+					Client:      "Acme",
+					Project:     "Website",
+					Description: "Review",
+					Tags:        []string{"backend"},
+					Billable:    true,
+					Start:       time.Date(2024, 3, 5, 13, 0, 0, 0, msk),
+					End:         time.Date(2024, 3, 5, 14, 30, 0, 0, msk),
+				},
+				{
+					Row:         3,
+					Project:     "Internal",
+					Description: "Planning",
+					Tags:        []string{},
+					Start:       time.Date(2024, 3, 5, 23, 30, 0, 0, msk),
+					End:         time.Date(2024, 3, 6, 0, 30, 0, 0, msk),
+				},
+			},
+			skipped: []Skip{},
+		},
+		{
+			name:   "toggl reports api json",
+			source: SourceToggl,
+			file:   "toggl_report.json",
+			want: []Record{
+				{
+					Row:         1,
+					ExternalID:  "3104458001",
+					Client:      "Acme",
+					Project:     "Website",
+					Description: "Landing page",
+					Tags:        []string{"design", "frontend"},
+					Billable:    true,
+					Start:       time.Date(2024, 3, 4, 9, 0, 0, 0, msk),
+					End:         time.Date(2024, 3, 4, 10, 30, 0, 0, msk),
+				},
+				{
+					Row:         2,
+					ExternalID:  "3104458002",
+					Project:     "Internal",
+					Description: "Standup",
+					Tags:        []string{},
+					Start:       time.Date(2024, 3, 4, 11, 0, 0, 0, msk),
+					End:         time.Date(2024, 3, 4, 11, 15, 0, 0, msk),
+				},
+			},
+			skipped: []Skip{},
+		},
+		{
+			name:   "toggl track api json",
+			source: SourceToggl,
+			file:   "toggl_entries.json",
+			want: []Record{
+				{
+					Row:         1,
+					ExternalID:  "3104458101",
+					Client:      "Acme",
+					Project:     "Website",
+					Description: "Code review",
+					Tags:        []string{"backend"},
+					Billable:    true,
+					Start:       time.Date(2024, 3, 6, 7, 0, 0, 0, time.UTC),
+					End:         time.Date(2024, 3, 6, 8, 0, 0, 0, time.UTC),
+				},
+				{
+					Row:         3,
+					Project:     "Internal",
+					Description: "Imported without id",
+					Tags:        []string{},
+					Start:       time.Date(2024, 3, 6, 10, 0, 0, 0, time.UTC),
+					End:         time.Date(2024, 3, 6, 10, 45, 0, 0, time.UTC),
+				},
+			},
+			skipped: []Skip{{Row: 2, Reason: "entry is still running"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, skipped := parseTestdata(t, tt.source, tt.file)
+
+			if len(records) != len(tt.want) {
+				t.Fatalf("got %d records, want %d: %+v", len(records), len(tt.want), records)
+			}
+
+			for i, want := range tt.want {
+				got := records[i]
+				// Идентификаторы по содержимому проверяются отдельно.
+				if want.ExternalID == "" {
+					if !strings.HasPrefix(got.ExternalID, "sha1:") {
+						t.Errorf("record %d: external id %q is not a content id", i, got.ExternalID)
+					}
+					want.ExternalID = got.ExternalID
+				}
+
+				if !got.Start.Equal(want.Start) || !got.End.Equal(want.End) {
+					t.Errorf("record %d: got %v - %v, want %v - %v", i, got.Start, got.End, want.Start, want.End)
+				}
+				got.Start, got.End = want.Start, want.End
+
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("record %d:\ngot  %+v\nwant %+v", i, got, want)
+				}
+			}
+
+			if !reflect.DeepEqual(skipped, tt.skipped) {
+				t.Errorf("skipped:\ngot  %+v\nwant %+v", skipped, tt.skipped)
+			}
+		})
+	}
+}
+
+func TestParseMalformedRows(t *testing.T) {
+	records, skipped := parseTestdata(t, SourceClockify, "malformed.csv")
+
+	if len(records) != 1 || records[0].Description != "Valid row" {
+		t.Fatalf("got records %+v, want only the valid row", records)
+	}
+
+	want := []struct {
+		row    int
+		reason string
+	}{
+		{3, "invalid time start"},
+		{4, "invalid time end"},
+		{5, "entry has no project"},
+		{6, "time end must be after time start"},
+		{7, `extraneous or missing " in quoted-field`},
+	}
+
+	if len(skipped) != len(want) {
+		t.Fatalf("got %d skipped rows, want %d: %+v", len(skipped), len(want), skipped)
+	}
+
+	for i, w := range want {
+		if skipped[i].Row != w.row || !strings.HasPrefix(skipped[i].Reason, w.reason) {
+			t.Errorf("skip %d: got row %d %q, want row %d %q", i, skipped[i].Row, skipped[i].Reason, w.row, w.reason)
+		}
+	}
+}
+
+func TestParseInvalidFile(t *testing.T) {
+	missingColumn, err := os.ReadFile(filepath.Join("testdata", "missing_column.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		source  string
+		input   string
+		wantErr error
+	}{
+		{"unknown source", "harvest", "Project\n", ErrUnknownSource},
+		{"empty file", SourceClockify, "", ErrInvalidFile},
+		{"missing column", SourceClockify, string(missingColumn), ErrInvalidFile},
+		{"broken json", SourceToggl, `[{"id": 1,`, ErrInvalidFile},
+		{"json of wrong shape", SourceToggl, `{"data": {"id": 1}}`, ErrInvalidFile},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := Parse(tt.source, strings.NewReader(tt.input), msk)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("got error %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// Повторный разбор того же файла дает те же идентификаторы, по ним импорт отбрасывает дубли.
+func TestExternalIDsAreStable(t *testing.T) {
+	for _, file := range []string{"toggl_detailed.csv", "clockify_detailed.csv", "toggl_entries.json"} {
+		t.Run(file, func(t *testing.T) {
+			source := SourceToggl
+			if strings.HasPrefix(file, "clockify") {
+				source = SourceClockify
+			}
+
+			first, _ := parseTestdata(t, source, file)
+			second, _ := parseTestdata(t, source, file)
+
+			seen := make(map[string]struct{}, len(first))
+			for i := range first {
+				if first[i].ExternalID != second[i].ExternalID {
+					t.Errorf("record %d: external id changed: %q != %q", i, first[i].ExternalID, second[i].ExternalID)
+				}
+				if _, ok := seen[first[i].ExternalID]; ok {
+					t.Errorf("record %d: external id %q is not unique", i, first[i].ExternalID)
+				}
+				seen[first[i].ExternalID] = struct{}{}
+			}
+		})
+	}
+}
+
+func TestContentIDDependsOnContent(t *testing.T) {
+	record := Record{
+		Project:     "Website",
+		Description: "Landing page",
+		Start:       time.Date(2024, 3, 4, 9, 0, 0, 0, msk),
+		End:         time.Date(2024, 3, 4, 10, 0, 0, 0, msk),
+	}
+
+	moved := record
+	moved.End = moved.End.Add(time.Minute)
+
+	// Разделитель полей не дает склеить соседние поля в то же значение.
+	shifted := record
+	shifted.Project, shifted.Description = "WebsiteLanding", " page"
+
+	if contentID(record) == contentID(moved) || contentID(record) == contentID(shifted) {
+		t.Error("different records got the same content id")
+	}
+	if got := len(contentID(record)); got > 64 {
+		t.Errorf("content id is %d characters long, entries.external_id fits 64", got)
+	}
+}
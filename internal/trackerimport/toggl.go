@@ -0,0 +1,144 @@
+package trackerimport
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// togglEntry запись времени Toggl в JSON. Поля покрывают детальный отчет Reports API
+// ("data", "project", "client", "end", "is_billable") и записи Track API v9 с meta=true
+// ("project_name", "client_name", "stop", "billable").
+type togglEntry struct {
+	ID          int64    `json:"id"`
+	Description string   `json:"description"`
+	Start       string   `json:"start"`
+	End         *string  `json:"end"`
+	Stop        *string  `json:"stop"`
+	Project     string   `json:"project"`
+	ProjectName string   `json:"project_name"`
+	Client      string   `json:"client"`
+	ClientName  string   `json:"client_name"`
+	Tags        []string `json:"tags"`
+	Billable    bool     `json:"billable"`
+	IsBillable  bool     `json:"is_billable"`
+}
+
+type togglReport struct {
+	Data []togglEntry `json:"data"`
+}
+
+// parseToggl разбирает выгрузку Toggl Track: JSON (массив записей или отчет с полем data)
+// либо CSV детального отчета.
+func parseToggl(r io.Reader, loc *time.Location) ([]Record, []Skip, error) {
+	br := bufio.NewReader(r)
+
+	first, err := firstNonSpace(br)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+	}
+
+	var entries []togglEntry
+	switch first {
+	case '[':
+		err = json.NewDecoder(br).Decode(&entries)
+	case '{':
+		var report togglReport
+		err = json.NewDecoder(br).Decode(&report)
+		entries = report.Data
+	default:
+		return parseCSV(br, loc)
+	}
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: decode json: %v", ErrInvalidFile, err)
+	}
+
+	var records []Record
+	skipped := []Skip{}
+
+	for i, entry := range entries {
+		row := i + 1
+
+		record := Record{
+			Row:         row,
+			ExternalID:  strconv.FormatInt(entry.ID, 10),
+			Client:      firstNonEmpty(entry.Client, entry.ClientName),
+			Project:     firstNonEmpty(entry.Project, entry.ProjectName),
+			Description: entry.Description,
+			Tags:        entry.Tags,
+			Billable:    entry.Billable || entry.IsBillable,
+		}
+		if record.Tags == nil {
+			record.Tags = []string{}
+		}
+
+		record.Start, err = time.Parse(time.RFC3339, entry.Start)
+		if err != nil {
+			skipped = append(skipped, Skip{Row: row, Reason: fmt.Sprintf("invalid time start: %v", err)})
+			continue
+		}
+
+		// У запущенного таймера окончание null.
+		if end := firstNonNil(entry.End, entry.Stop); end != nil {
+			record.End, err = time.Parse(time.RFC3339, *end)
+			if err != nil {
+				skipped = append(skipped, Skip{Row: row, Reason: fmt.Sprintf("invalid time end: %v", err)})
+				continue
+			}
+		}
+
+		if reason := validate(record); reason != "" {
+			skipped = append(skipped, Skip{Row: row, Reason: reason})
+			continue
+		}
+
+		if entry.ID == 0 {
+			record.ExternalID = contentID(record)
+		}
+		records = append(records, record)
+	}
+
+	return records, skipped, nil
+}
+
+// firstNonSpace возвращает первый значимый байт, не извлекая его из br.
+func firstNonSpace(br *bufio.Reader) (byte, error) {
+	for {
+		b, err := br.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+
+		// BOM UTF-8 пропускаем вместе с пробелами.
+		if bytes.IndexByte([]byte(" \t\r\n\xef\xbb\xbf"), b) >= 0 {
+			continue
+		}
+
+		return b, br.UnreadByte()
+	}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+
+	return ""
+}
+
+func firstNonNil(values ...*string) *string {
+	for _, v := range values {
+		if v != nil && *v != "" {
+			return v
+		}
+	}
+
+	return nil
+}
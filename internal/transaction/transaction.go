@@ -0,0 +1,102 @@
+package transaction
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type txKey struct{}
+
+// Executor выполняет запросы в транзакции из контекста или, если ее нет, напрямую в базе.
+type Executor interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+}
+
+// Manager выполняет функции в транзакции, которую репозитории берут из контекста.
+// Так изменение данных и связанные с ним записи (журнал, outbox) сохраняются атомарно.
+type Manager struct {
+	db *sqlx.DB
+}
+
+func NewManager(db *sqlx.DB) *Manager {
+	return &Manager{db: db}
+}
+
+// WithinTx выполняет fn в транзакции и фиксирует ее, если fn не вернула ошибку.
+// Вложенный вызов продолжает внешнюю транзакцию.
+func (m *Manager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*sqlx.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := m.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if err = fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+
+	return nil
+}
+
+// GetExecutor возвращает транзакцию из контекста или db.
+func GetExecutor(ctx context.Context, db *sqlx.DB) Executor {
+	if tx, ok := ctx.Value(txKey{}).(*sqlx.Tx); ok {
+		return tx
+	}
+
+	return db
+}
+
+// Tx транзакция репозитория. Внутри транзакции из контекста Commit и Rollback ничего не делают:
+// транзакцию завершает тот, кто ее начал.
+type Tx struct {
+	*sqlx.Tx
+	nested bool
+}
+
+// Begin начинает транзакцию или продолжает транзакцию из контекста.
+func Begin(ctx context.Context, db *sqlx.DB) (*Tx, error) {
+	if tx, ok := ctx.Value(txKey{}).(*sqlx.Tx); ok {
+		return &Tx{Tx: tx, nested: true}, nil
+	}
+
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Tx{Tx: tx}, nil
+}
+
+func (t *Tx) Commit() error {
+	if t.nested {
+		return nil
+	}
+
+	return t.Tx.Commit()
+}
+
+func (t *Tx) Rollback() error {
+	if t.nested {
+		return nil
+	}
+
+	return t.Tx.Rollback()
+}
@@ -0,0 +1,40 @@
+package delivery
+
+import "time"
+
+type TrashOut struct {
+	Entries  []DeletedEntryOut   `json:"entries"`  // Удаленные записи времени.
+	Projects []DeletedProjectOut `json:"projects"` // Удаленные проекты.
+	Goals    []DeletedGoalOut    `json:"goals"`    // Удаленные цели.
+}
+
+type DeletedEntryOut struct {
+	ID          int64     `json:"id" example:"1"`                            // Идентификатор записи.
+	ProjectID   int64     `json:"project_id" example:"1"`                    // Идентификатор проекта.
+	ProjectName string    `json:"project_name" example:"Работа"`             // Название проекта.
+	Name        string    `json:"name" example:"Созвон"`                     // Название записи.
+	TimeStart   time.Time `json:"time_start" example:"2024-03-23T15:04:05Z"` // Время начала.
+	TimeEnd     time.Time `json:"time_end" example:"2024-03-23T16:04:05Z"`   // Время окончания.
+	DeletedAt   time.Time `json:"deleted_at" example:"2024-03-24T10:00:00Z"` // Время удаления.
+	PurgeAt     time.Time `json:"purge_at" example:"2024-04-23T10:00:00Z"`   // Время окончательного удаления.
+}
+
+type DeletedProjectOut struct {
+	ID          int64     `json:"id" example:"1"`                            // Идентификатор проекта.
+	WorkspaceID int64     `json:"workspace_id,omitempty" example:"1"`        // Идентификатор пространства, если проект общий.
+	Name        string    `json:"name" example:"Работа"`                     // Название проекта.
+	DeletedAt   time.Time `json:"deleted_at" example:"2024-03-24T10:00:00Z"` // Время удаления.
+	PurgeAt     time.Time `json:"purge_at" example:"2024-04-23T10:00:00Z"`   // Время окончательного удаления.
+}
+
+type DeletedGoalOut struct {
+	ID          int64     `json:"id" example:"1"`                            // Идентификатор цели.
+	ProjectID   int64     `json:"project_id" example:"1"`                    // Идентификатор проекта.
+	ProjectName string    `json:"project_name" example:"Работа"`             // Название проекта.
+	Name        string    `json:"name" example:"Выучить Go"`                 // Название цели.
+	TimeSeconds int64     `json:"time_seconds" example:"36000"`              // Запланированное время (в сек.).
+	DateStart   time.Time `json:"date_start" example:"2024-03-01T00:00:00Z"` // Начало цели.
+	DateEnd     time.Time `json:"date_end" example:"2024-03-31T23:59:59Z"`   // Окончание цели.
+	DeletedAt   time.Time `json:"deleted_at" example:"2024-03-24T10:00:00Z"` // Время удаления.
+	PurgeAt     time.Time `json:"purge_at" example:"2024-04-23T10:00:00Z"`   // Время окончательного удаления.
+}
@@ -0,0 +1,109 @@
+package delivery
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/response"
+	usecaseDto "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/trash/usecase"
+)
+
+type usecase interface {
+	GetTrash(ctx context.Context, userID int64) (usecaseDto.Trash, error)
+}
+
+type Delivery struct {
+	usecase usecase
+
+	logger echo.Logger
+}
+
+func RegisterHandlers(
+	e *echo.Echo,
+	usecase usecase,
+	logger echo.Logger,
+) {
+	handler := &Delivery{
+		usecase: usecase,
+
+		logger: logger,
+	}
+
+	e.GET("/me/trash", handler.GetMyTrash)
+}
+
+// GetMyTrash godoc
+// @Summary      Получить корзину.
+// @Description  Получить удаленные записи, проекты и цели пользователя. Записи и цели, удаленные вместе с проектом, восстанавливаются вместе с ним.
+// @Tags     	 trash
+// @Accept	 	application/json
+// @Produce  	application/json
+// @Success  200 {object} TrashOut "success get trash"
+// @Failure 500 {object} response.Error "internal server error"
+// @Router   /me/trash [get]
+func (d *Delivery) GetMyTrash(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, ok := c.Get("user_id").(int64)
+	if !ok {
+		c.Logger().Error("can't parse context user_id")
+		return response.Status(http.StatusInternalServerError)
+	}
+
+	trash, err := d.usecase.GetTrash(ctx, userID)
+	if err != nil {
+		c.Logger().Errorf("usecase: %v", err)
+		return response.Status(http.StatusInternalServerError)
+	}
+
+	return c.JSON(http.StatusOK, convertFromUsecaseTrash(trash))
+}
+
+func convertFromUsecaseTrash(trash usecaseDto.Trash) TrashOut {
+	out := TrashOut{
+		Entries:  make([]DeletedEntryOut, 0, len(trash.Entries)),
+		Projects: make([]DeletedProjectOut, 0, len(trash.Projects)),
+		Goals:    make([]DeletedGoalOut, 0, len(trash.Goals)),
+	}
+
+	for _, e := range trash.Entries {
+		out.Entries = append(out.Entries, DeletedEntryOut{
+			ID:          e.ID,
+			ProjectID:   e.ProjectID,
+			ProjectName: e.ProjectName,
+			Name:        e.Name,
+			TimeStart:   e.TimeStart,
+			TimeEnd:     e.TimeEnd,
+			DeletedAt:   e.DeletedAt,
+			PurgeAt:     e.PurgeAt,
+		})
+	}
+
+	for _, p := range trash.Projects {
+		out.Projects = append(out.Projects, DeletedProjectOut{
+			ID:          p.ID,
+			WorkspaceID: p.WorkspaceID,
+			Name:        p.Name,
+			DeletedAt:   p.DeletedAt,
+			PurgeAt:     p.PurgeAt,
+		})
+	}
+
+	for _, g := range trash.Goals {
+		out.Goals = append(out.Goals, DeletedGoalOut{
+			ID:          g.ID,
+			ProjectID:   g.ProjectID,
+			ProjectName: g.ProjectName,
+			Name:        g.Name,
+			TimeSeconds: g.TimeSeconds,
+			DateStart:   g.DateStart,
+			DateEnd:     g.DateEnd,
+			DeletedAt:   g.DeletedAt,
+			PurgeAt:     g.PurgeAt,
+		})
+	}
+
+	return out
+}
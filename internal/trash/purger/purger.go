@@ -0,0 +1,55 @@
+package purger
+
+import (
+	"context"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type usecase interface {
+	Purge(ctx context.Context) (int64, error)
+}
+
+// Purger периодически очищает корзину от данных с истекшим сроком хранения.
+type Purger struct {
+	usecase  usecase
+	interval time.Duration
+
+	logger echo.Logger
+}
+
+func NewPurger(usecase usecase, interval time.Duration, logger echo.Logger) *Purger {
+	return &Purger{
+		usecase:  usecase,
+		interval: interval,
+
+		logger: logger,
+	}
+}
+
+// Run очищает корзину сразу и затем раз в interval, пока не отменен контекст.
+func (p *Purger) Run(ctx context.Context) {
+	if p.interval <= 0 {
+		p.logger.Warn("trash purge is disabled: purge interval is not set")
+		return
+	}
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := p.usecase.Purge(ctx)
+		if err != nil {
+			p.logger.Errorf("purge trash: %v", err)
+		} else if purged > 0 {
+			p.logger.Infof("purged %d rows from trash", purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+)
+
+type Entry struct {
+	ID          int64     `db:"id"`
+	ProjectID   int64     `db:"project_id"`
+	ProjectName string    `db:"project_name"`
+	Name        string    `db:"name"`
+	TimeStart   time.Time `db:"time_start"`
+	TimeEnd     time.Time `db:"time_end"`
+	DeletedAt   time.Time `db:"deleted_at"`
+}
+
+type Project struct {
+	ID          int64         `db:"id"`
+	WorkspaceID sql.NullInt64 `db:"workspace_id"`
+	Name        string        `db:"name"`
+	DeletedAt   time.Time     `db:"deleted_at"`
+}
+
+type Goal struct {
+	ID          int64     `db:"id"`
+	ProjectID   int64     `db:"project_id"`
+	ProjectName string    `db:"project_name"`
+	Name        string    `db:"name"`
+	TimeSeconds int64     `db:"time_seconds"`
+	DateStart   time.Time `db:"date_start"`
+	DateEnd     time.Time `db:"date_end"`
+	DeletedAt   time.Time `db:"deleted_at"`
+}
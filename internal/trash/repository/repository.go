@@ -0,0 +1,234 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	ErrEntryNotFound   = errors.New("deleted entry not found")
+	ErrProjectNotFound = errors.New("deleted project not found")
+	ErrGoalNotFound    = errors.New("deleted goal not found")
+)
+
+type Repository struct {
+	db    *sqlx.DB
+	close func() error
+}
+
+func NewRepository(db *sqlx.DB) *Repository {
+	return &Repository{
+		db: db,
+		close: func() error {
+			return db.Close()
+		},
+	}
+}
+
+// Close закрывает пул соединений с базой.
+func (r *Repository) Close() error {
+	return r.close()
+}
+
+// GetDeletedEntries возвращает записи пользователя из корзины.
+// Записи, удаленные вместе с проектом, восстанавливаются вместе с ним и в список не попадают.
+func (r *Repository) GetDeletedEntries(ctx context.Context, userID int64) ([]Entry, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT
+			e.id,
+			e.project_id,
+			p.name,
+			e.name,
+			e.time_start,
+			e.time_end,
+			e.deleted_at
+		FROM entries e
+		JOIN projects p ON p.id = e.project_id
+		WHERE e.user_id = $1
+		  AND e.deleted_at IS NOT NULL
+		  AND p.deleted_at IS DISTINCT FROM e.deleted_at
+		ORDER BY e.deleted_at DESC, e.id`, userID)
+
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var entries []Entry
+	for rows.Next() {
+		var entry Entry
+		if err = rows.Scan(
+			&entry.ID,
+			&entry.ProjectID,
+			&entry.ProjectName,
+			&entry.Name,
+			&entry.TimeStart,
+			&entry.TimeEnd,
+			&entry.DeletedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan: %w", err)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("rows err: %w", rows.Err())
+	}
+
+	if len(entries) == 0 {
+		return nil, ErrEntryNotFound
+	}
+
+	return entries, nil
+}
+
+// GetDeletedProjects возвращает проекты, которые удалил пользователь.
+func (r *Repository) GetDeletedProjects(ctx context.Context, userID int64) ([]Project, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT
+			id,
+			workspace_id,
+			name,
+			deleted_at
+		FROM projects
+		WHERE deleted_by = $1 AND deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC, id`, userID)
+
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var projects []Project
+	for rows.Next() {
+		var project Project
+		if err = rows.Scan(
+			&project.ID,
+			&project.WorkspaceID,
+			&project.Name,
+			&project.DeletedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan: %w", err)
+		}
+
+		projects = append(projects, project)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("rows err: %w", rows.Err())
+	}
+
+	if len(projects) == 0 {
+		return nil, ErrProjectNotFound
+	}
+
+	return projects, nil
+}
+
+// GetDeletedGoals возвращает цели пользователя из корзины, кроме удаленных вместе с проектом.
+func (r *Repository) GetDeletedGoals(ctx context.Context, userID int64) ([]Goal, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT
+			g.id,
+			g.project_id,
+			p.name,
+			g.name,
+			g.time_seconds,
+			g.date_start,
+			g.date_end,
+			g.deleted_at
+		FROM goals g
+		JOIN projects p ON p.id = g.project_id
+		WHERE g.user_id = $1
+		  AND g.deleted_at IS NOT NULL
+		  AND p.deleted_at IS DISTINCT FROM g.deleted_at
+		ORDER BY g.deleted_at DESC, g.id`, userID)
+
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var goals []Goal
+	for rows.Next() {
+		var goal Goal
+		if err = rows.Scan(
+			&goal.ID,
+			&goal.ProjectID,
+			&goal.ProjectName,
+			&goal.Name,
+			&goal.TimeSeconds,
+			&goal.DateStart,
+			&goal.DateEnd,
+			&goal.DeletedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan: %w", err)
+		}
+
+		goals = append(goals, goal)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("rows err: %w", rows.Err())
+	}
+
+	if len(goals) == 0 {
+		return nil, ErrGoalNotFound
+	}
+
+	return goals, nil
+}
+
+// Purge окончательно удаляет записи, цели и проекты, пролежавшие в корзине дольше retention.
+// Возвращает количество удаленных строк.
+func (r *Repository) Purge(ctx context.Context, retention time.Duration) (int64, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin tx: %w", err)
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	// Срок считаем в базе, чтобы не зависеть от часового пояса сессии.
+	queries := []string{
+		`DELETE FROM entries WHERE deleted_at < now() - make_interval(secs => $1);`,
+		`DELETE FROM goals WHERE deleted_at < now() - make_interval(secs => $1);`,
+		`DELETE FROM projects WHERE deleted_at < now() - make_interval(secs => $1);`,
+	}
+
+	var purged int64
+	for _, query := range queries {
+		res, err := tx.ExecContext(ctx, query, retention.Seconds())
+		if err != nil {
+			return 0, fmt.Errorf("exec context: %w", err)
+		}
+
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return 0, fmt.Errorf("rows affected: %w", err)
+		}
+		purged += affected
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit: %w", err)
+	}
+
+	return purged, nil
+}
@@ -0,0 +1,43 @@
+package usecase
+
+import "time"
+
+type Entry struct {
+	ID          int64
+	ProjectID   int64
+	ProjectName string
+	Name        string
+	TimeStart   time.Time
+	TimeEnd     time.Time
+	DeletedAt   time.Time
+	// Время, после которого запись будет удалена окончательно.
+	PurgeAt time.Time
+}
+
+type Project struct {
+	ID int64
+	// Идентификатор пространства, 0 для личного проекта.
+	WorkspaceID int64
+	Name        string
+	DeletedAt   time.Time
+	PurgeAt     time.Time
+}
+
+type Goal struct {
+	ID          int64
+	ProjectID   int64
+	ProjectName string
+	Name        string
+	TimeSeconds int64
+	DateStart   time.Time
+	DateEnd     time.Time
+	DeletedAt   time.Time
+	PurgeAt     time.Time
+}
+
+// Trash содержимое корзины пользователя.
+type Trash struct {
+	Entries  []Entry
+	Projects []Project
+	Goals    []Goal
+}
@@ -0,0 +1,113 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/tracing"
+	repo "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/trash/repository"
+)
+
+type repository interface {
+	GetDeletedEntries(ctx context.Context, userID int64) ([]repo.Entry, error)
+	GetDeletedProjects(ctx context.Context, userID int64) ([]repo.Project, error)
+	GetDeletedGoals(ctx context.Context, userID int64) ([]repo.Goal, error)
+	Purge(ctx context.Context, retention time.Duration) (int64, error)
+}
+
+type Usecase struct {
+	repository repository
+	// Сколько удаленные данные хранятся в корзине.
+	retention time.Duration
+}
+
+func NewUsecase(repository repository, retention time.Duration) *Usecase {
+	return &Usecase{
+		repository: repository,
+		retention:  retention,
+	}
+}
+
+// GetTrash возвращает содержимое корзины пользователя. Записи и цели, удаленные
+// вместе с проектом, не показываются отдельно и восстанавливаются вместе с ним.
+func (u *Usecase) GetTrash(ctx context.Context, userID int64) (Trash, error) {
+	ctx, span := tracing.Start(ctx, "trash.GetTrash")
+	defer span.End()
+
+	trash := Trash{
+		Entries:  []Entry{},
+		Projects: []Project{},
+		Goals:    []Goal{},
+	}
+
+	entries, err := u.repository.GetDeletedEntries(ctx, userID)
+	if err != nil && !errors.Is(err, repo.ErrEntryNotFound) {
+		return Trash{}, fmt.Errorf("repo get deleted entries: %w", err)
+	}
+	for _, e := range entries {
+		trash.Entries = append(trash.Entries, Entry{
+			ID:          e.ID,
+			ProjectID:   e.ProjectID,
+			ProjectName: e.ProjectName,
+			Name:        e.Name,
+			TimeStart:   e.TimeStart,
+			TimeEnd:     e.TimeEnd,
+			DeletedAt:   e.DeletedAt,
+			PurgeAt:     e.DeletedAt.Add(u.retention),
+		})
+	}
+
+	projects, err := u.repository.GetDeletedProjects(ctx, userID)
+	if err != nil && !errors.Is(err, repo.ErrProjectNotFound) {
+		return Trash{}, fmt.Errorf("repo get deleted projects: %w", err)
+	}
+	for _, p := range projects {
+		trash.Projects = append(trash.Projects, Project{
+			ID:          p.ID,
+			WorkspaceID: p.WorkspaceID.Int64,
+			Name:        p.Name,
+			DeletedAt:   p.DeletedAt,
+			PurgeAt:     p.DeletedAt.Add(u.retention),
+		})
+	}
+
+	goals, err := u.repository.GetDeletedGoals(ctx, userID)
+	if err != nil && !errors.Is(err, repo.ErrGoalNotFound) {
+		return Trash{}, fmt.Errorf("repo get deleted goals: %w", err)
+	}
+	for _, g := range goals {
+		trash.Goals = append(trash.Goals, Goal{
+			ID:          g.ID,
+			ProjectID:   g.ProjectID,
+			ProjectName: g.ProjectName,
+			Name:        g.Name,
+			TimeSeconds: g.TimeSeconds,
+			DateStart:   g.DateStart,
+			DateEnd:     g.DateEnd,
+			DeletedAt:   g.DeletedAt,
+			PurgeAt:     g.DeletedAt.Add(u.retention),
+		})
+	}
+
+	return trash, nil
+}
+
+// Purge окончательно удаляет данные с истекшим сроком хранения в корзине.
+// Если срок хранения не задан, корзина не очищается.
+func (u *Usecase) Purge(ctx context.Context) (int64, error) {
+	ctx, span := tracing.Start(ctx, "trash.Purge")
+	defer span.End()
+
+	if u.retention <= 0 {
+		return 0, nil
+	}
+
+	purged, err := u.repository.Purge(ctx, u.retention)
+	if err != nil {
+		return 0, fmt.Errorf("repo purge: %w", err)
+	}
+
+	return purged, nil
+}
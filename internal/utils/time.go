@@ -9,3 +9,35 @@ func GetDayInterval(date time.Time) (time.Time, time.Time) {
 	end := time.Date(date.Year(), date.Month(), date.Day(), 23, 59, 59, 0, date.Location())
 	return start, end
 }
+
+// CalculatePercentDuration возвращает долю (в процентах) duration от totalDuration.
+func CalculatePercentDuration(duration float64, totalDuration float64) float64 {
+	if totalDuration == 0 {
+		return 0
+	}
+
+	return duration / totalDuration * 100
+}
+
+// GetWeekStart возвращает начало (понедельник 00:00) недели, в которую попадает date.
+func GetWeekStart(date time.Time) time.Time {
+	// В Go неделя начинается с воскресенья, сдвигаем на понедельник.
+	offset := (int(date.Weekday()) + 6) % 7
+	start := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	return start.AddDate(0, 0, -offset)
+}
+
+// RetryDelay возвращает задержку перед попыткой номер attempt+1: base после первой попытки,
+// дальше удваивается с каждой попыткой, но не больше maxDelay.
+func RetryDelay(base, maxDelay time.Duration, attempt int) time.Duration {
+	delay := base
+	for i := 1; i < attempt && delay < maxDelay; i++ {
+		delay *= 2
+	}
+
+	if delay > maxDelay {
+		return maxDelay
+	}
+
+	return delay
+}
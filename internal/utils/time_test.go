@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		base     time.Duration
+		maxDelay time.Duration
+		attempt  int
+		want     time.Duration
+	}{
+		// Повторы доставки вебхуков.
+		{30 * time.Second, 12 * time.Hour, 1, 30 * time.Second},
+		{30 * time.Second, 12 * time.Hour, 2, time.Minute},
+		{30 * time.Second, 12 * time.Hour, 3, 2 * time.Minute},
+		{30 * time.Second, 12 * time.Hour, 10, 512 * 30 * time.Second},
+		{30 * time.Second, 12 * time.Hour, 11, 1024 * 30 * time.Second},
+		{30 * time.Second, 12 * time.Hour, 12, 12 * time.Hour},
+		{30 * time.Second, 12 * time.Hour, 1000, 12 * time.Hour},
+		// Повторы публикации outbox.
+		{time.Second, 10 * time.Minute, 1, time.Second},
+		{time.Second, 10 * time.Minute, 10, 512 * time.Second},
+		{time.Second, 10 * time.Minute, 11, 10 * time.Minute},
+		// Номер попытки меньше 1 не уменьшает задержку.
+		{time.Second, 10 * time.Minute, 0, time.Second},
+		// Начальная задержка больше предела.
+		{time.Hour, 10 * time.Minute, 1, 10 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		if got := RetryDelay(tt.base, tt.maxDelay, tt.attempt); got != tt.want {
+			t.Errorf("RetryDelay(%v, %v, %d) = %v, want %v", tt.base, tt.maxDelay, tt.attempt, got, tt.want)
+		}
+	}
+}
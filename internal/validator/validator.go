@@ -1,12 +1,28 @@
 package validator
 
-import "gopkg.in/go-playground/validator.v9"
+import (
+	"reflect"
+	"strings"
+
+	"gopkg.in/go-playground/validator.v9"
+)
 
 func IsRequestValid(entity interface{}) (bool, error) {
 	validate := validator.New()
+	// В ошибках поля называются как в JSON запроса, чтобы клиент мог их сопоставить.
+	validate.RegisterTagNameFunc(jsonFieldName)
 	err := validate.Struct(entity)
 	if err != nil {
 		return false, err
 	}
 	return true, nil
 }
+
+func jsonFieldName(field reflect.StructField) string {
+	name := strings.Split(field.Tag.Get("json"), ",")[0]
+	if name == "" || name == "-" {
+		return field.Name
+	}
+
+	return name
+}
@@ -0,0 +1,46 @@
+package delivery
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type SubscriptionIn struct {
+	URL         string   `json:"url" validate:"required,url,max=2048" example:"https://example.com/hooks/timetracker"` // Адрес получателя, http или https.
+	Secret      string   `json:"secret" validate:"omitempty,min=16,max=128" example:"d1f0c3e9a7b84c2f"`                // Ключ подписи, по умолчанию генерируется.
+	Events      []string `json:"events" validate:"required,min=1,dive,required" example:"entry.created,goal.achieved"` // Типы событий.
+	WorkspaceID int64    `json:"workspace_id" example:"1"`                                                             // Пространство, 0 — личная подписка.
+}
+
+type SubscriptionOut struct {
+	ID          int64     `json:"id" example:"1"`                                      // Идентификатор подписки.
+	UserID      int64     `json:"user_id" example:"1"`                                 // Кто создал подписку.
+	WorkspaceID int64     `json:"workspace_id,omitempty" example:"1"`                  // Пространство подписки.
+	URL         string    `json:"url" example:"https://example.com/hooks/timetracker"` // Адрес получателя.
+	Secret      string    `json:"secret,omitempty" example:"d1f0c3e9a7b84c2f"`         // Ключ подписи, возвращается только при создании.
+	Events      []string  `json:"events" example:"entry.created,goal.achieved"`        // Типы событий.
+	CreatedAt   time.Time `json:"created_at" example:"2024-03-23T15:04:05Z"`           // Время создания.
+}
+
+type DeliveryOut struct {
+	ID             int64           `json:"id" example:"1"`                                           // Идентификатор доставки.
+	EventID        string          `json:"event_id" example:"9b2e4f1c0a7d4e3b8c6f5a2d1e0b9c8a"`      // Идентификатор события, одинаковый для повторных доставок.
+	EventType      string          `json:"event_type" example:"entry.created"`                       // Тип события.
+	Payload        json.RawMessage `json:"payload" swaggertype:"object"`                             // Тело запроса.
+	Status         string          `json:"status" example:"succeeded"`                               // Статус: pending, succeeded, failed.
+	Attempts       int             `json:"attempts" example:"1"`                                     // Сколько было попыток.
+	NextAttemptAt  *time.Time      `json:"next_attempt_at,omitempty" example:"2024-03-23T15:05:05Z"` // Время следующей попытки для pending.
+	ResponseStatus int             `json:"response_status,omitempty" example:"200"`                  // Код последнего ответа получателя.
+	LastError      string          `json:"last_error,omitempty" example:"500 Internal Server Error"` // Ошибка последней попытки.
+	CreatedAt      time.Time       `json:"created_at" example:"2024-03-23T15:04:05Z"`                // Время события.
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty" example:"2024-03-23T15:04:06Z"`    // Время успешной доставки.
+}
+
+type DeliveryPageOut struct {
+	Deliveries   []DeliveryOut `json:"deliveries"`                           // Доставки, начиная с самых новых.
+	NextBeforeID int64         `json:"next_before_id,omitempty" example:"1"` // Значение before_id для следующей страницы.
+}
+
+type RedeliverOut struct {
+	ID int64 `json:"id" example:"2"` // Идентификатор новой доставки.
+}
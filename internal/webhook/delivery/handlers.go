@@ -0,0 +1,379 @@
+package delivery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/response"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/validator"
+	usecaseDto "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/webhook/usecase"
+)
+
+const (
+	defaultPageLimit = 50
+	maxPageLimit     = 200
+)
+
+type usecase interface {
+	CreateSubscription(ctx context.Context, sub usecaseDto.Subscription) (usecaseDto.Subscription, error)
+	GetSubscriptions(ctx context.Context, userID, workspaceID int64) ([]usecaseDto.Subscription, error)
+	DeleteSubscription(ctx context.Context, subscriptionID, userID int64) error
+	GetDeliveries(ctx context.Context, subscriptionID, userID, beforeID int64, limit int) ([]usecaseDto.Delivery, error)
+	Redeliver(ctx context.Context, subscriptionID, deliveryID, userID int64) (int64, error)
+}
+
+type Delivery struct {
+	usecase usecase
+
+	logger echo.Logger
+}
+
+func RegisterHandlers(
+	e *echo.Echo,
+	usecase usecase,
+	logger echo.Logger,
+) {
+	handler := &Delivery{
+		usecase: usecase,
+
+		logger: logger,
+	}
+
+	e.POST("/webhooks", handler.CreateSubscription)
+	e.GET("/webhooks", handler.GetSubscriptions)
+	e.DELETE("/webhooks/:id", handler.DeleteSubscription)
+	e.GET("/webhooks/:id/deliveries", handler.GetDeliveries)
+	e.POST("/webhooks/:id/deliveries/:delivery_id/redeliver", handler.Redeliver)
+}
+
+// CreateSubscription godoc
+// @Summary      Подписаться на события.
+// @Description  Создать подписку на события записей, проектов и целей: entry.created, entry.updated, entry.deleted, entry.restored, entry.imported, project.created, project.deleted, project.restored, goal.created, goal.deleted, goal.restored, goal.achieved. Личная подписка получает события, совершенные пользователем, подписка пространства (доступно admin и owner) — события в проектах пространства. События отправляются POST-запросом с JSON; заголовок X-Webhook-Signature содержит sha256=HMAC-SHA256 от "<X-Webhook-Timestamp>.<тело>" ключом подписки, X-Webhook-Id — идентификатор события для отбрасывания дублей. Неудачные доставки повторяются с экспоненциальной задержкой. Ключ возвращается только в ответе на создание.
+// @Tags     	 webhooks
+// @Accept	 application/json
+// @Produce  application/json
+// @Param    subscription body SubscriptionIn true "Подписка"
+// @Success  200 {object} SubscriptionOut "success create subscription"
+// @Failure 500 {object} response.Error "internal server error"
+// @Failure 400 {object} response.Error "bad request"
+// @Failure 403 {object} response.Error "forbidden"
+// @Failure 422 {object} response.Error "unprocessable entity"
+// @Router   /webhooks [post]
+func (d *Delivery) CreateSubscription(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var in SubscriptionIn
+	err := c.Bind(&in)
+
+	if err != nil {
+		c.Logger().Errorf("bind request: %v", err)
+		return response.Status(http.StatusUnprocessableEntity)
+	}
+
+	if ok, err := validator.IsRequestValid(&in); !ok {
+		c.Logger().Errorf("validation: %v", err)
+		return response.ValidationError(err)
+	}
+
+	userID, ok := c.Get("user_id").(int64)
+	if !ok {
+		c.Logger().Error("can't parse context user_id")
+		return response.Status(http.StatusInternalServerError)
+	}
+
+	sub, err := d.usecase.CreateSubscription(ctx, usecaseDto.Subscription{
+		UserID:      userID,
+		WorkspaceID: in.WorkspaceID,
+		URL:         in.URL,
+		Secret:      in.Secret,
+		Events:      in.Events,
+	})
+	if err != nil {
+		c.Logger().Errorf("usecase: %v", err)
+		return handleUsecaseError(err)
+	}
+
+	out := convertFromUsecaseSubscription(sub)
+	out.Secret = sub.Secret
+
+	return c.JSON(http.StatusOK, out)
+}
+
+// GetSubscriptions godoc
+// @Summary      Получить подписки.
+// @Description  Получить личные подписки пользователя или подписки пространства (доступно admin и owner). Ключи подписи не возвращаются.
+// @Tags     	 webhooks
+// @Accept	 	application/json
+// @Produce  	application/json
+// @Param    workspace_id query int false "Идентификатор пространства"
+// @Success  200 {object} []SubscriptionOut "success get subscriptions"
+// @Failure 500 {object} response.Error "internal server error"
+// @Failure 400 {object} response.Error "bad request"
+// @Failure 403 {object} response.Error "forbidden"
+// @Router   /webhooks [get]
+func (d *Delivery) GetSubscriptions(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var workspaceID int64
+	if workspaceIDStr := c.QueryParam("workspace_id"); workspaceIDStr != "" {
+		var err error
+		workspaceID, err = strconv.ParseInt(workspaceIDStr, 10, 64)
+		if err != nil {
+			c.Logger().Errorf("parse int: %v", err)
+			return response.InvalidParam("workspace_id", "int")
+		}
+	}
+
+	userID, ok := c.Get("user_id").(int64)
+	if !ok {
+		c.Logger().Error("can't parse context user_id")
+		return response.Status(http.StatusInternalServerError)
+	}
+
+	subs, err := d.usecase.GetSubscriptions(ctx, userID, workspaceID)
+	if err != nil {
+		c.Logger().Errorf("usecase: %v", err)
+		return handleUsecaseError(err)
+	}
+
+	out := make([]SubscriptionOut, 0, len(subs))
+	for _, sub := range subs {
+		out = append(out, convertFromUsecaseSubscription(sub))
+	}
+
+	return c.JSON(http.StatusOK, out)
+}
+
+// DeleteSubscription godoc
+// @Summary      Удалить подписку.
+// @Description  Удалить подписку вместе с журналом доставок.
+// @Tags     	 webhooks
+// @Accept	 	application/json
+// @Produce  	application/json
+// @Param    id path int true "Идентификатор подписки"
+// @Success  200  "success delete subscription"
+// @Failure 500 {object} response.Error "internal server error"
+// @Failure 400 {object} response.Error "bad request"
+// @Failure 403 {object} response.Error "forbidden"
+// @Failure 404 {object} response.Error "item is not found"
+// @Router   /webhooks/{id} [delete]
+func (d *Delivery) DeleteSubscription(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	subscriptionID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Logger().Errorf("parse int: %v", err)
+		return response.InvalidParam("id", "int")
+	}
+
+	userID, ok := c.Get("user_id").(int64)
+	if !ok {
+		c.Logger().Error("can't parse context user_id")
+		return response.Status(http.StatusInternalServerError)
+	}
+
+	err = d.usecase.DeleteSubscription(ctx, subscriptionID, userID)
+	if err != nil {
+		c.Logger().Errorf("usecase: %v", err)
+		return handleUsecaseError(err)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+// GetDeliveries godoc
+// @Summary      Журнал доставок.
+// @Description  Постраничный журнал доставок подписки: статус, число попыток, код ответа и ошибка последней попытки.
+// @Tags     	 webhooks
+// @Accept	 	application/json
+// @Produce  	application/json
+// @Param    id path int true "Идентификатор подписки"
+// @Param    limit query int false "Размер страницы (по умолчанию 50, максимум 200)"
+// @Param    before_id query int false "Вернуть доставки старше указанной"
+// @Success  200 {object} DeliveryPageOut "success get deliveries"
+// @Failure 500 {object} response.Error "internal server error"
+// @Failure 400 {object} response.Error "bad request"
+// @Failure 403 {object} response.Error "forbidden"
+// @Failure 404 {object} response.Error "item is not found"
+// @Router   /webhooks/{id}/deliveries [get]
+func (d *Delivery) GetDeliveries(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	subscriptionID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Logger().Errorf("parse int: %v", err)
+		return response.InvalidParam("id", "int")
+	}
+
+	limit, beforeID, err := parsePage(c)
+	if err != nil {
+		c.Logger().Errorf("parse page: %v", err)
+		return response.Status(http.StatusBadRequest)
+	}
+
+	userID, ok := c.Get("user_id").(int64)
+	if !ok {
+		c.Logger().Error("can't parse context user_id")
+		return response.Status(http.StatusInternalServerError)
+	}
+
+	deliveries, err := d.usecase.GetDeliveries(ctx, subscriptionID, userID, beforeID, limit)
+	if err != nil {
+		c.Logger().Errorf("usecase: %v", err)
+		return handleUsecaseError(err)
+	}
+
+	return c.JSON(http.StatusOK, convertFromUsecaseDeliveries(deliveries, limit))
+}
+
+// Redeliver godoc
+// @Summary      Повторить доставку.
+// @Description  Повторно отправить событие из журнала доставок с тем же идентификатором события. Создает новую доставку.
+// @Tags     	 webhooks
+// @Accept	 	application/json
+// @Produce  	application/json
+// @Param    id path int true "Идентификатор подписки"
+// @Param    delivery_id path int true "Идентификатор доставки"
+// @Success  200 {object} RedeliverOut "success redeliver"
+// @Failure 500 {object} response.Error "internal server error"
+// @Failure 400 {object} response.Error "bad request"
+// @Failure 403 {object} response.Error "forbidden"
+// @Failure 404 {object} response.Error "item is not found"
+// @Router   /webhooks/{id}/deliveries/{delivery_id}/redeliver [post]
+func (d *Delivery) Redeliver(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	subscriptionID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Logger().Errorf("parse int: %v", err)
+		return response.InvalidParam("id", "int")
+	}
+
+	deliveryID, err := strconv.ParseInt(c.Param("delivery_id"), 10, 64)
+	if err != nil {
+		c.Logger().Errorf("parse int: %v", err)
+		return response.InvalidParam("delivery_id", "int")
+	}
+
+	userID, ok := c.Get("user_id").(int64)
+	if !ok {
+		c.Logger().Error("can't parse context user_id")
+		return response.Status(http.StatusInternalServerError)
+	}
+
+	id, err := d.usecase.Redeliver(ctx, subscriptionID, deliveryID, userID)
+	if err != nil {
+		c.Logger().Errorf("usecase: %v", err)
+		return handleUsecaseError(err)
+	}
+
+	return c.JSON(http.StatusOK, RedeliverOut{ID: id})
+}
+
+func parsePage(c echo.Context) (int, int64, error) {
+	limit := defaultPageLimit
+	if limitStr := c.QueryParam("limit"); limitStr != "" {
+		var err error
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		if limit <= 0 || limit > maxPageLimit {
+			return 0, 0, errors.New("limit out of range")
+		}
+	}
+
+	var beforeID int64
+	if beforeIDStr := c.QueryParam("before_id"); beforeIDStr != "" {
+		var err error
+		beforeID, err = strconv.ParseInt(beforeIDStr, 10, 64)
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+
+	return limit, beforeID, nil
+}
+
+func handleUsecaseError(err error) *response.Error {
+	// Запрос не уложился в таймаут или был отменен.
+	if problem := response.ContextError(err); problem != nil {
+		return problem
+	}
+
+	if errors.Is(err, usecaseDto.ErrSubscriptionNotFound) {
+		return response.NotFound("webhook")
+	}
+
+	if errors.Is(err, usecaseDto.ErrDeliveryNotFound) {
+		return response.NotFound("delivery")
+	}
+
+	if errors.Is(err, usecaseDto.ErrForbidden) {
+		return response.Status(http.StatusForbidden)
+	}
+
+	if errors.Is(err, usecaseDto.ErrInvalidURL) {
+		return response.NewError(
+			http.StatusBadRequest,
+			"invalid_webhook_url",
+			fmt.Sprintf("%s: %v", response.ErrorMsgsByCode[http.StatusBadRequest], err))
+	}
+	if errors.Is(err, usecaseDto.ErrUnknownEventType) {
+		return response.NewError(
+			http.StatusBadRequest,
+			"unknown_event_type",
+			fmt.Sprintf("%s: %v", response.ErrorMsgsByCode[http.StatusBadRequest], err))
+	}
+
+	// По дефолту пятисотим.
+	return response.Status(http.StatusInternalServerError)
+}
+
+func convertFromUsecaseSubscription(sub usecaseDto.Subscription) SubscriptionOut {
+	return SubscriptionOut{
+		ID:          sub.ID,
+		UserID:      sub.UserID,
+		WorkspaceID: sub.WorkspaceID,
+		URL:         sub.URL,
+		Events:      sub.Events,
+		CreatedAt:   sub.CreatedAt,
+	}
+}
+
+func convertFromUsecaseDeliveries(deliveries []usecaseDto.Delivery, limit int) DeliveryPageOut {
+	out := DeliveryPageOut{Deliveries: make([]DeliveryOut, 0, len(deliveries))}
+	for _, d := range deliveries {
+		delivery := DeliveryOut{
+			ID:             d.ID,
+			EventID:        d.EventID,
+			EventType:      d.EventType,
+			Payload:        d.Payload,
+			Status:         d.Status,
+			Attempts:       d.Attempts,
+			ResponseStatus: d.ResponseStatus,
+			LastError:      d.LastError,
+			CreatedAt:      d.CreatedAt,
+			DeliveredAt:    d.DeliveredAt,
+		}
+		if d.Status == usecaseDto.StatusPending {
+			nextAttemptAt := d.NextAttemptAt
+			delivery.NextAttemptAt = &nextAttemptAt
+		}
+
+		out.Deliveries = append(out.Deliveries, delivery)
+	}
+
+	// Полная страница означает, что могут быть более старые доставки.
+	if len(deliveries) == limit {
+		out.NextBeforeID = deliveries[len(deliveries)-1].ID
+	}
+
+	return out
+}
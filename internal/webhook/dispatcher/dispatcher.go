@@ -0,0 +1,59 @@
+package dispatcher
+
+import (
+	"context"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type usecase interface {
+	DeliverPending(ctx context.Context) (int, error)
+}
+
+// Dispatcher в фоне отправляет ожидающие доставки вебхуков.
+type Dispatcher struct {
+	usecase  usecase
+	interval time.Duration
+
+	logger echo.Logger
+}
+
+func NewDispatcher(usecase usecase, interval time.Duration, logger echo.Logger) *Dispatcher {
+	return &Dispatcher{
+		usecase:  usecase,
+		interval: interval,
+
+		logger: logger,
+	}
+}
+
+// Run отправляет доставки порциями, пока они есть, затем ждет interval. Работает до отмены контекста.
+func (d *Dispatcher) Run(ctx context.Context) {
+	if d.interval <= 0 {
+		d.logger.Warn("webhook delivery is disabled: poll interval is not set")
+		return
+	}
+
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			sent, err := d.usecase.DeliverPending(ctx)
+			if err != nil {
+				d.logger.Errorf("deliver webhooks: %v", err)
+				break
+			}
+			if sent == 0 || ctx.Err() != nil {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
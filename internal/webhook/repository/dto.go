@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+)
+
+type Subscription struct {
+	ID          int64         `db:"id"`
+	UserID      int64         `db:"user_id"`
+	WorkspaceID sql.NullInt64 `db:"workspace_id"`
+	URL         string        `db:"url"`
+	Secret      string        `db:"secret"`
+	Events      []string      `db:"events"`
+	CreatedAt   time.Time     `db:"created_at"`
+}
+
+type Delivery struct {
+	ID             int64          `db:"id"`
+	SubscriptionID int64          `db:"subscription_id"`
+	EventID        string         `db:"event_id"`
+	EventType      string         `db:"event_type"`
+	Payload        []byte         `db:"payload"`
+	Status         string         `db:"status"`
+	Attempts       int            `db:"attempts"`
+	NextAttemptAt  time.Time      `db:"next_attempt_at"`
+	ResponseStatus sql.NullInt64  `db:"response_status"`
+	LastError      sql.NullString `db:"last_error"`
+	CreatedAt      time.Time      `db:"created_at"`
+	DeliveredAt    sql.NullTime   `db:"delivered_at"`
+}
+
+// PendingDelivery доставка, взятая в работу, вместе с адресом и секретом подписки.
+type PendingDelivery struct {
+	Delivery
+	URL    string `db:"url"`
+	Secret string `db:"secret"`
+}
+
+// Attempt результат попытки доставки.
+type Attempt struct {
+	DeliveryID int64
+	Status     string
+	// 0, если ответ не получен.
+	ResponseStatus int
+	Error          string
+	// Через сколько повторить доставку в статусе pending. Время считается по часам базы,
+	// как при выборке доставок.
+	RetryDelay time.Duration
+}
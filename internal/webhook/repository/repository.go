@@ -0,0 +1,377 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrSubscriptionNotFound = errors.New("webhook subscription not found")
+	ErrDeliveryNotFound     = errors.New("webhook delivery not found")
+)
+
+const subscriptionColumns = `
+			id,
+			user_id,
+			workspace_id,
+			url,
+			secret,
+			events,
+			created_at`
+
+const deliveryColumns = `
+			d.id,
+			d.subscription_id,
+			d.event_id,
+			d.event_type,
+			d.payload,
+			d.status,
+			d.attempts,
+			d.next_attempt_at,
+			d.response_status,
+			d.last_error,
+			d.created_at,
+			d.delivered_at`
+
+type Repository struct {
+	db    *sqlx.DB
+	close func() error
+}
+
+func NewRepository(db *sqlx.DB) *Repository {
+	return &Repository{
+		db: db,
+		close: func() error {
+			return db.Close()
+		},
+	}
+}
+
+// Close закрывает пул соединений с базой.
+func (r *Repository) Close() error {
+	return r.close()
+}
+
+func (r *Repository) CreateSubscription(ctx context.Context, sub Subscription) (int64, error) {
+	var id int64
+	err := r.db.QueryRowContext(ctx,
+		`INSERT INTO webhook_subscriptions
+				(
+					user_id,
+					workspace_id,
+					url,
+					secret,
+					events
+				) VALUES ($1, $2, $3, $4, $5) RETURNING id`,
+		sub.UserID,
+		sub.WorkspaceID,
+		sub.URL,
+		sub.Secret,
+		pq.Array(sub.Events),
+	).Scan(&id)
+
+	if err != nil {
+		return 0, fmt.Errorf("scan: %w", err)
+	}
+
+	return id, nil
+}
+
+func (r *Repository) GetSubscription(ctx context.Context, subscriptionID int64) (Subscription, error) {
+	row := r.db.QueryRowContext(ctx,
+		`SELECT`+subscriptionColumns+`
+		FROM webhook_subscriptions
+		WHERE id = $1`, subscriptionID)
+
+	sub, err := scanSubscription(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Subscription{}, ErrSubscriptionNotFound
+		}
+
+		return Subscription{}, fmt.Errorf("scan: %w", err)
+	}
+
+	return sub, nil
+}
+
+// GetUserSubscriptions возвращает личные подписки пользователя.
+func (r *Repository) GetUserSubscriptions(ctx context.Context, userID int64) ([]Subscription, error) {
+	return r.querySubscriptions(ctx,
+		`SELECT`+subscriptionColumns+`
+		FROM webhook_subscriptions
+		WHERE user_id = $1 AND workspace_id IS NULL
+		ORDER BY id`, userID)
+}
+
+func (r *Repository) GetWorkspaceSubscriptions(ctx context.Context, workspaceID int64) ([]Subscription, error) {
+	return r.querySubscriptions(ctx,
+		`SELECT`+subscriptionColumns+`
+		FROM webhook_subscriptions
+		WHERE workspace_id = $1
+		ORDER BY id`, workspaceID)
+}
+
+// GetMatchingSubscriptions возвращает подписки на событие: личные подписки автора события
+// и подписки пространства, в котором оно произошло.
+func (r *Repository) GetMatchingSubscriptions(ctx context.Context, actorID, workspaceID int64, eventType string) ([]Subscription, error) {
+	return r.querySubscriptions(ctx,
+		`SELECT`+subscriptionColumns+`
+		FROM webhook_subscriptions
+		WHERE (workspace_id IS NULL AND user_id = $1 OR workspace_id = $2)
+		  AND $3 = ANY (events)
+		ORDER BY id`, actorID, workspaceID, eventType)
+}
+
+func (r *Repository) DeleteSubscription(ctx context.Context, subscriptionID int64) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM webhook_subscriptions WHERE id = $1`, subscriptionID)
+	if err != nil {
+		return fmt.Errorf("exec: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("rows affected: %w", err)
+	}
+
+	if affected == 0 {
+		return ErrSubscriptionNotFound
+	}
+
+	return nil
+}
+
+// CreateDeliveries ставит событие в очередь доставки каждой из подписок.
+// Подписки, которым событие уже поставлено, пропускаются.
+func (r *Repository) CreateDeliveries(ctx context.Context, subscriptionIDs []int64, eventID, eventType string, payload []byte) error {
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO webhook_deliveries (subscription_id, event_id, event_type, payload)
+		SELECT s.id, $2::varchar, $3::varchar, $4::jsonb
+		FROM unnest($1::int[]) AS s(id)
+		ON CONFLICT (subscription_id, event_id) WHERE NOT redelivery DO NOTHING`,
+		pq.Array(subscriptionIDs),
+		eventID,
+		eventType,
+		string(payload),
+	)
+
+	if err != nil {
+		return fmt.Errorf("exec: %w", err)
+	}
+
+	return nil
+}
+
+// GetDeliveries возвращает страницу журнала доставок подписки, начиная с самых новых.
+// Нулевой beforeID означает первую страницу.
+func (r *Repository) GetDeliveries(ctx context.Context, subscriptionID, beforeID int64, limit int) ([]Delivery, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT`+deliveryColumns+`
+		FROM webhook_deliveries d
+		WHERE d.subscription_id = $1 AND ($2 = 0 OR d.id < $2)
+		ORDER BY d.id DESC
+		LIMIT $3`, subscriptionID, beforeID, limit)
+
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	deliveries := []Delivery{}
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan: %w", err)
+		}
+
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// Redeliver ставит событие доставки в очередь повторно и возвращает идентификатор новой доставки.
+func (r *Repository) Redeliver(ctx context.Context, subscriptionID, deliveryID int64) (int64, error) {
+	var id int64
+	err := r.db.QueryRowContext(ctx,
+		`INSERT INTO webhook_deliveries (subscription_id, event_id, event_type, payload, redelivery)
+		SELECT subscription_id, event_id, event_type, payload, true
+		FROM webhook_deliveries
+		WHERE id = $1 AND subscription_id = $2
+		RETURNING id`, deliveryID, subscriptionID).Scan(&id)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, ErrDeliveryNotFound
+		}
+
+		return 0, fmt.Errorf("scan: %w", err)
+	}
+
+	return id, nil
+}
+
+// ClaimDeliveries забирает в работу до limit ожидающих доставок, время которых подошло.
+// Взятые доставки откладываются на lease, поэтому другие экземпляры сервиса их не возьмут,
+// а если процесс упадет до сохранения результата, доставка будет повторена.
+func (r *Repository) ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]PendingDelivery, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`WITH due AS (
+			SELECT id
+			FROM webhook_deliveries
+			WHERE status = 'pending' AND next_attempt_at <= now()
+			ORDER BY next_attempt_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE webhook_deliveries d
+		SET next_attempt_at = now() + make_interval(secs => $2)
+		FROM due, webhook_subscriptions s
+		WHERE d.id = due.id AND s.id = d.subscription_id
+		RETURNING`+deliveryColumns+`,
+			s.url,
+			s.secret`, limit, lease.Seconds())
+
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var deliveries []PendingDelivery
+	for rows.Next() {
+		var d PendingDelivery
+		if err = rows.Scan(
+			&d.ID,
+			&d.SubscriptionID,
+			&d.EventID,
+			&d.EventType,
+			&d.Payload,
+			&d.Status,
+			&d.Attempts,
+			&d.NextAttemptAt,
+			&d.ResponseStatus,
+			&d.LastError,
+			&d.CreatedAt,
+			&d.DeliveredAt,
+			&d.URL,
+			&d.Secret,
+		); err != nil {
+			return nil, fmt.Errorf("scan: %w", err)
+		}
+
+		deliveries = append(deliveries, d)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// SaveAttempt сохраняет результат попытки доставки.
+func (r *Repository) SaveAttempt(ctx context.Context, attempt Attempt) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE webhook_deliveries
+		SET attempts        = attempts + 1,
+		    status          = $2::varchar,
+		    response_status = NULLIF($3, 0),
+		    last_error      = NULLIF($4, ''),
+		    next_attempt_at = now() + make_interval(secs => $5),
+		    delivered_at    = CASE WHEN $2::varchar = 'succeeded' THEN now() END
+		WHERE id = $1`,
+		attempt.DeliveryID,
+		attempt.Status,
+		attempt.ResponseStatus,
+		attempt.Error,
+		attempt.RetryDelay.Seconds(),
+	)
+
+	if err != nil {
+		return fmt.Errorf("exec: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) querySubscriptions(ctx context.Context, query string, args ...interface{}) ([]Subscription, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	subs := []Subscription{}
+	for rows.Next() {
+		sub, err := scanSubscription(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan: %w", err)
+		}
+
+		subs = append(subs, sub)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows: %w", err)
+	}
+
+	return subs, nil
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanSubscription(row scanner) (Subscription, error) {
+	var sub Subscription
+	err := row.Scan(
+		&sub.ID,
+		&sub.UserID,
+		&sub.WorkspaceID,
+		&sub.URL,
+		&sub.Secret,
+		pq.Array(&sub.Events),
+		&sub.CreatedAt,
+	)
+
+	return sub, err
+}
+
+func scanDelivery(row scanner) (Delivery, error) {
+	var d Delivery
+	err := row.Scan(
+		&d.ID,
+		&d.SubscriptionID,
+		&d.EventID,
+		&d.EventType,
+		&d.Payload,
+		&d.Status,
+		&d.Attempts,
+		&d.NextAttemptAt,
+		&d.ResponseStatus,
+		&d.LastError,
+		&d.CreatedAt,
+		&d.DeliveredAt,
+	)
+
+	return d, err
+}
@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Статусы доставки.
+const (
+	StatusPending   = "pending"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+)
+
+// Типы событий, на которые можно подписаться.
+const (
+	EventEntryCreated    = "entry.created"
+	EventEntryUpdated    = "entry.updated"
+	EventEntryDeleted    = "entry.deleted"
+	EventEntryRestored   = "entry.restored"
+	EventEntryImported   = "entry.imported"
+	EventProjectCreated  = "project.created"
+	EventProjectDeleted  = "project.deleted"
+	EventProjectRestored = "project.restored"
+	EventGoalCreated     = "goal.created"
+	EventGoalDeleted     = "goal.deleted"
+	EventGoalRestored    = "goal.restored"
+	EventGoalAchieved    = "goal.achieved"
+)
+
+type Subscription struct {
+	ID int64
+	// Кто создал подписку.
+	UserID int64
+	// 0 для личной подписки.
+	WorkspaceID int64
+	URL         string
+	// Ключ подписи HMAC-SHA256, пустой при создании — будет сгенерирован.
+	Secret    string
+	Events    []string
+	CreatedAt time.Time
+}
+
+type Delivery struct {
+	ID             int64
+	SubscriptionID int64
+	EventID        string
+	EventType      string
+	Payload        json.RawMessage
+	Status         string
+	Attempts       int
+	NextAttemptAt  time.Time
+	ResponseStatus int
+	LastError      string
+	CreatedAt      time.Time
+	DeliveredAt    *time.Time
+}
+
+// Payload тело запроса к получателю.
+type Payload struct {
+	// Ключ события из outbox, одинаковый для повторных доставок: по нему получатель отбрасывает дубли.
+	ID          string    `json:"id"`
+	Type        string    `json:"type"`
+	CreatedAt   time.Time `json:"created_at"`
+	ActorID     int64     `json:"actor_id"`
+	WorkspaceID int64     `json:"workspace_id,omitempty"`
+	EntityID    int64     `json:"entity_id,omitempty"`
+	// Состояние сущности после события, для удаления — до него.
+	Data json.RawMessage `json:"data,omitempty"`
+	// Состояние до изменения, только для *.updated.
+	Previous json.RawMessage `json:"previous,omitempty"`
+}
@@ -0,0 +1,134 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"syscall"
+	"time"
+
+	repo "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/webhook/repository"
+)
+
+// Заголовки запроса к получателю.
+const (
+	HeaderEventID   = "X-Webhook-Id"
+	HeaderEventType = "X-Webhook-Event"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// Сколько байт ответа получателя читать: тело не нужно, но соединение переиспользуется,
+// только если ответ дочитан.
+const maxResponseBytes = 64 * 1024
+
+// ErrPrivateAddress адрес получателя во внутренней сети: localhost, частные диапазоны,
+// link-local (в том числе метаданные облака 169.254.169.254).
+var ErrPrivateAddress = errors.New("webhook address is not public")
+
+// newHTTPClient создает клиент для доставки вебхуков. Редиректы не выполняются:
+// ответ 3xx считается неудачной доставкой. Если allowPrivate не задан, соединения
+// с внутренними адресами запрещены. Адрес проверяется при подключении, уже после
+// разрешения имени, поэтому проверку не обойти DNS-записью, которая меняется после создания подписки.
+func newHTTPClient(timeout time.Duration, allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   timeout,
+		KeepAlive: 30 * time.Second,
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if !allowPrivate {
+		dialer.Control = checkDialAddress
+		// Через прокси проверялся бы адрес прокси, а не получателя.
+		transport.Proxy = nil
+	}
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// checkDialAddress запрещает подключение к внутренним адресам. address — уже разрешенный ip:port.
+func checkDialAddress(_, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("parse dial address %q: %w", address, err)
+	}
+
+	if isPrivateAddr(addrPort.Addr()) {
+		return fmt.Errorf("%w: %s", ErrPrivateAddress, addrPort.Addr())
+	}
+
+	return nil
+}
+
+func isPrivateAddr(addr netip.Addr) bool {
+	// ::ffff:127.0.0.1 — тот же localhost.
+	addr = addr.Unmap()
+
+	return addr.IsLoopback() ||
+		addr.IsPrivate() ||
+		addr.IsLinkLocalUnicast() ||
+		addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() ||
+		addr.IsUnspecified()
+}
+
+// Sign вычисляет подпись тела запроса: HMAC-SHA256 от "<timestamp>.<body>" ключом подписки.
+// Получатель проверяет ее тем же способом и отбрасывает запросы со старым timestamp.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// send отправляет доставку и возвращает код ответа, 0 — ответ не получен.
+// Успешной считается доставка с ответом 2xx.
+func (u *Usecase) send(ctx context.Context, d repo.PendingDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("new request: %w", err)
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "timetracker-webhooks/1")
+	req.Header.Set(HeaderEventID, d.EventID)
+	req.Header.Set(HeaderEventType, d.EventType)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(d.Secret, timestamp, d.Payload))
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseBytes))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, errors.New(resp.Status)
+	}
+
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,458 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"time"
+
+	auditUC "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/audit/usecase"
+	outboxUC "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/outbox/usecase"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/roles"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/tracing"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/utils"
+	repo "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/webhook/repository"
+	workspaceRepoDto "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/workspace/repository"
+)
+
+var (
+	ErrSubscriptionNotFound = errors.New("webhook subscription not found")
+	ErrDeliveryNotFound     = errors.New("webhook delivery not found")
+	ErrForbidden            = errors.New("forbidden")
+	ErrInvalidURL           = errors.New("webhook url must be an absolute http or https url")
+	ErrUnknownEventType     = errors.New("unknown event type")
+)
+
+const (
+	secretBytes = 32
+	// Предел задержки между повторами.
+	maxRetryDelay = 12 * time.Hour
+)
+
+// Суффиксы типа события по действию из журнала аудита.
+var actionEvents = map[string]string{
+	auditUC.ActionCreate:  "created",
+	auditUC.ActionUpdate:  "updated",
+	auditUC.ActionDelete:  "deleted",
+	auditUC.ActionRestore: "restored",
+	auditUC.ActionImport:  "imported",
+	auditUC.ActionAchieve: "achieved",
+}
+
+var eventTypes = map[string]struct{}{
+	EventEntryCreated:    {},
+	EventEntryUpdated:    {},
+	EventEntryDeleted:    {},
+	EventEntryRestored:   {},
+	EventEntryImported:   {},
+	EventProjectCreated:  {},
+	EventProjectDeleted:  {},
+	EventProjectRestored: {},
+	EventGoalCreated:     {},
+	EventGoalDeleted:     {},
+	EventGoalRestored:    {},
+	EventGoalAchieved:    {},
+}
+
+type repository interface {
+	CreateSubscription(ctx context.Context, sub repo.Subscription) (int64, error)
+	GetSubscription(ctx context.Context, subscriptionID int64) (repo.Subscription, error)
+	GetUserSubscriptions(ctx context.Context, userID int64) ([]repo.Subscription, error)
+	GetWorkspaceSubscriptions(ctx context.Context, workspaceID int64) ([]repo.Subscription, error)
+	GetMatchingSubscriptions(ctx context.Context, actorID, workspaceID int64, eventType string) ([]repo.Subscription, error)
+	DeleteSubscription(ctx context.Context, subscriptionID int64) error
+
+	CreateDeliveries(ctx context.Context, subscriptionIDs []int64, eventID, eventType string, payload []byte) error
+	GetDeliveries(ctx context.Context, subscriptionID, beforeID int64, limit int) ([]repo.Delivery, error)
+	Redeliver(ctx context.Context, subscriptionID, deliveryID int64) (int64, error)
+	ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]repo.PendingDelivery, error)
+	SaveAttempt(ctx context.Context, attempt repo.Attempt) error
+}
+
+type workspaceRepository interface {
+	GetMemberRole(ctx context.Context, workspaceID, userID int64) (string, error)
+}
+
+type httpClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+type Usecase struct {
+	repository          repository
+	workspaceRepository workspaceRepository
+	client              httpClient
+	// Разрешает подписки на внутренние адреса, только для локальной разработки.
+	allowPrivate bool
+	// Пока доставки порции в работе, другие экземпляры сервиса их не берут.
+	lease time.Duration
+	// Сколько доставок отправлять за один вызов DeliverPending.
+	batchSize int
+	// Сколько раз пытаться доставить событие.
+	maxAttempts int
+	// Задержка перед первым повтором, каждая следующая вдвое больше.
+	retryBase time.Duration
+}
+
+func NewUsecase(
+	repository repository,
+	workspaceRepository workspaceRepository,
+	timeout time.Duration,
+	batchSize int,
+	maxAttempts int,
+	retryBase time.Duration,
+	allowPrivate bool,
+) *Usecase {
+	return &Usecase{
+		repository:          repository,
+		workspaceRepository: workspaceRepository,
+		client:              newHTTPClient(timeout, allowPrivate),
+		allowPrivate:        allowPrivate,
+		// Порция отправляется последовательно, аренда с запасом покрывает все запросы.
+		lease:       time.Duration(batchSize)*timeout + time.Minute,
+		batchSize:   batchSize,
+		maxAttempts: maxAttempts,
+		retryBase:   retryBase,
+	}
+}
+
+// CreateSubscription создает подписку. Подписываться на события пространства могут admin и owner.
+// Если секрет не задан, он генерируется; секрет возвращается только здесь.
+func (u *Usecase) CreateSubscription(ctx context.Context, sub Subscription) (Subscription, error) {
+	ctx, span := tracing.Start(ctx, "webhook.CreateSubscription")
+	defer span.End()
+
+	if sub.WorkspaceID != 0 {
+		if err := u.requireWorkspaceAdmin(ctx, sub.WorkspaceID, sub.UserID); err != nil {
+			return Subscription{}, err
+		}
+	}
+
+	parsed, err := url.Parse(sub.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return Subscription{}, ErrInvalidURL
+	}
+
+	// Явно внутренний адрес отклоняем сразу. Имена, которые разрешаются во внутренние адреса,
+	// отсекаются при подключении.
+	if !u.allowPrivate && isPrivateHost(parsed.Hostname()) {
+		return Subscription{}, fmt.Errorf("%w: %v", ErrInvalidURL, ErrPrivateAddress)
+	}
+
+	for _, eventType := range sub.Events {
+		if _, ok := eventTypes[eventType]; !ok {
+			return Subscription{}, fmt.Errorf("%w: %s", ErrUnknownEventType, eventType)
+		}
+	}
+
+	if sub.Secret == "" {
+		sub.Secret, err = randomHex(secretBytes)
+		if err != nil {
+			return Subscription{}, fmt.Errorf("generate secret: %w", err)
+		}
+	}
+
+	sub.ID, err = u.repository.CreateSubscription(ctx, repo.Subscription{
+		UserID:      sub.UserID,
+		WorkspaceID: sql.NullInt64{Int64: sub.WorkspaceID, Valid: sub.WorkspaceID != 0},
+		URL:         sub.URL,
+		Secret:      sub.Secret,
+		Events:      sub.Events,
+	})
+	if err != nil {
+		return Subscription{}, fmt.Errorf("repo create subscription: %w", err)
+	}
+
+	return sub, nil
+}
+
+// GetSubscriptions возвращает личные подписки пользователя или, если указан workspaceID,
+// подписки пространства. Секреты не возвращаются.
+func (u *Usecase) GetSubscriptions(ctx context.Context, userID, workspaceID int64) ([]Subscription, error) {
+	ctx, span := tracing.Start(ctx, "webhook.GetSubscriptions")
+	defer span.End()
+
+	var subs []repo.Subscription
+	var err error
+
+	if workspaceID != 0 {
+		if err = u.requireWorkspaceAdmin(ctx, workspaceID, userID); err != nil {
+			return nil, err
+		}
+
+		subs, err = u.repository.GetWorkspaceSubscriptions(ctx, workspaceID)
+	} else {
+		subs, err = u.repository.GetUserSubscriptions(ctx, userID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("repo get subscriptions: %w", err)
+	}
+
+	res := make([]Subscription, 0, len(subs))
+	for _, sub := range subs {
+		res = append(res, convertFromRepoSubscription(sub))
+	}
+
+	return res, nil
+}
+
+func (u *Usecase) DeleteSubscription(ctx context.Context, subscriptionID, userID int64) error {
+	ctx, span := tracing.Start(ctx, "webhook.DeleteSubscription")
+	defer span.End()
+
+	if _, err := u.getSubscription(ctx, subscriptionID, userID); err != nil {
+		return err
+	}
+
+	err := u.repository.DeleteSubscription(ctx, subscriptionID)
+	if err != nil {
+		if errors.Is(err, repo.ErrSubscriptionNotFound) {
+			return ErrSubscriptionNotFound
+		}
+
+		return fmt.Errorf("repo delete subscription: %w", err)
+	}
+
+	return nil
+}
+
+// GetDeliveries возвращает журнал доставок подписки, начиная с самых новых.
+func (u *Usecase) GetDeliveries(ctx context.Context, subscriptionID, userID, beforeID int64, limit int) ([]Delivery, error) {
+	ctx, span := tracing.Start(ctx, "webhook.GetDeliveries")
+	defer span.End()
+
+	if _, err := u.getSubscription(ctx, subscriptionID, userID); err != nil {
+		return nil, err
+	}
+
+	deliveries, err := u.repository.GetDeliveries(ctx, subscriptionID, beforeID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("repo get deliveries: %w", err)
+	}
+
+	res := make([]Delivery, 0, len(deliveries))
+	for _, d := range deliveries {
+		res = append(res, convertFromRepoDelivery(d))
+	}
+
+	return res, nil
+}
+
+// Redeliver повторно ставит в очередь событие из журнала доставок, например после того,
+// как получатель исправил ошибку. Событие отправляется с тем же идентификатором.
+func (u *Usecase) Redeliver(ctx context.Context, subscriptionID, deliveryID, userID int64) (int64, error) {
+	ctx, span := tracing.Start(ctx, "webhook.Redeliver")
+	defer span.End()
+
+	if _, err := u.getSubscription(ctx, subscriptionID, userID); err != nil {
+		return 0, err
+	}
+
+	id, err := u.repository.Redeliver(ctx, subscriptionID, deliveryID)
+	if err != nil {
+		if errors.Is(err, repo.ErrDeliveryNotFound) {
+			return 0, ErrDeliveryNotFound
+		}
+
+		return 0, fmt.Errorf("repo redeliver: %w", err)
+	}
+
+	return id, nil
+}
+
+// Publish ставит событие из outbox в очередь доставки подписчикам. События без
+// соответствующего типа вебхука игнорируются. Повторная публикация того же события
+// не создает новых доставок: идентификатором события служит ключ из outbox.
+func (u *Usecase) Publish(ctx context.Context, msg outboxUC.Message) error {
+	ctx, span := tracing.Start(ctx, "webhook.Publish")
+	defer span.End()
+
+	suffix, ok := actionEvents[msg.Action]
+	if !ok {
+		return nil
+	}
+
+	eventType := msg.EntityType + "." + suffix
+	if _, ok = eventTypes[eventType]; !ok {
+		return nil
+	}
+
+	subs, err := u.repository.GetMatchingSubscriptions(ctx, msg.ActorID, msg.WorkspaceID, eventType)
+	if err != nil {
+		return fmt.Errorf("repo get matching subscriptions: %w", err)
+	}
+
+	if len(subs) == 0 {
+		return nil
+	}
+
+	payload := Payload{
+		ID:          msg.Key,
+		Type:        eventType,
+		CreatedAt:   msg.CreatedAt,
+		ActorID:     msg.ActorID,
+		WorkspaceID: msg.WorkspaceID,
+		EntityID:    msg.EntityID,
+		Data:        msg.After,
+	}
+	switch msg.Action {
+	case auditUC.ActionDelete:
+		payload.Data = msg.Before
+	case auditUC.ActionUpdate:
+		payload.Previous = msg.Before
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal payload: %w", err)
+	}
+
+	subscriptionIDs := make([]int64, 0, len(subs))
+	for _, sub := range subs {
+		subscriptionIDs = append(subscriptionIDs, sub.ID)
+	}
+
+	if err = u.repository.CreateDeliveries(ctx, subscriptionIDs, msg.Key, eventType, body); err != nil {
+		return fmt.Errorf("repo create deliveries: %w", err)
+	}
+
+	return nil
+}
+
+// DeliverPending отправляет очередную порцию ожидающих доставок и возвращает их количество.
+// Неудачная доставка повторяется с экспоненциально растущей задержкой, после maxAttempts
+// попыток она помечается неудачной.
+func (u *Usecase) DeliverPending(ctx context.Context) (int, error) {
+	ctx, span := tracing.Start(ctx, "webhook.DeliverPending")
+	defer span.End()
+
+	deliveries, err := u.repository.ClaimDeliveries(ctx, u.batchSize, u.lease)
+	if err != nil {
+		return 0, fmt.Errorf("repo claim deliveries: %w", err)
+	}
+
+	for _, d := range deliveries {
+		attempt := repo.Attempt{
+			DeliveryID: d.ID,
+			Status:     StatusSucceeded,
+		}
+
+		attempt.ResponseStatus, err = u.send(ctx, d)
+		if err != nil {
+			attempt.Error = err.Error()
+			attempt.Status = StatusPending
+			attempt.RetryDelay = utils.RetryDelay(u.retryBase, maxRetryDelay, d.Attempts+1)
+			if d.Attempts+1 >= u.maxAttempts {
+				attempt.Status = StatusFailed
+			}
+		}
+
+		if err = u.repository.SaveAttempt(ctx, attempt); err != nil {
+			return 0, fmt.Errorf("repo save attempt: %w", err)
+		}
+	}
+
+	return len(deliveries), nil
+}
+
+// getSubscription возвращает подписку, которой пользователь может управлять:
+// личную подписку — создатель, подписку пространства — admin и owner.
+func (u *Usecase) getSubscription(ctx context.Context, subscriptionID, userID int64) (repo.Subscription, error) {
+	sub, err := u.repository.GetSubscription(ctx, subscriptionID)
+	if err != nil {
+		if errors.Is(err, repo.ErrSubscriptionNotFound) {
+			return repo.Subscription{}, ErrSubscriptionNotFound
+		}
+
+		return repo.Subscription{}, fmt.Errorf("repo get subscription: %w", err)
+	}
+
+	if !sub.WorkspaceID.Valid {
+		if sub.UserID != userID {
+			return repo.Subscription{}, ErrSubscriptionNotFound
+		}
+
+		return sub, nil
+	}
+
+	if err = u.requireWorkspaceAdmin(ctx, sub.WorkspaceID.Int64, userID); err != nil {
+		return repo.Subscription{}, err
+	}
+
+	return sub, nil
+}
+
+func (u *Usecase) requireWorkspaceAdmin(ctx context.Context, workspaceID, userID int64) error {
+	role, err := u.workspaceRepository.GetMemberRole(ctx, workspaceID, userID)
+	if err != nil {
+		if errors.Is(err, workspaceRepoDto.ErrMemberNotFound) {
+			return ErrForbidden
+		}
+
+		return fmt.Errorf("repo get member role: %w", err)
+	}
+
+	if !roles.Role(role).AtLeast(roles.Admin) {
+		return ErrForbidden
+	}
+
+	return nil
+}
+
+func isPrivateHost(host string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return true
+	}
+
+	addr, err := netip.ParseAddr(host)
+	return err == nil && isPrivateAddr(addr)
+}
+
+func randomHex(n int) (string, error) {
+	raw := make([]byte, n)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(raw), nil
+}
+
+func convertFromRepoSubscription(sub repo.Subscription) Subscription {
+	return Subscription{
+		ID:          sub.ID,
+		UserID:      sub.UserID,
+		WorkspaceID: sub.WorkspaceID.Int64,
+		URL:         sub.URL,
+		Events:      sub.Events,
+		CreatedAt:   sub.CreatedAt,
+	}
+}
+
+func convertFromRepoDelivery(d repo.Delivery) Delivery {
+	delivery := Delivery{
+		ID:             d.ID,
+		SubscriptionID: d.SubscriptionID,
+		EventID:        d.EventID,
+		EventType:      d.EventType,
+		Payload:        d.Payload,
+		Status:         d.Status,
+		Attempts:       d.Attempts,
+		NextAttemptAt:  d.NextAttemptAt,
+		ResponseStatus: int(d.ResponseStatus.Int64),
+		LastError:      d.LastError.String,
+		CreatedAt:      d.CreatedAt,
+	}
+	if d.DeliveredAt.Valid {
+		delivery.DeliveredAt = &d.DeliveredAt.Time
+	}
+
+	return delivery
+}
@@ -0,0 +1,403 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	repo "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/webhook/repository"
+)
+
+const (
+	testUserID = 1
+	testSecret = "test-secret"
+)
+
+// fakeRepository очередь доставок в памяти. Время ожидания доставки тест сдвигает сам через due.
+type fakeRepository struct {
+	repository
+
+	mu         sync.Mutex
+	subs       map[int64]repo.Subscription
+	deliveries []*repo.Delivery
+}
+
+func newFakeRepository(url string) *fakeRepository {
+	return &fakeRepository{
+		subs: map[int64]repo.Subscription{
+			1: {ID: 1, UserID: testUserID, URL: url, Secret: testSecret, Events: []string{EventEntryCreated}},
+		},
+	}
+}
+
+func (r *fakeRepository) enqueue(eventID string, payload string) *repo.Delivery {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	d := &repo.Delivery{
+		ID:             int64(len(r.deliveries) + 1),
+		SubscriptionID: 1,
+		EventID:        eventID,
+		EventType:      EventEntryCreated,
+		Payload:        []byte(payload),
+		Status:         StatusPending,
+		NextAttemptAt:  time.Now(),
+	}
+	r.deliveries = append(r.deliveries, d)
+
+	return d
+}
+
+// due делает доставку готовой к отправке, как будто задержка уже прошла.
+func (r *fakeRepository) due(d *repo.Delivery) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	d.NextAttemptAt = time.Now()
+}
+
+func (r *fakeRepository) GetSubscription(_ context.Context, subscriptionID int64) (repo.Subscription, error) {
+	sub, ok := r.subs[subscriptionID]
+	if !ok {
+		return repo.Subscription{}, repo.ErrSubscriptionNotFound
+	}
+
+	return sub, nil
+}
+
+func (r *fakeRepository) CreateSubscription(_ context.Context, sub repo.Subscription) (int64, error) {
+	sub.ID = int64(len(r.subs) + 1)
+	r.subs[sub.ID] = sub
+
+	return sub.ID, nil
+}
+
+func (r *fakeRepository) ClaimDeliveries(_ context.Context, limit int, lease time.Duration) ([]repo.PendingDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var res []repo.PendingDelivery
+	for _, d := range r.deliveries {
+		if len(res) == limit {
+			break
+		}
+		if d.Status != StatusPending || d.NextAttemptAt.After(time.Now()) {
+			continue
+		}
+
+		d.NextAttemptAt = time.Now().Add(lease)
+		sub := r.subs[d.SubscriptionID]
+		res = append(res, repo.PendingDelivery{Delivery: *d, URL: sub.URL, Secret: sub.Secret})
+	}
+
+	return res, nil
+}
+
+func (r *fakeRepository) SaveAttempt(_ context.Context, attempt repo.Attempt) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	d := r.deliveries[attempt.DeliveryID-1]
+	d.Attempts++
+	d.Status = attempt.Status
+	d.NextAttemptAt = time.Now().Add(attempt.RetryDelay)
+	d.ResponseStatus = sql.NullInt64{Int64: int64(attempt.ResponseStatus), Valid: attempt.ResponseStatus != 0}
+	d.LastError = sql.NullString{String: attempt.Error, Valid: attempt.Error != ""}
+
+	return nil
+}
+
+func (r *fakeRepository) Redeliver(_ context.Context, subscriptionID, deliveryID int64) (int64, error) {
+	r.mu.Lock()
+	src := r.deliveries[deliveryID-1]
+	r.mu.Unlock()
+
+	if src.SubscriptionID != subscriptionID {
+		return 0, repo.ErrDeliveryNotFound
+	}
+
+	return r.enqueue(src.EventID, string(src.Payload)).ID, nil
+}
+
+type fakeWorkspaceRepository struct{}
+
+func (fakeWorkspaceRepository) GetMemberRole(context.Context, int64, int64) (string, error) {
+	return "", errors.New("unexpected call")
+}
+
+// receiver получатель вебхуков: отвечает status и запоминает запросы.
+type receiver struct {
+	mu       sync.Mutex
+	status   int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	rc.requests = append(rc.requests, r)
+	rc.bodies = append(rc.bodies, body)
+	w.WriteHeader(rc.status)
+}
+
+func (rc *receiver) setStatus(status int) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	rc.status = status
+}
+
+func (rc *receiver) count() int {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	return len(rc.requests)
+}
+
+func newTestUsecase(t *testing.T, status int, allowPrivate bool) (*Usecase, *fakeRepository, *receiver) {
+	t.Helper()
+
+	rc := &receiver{status: status}
+	server := httptest.NewServer(rc)
+	t.Cleanup(server.Close)
+
+	r := newFakeRepository(server.URL)
+	u := NewUsecase(r, fakeWorkspaceRepository{}, 5*time.Second, 10, 3, time.Minute, allowPrivate)
+
+	return u, r, rc
+}
+
+func deliverPending(t *testing.T, u *Usecase, want int) {
+	t.Helper()
+
+	n, err := u.DeliverPending(context.Background())
+	if err != nil {
+		t.Fatalf("deliver pending: %v", err)
+	}
+	if n != want {
+		t.Fatalf("delivered %d, want %d", n, want)
+	}
+}
+
+func TestDeliverPendingSignsRequest(t *testing.T) {
+	u, r, rc := newTestUsecase(t, http.StatusNoContent, true)
+
+	payload := `{"id":"evt-1","type":"entry.created"}`
+	d := r.enqueue("evt-1", payload)
+
+	before := time.Now().Unix()
+	deliverPending(t, u, 1)
+
+	if rc.count() != 1 {
+		t.Fatalf("receiver got %d requests, want 1", rc.count())
+	}
+
+	req, body := rc.requests[0], rc.bodies[0]
+	if req.Method != http.MethodPost || string(body) != payload {
+		t.Errorf("got %s %q, want POST %q", req.Method, body, payload)
+	}
+
+	headers := map[string]string{
+		"Content-Type":  "application/json",
+		"User-Agent":    "timetracker-webhooks/1",
+		HeaderEventID:   "evt-1",
+		HeaderEventType: EventEntryCreated,
+	}
+	for name, want := range headers {
+		if got := req.Header.Get(name); got != want {
+			t.Errorf("header %s: got %q, want %q", name, got, want)
+		}
+	}
+
+	timestamp, err := strconv.ParseInt(req.Header.Get(HeaderTimestamp), 10, 64)
+	if err != nil || timestamp < before || timestamp > time.Now().Unix() {
+		t.Fatalf("bad timestamp %q", req.Header.Get(HeaderTimestamp))
+	}
+
+	// Получатель проверяет HMAC от "<timestamp>.<body>".
+	if got, want := req.Header.Get(HeaderSignature), Sign(testSecret, timestamp, body); got != want {
+		t.Errorf("signature: got %q, want %q", got, want)
+	}
+	if !strings.HasPrefix(req.Header.Get(HeaderSignature), "sha256=") {
+		t.Errorf("signature %q has no algorithm prefix", req.Header.Get(HeaderSignature))
+	}
+
+	if d.Status != StatusSucceeded || d.Attempts != 1 || d.ResponseStatus.Int64 != http.StatusNoContent {
+		t.Errorf("delivery: status %s, attempts %d, response %d", d.Status, d.Attempts, d.ResponseStatus.Int64)
+	}
+}
+
+func TestSign(t *testing.T) {
+	// Посчитано независимо: printf '1700000000.{}' | openssl dgst -sha256 -hmac test-secret
+	want := "sha256=87d3ed18b9b403e7da0fc3a3ae8b9394303805a049ea06f87c2ef4380b521fa9"
+	if got := Sign(testSecret, 1700000000, []byte("{}")); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestDeliverPendingRetriesServerErrors(t *testing.T) {
+	u, r, rc := newTestUsecase(t, http.StatusServiceUnavailable, true)
+	d := r.enqueue("evt-1", `{}`)
+
+	// Попытки 1 и 2 откладывают доставку на retryBase и 2*retryBase, третья последняя.
+	for attempt, wantDelay := range []time.Duration{time.Minute, 2 * time.Minute} {
+		before := time.Now()
+		deliverPending(t, u, 1)
+
+		if d.Status != StatusPending || d.Attempts != attempt+1 {
+			t.Fatalf("attempt %d: status %s, attempts %d", attempt+1, d.Status, d.Attempts)
+		}
+		if d.ResponseStatus.Int64 != http.StatusServiceUnavailable || !strings.Contains(d.LastError.String, "503") {
+			t.Errorf("attempt %d: response %d, error %q", attempt+1, d.ResponseStatus.Int64, d.LastError.String)
+		}
+
+		delay := d.NextAttemptAt.Sub(before)
+		if delay < wantDelay || delay > wantDelay+time.Second {
+			t.Errorf("attempt %d: next attempt in %v, want %v", attempt+1, delay, wantDelay)
+		}
+
+		// До истечения задержки доставка не отправляется.
+		deliverPending(t, u, 0)
+		r.due(d)
+	}
+
+	deliverPending(t, u, 1)
+	if d.Status != StatusFailed || d.Attempts != 3 {
+		t.Errorf("after max attempts: status %s, attempts %d", d.Status, d.Attempts)
+	}
+
+	r.due(d)
+	deliverPending(t, u, 0)
+	if rc.count() != 3 {
+		t.Errorf("receiver got %d requests, want 3", rc.count())
+	}
+}
+
+func TestRedeliver(t *testing.T) {
+	u, r, rc := newTestUsecase(t, http.StatusInternalServerError, true)
+	u.maxAttempts = 1
+
+	d := r.enqueue("evt-1", `{"id":"evt-1"}`)
+	deliverPending(t, u, 1)
+	if d.Status != StatusFailed {
+		t.Fatalf("delivery status %s, want failed", d.Status)
+	}
+
+	// Получатель исправил ошибку, и пользователь повторяет доставку вручную.
+	rc.setStatus(http.StatusOK)
+
+	if _, err := u.Redeliver(context.Background(), 1, d.ID, testUserID+1); !errors.Is(err, ErrSubscriptionNotFound) {
+		t.Errorf("redeliver by another user: got %v, want %v", err, ErrSubscriptionNotFound)
+	}
+
+	id, err := u.Redeliver(context.Background(), 1, d.ID, testUserID)
+	if err != nil {
+		t.Fatalf("redeliver: %v", err)
+	}
+
+	deliverPending(t, u, 1)
+
+	redelivery := r.deliveries[id-1]
+	if redelivery.Status != StatusSucceeded {
+		t.Errorf("redelivery status %s, want succeeded", redelivery.Status)
+	}
+	if got := rc.requests[1].Header.Get(HeaderEventID); got != "evt-1" {
+		t.Errorf("redelivery event id %q, want the original evt-1", got)
+	}
+	if string(rc.bodies[1]) != string(rc.bodies[0]) {
+		t.Errorf("redelivery body %q differs from %q", rc.bodies[1], rc.bodies[0])
+	}
+}
+
+func TestDeliverPendingRefusesPrivateAddresses(t *testing.T) {
+	// httptest слушает 127.0.0.1: без allow-private-networks подключаться к нему нельзя.
+	u, r, rc := newTestUsecase(t, http.StatusOK, false)
+	d := r.enqueue("evt-1", `{}`)
+
+	deliverPending(t, u, 1)
+
+	if rc.count() != 0 {
+		t.Errorf("receiver on loopback got %d requests", rc.count())
+	}
+	if d.Status != StatusPending || !strings.Contains(d.LastError.String, ErrPrivateAddress.Error()) {
+		t.Errorf("delivery: status %s, error %q", d.Status, d.LastError.String)
+	}
+}
+
+func TestCreateSubscriptionValidatesURL(t *testing.T) {
+	tests := []struct {
+		url          string
+		allowPrivate bool
+		wantErr      error
+	}{
+		{"https://hooks.example.com/tt", false, nil},
+		{"http://203.0.113.10:8080/hook", false, nil},
+		{"ftp://hooks.example.com/tt", false, ErrInvalidURL},
+		{"/relative", false, ErrInvalidURL},
+		{"http://localhost:8080/hook", false, ErrInvalidURL},
+		{"http://api.localhost/hook", false, ErrInvalidURL},
+		{"http://127.0.0.1/hook", false, ErrInvalidURL},
+		{"http://[::1]/hook", false, ErrInvalidURL},
+		{"http://[::ffff:127.0.0.1]/hook", false, ErrInvalidURL},
+		{"http://10.1.2.3/hook", false, ErrInvalidURL},
+		{"http://172.16.0.5/hook", false, ErrInvalidURL},
+		{"http://192.168.1.1/hook", false, ErrInvalidURL},
+		{"http://169.254.169.254/latest/meta-data", false, ErrInvalidURL},
+		{"http://0.0.0.0/hook", false, ErrInvalidURL},
+		{"http://[fd00::1]/hook", false, ErrInvalidURL},
+		{"http://localhost:8080/hook", true, nil},
+		{"http://10.1.2.3/hook", true, nil},
+	}
+
+	for _, tt := range tests {
+		u := NewUsecase(newFakeRepository(""), fakeWorkspaceRepository{}, time.Second, 1, 1, time.Second, tt.allowPrivate)
+
+		_, err := u.CreateSubscription(context.Background(), Subscription{
+			UserID: testUserID,
+			URL:    tt.url,
+			Events: []string{EventEntryCreated},
+		})
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s (allow private %v): got %v, want %v", tt.url, tt.allowPrivate, err, tt.wantErr)
+		}
+	}
+}
+
+func TestCheckDialAddress(t *testing.T) {
+	tests := []struct {
+		address string
+		private bool
+	}{
+		{"93.184.216.34:443", false},
+		{"[2606:2800:220:1::1]:443", false},
+		{"127.0.0.1:80", true},
+		{"127.1.2.3:80", true},
+		{"[::1]:80", true},
+		{"10.0.0.1:5432", true},
+		{"100.64.0.1:80", false},
+		{"169.254.169.254:80", true},
+		{"[fe80::1]:80", true},
+		{"0.0.0.0:80", true},
+		{"[::]:80", true},
+		{"[::ffff:192.168.0.1]:80", true},
+	}
+
+	for _, tt := range tests {
+		err := checkDialAddress("tcp", tt.address, nil)
+		if got := errors.Is(err, ErrPrivateAddress); got != tt.private {
+			t.Errorf("%s: got error %v, want private %v", tt.address, err, tt.private)
+		}
+	}
+}
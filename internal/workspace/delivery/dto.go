@@ -0,0 +1,36 @@
+package delivery
+
+type CreateWorkspaceIn struct {
+	Name string `json:"name" validate:"required,max=35" example:"Команда"` // Название пространства.
+}
+
+type CreateWorkspaceOut struct {
+	ID int64 `json:"id" validate:"required" example:"1"` // Идентификатор пространства.
+}
+
+type WorkspaceOut struct {
+	ID      int64  `json:"id" example:"1"`         // Идентификатор пространства.
+	OwnerID int64  `json:"owner_id" example:"1"`   // Идентификатор владельца.
+	Name    string `json:"name" example:"Команда"` // Название пространства.
+	Role    string `json:"role" example:"owner"`   // Роль текущего пользователя в пространстве.
+}
+
+type InviteMemberIn struct {
+	Email string `json:"email" validate:"required" example:"user@mail.ru"`                    // Email приглашаемого пользователя.
+	Role  string `json:"role" validate:"required,oneof=admin member viewer" example:"member"` // Роль в пространстве.
+}
+
+type InviteMemberOut struct {
+	UserID int64 `json:"user_id" example:"2"` // Идентификатор добавленного пользователя.
+}
+
+type UpdateMemberIn struct {
+	Role string `json:"role" validate:"required,oneof=admin member viewer" example:"viewer"` // Новая роль в пространстве.
+}
+
+type MemberOut struct {
+	UserID int64  `json:"user_id" example:"2"`          // Идентификатор пользователя.
+	Name   string `json:"name" example:"Иван"`          // Имя пользователя.
+	Email  string `json:"email" example:"user@mail.ru"` // Email пользователя.
+	Role   string `json:"role" example:"member"`        // Роль в пространстве.
+}
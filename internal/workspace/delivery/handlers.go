@@ -0,0 +1,367 @@
+package delivery
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/response"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/roles"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/validator"
+	usecaseDto "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/workspace/usecase"
+)
+
+type usecase interface {
+	CreateWorkspace(ctx context.Context, workspace usecaseDto.Workspace) (int64, error)
+	GetUserWorkspaces(ctx context.Context, userID int64) ([]usecaseDto.Workspace, error)
+	GetMembers(ctx context.Context, workspaceID, actorID int64) ([]usecaseDto.Member, error)
+	InviteMember(ctx context.Context, workspaceID, actorID int64, email string, role roles.Role) (int64, error)
+	UpdateMemberRole(ctx context.Context, workspaceID, actorID, userID int64, role roles.Role) error
+	RemoveMember(ctx context.Context, workspaceID, actorID, userID int64) error
+}
+
+type Delivery struct {
+	usecase usecase
+
+	logger echo.Logger
+}
+
+func RegisterHandlers(
+	e *echo.Echo,
+	usecase usecase,
+	logger echo.Logger,
+) {
+	handler := &Delivery{
+		usecase: usecase,
+
+		logger: logger,
+	}
+
+	e.POST("/workspaces/create", handler.CreateWorkspace)
+	e.GET("/me/workspaces", handler.GetMyWorkspaces)
+	e.GET("/workspaces/:workspace_id/members", handler.GetMembers)
+	e.POST("/workspaces/:workspace_id/members", handler.InviteMember)
+	e.PUT("/workspaces/:workspace_id/members/:user_id", handler.UpdateMember)
+	e.DELETE("/workspaces/:workspace_id/members/:user_id", handler.RemoveMember)
+}
+
+// CreateWorkspace godoc
+// @Summary      Создать пространство.
+// @Description  Создать общее пространство, текущий пользователь становится его владельцем.
+// @Tags     	 workspaces
+// @Accept	 application/json
+// @Produce  application/json
+// @Param    workspace body CreateWorkspaceIn true "workspace info"
+// @Success  200 {object} CreateWorkspaceOut "success create workspace"
+// @Failure 500 {object} response.Error "internal server error"
+// @Failure 400 {object} response.Error "bad request"
+// @Failure 422 {object} response.Error "unprocessable entity"
+// @Router   /workspaces/create [post]
+func (d *Delivery) CreateWorkspace(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var in CreateWorkspaceIn
+	err := c.Bind(&in)
+
+	if err != nil {
+		c.Logger().Errorf("bind request: %v", err)
+		return response.Status(http.StatusUnprocessableEntity)
+	}
+
+	if ok, err := validator.IsRequestValid(&in); !ok {
+		c.Logger().Errorf("validation: %v", err)
+		return response.ValidationError(err)
+	}
+
+	userID, ok := c.Get("user_id").(int64)
+	if !ok {
+		c.Logger().Error("can't parse context user_id")
+		return response.Status(http.StatusInternalServerError)
+	}
+
+	workspaceID, err := d.usecase.CreateWorkspace(ctx, usecaseDto.Workspace{
+		OwnerID: userID,
+		Name:    in.Name,
+	})
+	if err != nil {
+		c.Logger().Errorf("usecase: %v", err)
+		return handleUsecaseError(err)
+	}
+
+	out := CreateWorkspaceOut{ID: workspaceID}
+
+	return c.JSON(http.StatusOK, out)
+}
+
+// GetMyWorkspaces godoc
+// @Summary      Получить список пространств.
+// @Description  Получить список пространств, в которых состоит пользователь, с его ролью.
+// @Tags     	 workspaces
+// @Accept	 	application/json
+// @Produce  	application/json
+// @Success  200 {object} []WorkspaceOut "success get workspaces"
+// @Failure 500 {object} response.Error "internal server error"
+// @Router   /me/workspaces [get]
+func (d *Delivery) GetMyWorkspaces(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, ok := c.Get("user_id").(int64)
+	if !ok {
+		c.Logger().Error("can't parse context user_id")
+		return response.Status(http.StatusInternalServerError)
+	}
+
+	workspaces, err := d.usecase.GetUserWorkspaces(ctx, userID)
+	if err != nil {
+		c.Logger().Errorf("usecase: %v", err)
+		return handleUsecaseError(err)
+	}
+
+	out := make([]WorkspaceOut, 0, len(workspaces))
+	for _, w := range workspaces {
+		out = append(out, WorkspaceOut{
+			ID:      w.ID,
+			OwnerID: w.OwnerID,
+			Name:    w.Name,
+			Role:    string(w.Role),
+		})
+	}
+
+	return c.JSON(http.StatusOK, out)
+}
+
+// GetMembers godoc
+// @Summary      Получить участников пространства.
+// @Description  Получить участников пространства, доступно любому участнику.
+// @Tags     	 workspaces
+// @Accept	 	application/json
+// @Produce  	application/json
+// @Param    workspace_id path int true "Идентификатор пространства"
+// @Success  200 {object} []MemberOut "success get members"
+// @Failure 500 {object} response.Error "internal server error"
+// @Failure 400 {object} response.Error "bad request"
+// @Failure 403 {object} response.Error "forbidden"
+// @Router   /workspaces/{workspace_id}/members [get]
+func (d *Delivery) GetMembers(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	workspaceID, err := strconv.ParseInt(c.Param("workspace_id"), 10, 64)
+	if err != nil {
+		c.Logger().Errorf("parse int: %v", err)
+		return response.InvalidParam("workspace_id", "int")
+	}
+
+	userID, ok := c.Get("user_id").(int64)
+	if !ok {
+		c.Logger().Error("can't parse context user_id")
+		return response.Status(http.StatusInternalServerError)
+	}
+
+	members, err := d.usecase.GetMembers(ctx, workspaceID, userID)
+	if err != nil {
+		c.Logger().Errorf("usecase: %v", err)
+		return handleUsecaseError(err)
+	}
+
+	out := make([]MemberOut, 0, len(members))
+	for _, m := range members {
+		out = append(out, MemberOut{
+			UserID: m.UserID,
+			Name:   m.Name,
+			Email:  m.Email,
+			Role:   string(m.Role),
+		})
+	}
+
+	return c.JSON(http.StatusOK, out)
+}
+
+// InviteMember godoc
+// @Summary      Пригласить участника.
+// @Description  Добавить в пространство зарегистрированного пользователя по email. Доступно admin и owner, роль admin выдает только owner.
+// @Tags     	 workspaces
+// @Accept	 application/json
+// @Produce  application/json
+// @Param    workspace_id path int true "Идентификатор пространства"
+// @Param    member body InviteMemberIn true "Информация об участнике"
+// @Success  200 {object} InviteMemberOut "success invite member"
+// @Failure 500 {object} response.Error "internal server error"
+// @Failure 400 {object} response.Error "bad request"
+// @Failure 403 {object} response.Error "forbidden"
+// @Failure 404 {object} response.Error "item is not found"
+// @Failure 409 {object} response.Error "conflict"
+// @Failure 422 {object} response.Error "unprocessable entity"
+// @Router   /workspaces/{workspace_id}/members [post]
+func (d *Delivery) InviteMember(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	workspaceID, err := strconv.ParseInt(c.Param("workspace_id"), 10, 64)
+	if err != nil {
+		c.Logger().Errorf("parse int: %v", err)
+		return response.InvalidParam("workspace_id", "int")
+	}
+
+	var in InviteMemberIn
+	err = c.Bind(&in)
+
+	if err != nil {
+		c.Logger().Errorf("bind request: %v", err)
+		return response.Status(http.StatusUnprocessableEntity)
+	}
+
+	if ok, err := validator.IsRequestValid(&in); !ok {
+		c.Logger().Errorf("validation: %v", err)
+		return response.ValidationError(err)
+	}
+
+	userID, ok := c.Get("user_id").(int64)
+	if !ok {
+		c.Logger().Error("can't parse context user_id")
+		return response.Status(http.StatusInternalServerError)
+	}
+
+	memberID, err := d.usecase.InviteMember(ctx, workspaceID, userID, in.Email, roles.Role(in.Role))
+	if err != nil {
+		c.Logger().Errorf("usecase: %v", err)
+		return handleUsecaseError(err)
+	}
+
+	out := InviteMemberOut{UserID: memberID}
+
+	return c.JSON(http.StatusOK, out)
+}
+
+// UpdateMember godoc
+// @Summary      Изменить роль участника.
+// @Description  Изменить роль участника пространства. Роль владельца не меняется, администраторов меняет только owner.
+// @Tags     	 workspaces
+// @Accept	 application/json
+// @Produce  application/json
+// @Param    workspace_id path int true "Идентификатор пространства"
+// @Param    user_id path int true "Идентификатор участника"
+// @Param    member body UpdateMemberIn true "Новая роль"
+// @Success  200  "success update member"
+// @Failure 500 {object} response.Error "internal server error"
+// @Failure 400 {object} response.Error "bad request"
+// @Failure 403 {object} response.Error "forbidden"
+// @Failure 404 {object} response.Error "item is not found"
+// @Failure 422 {object} response.Error "unprocessable entity"
+// @Router   /workspaces/{workspace_id}/members/{user_id} [put]
+func (d *Delivery) UpdateMember(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	workspaceID, err := strconv.ParseInt(c.Param("workspace_id"), 10, 64)
+	if err != nil {
+		c.Logger().Errorf("parse int: %v", err)
+		return response.InvalidParam("workspace_id", "int")
+	}
+
+	memberID, err := strconv.ParseInt(c.Param("user_id"), 10, 64)
+	if err != nil {
+		c.Logger().Errorf("parse int: %v", err)
+		return response.InvalidParam("user_id", "int")
+	}
+
+	var in UpdateMemberIn
+	err = c.Bind(&in)
+
+	if err != nil {
+		c.Logger().Errorf("bind request: %v", err)
+		return response.Status(http.StatusUnprocessableEntity)
+	}
+
+	if ok, err := validator.IsRequestValid(&in); !ok {
+		c.Logger().Errorf("validation: %v", err)
+		return response.ValidationError(err)
+	}
+
+	userID, ok := c.Get("user_id").(int64)
+	if !ok {
+		c.Logger().Error("can't parse context user_id")
+		return response.Status(http.StatusInternalServerError)
+	}
+
+	err = d.usecase.UpdateMemberRole(ctx, workspaceID, userID, memberID, roles.Role(in.Role))
+	if err != nil {
+		c.Logger().Errorf("usecase: %v", err)
+		return handleUsecaseError(err)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+// RemoveMember godoc
+// @Summary      Удалить участника.
+// @Description  Удалить участника из пространства. Участник может удалить сам себя, владелец пространство не покидает.
+// @Tags     	 workspaces
+// @Accept	 application/json
+// @Produce  application/json
+// @Param    workspace_id path int true "Идентификатор пространства"
+// @Param    user_id path int true "Идентификатор участника"
+// @Success  200  "success remove member"
+// @Failure 500 {object} response.Error "internal server error"
+// @Failure 400 {object} response.Error "bad request"
+// @Failure 403 {object} response.Error "forbidden"
+// @Failure 404 {object} response.Error "item is not found"
+// @Router   /workspaces/{workspace_id}/members/{user_id} [delete]
+func (d *Delivery) RemoveMember(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	workspaceID, err := strconv.ParseInt(c.Param("workspace_id"), 10, 64)
+	if err != nil {
+		c.Logger().Errorf("parse int: %v", err)
+		return response.InvalidParam("workspace_id", "int")
+	}
+
+	memberID, err := strconv.ParseInt(c.Param("user_id"), 10, 64)
+	if err != nil {
+		c.Logger().Errorf("parse int: %v", err)
+		return response.InvalidParam("user_id", "int")
+	}
+
+	userID, ok := c.Get("user_id").(int64)
+	if !ok {
+		c.Logger().Error("can't parse context user_id")
+		return response.Status(http.StatusInternalServerError)
+	}
+
+	err = d.usecase.RemoveMember(ctx, workspaceID, userID, memberID)
+	if err != nil {
+		c.Logger().Errorf("usecase: %v", err)
+		return handleUsecaseError(err)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+func handleUsecaseError(err error) *response.Error {
+	// Запрос не уложился в таймаут или был отменен.
+	if problem := response.ContextError(err); problem != nil {
+		return problem
+	}
+
+	if errors.Is(err, usecaseDto.ErrForbidden) {
+		return response.Status(http.StatusForbidden)
+	}
+
+	// Не нашли пользователя или участника.
+	if errors.Is(err, usecaseDto.ErrUserNotFound) {
+		return response.NotFound("user")
+	}
+	if errors.Is(err, usecaseDto.ErrMemberNotFound) {
+		return response.NotFound("member")
+	}
+
+	if errors.Is(err, usecaseDto.ErrMemberExists) {
+		return response.NewError(http.StatusConflict, "member_exists", response.ErrorMsgsByCode[http.StatusConflict])
+	}
+	if errors.Is(err, usecaseDto.ErrInvalidRole) {
+		return response.NewError(http.StatusBadRequest, "invalid_role", response.ErrorMsgsByCode[http.StatusBadRequest])
+	}
+
+	// По дефолту пятисотим.
+	return response.Status(http.StatusInternalServerError)
+}
@@ -0,0 +1,20 @@
+package repository
+
+type Workspace struct {
+	ID      int64  `db:"id"`
+	OwnerID int64  `db:"owner_id"`
+	Name    string `db:"name"`
+
+	// Роль текущего пользователя, заполняется только при получении списка пространств пользователя.
+	Role string `db:"role"`
+}
+
+type Member struct {
+	WorkspaceID int64  `db:"workspace_id"`
+	UserID      int64  `db:"user_id"`
+	Role        string `db:"role"`
+
+	// Поля только для чтения.
+	Name  string `db:"name"`
+	Email string `db:"email"`
+}
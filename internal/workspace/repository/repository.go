@@ -0,0 +1,283 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrWorkspaceNotFound = errors.New("workspace not found")
+	ErrMemberNotFound    = errors.New("member not found")
+	ErrMemberExists      = errors.New("member already exists")
+	ErrUserNotFound      = errors.New("user not found")
+)
+
+// Код ошибки postgres при нарушении уникальности.
+const uniqueViolationCode = "23505"
+
+type Repository struct {
+	db    *sqlx.DB
+	close func() error
+}
+
+func NewRepository(db *sqlx.DB) *Repository {
+	return &Repository{
+		db: db,
+		close: func() error {
+			return db.Close()
+		},
+	}
+}
+
+// Close закрывает пул соединений с базой.
+func (r *Repository) Close() error {
+	return r.close()
+}
+
+// CreateWorkspace создает пространство и добавляет в него владельца с ролью owner.
+func (r *Repository) CreateWorkspace(ctx context.Context, workspace Workspace) (int64, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin tx: %w", err)
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var id int64
+	err = tx.QueryRowContext(ctx,
+		`INSERT INTO workspaces
+				(
+					owner_id,
+					name
+				) VALUES ($1, $2) RETURNING id;`,
+		workspace.OwnerID,
+		workspace.Name,
+	).Scan(&id)
+
+	if err != nil {
+		return 0, fmt.Errorf("insert workspace: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO workspace_members
+				(
+					workspace_id,
+					user_id,
+					role
+				) VALUES ($1, $2, 'owner');`,
+		id,
+		workspace.OwnerID,
+	)
+
+	if err != nil {
+		return 0, fmt.Errorf("insert owner: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit: %w", err)
+	}
+
+	return id, nil
+}
+
+func (r *Repository) GetUserWorkspaces(ctx context.Context, userID int64) ([]Workspace, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT 
+			w.id,
+			w.owner_id,
+			w.name,
+			wm.role
+		FROM workspaces w
+		JOIN workspace_members wm ON wm.workspace_id = w.id
+		WHERE wm.user_id = $1
+		ORDER BY w.id`, userID)
+
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var workspaces []Workspace
+	for rows.Next() {
+		var workspace Workspace
+		if err = rows.Scan(
+			&workspace.ID,
+			&workspace.OwnerID,
+			&workspace.Name,
+			&workspace.Role,
+		); err != nil {
+			return nil, fmt.Errorf("scan: %w", err)
+		}
+
+		workspaces = append(workspaces, workspace)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("rows err: %w", rows.Err())
+	}
+
+	if len(workspaces) == 0 {
+		return nil, ErrWorkspaceNotFound
+	}
+
+	return workspaces, nil
+}
+
+func (r *Repository) GetMemberRole(ctx context.Context, workspaceID, userID int64) (string, error) {
+	var role string
+	err := r.db.QueryRowContext(ctx,
+		`SELECT role
+		FROM workspace_members
+		WHERE workspace_id = $1 AND user_id = $2`, workspaceID, userID).Scan(&role)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", ErrMemberNotFound
+		}
+
+		return "", fmt.Errorf("scan: %w", err)
+	}
+
+	return role, nil
+}
+
+func (r *Repository) GetMembers(ctx context.Context, workspaceID int64) ([]Member, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT 
+			wm.workspace_id,
+			wm.user_id,
+			wm.role,
+			u.name,
+			u.email
+		FROM workspace_members wm
+		JOIN users u ON u.id = wm.user_id
+		WHERE wm.workspace_id = $1
+		ORDER BY wm.user_id`, workspaceID)
+
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var members []Member
+	for rows.Next() {
+		var member Member
+		if err = rows.Scan(
+			&member.WorkspaceID,
+			&member.UserID,
+			&member.Role,
+			&member.Name,
+			&member.Email,
+		); err != nil {
+			return nil, fmt.Errorf("scan: %w", err)
+		}
+
+		members = append(members, member)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("rows err: %w", rows.Err())
+	}
+
+	if len(members) == 0 {
+		return nil, ErrMemberNotFound
+	}
+
+	return members, nil
+}
+
+func (r *Repository) GetUserIDByEmail(ctx context.Context, email string) (int64, error) {
+	var id int64
+	err := r.db.QueryRowContext(ctx, `SELECT id FROM users WHERE email = $1`, email).Scan(&id)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, ErrUserNotFound
+		}
+
+		return 0, fmt.Errorf("scan: %w", err)
+	}
+
+	return id, nil
+}
+
+func (r *Repository) AddMember(ctx context.Context, member Member) error {
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO workspace_members
+				(
+					workspace_id,
+					user_id,
+					role
+				) VALUES ($1, $2, $3);`,
+		member.WorkspaceID,
+		member.UserID,
+		member.Role,
+	)
+
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == uniqueViolationCode {
+			return ErrMemberExists
+		}
+
+		return fmt.Errorf("exec: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) UpdateMemberRole(ctx context.Context, workspaceID, userID int64, role string) error {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE workspace_members
+		SET role = $3
+		WHERE workspace_id = $1 AND user_id = $2`, workspaceID, userID, role)
+
+	if err != nil {
+		return fmt.Errorf("exec: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("rows affected: %w", err)
+	}
+
+	if affected == 0 {
+		return ErrMemberNotFound
+	}
+
+	return nil
+}
+
+func (r *Repository) DeleteMember(ctx context.Context, workspaceID, userID int64) error {
+	res, err := r.db.ExecContext(ctx,
+		`DELETE FROM workspace_members
+		WHERE workspace_id = $1 AND user_id = $2`, workspaceID, userID)
+
+	if err != nil {
+		return fmt.Errorf("exec: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("rows affected: %w", err)
+	}
+
+	if affected == 0 {
+		return ErrMemberNotFound
+	}
+
+	return nil
+}
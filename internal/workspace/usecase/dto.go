@@ -0,0 +1,22 @@
+package usecase
+
+import "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/roles"
+
+type Workspace struct {
+	ID      int64
+	OwnerID int64
+	Name    string
+
+	// Поля только для чтения.
+	Role roles.Role
+}
+
+type Member struct {
+	WorkspaceID int64
+	UserID      int64
+	Role        roles.Role
+
+	// Поля только для чтения.
+	Name  string
+	Email string
+}
@@ -0,0 +1,291 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/roles"
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/tracing"
+	repo "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/workspace/repository"
+)
+
+var (
+	ErrWorkspaceNotFound = errors.New("workspace not found")
+	ErrMemberNotFound    = errors.New("member not found")
+	ErrMemberExists      = errors.New("member already exists")
+	ErrUserNotFound      = errors.New("user not found")
+	ErrInvalidRole       = errors.New("invalid role")
+	ErrForbidden         = errors.New("forbidden")
+)
+
+type repository interface {
+	CreateWorkspace(ctx context.Context, workspace repo.Workspace) (int64, error)
+	GetUserWorkspaces(ctx context.Context, userID int64) ([]repo.Workspace, error)
+	GetMemberRole(ctx context.Context, workspaceID, userID int64) (string, error)
+	GetMembers(ctx context.Context, workspaceID int64) ([]repo.Member, error)
+	GetUserIDByEmail(ctx context.Context, email string) (int64, error)
+	AddMember(ctx context.Context, member repo.Member) error
+	UpdateMemberRole(ctx context.Context, workspaceID, userID int64, role string) error
+	DeleteMember(ctx context.Context, workspaceID, userID int64) error
+}
+
+type statsCache interface {
+	InvalidateProjects(ctx context.Context, userID int64)
+}
+
+type Usecase struct {
+	repository repository
+	statsCache statsCache
+}
+
+func NewUsecase(repository repository, statsCache statsCache) *Usecase {
+	return &Usecase{
+		repository: repository,
+		statsCache: statsCache,
+	}
+}
+
+func (u *Usecase) CreateWorkspace(ctx context.Context, workspace Workspace) (int64, error) {
+	ctx, span := tracing.Start(ctx, "workspace.CreateWorkspace")
+	defer span.End()
+
+	id, err := u.repository.CreateWorkspace(ctx, repo.Workspace{
+		OwnerID: workspace.OwnerID,
+		Name:    workspace.Name,
+	})
+
+	if err != nil {
+		return 0, fmt.Errorf("repo create workspace: %w", err)
+	}
+
+	return id, nil
+}
+
+func (u *Usecase) GetUserWorkspaces(ctx context.Context, userID int64) ([]Workspace, error) {
+	ctx, span := tracing.Start(ctx, "workspace.GetUserWorkspaces")
+	defer span.End()
+
+	repoWorkspaces, err := u.repository.GetUserWorkspaces(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repo.ErrWorkspaceNotFound) {
+			return []Workspace{}, nil
+		}
+		return nil, fmt.Errorf("repo get user workspaces: %w", err)
+	}
+
+	workspaces := make([]Workspace, 0, len(repoWorkspaces))
+	for _, w := range repoWorkspaces {
+		workspaces = append(workspaces, Workspace{
+			ID:      w.ID,
+			OwnerID: w.OwnerID,
+			Name:    w.Name,
+			Role:    roles.Role(w.Role),
+		})
+	}
+
+	return workspaces, nil
+}
+
+// GetMemberRole возвращает роль пользователя в пространстве.
+// Если пользователь не участник пространства, возвращается ErrForbidden.
+func (u *Usecase) GetMemberRole(ctx context.Context, workspaceID, userID int64) (roles.Role, error) {
+	ctx, span := tracing.Start(ctx, "workspace.GetMemberRole")
+	defer span.End()
+
+	role, err := u.repository.GetMemberRole(ctx, workspaceID, userID)
+	if err != nil {
+		if errors.Is(err, repo.ErrMemberNotFound) {
+			return "", ErrForbidden
+		}
+		return "", fmt.Errorf("repo get member role: %w", err)
+	}
+
+	return roles.Role(role), nil
+}
+
+func (u *Usecase) GetMembers(ctx context.Context, workspaceID, actorID int64) ([]Member, error) {
+	ctx, span := tracing.Start(ctx, "workspace.GetMembers")
+	defer span.End()
+
+	if _, err := u.requireRole(ctx, workspaceID, actorID, roles.Viewer); err != nil {
+		return nil, err
+	}
+
+	repoMembers, err := u.repository.GetMembers(ctx, workspaceID)
+	if err != nil {
+		if errors.Is(err, repo.ErrMemberNotFound) {
+			return []Member{}, nil
+		}
+		return nil, fmt.Errorf("repo get members: %w", err)
+	}
+
+	members := make([]Member, 0, len(repoMembers))
+	for _, m := range repoMembers {
+		members = append(members, Member{
+			WorkspaceID: m.WorkspaceID,
+			UserID:      m.UserID,
+			Role:        roles.Role(m.Role),
+			Name:        m.Name,
+			Email:       m.Email,
+		})
+	}
+
+	return members, nil
+}
+
+// InviteMember добавляет в пространство зарегистрированного пользователя по email.
+func (u *Usecase) InviteMember(ctx context.Context, workspaceID, actorID int64, email string, role roles.Role) (int64, error) {
+	ctx, span := tracing.Start(ctx, "workspace.InviteMember")
+	defer span.End()
+
+	actorRole, err := u.requireRole(ctx, workspaceID, actorID, roles.Admin)
+	if err != nil {
+		return 0, err
+	}
+
+	if err = checkAssignableRole(actorRole, role); err != nil {
+		return 0, err
+	}
+
+	userID, err := u.repository.GetUserIDByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, repo.ErrUserNotFound) {
+			return 0, ErrUserNotFound
+		}
+		return 0, fmt.Errorf("repo get user id by email: %w", err)
+	}
+
+	err = u.repository.AddMember(ctx, repo.Member{
+		WorkspaceID: workspaceID,
+		UserID:      userID,
+		Role:        string(role),
+	})
+
+	if err != nil {
+		if errors.Is(err, repo.ErrMemberExists) {
+			return 0, ErrMemberExists
+		}
+		return 0, fmt.Errorf("repo add member: %w", err)
+	}
+
+	// Проекты пространства появляются в статистике участника.
+	u.statsCache.InvalidateProjects(ctx, userID)
+
+	return userID, nil
+}
+
+func (u *Usecase) UpdateMemberRole(ctx context.Context, workspaceID, actorID, userID int64, role roles.Role) error {
+	ctx, span := tracing.Start(ctx, "workspace.UpdateMemberRole")
+	defer span.End()
+
+	actorRole, err := u.requireRole(ctx, workspaceID, actorID, roles.Admin)
+	if err != nil {
+		return err
+	}
+
+	if err = checkAssignableRole(actorRole, role); err != nil {
+		return err
+	}
+
+	if err = u.checkManageableMember(ctx, workspaceID, actorRole, userID); err != nil {
+		return err
+	}
+
+	err = u.repository.UpdateMemberRole(ctx, workspaceID, userID, string(role))
+	if err != nil {
+		if errors.Is(err, repo.ErrMemberNotFound) {
+			return ErrMemberNotFound
+		}
+		return fmt.Errorf("repo update member role: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveMember удаляет участника из пространства.
+// Любой участник, кроме владельца, может покинуть пространство сам.
+func (u *Usecase) RemoveMember(ctx context.Context, workspaceID, actorID, userID int64) error {
+	ctx, span := tracing.Start(ctx, "workspace.RemoveMember")
+	defer span.End()
+
+	minRole := roles.Admin
+	if actorID == userID {
+		minRole = roles.Viewer
+	}
+
+	actorRole, err := u.requireRole(ctx, workspaceID, actorID, minRole)
+	if err != nil {
+		return err
+	}
+
+	if actorID == userID {
+		if actorRole == roles.Owner {
+			return ErrForbidden
+		}
+	} else if err = u.checkManageableMember(ctx, workspaceID, actorRole, userID); err != nil {
+		return err
+	}
+
+	err = u.repository.DeleteMember(ctx, workspaceID, userID)
+	if err != nil {
+		if errors.Is(err, repo.ErrMemberNotFound) {
+			return ErrMemberNotFound
+		}
+		return fmt.Errorf("repo delete member: %w", err)
+	}
+
+	u.statsCache.InvalidateProjects(ctx, userID)
+
+	return nil
+}
+
+// requireRole проверяет, что пользователь участник пространства с ролью не ниже min.
+func (u *Usecase) requireRole(ctx context.Context, workspaceID, userID int64, min roles.Role) (roles.Role, error) {
+	role, err := u.GetMemberRole(ctx, workspaceID, userID)
+	if err != nil {
+		return "", err
+	}
+
+	if !role.AtLeast(min) {
+		return "", ErrForbidden
+	}
+
+	return role, nil
+}
+
+// checkManageableMember проверяет, что actor может менять участника userID:
+// владельца менять нельзя, администраторов может менять только владелец.
+func (u *Usecase) checkManageableMember(ctx context.Context, workspaceID int64, actorRole roles.Role, userID int64) error {
+	role, err := u.repository.GetMemberRole(ctx, workspaceID, userID)
+	if err != nil {
+		if errors.Is(err, repo.ErrMemberNotFound) {
+			return ErrMemberNotFound
+		}
+		return fmt.Errorf("repo get member role: %w", err)
+	}
+
+	if roles.Role(role) == roles.Owner {
+		return ErrForbidden
+	}
+
+	if roles.Role(role) == roles.Admin && actorRole != roles.Owner {
+		return ErrForbidden
+	}
+
+	return nil
+}
+
+// checkAssignableRole проверяет, что actor может выдать роль:
+// роль owner не выдается, роль admin может выдать только владелец.
+func checkAssignableRole(actorRole roles.Role, role roles.Role) error {
+	if !role.IsValid() || role == roles.Owner {
+		return ErrInvalidRole
+	}
+
+	if role == roles.Admin && actorRole != roles.Owner {
+		return ErrForbidden
+	}
+
+	return nil
+}
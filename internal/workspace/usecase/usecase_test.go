@@ -0,0 +1,211 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/roles"
+	repo "github.com/BMSTU-TIMETRACKERS/timetracker-backend/internal/workspace/repository"
+)
+
+const testWorkspaceID = 5
+
+// Участники пространства testWorkspaceID.
+const (
+	ownerID  = 1
+	adminID  = 2
+	memberID = 3
+	viewerID = 4
+	// Зарегистрирован, но не участник.
+	outsiderID = 5
+)
+
+// fakeRepository хранит роли участников одного пространства.
+type fakeRepository struct {
+	repository
+
+	roles map[int64]string
+}
+
+func newFakeRepository() *fakeRepository {
+	return &fakeRepository{roles: map[int64]string{
+		ownerID:  "owner",
+		adminID:  "admin",
+		memberID: "member",
+		viewerID: "viewer",
+	}}
+}
+
+func (r *fakeRepository) GetMemberRole(_ context.Context, _, userID int64) (string, error) {
+	role, ok := r.roles[userID]
+	if !ok {
+		return "", repo.ErrMemberNotFound
+	}
+
+	return role, nil
+}
+
+func (r *fakeRepository) GetUserIDByEmail(_ context.Context, email string) (int64, error) {
+	if email != "outsider@example.com" {
+		return 0, repo.ErrUserNotFound
+	}
+
+	return outsiderID, nil
+}
+
+func (r *fakeRepository) AddMember(_ context.Context, member repo.Member) error {
+	if _, ok := r.roles[member.UserID]; ok {
+		return repo.ErrMemberExists
+	}
+
+	r.roles[member.UserID] = member.Role
+	return nil
+}
+
+func (r *fakeRepository) UpdateMemberRole(_ context.Context, _, userID int64, role string) error {
+	r.roles[userID] = role
+	return nil
+}
+
+func (r *fakeRepository) DeleteMember(_ context.Context, _, userID int64) error {
+	delete(r.roles, userID)
+	return nil
+}
+
+// fakeStatsCache запоминает пользователей, чей список проектов сброшен.
+type fakeStatsCache struct {
+	invalidated []int64
+}
+
+func (c *fakeStatsCache) InvalidateProjects(_ context.Context, userID int64) {
+	c.invalidated = append(c.invalidated, userID)
+}
+
+func TestInviteMember(t *testing.T) {
+	tests := []struct {
+		name    string
+		actorID int64
+		email   string
+		role    roles.Role
+		wantErr error
+	}{
+		{name: "admin invites member", actorID: adminID, email: "outsider@example.com", role: roles.Member},
+		{name: "owner invites admin", actorID: ownerID, email: "outsider@example.com", role: roles.Admin},
+		{name: "admin invites admin", actorID: adminID, email: "outsider@example.com", role: roles.Admin, wantErr: ErrForbidden},
+		{name: "owner role", actorID: ownerID, email: "outsider@example.com", role: roles.Owner, wantErr: ErrInvalidRole},
+		{name: "unknown role", actorID: ownerID, email: "outsider@example.com", role: "guest", wantErr: ErrInvalidRole},
+		{name: "member invites", actorID: memberID, email: "outsider@example.com", role: roles.Viewer, wantErr: ErrForbidden},
+		{name: "non-member invites", actorID: outsiderID, email: "outsider@example.com", role: roles.Viewer, wantErr: ErrForbidden},
+		{name: "unknown email", actorID: adminID, email: "nobody@example.com", role: roles.Member, wantErr: ErrUserNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, cache := newFakeRepository(), &fakeStatsCache{}
+			u := NewUsecase(r, cache)
+
+			userID, err := u.InviteMember(context.Background(), testWorkspaceID, tt.actorID, tt.email, tt.role)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("invite: error %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr != nil {
+				if len(r.roles) != 4 || len(cache.invalidated) != 0 {
+					t.Errorf("members %v, invalidated %v after a failed invite", r.roles, cache.invalidated)
+				}
+				return
+			}
+
+			if userID != outsiderID || r.roles[outsiderID] != string(tt.role) {
+				t.Errorf("invited user %d with role %q, want %d with %q", userID, r.roles[outsiderID], outsiderID, tt.role)
+			}
+			// Проекты пространства должны появиться в статистике нового участника.
+			if len(cache.invalidated) != 1 || cache.invalidated[0] != outsiderID {
+				t.Errorf("invalidated %v, want [%d]", cache.invalidated, outsiderID)
+			}
+		})
+	}
+}
+
+func TestUpdateMemberRole(t *testing.T) {
+	tests := []struct {
+		name    string
+		actorID int64
+		userID  int64
+		role    roles.Role
+		wantErr error
+	}{
+		{name: "admin promotes viewer", actorID: adminID, userID: viewerID, role: roles.Member},
+		{name: "owner promotes member to admin", actorID: ownerID, userID: memberID, role: roles.Admin},
+		{name: "owner demotes admin", actorID: ownerID, userID: adminID, role: roles.Viewer},
+		{name: "admin demotes admin", actorID: adminID, userID: adminID, role: roles.Viewer, wantErr: ErrForbidden},
+		{name: "admin changes owner", actorID: adminID, userID: ownerID, role: roles.Viewer, wantErr: ErrForbidden},
+		{name: "owner changes owner", actorID: ownerID, userID: ownerID, role: roles.Admin, wantErr: ErrForbidden},
+		{name: "member changes viewer", actorID: memberID, userID: viewerID, role: roles.Member, wantErr: ErrForbidden},
+		{name: "not a member", actorID: adminID, userID: outsiderID, role: roles.Member, wantErr: ErrMemberNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newFakeRepository()
+			before := r.roles[tt.userID]
+			u := NewUsecase(r, &fakeStatsCache{})
+
+			err := u.UpdateMemberRole(context.Background(), testWorkspaceID, tt.actorID, tt.userID, tt.role)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("update role: error %v, want %v", err, tt.wantErr)
+			}
+
+			want := string(tt.role)
+			if tt.wantErr != nil {
+				want = before
+			}
+			if r.roles[tt.userID] != want {
+				t.Errorf("role %q, want %q", r.roles[tt.userID], want)
+			}
+		})
+	}
+}
+
+func TestRemoveMember(t *testing.T) {
+	tests := []struct {
+		name    string
+		actorID int64
+		userID  int64
+		wantErr error
+	}{
+		{name: "viewer leaves", actorID: viewerID, userID: viewerID},
+		{name: "admin leaves", actorID: adminID, userID: adminID},
+		{name: "admin removes member", actorID: adminID, userID: memberID},
+		{name: "owner removes admin", actorID: ownerID, userID: adminID},
+		{name: "owner leaves", actorID: ownerID, userID: ownerID, wantErr: ErrForbidden},
+		{name: "not a member", actorID: adminID, userID: outsiderID, wantErr: ErrMemberNotFound},
+		{name: "member removes viewer", actorID: memberID, userID: viewerID, wantErr: ErrForbidden},
+		{name: "admin removes owner", actorID: adminID, userID: ownerID, wantErr: ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, cache := newFakeRepository(), &fakeStatsCache{}
+			u := NewUsecase(r, cache)
+
+			err := u.RemoveMember(context.Background(), testWorkspaceID, tt.actorID, tt.userID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("remove member: error %v, want %v", err, tt.wantErr)
+			}
+
+			_, stillMember := r.roles[tt.userID]
+			if tt.wantErr != nil {
+				if len(r.roles) != 4 || len(cache.invalidated) != 0 {
+					t.Errorf("members %v, invalidated %v after a failed removal", r.roles, cache.invalidated)
+				}
+				return
+			}
+
+			if stillMember || len(cache.invalidated) != 1 || cache.invalidated[0] != tt.userID {
+				t.Errorf("member %d still in workspace %v, invalidated %v", tt.userID, stillMember, cache.invalidated)
+			}
+		})
+	}
+}